@@ -79,6 +79,16 @@ type RedisParameters struct {
 	// +immutable
 	Location string `json:"location"`
 
+	// NamePrefix, if set, causes the Azure name of the cache to be generated
+	// from the prefix and the UID of the Redis resource, for example
+	// myorg-5d3c1ea3-08b6-4ff9-9c4b-6e0b4a8f2f7e. It is ignored if the
+	// crossplane.io/external-name annotation is already set.
+	// +kubebuilder:validation:MaxLength=26
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`
+	// +immutable
+	// +optional
+	NamePrefix *string `json:"namePrefix,omitempty"`
+
 	// SubnetID specifies the full resource ID of a subnet in a virtual network
 	// to deploy the Redis cache in. Example format:
 	// /subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/Microsoft.{Network|ClassicNetwork}/VirtualNetworks/vnet1/subnets/subnet1
@@ -117,12 +127,34 @@ type RedisParameters struct {
 	// +optional
 	ShardCount *int `json:"shardCount,omitempty"`
 
+	// ReplicasPerMaster specifies the number of replicas to be created per
+	// master shard. It may only be set for Premium clustered caches, i.e.
+	// when ShardCount is also set.
+	// +kubebuilder:validation:Minimum=1
+	// +immutable
+	// +optional
+	ReplicasPerMaster *int `json:"replicasPerMaster,omitempty"`
+
+	// RedisVersion is the major version of Redis to deploy. Caches may be
+	// upgraded to a newer major version in place, but not downgraded.
+	// Defaults to the version Azure deploys by default.
+	// +kubebuilder:validation:Enum="4";"6"
+	// +optional
+	RedisVersion *string `json:"redisVersion,omitempty"`
+
 	// MinimumTLSVersion - Optional: requires clients to use a specified TLS
 	// version (or higher) to connect (e,g, '1.0', '1.1', '1.2'). Possible
 	// values include: 'OneFullStopZero', 'OneFullStopOne', 'OneFullStopTwo'
 	// +optional
 	MinimumTLSVersion *string `json:"minimumTlsVersion,omitempty"`
 
+	// PublicNetworkAccess specifies whether the cache may be accessed from
+	// public networks. When Disabled the cache is only reachable via private
+	// endpoints or the subnet it is deployed in. Defaults to Enabled.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	PublicNetworkAccess *string `json:"publicNetworkAccess,omitempty"`
+
 	// Zones - A list of availability zones denoting where the resource needs to come from.
 	// +immutable
 	// +optional
@@ -137,6 +169,29 @@ type RedisParameters struct {
 type RedisSpec struct {
 	runtimev1alpha1.ResourceSpec `json:",inline"`
 	ForProvider                  RedisParameters `json:"forProvider"`
+	// ConnectionSecretKeys renames the keys of the connection secret. Each
+	// key of this map is a default connection secret key (e.g. endpoint,
+	// username, password or port) and each value is the name under which it
+	// should be published instead. Keys that are not mapped keep their default
+	// names. Renaming two keys to the same name, or a key to the default name
+	// of a key that is not renamed, is an error.
+	// +optional
+	ConnectionSecretKeys map[string]string `json:"connectionSecretKeys,omitempty"`
+}
+
+// An UpgradeNotification is a notification from Azure about maintenance that
+// is planned or has recently happened for a Redis.
+type UpgradeNotification struct {
+	// Name of the upgrade notification.
+	Name string `json:"name,omitempty"`
+
+	// Timestamp is when the upgrade notification occurred.
+	// +optional
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// Details about the upgrade notification.
+	// +optional
+	Details map[string]string `json:"details,omitempty"`
 }
 
 // RedisObservation represents the observed state of the Redis object in Azure.
@@ -159,9 +214,37 @@ type RedisObservation struct {
 	// SSLPort - Redis SSL port.
 	SSLPort int `json:"sslPort,omitempty"`
 
+	// PrivateIP - The private IP address of a Redis cache that is deployed
+	// inside a virtual network. It is read from the static IP reported by
+	// Azure, which the Redis API version we use does not report for caches
+	// whose address was dynamically assigned. Such caches have no PrivateIP,
+	// and publish no privateIp connection secret key.
+	PrivateIP string `json:"privateIp,omitempty"`
+
+	// PublicNetworkAccess - Whether the cache may be accessed from public
+	// networks. Possible values include: 'Enabled', 'Disabled'
+	PublicNetworkAccess string `json:"publicNetworkAccess,omitempty"`
+
+	// ReplicasPerMaster - The number of replicas created per master shard.
+	ReplicasPerMaster int `json:"replicasPerMaster,omitempty"`
+
 	// LinkedServers - List of the linked servers associated with the cache
 	LinkedServers []string `json:"linkedServers,omitempty"`
 
+	// ReplicationRole - The role of this cache in geo-replication, if it is
+	// linked to another cache. Possible values include: 'Primary',
+	// 'Secondary'
+	ReplicationRole string `json:"replicationRole,omitempty"`
+
+	// LinkedCacheID - The resource ID of the cache that this cache is linked
+	// to for geo-replication.
+	LinkedCacheID string `json:"linkedCacheId,omitempty"`
+
+	// UpgradeNotifications - Notifications about maintenance of the cache
+	// that Azure has emitted recently, oldest first.
+	// +optional
+	UpgradeNotifications []UpgradeNotification `json:"upgradeNotifications,omitempty"`
+
 	// ID - Resource ID.
 	ID string `json:"id,omitempty"`
 
@@ -173,6 +256,11 @@ type RedisObservation struct {
 type RedisStatus struct {
 	runtimev1alpha1.ResourceStatus `json:",inline"`
 	AtProvider                     RedisObservation `json:"atProvider,omitempty"`
+
+	// LastSyncTime is the last time the Redis was observed to be available
+	// and in sync with its desired state. It is updated at most once a minute.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
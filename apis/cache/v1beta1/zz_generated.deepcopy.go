@@ -92,6 +92,13 @@ func (in *RedisObservation) DeepCopyInto(out *RedisObservation) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UpgradeNotifications != nil {
+		in, out := &in.UpgradeNotifications, &out.UpgradeNotifications
+		*out = make([]UpgradeNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisObservation.
@@ -118,6 +125,11 @@ func (in *RedisParameters) DeepCopyInto(out *RedisParameters) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.SKU = in.SKU
+	if in.NamePrefix != nil {
+		in, out := &in.NamePrefix, &out.NamePrefix
+		*out = new(string)
+		**out = **in
+	}
 	if in.SubnetID != nil {
 		in, out := &in.SubnetID, &out.SubnetID
 		*out = new(string)
@@ -152,11 +164,26 @@ func (in *RedisParameters) DeepCopyInto(out *RedisParameters) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ReplicasPerMaster != nil {
+		in, out := &in.ReplicasPerMaster, &out.ReplicasPerMaster
+		*out = new(int)
+		**out = **in
+	}
+	if in.RedisVersion != nil {
+		in, out := &in.RedisVersion, &out.RedisVersion
+		*out = new(string)
+		**out = **in
+	}
 	if in.MinimumTLSVersion != nil {
 		in, out := &in.MinimumTLSVersion, &out.MinimumTLSVersion
 		*out = new(string)
 		**out = **in
 	}
+	if in.PublicNetworkAccess != nil {
+		in, out := &in.PublicNetworkAccess, &out.PublicNetworkAccess
+		*out = new(string)
+		**out = **in
+	}
 	if in.Zones != nil {
 		in, out := &in.Zones, &out.Zones
 		*out = make([]string, len(*in))
@@ -186,6 +213,13 @@ func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.ConnectionSecretKeys != nil {
+		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSpec.
@@ -203,6 +237,10 @@ func (in *RedisStatus) DeepCopyInto(out *RedisStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStatus.
@@ -229,3 +267,29 @@ func (in *SKU) DeepCopy() *SKU {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeNotification) DeepCopyInto(out *UpgradeNotification) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeNotification.
+func (in *UpgradeNotification) DeepCopy() *UpgradeNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeNotification)
+	in.DeepCopyInto(out)
+	return out
+}
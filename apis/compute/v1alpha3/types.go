@@ -59,8 +59,9 @@ type AKSClusterParameters struct {
 	// its ID
 	VnetSubnetIDSelector *runtimev1alpha1.Selector `json:"vnetSubnetIDSelector,omitempty"`
 
-	// NodeCount is the number of nodes that the cluster will initially be
-	// created with.  This can be scaled over time and defaults to 1.
+	// NodeCount is the number of nodes in the cluster. Changes are applied by
+	// scaling the cluster, unless the Azure cluster autoscaler is enabled, in
+	// which case Azure manages the node count. Defaults to 1.
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	// +optional
@@ -71,6 +72,57 @@ type AKSClusterParameters struct {
 	// +optional
 	NodeVMSize string `json:"nodeVMSize"`
 
+	// NodeOSType is the operating system of the worker nodes. Nodes run
+	// Linux if no OS type is specified.
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +optional
+	NodeOSType *string `json:"nodeOSType,omitempty"`
+
+	// NodeOSDiskSizeGB is the size of the OS disk of each worker node, in GB.
+	// The default size for the node VM size is used if it is omitted or 0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2048
+	// +optional
+	NodeOSDiskSizeGB *int `json:"nodeOSDiskSizeGB,omitempty"`
+
+	// NodeOSDiskType is the type of the OS disk of each worker node. Ephemeral
+	// OS disks are stored on the node VM's cache disk, and thus must fit
+	// within it. Nodes use managed OS disks if no type is specified.
+	// +kubebuilder:validation:Enum=Managed;Ephemeral
+	// +optional
+	NodeOSDiskType *string `json:"nodeOSDiskType,omitempty"`
+
+	// NodeMaxPods is the maximum number of pods that may run on each worker
+	// node.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=250
+	// +optional
+	NodeMaxPods *int `json:"nodeMaxPods,omitempty"`
+
+	// NodeScaleSetPriority is the priority of the worker node VM scale set.
+	// Spot nodes are cheaper, but may be evicted at any time. Nodes use
+	// Regular priority if no priority is specified. It cannot be changed once
+	// the cluster has been created.
+	// +kubebuilder:validation:Enum=Spot;Regular
+	// +optional
+	NodeScaleSetPriority *string `json:"nodeScaleSetPriority,omitempty"`
+
+	// NodeScaleSetEvictionPolicy determines what happens to evicted Spot
+	// nodes. Evicted nodes are deleted if no policy is specified. It may only
+	// be specified when NodeScaleSetPriority is Spot, and cannot be changed
+	// once the cluster has been created.
+	// +kubebuilder:validation:Enum=Delete;Deallocate
+	// +optional
+	NodeScaleSetEvictionPolicy *string `json:"nodeScaleSetEvictionPolicy,omitempty"`
+
+	// NodeSpotMaxPrice is the maximum price per hour, in US dollars, to pay
+	// for each Spot node, e.g. "0.05". A price of "-1" pays up to the
+	// on-demand price. It may only be specified when NodeScaleSetPriority is
+	// Spot, and cannot be changed once the cluster has been created.
+	// +kubebuilder:validation:Pattern=`^(-1|[0-9]+(\.[0-9]+)?)$`
+	// +optional
+	NodeSpotMaxPrice *string `json:"nodeSpotMaxPrice,omitempty"`
+
 	// DNSNamePrefix is the DNS name prefix to use with the hosted Kubernetes
 	// API server FQDN. You will use this to connect to the Kubernetes API when
 	// managing containers after creating the cluster.
@@ -81,6 +133,50 @@ type AKSClusterParameters struct {
 	// cluster.
 	// +optional
 	DisableRBAC bool `json:"disableRBAC,omitempty"`
+
+	// Tags to apply to the cluster. The cluster's tags are not managed if
+	// this field is unset. Tags injected by Azure are always preserved.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// SKU of the cluster. Clusters use the Free tier if no SKU is specified.
+	// +optional
+	SKU *AKSClusterSKU `json:"sku,omitempty"`
+
+	// APIServerAccessProfile configures access to the cluster's Kubernetes
+	// API server.
+	// +optional
+	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+}
+
+// An APIServerAccessProfile configures access to the Kubernetes API server of
+// an AKS cluster.
+type APIServerAccessProfile struct {
+	// EnablePrivateCluster exposes the API server only via a private
+	// endpoint. It cannot be changed once the cluster has been created.
+	// +optional
+	EnablePrivateCluster *bool `json:"enablePrivateCluster,omitempty"`
+
+	// PrivateDNSZone of a private cluster; either System, None, or the ID of
+	// an existing private DNS zone. It cannot be changed once the cluster has
+	// been created.
+	// +optional
+	PrivateDNSZone *string `json:"privateDNSZone,omitempty"`
+
+	// AuthorizedIPRanges that may access the public API server, in CIDR
+	// notation. An empty list removes any existing restrictions, allowing
+	// access from any IP address. Existing restrictions are left as is when
+	// this field is omitted.
+	// +optional
+	AuthorizedIPRanges *[]string `json:"authorizedIPRanges,omitempty"`
+}
+
+// An AKSClusterSKU specifies the SKU of an AKS cluster.
+type AKSClusterSKU struct {
+	// Tier of the cluster. Paid clusters are financially backed by an uptime
+	// SLA.
+	// +kubebuilder:validation:Enum=Free;Paid
+	Tier string `json:"tier"`
 }
 
 // An AKSClusterSpec defines the desired state of a AKSCluster.
@@ -102,6 +198,16 @@ type AKSClusterStatus struct {
 
 	// Endpoint is the endpoint where the cluster can be reached
 	Endpoint string `json:"endpoint"`
+
+	// SKUTier is the effective SKU tier of the cluster.
+	SKUTier string `json:"skuTier,omitempty"`
+
+	// PrivateFQDN is the FQDN of the API server of a private cluster.
+	PrivateFQDN string `json:"privateFQDN,omitempty"`
+
+	// NodeScaleSetEvictionPolicy is the effective eviction policy of Spot
+	// worker nodes.
+	NodeScaleSetEvictionPolicy string `json:"nodeScaleSetEvictionPolicy,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -112,6 +112,58 @@ func (in *AKSClusterParameters) DeepCopyInto(out *AKSClusterParameters) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.NodeOSType != nil {
+		in, out := &in.NodeOSType, &out.NodeOSType
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeOSDiskSizeGB != nil {
+		in, out := &in.NodeOSDiskSizeGB, &out.NodeOSDiskSizeGB
+		*out = new(int)
+		**out = **in
+	}
+	if in.NodeOSDiskType != nil {
+		in, out := &in.NodeOSDiskType, &out.NodeOSDiskType
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeMaxPods != nil {
+		in, out := &in.NodeMaxPods, &out.NodeMaxPods
+		*out = new(int)
+		**out = **in
+	}
+	if in.NodeScaleSetPriority != nil {
+		in, out := &in.NodeScaleSetPriority, &out.NodeScaleSetPriority
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeScaleSetEvictionPolicy != nil {
+		in, out := &in.NodeScaleSetEvictionPolicy, &out.NodeScaleSetEvictionPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSpotMaxPrice != nil {
+		in, out := &in.NodeSpotMaxPrice, &out.NodeSpotMaxPrice
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SKU != nil {
+		in, out := &in.SKU, &out.SKU
+		*out = new(AKSClusterSKU)
+		**out = **in
+	}
+	if in.APIServerAccessProfile != nil {
+		in, out := &in.APIServerAccessProfile, &out.APIServerAccessProfile
+		*out = new(APIServerAccessProfile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKSClusterParameters.
@@ -124,6 +176,21 @@ func (in *AKSClusterParameters) DeepCopy() *AKSClusterParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKSClusterSKU) DeepCopyInto(out *AKSClusterSKU) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKSClusterSKU.
+func (in *AKSClusterSKU) DeepCopy() *AKSClusterSKU {
+	if in == nil {
+		return nil
+	}
+	out := new(AKSClusterSKU)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AKSClusterSpec) DeepCopyInto(out *AKSClusterSpec) {
 	*out = *in
@@ -156,3 +223,37 @@ func (in *AKSClusterStatus) DeepCopy() *AKSClusterStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAccessProfile) DeepCopyInto(out *APIServerAccessProfile) {
+	*out = *in
+	if in.EnablePrivateCluster != nil {
+		in, out := &in.EnablePrivateCluster, &out.EnablePrivateCluster
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrivateDNSZone != nil {
+		in, out := &in.PrivateDNSZone, &out.PrivateDNSZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthorizedIPRanges != nil {
+		in, out := &in.AuthorizedIPRanges, &out.AuthorizedIPRanges
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAccessProfile.
+func (in *APIServerAccessProfile) DeepCopy() *APIServerAccessProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAccessProfile)
+	in.DeepCopyInto(out)
+	return out
+}
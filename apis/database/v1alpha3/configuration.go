@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+)
+
+// A SQLServerConfigurationObservation represents the observed state of an
+// Azure SQL server configuration parameter.
+type SQLServerConfigurationObservation struct {
+	// ID - Resource ID
+	ID string `json:"id,omitempty"`
+
+	// Type - Resource type.
+	Type string `json:"type,omitempty"`
+
+	// Description of the configuration parameter.
+	Description string `json:"description,omitempty"`
+
+	// DefaultValue of the configuration parameter.
+	DefaultValue string `json:"defaultValue,omitempty"`
+
+	// DataType of the configuration parameter.
+	DataType string `json:"dataType,omitempty"`
+
+	// AllowedValues of the configuration parameter.
+	AllowedValues string `json:"allowedValues,omitempty"`
+
+	// Source of the configuration parameter's value, for example
+	// system-default or user-override.
+	Source string `json:"source,omitempty"`
+
+	// RestartPending is true if the server must be restarted in order for
+	// the parameter's current value to take effect.
+	RestartPending bool `json:"restartPending,omitempty"`
+
+	// LastOperation represents the state of the last operation started by
+	// the controller, i.e. setting the parameter or restarting its server.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+}
+
+// A SQLServerConfigurationStatus represents the status of an Azure SQL server
+// configuration parameter.
+type SQLServerConfigurationStatus struct {
+	runtimev1alpha1.ResourceStatus `json:",inline"`
+	AtProvider                     SQLServerConfigurationObservation `json:"atProvider,omitempty"`
+}
+
+// SQLServerConfigurationParameters define the desired state of an Azure SQL
+// server configuration parameter.
+type SQLServerConfigurationParameters struct {
+	// ServerName - Name of the configuration parameter's server.
+	ServerName string `json:"serverName,omitempty"`
+
+	// ServerNameRef - A reference to the configuration parameter's server.
+	ServerNameRef *runtimev1alpha1.Reference `json:"serverNameRef,omitempty"`
+
+	// ServerNameSelector - Selects a server to reference.
+	ServerNameSelector *runtimev1alpha1.Selector `json:"serverNameSelector,omitempty"`
+
+	// ResourceGroupName - Name of the configuration parameter's resource
+	// group.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef - A reference to a ResourceGroup object to retrieve
+	// its name
+	ResourceGroupNameRef *runtimev1alpha1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector - Selects a ResourceGroup to reference.
+	ResourceGroupNameSelector *runtimev1alpha1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// Name of the configuration parameter, for example
+	// log_min_duration_statement.
+	// +immutable
+	Name string `json:"name"`
+
+	// Value of the configuration parameter.
+	Value string `json:"value"`
+
+	// RestartServer after the parameter's value is changed, so that the new
+	// value takes effect. Defaults to true for parameters that are known to
+	// require a restart, such as shared_preload_libraries.
+	// +optional
+	RestartServer *bool `json:"restartServer,omitempty"`
+}
+
+// A SQLServerConfigurationSpec defines the desired state of an Azure SQL
+// server configuration parameter.
+type SQLServerConfigurationSpec struct {
+	runtimev1alpha1.ResourceSpec `json:",inline"`
+	ForProvider                  SQLServerConfigurationParameters `json:"forProvider"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PostgreSQLServerConfiguration is a managed resource that represents an
+// Azure PostgreSQL server configuration parameter. Deleting it resets the
+// parameter to its default value.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PARAMETER",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="VALUE",type="string",JSONPath=".spec.forProvider.value"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type PostgreSQLServerConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SQLServerConfigurationSpec   `json:"spec"`
+	Status SQLServerConfigurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PostgreSQLServerConfigurationList contains a list of
+// PostgreSQLServerConfiguration.
+type PostgreSQLServerConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PostgreSQLServerConfiguration `json:"items"`
+}
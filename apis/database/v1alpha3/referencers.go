@@ -197,6 +197,41 @@ func (mg *PostgreSQLServerFirewallRule) ResolveReferences(ctx context.Context, c
 	return nil
 }
 
+// ResolveReferences of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.resourceGroupName
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ResourceGroupName,
+		Reference:    mg.Spec.ForProvider.ResourceGroupNameRef,
+		Selector:     mg.Spec.ForProvider.ResourceGroupNameSelector,
+		To:           reference.To{Managed: &v1alpha3.ResourceGroup{}, List: &v1alpha3.ResourceGroupList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.resourceGroupName")
+	}
+	mg.Spec.ForProvider.ResourceGroupName = rsp.ResolvedValue
+	mg.Spec.ForProvider.ResourceGroupNameRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.serverName
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ServerName,
+		Reference:    mg.Spec.ForProvider.ServerNameRef,
+		Selector:     mg.Spec.ForProvider.ServerNameSelector,
+		To:           reference.To{Managed: &v1beta1.PostgreSQLServer{}, List: &v1beta1.PostgreSQLServerList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.serverName")
+	}
+	mg.Spec.ForProvider.ServerName = rsp.ResolvedValue
+	mg.Spec.ForProvider.ServerNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // ResolveReferences of this CosmosDBAccount.
 func (mg *CosmosDBAccount) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPIResolver(c, mg)
@@ -69,6 +69,14 @@ var (
 	PostgreSQLServerFirewallRuleGroupVersionKind = SchemeGroupVersion.WithKind(PostgreSQLServerFirewallRuleKind)
 )
 
+// PostgreSQLServerConfiguration type metadata.
+var (
+	PostgreSQLServerConfigurationKind             = reflect.TypeOf(PostgreSQLServerConfiguration{}).Name()
+	PostgreSQLServerConfigurationGroupKind        = schema.GroupKind{Group: Group, Kind: PostgreSQLServerConfigurationKind}.String()
+	PostgreSQLServerConfigurationKindAPIVersion   = PostgreSQLServerConfigurationKind + "." + SchemeGroupVersion.String()
+	PostgreSQLServerConfigurationGroupVersionKind = SchemeGroupVersion.WithKind(PostgreSQLServerConfigurationKind)
+)
+
 // CosmosDBAccount type metadata.
 var (
 	CosmosDBAccountKind             = reflect.TypeOf(CosmosDBAccount{}).Name()
@@ -82,5 +90,6 @@ func init() {
 	SchemeBuilder.Register(&PostgreSQLServerVirtualNetworkRule{}, &PostgreSQLServerVirtualNetworkRuleList{})
 	SchemeBuilder.Register(&MySQLServerFirewallRule{}, &MySQLServerFirewallRuleList{})
 	SchemeBuilder.Register(&PostgreSQLServerFirewallRule{}, &PostgreSQLServerFirewallRuleList{})
+	SchemeBuilder.Register(&PostgreSQLServerConfiguration{}, &PostgreSQLServerConfigurationList{})
 	SchemeBuilder.Register(&CosmosDBAccount{}, &CosmosDBAccountList{})
 }
@@ -510,6 +510,65 @@ func (in *MySQLVirtualNetworkRuleSpec) DeepCopy() *MySQLVirtualNetworkRuleSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgreSQLServerConfiguration) DeepCopyInto(out *PostgreSQLServerConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQLServerConfiguration.
+func (in *PostgreSQLServerConfiguration) DeepCopy() *PostgreSQLServerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgreSQLServerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgreSQLServerConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgreSQLServerConfigurationList) DeepCopyInto(out *PostgreSQLServerConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PostgreSQLServerConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQLServerConfigurationList.
+func (in *PostgreSQLServerConfigurationList) DeepCopy() *PostgreSQLServerConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgreSQLServerConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgreSQLServerConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgreSQLServerFirewallRule) DeepCopyInto(out *PostgreSQLServerFirewallRule) {
 	*out = *in
@@ -665,6 +724,96 @@ func (in *PostgreSQLVirtualNetworkRuleSpec) DeepCopy() *PostgreSQLVirtualNetwork
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLServerConfigurationObservation) DeepCopyInto(out *SQLServerConfigurationObservation) {
+	*out = *in
+	out.LastOperation = in.LastOperation
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationObservation.
+func (in *SQLServerConfigurationObservation) DeepCopy() *SQLServerConfigurationObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLServerConfigurationObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLServerConfigurationParameters) DeepCopyInto(out *SQLServerConfigurationParameters) {
+	*out = *in
+	if in.ServerNameRef != nil {
+		in, out := &in.ServerNameRef, &out.ServerNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ServerNameSelector != nil {
+		in, out := &in.ServerNameSelector, &out.ServerNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceGroupNameRef != nil {
+		in, out := &in.ResourceGroupNameRef, &out.ResourceGroupNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ResourceGroupNameSelector != nil {
+		in, out := &in.ResourceGroupNameSelector, &out.ResourceGroupNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartServer != nil {
+		in, out := &in.RestartServer, &out.RestartServer
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationParameters.
+func (in *SQLServerConfigurationParameters) DeepCopy() *SQLServerConfigurationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLServerConfigurationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLServerConfigurationSpec) DeepCopyInto(out *SQLServerConfigurationSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationSpec.
+func (in *SQLServerConfigurationSpec) DeepCopy() *SQLServerConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLServerConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLServerConfigurationStatus) DeepCopyInto(out *SQLServerConfigurationStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	out.AtProvider = in.AtProvider
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationStatus.
+func (in *SQLServerConfigurationStatus) DeepCopy() *SQLServerConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLServerConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNetworkRuleProperties) DeepCopyInto(out *VirtualNetworkRuleProperties) {
 	*out = *in
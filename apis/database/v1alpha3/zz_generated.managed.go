@@ -188,6 +188,62 @@ func (mg *MySQLServerVirtualNetworkRule) SetWriteConnectionSecretToReference(r *
 	mg.Spec.WriteConnectionSecretToReference = r
 }
 
+// GetCondition of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetDeletionPolicy of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) GetDeletionPolicy() runtimev1alpha1.DeletionPolicy {
+	return mg.Spec.DeletionPolicy
+}
+
+// GetProviderConfigReference of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) GetProviderConfigReference() *runtimev1alpha1.Reference {
+	return mg.Spec.ProviderConfigReference
+}
+
+/*
+GetProviderReference of this PostgreSQLServerConfiguration.
+Deprecated: Use GetProviderConfigReference.
+*/
+func (mg *PostgreSQLServerConfiguration) GetProviderReference() *runtimev1alpha1.Reference {
+	return mg.Spec.ProviderReference
+}
+
+// GetWriteConnectionSecretToReference of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) GetWriteConnectionSecretToReference() *runtimev1alpha1.SecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) SetConditions(c ...runtimev1alpha1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetDeletionPolicy of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) SetDeletionPolicy(r runtimev1alpha1.DeletionPolicy) {
+	mg.Spec.DeletionPolicy = r
+}
+
+// SetProviderConfigReference of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) SetProviderConfigReference(r *runtimev1alpha1.Reference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+/*
+SetProviderReference of this PostgreSQLServerConfiguration.
+Deprecated: Use SetProviderConfigReference.
+*/
+func (mg *PostgreSQLServerConfiguration) SetProviderReference(r *runtimev1alpha1.Reference) {
+	mg.Spec.ProviderReference = r
+}
+
+// SetWriteConnectionSecretToReference of this PostgreSQLServerConfiguration.
+func (mg *PostgreSQLServerConfiguration) SetWriteConnectionSecretToReference(r *runtimev1alpha1.SecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
+
 // GetCondition of this PostgreSQLServerFirewallRule.
 func (mg *PostgreSQLServerFirewallRule) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
 	return mg.Status.GetCondition(ct)
@@ -47,6 +47,15 @@ func (l *MySQLServerVirtualNetworkRuleList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this PostgreSQLServerConfigurationList.
+func (l *PostgreSQLServerConfigurationList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this PostgreSQLServerFirewallRuleList.
 func (l *PostgreSQLServerFirewallRuleList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -115,8 +115,11 @@ type StorageProfile struct {
 	// +optional
 	GeoRedundantBackup *string `json:"geoRedundantBackup,omitempty"`
 
-	// StorageMB - Max storage allowed for a server.
-	StorageMB int `json:"storageMB"`
+	// StorageMB - Max storage allowed for a server. Defaults to a size that
+	// scales with the vCores of the server's SKU, within the limits of its
+	// pricing tier.
+	// +optional
+	StorageMB int `json:"storageMB,omitempty"`
 
 	// StorageAutogrow - Enable Storage Auto Grow.
 	// Possible values include: 'Enabled', 'Disabled'
@@ -151,15 +154,15 @@ type SQLServerParameters struct {
 	Location string `json:"location"`
 
 	// AdministratorLogin - The administrator's login name of a server. Can only be specified when the server is being created (and is required for creation).
+	// It must not be a name reserved by Azure, such as admin, root or guest.
 	// +immutable
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
 	AdministratorLogin string `json:"administratorLogin"`
 
 	// TODO(hasheddan): support AdministratorLoginPassword
 
-	// TODO(hasheddan): support MinimalTLSVersion
-
-	// TODO(hasheddan): support InfrastructureEncryption
-
 	// TODO(hasheddan): support PublicNetworkAccess
 
 	// TODO(hasheddan): support CreateMode
@@ -168,13 +171,32 @@ type SQLServerParameters struct {
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 
-	// Version - Server version.
+	// Version - Server version. Changing the version of an existing MySQL
+	// server from 5.6 to 5.7 upgrades it in place. No other version changes
+	// are allowed.
 	Version string `json:"version"`
 
 	// SSLEnforcement - Enable ssl enforcement or not when connect to server. Possible values include: 'Enabled', 'Disabled'
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	SSLEnforcement string `json:"sslEnforcement"`
 
+	// MinimalTLSVersion - The minimal TLS version clients must use to connect
+	// to the server. TLS versions may only be enforced when SSLEnforcement is
+	// Enabled. Possible values include: 'TLS1_0', 'TLS1_1', 'TLS1_2',
+	// 'TLSEnforcementDisabled'
+	// +kubebuilder:validation:Enum=TLS1_0;TLS1_1;TLS1_2;TLSEnforcementDisabled
+	// +optional
+	MinimalTLSVersion *string `json:"minimalTlsVersion,omitempty"`
+
+	// InfrastructureEncryption - Whether the server's data is encrypted a
+	// second time, at the infrastructure level, using a different key. It
+	// can only be set when the server is created. Possible values include:
+	// 'Enabled', 'Disabled'
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +immutable
+	// +optional
+	InfrastructureEncryption *string `json:"infrastructureEncryption,omitempty"`
+
 	// StorageProfile - Storage profile of a server.
 	StorageProfile StorageProfile `json:"storageProfile"`
 }
@@ -183,6 +205,14 @@ type SQLServerParameters struct {
 type SQLServerSpec struct {
 	runtimev1alpha1.ResourceSpec `json:",inline"`
 	ForProvider                  SQLServerParameters `json:"forProvider"`
+	// ConnectionSecretKeys renames the keys of the connection secret. Each
+	// key of this map is a default connection secret key (e.g. endpoint,
+	// username, password or port) and each value is the name under which it
+	// should be published instead. Keys that are not mapped keep their default
+	// names. Renaming two keys to the same name, or a key to the default name
+	// of a key that is not renamed, is an error.
+	// +optional
+	ConnectionSecretKeys map[string]string `json:"connectionSecretKeys,omitempty"`
 }
 
 // SQLServerObservation represents the current state of Azure SQL resource.
@@ -199,12 +229,20 @@ type SQLServerObservation struct {
 	// UserVisibleState - A state of a server that is visible to user.
 	UserVisibleState string `json:"userVisibleState,omitempty"`
 
+	// Version - The server version reported by Azure.
+	Version string `json:"version,omitempty"`
+
 	// FullyQualifiedDomainName - The fully qualified domain name of a server.
 	FullyQualifiedDomainName string `json:"fullyQualifiedDomainName,omitempty"`
 
 	// MasterServerID - The master server id of a replica server.
 	MasterServerID string `json:"masterServerId,omitempty"`
 
+	// InfrastructureEncryption - Whether the server's data is encrypted a
+	// second time, at the infrastructure level. Disabled if Azure reports
+	// none.
+	InfrastructureEncryption string `json:"infrastructureEncryption,omitempty"`
+
 	// LastOperation represents the state of the last operation started by the
 	// controller.
 	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
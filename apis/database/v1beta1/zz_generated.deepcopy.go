@@ -200,6 +200,16 @@ func (in *SQLServerParameters) DeepCopyInto(out *SQLServerParameters) {
 			(*out)[key] = val
 		}
 	}
+	if in.MinimalTLSVersion != nil {
+		in, out := &in.MinimalTLSVersion, &out.MinimalTLSVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.InfrastructureEncryption != nil {
+		in, out := &in.InfrastructureEncryption, &out.InfrastructureEncryption
+		*out = new(string)
+		**out = **in
+	}
 	in.StorageProfile.DeepCopyInto(&out.StorageProfile)
 }
 
@@ -218,6 +228,13 @@ func (in *SQLServerSpec) DeepCopyInto(out *SQLServerSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.ConnectionSecretKeys != nil {
+		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerSpec.
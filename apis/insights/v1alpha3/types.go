@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// A RetentionPolicy specifies how long logs or metrics are retained by a
+// storage account destination.
+type RetentionPolicy struct {
+	// Enabled - Whether the retention policy is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Days - The number of days to retain logs or metrics. A value of 0
+	// retains them indefinitely.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Days *int32 `json:"days,omitempty"`
+}
+
+// A LogSetting enables or disables a category of diagnostic logs.
+type LogSetting struct {
+	// Category - The name of the diagnostic log category, for example
+	// MySqlSlowLogs.
+	Category string `json:"category"`
+
+	// Enabled - Whether logs of this category are sent to the destinations.
+	Enabled bool `json:"enabled"`
+
+	// RetentionPolicy - The retention policy for this category.
+	// +optional
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// A MetricSetting enables or disables a category of diagnostic metrics.
+type MetricSetting struct {
+	// Category - The name of the diagnostic metric category, for example
+	// AllMetrics.
+	Category string `json:"category"`
+
+	// Enabled - Whether metrics of this category are sent to the
+	// destinations.
+	Enabled bool `json:"enabled"`
+
+	// TimeGrain - The time grain of the metrics in ISO8601 format.
+	// +optional
+	TimeGrain *string `json:"timeGrain,omitempty"`
+
+	// RetentionPolicy - The retention policy for this category.
+	// +optional
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// DiagnosticSettingProperties defines properties of a DiagnosticSetting. At
+// least one destination must be specified.
+type DiagnosticSettingProperties struct {
+	// WorkspaceID - The ID of the Log Analytics workspace to which logs and
+	// metrics are sent.
+	// +optional
+	WorkspaceID *string `json:"workspaceId,omitempty"`
+
+	// LogAnalyticsDestinationType - Whether logs are sent to the
+	// AzureDiagnostics table of the workspace (the default), or to a
+	// Dedicated table per category.
+	// +kubebuilder:validation:Enum=Dedicated
+	// +optional
+	LogAnalyticsDestinationType *string `json:"logAnalyticsDestinationType,omitempty"`
+
+	// StorageAccountID - The ID of the storage account to which logs and
+	// metrics are sent.
+	// +optional
+	StorageAccountID *string `json:"storageAccountId,omitempty"`
+
+	// EventHubAuthorizationRuleID - The ID of the event hub authorization
+	// rule used to send logs and metrics to an event hub.
+	// +optional
+	EventHubAuthorizationRuleID *string `json:"eventHubAuthorizationRuleId,omitempty"`
+
+	// EventHubName - The name of the event hub to which logs and metrics are
+	// sent. The default event hub of the namespace is used if omitted.
+	// +optional
+	EventHubName *string `json:"eventHubName,omitempty"`
+
+	// Logs - The diagnostic log categories to send to the destinations.
+	// +optional
+	Logs []LogSetting `json:"logs,omitempty"`
+
+	// Metrics - The diagnostic metric categories to send to the
+	// destinations.
+	// +optional
+	Metrics []MetricSetting `json:"metrics,omitempty"`
+}
+
+// A DiagnosticSettingSpec defines the desired state of a DiagnosticSetting.
+type DiagnosticSettingSpec struct {
+	runtimev1alpha1.ResourceSpec `json:",inline"`
+
+	// ResourceID - The ID of the Azure resource, for example a SQL server or
+	// storage account, whose logs and metrics are sent to the destinations.
+	// +immutable
+	ResourceID string `json:"resourceId"`
+
+	// DiagnosticSettingProperties - Properties of the diagnostic setting.
+	DiagnosticSettingProperties `json:"properties"`
+}
+
+// A DiagnosticSettingStatus represents the observed state of a
+// DiagnosticSetting.
+type DiagnosticSettingStatus struct {
+	runtimev1alpha1.ResourceStatus `json:",inline"`
+
+	// ID of this DiagnosticSetting.
+	ID string `json:"id,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DiagnosticSetting is a managed resource that represents the Azure
+// diagnostic setting of a resource.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type DiagnosticSetting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DiagnosticSettingSpec   `json:"spec"`
+	Status DiagnosticSettingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DiagnosticSettingList contains a list of DiagnosticSetting items
+type DiagnosticSettingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DiagnosticSetting `json:"items"`
+}
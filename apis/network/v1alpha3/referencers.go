@@ -40,6 +40,30 @@ func SubnetID() reference.ExtractValueFn {
 	}
 }
 
+// NATGatewayID extracts status.ID from the supplied managed resource, which
+// must be a NATGateway.
+func NATGatewayID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		g, ok := mg.(*NATGateway)
+		if !ok {
+			return ""
+		}
+		return g.Status.ID
+	}
+}
+
+// ServiceEndpointPolicyID extracts status.ID from the supplied managed
+// resource, which must be a ServiceEndpointPolicy.
+func ServiceEndpointPolicyID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		p, ok := mg.(*ServiceEndpointPolicy)
+		if !ok {
+			return ""
+		}
+		return p.Status.ID
+	}
+}
+
 // ResolveReferences of this VirtualNetwork
 func (mg *VirtualNetwork) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPIResolver(c, mg)
@@ -93,5 +117,75 @@ func (mg *Subnet) ResolveReferences(ctx context.Context, c client.Reader) error
 	mg.Spec.VirtualNetworkName = rsp.ResolvedValue
 	mg.Spec.VirtualNetworkNameRef = rsp.ResolvedReference
 
+	// Resolve spec.properties.natGatewayId
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.NATGatewayID),
+		Reference:    mg.Spec.NATGatewayIDRef,
+		Selector:     mg.Spec.NATGatewayIDSelector,
+		To:           reference.To{Managed: &NATGateway{}, List: &NATGatewayList{}},
+		Extract:      NATGatewayID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.properties.natGatewayId")
+	}
+	mg.Spec.NATGatewayID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.NATGatewayIDRef = rsp.ResolvedReference
+
+	// Resolve spec.properties.serviceEndpointPolicyId
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ServiceEndpointPolicyID),
+		Reference:    mg.Spec.ServiceEndpointPolicyIDRef,
+		Selector:     mg.Spec.ServiceEndpointPolicyIDSelector,
+		To:           reference.To{Managed: &ServiceEndpointPolicy{}, List: &ServiceEndpointPolicyList{}},
+		Extract:      ServiceEndpointPolicyID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.properties.serviceEndpointPolicyId")
+	}
+	mg.Spec.ServiceEndpointPolicyID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ServiceEndpointPolicyIDRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this NATGateway
+func (mg *NATGateway) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.resourceGroupName
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ResourceGroupName,
+		Reference:    mg.Spec.ResourceGroupNameRef,
+		Selector:     mg.Spec.ResourceGroupNameSelector,
+		To:           reference.To{Managed: &v1alpha3.ResourceGroup{}, List: &v1alpha3.ResourceGroupList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.resourceGroupName")
+	}
+	mg.Spec.ResourceGroupName = rsp.ResolvedValue
+	mg.Spec.ResourceGroupNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this ServiceEndpointPolicy
+func (mg *ServiceEndpointPolicy) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.resourceGroupName
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ResourceGroupName,
+		Reference:    mg.Spec.ResourceGroupNameRef,
+		Selector:     mg.Spec.ResourceGroupNameSelector,
+		To:           reference.To{Managed: &v1alpha3.ResourceGroup{}, List: &v1alpha3.ResourceGroupList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.resourceGroupName")
+	}
+	mg.Spec.ResourceGroupName = rsp.ResolvedValue
+	mg.Spec.ResourceGroupNameRef = rsp.ResolvedReference
+
 	return nil
 }
@@ -53,7 +53,25 @@ var (
 	SubnetGroupVersionKind = SchemeGroupVersion.WithKind(SubnetKind)
 )
 
+// NATGateway type metadata.
+var (
+	NATGatewayKind             = reflect.TypeOf(NATGateway{}).Name()
+	NATGatewayGroupKind        = schema.GroupKind{Group: Group, Kind: NATGatewayKind}.String()
+	NATGatewayKindAPIVersion   = NATGatewayKind + "." + SchemeGroupVersion.String()
+	NATGatewayGroupVersionKind = SchemeGroupVersion.WithKind(NATGatewayKind)
+)
+
+// ServiceEndpointPolicy type metadata.
+var (
+	ServiceEndpointPolicyKind             = reflect.TypeOf(ServiceEndpointPolicy{}).Name()
+	ServiceEndpointPolicyGroupKind        = schema.GroupKind{Group: Group, Kind: ServiceEndpointPolicyKind}.String()
+	ServiceEndpointPolicyKindAPIVersion   = ServiceEndpointPolicyKind + "." + SchemeGroupVersion.String()
+	ServiceEndpointPolicyGroupVersionKind = SchemeGroupVersion.WithKind(ServiceEndpointPolicyKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&VirtualNetwork{}, &VirtualNetworkList{})
 	SchemeBuilder.Register(&Subnet{}, &SubnetList{})
+	SchemeBuilder.Register(&NATGateway{}, &NATGatewayList{})
+	SchemeBuilder.Register(&ServiceEndpointPolicy{}, &ServiceEndpointPolicyList{})
 }
@@ -43,10 +43,74 @@ type VirtualNetworkPropertiesFormat struct {
 	// +optional
 	EnableDDOSProtection bool `json:"enableDdosProtection,omitempty"`
 
+	// DDOSProtectionPlanID - The ID of the DDoS protection plan associated
+	// with the virtual network.
+	// +optional
+	DDOSProtectionPlanID *string `json:"ddosProtectionPlanId,omitempty"`
+
+	// Subnets - Subnets to reconcile as part of the virtual network. Subnets
+	// declared here must not also be managed by a Subnet resource. Removing
+	// a subnet from this list does not delete it from the virtual network;
+	// subnets that are not declared here are preserved, so it must be
+	// deleted in Azure.
+	// +optional
+	Subnets []InlineSubnet `json:"subnets,omitempty"`
+
 	// EnableVMProtection - Indicates if VM protection is enabled for all the
 	// subnets in the virtual network.
 	// +optional
 	EnableVMProtection bool `json:"enableVmProtection,omitempty"`
+
+	// Encryption - Indicates if encryption is enabled on the virtual network
+	// and if VMs without encryption are allowed in it. Virtual network
+	// encryption is only available in some regions.
+	// +optional
+	Encryption *VirtualNetworkEncryption `json:"encryption,omitempty"`
+
+	// FlowTimeoutInMinutes - The FlowTimeout value (in minutes) for the
+	// virtual network.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	FlowTimeoutInMinutes *int `json:"flowTimeoutInMinutes,omitempty"`
+
+	// BGPCommunities - The BGP communities sent over ExpressRoute with
+	// traffic from the virtual network, for example to identify the spokes
+	// of a hub and spoke topology.
+	// +optional
+	BGPCommunities *VirtualNetworkBGPCommunities `json:"bgpCommunities,omitempty"`
+}
+
+// VirtualNetworkBGPCommunities configures the BGP communities of a virtual
+// network.
+type VirtualNetworkBGPCommunities struct {
+	// VirtualNetworkCommunity - The BGP community associated with the virtual
+	// network, of the form 12076:<value> where value is between 20000 and
+	// 49999.
+	VirtualNetworkCommunity string `json:"virtualNetworkCommunity"`
+}
+
+// VirtualNetworkEncryption configures encryption of traffic within a virtual
+// network.
+type VirtualNetworkEncryption struct {
+	// Enabled - Indicates if encryption is enabled on the virtual network.
+	Enabled bool `json:"enabled"`
+
+	// Enforcement - Indicates whether VMs without encryption support are
+	// allowed in the encrypted virtual network. It may only be set when
+	// encryption is enabled.
+	// +kubebuilder:validation:Enum=DropUnencrypted;AllowUnencrypted
+	// +optional
+	Enforcement *string `json:"enforcement,omitempty"`
+}
+
+// An InlineSubnet is a subnet declared as part of a VirtualNetwork.
+type InlineSubnet struct {
+	// Name - Name of the subnet.
+	Name string `json:"name"`
+
+	// SubnetPropertiesFormat - Properties of the subnet.
+	SubnetPropertiesFormat `json:"properties"`
 }
 
 // A VirtualNetworkSpec defines the desired state of a VirtualNetwork.
@@ -98,6 +162,27 @@ type VirtualNetworkStatus struct {
 
 	// Type of this VirtualNetwork.
 	Type string `json:"type,omitempty"`
+
+	// Location of this VirtualNetwork.
+	Location string `json:"location,omitempty"`
+
+	// Subnets of this VirtualNetwork, whether declared inline or managed by a
+	// Subnet.
+	Subnets []VirtualNetworkSubnet `json:"subnets,omitempty"`
+
+	// ObservedGeneration is the generation of this VirtualNetwork's spec that
+	// was last applied to Azure. Differences from Azure are only reported as
+	// drift while it is the current generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// A VirtualNetworkSubnet identifies a subnet of a VirtualNetwork.
+type VirtualNetworkSubnet struct {
+	// Name of this subnet.
+	Name string `json:"name"`
+
+	// ID of this subnet.
+	ID string `json:"id"`
 }
 
 // +kubebuilder:object:root=true
@@ -145,11 +230,44 @@ type ServiceEndpointPropertiesFormat struct {
 
 // SubnetPropertiesFormat defines properties of a Subnet.
 type SubnetPropertiesFormat struct {
-	// AddressPrefix - The address prefix for the subnet.
-	AddressPrefix string `json:"addressPrefix"`
+	// AddressPrefix - The address prefix for the subnet. Exactly one of
+	// AddressPrefix and AddressPrefixes must be set.
+	// +optional
+	AddressPrefix string `json:"addressPrefix,omitempty"`
+
+	// AddressPrefixes - The address prefixes for the subnet, for example an
+	// IPv4 and an IPv6 prefix for a dual-stack subnet. Exactly one of
+	// AddressPrefix and AddressPrefixes must be set.
+	// +optional
+	AddressPrefixes []string `json:"addressPrefixes,omitempty"`
 
 	// ServiceEndpoints - An array of service endpoints.
 	ServiceEndpoints []ServiceEndpointPropertiesFormat `json:"serviceEndpoints,omitempty"`
+
+	// PrivateEndpointNetworkPolicies - Enable or disable applying network
+	// policies on private endpoints in the subnet. Must be Disabled for
+	// subnets that host private endpoints.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	PrivateEndpointNetworkPolicies *string `json:"privateEndpointNetworkPolicies,omitempty"`
+
+	// PrivateLinkServiceNetworkPolicies - Enable or disable applying network
+	// policies on private link services in the subnet. Must be Disabled for
+	// subnets that host private link services.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	PrivateLinkServiceNetworkPolicies *string `json:"privateLinkServiceNetworkPolicies,omitempty"`
+
+	// NATGatewayID - The ID of the NAT gateway associated with the subnet.
+	// The subnet's NAT gateway association is not managed if omitted.
+	// +optional
+	NATGatewayID *string `json:"natGatewayId,omitempty"`
+
+	// ServiceEndpointPolicyID - The ID of the service endpoint policy
+	// associated with the subnet. The subnet is disassociated from any
+	// service endpoint policy if omitted.
+	// +optional
+	ServiceEndpointPolicyID *string `json:"serviceEndpointPolicyId,omitempty"`
 }
 
 // A SubnetSpec defines the desired state of a Subnet.
@@ -176,6 +294,25 @@ type SubnetSpec struct {
 	// resource group.
 	ResourceGroupNameSelector *runtimev1alpha1.Selector `json:"resourceGroupNameSelector,omitempty"`
 
+	// NATGatewayIDRef - A reference to a NATGateway to retrieve its ID.
+	// +optional
+	NATGatewayIDRef *runtimev1alpha1.Reference `json:"natGatewayIdRef,omitempty"`
+
+	// NATGatewayIDSelector - Selects a reference to a NATGateway to retrieve
+	// its ID.
+	// +optional
+	NATGatewayIDSelector *runtimev1alpha1.Selector `json:"natGatewayIdSelector,omitempty"`
+
+	// ServiceEndpointPolicyIDRef - A reference to a ServiceEndpointPolicy to
+	// retrieve its ID.
+	// +optional
+	ServiceEndpointPolicyIDRef *runtimev1alpha1.Reference `json:"serviceEndpointPolicyIdRef,omitempty"`
+
+	// ServiceEndpointPolicyIDSelector - Selects a reference to a
+	// ServiceEndpointPolicy to retrieve its ID.
+	// +optional
+	ServiceEndpointPolicyIDSelector *runtimev1alpha1.Selector `json:"serviceEndpointPolicyIdSelector,omitempty"`
+
 	// SubnetPropertiesFormat - Properties of the subnet.
 	SubnetPropertiesFormat `json:"properties"`
 }
@@ -199,12 +336,32 @@ type SubnetStatus struct {
 	// Purpose - A string identifying the intention of use for this subnet based
 	// on delegations and other user-defined properties.
 	Purpose string `json:"purpose,omitempty"`
+
+	// AddressPrefix - The address prefix of this Subnet.
+	AddressPrefix string `json:"addressPrefix,omitempty"`
+
+	// AddressPrefixes - The address prefixes of this Subnet, if it has more
+	// than one.
+	AddressPrefixes []string `json:"addressPrefixes,omitempty"`
+
+	// AvailableIPAddressCount - The number of IP addresses in this Subnet
+	// that are neither reserved by Azure nor allocated to an IP
+	// configuration. It is derived from the Subnet's IPv4 address
+	// prefixes, and omitted if it has none.
+	// +optional
+	AvailableIPAddressCount *int `json:"availableIpAddressCount,omitempty"`
+
+	// ObservedGeneration is the generation of this Subnet's spec that was
+	// last applied to Azure. Differences from Azure are only reported as
+	// drift while it is the current generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 
 // A Subnet is a managed resource that represents an Azure Subnet.
 // +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="AVAILABLE-IPS",type="integer",JSONPath=".status.availableIpAddressCount"
 // +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.location"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
@@ -225,3 +382,214 @@ type SubnetList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []Subnet `json:"items"`
 }
+
+// NATGatewayPropertiesFormat defines properties of a NATGateway.
+type NATGatewayPropertiesFormat struct {
+	// IdleTimeoutInMinutes - The idle timeout of the NAT gateway.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=120
+	// +optional
+	IdleTimeoutInMinutes *int `json:"idleTimeoutInMinutes,omitempty"`
+
+	// PublicIPAddressIDs - The IDs of the public IP addresses associated with
+	// the NAT gateway.
+	// +optional
+	PublicIPAddressIDs []string `json:"publicIpAddressIds,omitempty"`
+
+	// PublicIPPrefixIDs - The IDs of the public IP prefixes associated with
+	// the NAT gateway.
+	// +optional
+	PublicIPPrefixIDs []string `json:"publicIpPrefixIds,omitempty"`
+}
+
+// A NATGatewaySpec defines the desired state of a NATGateway.
+type NATGatewaySpec struct {
+	runtimev1alpha1.ResourceSpec `json:",inline"`
+
+	// ResourceGroupName - Name of the NAT gateway's resource group.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef - A reference to the the NAT gateway's resource
+	// group.
+	ResourceGroupNameRef *runtimev1alpha1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector - Selects a reference to the the NAT gateway's
+	// resource group.
+	ResourceGroupNameSelector *runtimev1alpha1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// NATGatewayPropertiesFormat - Properties of the NAT gateway.
+	// +optional
+	NATGatewayPropertiesFormat `json:"properties,omitempty"`
+
+	// Location - Resource location.
+	// +immutable
+	Location string `json:"location"`
+
+	// Zones - The availability zones in which to deploy the NAT gateway.
+	// +immutable
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// Tags - Resource tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// A NATGatewayStatus represents the observed state of a NATGateway.
+type NATGatewayStatus struct {
+	runtimev1alpha1.ResourceStatus `json:",inline"`
+
+	// State of this NATGateway.
+	State string `json:"state,omitempty"`
+
+	// ID of this NATGateway.
+	ID string `json:"id,omitempty"`
+
+	// Etag - A unique read-only string that changes whenever the resource is
+	// updated.
+	Etag string `json:"etag,omitempty"`
+
+	// ResourceGUID - The GUID of this NATGateway.
+	ResourceGUID string `json:"resourceGuid,omitempty"`
+
+	// SubnetIDs - The IDs of the subnets associated with this NATGateway.
+	SubnetIDs []string `json:"subnetIds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A NATGateway is a managed resource that represents an Azure NAT gateway.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type NATGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NATGatewaySpec   `json:"spec"`
+	Status NATGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NATGatewayList contains a list of NATGateway items
+type NATGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NATGateway `json:"items"`
+}
+
+// A ServiceEndpointPolicyDefinition restricts the service resources that may
+// be reached through a service endpoint.
+type ServiceEndpointPolicyDefinition struct {
+	// Name - Name of the definition.
+	Name string `json:"name"`
+
+	// Description - A description of the definition.
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Service - The service endpoint the definition applies to, for example
+	// Microsoft.Storage.
+	Service string `json:"service"`
+
+	// ServiceResources - The IDs of the service resources that may be reached
+	// through the service endpoint, for example storage accounts, resource
+	// groups, or subscriptions.
+	ServiceResources []string `json:"serviceResources"`
+}
+
+// ServiceEndpointPolicyPropertiesFormat defines properties of a
+// ServiceEndpointPolicy.
+type ServiceEndpointPolicyPropertiesFormat struct {
+	// ServiceEndpointPolicyDefinitions - The definitions of the service
+	// endpoint policy.
+	// +optional
+	ServiceEndpointPolicyDefinitions []ServiceEndpointPolicyDefinition `json:"serviceEndpointPolicyDefinitions,omitempty"`
+}
+
+// A ServiceEndpointPolicySpec defines the desired state of a
+// ServiceEndpointPolicy.
+type ServiceEndpointPolicySpec struct {
+	runtimev1alpha1.ResourceSpec `json:",inline"`
+
+	// ResourceGroupName - Name of the service endpoint policy's resource
+	// group.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef - A reference to the the service endpoint policy's
+	// resource group.
+	ResourceGroupNameRef *runtimev1alpha1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector - Selects a reference to the the service
+	// endpoint policy's resource group.
+	ResourceGroupNameSelector *runtimev1alpha1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// ServiceEndpointPolicyPropertiesFormat - Properties of the service
+	// endpoint policy.
+	// +optional
+	ServiceEndpointPolicyPropertiesFormat `json:"properties,omitempty"`
+
+	// Location - Resource location.
+	// +immutable
+	Location string `json:"location"`
+
+	// Tags - Resource tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// A ServiceEndpointPolicyStatus represents the observed state of a
+// ServiceEndpointPolicy.
+type ServiceEndpointPolicyStatus struct {
+	runtimev1alpha1.ResourceStatus `json:",inline"`
+
+	// State of this ServiceEndpointPolicy.
+	State string `json:"state,omitempty"`
+
+	// ID of this ServiceEndpointPolicy.
+	ID string `json:"id,omitempty"`
+
+	// Etag - A unique read-only string that changes whenever the resource is
+	// updated.
+	Etag string `json:"etag,omitempty"`
+
+	// ResourceGUID - The GUID of this ServiceEndpointPolicy.
+	ResourceGUID string `json:"resourceGuid,omitempty"`
+
+	// SubnetIDs - The IDs of the subnets associated with this
+	// ServiceEndpointPolicy.
+	SubnetIDs []string `json:"subnetIds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ServiceEndpointPolicy is a managed resource that represents an Azure
+// service endpoint policy.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type ServiceEndpointPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceEndpointPolicySpec   `json:"spec"`
+	Status ServiceEndpointPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServiceEndpointPolicyList contains a list of ServiceEndpointPolicy items
+type ServiceEndpointPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceEndpointPolicy `json:"items"`
+}
@@ -45,6 +45,332 @@ func (in *AddressSpace) DeepCopy() *AddressSpace {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineSubnet) DeepCopyInto(out *InlineSubnet) {
+	*out = *in
+	in.SubnetPropertiesFormat.DeepCopyInto(&out.SubnetPropertiesFormat)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineSubnet.
+func (in *InlineSubnet) DeepCopy() *InlineSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGateway) DeepCopyInto(out *NATGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGateway.
+func (in *NATGateway) DeepCopy() *NATGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NATGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewayList) DeepCopyInto(out *NATGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NATGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewayList.
+func (in *NATGatewayList) DeepCopy() *NATGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NATGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewayPropertiesFormat) DeepCopyInto(out *NATGatewayPropertiesFormat) {
+	*out = *in
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.PublicIPAddressIDs != nil {
+		in, out := &in.PublicIPAddressIDs, &out.PublicIPAddressIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicIPPrefixIDs != nil {
+		in, out := &in.PublicIPPrefixIDs, &out.PublicIPPrefixIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewayPropertiesFormat.
+func (in *NATGatewayPropertiesFormat) DeepCopy() *NATGatewayPropertiesFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewayPropertiesFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewaySpec) DeepCopyInto(out *NATGatewaySpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.ResourceGroupNameRef != nil {
+		in, out := &in.ResourceGroupNameRef, &out.ResourceGroupNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ResourceGroupNameSelector != nil {
+		in, out := &in.ResourceGroupNameSelector, &out.ResourceGroupNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.NATGatewayPropertiesFormat.DeepCopyInto(&out.NATGatewayPropertiesFormat)
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewaySpec.
+func (in *NATGatewaySpec) DeepCopy() *NATGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewayStatus) DeepCopyInto(out *NATGatewayStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewayStatus.
+func (in *NATGatewayStatus) DeepCopy() *NATGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicy) DeepCopyInto(out *ServiceEndpointPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicy.
+func (in *ServiceEndpointPolicy) DeepCopy() *ServiceEndpointPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceEndpointPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicyDefinition) DeepCopyInto(out *ServiceEndpointPolicyDefinition) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceResources != nil {
+		in, out := &in.ServiceResources, &out.ServiceResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicyDefinition.
+func (in *ServiceEndpointPolicyDefinition) DeepCopy() *ServiceEndpointPolicyDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicyDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicyList) DeepCopyInto(out *ServiceEndpointPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceEndpointPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicyList.
+func (in *ServiceEndpointPolicyList) DeepCopy() *ServiceEndpointPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceEndpointPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicyPropertiesFormat) DeepCopyInto(out *ServiceEndpointPolicyPropertiesFormat) {
+	*out = *in
+	if in.ServiceEndpointPolicyDefinitions != nil {
+		in, out := &in.ServiceEndpointPolicyDefinitions, &out.ServiceEndpointPolicyDefinitions
+		*out = make([]ServiceEndpointPolicyDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicyPropertiesFormat.
+func (in *ServiceEndpointPolicyPropertiesFormat) DeepCopy() *ServiceEndpointPolicyPropertiesFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicyPropertiesFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicySpec) DeepCopyInto(out *ServiceEndpointPolicySpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.ResourceGroupNameRef != nil {
+		in, out := &in.ResourceGroupNameRef, &out.ResourceGroupNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ResourceGroupNameSelector != nil {
+		in, out := &in.ResourceGroupNameSelector, &out.ResourceGroupNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ServiceEndpointPolicyPropertiesFormat.DeepCopyInto(&out.ServiceEndpointPolicyPropertiesFormat)
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicySpec.
+func (in *ServiceEndpointPolicySpec) DeepCopy() *ServiceEndpointPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointPolicyStatus) DeepCopyInto(out *ServiceEndpointPolicyStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointPolicyStatus.
+func (in *ServiceEndpointPolicyStatus) DeepCopy() *ServiceEndpointPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceEndpointPropertiesFormat) DeepCopyInto(out *ServiceEndpointPropertiesFormat) {
 	*out = *in
@@ -127,6 +453,11 @@ func (in *SubnetList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetPropertiesFormat) DeepCopyInto(out *SubnetPropertiesFormat) {
 	*out = *in
+	if in.AddressPrefixes != nil {
+		in, out := &in.AddressPrefixes, &out.AddressPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ServiceEndpoints != nil {
 		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
 		*out = make([]ServiceEndpointPropertiesFormat, len(*in))
@@ -134,6 +465,26 @@ func (in *SubnetPropertiesFormat) DeepCopyInto(out *SubnetPropertiesFormat) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PrivateEndpointNetworkPolicies != nil {
+		in, out := &in.PrivateEndpointNetworkPolicies, &out.PrivateEndpointNetworkPolicies
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrivateLinkServiceNetworkPolicies != nil {
+		in, out := &in.PrivateLinkServiceNetworkPolicies, &out.PrivateLinkServiceNetworkPolicies
+		*out = new(string)
+		**out = **in
+	}
+	if in.NATGatewayID != nil {
+		in, out := &in.NATGatewayID, &out.NATGatewayID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceEndpointPolicyID != nil {
+		in, out := &in.ServiceEndpointPolicyID, &out.ServiceEndpointPolicyID
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPropertiesFormat.
@@ -170,6 +521,26 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 		*out = new(v1alpha1.Selector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NATGatewayIDRef != nil {
+		in, out := &in.NATGatewayIDRef, &out.NATGatewayIDRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.NATGatewayIDSelector != nil {
+		in, out := &in.NATGatewayIDSelector, &out.NATGatewayIDSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceEndpointPolicyIDRef != nil {
+		in, out := &in.ServiceEndpointPolicyIDRef, &out.ServiceEndpointPolicyIDRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ServiceEndpointPolicyIDSelector != nil {
+		in, out := &in.ServiceEndpointPolicyIDSelector, &out.ServiceEndpointPolicyIDSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	in.SubnetPropertiesFormat.DeepCopyInto(&out.SubnetPropertiesFormat)
 }
 
@@ -187,6 +558,16 @@ func (in *SubnetSpec) DeepCopy() *SubnetSpec {
 func (in *SubnetStatus) DeepCopyInto(out *SubnetStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.AddressPrefixes != nil {
+		in, out := &in.AddressPrefixes, &out.AddressPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableIPAddressCount != nil {
+		in, out := &in.AvailableIPAddressCount, &out.AvailableIPAddressCount
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetStatus.
@@ -226,6 +607,41 @@ func (in *VirtualNetwork) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkBGPCommunities) DeepCopyInto(out *VirtualNetworkBGPCommunities) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkBGPCommunities.
+func (in *VirtualNetworkBGPCommunities) DeepCopy() *VirtualNetworkBGPCommunities {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkBGPCommunities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkEncryption) DeepCopyInto(out *VirtualNetworkEncryption) {
+	*out = *in
+	if in.Enforcement != nil {
+		in, out := &in.Enforcement, &out.Enforcement
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkEncryption.
+func (in *VirtualNetworkEncryption) DeepCopy() *VirtualNetworkEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNetworkList) DeepCopyInto(out *VirtualNetworkList) {
 	*out = *in
@@ -262,6 +678,33 @@ func (in *VirtualNetworkList) DeepCopyObject() runtime.Object {
 func (in *VirtualNetworkPropertiesFormat) DeepCopyInto(out *VirtualNetworkPropertiesFormat) {
 	*out = *in
 	in.AddressSpace.DeepCopyInto(&out.AddressSpace)
+	if in.DDOSProtectionPlanID != nil {
+		in, out := &in.DDOSProtectionPlanID, &out.DDOSProtectionPlanID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]InlineSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(VirtualNetworkEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FlowTimeoutInMinutes != nil {
+		in, out := &in.FlowTimeoutInMinutes, &out.FlowTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.BGPCommunities != nil {
+		in, out := &in.BGPCommunities, &out.BGPCommunities
+		*out = new(VirtualNetworkBGPCommunities)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkPropertiesFormat.
@@ -312,6 +755,11 @@ func (in *VirtualNetworkSpec) DeepCopy() *VirtualNetworkSpec {
 func (in *VirtualNetworkStatus) DeepCopyInto(out *VirtualNetworkStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]VirtualNetworkSubnet, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkStatus.
@@ -323,3 +771,18 @@ func (in *VirtualNetworkStatus) DeepCopy() *VirtualNetworkStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkSubnet) DeepCopyInto(out *VirtualNetworkSubnet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkSubnet.
+func (in *VirtualNetworkSubnet) DeepCopy() *VirtualNetworkSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,6 +20,24 @@ package v1alpha3
 
 import resource "github.com/crossplane/crossplane-runtime/pkg/resource"
 
+// GetItems of this NATGatewayList.
+func (l *NATGatewayList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+// GetItems of this ServiceEndpointPolicyList.
+func (l *ServiceEndpointPolicyList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this SubnetList.
 func (l *SubnetList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
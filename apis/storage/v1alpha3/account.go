@@ -637,9 +637,10 @@ type StorageAccountSpec struct {
 	// +optional
 	Identity *Identity `json:"identity,omitempty"`
 
-	// Kind - Indicates the type of storage account.
-	// Possible values include: 'Storage', 'BlobStorage'
-	// +kubebuilder:validation:Enum=Storage;BlobStorage
+	// Kind - Indicates the type of storage account. Storage and BlobStorage
+	// accounts may be upgraded in place to StorageV2 by changing their kind.
+	// Possible values include: 'Storage', 'BlobStorage', 'StorageV2'
+	// +kubebuilder:validation:Enum=Storage;BlobStorage;StorageV2
 	Kind storage.Kind `json:"kind"`
 
 	// Location - The location of the resource. This will be one of the
@@ -738,6 +739,12 @@ type StorageAccountStatus struct {
 	// Type of this Account.
 	Type string `json:"type,omitempty"`
 
+	// Location of this Account.
+	Location string `json:"location,omitempty"`
+
+	// Sku of this Account.
+	Sku *Sku `json:"sku,omitempty"`
+
 	// Properties of this Account.
 	*StorageAccountStatusProperties `json:"properties,omitempty"`
 }
@@ -751,6 +758,8 @@ func NewStorageAccountStatus(a *storage.Account) *StorageAccountStatus {
 		ID:                             to.String(a.ID),
 		Name:                           to.String(a.Name),
 		Type:                           to.String(a.Type),
+		Location:                       to.String(a.Location),
+		Sku:                            newSku(a.Sku),
 		StorageAccountStatusProperties: newStorageAccountStatusProperties(a.AccountProperties),
 	}
 }
@@ -938,6 +938,16 @@ func Test_NewStorageAccountStatus(t *testing.T) {
 			args: &storage.Account{},
 			want: &StorageAccountStatus{},
 		},
+		{
+			name: "sku",
+			args: &storage.Account{Sku: &storage.Sku{Name: storage.StandardGRS, Tier: storage.Standard}},
+			want: &StorageAccountStatus{Sku: &Sku{Name: storage.StandardGRS, Tier: storage.Standard}},
+		},
+		{
+			name: "location",
+			args: &storage.Account{ID: to.StringPtr("test-id"), Location: to.StringPtr("westus")},
+			want: &StorageAccountStatus{ID: "test-id", Location: "westus"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
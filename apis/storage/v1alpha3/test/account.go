@@ -25,6 +25,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	storagev1alpha3 "github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
 )
 
 // MockAccount builder for testing account object
@@ -95,6 +96,55 @@ func (ta *MockAccount) WithSpecStorageAccountSpec(spec *storagev1alpha3.StorageA
 	return ta
 }
 
+// WithSpecBlobServiceProperties sets blob service properties
+func (ta *MockAccount) WithSpecBlobServiceProperties(p *storagev1alpha3.BlobServiceProperties) *MockAccount {
+	ta.Spec.BlobServiceProperties = p
+	return ta
+}
+
+// WithBlobServiceStatus sets blob service status
+func (ta *MockAccount) WithBlobServiceStatus(status *storagev1alpha3.BlobServiceStatus) *MockAccount {
+	ta.Status.BlobService = status
+	return ta
+}
+
+// WithSpecRoutingPreference sets routing preference
+func (ta *MockAccount) WithSpecRoutingPreference(p *storagev1alpha3.RoutingPreference) *MockAccount {
+	ta.Spec.RoutingPreference = p
+	return ta
+}
+
+// WithSpecAllowSharedKeyAccess sets whether shared key access is allowed
+func (ta *MockAccount) WithSpecAllowSharedKeyAccess(allow bool) *MockAccount {
+	ta.Spec.AllowSharedKeyAccess = &allow
+	return ta
+}
+
+// WithSpecImmutableStorageWithVersioning sets account-level immutable storage
+func (ta *MockAccount) WithSpecImmutableStorageWithVersioning(s *storagev1alpha3.ImmutableStorageWithVersioning) *MockAccount {
+	ta.Spec.ImmutableStorageWithVersioning = s
+	return ta
+}
+
+// WithRoutingEndpoints sets Microsoft and internet routing endpoints status
+func (ta *MockAccount) WithRoutingEndpoints(microsoft, internet *storagev1alpha3.RoutingEndpoints) *MockAccount {
+	ta.Status.MicrosoftEndpoints = microsoft
+	ta.Status.InternetEndpoints = internet
+	return ta
+}
+
+// WithAnnotations sets the storage account's annotations
+func (ta *MockAccount) WithAnnotations(a map[string]string) *MockAccount {
+	meta.AddAnnotations(ta, a)
+	return ta
+}
+
+// WithLastFailover sets the storage account's last failover status
+func (ta *MockAccount) WithLastFailover(op *v1alpha3.AsyncOperation) *MockAccount {
+	ta.Status.LastFailover = op
+	return ta
+}
+
 // WithStorageAccountStatus set storage account status
 func (ta *MockAccount) WithStorageAccountStatus(status *storagev1alpha3.StorageAccountStatus) *MockAccount {
 	ta.Status.StorageAccountStatus = status
@@ -105,6 +105,30 @@ func (tc *MockContainer) WithSpecMetadata(meta map[string]string) *MockContainer
 	return tc
 }
 
+// WithSpecLegalHoldTags sets spec legal hold tags
+func (tc *MockContainer) WithSpecLegalHoldTags(tags ...string) *MockContainer {
+	tc.Container.Spec.LegalHoldTags = tags
+	return tc
+}
+
+// WithSpecStoredAccessPolicies sets spec stored access policies
+func (tc *MockContainer) WithSpecStoredAccessPolicies(p ...storagev1alpha3.StoredAccessPolicy) *MockContainer {
+	tc.Container.Spec.StoredAccessPolicies = p
+	return tc
+}
+
+// WithSpecForceDelete sets spec force delete
+func (tc *MockContainer) WithSpecForceDelete(f bool) *MockContainer {
+	tc.Container.Spec.ForceDelete = f
+	return tc
+}
+
+// WithStatusLegalHoldTags sets status legal hold tags
+func (tc *MockContainer) WithStatusLegalHoldTags(tags ...string) *MockContainer {
+	tc.Container.Status.LegalHoldTags = tags
+	return tc
+}
+
 // WithStatusConditions sets the conditioned status.
 func (tc *MockContainer) WithStatusConditions(c ...runtimev1alpha1.Condition) *MockContainer {
 	tc.Status.SetConditions(c...)
@@ -21,6 +21,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
 )
 
 // AccountParameters define the desired state of an Azure Blob Storage Account.
@@ -30,6 +32,145 @@ type AccountParameters struct {
 
 	// StorageAccountSpec specifies the desired state of this Account.
 	StorageAccountSpec *StorageAccountSpec `json:"storageAccountSpec"`
+
+	// BlobServiceProperties specifies the desired state of this Account's
+	// blob service.
+	// +optional
+	BlobServiceProperties *BlobServiceProperties `json:"blobServiceProperties,omitempty"`
+
+	// RoutingPreference specifies how this Account's data is routed between
+	// Azure and its clients, and which routing specific endpoints it
+	// publishes.
+	// +optional
+	RoutingPreference *RoutingPreference `json:"routingPreference,omitempty"`
+
+	// AllowBlobPublicAccess specifies whether this Account's blobs and
+	// containers may be made publicly readable. Azure allows it if
+	// unspecified.
+	// +optional
+	AllowBlobPublicAccess *bool `json:"allowBlobPublicAccess,omitempty"`
+
+	// AllowSharedKeyAccess specifies whether requests to this Account may be
+	// authorized with its access keys. If false, requests must be authorized
+	// with Azure Active Directory, and the Account's connection secret omits
+	// its access key. Containers of an Account that disallows shared key
+	// access cannot be managed. Azure allows it if unspecified.
+	// +optional
+	AllowSharedKeyAccess *bool `json:"allowSharedKeyAccess,omitempty"`
+
+	// IgnoreTagKeyCase specifies whether the keys of this Account's tags are
+	// compared case-insensitively when determining whether they have drifted.
+	// Enable it when an Azure policy normalizes the casing of tag keys, to
+	// avoid repeatedly reverting the policy's changes. Updates then use the
+	// casing of tag keys that already exist in Azure. Tag keys are compared
+	// case-sensitively if unspecified.
+	// +optional
+	IgnoreTagKeyCase *bool `json:"ignoreTagKeyCase,omitempty"`
+
+	// ImmutableStorageWithVersioning configures account-level immutable
+	// (i.e. write once, read many) storage of this Account's blobs. It can
+	// only be configured when the Account is created.
+	// +immutable
+	// +optional
+	ImmutableStorageWithVersioning *ImmutableStorageWithVersioning `json:"immutableStorageWithVersioning,omitempty"`
+}
+
+// ImmutableStorageWithVersioning configures account-level immutable storage
+// of an Account's blobs.
+type ImmutableStorageWithVersioning struct {
+	// Enabled specifies whether blobs may be made immutable. Azure enables
+	// blob versioning, which immutable storage requires, when set.
+	Enabled bool `json:"enabled"`
+
+	// ImmutabilityPolicy is the default time-based retention policy of the
+	// Account's blobs.
+	// +optional
+	ImmutabilityPolicy *AccountImmutabilityPolicy `json:"immutabilityPolicy,omitempty"`
+}
+
+// An AccountImmutabilityPolicy configures time-based retention of blobs.
+type AccountImmutabilityPolicy struct {
+	// ImmutabilityPeriodSinceCreationInDays is the number of days for which
+	// blobs are immutable after they are created.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=146000
+	ImmutabilityPeriodSinceCreationInDays int32 `json:"immutabilityPeriodSinceCreationInDays"`
+
+	// State of the policy. A Locked policy cannot be disabled, and its
+	// period can only be increased. Azure uses Unlocked if no state is
+	// specified.
+	// +kubebuilder:validation:Enum=Unlocked;Locked;Disabled
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// AllowProtectedAppendWrites specifies whether new blocks may be
+	// appended to immutable append blobs.
+	// +optional
+	AllowProtectedAppendWrites bool `json:"allowProtectedAppendWrites,omitempty"`
+}
+
+// A RoutingPreference configures the network routing of an Account.
+type RoutingPreference struct {
+	// RoutingChoice specifies whether data is routed via the Microsoft
+	// global network or via the public internet by default. Azure uses
+	// MicrosoftRouting if no choice is specified.
+	// +kubebuilder:validation:Enum=MicrosoftRouting;InternetRouting
+	// +optional
+	RoutingChoice string `json:"routingChoice,omitempty"`
+
+	// PublishMicrosoftEndpoints specifies whether endpoints that route data
+	// via the Microsoft global network are published.
+	// +optional
+	PublishMicrosoftEndpoints bool `json:"publishMicrosoftEndpoints,omitempty"`
+
+	// PublishInternetEndpoints specifies whether endpoints that route data
+	// via the public internet are published.
+	// +optional
+	PublishInternetEndpoints bool `json:"publishInternetEndpoints,omitempty"`
+}
+
+// BlobServiceProperties configure the blob service of an Account.
+type BlobServiceProperties struct {
+	// DeleteRetentionPolicy configures soft delete of blobs.
+	// +optional
+	DeleteRetentionPolicy *DeleteRetentionPolicy `json:"deleteRetentionPolicy,omitempty"`
+
+	// ContainerDeleteRetentionPolicy configures soft delete of containers.
+	// +optional
+	ContainerDeleteRetentionPolicy *DeleteRetentionPolicy `json:"containerDeleteRetentionPolicy,omitempty"`
+
+	// RestorePolicy configures point-in-time restore of block blobs. Blob
+	// versioning and change feed are enabled automatically when restore is
+	// enabled. Restore also requires blobs to be soft deleted for more days
+	// than they may be restored; blob soft delete is enabled automatically
+	// if no DeleteRetentionPolicy is specified.
+	// +optional
+	RestorePolicy *RestorePolicy `json:"restorePolicy,omitempty"`
+}
+
+// A DeleteRetentionPolicy configures how long deleted items are retained.
+type DeleteRetentionPolicy struct {
+	// Enabled specifies whether deleted items are retained.
+	Enabled bool `json:"enabled"`
+
+	// Days for which deleted items are retained.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=365
+	// +optional
+	Days *int32 `json:"days,omitempty"`
+}
+
+// A RestorePolicy configures point-in-time restore of block blobs.
+type RestorePolicy struct {
+	// Enabled specifies whether block blobs may be restored.
+	Enabled bool `json:"enabled"`
+
+	// Days for which block blobs may be restored. Required when restore is
+	// enabled, and must be less than the days of the DeleteRetentionPolicy.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=364
+	// +optional
+	Days *int32 `json:"days,omitempty"`
 }
 
 // An AccountSpec defines the desired state of an Account.
@@ -43,6 +184,58 @@ type AccountStatus struct {
 	runtimev1alpha1.ResourceStatus `json:",inline"`
 
 	*StorageAccountStatus `json:",inline"`
+
+	// BlobService represents the observed state of this Account's blob
+	// service. It is only reported when BlobServiceProperties are specified.
+	BlobService *BlobServiceStatus `json:"blobService,omitempty"`
+
+	// MicrosoftEndpoints are the published endpoints of this Account that
+	// route data via the Microsoft global network.
+	MicrosoftEndpoints *RoutingEndpoints `json:"microsoftEndpoints,omitempty"`
+
+	// InternetEndpoints are the published endpoints of this Account that
+	// route data via the public internet.
+	InternetEndpoints *RoutingEndpoints `json:"internetEndpoints,omitempty"`
+
+	// LastFailover is the most recently requested failover of this Account
+	// to its secondary region.
+	LastFailover *v1alpha3.AsyncOperation `json:"lastFailover,omitempty"`
+}
+
+// RoutingEndpoints are the routing specific endpoints of an Account.
+type RoutingEndpoints struct {
+	// Blob - the blob endpoint.
+	Blob string `json:"blob,omitempty"`
+	// Queue - the queue endpoint.
+	Queue string `json:"queue,omitempty"`
+	// Table - the table endpoint.
+	Table string `json:"table,omitempty"`
+	// File - the file endpoint.
+	File string `json:"file,omitempty"`
+	// Web - the web endpoint.
+	Web string `json:"web,omitempty"`
+	// Dfs - the data lake storage endpoint.
+	Dfs string `json:"dfs,omitempty"`
+}
+
+// A BlobServiceStatus represents the observed state of an Account's blob
+// service.
+type BlobServiceStatus struct {
+	// IsVersioningEnabled indicates whether blob versioning is enabled.
+	IsVersioningEnabled bool `json:"isVersioningEnabled,omitempty"`
+
+	// ChangeFeedEnabled indicates whether the change feed is enabled.
+	ChangeFeedEnabled bool `json:"changeFeedEnabled,omitempty"`
+
+	// RestorePolicyLastEnabledTime is the time at which point-in-time
+	// restore was last enabled.
+	RestorePolicyLastEnabledTime *metav1.Time `json:"restorePolicyLastEnabledTime,omitempty"`
+
+	// MinRestoreTime is the earliest time to which block blobs may be
+	// restored, i.e. the later of the time at which point-in-time restore was
+	// enabled and the restore policy's days before the account was last
+	// observed. It is omitted when point-in-time restore is disabled.
+	MinRestoreTime *metav1.Time `json:"minRestoreTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -80,6 +273,50 @@ type ContainerParameters struct {
 	// PublicAccessType for this container; either "blob" or "container".
 	// +optional
 	PublicAccessType azblob.PublicAccessType `json:"publicAccessType,omitempty"`
+
+	// LegalHoldTags that should be applied to this container. Each tag must
+	// be 3 to 23 alphanumeric characters. Legal hold tags are only managed
+	// while this field lists at least one tag, during which legal hold tags
+	// that exist in Azure but are not listed here will be cleared. Clearing
+	// this field clears all legal hold tags once, after which legal hold tags
+	// added outside of Crossplane are left as they are.
+	// +optional
+	LegalHoldTags []string `json:"legalHoldTags,omitempty"`
+
+	// StoredAccessPolicies of this container. Shared access signatures (SAS)
+	// that reference a stored access policy by ID inherit its permissions
+	// and validity period, and may be revoked by changing or removing it.
+	// Stored access policies that exist in Azure but are not listed here will
+	// be removed. Stored access policies are not managed if this field is
+	// unset.
+	// +kubebuilder:validation:MaxItems=5
+	// +optional
+	StoredAccessPolicies []StoredAccessPolicy `json:"storedAccessPolicies,omitempty"`
+
+	// ForceDelete this container when it is deleted, along with any blobs
+	// it contains. By default a container that contains blobs, including soft
+	// deleted blobs and previous versions of blobs, is not deleted.
+	// Storage accounts are always deleted along with their containers.
+	// +optional
+	ForceDelete bool `json:"forceDelete,omitempty"`
+}
+
+// A StoredAccessPolicy (or signed identifier) of a Container.
+type StoredAccessPolicy struct {
+	// ID of this policy, by which shared access signatures reference it.
+	// +kubebuilder:validation:MaxLength=64
+	ID string `json:"id"`
+
+	// Permissions granted by this policy; any combination of r (read), a
+	// (add), c (create), w (write), d (delete), and l (list), e.g. "rl".
+	// +kubebuilder:validation:Pattern=`^[racwdl]+$`
+	Permissions string `json:"permissions"`
+
+	// Start time from which this policy is valid.
+	Start metav1.Time `json:"start"`
+
+	// Expiry time after which this policy is no longer valid.
+	Expiry metav1.Time `json:"expiry"`
 }
 
 // A ContainerSpec defines the desired state of a Container.
@@ -91,6 +328,9 @@ type ContainerSpec struct {
 // A ContainerStatus represents the observed status of a Container.
 type ContainerStatus struct {
 	runtimev1alpha1.ResourceStatus `json:",inline"`
+
+	// LegalHoldTags that are applied to this container.
+	LegalHoldTags []string `json:"legalHoldTags,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -119,3 +359,119 @@ type ContainerList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []Container `json:"items"`
 }
+
+// FileShareParameters define the desired state of an Azure File Share.
+type FileShareParameters struct {
+	// ResourceGroupName specifies the resource group of this FileShare's
+	// Account.
+	// +optional
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef references a ResourceGroup to retrieve its name.
+	// +optional
+	ResourceGroupNameRef *runtimev1alpha1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector selects a ResourceGroup to retrieve its name.
+	// +optional
+	ResourceGroupNameSelector *runtimev1alpha1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// AccountName specifies the storage Account this FileShare belongs to.
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// AccountNameRef references an Account to retrieve its name.
+	// +optional
+	AccountNameRef *runtimev1alpha1.Reference `json:"accountNameRef,omitempty"`
+
+	// AccountNameSelector selects an Account to retrieve its name.
+	// +optional
+	AccountNameSelector *runtimev1alpha1.Selector `json:"accountNameSelector,omitempty"`
+
+	// ShareQuota is the maximum size of the share, in gigabytes. Must be
+	// greater than 0, and less than or equal to 5TB (5120). For Large File
+	// Shares, the maximum size is 102400.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=102400
+	// +optional
+	ShareQuota *int32 `json:"shareQuota,omitempty"`
+
+	// AccessTier for this share. Premium is only available to shares in
+	// FileStorage accounts.
+	// +kubebuilder:validation:Enum=TransactionOptimized;Hot;Cool;Premium
+	// +optional
+	AccessTier *string `json:"accessTier,omitempty"`
+
+	// EnabledProtocols is the authentication protocol used for the share.
+	// Can only be specified when creating a share.
+	// +kubebuilder:validation:Enum=SMB;NFS
+	// +immutable
+	// +optional
+	EnabledProtocols *string `json:"enabledProtocols,omitempty"`
+
+	// Metadata for this FileShare.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// A FileShareSpec defines the desired state of a FileShare.
+type FileShareSpec struct {
+	runtimev1alpha1.ResourceSpec `json:",inline"`
+	ForProvider                  FileShareParameters `json:"forProvider"`
+}
+
+// FileShareObservation represents the observed state of an Azure File Share.
+type FileShareObservation struct {
+	// ID of this FileShare.
+	ID string `json:"id,omitempty"`
+
+	// Name of this FileShare.
+	Name string `json:"name,omitempty"`
+
+	// Type of this FileShare.
+	Type string `json:"type,omitempty"`
+
+	// LastModifiedTime of this FileShare.
+	LastModifiedTime *metav1.Time `json:"lastModifiedTime,omitempty"`
+
+	// AccessTierChangeTime is the last time the access tier was changed.
+	AccessTierChangeTime *metav1.Time `json:"accessTierChangeTime,omitempty"`
+
+	// AccessTierStatus indicates the progress of an access tier change.
+	AccessTierStatus string `json:"accessTierStatus,omitempty"`
+
+	// ShareUsageBytes is the approximate size of the data stored on the
+	// share.
+	ShareUsageBytes *int64 `json:"shareUsageBytes,omitempty"`
+}
+
+// A FileShareStatus represents the observed state of a FileShare.
+type FileShareStatus struct {
+	runtimev1alpha1.ResourceStatus `json:",inline"`
+	AtProvider                     FileShareObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A FileShare is a managed resource that represents an Azure File Share.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STORAGE_ACCOUNT",type="string",JSONPath=".spec.forProvider.accountName"
+// +kubebuilder:printcolumn:name="QUOTA",type="integer",JSONPath=".spec.forProvider.shareQuota"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type FileShare struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              FileShareSpec   `json:"spec"`
+	Status            FileShareStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FileShareList contains a list of FileShare.
+type FileShareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FileShare `json:"items"`
+}
@@ -22,6 +22,8 @@ package v1alpha3
 
 import (
 	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -52,6 +54,21 @@ func (in *Account) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountImmutabilityPolicy) DeepCopyInto(out *AccountImmutabilityPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountImmutabilityPolicy.
+func (in *AccountImmutabilityPolicy) DeepCopy() *AccountImmutabilityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountImmutabilityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountList) DeepCopyInto(out *AccountList) {
 	*out = *in
@@ -92,6 +109,36 @@ func (in *AccountParameters) DeepCopyInto(out *AccountParameters) {
 		*out = new(StorageAccountSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BlobServiceProperties != nil {
+		in, out := &in.BlobServiceProperties, &out.BlobServiceProperties
+		*out = new(BlobServiceProperties)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoutingPreference != nil {
+		in, out := &in.RoutingPreference, &out.RoutingPreference
+		*out = new(RoutingPreference)
+		**out = **in
+	}
+	if in.AllowBlobPublicAccess != nil {
+		in, out := &in.AllowBlobPublicAccess, &out.AllowBlobPublicAccess
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSharedKeyAccess != nil {
+		in, out := &in.AllowSharedKeyAccess, &out.AllowSharedKeyAccess
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreTagKeyCase != nil {
+		in, out := &in.IgnoreTagKeyCase, &out.IgnoreTagKeyCase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImmutableStorageWithVersioning != nil {
+		in, out := &in.ImmutableStorageWithVersioning, &out.ImmutableStorageWithVersioning
+		*out = new(ImmutableStorageWithVersioning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountParameters.
@@ -130,6 +177,26 @@ func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
 		*out = new(StorageAccountStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BlobService != nil {
+		in, out := &in.BlobService, &out.BlobService
+		*out = new(BlobServiceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MicrosoftEndpoints != nil {
+		in, out := &in.MicrosoftEndpoints, &out.MicrosoftEndpoints
+		*out = new(RoutingEndpoints)
+		**out = **in
+	}
+	if in.InternetEndpoints != nil {
+		in, out := &in.InternetEndpoints, &out.InternetEndpoints
+		*out = new(RoutingEndpoints)
+		**out = **in
+	}
+	if in.LastFailover != nil {
+		in, out := &in.LastFailover, &out.LastFailover
+		*out = new(apisv1alpha3.AsyncOperation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
@@ -142,6 +209,59 @@ func (in *AccountStatus) DeepCopy() *AccountStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlobServiceProperties) DeepCopyInto(out *BlobServiceProperties) {
+	*out = *in
+	if in.DeleteRetentionPolicy != nil {
+		in, out := &in.DeleteRetentionPolicy, &out.DeleteRetentionPolicy
+		*out = new(DeleteRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerDeleteRetentionPolicy != nil {
+		in, out := &in.ContainerDeleteRetentionPolicy, &out.ContainerDeleteRetentionPolicy
+		*out = new(DeleteRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestorePolicy != nil {
+		in, out := &in.RestorePolicy, &out.RestorePolicy
+		*out = new(RestorePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobServiceProperties.
+func (in *BlobServiceProperties) DeepCopy() *BlobServiceProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobServiceProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlobServiceStatus) DeepCopyInto(out *BlobServiceStatus) {
+	*out = *in
+	if in.RestorePolicyLastEnabledTime != nil {
+		in, out := &in.RestorePolicyLastEnabledTime, &out.RestorePolicyLastEnabledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MinRestoreTime != nil {
+		in, out := &in.MinRestoreTime, &out.MinRestoreTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobServiceStatus.
+func (in *BlobServiceStatus) DeepCopy() *BlobServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Container) DeepCopyInto(out *Container) {
 	*out = *in
@@ -211,6 +331,18 @@ func (in *ContainerParameters) DeepCopyInto(out *ContainerParameters) {
 			(*out)[key] = val
 		}
 	}
+	if in.LegalHoldTags != nil {
+		in, out := &in.LegalHoldTags, &out.LegalHoldTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StoredAccessPolicies != nil {
+		in, out := &in.StoredAccessPolicies, &out.StoredAccessPolicies
+		*out = make([]StoredAccessPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerParameters.
@@ -244,6 +376,11 @@ func (in *ContainerSpec) DeepCopy() *ContainerSpec {
 func (in *ContainerStatus) DeepCopyInto(out *ContainerStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.LegalHoldTags != nil {
+		in, out := &in.LegalHoldTags, &out.LegalHoldTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerStatus.
@@ -271,6 +408,26 @@ func (in *CustomDomain) DeepCopy() *CustomDomain {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeleteRetentionPolicy) DeepCopyInto(out *DeleteRetentionPolicy) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteRetentionPolicy.
+func (in *DeleteRetentionPolicy) DeepCopy() *DeleteRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeleteRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnabledEncryptionServices) DeepCopyInto(out *EnabledEncryptionServices) {
 	*out = *in
@@ -326,6 +483,184 @@ func (in *Endpoints) DeepCopy() *Endpoints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShare) DeepCopyInto(out *FileShare) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShare.
+func (in *FileShare) DeepCopy() *FileShare {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShare)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FileShare) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShareList) DeepCopyInto(out *FileShareList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FileShare, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShareList.
+func (in *FileShareList) DeepCopy() *FileShareList {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShareList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FileShareList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShareObservation) DeepCopyInto(out *FileShareObservation) {
+	*out = *in
+	if in.LastModifiedTime != nil {
+		in, out := &in.LastModifiedTime, &out.LastModifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AccessTierChangeTime != nil {
+		in, out := &in.AccessTierChangeTime, &out.AccessTierChangeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ShareUsageBytes != nil {
+		in, out := &in.ShareUsageBytes, &out.ShareUsageBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShareObservation.
+func (in *FileShareObservation) DeepCopy() *FileShareObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShareObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShareParameters) DeepCopyInto(out *FileShareParameters) {
+	*out = *in
+	if in.ResourceGroupNameRef != nil {
+		in, out := &in.ResourceGroupNameRef, &out.ResourceGroupNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.ResourceGroupNameSelector != nil {
+		in, out := &in.ResourceGroupNameSelector, &out.ResourceGroupNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccountNameRef != nil {
+		in, out := &in.AccountNameRef, &out.AccountNameRef
+		*out = new(v1alpha1.Reference)
+		**out = **in
+	}
+	if in.AccountNameSelector != nil {
+		in, out := &in.AccountNameSelector, &out.AccountNameSelector
+		*out = new(v1alpha1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShareQuota != nil {
+		in, out := &in.ShareQuota, &out.ShareQuota
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AccessTier != nil {
+		in, out := &in.AccessTier, &out.AccessTier
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnabledProtocols != nil {
+		in, out := &in.EnabledProtocols, &out.EnabledProtocols
+		*out = new(string)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShareParameters.
+func (in *FileShareParameters) DeepCopy() *FileShareParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShareParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShareSpec) DeepCopyInto(out *FileShareSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShareSpec.
+func (in *FileShareSpec) DeepCopy() *FileShareSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShareSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileShareStatus) DeepCopyInto(out *FileShareStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileShareStatus.
+func (in *FileShareStatus) DeepCopy() *FileShareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FileShareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPRule) DeepCopyInto(out *IPRule) {
 	*out = *in
@@ -356,6 +691,26 @@ func (in *Identity) DeepCopy() *Identity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableStorageWithVersioning) DeepCopyInto(out *ImmutableStorageWithVersioning) {
+	*out = *in
+	if in.ImmutabilityPolicy != nil {
+		in, out := &in.ImmutabilityPolicy, &out.ImmutabilityPolicy
+		*out = new(AccountImmutabilityPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableStorageWithVersioning.
+func (in *ImmutableStorageWithVersioning) DeepCopy() *ImmutableStorageWithVersioning {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableStorageWithVersioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyVaultProperties) DeepCopyInto(out *KeyVaultProperties) {
 	*out = *in
@@ -396,6 +751,56 @@ func (in *NetworkRuleSet) DeepCopy() *NetworkRuleSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestorePolicy) DeepCopyInto(out *RestorePolicy) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestorePolicy.
+func (in *RestorePolicy) DeepCopy() *RestorePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RestorePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingEndpoints) DeepCopyInto(out *RoutingEndpoints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingEndpoints.
+func (in *RoutingEndpoints) DeepCopy() *RoutingEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingPreference) DeepCopyInto(out *RoutingPreference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingPreference.
+func (in *RoutingPreference) DeepCopy() *RoutingPreference {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingPreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sku) DeepCopyInto(out *Sku) {
 	*out = *in
@@ -491,6 +896,11 @@ func (in *StorageAccountSpecProperties) DeepCopy() *StorageAccountSpecProperties
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageAccountStatus) DeepCopyInto(out *StorageAccountStatus) {
 	*out = *in
+	if in.Sku != nil {
+		in, out := &in.Sku, &out.Sku
+		*out = new(Sku)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageAccountStatusProperties != nil {
 		in, out := &in.StorageAccountStatusProperties, &out.StorageAccountStatusProperties
 		*out = new(StorageAccountStatusProperties)
@@ -541,6 +951,23 @@ func (in *StorageAccountStatusProperties) DeepCopy() *StorageAccountStatusProper
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoredAccessPolicy) DeepCopyInto(out *StoredAccessPolicy) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.Expiry.DeepCopyInto(&out.Expiry)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoredAccessPolicy.
+func (in *StoredAccessPolicy) DeepCopy() *StoredAccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StoredAccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNetworkRule) DeepCopyInto(out *VirtualNetworkRule) {
 	*out = *in
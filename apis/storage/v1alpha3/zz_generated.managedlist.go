@@ -37,3 +37,12 @@ func (l *ContainerList) GetItems() []resource.Managed {
 	}
 	return items
 }
+
+// GetItems of this FileShareList.
+func (l *FileShareList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
@@ -36,6 +36,19 @@ type ProviderSpec struct {
 	// CredentialsSecretRef references a specific secret's key that contains
 	// the credentials that are used to connect to the Azure API.
 	CredentialsSecretRef runtimev1alpha1.SecretKeySelector `json:"credentialsSecretRef"`
+
+	// APIVersions overrides the Azure API version used to manage each kind
+	// of resource, keyed by kind, e.g. Redis. Only supported API versions may
+	// be specified. Omitted kinds use their default API version.
+	// +optional
+	APIVersions map[string]string `json:"apiVersions,omitempty"`
+
+	// OperationTimeout bounds each Azure operation made to observe, create,
+	// update, or delete a managed resource, e.g. 30s. Operations that do not
+	// complete in time fail and are retried. Operations are never allowed to
+	// exceed the reconcile timeout, regardless of this setting.
+	// +optional
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -44,7 +45,7 @@ func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
@@ -101,6 +102,18 @@ func (in *ProviderList) DeepCopyObject() runtime.Object {
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
 	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.APIVersions != nil {
+		in, out := &in.APIVersions, &out.APIVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OperationTimeout != nil {
+		in, out := &in.OperationTimeout, &out.OperationTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
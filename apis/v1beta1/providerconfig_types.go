@@ -25,6 +25,19 @@ import (
 // A ProviderConfigSpec defines the desired state of a ProviderConfig.
 type ProviderConfigSpec struct {
 	runtimev1alpha1.ProviderConfigSpec `json:",inline"`
+
+	// APIVersions overrides the Azure API version used to manage each kind
+	// of resource, keyed by kind, e.g. Redis. Only supported API versions may
+	// be specified. Omitted kinds use their default API version.
+	// +optional
+	APIVersions map[string]string `json:"apiVersions,omitempty"`
+
+	// OperationTimeout bounds each Azure operation made to observe, create,
+	// update, or delete a managed resource, e.g. 30s. Operations that do not
+	// complete in time fail and are retried. Operations are never allowed to
+	// exceed the reconcile timeout, regardless of this setting.
+	// +optional
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
 }
 
 // A ProviderConfigStatus represents the status of a ProviderConfig.
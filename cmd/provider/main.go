@@ -19,15 +19,26 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/provider-azure/apis"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/database"
 	"github.com/crossplane/provider-azure/pkg/controller"
+	"github.com/crossplane/provider-azure/pkg/controller/concurrency"
+	"github.com/crossplane/provider-azure/pkg/controller/deletion"
+	"github.com/crossplane/provider-azure/pkg/controller/drain"
+	"github.com/crossplane/provider-azure/pkg/controller/jitter"
+	"github.com/crossplane/provider-azure/pkg/controller/poll"
+	"github.com/crossplane/provider-azure/pkg/controller/stuck"
 )
 
 func main() {
@@ -36,8 +47,31 @@ func main() {
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncPeriod     = app.Flag("sync", "Controller manager sync period duration such as 300ms, 1.5h or 2h45m").Short('s').Default("1h").Duration()
 		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		leaderElectNS  = app.Flag("leader-election-namespace", "Namespace in which to create the leader election lock. Defaults to the namespace the provider runs in.").String()
+		metricsAddr    = app.Flag("metrics-bind-address", "Address at which to serve Prometheus metrics. Set to 0 to disable.").Default(":8080").String()
+		requeueJitter  = app.Flag("requeue-jitter", "Maximum fraction of a controller's requeue interval to add as random jitter. Set to 0 to disable.").Default(strconv.FormatFloat(jitter.DefaultMaxFactor, 'f', -1, 64)).Float64()
+		healthProbe    = app.Flag("health-probe-bind-address", "Address at which to serve health and readiness probes.").Default(":8081").String()
+		credsCheck     = app.Flag("credentials-check-interval", "Interval at which to check that the credentials of each ProviderConfig and Provider can authenticate to Azure. A warning event is recorded for each that cannot. The readiness probe fails only if none can.").Default(azure.DefaultCredentialsCheckInterval.String()).Duration()
+		ignoredTags    = app.Flag("ignore-tag-prefix", "Prefix of tag keys to ignore when detecting tag drift. May be repeated.").Default(azure.DefaultIgnoredTagPrefixes...).Strings()
+		vnetRuleTTL    = app.Flag("vnet-rule-list-ttl", "Duration for which to cache the listed virtual network rules of each SQL server, reducing the Azure API calls needed to observe them. Set to 0 to observe each rule individually.").Default("0s").Duration()
+		maxReconciles  = app.Flag("max-concurrent-reconciles", "Maximum number of reconciles each controller may run concurrently.").Default(strconv.Itoa(concurrency.DefaultMaxConcurrentReconciles)).Int()
+		drainTimeout   = app.Flag("drain-timeout", "Maximum duration for which to wait for in-flight reconciles to finish when stopping. Should be less than the termination grace period of the provider's pod.").Default(drain.DefaultTimeout.String()).Duration()
+		stuckThreshold = app.Flag("stuck-threshold", "Number of consecutive failed reconciles after which a managed resource is reported as stuck. Set to 0 to disable.").Default(strconv.Itoa(stuck.DefaultThreshold)).Int()
+		pollInterval   = app.Flag("operation-poll-interval", "Interval after which to first poll a long-running SQL server operation. Doubles each poll while the operation remains in progress. Intervals shorter than 1m are raised to 1m. Set to 0 to disable.").Default(poll.DefaultInterval.String()).Duration()
+		pollMax        = app.Flag("operation-poll-max-interval", "Maximum interval at which to poll a long-running SQL server operation.").Default(poll.DefaultMaxInterval.String()).Duration()
+		deleteTimeout  = app.Flag("delete-timeout", "Duration after which a managed resource whose external resource has not been deleted is reported with a DeleteTimeout condition, and orphaned if annotated to be. Set to 0 to disable.").Default(deletion.DefaultTimeout.String()).Duration()
+		observeOnly    = app.Flag("observe-only", "Only observe external resources, populating the status of their managed resources. External resources are never created, updated, or deleted, and deleted managed resources orphan them.").Bool()
+		sqlCABundle    = app.Flag("sql-ca-bundle", "Path to a PEM encoded bundle of root CA certificates to publish to the connection secrets of MySQL and PostgreSQL servers in the public and government clouds, replacing the built in bundle.").ExistingFile()
+		sqlCABundleCN  = app.Flag("sql-ca-bundle-china", "Path to a PEM encoded bundle of root CA certificates to publish to the connection secrets of MySQL and PostgreSQL servers in the China clouds, replacing the built in bundle.").ExistingFile()
+		maxReconcilesF = app.Flag("max-concurrent-reconciles-for", "Maximum number of reconciles the named controller may run concurrently, overriding --max-concurrent-reconciles. Controllers are named by the kind they reconcile, e.g. redis.cache.azure.crossplane.io=4. May be repeated.").PlaceHolder("KIND=N").StringMap()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
+	maxReconcilesFor := make(map[string]int, len(*maxReconcilesF))
+	for name, n := range *maxReconcilesF {
+		i, err := strconv.Atoi(n)
+		kingpin.FatalIfError(err, "Cannot parse maximum concurrent reconciles of %s", name)
+		maxReconcilesFor[name] = i
+	}
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-azure"))
@@ -48,20 +82,58 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting",
+		"sync-period", syncPeriod.String(),
+		"leader-election", *leaderElection,
+		"metrics-bind-address", *metricsAddr,
+		"max-concurrent-reconciles", *maxReconciles,
+		"observe-only", *observeOnly,
+		"health-probe-bind-address", *healthProbe)
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		LeaderElection:   *leaderElection,
-		LeaderElectionID: "crossplane-leader-election-provider-azure",
-		SyncPeriod:       syncPeriod,
+		LeaderElection:          *leaderElection,
+		LeaderElectionID:        "crossplane-leader-election-provider-azure",
+		LeaderElectionNamespace: *leaderElectNS,
+		SyncPeriod:              syncPeriod,
+		MetricsBindAddress:      *metricsAddr,
+		HealthProbeBindAddress:  *healthProbe,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Azure APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup Azure controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	ca, err := database.LoadCABundle(*sqlCABundle, *sqlCABundleCN)
+	kingpin.FatalIfError(err, "Cannot load SQL server CA bundle")
+	inflight := &drain.Tracker{}
+	kingpin.FatalIfError(controller.Setup(mgr, log, controller.Options{
+		SQLServerCABundle:          ca,
+		IgnoredTags:                *ignoredTags,
+		VirtualNetworkRuleListTTL:  *vnetRuleTTL,
+		RequeueJitter:              *requeueJitter,
+		ObserveOnly:                *observeOnly,
+		MaxConcurrentReconciles:    *maxReconciles,
+		MaxConcurrentReconcilesFor: maxReconcilesFor,
+		StuckThreshold:             *stuckThreshold,
+		OperationPollInterval:      *pollInterval,
+		OperationPollMaxInterval:   *pollMax,
+		DeleteTimeout:              *deleteTimeout,
+		Drain:                      inflight,
+	}), "Cannot setup Azure controllers")
+
+	cc := azure.NewCredentialsChecker(mgr.GetClient(), event.NewAPIRecorder(mgr.GetEventRecorderFor("credentials-checker")), *credsCheck)
+	kingpin.FatalIfError(mgr.Add(cc), "Cannot add Azure credentials checker")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("ping", healthz.Ping), "Cannot add health check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("azure-credentials", cc.Check), "Cannot add Azure credentials readiness check")
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// The manager stops its controllers without waiting for their in-flight
+	// reconciles, which may be part way through creating or updating an
+	// Azure resource.
+	if !inflight.Drain(*drainTimeout) {
+		log.Info("Stopping before all in-flight reconciles finished", "drain-timeout", drainTimeout.String())
+	}
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 
 }
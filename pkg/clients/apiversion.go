@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errFmtUnsupportedAPIVersion = "API version %q is not supported for %s; supported versions are %s"
+	errFmtNoAPIVersions         = "API version of %s cannot be configured"
+)
+
+// SupportedAPIVersions are the Azure API versions that may be used to manage
+// each kind of resource. The first version of each kind is its default, i.e.
+// the version of the Azure SDK client that manages it. Any other versions
+// accept and return the properties the Azure SDK client uses.
+var SupportedAPIVersions = map[string][]string{
+	"Redis":            {"2018-03-01", "2019-07-01", "2020-06-01"},
+	"MySQLServer":      {"2017-12-01"},
+	"PostgreSQLServer": {"2017-12-01"},
+}
+
+// ValidateAPIVersion returns an error if the supplied API version is not
+// supported for the supplied kind of resource. The empty string is always
+// supported, and selects the kind's default API version.
+func ValidateAPIVersion(kind, version string) error {
+	if version == "" {
+		return nil
+	}
+	supported, ok := SupportedAPIVersions[kind]
+	if !ok {
+		return errors.Errorf(errFmtNoAPIVersions, kind)
+	}
+	for _, v := range supported {
+		if v == version {
+			return nil
+		}
+	}
+	return errors.Errorf(errFmtUnsupportedAPIVersion, version, kind, strings.Join(supported, ", "))
+}
+
+// GetAPIVersion returns the API version that the ProviderConfig (or Provider)
+// of the supplied managed resource configures for the supplied kind. It
+// returns the empty string if no API version is configured, in which case the
+// kind's default API version should be used.
+func GetAPIVersion(ctx context.Context, c client.Client, mg resource.Managed, kind string) (string, error) {
+	cfg, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return "", err
+	}
+	var versions map[string]string
+	if cfg.pc != nil {
+		versions = cfg.pc.Spec.APIVersions
+	} else {
+		versions = cfg.p.Spec.APIVersions
+	}
+	v := versions[kind]
+	if err := ValidateAPIVersion(kind, v); err != nil {
+		return "", err
+	}
+	return v, nil
+}
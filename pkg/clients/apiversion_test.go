@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+func TestValidateAPIVersion(t *testing.T) {
+	cases := map[string]struct {
+		kind    string
+		version string
+		want    error
+	}{
+		"Default": {
+			kind: "Redis",
+		},
+		"Supported": {
+			kind:    "Redis",
+			version: "2020-06-01",
+		},
+		"Unsupported": {
+			kind:    "Redis",
+			version: "2016-04-01",
+			want:    errors.Errorf(errFmtUnsupportedAPIVersion, "2016-04-01", "Redis", "2018-03-01, 2019-07-01, 2020-06-01"),
+		},
+		"UnknownKind": {
+			kind:    "Wat",
+			version: "2020-06-01",
+			want:    errors.Errorf(errFmtNoAPIVersions, "Wat"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateAPIVersion(tc.kind, tc.version)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateAPIVersion(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetAPIVersion(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		version string
+		err     error
+	}
+
+	cases := map[string]struct {
+		c    client.Client
+		mg   resource.Managed
+		want want
+	}{
+		"ProviderConfig": {
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*v1beta1.ProviderConfig) = v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{
+					APIVersions: map[string]string{"Redis": "2020-06-01"},
+				}}
+				return nil
+			}},
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{version: "2020-06-01"},
+		},
+		"Provider": {
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*v1alpha3.Provider) = v1alpha3.Provider{Spec: v1alpha3.ProviderSpec{
+					APIVersions: map[string]string{"Redis": "2019-07-01"},
+				}}
+				return nil
+			}},
+			mg:   &fake.Managed{ProviderReferencer: fake.ProviderReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{version: "2019-07-01"},
+		},
+		"NotConfigured": {
+			c:  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			mg: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+		},
+		"Unsupported": {
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*v1beta1.ProviderConfig) = v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{
+					APIVersions: map[string]string{"Redis": "2016-04-01"},
+				}}
+				return nil
+			}},
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{err: errors.Errorf(errFmtUnsupportedAPIVersion, "2016-04-01", "Redis", "2018-03-01, 2019-07-01, 2020-06-01")},
+		},
+		"GetProviderConfigError": {
+			c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+		"NoReference": {
+			c:    &test.MockClient{},
+			mg:   &fake.Managed{},
+			want: want{err: errors.New(errNeitherPCNorPGiven)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := GetAPIVersion(context.Background(), tc.c, tc.mg, "Redis")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("GetAPIVersion(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.version, v); diff != "" {
+				t.Errorf("GetAPIVersion(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // Azure identifies certificates by SHA-1 thumbprint.
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/pkg/errors"
+)
+
+// Error strings.
+const (
+	errNoCertificate            = "client certificate PEM does not contain a certificate"
+	errNoPrivateKey             = "client certificate PEM does not contain a private key"
+	errParseCertificate         = "cannot parse client certificate"
+	errParsePrivateKey          = "cannot parse client certificate private key"
+	errNotRSAPrivateKey         = "client certificate private key is not an RSA key"
+	errGetOAuthConfig           = "cannot get OAuth config"
+	errGetCertificateToken      = "cannot get service principal token from client certificate"
+	errFmtThumbprintMismatch    = "client certificate thumbprint %q does not match configured thumbprint %q"
+	errFmtUnsupportedCredType   = "unsupported credential type %q"
+	errFmtUnsupportedPEMBlock   = "unsupported PEM block type %q in client certificate"
+	errFmtCertificateBlockCount = "client certificate PEM must contain exactly one certificate, found %d"
+)
+
+// Credentials Secret keys used for certificate based authentication.
+const (
+	// CredentialsKeyCredentialType selects how to authenticate as the service
+	// principal. It defaults to CredentialTypeClientSecret.
+	CredentialsKeyCredentialType = "credentialType"
+
+	// CredentialsKeyClientCertificate is a PEM encoded certificate and its
+	// (unencrypted) private key.
+	CredentialsKeyClientCertificate = "clientCertificate"
+
+	// CredentialsKeyClientCertificateThumbprint is the optional hex encoded
+	// SHA-1 thumbprint of the client certificate. If supplied it must match
+	// the certificate.
+	CredentialsKeyClientCertificateThumbprint = "clientCertificateThumbprint"
+)
+
+// Supported credential types.
+const (
+	CredentialTypeClientSecret      = "clientSecret"
+	CredentialTypeClientCertificate = "clientCertificate"
+)
+
+// publicCloudSQLManagementEndpoint is the SQL management endpoint of the Azure
+// public cloud, as emitted by az ad sp create-for-rbac --sdk-auth.
+const publicCloudSQLManagementEndpoint = "https://management.core.windows.net:8443/"
+
+// ParseCredentials parses the supplied JSON encoded credentials. It accepts
+// both the output of az ad sp create-for-rbac --sdk-auth and a minimal form
+// that includes only the clientId, clientSecret, tenantId and subscriptionId
+// keys. Any omitted endpoints default to those of the Azure public cloud.
+func ParseCredentials(data []byte) (map[string]string, error) {
+	m := map[string]string{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalCredentialSecret)
+	}
+	defaults := map[string]string{
+		CredentialsKeyActiveDirectoryEndpointURL:     azure.PublicCloud.ActiveDirectoryEndpoint,
+		CredentialsKeyResourceManagerEndpointURL:     azure.PublicCloud.ResourceManagerEndpoint,
+		CredentialsKeyActiveDirectoryGraphResourceID: azure.PublicCloud.GraphEndpoint,
+		CredentialsKeySQLManagementEndpointURL:       publicCloudSQLManagementEndpoint,
+		CredentialsKeyGalleryEndpointURL:             azure.PublicCloud.GalleryEndpoint,
+		CredentialsManagementEndpointURL:             azure.PublicCloud.ServiceManagementEndpoint,
+	}
+	for k, v := range defaults {
+		if m[k] == "" {
+			m[k] = v
+		}
+	}
+	return m, nil
+}
+
+// NewAuthorizer returns an authorizer for the supplied credentials, which are
+// the decoded contents of a credentials secret. Service principals may
+// authenticate using either a client secret or a client certificate, depending
+// on the credential type.
+func NewAuthorizer(creds map[string]string) (autorest.Authorizer, error) {
+	switch t := creds[CredentialsKeyCredentialType]; t {
+	case "", CredentialTypeClientSecret:
+		cfg := auth.NewClientCredentialsConfig(creds[CredentialsKeyClientID], creds[CredentialsKeyClientSecret], creds[CredentialsKeyTenantID])
+		cfg.AADEndpoint = creds[CredentialsKeyActiveDirectoryEndpointURL]
+		cfg.Resource = creds[CredentialsKeyResourceManagerEndpointURL]
+		a, err := cfg.Authorizer()
+		return a, errors.Wrap(err, errGetAuthorizer)
+	case CredentialTypeClientCertificate:
+		return newCertificateAuthorizer(creds)
+	default:
+		return nil, errors.Errorf(errFmtUnsupportedCredType, t)
+	}
+}
+
+func newCertificateAuthorizer(creds map[string]string) (autorest.Authorizer, error) {
+	cert, key, err := ParseClientCertificate([]byte(creds[CredentialsKeyClientCertificate]))
+	if err != nil {
+		return nil, err
+	}
+	if want := creds[CredentialsKeyClientCertificateThumbprint]; want != "" {
+		if got := Thumbprint(cert); !strings.EqualFold(got, strings.ReplaceAll(want, ":", "")) {
+			return nil, errors.Errorf(errFmtThumbprintMismatch, got, want)
+		}
+	}
+
+	oc, err := adal.NewOAuthConfig(creds[CredentialsKeyActiveDirectoryEndpointURL], creds[CredentialsKeyTenantID])
+	if err != nil {
+		return nil, errors.Wrap(err, errGetOAuthConfig)
+	}
+	spt, err := adal.NewServicePrincipalTokenFromCertificate(*oc, creds[CredentialsKeyClientID], cert, key, creds[CredentialsKeyResourceManagerEndpointURL])
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCertificateToken)
+	}
+	return autorest.NewBearerAuthorizer(spt), nil
+}
+
+// ParseClientCertificate parses the supplied PEM data, which must contain
+// exactly one certificate and an RSA private key in either PKCS #1 or PKCS #8
+// form.
+func ParseClientCertificate(data []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	var certs []*x509.Certificate
+	var key *rsa.PrivateKey
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+		switch b.Type {
+		case "CERTIFICATE":
+			c, err := x509.ParseCertificate(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParseCertificate)
+			}
+			certs = append(certs, c)
+		case "RSA PRIVATE KEY":
+			k, err := x509.ParsePKCS1PrivateKey(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParsePrivateKey)
+			}
+			key = k
+		case "PRIVATE KEY":
+			k, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParsePrivateKey)
+			}
+			rk, ok := k.(*rsa.PrivateKey)
+			if !ok {
+				return nil, nil, errors.New(errNotRSAPrivateKey)
+			}
+			key = rk
+		default:
+			return nil, nil, errors.Errorf(errFmtUnsupportedPEMBlock, b.Type)
+		}
+	}
+	switch {
+	case len(certs) == 0:
+		return nil, nil, errors.New(errNoCertificate)
+	case len(certs) > 1:
+		return nil, nil, errors.Errorf(errFmtCertificateBlockCount, len(certs))
+	case key == nil:
+		return nil, nil, errors.New(errNoPrivateKey)
+	}
+	return certs[0], key, nil
+}
+
+// Thumbprint returns the hex encoded SHA-1 thumbprint of the supplied
+// certificate, as displayed by the Azure portal.
+func Thumbprint(c *x509.Certificate) string {
+	sum := sha1.Sum(c.Raw) //nolint:gosec // Azure identifies certificates by SHA-1 thumbprint.
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
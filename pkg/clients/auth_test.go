@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func newTestCertificate(t *testing.T) (certPEM, pkcs1PEM, pkcs8PEM []byte, cert *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "crossplane"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		cert
+}
+
+func TestParseClientCertificate(t *testing.T) {
+	certPEM, pkcs1PEM, pkcs8PEM, cert := newTestCertificate(t)
+
+	cases := map[string]struct {
+		data     []byte
+		wantCert bool
+		err      error
+	}{
+		"PKCS1": {
+			data:     append(append([]byte{}, certPEM...), pkcs1PEM...),
+			wantCert: true,
+		},
+		"PKCS8": {
+			data:     append(append([]byte{}, pkcs8PEM...), certPEM...),
+			wantCert: true,
+		},
+		"NoCertificate": {
+			data: pkcs1PEM,
+			err:  errors.New(errNoCertificate),
+		},
+		"TooManyCertificates": {
+			data: append(append(append([]byte{}, certPEM...), certPEM...), pkcs1PEM...),
+			err:  errors.Errorf(errFmtCertificateBlockCount, 2),
+		},
+		"NoPrivateKey": {
+			data: certPEM,
+			err:  errors.New(errNoPrivateKey),
+		},
+		"UnsupportedBlock": {
+			data: pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY"}),
+			err:  errors.Errorf(errFmtUnsupportedPEMBlock, "ENCRYPTED PRIVATE KEY"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, k, err := ParseClientCertificate(tc.data)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ParseClientCertificate(...): -want error, +got error:\n%s", diff)
+			}
+			if !tc.wantCert {
+				return
+			}
+			if !c.Equal(cert) {
+				t.Errorf("ParseClientCertificate(...): returned certificate does not match")
+			}
+			if k == nil {
+				t.Errorf("ParseClientCertificate(...): returned nil private key")
+			}
+		})
+	}
+}
+
+func TestParseCredentials(t *testing.T) {
+	type want struct {
+		creds map[string]string
+		err   bool
+	}
+	cases := map[string]struct {
+		data string
+		want want
+	}{
+		"SDKAuth": {
+			data: `{
+				"clientId": "id",
+				"clientSecret": "secret",
+				"subscriptionId": "sub",
+				"tenantId": "tenant",
+				"activeDirectoryEndpointUrl": "https://login.microsoftonline.us/",
+				"resourceManagerEndpointUrl": "https://management.usgovcloudapi.net/",
+				"activeDirectoryGraphResourceId": "https://graph.windows.net/",
+				"sqlManagementEndpointUrl": "https://management.core.usgovcloudapi.net:8443/",
+				"galleryEndpointUrl": "https://gallery.usgovcloudapi.net/",
+				"managementEndpointUrl": "https://management.core.usgovcloudapi.net/"
+			}`,
+			want: want{creds: map[string]string{
+				CredentialsKeyClientID:                       "id",
+				CredentialsKeyClientSecret:                   "secret",
+				CredentialsKeySubscriptionID:                 "sub",
+				CredentialsKeyTenantID:                       "tenant",
+				CredentialsKeyActiveDirectoryEndpointURL:     "https://login.microsoftonline.us/",
+				CredentialsKeyResourceManagerEndpointURL:     "https://management.usgovcloudapi.net/",
+				CredentialsKeyActiveDirectoryGraphResourceID: "https://graph.windows.net/",
+				CredentialsKeySQLManagementEndpointURL:       "https://management.core.usgovcloudapi.net:8443/",
+				CredentialsKeyGalleryEndpointURL:             "https://gallery.usgovcloudapi.net/",
+				CredentialsManagementEndpointURL:             "https://management.core.usgovcloudapi.net/",
+			}},
+		},
+		"Minimal": {
+			data: `{"clientId": "id", "clientSecret": "secret", "subscriptionId": "sub", "tenantId": "tenant"}`,
+			want: want{creds: map[string]string{
+				CredentialsKeyClientID:                       "id",
+				CredentialsKeyClientSecret:                   "secret",
+				CredentialsKeySubscriptionID:                 "sub",
+				CredentialsKeyTenantID:                       "tenant",
+				CredentialsKeyActiveDirectoryEndpointURL:     "https://login.microsoftonline.com/",
+				CredentialsKeyResourceManagerEndpointURL:     "https://management.azure.com/",
+				CredentialsKeyActiveDirectoryGraphResourceID: "https://graph.windows.net/",
+				CredentialsKeySQLManagementEndpointURL:       "https://management.core.windows.net:8443/",
+				CredentialsKeyGalleryEndpointURL:             "https://gallery.azure.com/",
+				CredentialsManagementEndpointURL:             "https://management.core.windows.net/",
+			}},
+		},
+		"NotJSON": {
+			data: "wat",
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCredentials([]byte(tc.data))
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("ParseCredentials(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("ParseCredentials(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	certPEM, pkcs1PEM, _, cert := newTestCertificate(t)
+	bundle := string(certPEM) + string(pkcs1PEM)
+
+	cases := map[string]struct {
+		creds map[string]string
+		err   error
+	}{
+		"ClientSecret": {
+			creds: map[string]string{
+				CredentialsKeyClientID:                   "id",
+				CredentialsKeyClientSecret:               "secret",
+				CredentialsKeyTenantID:                   "tenant",
+				CredentialsKeyActiveDirectoryEndpointURL: "https://login.microsoftonline.com/",
+				CredentialsKeyResourceManagerEndpointURL: "https://management.azure.com/",
+			},
+		},
+		"ClientCertificate": {
+			creds: map[string]string{
+				CredentialsKeyCredentialType:              CredentialTypeClientCertificate,
+				CredentialsKeyClientID:                    "id",
+				CredentialsKeyTenantID:                    "tenant",
+				CredentialsKeyClientCertificate:           bundle,
+				CredentialsKeyClientCertificateThumbprint: Thumbprint(cert),
+				CredentialsKeyActiveDirectoryEndpointURL:  "https://login.microsoftonline.com/",
+				CredentialsKeyResourceManagerEndpointURL:  "https://management.azure.com/",
+			},
+		},
+		"ThumbprintMismatch": {
+			creds: map[string]string{
+				CredentialsKeyCredentialType:              CredentialTypeClientCertificate,
+				CredentialsKeyClientID:                    "id",
+				CredentialsKeyTenantID:                    "tenant",
+				CredentialsKeyClientCertificate:           bundle,
+				CredentialsKeyClientCertificateThumbprint: "AA:BB",
+			},
+			err: errors.Errorf(errFmtThumbprintMismatch, Thumbprint(cert), "AA:BB"),
+		},
+		"UnsupportedCredentialType": {
+			creds: map[string]string{CredentialsKeyCredentialType: "password"},
+			err:   errors.Errorf(errFmtUnsupportedCredType, "password"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAuthorizer(tc.creds)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("NewAuthorizer(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,13 +18,17 @@ package azure
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +36,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-azure/apis/v1alpha3"
@@ -45,6 +51,13 @@ const (
 	// that indicates the operation is still ongoing.
 	AsyncOperationStatusInProgress = "InProgress"
 	asyncOperationPollingMethod    = "AsyncOperation"
+
+	// AnnotationKeySubscriptionID may be added to a managed resource in order
+	// to manage it in a subscription other than the default subscription of
+	// its credentials. The credentials must be authorized to access the
+	// subscription. The subscription cannot be changed once the managed
+	// resource has recorded the ID of its external resource.
+	AnnotationKeySubscriptionID = "azure.crossplane.io/subscription-id"
 )
 
 // Error strings.
@@ -58,6 +71,13 @@ const (
 	errGetAuthorizer             = "cannot get authorizer from client credentials config"
 
 	errFmtUnsupportedCredSource = "unsupported credentials source %q"
+	errFmtSubscriptionChanged   = "cannot use subscription %q: the external resource exists in subscription %q, and the subscription of a managed resource cannot be changed"
+)
+
+// Azure response headers that identify a request.
+const (
+	HeaderRequestID            = "x-ms-request-id"
+	HeaderCorrelationRequestID = "x-ms-correlation-request-id"
 )
 
 // A FieldOption determines how common Go types are translated to the types
@@ -89,53 +109,117 @@ const (
 
 // GetAuthInfo figures out how to connect to Azure API and returns the necessary
 // information to be used for controllers to construct their specific clients.
+// The credentials secret is read every time GetAuthInfo is called, so clients
+// built from its results should not outlive a reconcile; this ensures rotated
+// credentials take effect without restarting the provider.
+//
+// The subscription ID in the returned content is overridden by the supplied
+// managed resource's AnnotationKeySubscriptionID annotation, if any. An error
+// is returned if it differs from the subscription of the external resource
+// whose ID the managed resource recorded in its status, so that changing the
+// annotation can't silently point the managed resource at another external
+// resource.
 func GetAuthInfo(ctx context.Context, c client.Client, mg resource.Managed) (content map[string]string, authorizer autorest.Authorizer, err error) {
 	switch {
 	case mg.GetProviderConfigReference() != nil:
-		return UseProviderConfig(ctx, c, mg)
+		content, authorizer, err = UseProviderConfig(ctx, c, mg)
 	case mg.GetProviderReference() != nil:
-		return UseProvider(ctx, c, mg)
+		content, authorizer, err = UseProvider(ctx, c, mg)
 	default:
 		return nil, nil, errors.New(errNeitherPCNorPGiven)
 	}
+	if err != nil {
+		return content, authorizer, err
+	}
+	if id := mg.GetAnnotations()[AnnotationKeySubscriptionID]; id != "" {
+		content[CredentialsKeySubscriptionID] = id
+	}
+	if r := recordedSubscriptionID(mg); r != "" && !strings.EqualFold(r, content[CredentialsKeySubscriptionID]) {
+		return nil, nil, errors.Errorf(errFmtSubscriptionChanged, content[CredentialsKeySubscriptionID], r)
+	}
+	return content, authorizer, nil
+}
+
+// statusIDPaths are the field paths at which managed resources record the
+// Azure resource ID of their external resource.
+var statusIDPaths = []string{"status.atProvider.id", "status.id", "status.providerID"}
+
+// recordedSubscriptionID returns the subscription of the external resource
+// whose Azure resource ID the supplied managed resource recorded in its
+// status, if any.
+func recordedSubscriptionID(mg resource.Managed) string {
+	p, err := fieldpath.PaveObject(mg)
+	if err != nil {
+		return ""
+	}
+	for _, path := range statusIDPaths {
+		id, err := p.GetString(path)
+		if err != nil || id == "" {
+			continue
+		}
+		if r, err := azure.ParseResourceID(id); err == nil {
+			return r.SubscriptionID
+		}
+	}
+	return ""
+}
+
+// CredentialsFingerprint returns a digest of the supplied credentials that may
+// be used to determine whether they have changed, for example because they were
+// rotated, without retaining the credentials themselves. Each key and value is
+// prefixed with its length, so that no two sets of credentials encode alike.
+func CredentialsFingerprint(creds map[string]string) string {
+	keys := make([]string, 0, len(creds))
+	for k := range creds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(creds[k]), creds[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // UseProvider to return the necessary information to construct an Azure client.
 // Deprecated: Use UseProviderConfig
 func UseProvider(ctx context.Context, c client.Client, mg resource.Managed) (content map[string]string, authorizer autorest.Authorizer, err error) {
-	p := &v1alpha3.Provider{}
-	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderReference().Name}, p); err != nil {
-		return nil, nil, errors.Wrap(err, errGetProvider)
+	cfg, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.p == nil {
+		return nil, nil, errors.New(errNeitherPCNorPGiven)
 	}
 
-	ref := p.Spec.CredentialsSecretRef
+	ref := cfg.p.Spec.CredentialsSecretRef
 	s := &corev1.Secret{}
 	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, s); err != nil {
 		return nil, nil, err
 	}
-	m := map[string]string{}
-	if err := json.Unmarshal(s.Data[ref.Key], &m); err != nil {
-		return nil, nil, errors.Wrap(err, errUnmarshalCredentialSecret)
+	m, err := ParseCredentials(s.Data[ref.Key])
+	if err != nil {
+		return nil, nil, err
 	}
-	cfg := auth.NewClientCredentialsConfig(m[CredentialsKeyClientID], m[CredentialsKeyClientSecret], m[CredentialsKeyTenantID])
-	cfg.AADEndpoint = m[CredentialsKeyActiveDirectoryEndpointURL]
-	cfg.Resource = m[CredentialsKeyResourceManagerEndpointURL]
-
-	a, err := cfg.Authorizer()
-	return m, a, errors.Wrap(err, errGetAuthorizer)
+	a, err := NewAuthorizer(m)
+	return m, a, err
 }
 
 // UseProviderConfig to return the necessary information to construct an Azure
 // client.
 func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (content map[string]string, authorizer autorest.Authorizer, err error) {
-	pc := &v1beta1.ProviderConfig{}
 	t := resource.NewProviderConfigUsageTracker(c, &v1beta1.ProviderConfigUsage{})
 	if err := t.Track(ctx, mg); err != nil {
 		return nil, nil, errors.Wrap(err, errTrackProviderConfigUsage)
 	}
-	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, nil, errors.Wrap(err, errGetProviderConfig)
+	cfg, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return nil, nil, err
 	}
+	if cfg.pc == nil {
+		return nil, nil, errors.New(errNeitherPCNorPGiven)
+	}
+	pc := cfg.pc
 
 	// NOTE(muvaf): When we implement the workload identity, we will only need to
 	// return a different type of option.ClientOption, which is WithTokenSource().
@@ -152,16 +236,12 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, s); err != nil {
 		return nil, nil, err
 	}
-	m := map[string]string{}
-	if err := json.Unmarshal(s.Data[ref.Key], &m); err != nil {
-		return nil, nil, errors.Wrap(err, errUnmarshalCredentialSecret)
+	m, err := ParseCredentials(s.Data[ref.Key])
+	if err != nil {
+		return nil, nil, err
 	}
-	cfg := auth.NewClientCredentialsConfig(m[CredentialsKeyClientID], m[CredentialsKeyClientSecret], m[CredentialsKeyTenantID])
-	cfg.AADEndpoint = m[CredentialsKeyActiveDirectoryEndpointURL]
-	cfg.Resource = m[CredentialsKeyResourceManagerEndpointURL]
-
-	a, err := cfg.Authorizer()
-	return m, a, errors.Wrap(err, errGetAuthorizer)
+	a, err := NewAuthorizer(m)
+	return m, a, err
 }
 
 // Client struct that represents the information needed to connect to the Azure services as a client
@@ -186,17 +266,12 @@ type Credentials struct {
 // NewClient returns a client that can be used to connect to Azure services
 // using the supplied JSON credentials.
 func NewClient(credentials []byte) (*Client, error) {
-	creds := Credentials{}
-	if err := json.Unmarshal(credentials, &creds); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal azure client secret data")
+	m, err := ParseCredentials(credentials)
+	if err != nil {
+		return nil, err
 	}
 
-	// create a config object from the loaded credentials data
-	config := auth.NewClientCredentialsConfig(creds.ClientID, creds.ClientSecret, creds.TenantID)
-	config.AADEndpoint = creds.ActiveDirectoryEndpointURL
-	config.Resource = creds.ResourceManagerEndpointURL
-
-	authorizer, err := config.Authorizer()
+	authorizer, err := NewAuthorizer(m)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get authorizer from config")
 	}
@@ -204,24 +279,24 @@ func NewClient(credentials []byte) (*Client, error) {
 	return &Client{
 		Authorizer: authorizer,
 		Credentials: Credentials{
-			SubscriptionID:                 creds.SubscriptionID,
-			ClientID:                       creds.ClientID,
-			ClientSecret:                   creds.ClientSecret,
-			TenantID:                       creds.TenantID,
-			ActiveDirectoryEndpointURL:     creds.ActiveDirectoryEndpointURL,
-			ActiveDirectoryGraphResourceID: creds.ActiveDirectoryGraphResourceID,
+			SubscriptionID:                 m[CredentialsKeySubscriptionID],
+			ClientID:                       m[CredentialsKeyClientID],
+			ClientSecret:                   m[CredentialsKeyClientSecret],
+			TenantID:                       m[CredentialsKeyTenantID],
+			ActiveDirectoryEndpointURL:     m[CredentialsKeyActiveDirectoryEndpointURL],
+			ActiveDirectoryGraphResourceID: m[CredentialsKeyActiveDirectoryGraphResourceID],
 		},
 	}, nil
 }
 
 // ValidateClient verifies if the given client is valid by testing if it can make an Azure service API call
 // TODO: is there a better way to validate the Azure client?
-func ValidateClient(client *Client) error {
+func ValidateClient(ctx context.Context, client *Client) error {
 	groupsClient := resources.NewGroupsClient(client.SubscriptionID)
 	groupsClient.Authorizer = client.Authorizer
 	groupsClient.AddToUserAgent(UserAgent)
 
-	_, err := groupsClient.ListComplete(context.TODO(), "", nil)
+	_, err := groupsClient.ListComplete(ctx, "", nil)
 	return err
 }
 
@@ -277,6 +352,141 @@ func IsNotFound(err error) bool {
 	return statusCode == http.StatusNotFound
 }
 
+// IsThrottled returns true if the supplied error, or the error it wraps,
+// indicates that Azure throttled the request that caused it.
+func IsThrottled(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// IsConflict returns true if the supplied error, or the error it wraps,
+// indicates that the request that caused it conflicted with the current state
+// of the Azure resource, typically because another operation was in progress.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsForbidden returns true if the supplied error, or the error it wraps,
+// indicates that the Azure credentials were not authorized to make the request
+// that caused it.
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// A responseError is an error that carries the HTTP response that caused it,
+// like those returned by the Azure Storage data plane SDK.
+type responseError interface {
+	error
+	Response() *http.Response
+}
+
+// hasStatusCode returns true if the supplied error, or the error it wraps, is
+// an Azure API error with the supplied HTTP status code.
+func hasStatusCode(err error, code int) bool {
+	var de autorest.DetailedError
+	if errors.As(err, &de) {
+		sc, ok := de.StatusCode.(int)
+		return ok && sc == code
+	}
+	r := response(err)
+	return r != nil && r.StatusCode == code
+}
+
+// response returns the HTTP response that caused the supplied error, or the
+// error it wraps, if any.
+func response(err error) *http.Response {
+	var de autorest.DetailedError
+	if errors.As(err, &de) {
+		return de.Response
+	}
+	var re responseError
+	if errors.As(err, &re) {
+		return re.Response() // nolint:bodyclose
+	}
+	return nil
+}
+
+// A requestIDError is an error annotated with the IDs of the failed Azure
+// request that caused it.
+type requestIDError struct {
+	error
+	requestID     string
+	correlationID string
+}
+
+func (e *requestIDError) Error() string {
+	return fmt.Sprintf("%s (%s: %s, %s: %s)", e.error, HeaderRequestID, e.requestID, HeaderCorrelationRequestID, e.correlationID)
+}
+
+func (e *requestIDError) Cause() error  { return e.error }
+func (e *requestIDError) Unwrap() error { return e.error }
+
+// WithRequestIDs annotates the supplied error with the request and correlation
+// request IDs of the failed Azure request that caused it, if any. Microsoft
+// support uses these IDs to find the request.
+func WithRequestIDs(err error) error {
+	var re *requestIDError
+	if err == nil || errors.As(err, &re) {
+		return err
+	}
+	var de autorest.DetailedError
+	if !errors.As(err, &de) {
+		return err
+	}
+	e := &requestIDError{error: err}
+	if de.Response != nil {
+		e.requestID = de.Response.Header.Get(HeaderRequestID)
+		e.correlationID = de.Response.Header.Get(HeaderCorrelationRequestID)
+	}
+	if ae, ok := de.Original.(*azure.RequestError); ok && e.requestID == "" {
+		e.requestID = ae.RequestID
+	}
+	if e.requestID == "" && e.correlationID == "" {
+		return err
+	}
+	return e
+}
+
+// NewRequestIDConnecter returns a managed.ExternalConnecter that annotates the
+// errors returned by the supplied connecter's clients with Azure request IDs.
+func NewRequestIDConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &requestIDConnecter{ExternalConnecter: c}
+}
+
+type requestIDConnecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *requestIDConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, WithRequestIDs(err)
+	}
+	return &requestIDExternal{ExternalClient: e}, nil
+}
+
+type requestIDExternal struct {
+	managed.ExternalClient
+}
+
+func (e *requestIDExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, WithRequestIDs(err)
+}
+
+func (e *requestIDExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, WithRequestIDs(err)
+}
+
+func (e *requestIDExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, WithRequestIDs(err)
+}
+
+func (e *requestIDExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return WithRequestIDs(e.ExternalClient.Delete(ctx, mg))
+}
+
 // ToStringPtr converts the supplied string for use with the Azure Go SDK.
 func ToStringPtr(s string, o ...FieldOption) *string {
 	for _, fo := range o {
@@ -26,11 +26,21 @@ import (
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
 	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	cachev1beta1 "github.com/crossplane/provider-azure/apis/cache/v1beta1"
 	"github.com/crossplane/provider-azure/apis/v1alpha3"
 )
 
@@ -57,6 +67,75 @@ func TestNewClient(t *testing.T) {
 	g.Expect(client.SubscriptionID).To(gomega.Equal("bf1b0e59-93da-42e0-82c6-5a1d94227911"))
 }
 
+func TestGetAuthInfo(t *testing.T) {
+	override := "7a8d1e2c-0b7e-4c39-9b1e-4c1d2f3a5b6c"
+	kube := &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha3.Provider:
+			o.Spec.CredentialsSecretRef = runtimev1alpha1.SecretKeySelector{Key: "creds"}
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"creds": []byte(authData)}
+		}
+		return nil
+	}}
+	managed := func(annotations map[string]string) resource.Managed {
+		mg := &fake.Managed{ProviderReferencer: fake.ProviderReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}}
+		meta.AddAnnotations(mg, annotations)
+		return mg
+	}
+
+	redis := func(annotations map[string]string, id string) resource.Managed {
+		r := &cachev1beta1.Redis{}
+		r.SetProviderReference(&runtimev1alpha1.Reference{Name: "default"})
+		meta.AddAnnotations(r, annotations)
+		r.Status.AtProvider.ID = id
+		return r
+	}
+
+	cases := map[string]struct {
+		mg   resource.Managed
+		want string
+		err  error
+	}{
+		"DefaultSubscription": {
+			mg:   managed(nil),
+			want: "bf1b0e59-93da-42e0-82c6-5a1d94227911",
+		},
+		"SubscriptionOverridden": {
+			mg:   managed(map[string]string{AnnotationKeySubscriptionID: override}),
+			want: override,
+		},
+		"NoReference": {
+			mg:  &fake.Managed{},
+			err: errors.New(errNeitherPCNorPGiven),
+		},
+		"RecordedSubscriptionUnchanged": {
+			mg:   redis(map[string]string{AnnotationKeySubscriptionID: override}, "/subscriptions/"+override+"/resourceGroups/rg/providers/Microsoft.Cache/Redis/cool"),
+			want: override,
+		},
+		"RecordedSubscriptionChanged": {
+			mg:  redis(map[string]string{AnnotationKeySubscriptionID: override}, "/subscriptions/bf1b0e59-93da-42e0-82c6-5a1d94227911/resourceGroups/rg/providers/Microsoft.Cache/Redis/cool"),
+			err: errors.Errorf(errFmtSubscriptionChanged, override, "bf1b0e59-93da-42e0-82c6-5a1d94227911"),
+		},
+		"RecordedSubscriptionAnnotationRemoved": {
+			mg:  redis(nil, "/subscriptions/"+override+"/resourceGroups/rg/providers/Microsoft.Cache/Redis/cool"),
+			err: errors.Errorf(errFmtSubscriptionChanged, "bf1b0e59-93da-42e0-82c6-5a1d94227911", override),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			creds, _, err := GetAuthInfo(context.Background(), kube, tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("GetAuthInfo(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, creds[CredentialsKeySubscriptionID]); diff != "" {
+				t.Errorf("GetAuthInfo(...): -want subscription, +got subscription\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFetchAsyncOperation(t *testing.T) {
 	inprogressStatus := "inprogress"
 	inProgressResponse := fmt.Sprintf(`{"status": "%s"}`, inprogressStatus)
@@ -163,6 +242,103 @@ func TestIsNotFound(t *testing.T) {
 	}
 }
 
+func TestCredentialsFingerprint(t *testing.T) {
+	creds := map[string]string{CredentialsKeyClientID: "id", CredentialsKeyClientSecret: "secret"}
+	cases := map[string]struct {
+		a    map[string]string
+		b    map[string]string
+		want bool
+	}{
+		"Same": {
+			a:    creds,
+			b:    map[string]string{CredentialsKeyClientSecret: "secret", CredentialsKeyClientID: "id"},
+			want: true,
+		},
+		"Rotated": {
+			a:    creds,
+			b:    map[string]string{CredentialsKeyClientID: "id", CredentialsKeyClientSecret: "rotated"},
+			want: false,
+		},
+		"AmbiguousConcatenation": {
+			a:    map[string]string{"a": "b\nc=d"},
+			b:    map[string]string{"a": "b", "c": "d"},
+			want: false,
+		},
+		"AmbiguousSeparator": {
+			a:    map[string]string{"a=b": "c"},
+			b:    map[string]string{"a": "b=c"},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CredentialsFingerprint(tc.a) == CredentialsFingerprint(tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("CredentialsFingerprint(a) == CredentialsFingerprint(b): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithRequestIDs(t *testing.T) {
+	errBoom := errors.New("boom")
+	withHeaders := autorest.DetailedError{
+		Original: errBoom,
+		Response: &http.Response{Header: http.Header{
+			http.CanonicalHeaderKey(HeaderRequestID):            []string{"req"},
+			http.CanonicalHeaderKey(HeaderCorrelationRequestID): []string{"corr"},
+		}},
+	}
+
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"Nil": {
+			err: nil,
+		},
+		"NotDetailedError": {
+			err:  errBoom,
+			want: "boom",
+		},
+		"NoIDs": {
+			err:  autorest.DetailedError{Original: errBoom},
+			want: autorest.DetailedError{Original: errBoom}.Error(),
+		},
+		"Headers": {
+			err:  errors.Wrap(withHeaders, "cannot create"),
+			want: fmt.Sprintf("cannot create: %s (x-ms-request-id: req, x-ms-correlation-request-id: corr)", withHeaders.Error()),
+		},
+		"RequestError": {
+			err:  autorest.DetailedError{Original: &azure.RequestError{RequestID: "req"}},
+			want: fmt.Sprintf("%s (x-ms-request-id: req, x-ms-correlation-request-id: )", autorest.DetailedError{Original: &azure.RequestError{RequestID: "req"}}.Error()),
+		},
+		"AlreadyAnnotated": {
+			err:  errors.Wrap(WithRequestIDs(withHeaders), "cannot create"),
+			want: fmt.Sprintf("cannot create: %s (x-ms-request-id: req, x-ms-correlation-request-id: corr)", withHeaders.Error()),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WithRequestIDs(tc.err)
+			if tc.err == nil {
+				if got != nil {
+					t.Errorf("WithRequestIDs(nil): want nil, got %v", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, got.Error()); diff != "" {
+				t.Errorf("WithRequestIDs(...): -want, +got:\n%s", diff)
+			}
+			var want, de autorest.DetailedError
+			if errors.As(tc.err, &want) && !errors.As(got, &de) {
+				t.Errorf("WithRequestIDs(...): %v does not wrap an autorest.DetailedError", got)
+			}
+		})
+	}
+}
+
 func TestStringHelpers(t *testing.T) {
 	t.Run("ToStringMap", func(t *testing.T) {
 		original := make(map[string]*string)
@@ -18,12 +18,16 @@ package compute
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
 	authorizationmgmt "github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
+	computemgmt "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-03-01/containerservice"
 	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/adal"
@@ -49,6 +53,47 @@ const (
 	NetworkContributorRoleID = "/providers/Microsoft.Authorization/roleDefinitions/4d97b98b-1d4f-4787-a291-c67834d212e7"
 
 	appCredsValidYears = 5
+
+	// PrivateDNSZoneAPIVersion is the API version used to create clusters
+	// with a private DNS zone, which the Azure SDK we use does not support.
+	PrivateDNSZoneAPIVersion = "2020-11-01"
+
+	// OSDiskTypeAPIVersion is the API version used to create clusters with an
+	// OS disk type, which the Azure SDK we use does not support. Clusters
+	// with an OS disk type are also updated using this API version, so that
+	// their OS disk type is not reset to the default.
+	OSDiskTypeAPIVersion = "2020-11-01"
+
+	// OSDiskTypeEphemeral is the OS disk type of nodes whose OS disk is
+	// stored on their VM's cache disk.
+	OSDiskTypeEphemeral = "Ephemeral"
+
+	// DefaultOSDiskSizeGB is the size of the OS disk Azure creates when no
+	// size is specified.
+	DefaultOSDiskSizeGB = 128
+)
+
+// Error strings.
+const (
+	errEncodeAgentPoolProfile     = "cannot encode agent pool profile"
+	errDecodeAgentPoolProfile     = "cannot decode agent pool profile"
+	errFmtEphemeralOSDiskTooLarge = "ephemeral OS disk of %dGB does not fit in the %dGB cache disk of VM size %s; specify a smaller nodeOSDiskSizeGB or a larger nodeVMSize"
+	errFmtImmutableField          = "%s cannot be changed after the cluster is created"
+	errListResourceSKUs           = "cannot list resource SKUs"
+	errParseSpotMaxPrice          = "cannot parse nodeSpotMaxPrice"
+	errSpotSettingsWithoutSpot    = "nodeScaleSetEvictionPolicy and nodeSpotMaxPrice may only be specified when nodeScaleSetPriority is Spot"
+)
+
+// Resource SKU properties used to determine the cache disk size of a VM size.
+const (
+	resourceTypeVirtualMachines = "virtualMachines"
+	capabilityCachedDiskBytes   = "CachedDiskBytes"
+	bytesPerGB                  = 1 << 30
+)
+
+// Connection secret keys.
+const (
+	ConnectionSecretPrivateFQDNKey = "privateFqdn"
 )
 
 // An AKSClient can create, read, and delete AKS clusters and the various other
@@ -56,6 +101,7 @@ const (
 type AKSClient interface {
 	GetManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error)
 	EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error
+	UpdateManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error
 	DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error
 	GetKubeConfig(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error)
 }
@@ -66,10 +112,14 @@ type AggregateClient struct {
 	Applications      graphrbac.ApplicationsClient
 	ServicePrincipals graphrbac.ServicePrincipalsClient
 	RoleAssignments   authorization.RoleAssignmentsClient
+	ResourceSKUs      computemgmt.ResourceSkusClient
+
+	// IgnoredTags are preserved when a managed cluster is updated.
+	IgnoredTags azure.IgnoredTags
 }
 
 // NewAggregateClient produces the various clients used by the AKS controller.
-func NewAggregateClient(creds map[string]string, auth autorest.Authorizer) (AKSClient, error) {
+func NewAggregateClient(creds map[string]string, auth autorest.Authorizer, ignored azure.IgnoredTags) (AKSClient, error) {
 	mcc := containerservice.NewManagedClustersClient(creds[azure.CredentialsKeySubscriptionID])
 	mcc.Authorizer = auth
 	_ = mcc.AddToUserAgent(azure.UserAgent)
@@ -94,6 +144,10 @@ func NewAggregateClient(creds map[string]string, auth autorest.Authorizer) (AKSC
 		return nil, errors.Wrap(err, "cannot refresh service principal token")
 	}
 
+	rsc := computemgmt.NewResourceSkusClient(creds[azure.CredentialsKeySubscriptionID])
+	rsc.Authorizer = auth
+	_ = rsc.AddToUserAgent(azure.UserAgent)
+
 	ta := autorest.NewBearerAuthorizer(token)
 
 	ac := graphrbac.NewApplicationsClient(creds[azure.CredentialsKeyTenantID])
@@ -109,6 +163,8 @@ func NewAggregateClient(creds map[string]string, auth autorest.Authorizer) (AKSC
 		Applications:      ac,
 		ServicePrincipals: spc,
 		RoleAssignments:   rac,
+		ResourceSKUs:      rsc,
+		IgnoredTags:       ignored,
 	}, nil
 }
 
@@ -120,6 +176,13 @@ func (c AggregateClient) GetManagedCluster(ctx context.Context, ac *v1alpha3.AKS
 // EnsureManagedCluster ensures the supplied AKS cluster exists, including
 // ensuring any required service principals and role assignments exist.
 func (c AggregateClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error {
+	if err := ValidateSpotSettings(ac.Spec.AKSClusterParameters); err != nil {
+		return err
+	}
+	if err := c.validateNodeOSDisk(ctx, ac); err != nil {
+		return err
+	}
+
 	app, err := c.ensureApplication(ctx, meta.GetExternalName(ac), secret)
 	if err != nil {
 		return err
@@ -134,11 +197,88 @@ func (c AggregateClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.
 		return err
 	}
 
-	mc := newManagedCluster(ac, to.String(app.AppID), secret)
-	_, err = c.ManagedClusters.CreateOrUpdate(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac), mc)
+	mc, err := newManagedCluster(ac, to.String(app.AppID), secret)
+	if err != nil {
+		return err
+	}
+	return c.createOrUpdate(ctx, ac, mc)
+}
+
+// UpdateManagedCluster updates the mutable properties of the supplied AKS
+// cluster, e.g. its tags, SKU tier, and node count. The cluster is updated by
+// submitting it to Azure in its entirety, as Azure only supports updating tags
+// alone. Changing the node count scales the cluster.
+func (c AggregateClient) UpdateManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error {
+	mc, err := c.ManagedClusters.Get(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac))
+	if err != nil {
+		return err
+	}
+	if err := ValidateSpotSettings(ac.Spec.AKSClusterParameters); err != nil {
+		return err
+	}
+	if err := ValidateImmutableFields(ac.Spec.AKSClusterParameters, mc); err != nil {
+		return err
+	}
+	if err := c.validateNodeOSDisk(ctx, ac); err != nil {
+		return err
+	}
+	UpdateManagedCluster(&mc, ac.Spec.AKSClusterParameters, c.IgnoredTags)
+	return c.createOrUpdate(ctx, ac, mc)
+}
+
+// createOrUpdate submits the supplied managed cluster to Azure. Properties
+// the Azure SDK we use does not support, i.e. the private DNS zone and the
+// OS disk type, are added to the request body using a newer API version.
+func (c AggregateClient) createOrUpdate(ctx context.Context, ac *v1alpha3.AKSCluster, mc containerservice.ManagedCluster) error {
+	version := ""
+	props := map[string]interface{}{}
+	if p := ac.Spec.APIServerAccessProfile; p != nil && p.PrivateDNSZone != nil {
+		version = PrivateDNSZoneAPIVersion
+		props["apiServerAccessProfile"] = newAPIServerAccessProfileProperties(p)
+	}
+	if t := ac.Spec.NodeOSDiskType; t != nil && mc.AgentPoolProfiles != nil {
+		app, err := newAgentPoolProfilesProperties(*mc.AgentPoolProfiles, *t)
+		if err != nil {
+			return err
+		}
+		version = OSDiskTypeAPIVersion
+		props["agentPoolProfiles"] = app
+	}
+
+	mcc := c.ManagedClusters
+	if version != "" {
+		mcc.RequestInspector = func(pr autorest.Preparer) autorest.Preparer {
+			return autorest.DecoratePreparer(pr, azure.WithAPIVersion(version), azure.WithProperties(props))
+		}
+	}
+
+	_, err := mcc.CreateOrUpdate(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac), mc)
 	return err
 }
 
+// validateNodeOSDisk returns an error if the supplied cluster specifies an
+// ephemeral OS disk that will not fit in the cache disk of its node VM size.
+// The cache disk size is read from the resource SKUs available in the
+// cluster's location. VM sizes that are not found are validated by Azure.
+func (c AggregateClient) validateNodeOSDisk(ctx context.Context, ac *v1alpha3.AKSCluster) error {
+	p := ac.Spec.AKSClusterParameters
+	if p.NodeOSDiskType == nil || *p.NodeOSDiskType != OSDiskTypeEphemeral {
+		return nil
+	}
+	it, err := c.ResourceSKUs.ListComplete(ctx, fmt.Sprintf("location eq '%s'", p.Location))
+	for ; err == nil && it.NotDone(); err = it.NextWithContext(ctx) {
+		sku := it.Value()
+		if to.String(sku.ResourceType) != resourceTypeVirtualMachines || !strings.EqualFold(to.String(sku.Name), p.NodeVMSize) {
+			continue
+		}
+		if cache, ok := CacheDiskSizeGB(sku); ok {
+			return ValidateNodeOSDisk(p, cache)
+		}
+		return nil
+	}
+	return errors.Wrap(err, errListResourceSKUs)
+}
+
 // DeleteManagedCluster deletes the supplied AKS cluster, including its service
 // principals and any role assignments.
 func (c AggregateClient) DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error {
@@ -264,25 +404,20 @@ func (c AggregateClient) deleteApplication(ctx context.Context, name string) err
 	return nil
 }
 
-func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string) containerservice.ManagedCluster {
-	nodeCount := int32(v1alpha3.DefaultNodeCount)
-	if c.Spec.NodeCount != nil {
-		nodeCount = int32(*c.Spec.NodeCount)
+func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string) (containerservice.ManagedCluster, error) {
+	app, err := newAgentPoolProfile(c)
+	if err != nil {
+		return containerservice.ManagedCluster{}, err
 	}
 
 	p := containerservice.ManagedCluster{
 		Name:     to.StringPtr(meta.GetExternalName(c)),
 		Location: to.StringPtr(c.Spec.Location),
+		Tags:     azure.WithOwnershipTags(azure.ToStringPtrMap(c.Spec.Tags), v1alpha3.AKSClusterKind, c),
 		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 			KubernetesVersion: to.StringPtr(c.Spec.Version),
 			DNSPrefix:         to.StringPtr(c.Spec.DNSNamePrefix),
-			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
-				{
-					Name:   to.StringPtr(AgentPoolProfileName),
-					Count:  &nodeCount,
-					VMSize: containerservice.VMSizeTypes(c.Spec.NodeVMSize),
-				},
-			},
+			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{app},
 			ServicePrincipalProfile: &containerservice.ManagedClusterServicePrincipalProfile{
 				ClientID: to.StringPtr(appID),
 				Secret:   to.StringPtr(secret),
@@ -291,19 +426,310 @@ func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string) containerse
 		},
 	}
 
+	if c.Spec.SKU != nil {
+		p.Sku = newManagedClusterSKU(c.Spec.SKU)
+	}
+
+	if c.Spec.APIServerAccessProfile != nil {
+		p.ManagedClusterProperties.APIServerAccessProfile = newAPIServerAccessProfile(c.Spec.APIServerAccessProfile)
+	}
+
 	if c.Spec.VnetSubnetID != "" {
-		p.ManagedClusterProperties.NetworkProfile = &containerservice.NetworkProfile{NetworkPlugin: containerservice.Azure}
-		p.ManagedClusterProperties.AgentPoolProfiles = &[]containerservice.ManagedClusterAgentPoolProfile{
-			{
-				Name:         to.StringPtr(AgentPoolProfileName),
-				Count:        &nodeCount,
-				VMSize:       containerservice.VMSizeTypes(c.Spec.NodeVMSize),
-				VnetSubnetID: to.StringPtr(c.Spec.VnetSubnetID),
-			},
+		p.ManagedClusterProperties.NetworkProfile = &containerservice.NetworkProfileType{NetworkPlugin: containerservice.Azure}
+		(*p.ManagedClusterProperties.AgentPoolProfiles)[0].VnetSubnetID = to.StringPtr(c.Spec.VnetSubnetID)
+	}
+
+	return p, nil
+}
+
+func newAgentPoolProfile(c *v1alpha3.AKSCluster) (containerservice.ManagedClusterAgentPoolProfile, error) {
+	nodeCount := int32(v1alpha3.DefaultNodeCount)
+	if c.Spec.NodeCount != nil {
+		nodeCount = int32(*c.Spec.NodeCount)
+	}
+
+	app := containerservice.ManagedClusterAgentPoolProfile{
+		Name:         to.StringPtr(AgentPoolProfileName),
+		Count:        &nodeCount,
+		VMSize:       containerservice.VMSizeTypes(c.Spec.NodeVMSize),
+		OsDiskSizeGB: azure.ToInt32PtrFromIntPtr(c.Spec.NodeOSDiskSizeGB),
+		MaxPods:      azure.ToInt32PtrFromIntPtr(c.Spec.NodeMaxPods),
+	}
+	if c.Spec.NodeOSType != nil {
+		app.OsType = containerservice.OSType(*c.Spec.NodeOSType)
+	}
+	if c.Spec.NodeScaleSetPriority != nil {
+		app.ScaleSetPriority = containerservice.ScaleSetPriority(*c.Spec.NodeScaleSetPriority)
+	}
+	if c.Spec.NodeScaleSetEvictionPolicy != nil {
+		app.ScaleSetEvictionPolicy = containerservice.ScaleSetEvictionPolicy(*c.Spec.NodeScaleSetEvictionPolicy)
+	}
+	if c.Spec.NodeSpotMaxPrice != nil {
+		price, err := strconv.ParseFloat(*c.Spec.NodeSpotMaxPrice, 64)
+		if err != nil {
+			return containerservice.ManagedClusterAgentPoolProfile{}, errors.Wrap(err, errParseSpotMaxPrice)
+		}
+		app.SpotMaxPrice = &price
+	}
+	return app, nil
+}
+
+// agentPoolProfile returns the agent pool profile of the supplied managed
+// cluster that was created for its worker nodes, if any.
+func agentPoolProfile(mc containerservice.ManagedCluster) *containerservice.ManagedClusterAgentPoolProfile {
+	if mc.ManagedClusterProperties == nil || mc.AgentPoolProfiles == nil {
+		return nil
+	}
+	for i := range *mc.AgentPoolProfiles {
+		if to.String((*mc.AgentPoolProfiles)[i].Name) == AgentPoolProfileName {
+			return &(*mc.AgentPoolProfiles)[i]
 		}
 	}
+	return nil
+}
 
-	return p
+// nodeCountNeedsUpdate returns true if the supplied parameters specify a node
+// count that differs from that of the supplied managed cluster. Node count
+// drift is ignored when the cluster autoscaler is enabled, because Azure
+// manages the node count.
+func nodeCountNeedsUpdate(p v1alpha3.AKSClusterParameters, mc containerservice.ManagedCluster) bool {
+	app := agentPoolProfile(mc)
+	if p.NodeCount == nil || app == nil || to.Bool(app.EnableAutoScaling) {
+		return false
+	}
+	return int32(*p.NodeCount) != to.Int32(app.Count)
+}
+
+// NodeScaleSetEvictionPolicy returns the eviction policy of the worker nodes
+// of the supplied managed cluster.
+func NodeScaleSetEvictionPolicy(mc containerservice.ManagedCluster) string {
+	app := agentPoolProfile(mc)
+	if app == nil {
+		return ""
+	}
+	return string(app.ScaleSetEvictionPolicy)
+}
+
+// ValidateSpotSettings returns an error if the supplied parameters specify an
+// eviction policy or maximum price for worker nodes that are not Spot nodes,
+// or a maximum price that cannot be parsed.
+func ValidateSpotSettings(p v1alpha3.AKSClusterParameters) error {
+	if p.NodeSpotMaxPrice != nil {
+		if _, err := strconv.ParseFloat(*p.NodeSpotMaxPrice, 64); err != nil {
+			return errors.Wrap(err, errParseSpotMaxPrice)
+		}
+	}
+	if p.NodeScaleSetEvictionPolicy == nil && p.NodeSpotMaxPrice == nil {
+		return nil
+	}
+	if p.NodeScaleSetPriority == nil || *p.NodeScaleSetPriority != string(containerservice.Spot) {
+		return errors.New(errSpotSettingsWithoutSpot)
+	}
+	return nil
+}
+
+// ValidateImmutableFields returns an error if the supplied parameters change
+// any fields of the supplied managed cluster that cannot be modified once the
+// cluster has been created.
+func ValidateImmutableFields(p v1alpha3.AKSClusterParameters, mc containerservice.ManagedCluster) error {
+	app := agentPoolProfile(mc)
+	if app == nil {
+		return nil
+	}
+	if p.NodeScaleSetPriority != nil {
+		priority := app.ScaleSetPriority
+		if priority == "" {
+			priority = containerservice.Regular
+		}
+		if *p.NodeScaleSetPriority != string(priority) {
+			return errors.Errorf(errFmtImmutableField, "nodeScaleSetPriority")
+		}
+	}
+	if p.NodeScaleSetEvictionPolicy != nil && *p.NodeScaleSetEvictionPolicy != string(app.ScaleSetEvictionPolicy) {
+		return errors.Errorf(errFmtImmutableField, "nodeScaleSetEvictionPolicy")
+	}
+	if p.NodeSpotMaxPrice != nil {
+		price, err := strconv.ParseFloat(*p.NodeSpotMaxPrice, 64)
+		if err != nil {
+			return errors.Wrap(err, errParseSpotMaxPrice)
+		}
+		if app.SpotMaxPrice == nil || price != *app.SpotMaxPrice {
+			return errors.Errorf(errFmtImmutableField, "nodeSpotMaxPrice")
+		}
+	}
+	return nil
+}
+
+// newAgentPoolProfilesProperties returns the supplied agent pool profiles as
+// request body properties with the supplied OS disk type, which the Azure SDK
+// we use does not support.
+func newAgentPoolProfilesProperties(profiles []containerservice.ManagedClusterAgentPoolProfile, osDiskType string) ([]interface{}, error) {
+	props := make([]interface{}, len(profiles))
+	for i := range profiles {
+		b, err := json.Marshal(profiles[i])
+		if err != nil {
+			return nil, errors.Wrap(err, errEncodeAgentPoolProfile)
+		}
+		app := map[string]interface{}{}
+		if err := json.Unmarshal(b, &app); err != nil {
+			return nil, errors.Wrap(err, errDecodeAgentPoolProfile)
+		}
+		app["osDiskType"] = osDiskType
+		props[i] = app
+	}
+	return props, nil
+}
+
+// CacheDiskSizeGB returns the size in GB of the cache disk of the supplied VM
+// resource SKU, if the SKU reports one.
+func CacheDiskSizeGB(sku computemgmt.ResourceSku) (int, bool) {
+	if sku.Capabilities == nil {
+		return 0, false
+	}
+	for _, c := range *sku.Capabilities {
+		if to.String(c.Name) != capabilityCachedDiskBytes {
+			continue
+		}
+		b, err := strconv.ParseInt(to.String(c.Value), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return int(b / bytesPerGB), true
+	}
+	return 0, false
+}
+
+// ValidateNodeOSDisk returns an error if the supplied parameters specify an
+// ephemeral OS disk that will not fit in a node VM cache disk of the supplied
+// size in GB.
+func ValidateNodeOSDisk(p v1alpha3.AKSClusterParameters, cache int) error {
+	if p.NodeOSDiskType == nil || *p.NodeOSDiskType != OSDiskTypeEphemeral {
+		return nil
+	}
+	size := DefaultOSDiskSizeGB
+	if p.NodeOSDiskSizeGB != nil && *p.NodeOSDiskSizeGB != 0 {
+		size = *p.NodeOSDiskSizeGB
+	}
+	if size > cache {
+		return errors.Errorf(errFmtEphemeralOSDiskTooLarge, size, cache, p.NodeVMSize)
+	}
+	return nil
+}
+
+// UpdateManagedCluster updates the mutable properties of the supplied managed
+// cluster to match the supplied parameters. Tags are only managed if the
+// parameters specify them, and ignored tags such as those injected by Azure
+// are preserved.
+func UpdateManagedCluster(mc *containerservice.ManagedCluster, p v1alpha3.AKSClusterParameters, ignored azure.IgnoredTags) {
+	if p.Tags != nil {
+		mc.Tags = ignored.Preserve(azure.ToStringPtrMap(p.Tags), mc.Tags)
+	}
+	if nodeCountNeedsUpdate(p, *mc) {
+		agentPoolProfile(*mc).Count = azure.ToInt32PtrFromIntPtr(p.NodeCount)
+	}
+	if p.SKU != nil {
+		mc.Sku = newManagedClusterSKU(p.SKU)
+	}
+	if p.APIServerAccessProfile != nil && p.APIServerAccessProfile.AuthorizedIPRanges != nil {
+		if mc.ManagedClusterProperties == nil {
+			mc.ManagedClusterProperties = &containerservice.ManagedClusterProperties{}
+		}
+		if mc.APIServerAccessProfile == nil {
+			mc.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{}
+		}
+		// An empty list (as opposed to a nil one) removes all restrictions.
+		r := append([]string{}, *p.APIServerAccessProfile.AuthorizedIPRanges...)
+		mc.APIServerAccessProfile.AuthorizedIPRanges = &r
+	}
+}
+
+// IsUpToDate returns true if the mutable properties of the supplied managed
+// cluster match the supplied parameters, disregarding the supplied ignored
+// tags.
+func IsUpToDate(p v1alpha3.AKSClusterParameters, mc containerservice.ManagedCluster, ignored azure.IgnoredTags) bool {
+	if ValidateImmutableFields(p, mc) != nil {
+		// Report the cluster as needing an update so that Update surfaces the
+		// attempt to change an immutable field.
+		return false
+	}
+	if p.Tags != nil && ignored.NeedUpdate(azure.ToStringPtrMap(p.Tags), mc.Tags) {
+		return false
+	}
+	if p.SKU != nil && p.SKU.Tier != SKUTier(mc) {
+		return false
+	}
+	if nodeCountNeedsUpdate(p, mc) {
+		return false
+	}
+	if p.APIServerAccessProfile != nil && p.APIServerAccessProfile.AuthorizedIPRanges != nil {
+		return sameStrings(*p.APIServerAccessProfile.AuthorizedIPRanges, authorizedIPRanges(mc))
+	}
+	return true
+}
+
+func authorizedIPRanges(mc containerservice.ManagedCluster) []string {
+	if mc.ManagedClusterProperties == nil || mc.APIServerAccessProfile == nil || mc.APIServerAccessProfile.AuthorizedIPRanges == nil {
+		return nil
+	}
+	return *mc.APIServerAccessProfile.AuthorizedIPRanges
+}
+
+// sameStrings returns true if the supplied slices contain the same strings,
+// regardless of order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		if seen[s] == 0 {
+			return false
+		}
+		seen[s]--
+	}
+	return true
+}
+
+// SKUTier returns the effective SKU tier of the supplied managed cluster.
+// Clusters without a SKU tier use the Free tier.
+func SKUTier(mc containerservice.ManagedCluster) string {
+	if mc.Sku == nil || mc.Sku.Tier == "" {
+		return string(containerservice.Free)
+	}
+	return string(mc.Sku.Tier)
+}
+
+func newAPIServerAccessProfile(p *v1alpha3.APIServerAccessProfile) *containerservice.ManagedClusterAPIServerAccessProfile {
+	ap := &containerservice.ManagedClusterAPIServerAccessProfile{EnablePrivateCluster: p.EnablePrivateCluster}
+	if p.AuthorizedIPRanges != nil {
+		r := append([]string{}, *p.AuthorizedIPRanges...)
+		ap.AuthorizedIPRanges = &r
+	}
+	return ap
+}
+
+func newAPIServerAccessProfileProperties(p *v1alpha3.APIServerAccessProfile) map[string]interface{} {
+	props := map[string]interface{}{}
+	if p.EnablePrivateCluster != nil {
+		props["enablePrivateCluster"] = *p.EnablePrivateCluster
+	}
+	if p.PrivateDNSZone != nil {
+		props["privateDNSZone"] = *p.PrivateDNSZone
+	}
+	if p.AuthorizedIPRanges != nil {
+		props["authorizedIPRanges"] = *p.AuthorizedIPRanges
+	}
+	return props
+}
+
+func newManagedClusterSKU(s *v1alpha3.AKSClusterSKU) *containerservice.ManagedClusterSKU {
+	return &containerservice.ManagedClusterSKU{
+		Name: containerservice.ManagedClusterSKUNameBasic,
+		Tier: containerservice.ManagedClusterSKUTier(s.Tier),
+	}
 }
 
 func newPasswordCredential(secret string) (graphrbac.PasswordCredential, error) {
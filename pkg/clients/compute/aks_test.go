@@ -0,0 +1,580 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"strconv"
+	"testing"
+
+	computemgmt "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-03-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/compute/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestIsUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.AKSClusterParameters
+		mc   containerservice.ManagedCluster
+		want bool
+	}{
+		"UpToDate": {
+			p: v1alpha3.AKSClusterParameters{
+				Tags: map[string]string{"team": "platform"},
+				SKU:  &v1alpha3.AKSClusterSKU{Tier: "Paid"},
+			},
+			mc: containerservice.ManagedCluster{
+				Tags: map[string]*string{"team": to.StringPtr("platform"), "aks-managed-poolName": to.StringPtr("agentpool")},
+				Sku:  &containerservice.ManagedClusterSKU{Name: containerservice.ManagedClusterSKUNameBasic, Tier: containerservice.Paid},
+			},
+			want: true,
+		},
+		"ImplicitFreeTier": {
+			p:    v1alpha3.AKSClusterParameters{SKU: &v1alpha3.AKSClusterSKU{Tier: "Free"}},
+			mc:   containerservice.ManagedCluster{},
+			want: true,
+		},
+		"NoDesiredTier": {
+			p:    v1alpha3.AKSClusterParameters{},
+			mc:   containerservice.ManagedCluster{Sku: &containerservice.ManagedClusterSKU{Tier: containerservice.Paid}},
+			want: true,
+		},
+		"TagsDiffer": {
+			p:    v1alpha3.AKSClusterParameters{Tags: map[string]string{"team": "platform"}},
+			mc:   containerservice.ManagedCluster{Tags: map[string]*string{"team": to.StringPtr("data")}},
+			want: false,
+		},
+		"TagsUnmanaged": {
+			p:    v1alpha3.AKSClusterParameters{},
+			mc:   containerservice.ManagedCluster{Tags: map[string]*string{"team": to.StringPtr("data")}},
+			want: true,
+		},
+		"TierDiffers": {
+			p:    v1alpha3.AKSClusterParameters{SKU: &v1alpha3.AKSClusterSKU{Tier: "Paid"}},
+			mc:   containerservice.ManagedCluster{},
+			want: false,
+		},
+		"NodeCountDiffers": {
+			p: v1alpha3.AKSClusterParameters{NodeCount: to.IntPtr(3)},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(1)}},
+			}},
+			want: false,
+		},
+		"AutoscaledNodeCountDiffers": {
+			p: v1alpha3.AKSClusterParameters{NodeCount: to.IntPtr(3)},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(1), EnableAutoScaling: to.BoolPtr(true)}},
+			}},
+			want: true,
+		},
+		"ImmutableFieldChanged": {
+			p: v1alpha3.AKSClusterParameters{NodeScaleSetPriority: to.StringPtr("Spot")},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName)}},
+			}},
+			want: false,
+		},
+		"AuthorizedIPRangesReordered": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				AuthorizedIPRanges: &[]string{"10.0.0.0/16", "192.168.0.0/24"},
+			}},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{"192.168.0.0/24", "10.0.0.0/16"},
+				},
+			}},
+			want: true,
+		},
+		"AuthorizedIPRangesDiffer": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				AuthorizedIPRanges: &[]string{"10.0.0.0/16"},
+			}},
+			mc:   containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}},
+			want: false,
+		},
+		"RestrictionsRemoved": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				AuthorizedIPRanges: &[]string{},
+			}},
+			mc:   containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}},
+			want: true,
+		},
+		"RestrictionsNotRemoved": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				AuthorizedIPRanges: &[]string{},
+			}},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{"10.0.0.0/16"},
+				},
+			}},
+			want: false,
+		},
+		"RestrictionsUnmanaged": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{}},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{"10.0.0.0/16"},
+				},
+			}},
+			want: true,
+		},
+		"PrivateClusterIgnored": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				EnablePrivateCluster: to.BoolPtr(true),
+			}},
+			mc:   containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsUpToDate(tc.p, tc.mc, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateManagedCluster(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.AKSClusterParameters
+		mc   containerservice.ManagedCluster
+		want containerservice.ManagedCluster
+	}{
+		"TagsAndTier": {
+			p: v1alpha3.AKSClusterParameters{
+				Tags: map[string]string{"team": "platform"},
+				SKU:  &v1alpha3.AKSClusterSKU{Tier: "Paid"},
+			},
+			mc: containerservice.ManagedCluster{
+				Location: to.StringPtr("westus"),
+				Tags:     map[string]*string{"team": to.StringPtr("data"), "aks-managed-poolName": to.StringPtr("agentpool")},
+			},
+			want: containerservice.ManagedCluster{
+				Location: to.StringPtr("westus"),
+				Tags:     map[string]*string{"team": to.StringPtr("platform"), "aks-managed-poolName": to.StringPtr("agentpool")},
+				Sku:      &containerservice.ManagedClusterSKU{Name: containerservice.ManagedClusterSKUNameBasic, Tier: containerservice.Paid},
+			},
+		},
+		"TagsUnmanaged": {
+			p:    v1alpha3.AKSClusterParameters{},
+			mc:   containerservice.ManagedCluster{Tags: map[string]*string{"team": to.StringPtr("data")}},
+			want: containerservice.ManagedCluster{Tags: map[string]*string{"team": to.StringPtr("data")}},
+		},
+		"ScaleNodeCount": {
+			p: v1alpha3.AKSClusterParameters{NodeCount: to.IntPtr(3)},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(1)}},
+			}},
+			want: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(3)}},
+			}},
+		},
+		"AutoscaledNodeCount": {
+			p: v1alpha3.AKSClusterParameters{NodeCount: to.IntPtr(3)},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(5), EnableAutoScaling: to.BoolPtr(true)}},
+			}},
+			want: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName), Count: to.Int32Ptr(5), EnableAutoScaling: to.BoolPtr(true)}},
+			}},
+		},
+		"AuthorizedIPRanges": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				EnablePrivateCluster: to.BoolPtr(true),
+				AuthorizedIPRanges:   &[]string{"10.0.0.0/16"},
+			}},
+			mc: containerservice.ManagedCluster{},
+			want: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{"10.0.0.0/16"},
+				},
+			}},
+		},
+		"RemoveRestrictions": {
+			p: v1alpha3.AKSClusterParameters{APIServerAccessProfile: &v1alpha3.APIServerAccessProfile{
+				AuthorizedIPRanges: &[]string{},
+			}},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{"10.0.0.0/16"},
+				},
+			}},
+			want: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: &[]string{},
+				},
+			}},
+		},
+		"KeepsTierIfUnspecified": {
+			p: v1alpha3.AKSClusterParameters{},
+			mc: containerservice.ManagedCluster{
+				Sku: &containerservice.ManagedClusterSKU{Name: containerservice.ManagedClusterSKUNameBasic, Tier: containerservice.Paid},
+			},
+			want: containerservice.ManagedCluster{
+				Sku: &containerservice.ManagedClusterSKU{Name: containerservice.ManagedClusterSKUNameBasic, Tier: containerservice.Paid},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			UpdateManagedCluster(&tc.mc, tc.p, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, tc.mc); diff != "" {
+				t.Errorf("UpdateManagedCluster(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewAPIServerAccessProfileProperties(t *testing.T) {
+	cases := map[string]struct {
+		p    *v1alpha3.APIServerAccessProfile
+		want map[string]interface{}
+	}{
+		"Empty": {
+			p:    &v1alpha3.APIServerAccessProfile{},
+			want: map[string]interface{}{},
+		},
+		"PrivateCluster": {
+			p: &v1alpha3.APIServerAccessProfile{
+				EnablePrivateCluster: to.BoolPtr(true),
+				PrivateDNSZone:       to.StringPtr("System"),
+				AuthorizedIPRanges:   &[]string{"10.0.0.0/16"},
+			},
+			want: map[string]interface{}{
+				"enablePrivateCluster": true,
+				"privateDNSZone":       "System",
+				"authorizedIPRanges":   []string{"10.0.0.0/16"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := newAPIServerAccessProfileProperties(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("newAPIServerAccessProfileProperties(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewAgentPoolProfile(t *testing.T) {
+	cases := map[string]struct {
+		c    *v1alpha3.AKSCluster
+		want containerservice.ManagedClusterAgentPoolProfile
+		err  error
+	}{
+		"Defaults": {
+			c: &v1alpha3.AKSCluster{Spec: v1alpha3.AKSClusterSpec{AKSClusterParameters: v1alpha3.AKSClusterParameters{
+				NodeVMSize: "Standard_DS2_v2",
+			}}},
+			want: containerservice.ManagedClusterAgentPoolProfile{
+				Name:   to.StringPtr(AgentPoolProfileName),
+				Count:  to.Int32Ptr(v1alpha3.DefaultNodeCount),
+				VMSize: containerservice.VMSizeTypes("Standard_DS2_v2"),
+			},
+		},
+		"NodeOSAndMaxPods": {
+			c: &v1alpha3.AKSCluster{Spec: v1alpha3.AKSClusterSpec{AKSClusterParameters: v1alpha3.AKSClusterParameters{
+				NodeCount:        to.IntPtr(3),
+				NodeVMSize:       "Standard_DS2_v2",
+				NodeOSType:       to.StringPtr("Windows"),
+				NodeOSDiskSizeGB: to.IntPtr(64),
+				NodeMaxPods:      to.IntPtr(50),
+			}}},
+			want: containerservice.ManagedClusterAgentPoolProfile{
+				Name:         to.StringPtr(AgentPoolProfileName),
+				Count:        to.Int32Ptr(3),
+				VMSize:       containerservice.VMSizeTypes("Standard_DS2_v2"),
+				OsType:       containerservice.Windows,
+				OsDiskSizeGB: to.Int32Ptr(64),
+				MaxPods:      to.Int32Ptr(50),
+			},
+		},
+		"Spot": {
+			c: &v1alpha3.AKSCluster{Spec: v1alpha3.AKSClusterSpec{AKSClusterParameters: v1alpha3.AKSClusterParameters{
+				NodeVMSize:                 "Standard_DS2_v2",
+				NodeScaleSetPriority:       to.StringPtr("Spot"),
+				NodeScaleSetEvictionPolicy: to.StringPtr("Deallocate"),
+				NodeSpotMaxPrice:           to.StringPtr("0.05"),
+			}}},
+			want: containerservice.ManagedClusterAgentPoolProfile{
+				Name:                   to.StringPtr(AgentPoolProfileName),
+				Count:                  to.Int32Ptr(v1alpha3.DefaultNodeCount),
+				VMSize:                 containerservice.VMSizeTypes("Standard_DS2_v2"),
+				ScaleSetPriority:       containerservice.Spot,
+				ScaleSetEvictionPolicy: containerservice.Deallocate,
+				SpotMaxPrice:           to.Float64Ptr(0.05),
+			},
+		},
+		"InvalidSpotMaxPrice": {
+			c: &v1alpha3.AKSCluster{Spec: v1alpha3.AKSClusterSpec{AKSClusterParameters: v1alpha3.AKSClusterParameters{
+				NodeVMSize:           "Standard_DS2_v2",
+				NodeScaleSetPriority: to.StringPtr("Spot"),
+				NodeSpotMaxPrice:     to.StringPtr("cheap"),
+			}}},
+			err: errors.Wrap(&strconv.NumError{Func: "ParseFloat", Num: "cheap", Err: strconv.ErrSyntax}, errParseSpotMaxPrice),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := newAgentPoolProfile(tc.c)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("newAgentPoolProfile(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("newAgentPoolProfile(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewAgentPoolProfilesProperties(t *testing.T) {
+	profiles := []containerservice.ManagedClusterAgentPoolProfile{{
+		Name:         to.StringPtr(AgentPoolProfileName),
+		Count:        to.Int32Ptr(1),
+		OsDiskSizeGB: to.Int32Ptr(64),
+	}}
+	want := []interface{}{map[string]interface{}{
+		"name":         AgentPoolProfileName,
+		"count":        float64(1),
+		"osDiskSizeGB": float64(64),
+		"osDiskType":   OSDiskTypeEphemeral,
+	}}
+
+	got, err := newAgentPoolProfilesProperties(profiles, OSDiskTypeEphemeral)
+	if err != nil {
+		t.Fatalf("newAgentPoolProfilesProperties(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newAgentPoolProfilesProperties(...): -want, +got\n%s", diff)
+	}
+}
+
+func TestCacheDiskSizeGB(t *testing.T) {
+	type want struct {
+		size int
+		ok   bool
+	}
+	cases := map[string]struct {
+		sku  computemgmt.ResourceSku
+		want want
+	}{
+		"NoCapabilities": {
+			sku: computemgmt.ResourceSku{},
+		},
+		"NoCachedDiskBytes": {
+			sku: computemgmt.ResourceSku{Capabilities: &[]computemgmt.ResourceSkuCapabilities{
+				{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("2")},
+			}},
+		},
+		"InvalidCachedDiskBytes": {
+			sku: computemgmt.ResourceSku{Capabilities: &[]computemgmt.ResourceSkuCapabilities{
+				{Name: to.StringPtr(capabilityCachedDiskBytes), Value: to.StringPtr("lots")},
+			}},
+		},
+		"CachedDiskBytes": {
+			sku: computemgmt.ResourceSku{Capabilities: &[]computemgmt.ResourceSkuCapabilities{
+				{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("2")},
+				{Name: to.StringPtr(capabilityCachedDiskBytes), Value: to.StringPtr("92341796864")},
+			}},
+			want: want{size: 86, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			size, ok := CacheDiskSizeGB(tc.sku)
+			if diff := cmp.Diff(tc.want, want{size: size, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("CacheDiskSizeGB(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateNodeOSDisk(t *testing.T) {
+	cases := map[string]struct {
+		p     v1alpha3.AKSClusterParameters
+		cache int
+		want  error
+	}{
+		"ManagedDisk": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeVMSize:       "Standard_B2s",
+				NodeOSDiskType:   to.StringPtr("Managed"),
+				NodeOSDiskSizeGB: to.IntPtr(256),
+			},
+		},
+		"EphemeralDiskFits": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeVMSize:       "Standard_DS3_v2",
+				NodeOSDiskType:   to.StringPtr(OSDiskTypeEphemeral),
+				NodeOSDiskSizeGB: to.IntPtr(100),
+			},
+			cache: 172,
+		},
+		"EphemeralDiskTooLarge": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeVMSize:       "Standard_DS2_v2",
+				NodeOSDiskType:   to.StringPtr(OSDiskTypeEphemeral),
+				NodeOSDiskSizeGB: to.IntPtr(100),
+			},
+			cache: 86,
+			want:  errors.Errorf(errFmtEphemeralOSDiskTooLarge, 100, 86, "Standard_DS2_v2"),
+		},
+		"EphemeralDefaultDiskTooLarge": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeVMSize:     "Standard_D2s_v3",
+				NodeOSDiskType: to.StringPtr(OSDiskTypeEphemeral),
+			},
+			cache: 50,
+			want:  errors.Errorf(errFmtEphemeralOSDiskTooLarge, DefaultOSDiskSizeGB, 50, "Standard_D2s_v3"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateNodeOSDisk(tc.p, tc.cache)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateNodeOSDisk(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateSpotSettings(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.AKSClusterParameters
+		want error
+	}{
+		"NoSpotSettings": {
+			p: v1alpha3.AKSClusterParameters{NodeScaleSetPriority: to.StringPtr("Regular")},
+		},
+		"Spot": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeScaleSetPriority:       to.StringPtr("Spot"),
+				NodeScaleSetEvictionPolicy: to.StringPtr("Delete"),
+				NodeSpotMaxPrice:           to.StringPtr("-1"),
+			},
+		},
+		"EvictionPolicyWithRegularPriority": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeScaleSetPriority:       to.StringPtr("Regular"),
+				NodeScaleSetEvictionPolicy: to.StringPtr("Delete"),
+			},
+			want: errors.New(errSpotSettingsWithoutSpot),
+		},
+		"SpotMaxPriceWithoutPriority": {
+			p:    v1alpha3.AKSClusterParameters{NodeSpotMaxPrice: to.StringPtr("0.05")},
+			want: errors.New(errSpotSettingsWithoutSpot),
+		},
+		"InvalidSpotMaxPrice": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeScaleSetPriority: to.StringPtr("Spot"),
+				NodeSpotMaxPrice:     to.StringPtr("cheap"),
+			},
+			want: errors.Wrap(&strconv.NumError{Func: "ParseFloat", Num: "cheap", Err: strconv.ErrSyntax}, errParseSpotMaxPrice),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateSpotSettings(tc.p)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateSpotSettings(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateImmutableFields(t *testing.T) {
+	spot := containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+		AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{
+			Name:                   to.StringPtr(AgentPoolProfileName),
+			ScaleSetPriority:       containerservice.Spot,
+			ScaleSetEvictionPolicy: containerservice.Delete,
+			SpotMaxPrice:           to.Float64Ptr(-1),
+		}},
+	}}
+
+	cases := map[string]struct {
+		p    v1alpha3.AKSClusterParameters
+		mc   containerservice.ManagedCluster
+		want error
+	}{
+		"NoAgentPool": {
+			p:  v1alpha3.AKSClusterParameters{NodeScaleSetPriority: to.StringPtr("Spot")},
+			mc: containerservice.ManagedCluster{},
+		},
+		"Unchanged": {
+			p: v1alpha3.AKSClusterParameters{
+				NodeScaleSetPriority:       to.StringPtr("Spot"),
+				NodeScaleSetEvictionPolicy: to.StringPtr("Delete"),
+				NodeSpotMaxPrice:           to.StringPtr("-1"),
+			},
+			mc: spot,
+		},
+		"ImplicitRegularPriority": {
+			p: v1alpha3.AKSClusterParameters{NodeScaleSetPriority: to.StringPtr("Regular")},
+			mc: containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{{Name: to.StringPtr(AgentPoolProfileName)}},
+			}},
+		},
+		"PriorityChanged": {
+			p:    v1alpha3.AKSClusterParameters{NodeScaleSetPriority: to.StringPtr("Regular")},
+			mc:   spot,
+			want: errors.Errorf(errFmtImmutableField, "nodeScaleSetPriority"),
+		},
+		"EvictionPolicyChanged": {
+			p:    v1alpha3.AKSClusterParameters{NodeScaleSetEvictionPolicy: to.StringPtr("Deallocate")},
+			mc:   spot,
+			want: errors.Errorf(errFmtImmutableField, "nodeScaleSetEvictionPolicy"),
+		},
+		"SpotMaxPriceChanged": {
+			p:    v1alpha3.AKSClusterParameters{NodeSpotMaxPrice: to.StringPtr("0.05")},
+			mc:   spot,
+			want: errors.Errorf(errFmtImmutableField, "nodeSpotMaxPrice"),
+		},
+		"InvalidSpotMaxPrice": {
+			p:    v1alpha3.AKSClusterParameters{NodeSpotMaxPrice: to.StringPtr("cheap")},
+			mc:   spot,
+			want: errors.Wrap(&strconv.NumError{Func: "ParseFloat", Num: "cheap", Err: strconv.ErrSyntax}, errParseSpotMaxPrice),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateImmutableFields(tc.p, tc.mc)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateImmutableFields(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ package fake
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-03-01/containerservice"
 
 	"github.com/crossplane/provider-azure/apis/compute/v1alpha3"
 )
@@ -28,6 +28,7 @@ import (
 type AKSClient struct {
 	MockGetManagedCluster    func(ctx context.Context, ac *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error)
 	MockEnsureManagedCluster func(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error
+	MockUpdateManagedCluster func(ctx context.Context, ac *v1alpha3.AKSCluster) error
 	MockDeleteManagedCluster func(ctx context.Context, ac *v1alpha3.AKSCluster) error
 	MockGetKubeConfig        func(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error)
 }
@@ -42,6 +43,11 @@ func (c AKSClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSClu
 	return c.MockEnsureManagedCluster(ctx, ac, secret)
 }
 
+// UpdateManagedCluster calls MockUpdateManagedCluster.
+func (c AKSClient) UpdateManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error {
+	return c.MockUpdateManagedCluster(ctx, ac)
+}
+
 // DeleteManagedCluster calls DeleteManagedCluster.
 func (c AKSClient) DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error {
 	return c.MockDeleteManagedCluster(ctx, ac)
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNoSecretNamespace  = "writeConnectionSecretToRef must specify a namespace"
+	errReviewSecretAccess = "cannot review access to connection secret"
+	errFmtSecretForbidden = "provider is not permitted to %s secrets in namespace %q; grant it RBAC access to write connection secrets there"
+	errFmtDuplicateKey    = "connectionSecretKeys renames both %q and %q to %q"
+	errFmtKeyClash        = "connectionSecretKeys renames %q to %q, which is the name of a connection secret key that is not renamed"
+)
+
+// ConnectionSecretReadyKey is published alongside the connection details of
+// managed resources that publish them as soon as they are known, rather than
+// once the resource is ready. It is "false" while only some connection details
+// may be known, and "true" once the resource is ready for use.
+const ConnectionSecretReadyKey = "ready"
+
+// WithReadiness returns the supplied connection details, indicating whether
+// the resource they connect to is ready for use.
+func WithReadiness(cd managed.ConnectionDetails, ready bool) managed.ConnectionDetails {
+	if cd == nil {
+		cd = managed.ConnectionDetails{}
+	}
+	cd[ConnectionSecretReadyKey] = []byte(strconv.FormatBool(ready))
+	return cd
+}
+
+// secretVerbs are the verbs a SecretPublisher needs in order to publish a
+// connection secret.
+var secretVerbs = []string{"get", "create", "update"}
+
+// A SecretPublisher publishes connection details to the namespace named by a
+// managed resource's writeConnectionSecretToRef, and only that namespace. It
+// never defaults the namespace, and verifies that the provider's RBAC allows it
+// to write secrets to the namespace before publishing.
+type SecretPublisher struct {
+	kube      client.Client
+	publisher managed.ConnectionPublisher
+
+	mu      sync.Mutex
+	allowed map[string]bool
+}
+
+// NewSecretPublisher returns a SecretPublisher that publishes connection
+// details using the supplied client.
+func NewSecretPublisher(c client.Client, ot runtime.ObjectTyper) *SecretPublisher {
+	return &SecretPublisher{
+		kube:      c,
+		publisher: managed.NewAPISecretPublisher(c, ot),
+		allowed:   map[string]bool{},
+	}
+}
+
+// PublishConnection publishes the supplied connection details to the secret
+// referenced by the supplied managed resource.
+func (p *SecretPublisher) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	ref := mg.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return nil
+	}
+	if ref.Namespace == "" {
+		return errors.New(errNoSecretNamespace)
+	}
+	if err := p.authorize(ctx, ref.Namespace); err != nil {
+		return err
+	}
+	err := p.publisher.PublishConnection(ctx, mg, c)
+	if kerrors.IsForbidden(err) {
+		// Our RBAC was revoked since we last reviewed it.
+		p.forget(ref.Namespace)
+		return errors.Wrapf(err, errFmtSecretForbidden, "write", ref.Namespace)
+	}
+	return err
+}
+
+// UnpublishConnection unpublishes the supplied connection details.
+func (p *SecretPublisher) UnpublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	return p.publisher.UnpublishConnection(ctx, mg, c)
+}
+
+// authorize returns an error if the provider may not write secrets to the
+// supplied namespace. Namespaces that were found to be writable are cached to
+// avoid reviewing our access every time a resource is reconciled.
+func (p *SecretPublisher) authorize(ctx context.Context, namespace string) error {
+	p.mu.Lock()
+	ok := p.allowed[namespace]
+	p.mu.Unlock()
+	if ok {
+		return nil
+	}
+	for _, verb := range secretVerbs {
+		r := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Resource:  "secrets",
+				},
+			},
+		}
+		if err := p.kube.Create(ctx, r); err != nil {
+			return errors.Wrap(err, errReviewSecretAccess)
+		}
+		if !r.Status.Allowed {
+			return errors.Errorf(errFmtSecretForbidden, verb, namespace)
+		}
+	}
+	p.mu.Lock()
+	p.allowed[namespace] = true
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *SecretPublisher) forget(namespace string) {
+	p.mu.Lock()
+	delete(p.allowed, namespace)
+	p.mu.Unlock()
+}
+
+// RenameConnectionDetails returns the supplied connection details with each
+// key that appears in the supplied map renamed to its corresponding value.
+// Keys that do not appear in the map, or that map to an empty string, are left
+// as is. It returns an error if two keys would be renamed to the same name, or
+// if a key would be renamed to the name of a key that is left as is, because
+// which of their values was published would then be arbitrary.
+func RenameConnectionDetails(cd managed.ConnectionDetails, keys map[string]string) (managed.ConnectionDetails, error) {
+	if len(keys) == 0 || cd == nil {
+		return cd, nil
+	}
+
+	// Keys are checked in order so that the same error is returned each time
+	// a resource is reconciled.
+	from := make([]string, 0, len(keys))
+	for k, to := range keys {
+		if to != "" {
+			from = append(from, k)
+		}
+	}
+	sort.Strings(from)
+	renamed := map[string]string{}
+	for _, k := range from {
+		to := keys[k]
+		if other, ok := renamed[to]; ok {
+			return nil, errors.Errorf(errFmtDuplicateKey, other, k, to)
+		}
+		renamed[to] = k
+		if _, ok := cd[to]; ok && keys[to] == "" {
+			return nil, errors.Errorf(errFmtKeyClash, k, to)
+		}
+	}
+
+	out := make(managed.ConnectionDetails, len(cd))
+	for k, v := range cd {
+		if to := keys[k]; to != "" {
+			k = to
+		}
+		out[k] = v
+	}
+	return out, nil
+}
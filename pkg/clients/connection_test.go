@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRenameConnectionDetails(t *testing.T) {
+	cd := managed.ConnectionDetails{
+		runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("host"),
+		runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("pw"),
+	}
+
+	type want struct {
+		cd  managed.ConnectionDetails
+		err error
+	}
+
+	cases := map[string]struct {
+		cd   managed.ConnectionDetails
+		keys map[string]string
+		want want
+	}{
+		"NilDetails": {
+			keys: map[string]string{runtimev1alpha1.ResourceCredentialsSecretEndpointKey: "REDIS_HOST"},
+			want: want{cd: nil},
+		},
+		"NoKeys": {
+			cd:   cd,
+			want: want{cd: cd},
+		},
+		"Renamed": {
+			cd: cd,
+			keys: map[string]string{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: "REDIS_HOST",
+				runtimev1alpha1.ResourceCredentialsSecretUserKey:     "REDIS_USER",
+				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: "",
+			},
+			want: want{cd: managed.ConnectionDetails{
+				"REDIS_HOST": []byte("host"),
+				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("pw"),
+			}},
+		},
+		"Swapped": {
+			cd: cd,
+			keys: map[string]string{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: runtimev1alpha1.ResourceCredentialsSecretPasswordKey,
+				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: runtimev1alpha1.ResourceCredentialsSecretEndpointKey,
+			},
+			want: want{cd: managed.ConnectionDetails{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("pw"),
+				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("host"),
+			}},
+		},
+		"DuplicateTarget": {
+			cd: cd,
+			keys: map[string]string{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: "REDIS",
+				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: "REDIS",
+			},
+			want: want{err: errors.Errorf(errFmtDuplicateKey, runtimev1alpha1.ResourceCredentialsSecretEndpointKey, runtimev1alpha1.ResourceCredentialsSecretPasswordKey, "REDIS")},
+		},
+		"ClashesWithUnmappedKey": {
+			cd: cd,
+			keys: map[string]string{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: runtimev1alpha1.ResourceCredentialsSecretPasswordKey,
+			},
+			want: want{err: errors.Errorf(errFmtKeyClash, runtimev1alpha1.ResourceCredentialsSecretEndpointKey, runtimev1alpha1.ResourceCredentialsSecretPasswordKey)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenameConnectionDetails(tc.cd, tc.keys)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("RenameConnectionDetails(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("RenameConnectionDetails(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithReadiness(t *testing.T) {
+	cases := map[string]struct {
+		cd    managed.ConnectionDetails
+		ready bool
+		want  managed.ConnectionDetails
+	}{
+		"NilDetails": {
+			want: managed.ConnectionDetails{ConnectionSecretReadyKey: []byte("false")},
+		},
+		"Ready": {
+			cd:    managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("host")},
+			ready: true,
+			want: managed.ConnectionDetails{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("host"),
+				ConnectionSecretReadyKey:                             []byte("true"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WithReadiness(tc.cd, tc.ready)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("WithReadiness(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSecretPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+	forbidden := kerrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "cool", errBoom)
+
+	withRef := func(ns string) resource.Managed {
+		return &fake.Managed{ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &runtimev1alpha1.SecretReference{Namespace: ns, Name: "cool"}}}
+	}
+	review := func(allowed bool) *test.MockClient {
+		return &test.MockClient{MockCreate: test.NewMockCreateFn(nil, func(obj runtime.Object) error {
+			obj.(*authorizationv1.SelfSubjectAccessReview).Status.Allowed = allowed
+			return nil
+		})}
+	}
+	publish := func(err error) managed.ConnectionPublisher {
+		return managed.ConnectionPublisherFns{PublishConnectionFn: func(_ context.Context, _ resource.Managed, _ managed.ConnectionDetails) error { return err }}
+	}
+
+	cases := map[string]struct {
+		kube      *test.MockClient
+		publisher managed.ConnectionPublisher
+		mg        resource.Managed
+		want      error
+	}{
+		"NoReference": {
+			mg: &fake.Managed{},
+		},
+		"NoNamespace": {
+			mg:   withRef(""),
+			want: errors.New(errNoSecretNamespace),
+		},
+		"ReviewFailed": {
+			kube: &test.MockClient{MockCreate: test.NewMockCreateFn(errBoom)},
+			mg:   withRef("cool-ns"),
+			want: errors.Wrap(errBoom, errReviewSecretAccess),
+		},
+		"NotAllowed": {
+			kube: review(false),
+			mg:   withRef("cool-ns"),
+			want: errors.Errorf(errFmtSecretForbidden, "get", "cool-ns"),
+		},
+		"PublishForbidden": {
+			kube:      review(true),
+			publisher: publish(forbidden),
+			mg:        withRef("cool-ns"),
+			want:      errors.Wrapf(forbidden, errFmtSecretForbidden, "write", "cool-ns"),
+		},
+		"Published": {
+			kube:      review(true),
+			publisher: publish(nil),
+			mg:        withRef("cool-ns"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &SecretPublisher{kube: tc.kube, publisher: tc.publisher, allowed: map[string]bool{}}
+			err := p.PublishConnection(context.Background(), tc.mg, managed.ConnectionDetails{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("PublishConnection(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// commonNames returns the common names of the certificates in the supplied
+// PEM encoded bundle.
+func commonNames(t *testing.T, rest []byte) []string {
+	t.Helper()
+	var got []string
+	for {
+		var b *pem.Block
+		b, rest = pem.Decode(rest)
+		if b == nil {
+			return got
+		}
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			t.Fatalf("x509.ParseCertificate(...): %s", err)
+		}
+		got = append(got, c.Subject.CommonName)
+	}
+}
+
+func TestCABundleFor(t *testing.T) {
+	cases := map[string]struct {
+		location string
+		want     []string
+	}{
+		"Public": {
+			location: "westus2",
+			want:     []string{"DigiCert Global Root G2"},
+		},
+		"Government": {
+			location: "usgovvirginia",
+			want:     []string{"DigiCert Global Root G2"},
+		},
+		"China": {
+			location: "China East 2",
+			want:     []string{"DigiCert Global Root CA"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := commonNames(t, DefaultCABundle().For(tc.location))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("For(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	rotated := filepath.Join(dir, "rotated.pem")
+	if err := ioutil.WriteFile(rotated, []byte(digiCertGlobalRootCA+digiCertGlobalRootG2), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile(...): %s", err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := ioutil.WriteFile(empty, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile(...): %s", err)
+	}
+
+	type want struct {
+		public []string
+		china  []string
+		err    bool
+	}
+
+	cases := map[string]struct {
+		public string
+		china  string
+		want   want
+	}{
+		"Defaults": {
+			want: want{
+				public: []string{"DigiCert Global Root G2"},
+				china:  []string{"DigiCert Global Root CA"},
+			},
+		},
+		"PublicOverridden": {
+			public: rotated,
+			want: want{
+				public: []string{"DigiCert Global Root CA", "DigiCert Global Root G2"},
+				china:  []string{"DigiCert Global Root CA"},
+			},
+		},
+		"ChinaOverridden": {
+			china: rotated,
+			want: want{
+				public: []string{"DigiCert Global Root G2"},
+				china:  []string{"DigiCert Global Root CA", "DigiCert Global Root G2"},
+			},
+		},
+		"MissingFile": {
+			public: filepath.Join(dir, "missing.pem"),
+			want:   want{err: true},
+		},
+		"NoCertificates": {
+			china: empty,
+			want:  want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := LoadCABundle(tc.public, tc.china)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("LoadCABundle(...): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.public, commonNames(t, b.Public)); diff != "" {
+				t.Errorf("LoadCABundle(...).Public: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.china, commonNames(t, b.China)); diff != "" {
+				t.Errorf("LoadCABundle(...).China: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
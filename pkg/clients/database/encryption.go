@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+)
+
+// infrastructureEncryptionDisabled is the infrastructure encryption of servers
+// for which Azure reports none, i.e. servers created before it was supported.
+const infrastructureEncryptionDisabled = "Disabled"
+
+const errFmtInfrastructureEncryptionChange = "cannot change infrastructureEncryption from %q to %q: it can only be set when a server is created"
+
+// ValidateInfrastructureEncryption returns an error if the desired
+// infrastructure encryption of the supplied SQL server parameters differs from
+// the supplied observed infrastructure encryption. Azure does not support
+// enabling or disabling infrastructure encryption on an existing server.
+func ValidateInfrastructureEncryption(p v1beta1.SQLServerParameters, observed string) error {
+	if InfrastructureEncryptionUpToDate(p, observed) {
+		return nil
+	}
+	return errors.Errorf(errFmtInfrastructureEncryptionChange, ObservedInfrastructureEncryption(observed), *p.InfrastructureEncryption)
+}
+
+// InfrastructureEncryptionUpToDate returns true if the supplied SQL server
+// parameters do not specify an infrastructure encryption, or specify the
+// supplied observed infrastructure encryption. Azure reports no
+// infrastructure encryption for some servers on which it is disabled.
+func InfrastructureEncryptionUpToDate(p v1beta1.SQLServerParameters, observed string) bool {
+	return p.InfrastructureEncryption == nil || *p.InfrastructureEncryption == ObservedInfrastructureEncryption(observed)
+}
+
+// ObservedInfrastructureEncryption returns the supplied infrastructure
+// encryption reported by Azure, or Disabled if Azure reported none.
+func ObservedInfrastructureEncryption(observed string) string {
+	if observed == "" {
+		return infrastructureEncryptionDisabled
+	}
+	return observed
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestValidateInfrastructureEncryption(t *testing.T) {
+	cases := map[string]struct {
+		p        v1beta1.SQLServerParameters
+		observed string
+		want     error
+	}{
+		"Unspecified": {
+			observed: "Enabled",
+		},
+		"NotReported": {
+			p:    v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			want: errors.Errorf(errFmtInfrastructureEncryptionChange, "Disabled", "Enabled"),
+		},
+		"Unchanged": {
+			p:        v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			observed: "Enabled",
+		},
+		"Changed": {
+			p:        v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			observed: "Disabled",
+			want:     errors.Errorf(errFmtInfrastructureEncryptionChange, "Disabled", "Enabled"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateInfrastructureEncryption(tc.p, tc.observed)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateInfrastructureEncryption(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInfrastructureEncryptionUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		p        v1beta1.SQLServerParameters
+		observed string
+		want     bool
+	}{
+		"Unspecified": {
+			observed: "Enabled",
+			want:     true,
+		},
+		"Unchanged": {
+			p:        v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			observed: "Enabled",
+			want:     true,
+		},
+		"DisabledNotReported": {
+			p:    v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Disabled")},
+			want: true,
+		},
+		"EnabledNotReported": {
+			p:    v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			want: false,
+		},
+		"Changed": {
+			p:        v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")},
+			observed: "Disabled",
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := InfrastructureEncryptionUpToDate(tc.p, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("InfrastructureEncryptionUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// Event reasons for SQL server operations. The managed resource reconciler
+// records events when it starts operations; these record when the long
+// running operations it started complete.
+const (
+	ReasonOperationCompleted event.Reason = "CompletedExternalOperation"
+	ReasonOperationFailed    event.Reason = "FailedExternalOperation"
+)
+
+// ReasonCannotListReplicas is the reason of the event recorded when the read
+// replicas of a SQL server cannot be listed.
+const ReasonCannotListReplicas event.Reason = "CannotListReadReplicas"
+
+// Azure long running operation statuses that indicate completion.
+const (
+	operationStatusSucceeded = "Succeeded"
+	operationStatusFailed    = "Failed"
+	operationStatusCanceled  = "Canceled"
+)
+
+// operationNames describes the kind of operation started using each HTTP
+// method.
+var operationNames = map[string]string{
+	http.MethodPut:    "creation",
+	http.MethodPatch:  "update",
+	http.MethodPost:   "restart",
+	http.MethodDelete: "deletion",
+}
+
+// OperationCompletedEvent returns an event describing the outcome of an
+// operation that was previously observed in progress (before) and has since
+// completed (after). It returns false if the operation has not completed
+// since it was last observed, in which case there is nothing to record.
+func OperationCompletedEvent(before, after v1alpha3.AsyncOperation) (event.Event, bool) {
+	if before.Status != azure.AsyncOperationStatusInProgress || after.Status == "" || after.Status == azure.AsyncOperationStatusInProgress {
+		return event.Event{}, false
+	}
+	name, ok := operationNames[after.Method]
+	if !ok {
+		name = "operation"
+	}
+	if after.ErrorMessage != "" || strings.EqualFold(after.Status, operationStatusFailed) || strings.EqualFold(after.Status, operationStatusCanceled) {
+		err := errors.Errorf("external resource %s did not succeed: status %s", name, after.Status)
+		if after.ErrorMessage != "" {
+			err = errors.Wrap(errors.New(after.ErrorMessage), err.Error())
+		}
+		return event.Warning(ReasonOperationFailed, err), true
+	}
+	return event.Normal(ReasonOperationCompleted, "Successfully completed "+name+" of external resource"), true
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestOperationCompletedEvent(t *testing.T) {
+	inProgress := v1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress}
+
+	type want struct {
+		e  event.Event
+		ok bool
+	}
+	cases := map[string]struct {
+		before v1alpha3.AsyncOperation
+		after  v1alpha3.AsyncOperation
+		want   want
+	}{
+		"NotPreviouslyInProgress": {
+			before: v1alpha3.AsyncOperation{Method: http.MethodPut, Status: "Succeeded"},
+			after:  v1alpha3.AsyncOperation{Method: http.MethodPut, Status: "Succeeded"},
+		},
+		"StillInProgress": {
+			before: inProgress,
+			after:  inProgress,
+		},
+		"CreateSucceeded": {
+			before: inProgress,
+			after:  v1alpha3.AsyncOperation{Method: http.MethodPut, Status: "Succeeded"},
+			want: want{
+				e:  event.Normal(ReasonOperationCompleted, "Successfully completed creation of external resource"),
+				ok: true,
+			},
+		},
+		"DeleteFailed": {
+			before: v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusInProgress},
+			after:  v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: "Failed", ErrorMessage: "boom"},
+			want: want{
+				e:  event.Warning(ReasonOperationFailed, errors.New("external resource deletion did not succeed: status Failed: boom")),
+				ok: true,
+			},
+		},
+		"RestartCanceled": {
+			before: v1alpha3.AsyncOperation{Method: http.MethodPost, Status: azure.AsyncOperationStatusInProgress},
+			after:  v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Canceled"},
+			want: want{
+				e:  event.Warning(ReasonOperationFailed, errors.New("external resource restart did not succeed: status Canceled")),
+				ok: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, ok := OperationCompletedEvent(tc.before, tc.after)
+			if diff := cmp.Diff(tc.want.e, e); diff != "" {
+				t.Errorf("OperationCompletedEvent(...): -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("OperationCompletedEvent(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
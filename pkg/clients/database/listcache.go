@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// A ListFn lists the children of a server, keyed by name.
+type ListFn func() (map[string]interface{}, error)
+
+type listEntry struct {
+	expires time.Time
+	items   map[string]interface{}
+}
+
+// A ListCache caches the children (e.g. virtual network rules) of servers.
+// Caching allows the many children of a server to be observed using one list
+// call, rather than one get call per child.
+type ListCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mx      sync.Mutex
+	entries map[string]listEntry
+}
+
+// NewListCache returns a ListCache that caches lists for the supplied TTL.
+func NewListCache(ttl time.Duration) *ListCache {
+	return &ListCache{ttl: ttl, now: time.Now, entries: map[string]listEntry{}}
+}
+
+// ServerKey returns the key of the supplied server within the supplied
+// subscription.
+func ServerKey(subscriptionID, resourceGroup, server string) string {
+	return strings.ToLower(strings.Join([]string{subscriptionID, resourceGroup, server}, "/"))
+}
+
+// Get returns the child with the supplied name of the server with the
+// supplied key, and whether it exists. The server's children are listed using
+// the supplied function unless they are cached and have not expired.
+func (c *ListCache) Get(key, name string, list ListFn) (interface{}, bool, error) {
+	c.mx.Lock()
+	e, ok := c.entries[key]
+	c.mx.Unlock()
+
+	if !ok || !c.now().Before(e.expires) {
+		items, err := list()
+		if err != nil {
+			return nil, false, err
+		}
+		e = listEntry{expires: c.now().Add(c.ttl), items: map[string]interface{}{}}
+		for n, i := range items {
+			e.items[strings.ToLower(n)] = i
+		}
+		c.mx.Lock()
+		c.evict()
+		c.entries[key] = e
+		c.mx.Unlock()
+	}
+
+	i, ok := e.items[strings.ToLower(name)]
+	return i, ok, nil
+}
+
+// evict the cached children of any server that have expired, so that servers
+// that are no longer observed are not cached indefinitely. It must be called
+// with the mutex held.
+func (c *ListCache) evict() {
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Invalidate the cached children of the server with the supplied key, for
+// example because one of them was changed.
+func (c *ListCache) Invalidate(key string) {
+	c.mx.Lock()
+	delete(c.entries, key)
+	c.mx.Unlock()
+}
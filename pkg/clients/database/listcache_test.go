@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestListCache(t *testing.T) {
+	errBoom := errors.New("boom")
+	key := ServerKey("sub", "rg", "server")
+	now := time.Now()
+
+	type call struct {
+		name       string
+		advance    time.Duration
+		invalidate bool
+	}
+	type want struct {
+		Item   interface{}
+		Exists bool
+		Err    error
+		Lists  int
+	}
+
+	cases := map[string]struct {
+		reason string
+		items  map[string]interface{}
+		err    error
+		calls  []call
+		want   want
+	}{
+		"Cached": {
+			reason: "Repeated gets within the TTL should list once.",
+			items:  map[string]interface{}{"Rule": 1},
+			calls:  []call{{name: "rule"}, {name: "rule", advance: 30 * time.Second}},
+			want:   want{Item: 1, Exists: true, Lists: 1},
+		},
+		"Expired": {
+			reason: "A get after the TTL has elapsed should list again.",
+			items:  map[string]interface{}{"rule": 1},
+			calls:  []call{{name: "rule"}, {name: "rule", advance: 2 * time.Minute}},
+			want:   want{Item: 1, Exists: true, Lists: 2},
+		},
+		"Invalidated": {
+			reason: "A get after the server is invalidated should list again.",
+			items:  map[string]interface{}{"rule": 1},
+			calls:  []call{{name: "rule"}, {name: "rule", invalidate: true}},
+			want:   want{Item: 1, Exists: true, Lists: 2},
+		},
+		"NotFound": {
+			reason: "A get for a child that was not listed should report that it does not exist.",
+			items:  map[string]interface{}{"other": 1},
+			calls:  []call{{name: "rule"}},
+			want:   want{Lists: 1},
+		},
+		"ListError": {
+			reason: "Errors listing children should be returned.",
+			err:    errBoom,
+			calls:  []call{{name: "rule"}},
+			want:   want{Err: errBoom, Lists: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewListCache(time.Minute)
+			elapsed := time.Duration(0)
+			c.now = func() time.Time { return now.Add(elapsed) }
+
+			lists := 0
+			list := func() (map[string]interface{}, error) {
+				lists++
+				return tc.items, tc.err
+			}
+
+			var got want
+			for _, call := range tc.calls {
+				elapsed += call.advance
+				if call.invalidate {
+					c.Invalidate(key)
+				}
+				got.Item, got.Exists, got.Err = c.Get(key, call.name, list)
+			}
+			got.Lists = lists
+
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestListCacheEvictsExpired(t *testing.T) {
+	now := time.Now()
+	elapsed := time.Duration(0)
+	c := NewListCache(time.Minute)
+	c.now = func() time.Time { return now.Add(elapsed) }
+	list := func() (map[string]interface{}, error) { return map[string]interface{}{"rule": 1}, nil }
+
+	stale := ServerKey("sub", "rg", "deleted")
+	if _, _, err := c.Get(stale, "rule", list); err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+
+	elapsed = 2 * time.Minute
+	fresh := ServerKey("sub", "rg", "server")
+	if _, _, err := c.Get(fresh, "rule", list); err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+
+	want := []string{fresh}
+	got := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		got = append(got, k)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get(...): expired servers should be evicted when another server is listed: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtReservedLogin       = "administratorLogin %q is reserved by Azure"
+	errFmtReservedLoginPrefix = "administratorLogin %q must not begin with %q"
+)
+
+// mysqlReservedLogins are MySQL administrator login names that Azure rejects.
+var mysqlReservedLogins = map[string]bool{
+	"admin":           true,
+	"administrator":   true,
+	"azure_superuser": true,
+	"guest":           true,
+	"public":          true,
+	"root":            true,
+	"sa":              true,
+}
+
+// postgresqlReservedLogins are PostgreSQL administrator login names that Azure
+// rejects.
+var postgresqlReservedLogins = map[string]bool{
+	"admin":           true,
+	"administrator":   true,
+	"azure_pg_admin":  true,
+	"azure_superuser": true,
+	"guest":           true,
+	"public":          true,
+	"root":            true,
+	"sa":              true,
+}
+
+// postgresqlReservedLoginPrefix may not begin a PostgreSQL administrator login
+// name.
+const postgresqlReservedLoginPrefix = "pg_"
+
+// ValidateMySQLAdministratorLogin returns an error if Azure would reject the
+// supplied MySQL administrator login name because it is reserved. The length
+// and character set of the name are validated by the API server.
+func ValidateMySQLAdministratorLogin(login string) error {
+	if mysqlReservedLogins[strings.ToLower(login)] {
+		return errors.Errorf(errFmtReservedLogin, login)
+	}
+	return nil
+}
+
+// ValidatePostgreSQLAdministratorLogin returns an error if Azure would reject
+// the supplied PostgreSQL administrator login name because it is reserved. The
+// length and character set of the name are validated by the API server.
+func ValidatePostgreSQLAdministratorLogin(login string) error {
+	l := strings.ToLower(login)
+	if postgresqlReservedLogins[l] {
+		return errors.Errorf(errFmtReservedLogin, login)
+	}
+	if strings.HasPrefix(l, postgresqlReservedLoginPrefix) {
+		return errors.Errorf(errFmtReservedLoginPrefix, login, postgresqlReservedLoginPrefix)
+	}
+	return nil
+}
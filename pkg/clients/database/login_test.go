@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestValidateMySQLAdministratorLogin(t *testing.T) {
+	cases := map[string]struct {
+		login string
+		want  error
+	}{
+		"Valid": {
+			login: "crossplane",
+		},
+		"Reserved": {
+			login: "Admin",
+			want:  errors.Errorf(errFmtReservedLogin, "Admin"),
+		},
+		"ReservedByPostgreSQL": {
+			login: "azure_pg_admin",
+		},
+		"PostgreSQLReservedPrefix": {
+			login: "pg_crossplane",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateMySQLAdministratorLogin(tc.login)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateMySQLAdministratorLogin(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidatePostgreSQLAdministratorLogin(t *testing.T) {
+	cases := map[string]struct {
+		login string
+		want  error
+	}{
+		"Valid": {
+			login: "crossplane",
+		},
+		"Reserved": {
+			login: "Admin",
+			want:  errors.Errorf(errFmtReservedLogin, "Admin"),
+		},
+		"ReservedByPostgreSQL": {
+			login: "azure_pg_admin",
+			want:  errors.Errorf(errFmtReservedLogin, "azure_pg_admin"),
+		},
+		"ReservedPrefix": {
+			login: "pg_crossplane",
+			want:  errors.Errorf(errFmtReservedLoginPrefix, "pg_crossplane", postgresqlReservedLoginPrefix),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidatePostgreSQLAdministratorLogin(tc.login)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidatePostgreSQLAdministratorLogin(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -56,6 +56,9 @@ type MySQLServerAPI interface {
 	CreateServer(ctx context.Context, s *azuredbv1beta1.MySQLServer, adminPassword string) error
 	UpdateServer(ctx context.Context, s *azuredbv1beta1.MySQLServer) error
 	DeleteServer(ctx context.Context, s *azuredbv1beta1.MySQLServer) error
+	RestartServer(ctx context.Context, s *azuredbv1beta1.MySQLServer) error
+	UpgradeServer(ctx context.Context, s *azuredbv1beta1.MySQLServer) error
+	ListReplicas(ctx context.Context, s *azuredbv1beta1.MySQLServer) (mysql.ServerListResult, error)
 	GetRESTClient() autorest.Sender
 }
 
@@ -63,12 +66,16 @@ type MySQLServerAPI interface {
 // interface for MySQL that calls Azure API.
 type MySQLServerClient struct {
 	mysql.ServersClient
+	replicas mysql.ReplicasClient
+	ignored  azure.IgnoredTags
 }
 
 // NewMySQLServerClient creates and initializes a MySQLServerClient instance.
-func NewMySQLServerClient(cl mysql.ServersClient) *MySQLServerClient {
+func NewMySQLServerClient(cl mysql.ServersClient, ignored azure.IgnoredTags) *MySQLServerClient {
 	return &MySQLServerClient{
 		ServersClient: cl,
+		replicas:      mysql.ReplicasClient{BaseClient: cl.BaseClient},
+		ignored:       ignored,
 	}
 }
 
@@ -77,6 +84,11 @@ func (c *MySQLServerClient) GetRESTClient() autorest.Sender {
 	return c.ServersClient.Client
 }
 
+// ListReplicas lists the read replicas of the supplied MySQL Server.
+func (c *MySQLServerClient) ListReplicas(ctx context.Context, cr *azuredbv1beta1.MySQLServer) (mysql.ServerListResult, error) {
+	return c.replicas.ListByServer(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+}
+
 // GetServer retrieves the requested MySQL Server
 func (c *MySQLServerClient) GetServer(ctx context.Context, cr *azuredbv1beta1.MySQLServer) (mysql.Server, error) {
 	return c.ServersClient.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
@@ -90,6 +102,8 @@ func (c *MySQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1beta1
 		AdministratorLoginPassword: &adminPassword,
 		Version:                    mysql.ServerVersion(s.Version),
 		SslEnforcement:             mysql.SslEnforcementEnum(s.SSLEnforcement),
+		MinimalTLSVersion:          mysql.MinimalTLSVersionEnum(azure.ToString(s.MinimalTLSVersion)),
+		InfrastructureEncryption:   mysql.InfrastructureEncryption(azure.ToString(s.InfrastructureEncryption)),
 		CreateMode:                 mysql.CreateModeDefault,
 		StorageProfile: &mysql.StorageProfile{
 			BackupRetentionDays: azure.ToInt32PtrFromIntPtr(s.StorageProfile.BackupRetentionDays),
@@ -98,6 +112,9 @@ func (c *MySQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1beta1
 			StorageAutogrow:     mysql.StorageAutogrow(azure.ToString(s.StorageProfile.StorageAutogrow)),
 		},
 	}
+	if err := ValidateMinimalTLSVersion(s); err != nil {
+		return err
+	}
 	sku, err := ToMySQLSKU(s.SKU)
 	if err != nil {
 		return err
@@ -106,7 +123,7 @@ func (c *MySQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1beta1
 		Sku:        sku,
 		Properties: properties,
 		Location:   &s.Location,
-		Tags:       azure.ToStringPtrMap(s.Tags),
+		Tags:       azure.WithOwnershipTags(azure.ToStringPtrMap(s.Tags), azuredbv1beta1.MySQLServerKind, cr),
 	}
 	op, err := c.Create(ctx, s.ResourceGroupName, meta.GetExternalName(cr), createParams)
 	if err != nil {
@@ -125,8 +142,9 @@ func (c *MySQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1beta1
 	// we don't support that.
 	s := cr.Spec.ForProvider
 	properties := &mysql.ServerUpdateParametersProperties{
-		Version:        mysql.ServerVersion(s.Version),
-		SslEnforcement: mysql.SslEnforcementEnum(s.SSLEnforcement),
+		Version:           mysql.ServerVersion(s.Version),
+		SslEnforcement:    mysql.SslEnforcementEnum(s.SSLEnforcement),
+		MinimalTLSVersion: mysql.MinimalTLSVersionEnum(azure.ToString(s.MinimalTLSVersion)),
 		StorageProfile: &mysql.StorageProfile{
 			BackupRetentionDays: azure.ToInt32PtrFromIntPtr(s.StorageProfile.BackupRetentionDays),
 			GeoRedundantBackup:  mysql.GeoRedundantBackup(azure.ToString(s.StorageProfile.GeoRedundantBackup)),
@@ -134,14 +152,23 @@ func (c *MySQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1beta1
 			StorageAutogrow:     mysql.StorageAutogrow(azure.ToString(s.StorageProfile.StorageAutogrow)),
 		},
 	}
+	if err := ValidateMinimalTLSVersion(s); err != nil {
+		return err
+	}
 	sku, err := ToMySQLSKU(s.SKU)
 	if err != nil {
 		return err
 	}
+	// Azure replaces all tags when a server is updated, so we must send
+	// any tags that were injected by Azure in order to preserve them.
+	current, err := c.ServersClient.Get(ctx, s.ResourceGroupName, meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
 	updateParams := mysql.ServerUpdateParameters{
 		Sku:                              sku,
 		ServerUpdateParametersProperties: properties,
-		Tags:                             azure.ToStringPtrMap(s.Tags),
+		Tags:                             c.ignored.Preserve(azure.ToStringPtrMap(s.Tags), current.Tags),
 	}
 	op, err := c.Update(ctx, s.ResourceGroupName, meta.GetExternalName(cr), updateParams)
 	if err != nil {
@@ -167,6 +194,29 @@ func (c *MySQLServerClient) DeleteServer(ctx context.Context, cr *azuredbv1beta1
 	return nil
 }
 
+// RestartServer restarts a MySQL Server.
+func (c *MySQLServerClient) RestartServer(ctx context.Context, cr *azuredbv1beta1.MySQLServer) error {
+	op, err := c.ServersClient.Restart(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
+		PollingURL: op.PollingURL(),
+		Method:     http.MethodPost,
+	}
+	return nil
+}
+
+// UpgradeServer upgrades a MySQL Server in place to its desired version.
+func (c *MySQLServerClient) UpgradeServer(ctx context.Context, cr *azuredbv1beta1.MySQLServer) error {
+	op, err := upgradeServer(ctx, c.ServersClient.Client, c.BaseURI, c.SubscriptionID, providerMySQL, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), cr.Spec.ForProvider.Version)
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.LastOperation = op
+	return nil
+}
+
 // NewMySQLVirtualNetworkRuleParameters returns an Azure VirtualNetworkRule object from a virtual network spec
 func NewMySQLVirtualNetworkRuleParameters(v *azuredbv1alpha3.MySQLServerVirtualNetworkRule) mysql.VirtualNetworkRule {
 	return mysql.VirtualNetworkRule{
@@ -243,17 +293,22 @@ func UpdateMySQLObservation(o *azuredbv1beta1.SQLServerObservation, in mysql.Ser
 	o.Name = azure.ToString(in.Name)
 	o.Type = azure.ToString(in.Type)
 	o.UserVisibleState = string(in.UserVisibleState)
+	o.Version = string(in.Version)
 	o.FullyQualifiedDomainName = azure.ToString(in.FullyQualifiedDomainName)
 	o.MasterServerID = azure.ToString(in.MasterServerID)
+	o.InfrastructureEncryption = ObservedInfrastructureEncryption(string(in.InfrastructureEncryption))
 }
 
 // LateInitializeMySQL fills the empty values of SQLServerParameters with the
-// ones that are retrieved from the Azure API.
-func LateInitializeMySQL(p *azuredbv1beta1.SQLServerParameters, in mysql.Server) {
+// ones that are retrieved from the Azure API, disregarding the supplied ignored
+// tags.
+func LateInitializeMySQL(p *azuredbv1beta1.SQLServerParameters, in mysql.Server, ignored azure.IgnoredTags) {
 	if in.Sku != nil {
 		p.SKU.Size = azure.LateInitializeStringPtrFromPtr(p.SKU.Size, in.Sku.Size)
 	}
-	p.Tags = azure.LateInitializeStringMap(p.Tags, in.Tags)
+	p.Tags = azure.LateInitializeStringMap(p.Tags, azure.ToStringPtrMap(ignored.Filter(azure.ToStringMap(in.Tags))))
+	p.MinimalTLSVersion = azure.LateInitializeStringPtrFromVal(p.MinimalTLSVersion, string(in.MinimalTLSVersion))
+	p.InfrastructureEncryption = azure.LateInitializeStringPtrFromVal(p.InfrastructureEncryption, ObservedInfrastructureEncryption(string(in.InfrastructureEncryption)))
 	if in.StorageProfile != nil {
 		p.StorageProfile.BackupRetentionDays = azure.LateInitializeIntPtrFromInt32Ptr(p.StorageProfile.BackupRetentionDays, in.StorageProfile.BackupRetentionDays)
 		p.StorageProfile.GeoRedundantBackup = azure.LateInitializeStringPtrFromVal(p.StorageProfile.GeoRedundantBackup, string(in.StorageProfile.GeoRedundantBackup))
@@ -263,16 +318,20 @@ func LateInitializeMySQL(p *azuredbv1beta1.SQLServerParameters, in mysql.Server)
 
 // IsMySQLUpToDate is used to report whether given mysql.Server is in
 // sync with the SQLServerParameters that user desires.
-func IsMySQLUpToDate(p azuredbv1beta1.SQLServerParameters, in mysql.Server) bool { // nolint:gocyclo
+func IsMySQLUpToDate(p azuredbv1beta1.SQLServerParameters, in mysql.Server, ignored azure.IgnoredTags) bool { // nolint:gocyclo
 	if in.StorageProfile == nil || in.Sku == nil {
 		return false
 	}
 	switch {
 	case p.SSLEnforcement != string(in.SslEnforcement):
 		return false
+	case p.MinimalTLSVersion != nil && *p.MinimalTLSVersion != string(in.MinimalTLSVersion):
+		return false
+	case !InfrastructureEncryptionUpToDate(p, string(in.InfrastructureEncryption)):
+		return false
 	case p.Version != string(in.Version):
 		return false
-	case !reflect.DeepEqual(azure.ToStringPtrMap(p.Tags), in.Tags):
+	case ignored.NeedUpdate(azure.ToStringPtrMap(p.Tags), in.Tags):
 		return false
 	case p.SKU.Tier != string(in.Sku.Tier):
 		return false
@@ -27,6 +27,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 )
 
@@ -357,3 +358,48 @@ func TestMySQLServerFirewallRuleIsUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestIsMySQLUpToDateTags(t *testing.T) {
+	cases := map[string]struct {
+		spec     map[string]string
+		observed map[string]*string
+		want     bool
+	}{
+		"InSync": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("db")},
+			want:     true,
+		},
+		"SystemTagsInjected": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("db"), "hidden-link": to.StringPtr("x")},
+			want:     true,
+		},
+		"TagChanged": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := v1beta1.SQLServerParameters{Tags: tc.spec}
+			in := mysql.Server{Sku: &mysql.Sku{}, Tags: tc.observed, ServerProperties: &mysql.ServerProperties{StorageProfile: &mysql.StorageProfile{}}}
+			got := IsMySQLUpToDate(p, in, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsMySQLUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeMySQLTags(t *testing.T) {
+	p := v1beta1.SQLServerParameters{}
+	LateInitializeMySQL(&p, mysql.Server{
+		Tags:             map[string]*string{"cost-center": to.StringPtr("db"), "hidden-link": to.StringPtr("x")},
+		ServerProperties: &mysql.ServerProperties{},
+	}, azure.DefaultIgnoredTagPrefixes)
+	if diff := cmp.Diff(map[string]string{"cost-center": "db"}, p.Tags); diff != "" {
+		t.Errorf("LateInitializeMySQL(...): -want, +got\n%s", diff)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
@@ -48,6 +49,8 @@ type PostgreSQLServerAPI interface {
 	GetServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) (postgresql.Server, error)
 	CreateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer, adminPassword string) error
 	DeleteServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) error
+	RestartServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) error
+	ListReplicas(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) (postgresql.ServerListResult, error)
 	UpdateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) error
 	GetRESTClient() autorest.Sender
 }
@@ -55,12 +58,16 @@ type PostgreSQLServerAPI interface {
 // PostgreSQLServerClient is the concreate implementation of the SQLServerAPI interface for PostgreSQL that calls Azure API.
 type PostgreSQLServerClient struct {
 	postgresql.ServersClient
+	replicas postgresql.ReplicasClient
+	ignored  azure.IgnoredTags
 }
 
 // NewPostgreSQLServerClient creates and initializes a PostgreSQLServerClient instance.
-func NewPostgreSQLServerClient(cl postgresql.ServersClient) *PostgreSQLServerClient {
+func NewPostgreSQLServerClient(cl postgresql.ServersClient, ignored azure.IgnoredTags) *PostgreSQLServerClient {
 	return &PostgreSQLServerClient{
 		ServersClient: cl,
+		replicas:      postgresql.ReplicasClient{BaseClient: cl.BaseClient},
+		ignored:       ignored,
 	}
 }
 
@@ -69,6 +76,11 @@ func (c *PostgreSQLServerClient) GetRESTClient() autorest.Sender {
 	return c.ServersClient.Client
 }
 
+// ListReplicas lists the read replicas of the supplied PostgreSQL Server.
+func (c *PostgreSQLServerClient) ListReplicas(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer) (postgresql.ServerListResult, error) {
+	return c.replicas.ListByServer(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+}
+
 // GetServer retrieves the requested PostgreSQL Server
 func (c *PostgreSQLServerClient) GetServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer) (postgresql.Server, error) {
 	return c.ServersClient.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
@@ -82,6 +94,8 @@ func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1
 		AdministratorLoginPassword: &adminPassword,
 		Version:                    postgresql.ServerVersion(s.Version),
 		SslEnforcement:             postgresql.SslEnforcementEnum(s.SSLEnforcement),
+		MinimalTLSVersion:          postgresql.MinimalTLSVersionEnum(azure.ToString(s.MinimalTLSVersion)),
+		InfrastructureEncryption:   postgresql.InfrastructureEncryption(azure.ToString(s.InfrastructureEncryption)),
 		CreateMode:                 postgresql.CreateModeDefault,
 		StorageProfile: &postgresql.StorageProfile{
 			BackupRetentionDays: azure.ToInt32PtrFromIntPtr(s.StorageProfile.BackupRetentionDays),
@@ -90,6 +104,9 @@ func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1
 			StorageAutogrow:     postgresql.StorageAutogrow(azure.ToString(s.StorageProfile.StorageAutogrow)),
 		},
 	}
+	if err := ValidateMinimalTLSVersion(s); err != nil {
+		return err
+	}
 	sku, err := ToPostgreSQLSKU(s.SKU)
 	if err != nil {
 		return err
@@ -98,7 +115,7 @@ func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1
 		Sku:        sku,
 		Properties: properties,
 		Location:   &s.Location,
-		Tags:       azure.ToStringPtrMap(s.Tags),
+		Tags:       azure.WithOwnershipTags(azure.ToStringPtrMap(s.Tags), azuredbv1beta1.PostgreSQLServerKind, cr),
 	}
 	op, err := c.Create(ctx, s.ResourceGroupName, meta.GetExternalName(cr), createParams)
 	if err != nil {
@@ -117,8 +134,9 @@ func (c *PostgreSQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1
 	// we don't support that.
 	s := cr.Spec.ForProvider
 	properties := &postgresql.ServerUpdateParametersProperties{
-		Version:        postgresql.ServerVersion(s.Version),
-		SslEnforcement: postgresql.SslEnforcementEnum(s.SSLEnforcement),
+		Version:           postgresql.ServerVersion(s.Version),
+		SslEnforcement:    postgresql.SslEnforcementEnum(s.SSLEnforcement),
+		MinimalTLSVersion: postgresql.MinimalTLSVersionEnum(azure.ToString(s.MinimalTLSVersion)),
 		StorageProfile: &postgresql.StorageProfile{
 			BackupRetentionDays: azure.ToInt32PtrFromIntPtr(s.StorageProfile.BackupRetentionDays),
 			GeoRedundantBackup:  postgresql.GeoRedundantBackup(azure.ToString(s.StorageProfile.GeoRedundantBackup)),
@@ -126,14 +144,23 @@ func (c *PostgreSQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1
 			StorageAutogrow:     postgresql.StorageAutogrow(azure.ToString(s.StorageProfile.StorageAutogrow)),
 		},
 	}
+	if err := ValidateMinimalTLSVersion(s); err != nil {
+		return err
+	}
 	sku, err := ToPostgreSQLSKU(s.SKU)
 	if err != nil {
 		return err
 	}
+	// Azure replaces all tags when a server is updated, so we must send
+	// any tags that were injected by Azure in order to preserve them.
+	current, err := c.ServersClient.Get(ctx, s.ResourceGroupName, meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
 	updateParams := postgresql.ServerUpdateParameters{
 		Sku:                              sku,
 		ServerUpdateParametersProperties: properties,
-		Tags:                             azure.ToStringPtrMap(s.Tags),
+		Tags:                             c.ignored.Preserve(azure.ToStringPtrMap(s.Tags), current.Tags),
 	}
 	op, err := c.Update(ctx, s.ResourceGroupName, meta.GetExternalName(cr), updateParams)
 	if err != nil {
@@ -159,6 +186,19 @@ func (c *PostgreSQLServerClient) DeleteServer(ctx context.Context, cr *azuredbv1
 	return nil
 }
 
+// RestartServer restarts a PostgreSQL Server.
+func (c *PostgreSQLServerClient) RestartServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer) error {
+	op, err := c.ServersClient.Restart(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
+		PollingURL: op.PollingURL(),
+		Method:     http.MethodPost,
+	}
+	return nil
+}
+
 // NewPostgreSQLVirtualNetworkRuleParameters returns an Azure VirtualNetworkRule object from a virtual network spec
 func NewPostgreSQLVirtualNetworkRuleParameters(v *azuredbv1alpha3.PostgreSQLServerVirtualNetworkRule) postgresql.VirtualNetworkRule {
 	return postgresql.VirtualNetworkRule{
@@ -211,6 +251,89 @@ func PostgreSQLServerFirewallRuleIsUpToDate(kube *azuredbv1alpha3.PostgreSQLServ
 	return cmp.Equal(up.FirewallRuleProperties, az.FirewallRuleProperties)
 }
 
+// Sources of a server configuration parameter's value.
+const (
+	ConfigurationSourceSystemDefault = "system-default"
+	ConfigurationSourceUserOverride  = "user-override"
+)
+
+// PostgreSQLRestartRequiredParameters are the PostgreSQL server configuration
+// parameters that are known to take effect only once the server is restarted.
+var PostgreSQLRestartRequiredParameters = []string{
+	"azure.extensions",
+	"azure.replication_support",
+	"shared_preload_libraries",
+}
+
+// PostgreSQLConfigurationRequiresRestart returns true if the server must be
+// restarted for changes to the supplied configuration parameter to take
+// effect.
+func PostgreSQLConfigurationRequiresRestart(p azuredbv1alpha3.SQLServerConfigurationParameters) bool {
+	if p.RestartServer != nil {
+		return *p.RestartServer
+	}
+	for _, n := range PostgreSQLRestartRequiredParameters {
+		if strings.EqualFold(p.Name, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPostgreSQLConfigurationParameters returns an Azure Configuration object
+// from a configuration spec.
+func NewPostgreSQLConfigurationParameters(cr *azuredbv1alpha3.PostgreSQLServerConfiguration) postgresql.Configuration {
+	return postgresql.Configuration{
+		ConfigurationProperties: &postgresql.ConfigurationProperties{
+			Value:  azure.ToStringPtr(cr.Spec.ForProvider.Value, azure.FieldRequired),
+			Source: azure.ToStringPtr(ConfigurationSourceUserOverride),
+		},
+	}
+}
+
+// NewPostgreSQLConfigurationResetParameters returns an Azure Configuration
+// object that resets the supplied Configuration to its default value.
+func NewPostgreSQLConfigurationResetParameters(az postgresql.Configuration) postgresql.Configuration {
+	p := postgresql.Configuration{
+		ConfigurationProperties: &postgresql.ConfigurationProperties{
+			Source: azure.ToStringPtr(ConfigurationSourceSystemDefault),
+		},
+	}
+	if az.ConfigurationProperties != nil {
+		p.Value = az.DefaultValue
+	}
+	return p
+}
+
+// UpdatePostgreSQLConfigurationObservation updates the supplied observation
+// with the supplied Azure Configuration.
+func UpdatePostgreSQLConfigurationObservation(o *azuredbv1alpha3.SQLServerConfigurationObservation, az postgresql.Configuration) {
+	o.ID = azure.ToString(az.ID)
+	o.Type = azure.ToString(az.Type)
+	if az.ConfigurationProperties == nil {
+		return
+	}
+	o.Description = azure.ToString(az.Description)
+	o.DefaultValue = azure.ToString(az.DefaultValue)
+	o.DataType = azure.ToString(az.DataType)
+	o.AllowedValues = azure.ToString(az.AllowedValues)
+	o.Source = azure.ToString(az.Source)
+}
+
+// PostgreSQLConfigurationIsOverridden returns true if the supplied
+// Configuration has been set to something other than its default value, and
+// thus should be considered to exist.
+func PostgreSQLConfigurationIsOverridden(az postgresql.Configuration) bool {
+	return az.ConfigurationProperties != nil && !strings.EqualFold(azure.ToString(az.Source), ConfigurationSourceSystemDefault)
+}
+
+// PostgreSQLServerConfigurationIsUpToDate returns true if the supplied
+// Configuration appears to be up to date with the supplied
+// PostgreSQLServerConfiguration.
+func PostgreSQLServerConfigurationIsUpToDate(cr *azuredbv1alpha3.PostgreSQLServerConfiguration, az postgresql.Configuration) bool {
+	return az.ConfigurationProperties != nil && cr.Spec.ForProvider.Value == azure.ToString(az.Value)
+}
+
 // The name must match the specification of the SKU, so, we don't allow user
 // to specify an arbitrary name. The format is tier + family + cores, e.g. B_Gen4_1, GP_Gen5_8.
 
@@ -235,17 +358,22 @@ func UpdatePostgreSQLObservation(o *azuredbv1beta1.SQLServerObservation, in post
 	o.Name = azure.ToString(in.Name)
 	o.Type = azure.ToString(in.Type)
 	o.UserVisibleState = string(in.UserVisibleState)
+	o.Version = string(in.Version)
 	o.FullyQualifiedDomainName = azure.ToString(in.FullyQualifiedDomainName)
 	o.MasterServerID = azure.ToString(in.MasterServerID)
+	o.InfrastructureEncryption = ObservedInfrastructureEncryption(string(in.InfrastructureEncryption))
 }
 
 // LateInitializePostgreSQL fills the empty values of SQLServerParameters with the
-// ones that are retrieved from the Azure API.
-func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgresql.Server) {
+// ones that are retrieved from the Azure API, disregarding the supplied ignored
+// tags.
+func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgresql.Server, ignored azure.IgnoredTags) {
 	if in.Sku != nil {
 		p.SKU.Size = azure.LateInitializeStringPtrFromPtr(p.SKU.Size, in.Sku.Size)
 	}
-	p.Tags = azure.LateInitializeStringMap(p.Tags, in.Tags)
+	p.Tags = azure.LateInitializeStringMap(p.Tags, azure.ToStringPtrMap(ignored.Filter(azure.ToStringMap(in.Tags))))
+	p.MinimalTLSVersion = azure.LateInitializeStringPtrFromVal(p.MinimalTLSVersion, string(in.MinimalTLSVersion))
+	p.InfrastructureEncryption = azure.LateInitializeStringPtrFromVal(p.InfrastructureEncryption, ObservedInfrastructureEncryption(string(in.InfrastructureEncryption)))
 	if in.StorageProfile != nil {
 		p.StorageProfile.BackupRetentionDays = azure.LateInitializeIntPtrFromInt32Ptr(p.StorageProfile.BackupRetentionDays, in.StorageProfile.BackupRetentionDays)
 		p.StorageProfile.GeoRedundantBackup = azure.LateInitializeStringPtrFromVal(p.StorageProfile.GeoRedundantBackup, string(in.StorageProfile.GeoRedundantBackup))
@@ -255,16 +383,20 @@ func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgres
 
 // IsPostgreSQLUpToDate is used to report whether given postgresql.Server is in
 // sync with the SQLServerParameters that user desires.
-func IsPostgreSQLUpToDate(p azuredbv1beta1.SQLServerParameters, in postgresql.Server) bool { // nolint:gocyclo
+func IsPostgreSQLUpToDate(p azuredbv1beta1.SQLServerParameters, in postgresql.Server, ignored azure.IgnoredTags) bool { // nolint:gocyclo
 	if in.StorageProfile == nil || in.Sku == nil {
 		return false
 	}
 	switch {
 	case p.SSLEnforcement != string(in.SslEnforcement):
 		return false
+	case p.MinimalTLSVersion != nil && *p.MinimalTLSVersion != string(in.MinimalTLSVersion):
+		return false
+	case !InfrastructureEncryptionUpToDate(p, string(in.InfrastructureEncryption)):
+		return false
 	case p.Version != string(in.Version):
 		return false
-	case !reflect.DeepEqual(azure.ToStringPtrMap(p.Tags), in.Tags):
+	case ignored.NeedUpdate(azure.ToStringPtrMap(p.Tags), in.Tags):
 		return false
 	case p.SKU.Tier != string(in.Sku.Tier):
 		return false
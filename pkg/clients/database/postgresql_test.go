@@ -27,6 +27,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 )
 
@@ -345,3 +346,273 @@ func TestPostgreSQLServerFirewallRuleIsUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPostgreSQLConfigurationParameters(t *testing.T) {
+	value := "500"
+
+	cases := map[string]struct {
+		r    *v1alpha3.PostgreSQLServerConfiguration
+		want postgresql.Configuration
+	}{
+		"Successful": {
+			r: &v1alpha3.PostgreSQLServerConfiguration{
+				Spec: v1alpha3.SQLServerConfigurationSpec{
+					ForProvider: v1alpha3.SQLServerConfigurationParameters{Value: value},
+				},
+			},
+			want: postgresql.Configuration{
+				ConfigurationProperties: &postgresql.ConfigurationProperties{
+					Value:  azure.ToStringPtr(value),
+					Source: azure.ToStringPtr(ConfigurationSourceUserOverride),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewPostgreSQLConfigurationParameters(tc.r)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewPostgreSQLConfigurationParameters(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPostgreSQLConfigurationResetParameters(t *testing.T) {
+	def := "-1"
+
+	cases := map[string]struct {
+		az   postgresql.Configuration
+		want postgresql.Configuration
+	}{
+		"Successful": {
+			az: postgresql.Configuration{
+				ConfigurationProperties: &postgresql.ConfigurationProperties{
+					Value:        azure.ToStringPtr("500"),
+					DefaultValue: azure.ToStringPtr(def),
+					Source:       azure.ToStringPtr(ConfigurationSourceUserOverride),
+				},
+			},
+			want: postgresql.Configuration{
+				ConfigurationProperties: &postgresql.ConfigurationProperties{
+					Value:  azure.ToStringPtr(def),
+					Source: azure.ToStringPtr(ConfigurationSourceSystemDefault),
+				},
+			},
+		},
+		"NoProperties": {
+			az: postgresql.Configuration{},
+			want: postgresql.Configuration{
+				ConfigurationProperties: &postgresql.ConfigurationProperties{
+					Source: azure.ToStringPtr(ConfigurationSourceSystemDefault),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewPostgreSQLConfigurationResetParameters(tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewPostgreSQLConfigurationResetParameters(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdatePostgreSQLConfigurationObservation(t *testing.T) {
+	id := "/subscriptions/sub/resourceGroups/myrg/providers/Microsoft.DBforPostgreSQL/servers/myserver/configurations/log_min_duration_statement"
+
+	cases := map[string]struct {
+		az   postgresql.Configuration
+		want v1alpha3.SQLServerConfigurationObservation
+	}{
+		"Successful": {
+			az: postgresql.Configuration{
+				ID:   azure.ToStringPtr(id),
+				Type: azure.ToStringPtr("Microsoft.DBforPostgreSQL/servers/configurations"),
+				ConfigurationProperties: &postgresql.ConfigurationProperties{
+					Description:   azure.ToStringPtr("cool"),
+					DefaultValue:  azure.ToStringPtr("-1"),
+					DataType:      azure.ToStringPtr("Integer"),
+					AllowedValues: azure.ToStringPtr("-1-2147483647"),
+					Source:        azure.ToStringPtr(ConfigurationSourceUserOverride),
+				},
+			},
+			want: v1alpha3.SQLServerConfigurationObservation{
+				ID:            id,
+				Type:          "Microsoft.DBforPostgreSQL/servers/configurations",
+				Description:   "cool",
+				DefaultValue:  "-1",
+				DataType:      "Integer",
+				AllowedValues: "-1-2147483647",
+				Source:        ConfigurationSourceUserOverride,
+			},
+		},
+		"NoProperties": {
+			az:   postgresql.Configuration{ID: azure.ToStringPtr(id)},
+			want: v1alpha3.SQLServerConfigurationObservation{ID: id},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := v1alpha3.SQLServerConfigurationObservation{}
+			UpdatePostgreSQLConfigurationObservation(&got, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UpdatePostgreSQLConfigurationObservation(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLConfigurationIsOverridden(t *testing.T) {
+	cases := map[string]struct {
+		az   postgresql.Configuration
+		want bool
+	}{
+		"UserOverride": {
+			az: postgresql.Configuration{ConfigurationProperties: &postgresql.ConfigurationProperties{
+				Source: azure.ToStringPtr(ConfigurationSourceUserOverride),
+			}},
+			want: true,
+		},
+		"SystemDefault": {
+			az: postgresql.Configuration{ConfigurationProperties: &postgresql.ConfigurationProperties{
+				Source: azure.ToStringPtr("System-Default"),
+			}},
+			want: false,
+		},
+		"NoProperties": {
+			az:   postgresql.Configuration{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PostgreSQLConfigurationIsOverridden(tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PostgreSQLConfigurationIsOverridden(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLServerConfigurationIsUpToDate(t *testing.T) {
+	cr := &v1alpha3.PostgreSQLServerConfiguration{
+		Spec: v1alpha3.SQLServerConfigurationSpec{
+			ForProvider: v1alpha3.SQLServerConfigurationParameters{Value: "500"},
+		},
+	}
+
+	cases := map[string]struct {
+		az   postgresql.Configuration
+		want bool
+	}{
+		"UpToDate": {
+			az: postgresql.Configuration{ConfigurationProperties: &postgresql.ConfigurationProperties{
+				Value: azure.ToStringPtr("500"),
+			}},
+			want: true,
+		},
+		"NeedsUpdate": {
+			az: postgresql.Configuration{ConfigurationProperties: &postgresql.ConfigurationProperties{
+				Value: azure.ToStringPtr("-1"),
+			}},
+			want: false,
+		},
+		"NoProperties": {
+			az:   postgresql.Configuration{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PostgreSQLServerConfigurationIsUpToDate(cr, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PostgreSQLServerConfigurationIsUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLConfigurationRequiresRestart(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.SQLServerConfigurationParameters
+		want bool
+	}{
+		"KnownStaticParameter": {
+			p:    v1alpha3.SQLServerConfigurationParameters{Name: "shared_preload_libraries"},
+			want: true,
+		},
+		"DynamicParameter": {
+			p:    v1alpha3.SQLServerConfigurationParameters{Name: "log_min_duration_statement"},
+			want: false,
+		},
+		"RestartDisabled": {
+			p:    v1alpha3.SQLServerConfigurationParameters{Name: "azure.extensions", RestartServer: to.BoolPtr(false)},
+			want: false,
+		},
+		"RestartEnabled": {
+			p:    v1alpha3.SQLServerConfigurationParameters{Name: "log_min_duration_statement", RestartServer: to.BoolPtr(true)},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PostgreSQLConfigurationRequiresRestart(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PostgreSQLConfigurationRequiresRestart(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPostgreSQLUpToDateTags(t *testing.T) {
+	cases := map[string]struct {
+		spec     map[string]string
+		observed map[string]*string
+		want     bool
+	}{
+		"InSync": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("db")},
+			want:     true,
+		},
+		"SystemTagsInjected": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("db"), "hidden-link": to.StringPtr("x")},
+			want:     true,
+		},
+		"TagChanged": {
+			spec:     map[string]string{"cost-center": "db"},
+			observed: map[string]*string{"cost-center": to.StringPtr("web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := v1beta1.SQLServerParameters{Tags: tc.spec}
+			in := postgresql.Server{Sku: &postgresql.Sku{}, Tags: tc.observed, ServerProperties: &postgresql.ServerProperties{StorageProfile: &postgresql.StorageProfile{}}}
+			got := IsPostgreSQLUpToDate(p, in, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsPostgreSQLUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializePostgreSQLTags(t *testing.T) {
+	p := v1beta1.SQLServerParameters{}
+	LateInitializePostgreSQL(&p, postgresql.Server{
+		Tags:             map[string]*string{"cost-center": to.StringPtr("db"), "hidden-link": to.StringPtr("x")},
+		ServerProperties: &postgresql.ServerProperties{},
+	}, azure.DefaultIgnoredTagPrefixes)
+	if diff := cmp.Diff(map[string]string{"cost-center": "db"}, p.Tags); diff != "" {
+		t.Errorf("LateInitializePostgreSQL(...): -want, +got\n%s", diff)
+	}
+}
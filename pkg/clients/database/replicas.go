@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// ConnectionSecretReadEndpointsKey is the connection secret key under which
+// the endpoints of a SQL server's read replicas are published, as a comma
+// separated list. It is empty when the server has no read replicas.
+const ConnectionSecretReadEndpointsKey = "readEndpoints"
+
+// MySQLReadEndpoints returns the fully qualified domain names of the supplied
+// MySQL read replicas, formatted for publication in a connection secret.
+func MySQLReadEndpoints(l mysql.ServerListResult) []byte {
+	if l.Value == nil {
+		return []byte{}
+	}
+	fqdns := make([]string, 0, len(*l.Value))
+	for _, s := range *l.Value {
+		if s.ServerProperties != nil {
+			fqdns = append(fqdns, azure.ToString(s.FullyQualifiedDomainName))
+		}
+	}
+	return readEndpoints(fqdns)
+}
+
+// PostgreSQLReadEndpoints returns the fully qualified domain names of the
+// supplied PostgreSQL read replicas, formatted for publication in a connection
+// secret.
+func PostgreSQLReadEndpoints(l postgresql.ServerListResult) []byte {
+	if l.Value == nil {
+		return []byte{}
+	}
+	fqdns := make([]string, 0, len(*l.Value))
+	for _, s := range *l.Value {
+		if s.ServerProperties != nil {
+			fqdns = append(fqdns, azure.ToString(s.FullyQualifiedDomainName))
+		}
+	}
+	return readEndpoints(fqdns)
+}
+
+// readEndpoints sorts the supplied non-empty endpoints so that the connection
+// secret does not change when Azure lists replicas in a different order.
+func readEndpoints(fqdns []string) []byte {
+	out := make([]string, 0, len(fqdns))
+	for _, f := range fqdns {
+		if f != "" {
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return []byte(strings.Join(out, ","))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
+	"github.com/google/go-cmp/cmp"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestMySQLReadEndpoints(t *testing.T) {
+	cases := map[string]struct {
+		l    mysql.ServerListResult
+		want []byte
+	}{
+		"NoReplicas": {
+			want: []byte{},
+		},
+		"Replicas": {
+			l: mysql.ServerListResult{Value: &[]mysql.Server{
+				{ServerProperties: &mysql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("b.example.org")}},
+				{ServerProperties: &mysql.ServerProperties{}},
+				{},
+				{ServerProperties: &mysql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("a.example.org")}},
+			}},
+			want: []byte("a.example.org,b.example.org"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MySQLReadEndpoints(tc.l)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("MySQLReadEndpoints(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLReadEndpoints(t *testing.T) {
+	cases := map[string]struct {
+		l    postgresql.ServerListResult
+		want []byte
+	}{
+		"NoReplicas": {
+			l:    postgresql.ServerListResult{Value: &[]postgresql.Server{}},
+			want: []byte{},
+		},
+		"Replicas": {
+			l: postgresql.ServerListResult{Value: &[]postgresql.Server{
+				{ServerProperties: &postgresql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("b.example.org")}},
+				{ServerProperties: &postgresql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("a.example.org")}},
+			}},
+			want: []byte("a.example.org,b.example.org"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PostgreSQLReadEndpoints(tc.l)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PostgreSQLReadEndpoints(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
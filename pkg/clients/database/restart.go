@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// AnnotationKeyRestart may be added to a SQL server in order to request that
+// it be restarted, for example to apply a change to a static server parameter.
+// The annotation is removed once the restart has succeeded. A restart that
+// fails or is canceled is reported and then retried until either it succeeds
+// or the annotation is removed.
+const AnnotationKeyRestart = "database.azure.crossplane.io/restart"
+
+const errFmtRestartFailed = "restart did not succeed: status %s"
+
+// RestartRequested returns true if a restart of the supplied SQL server has
+// been requested.
+func RestartRequested(o metav1.Object) bool {
+	_, ok := o.GetAnnotations()[AnnotationKeyRestart]
+	return ok
+}
+
+// RestartSucceeded returns true if the supplied operation is a restart that
+// completed successfully.
+func RestartSucceeded(op v1alpha3.AsyncOperation) bool {
+	return op.Method == http.MethodPost && strings.EqualFold(op.Status, operationStatusSucceeded) && op.ErrorMessage == ""
+}
+
+// RestartError returns an error if the supplied operation is a restart that
+// completed unsuccessfully, for example because it failed or was canceled.
+func RestartError(op v1alpha3.AsyncOperation) error {
+	if op.Method != http.MethodPost || op.Status == "" || op.Status == azure.AsyncOperationStatusInProgress || RestartSucceeded(op) {
+		return nil
+	}
+	err := errors.Errorf(errFmtRestartFailed, op.Status)
+	if op.ErrorMessage != "" {
+		err = errors.Wrap(errors.New(op.ErrorMessage), err.Error())
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	azuredbv1beta1 "github.com/crossplane/provider-azure/apis/database/v1beta1"
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestRestartRequested(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want bool
+	}{
+		"Requested": {
+			o: &azuredbv1beta1.MySQLServer{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{AnnotationKeyRestart: "2020-10-01T00:00:00Z"},
+			}},
+			want: true,
+		},
+		"NotRequested": {
+			o:    &azuredbv1beta1.MySQLServer{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RestartRequested(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("RestartRequested(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRestartSucceeded(t *testing.T) {
+	cases := map[string]struct {
+		op   v1alpha3.AsyncOperation
+		want bool
+	}{
+		"Succeeded": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Succeeded"},
+			want: true,
+		},
+		"SucceededWithError": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Succeeded", ErrorMessage: "boom"},
+			want: false,
+		},
+		"Failed": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"},
+			want: false,
+		},
+		"Canceled": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Canceled"},
+			want: false,
+		},
+		"InProgress": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: azure.AsyncOperationStatusInProgress},
+			want: false,
+		},
+		"NotARestart": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPut, Status: "Succeeded"},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RestartSucceeded(tc.op)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("RestartSucceeded(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRestartError(t *testing.T) {
+	cases := map[string]struct {
+		op   v1alpha3.AsyncOperation
+		want error
+	}{
+		"Succeeded": {
+			op: v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Succeeded"},
+		},
+		"Failed": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"},
+			want: errors.Errorf(errFmtRestartFailed, "Failed"),
+		},
+		"FailedWithError": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed", ErrorMessage: "boom"},
+			want: errors.Wrap(errors.New("boom"), fmt.Sprintf(errFmtRestartFailed, "Failed")),
+		},
+		"Canceled": {
+			op:   v1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Canceled"},
+			want: errors.Errorf(errFmtRestartFailed, "Canceled"),
+		},
+		"InProgress": {
+			op: v1alpha3.AsyncOperation{Method: http.MethodPost, Status: azure.AsyncOperationStatusInProgress},
+		},
+		"NotYetFetched": {
+			op: v1alpha3.AsyncOperation{Method: http.MethodPost},
+		},
+		"NotARestart": {
+			op: v1alpha3.AsyncOperation{Method: http.MethodPut, Status: "Failed"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RestartError(tc.op)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("RestartError(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+)
+
+const msgFmtServerState = "Azure reports the server is %s"
+
+// SQLServerCondition returns the condition that corresponds to the supplied
+// user visible state of a SQL server. Servers that are neither ready nor
+// being dropped are unavailable, with a message that reports their state.
+func SQLServerCondition(state string) runtimev1alpha1.Condition {
+	switch state {
+	case v1beta1.StateReady:
+		return runtimev1alpha1.Available()
+	case v1beta1.StateDropping:
+		return runtimev1alpha1.Deleting()
+	case "":
+		return runtimev1alpha1.Unavailable()
+	}
+	return runtimev1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtServerState, state))
+}
+
+// SQLServerUpdatable returns true if a SQL server in the supplied user visible
+// state may be updated. Azure rejects updates to servers that are disabled or
+// being dropped, so there is no point in asking it to update them.
+func SQLServerUpdatable(state string) bool {
+	return state != v1beta1.StateDisabled && state != v1beta1.StateDropping
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+)
+
+func TestSQLServerCondition(t *testing.T) {
+	cases := map[string]struct {
+		state     string
+		want      runtimev1alpha1.Condition
+		updatable bool
+	}{
+		"Ready": {
+			state:     v1beta1.StateReady,
+			want:      runtimev1alpha1.Available(),
+			updatable: true,
+		},
+		"Dropping": {
+			state: v1beta1.StateDropping,
+			want:  runtimev1alpha1.Deleting(),
+		},
+		"Disabled": {
+			state: v1beta1.StateDisabled,
+			want:  runtimev1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtServerState, v1beta1.StateDisabled)),
+		},
+		"Inaccessible": {
+			state:     "Inaccessible",
+			want:      runtimev1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtServerState, "Inaccessible")),
+			updatable: true,
+		},
+		"Unknown": {
+			want:      runtimev1alpha1.Unavailable(),
+			updatable: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SQLServerCondition(tc.state)
+			if !tc.want.Equal(got) {
+				t.Errorf("SQLServerCondition(...): want %+v, got %+v", tc.want, got)
+			}
+			if diff := cmp.Diff(tc.updatable, SQLServerUpdatable(tc.state)); diff != "" {
+				t.Errorf("SQLServerUpdatable(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azuredbv1beta1 "github.com/crossplane/provider-azure/apis/database/v1beta1"
+)
+
+const errUpdateStorageDefault = "cannot update SQL server with default storage size"
+
+// A storageSize describes the storage that may be allocated to a SQL server of
+// a particular pricing tier. Storage is expressed in MB.
+type storageSize struct {
+	min     int
+	max     int
+	perCore int
+}
+
+// storageSizes are the storage sizes of each pricing tier. Servers default to
+// a storage size that scales with their vCores, within the limits of the tier.
+var storageSizes = map[mysql.SkuTier]storageSize{
+	mysql.Basic:           {min: 5 * 1024, max: 1024 * 1024, perCore: 5 * 1024},
+	mysql.GeneralPurpose:  {min: 5 * 1024, max: 16 * 1024 * 1024, perCore: 25 * 1024},
+	mysql.MemoryOptimized: {min: 5 * 1024, max: 16 * 1024 * 1024, perCore: 25 * 1024},
+}
+
+// DefaultStorageMB returns the default storage size in MB of a SQL server with
+// the supplied SKU, or zero if the SKU's tier is unknown.
+func DefaultStorageMB(sku azuredbv1beta1.SKU) int {
+	s, ok := storageSizes[mysql.SkuTier(sku.Tier)]
+	if !ok {
+		return 0
+	}
+	mb := sku.Capacity * s.perCore
+	switch {
+	case mb < s.min:
+		return s.min
+	case mb > s.max:
+		return s.max
+	}
+	return mb
+}
+
+// A StorageDefaulter defaults the storage size of MySQL and PostgreSQL servers
+// that do not specify one, according to their SKU.
+type StorageDefaulter struct {
+	client client.Client
+}
+
+// NewStorageDefaulter returns a StorageDefaulter that persists the storage
+// size it defaults using the supplied client.
+func NewStorageDefaulter(c client.Client) *StorageDefaulter {
+	return &StorageDefaulter{client: c}
+}
+
+// Initialize defaults the storage size of the supplied SQL server, if it does
+// not specify one.
+func (d *StorageDefaulter) Initialize(ctx context.Context, mg resource.Managed) error {
+	var p *azuredbv1beta1.SQLServerParameters
+	switch cr := mg.(type) {
+	case *azuredbv1beta1.MySQLServer:
+		p = &cr.Spec.ForProvider
+	case *azuredbv1beta1.PostgreSQLServer:
+		p = &cr.Spec.ForProvider
+	default:
+		return nil
+	}
+	if p.StorageProfile.StorageMB != 0 {
+		return nil
+	}
+	mb := DefaultStorageMB(p.SKU)
+	if mb == 0 {
+		return nil
+	}
+	p.StorageProfile.StorageMB = mb
+	return errors.Wrap(d.client.Update(ctx, mg), errUpdateStorageDefault)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	azuredbv1beta1 "github.com/crossplane/provider-azure/apis/database/v1beta1"
+)
+
+func TestDefaultStorageMB(t *testing.T) {
+	cases := map[string]struct {
+		sku  azuredbv1beta1.SKU
+		want int
+	}{
+		"Basic": {
+			sku:  azuredbv1beta1.SKU{Tier: "Basic", Capacity: 2},
+			want: 10240,
+		},
+		"GeneralPurpose": {
+			sku:  azuredbv1beta1.SKU{Tier: "GeneralPurpose", Capacity: 4},
+			want: 102400,
+		},
+		"TierMinimum": {
+			sku:  azuredbv1beta1.SKU{Tier: "MemoryOptimized"},
+			want: 5120,
+		},
+		"UnknownTier": {
+			sku:  azuredbv1beta1.SKU{Tier: "Premium", Capacity: 2},
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DefaultStorageMB(tc.sku)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DefaultStorageMB(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStorageDefaulter(t *testing.T) {
+	errBoom := errors.New("boom")
+	server := func(mb int) *azuredbv1beta1.MySQLServer {
+		s := &azuredbv1beta1.MySQLServer{}
+		s.Spec.ForProvider.SKU = azuredbv1beta1.SKU{Tier: "Basic", Capacity: 1}
+		s.Spec.ForProvider.StorageProfile.StorageMB = mb
+		return s
+	}
+
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client *test.MockClient
+		mg     resource.Managed
+		want   want
+	}{
+		"Defaulted": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			mg:     server(0),
+			want:   want{mg: server(5120)},
+		},
+		"AlreadySet": {
+			mg:   server(10240),
+			want: want{mg: server(10240)},
+		},
+		"UpdateError": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			mg:     server(0),
+			want:   want{mg: server(5120), err: errors.Wrap(errBoom, errUpdateStorageDefault)},
+		},
+		"NotASQLServer": {
+			mg:   &fake.Managed{},
+			want: want{mg: &fake.Managed{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewStorageDefaulter(tc.client).Initialize(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Initialize(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg); diff != "" {
+				t.Errorf("Initialize(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// ValidateMinimalTLSVersion returns an error if the minimal TLS version of the
+// supplied SQL server parameters is not supported by Azure, or cannot be
+// enforced because SSL enforcement is disabled. MySQL and PostgreSQL servers
+// support the same minimal TLS versions.
+func ValidateMinimalTLSVersion(p v1beta1.SQLServerParameters) error {
+	if p.MinimalTLSVersion == nil {
+		return nil
+	}
+	v := mysql.MinimalTLSVersionEnum(azure.ToString(p.MinimalTLSVersion))
+	valid := false
+	for _, s := range mysql.PossibleMinimalTLSVersionEnumValues() {
+		if v == s {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("minimal TLS version '%s' is not one of the supported values: %+v", v, mysql.PossibleMinimalTLSVersionEnumValues())
+	}
+	if p.SSLEnforcement == string(mysql.SslEnforcementEnumDisabled) && v != mysql.TLSEnforcementDisabled {
+		return fmt.Errorf("minimal TLS version '%s' cannot be enforced unless SSL enforcement is enabled", v)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestValidateMinimalTLSVersion(t *testing.T) {
+	cases := map[string]struct {
+		p    v1beta1.SQLServerParameters
+		want error
+	}{
+		"Unset": {
+			p: v1beta1.SQLServerParameters{SSLEnforcement: "Disabled"},
+		},
+		"Valid": {
+			p: v1beta1.SQLServerParameters{SSLEnforcement: "Enabled", MinimalTLSVersion: azure.ToStringPtr("TLS1_2")},
+		},
+		"Unsupported": {
+			p:    v1beta1.SQLServerParameters{SSLEnforcement: "Enabled", MinimalTLSVersion: azure.ToStringPtr("TLS1_3")},
+			want: fmt.Errorf("minimal TLS version 'TLS1_3' is not one of the supported values: %+v", mysql.PossibleMinimalTLSVersionEnumValues()),
+		},
+		"SSLDisabled": {
+			p:    v1beta1.SQLServerParameters{SSLEnforcement: "Disabled", MinimalTLSVersion: azure.ToStringPtr("TLS1_2")},
+			want: fmt.Errorf("minimal TLS version 'TLS1_2' cannot be enforced unless SSL enforcement is enabled"),
+		},
+		"SSLDisabledEnforcementDisabled": {
+			p: v1beta1.SQLServerParameters{SSLEnforcement: "Disabled", MinimalTLSVersion: azure.ToStringPtr("TLSEnforcementDisabled")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateMinimalTLSVersion(tc.p)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateMinimalTLSVersion(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
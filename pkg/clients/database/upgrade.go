@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+)
+
+// Error strings.
+const (
+	errFmtUnsupportedMySQLUpgrade      = "cannot upgrade server from version %q to %q: only upgrades from MySQL 5.6 to 5.7 are supported"
+	errFmtUnsupportedPostgreSQLUpgrade = "cannot upgrade server from version %q to %q: in place upgrades of PostgreSQL servers are not supported"
+)
+
+// The Azure SDK version we use predates in place major version upgrades, so
+// we call the upgrade API directly.
+const (
+	upgradeAPIVersion = "2020-01-01"
+	upgradePath       = "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/{provider}/servers/{serverName}/upgrade"
+
+	providerMySQL = "Microsoft.DBforMySQL"
+)
+
+// mySQLUpgrades maps the MySQL server versions that may be upgraded in place
+// to the version they may be upgraded to.
+var mySQLUpgrades = map[string]string{"5.6": "5.7"}
+
+// UpgradeRequested returns true if the desired version of a SQL server differs
+// from its observed version.
+func UpgradeRequested(desired, observed string) bool {
+	return observed != "" && desired != observed
+}
+
+// ValidateMySQLUpgrade returns an error unless a MySQL server may be upgraded
+// in place from one version to another.
+func ValidateMySQLUpgrade(from, to string) error {
+	if mySQLUpgrades[from] != to {
+		return errors.Errorf(errFmtUnsupportedMySQLUpgrade, from, to)
+	}
+	return nil
+}
+
+// ValidatePostgreSQLUpgrade returns an error, because PostgreSQL servers may
+// not be upgraded in place.
+func ValidatePostgreSQLUpgrade(from, to string) error {
+	return errors.Errorf(errFmtUnsupportedPostgreSQLUpgrade, from, to)
+}
+
+// upgradeServer starts an in place upgrade of the supplied server to the
+// supplied version, returning the operation that tracks the upgrade.
+func upgradeServer(ctx context.Context, c autorest.Client, baseURI, subscriptionID, provider, group, name, version string) (v1alpha3.AsyncOperation, error) {
+	params := map[string]interface{}{
+		"subscriptionId":    autorest.Encode("path", subscriptionID),
+		"resourceGroupName": autorest.Encode("path", group),
+		"provider":          provider,
+		"serverName":        autorest.Encode("path", name),
+	}
+	body := map[string]interface{}{
+		"properties": map[string]string{"targetServerVersion": version},
+	}
+	req, err := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPost(),
+		autorest.WithBaseURL(baseURI),
+		autorest.WithPathParameters(upgradePath, params),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": upgradeAPIVersion}),
+		autorest.WithJSON(body),
+	).Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return v1alpha3.AsyncOperation{}, err
+	}
+	resp, err := c.Send(req, azure.DoRetryWithRegistration(c))
+	if err != nil {
+		return v1alpha3.AsyncOperation{}, err
+	}
+	f, err := azure.NewFutureFromResponse(resp)
+	if err != nil {
+		return v1alpha3.AsyncOperation{}, err
+	}
+	if err := autorest.Respond(resp, azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusAccepted)); err != nil {
+		return v1alpha3.AsyncOperation{}, err
+	}
+	return v1alpha3.AsyncOperation{PollingURL: f.PollingURL(), Method: http.MethodPost}, nil
+}
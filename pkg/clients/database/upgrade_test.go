@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestUpgradeRequested(t *testing.T) {
+	cases := map[string]struct {
+		desired  string
+		observed string
+		want     bool
+	}{
+		"Requested": {
+			desired:  "5.7",
+			observed: "5.6",
+			want:     true,
+		},
+		"UpToDate": {
+			desired:  "5.7",
+			observed: "5.7",
+			want:     false,
+		},
+		"NotYetObserved": {
+			desired: "5.7",
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UpgradeRequested(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UpgradeRequested(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateMySQLUpgrade(t *testing.T) {
+	cases := map[string]struct {
+		from string
+		to   string
+		want error
+	}{
+		"Upgrade": {
+			from: "5.6",
+			to:   "5.7",
+		},
+		"SkipVersion": {
+			from: "5.6",
+			to:   "8.0",
+			want: errors.Errorf(errFmtUnsupportedMySQLUpgrade, "5.6", "8.0"),
+		},
+		"Downgrade": {
+			from: "5.7",
+			to:   "5.6",
+			want: errors.Errorf(errFmtUnsupportedMySQLUpgrade, "5.7", "5.6"),
+		},
+		"Unsupported": {
+			from: "5.7",
+			to:   "8.0",
+			want: errors.Errorf(errFmtUnsupportedMySQLUpgrade, "5.7", "8.0"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateMySQLUpgrade(tc.from, tc.to)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateMySQLUpgrade(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidatePostgreSQLUpgrade(t *testing.T) {
+	cases := map[string]struct {
+		from string
+		to   string
+		want error
+	}{
+		"Upgrade": {
+			from: "10",
+			to:   "11",
+			want: errors.Errorf(errFmtUnsupportedPostgreSQLUpgrade, "10", "11"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePostgreSQLUpgrade(tc.from, tc.to)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidatePostgreSQLUpgrade(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// TypeDriftCorrected resources have had changes made to their external
+// resource outside of Crossplane reverted to match their spec.
+const TypeDriftCorrected runtimev1alpha1.ConditionType = "DriftCorrected"
+
+// ReasonDriftCorrected indicates drift was corrected.
+const ReasonDriftCorrected runtimev1alpha1.ConditionReason = "DriftCorrected"
+
+const msgFmtDriftCorrected = "Azure resource was updated to match the declared %s"
+
+// DriftCorrected returns a condition that indicates the supplied fields of an
+// external resource were found to differ from those declared by its managed
+// resource, and were updated to match.
+func DriftCorrected(fields []string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeDriftCorrected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDriftCorrected,
+		Message:            fmt.Sprintf(msgFmtDriftCorrected, strings.Join(fields, ", ")),
+	}
+}
+
+// ClearDriftCorrected removes any DriftCorrected condition from the supplied
+// status, e.g. because the spec of its managed resource changed.
+func ClearDriftCorrected(s *runtimev1alpha1.ConditionedStatus) {
+	var kept []runtimev1alpha1.Condition
+	for _, c := range s.Conditions {
+		if c.Type != TypeDriftCorrected {
+			kept = append(kept, c)
+		}
+	}
+	s.Conditions = kept
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Throttling backoff bounds. Azure's own Retry-After hint is used instead when
+// it is supplied.
+const (
+	DefaultThrottleBackoff = 30 * time.Second
+	MaxThrottleBackoff     = 10 * time.Minute
+)
+
+// ReasonForbidden indicates Azure refused to tell us about an external
+// resource because our credentials are not authorized to access it.
+const ReasonForbidden runtimev1alpha1.ConditionReason = "Forbidden"
+
+// Forbidden returns a condition that indicates whether the external resource
+// is ready is unknown, because our credentials are not authorized to access
+// it.
+func Forbidden() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeReady,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonForbidden,
+	}
+}
+
+// Error strings.
+const (
+	errFmtThrottled = "Azure throttled the request; backing off until %s"
+	errFmtBackoff   = "not calling Azure until %s: it recently throttled requests for this resource"
+	errForbidden    = "the Azure credentials are not authorized to perform this operation; check the role assignments of the service principal"
+	errConflict     = "the Azure resource is being changed by another operation"
+)
+
+// A Throttle tracks how often, and until when, Azure has throttled requests
+// for each managed resource, so that we can back off from calling Azure for
+// a managed resource until its backoff has elapsed. A nil Throttle never backs
+// off.
+type Throttle struct {
+	mu        sync.Mutex
+	throttled map[types.UID]throttle
+	now       func() time.Time
+}
+
+// A throttle records how often, and until when, Azure has throttled requests
+// for a managed resource.
+type throttle struct {
+	count int
+	until time.Time
+}
+
+// NewThrottle returns a Throttle that is not backing off from any managed
+// resource.
+func NewThrottle() *Throttle {
+	return &Throttle{throttled: make(map[types.UID]throttle), now: time.Now}
+}
+
+// Backoff returns the time until which we should not call Azure for the
+// supplied managed resource, if any.
+func (t *Throttle) Backoff(mg resource.Managed) (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	th, ok := t.throttled[mg.GetUID()]
+	if !ok || !t.now().Before(th.until) {
+		return time.Time{}, false
+	}
+	return th.until, true
+}
+
+// Classify the supplied error returned by an Azure request for the supplied
+// managed resource, recording or resetting its throttling backoff. Any request
+// Azure does not throttle resets the backoff, including one that finds the
+// external resource does not exist or deletes it. Authorization and conflict
+// errors are annotated so that they are easy to distinguish in the managed
+// resource's Synced condition. Authorization errors also clear the managed
+// resource's Ready condition, because whether its external resource is ready
+// can no longer be observed.
+func (t *Throttle) Classify(mg resource.Managed, err error) error {
+	if t != nil {
+		if until, ok := t.record(mg, err); ok {
+			return errors.Wrapf(err, errFmtThrottled, until.Format(time.RFC3339))
+		}
+	}
+
+	switch {
+	case IsForbidden(err):
+		mg.SetConditions(Forbidden())
+		return errors.Wrap(err, errForbidden)
+	case IsConflict(err):
+		return errors.Wrap(err, errConflict)
+	}
+	return err
+}
+
+// record the throttling backoff of the supplied managed resource if the
+// supplied error indicates Azure throttled it, or reset it if not.
+func (t *Throttle) record(mg resource.Managed, err error) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.evict()
+
+	if !IsThrottled(err) {
+		delete(t.throttled, mg.GetUID())
+		return time.Time{}, false
+	}
+	th := t.throttled[mg.GetUID()]
+	th.count++
+	th.until = t.now().Add(throttleBackoff(err, th.count))
+	t.throttled[mg.GetUID()] = th
+	return th.until, true
+}
+
+// evict the throttling backoffs of managed resources that have not been
+// throttled for a while. A backoff is otherwise only reset by the next request
+// for its managed resource that Azure does not throttle, which never happens
+// if the managed resource is deleted while it is backing off. The caller must
+// hold the lock.
+func (t *Throttle) evict() {
+	for uid, th := range t.throttled {
+		if t.now().After(th.until.Add(MaxThrottleBackoff)) {
+			delete(t.throttled, uid)
+		}
+	}
+}
+
+// NewErrorClassifyingConnecter returns a managed.ExternalConnecter whose
+// clients classify the Azure API errors their operations return using the
+// supplied Throttle. Clients refuse to call Azure for a managed resource
+// while the Throttle is backing off from it.
+func NewErrorClassifyingConnecter(c managed.ExternalConnecter, t *Throttle) managed.ExternalConnecter {
+	return &errorClassifyingConnecter{ExternalConnecter: c, throttle: t}
+}
+
+type errorClassifyingConnecter struct {
+	managed.ExternalConnecter
+	throttle *Throttle
+}
+
+func (c *errorClassifyingConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &errorClassifyingExternal{ExternalClient: e, throttle: c.throttle}, nil
+}
+
+// throttleBackoff returns how long to back off after Azure throttled the
+// request that caused the supplied error, given how many times in a row it has
+// done so.
+func throttleBackoff(err error, count int) time.Duration {
+	d := DefaultThrottleBackoff
+	for i := 1; i < count && d < MaxThrottleBackoff; i++ {
+		d *= 2
+	}
+	if d > MaxThrottleBackoff {
+		d = MaxThrottleBackoff
+	}
+	if r := response(err); r != nil {
+		return autorest.GetRetryAfter(r, d)
+	}
+	return d
+}
+
+type errorClassifyingExternal struct {
+	managed.ExternalClient
+	throttle *Throttle
+}
+
+func (e *errorClassifyingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	// Every reconcile starts by observing the external resource, so refusing
+	// to observe it is sufficient to stop the reconcile from calling Azure.
+	// Reconcilers wrapped by a throttle reconciler don't get this far while
+	// backing off; they're requeued once the backoff has elapsed instead.
+	if until, ok := e.throttle.Backoff(mg); ok {
+		return managed.ExternalObservation{}, errors.Errorf(errFmtBackoff, until.Format(time.RFC3339))
+	}
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, e.throttle.Classify(mg, err)
+}
+
+func (e *errorClassifyingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, e.throttle.Classify(mg, err)
+}
+
+func (e *errorClassifyingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, e.throttle.Classify(mg, err)
+}
+
+func (e *errorClassifyingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.throttle.Classify(mg, e.ExternalClient.Delete(ctx, mg))
+}
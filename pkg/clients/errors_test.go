@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestErrorClassification(t *testing.T) {
+	type want struct {
+		throttled bool
+		conflict  bool
+		forbidden bool
+	}
+
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"Nil": {},
+		"NotAnAzureError": {
+			err: errors.New("boom"),
+		},
+		"Throttled": {
+			err:  autorest.DetailedError{StatusCode: http.StatusTooManyRequests},
+			want: want{throttled: true},
+		},
+		"WrappedThrottled": {
+			err:  errors.Wrap(autorest.DetailedError{StatusCode: http.StatusTooManyRequests}, "cannot get resource"),
+			want: want{throttled: true},
+		},
+		"Conflict": {
+			err:  autorest.DetailedError{StatusCode: http.StatusConflict},
+			want: want{conflict: true},
+		},
+		"Forbidden": {
+			err:  &requestIDError{error: autorest.DetailedError{StatusCode: http.StatusForbidden}},
+			want: want{forbidden: true},
+		},
+		"NotFound": {
+			err: autorest.DetailedError{StatusCode: http.StatusNotFound},
+		},
+		"StorageThrottled": {
+			err:  errors.Wrap(storageError{code: http.StatusTooManyRequests}, "cannot get container"),
+			want: want{throttled: true},
+		},
+		"StorageForbidden": {
+			err:  storageError{code: http.StatusForbidden},
+			want: want{forbidden: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{throttled: IsThrottled(tc.err), conflict: IsConflict(tc.err), forbidden: IsForbidden(tc.err)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("Is...(%v): -want, +got\n%s", tc.err, diff)
+			}
+		})
+	}
+}
+
+func TestThrottleBackoff(t *testing.T) {
+	retryAfter := &http.Response{Header: http.Header{autorest.HeaderRetryAfter: []string{"90"}}}
+
+	cases := map[string]struct {
+		err   error
+		count int
+		want  time.Duration
+	}{
+		"First": {
+			err:   autorest.DetailedError{StatusCode: http.StatusTooManyRequests},
+			count: 1,
+			want:  DefaultThrottleBackoff,
+		},
+		"Third": {
+			err:   autorest.DetailedError{StatusCode: http.StatusTooManyRequests},
+			count: 3,
+			want:  4 * DefaultThrottleBackoff,
+		},
+		"Capped": {
+			err:   autorest.DetailedError{StatusCode: http.StatusTooManyRequests},
+			count: 100,
+			want:  MaxThrottleBackoff,
+		},
+		"RetryAfter": {
+			err:   autorest.DetailedError{StatusCode: http.StatusTooManyRequests, Response: retryAfter},
+			count: 3,
+			want:  90 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := throttleBackoff(tc.err, tc.count)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("throttleBackoff(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+// A storageError resembles the errors returned by the Azure Storage data plane
+// SDK, which carry the HTTP response that caused them.
+type storageError struct{ code int }
+
+func (e storageError) Error() string { return http.StatusText(e.code) }
+
+func (e storageError) Response() *http.Response { return &http.Response{StatusCode: e.code} }
+
+func TestErrorClassifyingExternal(t *testing.T) {
+	errThrottled := autorest.DetailedError{StatusCode: http.StatusTooManyRequests}
+	errForbiddenAPI := autorest.DetailedError{StatusCode: http.StatusForbidden}
+	errConflictAPI := autorest.DetailedError{StatusCode: http.StatusConflict}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	var observeErr error
+	calls := 0
+	ec := &managed.ExternalClientFns{
+		ObserveFn: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+			calls++
+			return managed.ExternalObservation{ResourceExists: true}, observeErr
+		},
+		DeleteFn: func(context.Context, resource.Managed) error { return errConflictAPI },
+	}
+	th := NewThrottle()
+	th.now = func() time.Time { return now }
+	c := NewErrorClassifyingConnecter(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return ec, nil
+	}), th)
+
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+	e, err := c.Connect(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+
+	type want struct {
+		err   error
+		calls int
+	}
+
+	steps := []struct {
+		name    string
+		elapsed time.Duration
+		err     error
+		want    want
+	}{
+		{
+			name: "Throttled",
+			err:  errThrottled,
+			want: want{
+				err:   errors.Wrapf(errThrottled, errFmtThrottled, start.Add(DefaultThrottleBackoff).Format(time.RFC3339)),
+				calls: 1,
+			},
+		},
+		{
+			name:    "BackingOff",
+			elapsed: DefaultThrottleBackoff / 2,
+			want: want{
+				err:   errors.Errorf(errFmtBackoff, start.Add(DefaultThrottleBackoff).Format(time.RFC3339)),
+				calls: 1,
+			},
+		},
+		{
+			name:    "ThrottledAgain",
+			elapsed: DefaultThrottleBackoff / 2,
+			err:     errThrottled,
+			want: want{
+				err:   errors.Wrapf(errThrottled, errFmtThrottled, start.Add(DefaultThrottleBackoff+2*DefaultThrottleBackoff).Format(time.RFC3339)),
+				calls: 2,
+			},
+		},
+		{
+			name:    "Forbidden",
+			elapsed: 2 * DefaultThrottleBackoff,
+			err:     errForbiddenAPI,
+			want: want{
+				err:   errors.Wrap(errForbiddenAPI, errForbidden),
+				calls: 3,
+			},
+		},
+		{
+			name:    "NoLongerBackingOff",
+			elapsed: 0,
+			want:    want{calls: 4},
+		},
+	}
+
+	for _, s := range steps {
+		now = now.Add(s.elapsed)
+		observeErr = s.err
+		_, err := e.Observe(context.Background(), mg)
+		if diff := cmp.Diff(s.want.err, err, test.EquateErrors()); diff != "" {
+			t.Errorf("%s: Observe(...): -want error, +got error\n%s", s.name, diff)
+		}
+		if diff := cmp.Diff(s.want.calls, calls); diff != "" {
+			t.Errorf("%s: Observe(...) calls: -want, +got\n%s", s.name, diff)
+		}
+	}
+
+	if diff := cmp.Diff(Forbidden(), mg.GetCondition(runtimev1alpha1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want Ready condition, +got Ready condition\n%s", diff)
+	}
+
+	if diff := cmp.Diff(errors.Wrap(errConflictAPI, errConflict), e.Delete(context.Background(), mg), test.EquateErrors()); diff != "" {
+		t.Errorf("Delete(...): -want error, +got error\n%s", diff)
+	}
+}
+
+func TestThrottleEvict(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	th := NewThrottle()
+	th.now = func() time.Time { return now }
+
+	errThrottled := autorest.DetailedError{StatusCode: http.StatusTooManyRequests}
+	deleted := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "deleted-uid"}}
+	_ = th.Classify(deleted, errThrottled)
+
+	now = now.Add(DefaultThrottleBackoff + MaxThrottleBackoff + time.Second)
+	_ = th.Classify(&fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "other-uid"}}, errThrottled)
+
+	if _, ok := th.throttled[deleted.GetUID()]; ok {
+		t.Errorf("Classify(...): wanted stale backoff of %q to be evicted", deleted.GetUID())
+	}
+	if diff := cmp.Diff(1, len(th.throttled)); diff != "" {
+		t.Errorf("Classify(...): -want backoffs, +got backoffs\n%s", diff)
+	}
+}
+
+func TestNilThrottle(t *testing.T) {
+	var th *Throttle
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+	errThrottled := autorest.DetailedError{StatusCode: http.StatusTooManyRequests}
+
+	if diff := cmp.Diff(errThrottled, th.Classify(mg, errThrottled), test.EquateErrors()); diff != "" {
+		t.Errorf("Classify(...): -want error, +got error\n%s", diff)
+	}
+	if _, ok := th.Backoff(mg); ok {
+		t.Errorf("Backoff(...): wanted a nil Throttle not to back off")
+	}
+}
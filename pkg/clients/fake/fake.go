@@ -34,6 +34,8 @@ type MockMySQLVirtualNetworkRulesClient struct {
 	MockCreateOrUpdate func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string, parameters mysql.VirtualNetworkRule) (result mysql.VirtualNetworkRulesCreateOrUpdateFuture, err error)
 	MockDelete         func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string) (result mysql.VirtualNetworkRulesDeleteFuture, err error)
 	MockGet            func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string) (result mysql.VirtualNetworkRule, err error)
+
+	MockListByServerComplete func(ctx context.Context, resourceGroupName string, serverName string) (result mysql.VirtualNetworkRuleListResultIterator, err error)
 }
 
 // CreateOrUpdate calls the MockMySQLVirtualNetworkRulesClient's MockCreateOrUpdate method.
@@ -51,6 +53,11 @@ func (c *MockMySQLVirtualNetworkRulesClient) Get(ctx context.Context, resourceGr
 	return c.MockGet(ctx, resourceGroupName, serverName, virtualNetworkRuleName)
 }
 
+// ListByServerComplete calls the MockMySQLVirtualNetworkRulesClient's MockListByServerComplete method.
+func (c *MockMySQLVirtualNetworkRulesClient) ListByServerComplete(ctx context.Context, resourceGroupName string, serverName string) (result mysql.VirtualNetworkRuleListResultIterator, err error) {
+	return c.MockListByServerComplete(ctx, resourceGroupName, serverName)
+}
+
 var _ postgresqlapi.VirtualNetworkRulesClientAPI = &MockPostgreSQLVirtualNetworkRulesClient{}
 
 // MockPostgreSQLVirtualNetworkRulesClient is a fake implementation of postgresql.VirtualNetworkRulesClient.
@@ -60,6 +67,8 @@ type MockPostgreSQLVirtualNetworkRulesClient struct {
 	MockCreateOrUpdate func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string, parameters postgresql.VirtualNetworkRule) (result postgresql.VirtualNetworkRulesCreateOrUpdateFuture, err error)
 	MockDelete         func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string) (result postgresql.VirtualNetworkRulesDeleteFuture, err error)
 	MockGet            func(ctx context.Context, resourceGroupName string, serverName string, virtualNetworkRuleName string) (result postgresql.VirtualNetworkRule, err error)
+
+	MockListByServerComplete func(ctx context.Context, resourceGroupName string, serverName string) (result postgresql.VirtualNetworkRuleListResultIterator, err error)
 }
 
 // CreateOrUpdate calls the MockPostgreSQLVirtualNetworkRulesClient's MockCreateOrUpdate method.
@@ -77,6 +86,11 @@ func (c *MockPostgreSQLVirtualNetworkRulesClient) Get(ctx context.Context, resou
 	return c.MockGet(ctx, resourceGroupName, serverName, virtualNetworkRuleName)
 }
 
+// ListByServerComplete calls the MockPostgreSQLVirtualNetworkRulesClient's MockListByServerComplete method.
+func (c *MockPostgreSQLVirtualNetworkRulesClient) ListByServerComplete(ctx context.Context, resourceGroupName string, serverName string) (result postgresql.VirtualNetworkRuleListResultIterator, err error) {
+	return c.MockListByServerComplete(ctx, resourceGroupName, serverName)
+}
+
 var _ mysqlapi.FirewallRulesClientAPI = &MockMySQLFirewallRulesClient{}
 
 // MockMySQLFirewallRulesClient is a fake implementation of mysql.FirewallRulesClient.
@@ -128,3 +142,37 @@ func (c *MockPostgreSQLFirewallRulesClient) Delete(ctx context.Context, resource
 func (c *MockPostgreSQLFirewallRulesClient) Get(ctx context.Context, resourceGroupName string, serverName string, firewallRuleName string) (result postgresql.FirewallRule, err error) {
 	return c.MockGet(ctx, resourceGroupName, serverName, firewallRuleName)
 }
+
+var _ postgresqlapi.ConfigurationsClientAPI = &MockPostgreSQLConfigurationsClient{}
+
+// MockPostgreSQLConfigurationsClient is a fake implementation of postgresql.ConfigurationsClient.
+type MockPostgreSQLConfigurationsClient struct {
+	postgresqlapi.ConfigurationsClientAPI
+
+	MockCreateOrUpdate func(ctx context.Context, resourceGroupName string, serverName string, configurationName string, parameters postgresql.Configuration) (result postgresql.ConfigurationsCreateOrUpdateFuture, err error)
+	MockGet            func(ctx context.Context, resourceGroupName string, serverName string, configurationName string) (result postgresql.Configuration, err error)
+}
+
+// CreateOrUpdate calls the MockPostgreSQLConfigurationsClient's MockCreateOrUpdate method.
+func (c *MockPostgreSQLConfigurationsClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, serverName string, configurationName string, parameters postgresql.Configuration) (result postgresql.ConfigurationsCreateOrUpdateFuture, err error) {
+	return c.MockCreateOrUpdate(ctx, resourceGroupName, serverName, configurationName, parameters)
+}
+
+// Get calls the MockPostgreSQLConfigurationsClient's MockGet method.
+func (c *MockPostgreSQLConfigurationsClient) Get(ctx context.Context, resourceGroupName string, serverName string, configurationName string) (result postgresql.Configuration, err error) {
+	return c.MockGet(ctx, resourceGroupName, serverName, configurationName)
+}
+
+var _ postgresqlapi.ServersClientAPI = &MockPostgreSQLServersClient{}
+
+// MockPostgreSQLServersClient is a fake implementation of postgresql.ServersClient.
+type MockPostgreSQLServersClient struct {
+	postgresqlapi.ServersClientAPI
+
+	MockRestart func(ctx context.Context, resourceGroupName string, serverName string) (result postgresql.ServersRestartFuture, err error)
+}
+
+// Restart calls the MockPostgreSQLServersClient's MockRestart method.
+func (c *MockPostgreSQLServersClient) Restart(ctx context.Context, resourceGroupName string, serverName string) (result postgresql.ServersRestartFuture, err error) {
+	return c.MockRestart(ctx, resourceGroupName, serverName)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// ReasonWaitingForResourceGroup indicates a resource cannot be created until
+// the resource group it belongs to exists.
+const ReasonWaitingForResourceGroup runtimev1alpha1.ConditionReason = "WaitingForResourceGroup"
+
+// errCodeResourceGroupNotFound is the code of the error Azure returns when
+// asked to create a resource in a resource group that does not exist.
+const errCodeResourceGroupNotFound = "ResourceGroupNotFound"
+
+const (
+	errFmtResourceGroupNotFound   = "resource group %s does not exist"
+	msgFmtWaitingForResourceGroup = "Waiting for resource group %s to exist"
+)
+
+// IsResourceGroupNotFound returns true if the supplied error indicates that
+// Azure rejected a request because the resource group it targeted does not
+// exist.
+func IsResourceGroupNotFound(err error) bool {
+	var se *azureautorest.ServiceError
+	if errors.As(err, &se) {
+		return se.Code == errCodeResourceGroupNotFound
+	}
+	var de autorest.DetailedError
+	if !errors.As(err, &de) {
+		return false
+	}
+	re, ok := de.Original.(*azureautorest.RequestError)
+	if !ok || re.ServiceError == nil {
+		return false
+	}
+	return re.ServiceError.Code == errCodeResourceGroupNotFound
+}
+
+// WaitingForResourceGroup returns a condition that indicates a resource cannot
+// be created until the supplied resource group exists.
+func WaitingForResourceGroup(group string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForResourceGroup,
+		Message:            fmt.Sprintf(msgFmtWaitingForResourceGroup, group),
+	}
+}
+
+// WaitForResourceGroup returns the supplied error unchanged unless it
+// indicates that the supplied resource group does not exist. If it does, the
+// supplied resource is marked as waiting for the resource group and an error
+// that names the missing group is returned in place of Azure's.
+func WaitForResourceGroup(o resource.Conditioned, group string, err error) error {
+	if !IsResourceGroupNotFound(err) {
+		return err
+	}
+	o.SetConditions(WaitingForResourceGroup(group))
+	return errors.Errorf(errFmtResourceGroupNotFound, group)
+}
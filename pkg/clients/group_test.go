@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestIsResourceGroupNotFound(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"ResourceGroupNotFound": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusNotFound,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "ResourceGroupNotFound"}},
+			},
+			want: true,
+		},
+		"ServiceError": {
+			err:  errors.Wrap(&azureautorest.ServiceError{Code: "ResourceGroupNotFound"}, "cannot create"),
+			want: true,
+		},
+		"OtherNotFound": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusNotFound,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "ResourceNotFound"}},
+			},
+			want: false,
+		},
+		"NotDetailed": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsResourceGroupNotFound(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsResourceGroupNotFound(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWaitForResourceGroup(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := autorest.DetailedError{
+		StatusCode: http.StatusNotFound,
+		Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "ResourceGroupNotFound"}},
+	}
+
+	type want struct {
+		err        error
+		conditions []runtimev1alpha1.Condition
+	}
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"OtherError": {
+			err:  errBoom,
+			want: want{err: errBoom},
+		},
+		"ResourceGroupNotFound": {
+			err: notFound,
+			want: want{
+				err:        errors.Errorf(errFmtResourceGroupNotFound, "coolgroup"),
+				conditions: []runtimev1alpha1.Condition{WaitingForResourceGroup("coolgroup")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &fake.Managed{}
+			err := WaitForResourceGroup(o, "coolgroup", tc.err)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("WaitForResourceGroup(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, o.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("WaitForResourceGroup(...): -want conditions, +got conditions:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+// Error strings.
+const (
+	errNotYetChecked       = "Azure credentials have not yet been checked"
+	errListProviderConfigs = "cannot list ProviderConfigs"
+	errListProviders       = "cannot list Providers"
+	errGetCredentials      = "cannot get credentials secret"
+	errFmtCheckCredentials = "cannot authenticate to Azure using %s %q"
+	errNoneAuthenticate    = "no ProviderConfig or Provider can authenticate to Azure"
+)
+
+// ReasonCannotAuthenticate is the reason of the events recorded for a
+// ProviderConfig or Provider whose credentials cannot authenticate to Azure.
+const ReasonCannotAuthenticate event.Reason = "CannotAuthenticate"
+
+const (
+	// DefaultCredentialsCheckInterval is the default interval at which a
+	// CredentialsChecker checks credentials.
+	DefaultCredentialsCheckInterval = 1 * time.Minute
+
+	// credentialsCheckTimeout bounds the check of each ProviderConfig or
+	// Provider, so that one that hangs doesn't delay the others.
+	credentialsCheckTimeout = 30 * time.Second
+)
+
+// A CredentialsChecker periodically checks that the credentials referenced by
+// each ProviderConfig, and each deprecated Provider, can be used to
+// authenticate to Azure. It is intended to be added to a controller manager as
+// a runnable, and its Check method used as a readiness check.
+//
+// Each ProviderConfig or Provider is checked independently. A Warning event is
+// recorded for each one that cannot authenticate, but readiness fails only if
+// none of them can, so that one bad ProviderConfig doesn't make every replica
+// of the provider unready.
+type CredentialsChecker struct {
+	kube     client.Client
+	record   event.Recorder
+	interval time.Duration
+	timeout  time.Duration
+	newFn    func(credentials []byte) (*Client, error)
+	validate func(context.Context, *Client) error
+
+	mu  sync.RWMutex
+	err error
+}
+
+// NewCredentialsChecker returns a CredentialsChecker that checks credentials
+// at the supplied interval, recording events with the supplied recorder.
+func NewCredentialsChecker(c client.Client, r event.Recorder, interval time.Duration) *CredentialsChecker {
+	return &CredentialsChecker{
+		kube:     c,
+		record:   r,
+		interval: interval,
+		timeout:  credentialsCheckTimeout,
+		newFn:    NewClient,
+		validate: ValidateClient,
+		err:      errors.New(errNotYetChecked),
+	}
+}
+
+// Start checking credentials until the supplied channel is closed.
+func (cc *CredentialsChecker) Start(stop <-chan struct{}) error {
+	wait.Until(func() {
+		err := cc.check(context.Background())
+		cc.mu.Lock()
+		cc.err = err
+		cc.mu.Unlock()
+	}, cc.interval, stop)
+	return nil
+}
+
+// NeedLeaderElection returns false, so that credentials are checked (and
+// readiness reported) by every replica of the provider.
+func (cc *CredentialsChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// Check returns the error encountered by the most recent credentials check,
+// if any. It satisfies healthz.Checker.
+func (cc *CredentialsChecker) Check(_ *http.Request) error {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.err
+}
+
+// A credentialsSource is a ProviderConfig or Provider that references
+// credentials.
+type credentialsSource struct {
+	obj  runtime.Object
+	kind string
+	name string
+	ref  runtimev1alpha1.SecretKeySelector
+}
+
+func (cc *CredentialsChecker) check(ctx context.Context) error {
+	srcs, err := cc.sources(ctx)
+	if err != nil {
+		return err
+	}
+
+	var first error
+	ok := len(srcs) == 0
+	for _, src := range srcs {
+		err := cc.checkSource(ctx, src)
+		if err == nil {
+			ok = true
+			continue
+		}
+		cc.record.Event(src.obj, event.Warning(ReasonCannotAuthenticate, err))
+		if first == nil {
+			first = err
+		}
+	}
+	if ok {
+		return nil
+	}
+	return errors.Wrap(first, errNoneAuthenticate)
+}
+
+// sources returns the ProviderConfigs and Providers whose credentials should
+// be checked. Only credentials loaded from a secret can be checked.
+func (cc *CredentialsChecker) sources(ctx context.Context) ([]credentialsSource, error) {
+	ctx, cancel := context.WithTimeout(ctx, cc.timeout)
+	defer cancel()
+
+	pcl := &v1beta1.ProviderConfigList{}
+	if err := cc.kube.List(ctx, pcl); err != nil {
+		return nil, errors.Wrap(err, errListProviderConfigs)
+	}
+	pl := &v1alpha3.ProviderList{}
+	if err := cc.kube.List(ctx, pl); err != nil {
+		return nil, errors.Wrap(err, errListProviders)
+	}
+
+	srcs := make([]credentialsSource, 0, len(pcl.Items)+len(pl.Items))
+	for i := range pcl.Items {
+		pc := &pcl.Items[i]
+		ref := pc.Spec.Credentials.SecretRef
+		if pc.Spec.Credentials.Source != runtimev1alpha1.CredentialsSourceSecret || ref == nil {
+			continue
+		}
+		srcs = append(srcs, credentialsSource{obj: pc, kind: v1beta1.ProviderConfigKind, name: pc.GetName(), ref: *ref})
+	}
+	for i := range pl.Items {
+		p := &pl.Items[i]
+		srcs = append(srcs, credentialsSource{obj: p, kind: v1alpha3.ProviderKind, name: p.GetName(), ref: p.Spec.CredentialsSecretRef})
+	}
+	return srcs, nil
+}
+
+func (cc *CredentialsChecker) checkSource(ctx context.Context, src credentialsSource) error {
+	// Don't let one hung check delay the others.
+	ctx, cancel := context.WithTimeout(ctx, cc.timeout)
+	defer cancel()
+
+	s := &corev1.Secret{}
+	if err := cc.kube.Get(ctx, types.NamespacedName{Name: src.ref.Name, Namespace: src.ref.Namespace}, s); err != nil {
+		return errors.Wrapf(errors.Wrap(err, errGetCredentials), errFmtCheckCredentials, src.kind, src.name)
+	}
+	c, err := cc.newFn(s.Data[src.ref.Key])
+	if err != nil {
+		return errors.Wrapf(err, errFmtCheckCredentials, src.kind, src.name)
+	}
+	return errors.Wrapf(cc.validate(ctx, c), errFmtCheckCredentials, src.kind, src.name)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+// eventRecorder records the names of the objects it records events for, and
+// the events it records.
+type eventRecorder struct {
+	names  []string
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(obj runtime.Object, e event.Event) {
+	r.names = append(r.names, obj.(interface{ GetName() string }).GetName())
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestCredentialsCheckerCheck(t *testing.T) {
+	errBoom := errors.New("boom")
+	goodCreds := []byte("good-creds")
+	badCreds := []byte("bad-creds")
+
+	withPCs := func(src runtimev1alpha1.CredentialsSource, names ...string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			l, ok := obj.(*v1beta1.ProviderConfigList)
+			if !ok {
+				return nil
+			}
+			for _, n := range names {
+				pc := v1beta1.ProviderConfig{}
+				pc.SetName(n)
+				pc.Spec.Credentials.Source = src
+				pc.Spec.Credentials.SecretRef = &runtimev1alpha1.SecretKeySelector{
+					SecretReference: runtimev1alpha1.SecretReference{Name: n},
+					Key:             "creds",
+				}
+				l.Items = append(l.Items, pc)
+			}
+			return nil
+		}
+	}
+	withProviders := func(names ...string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			l, ok := obj.(*v1alpha3.ProviderList)
+			if !ok {
+				return nil
+			}
+			for _, n := range names {
+				p := v1alpha3.Provider{}
+				p.SetName(n)
+				p.Spec.CredentialsSecretRef = runtimev1alpha1.SecretKeySelector{
+					SecretReference: runtimev1alpha1.SecretReference{Name: n},
+					Key:             "creds",
+				}
+				l.Items = append(l.Items, p)
+			}
+			return nil
+		}
+	}
+
+	withCreds := func(obj runtime.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"creds": goodCreds}
+		return nil
+	}
+	// Secrets whose names start with "bad" contain bad credentials.
+	getCreds := func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		c := goodCreds
+		if strings.HasPrefix(key.Name, "bad") {
+			c = badCreds
+		}
+		obj.(*corev1.Secret).Data = map[string][]byte{"creds": c}
+		return nil
+	}
+	newFn := func(c []byte) (*Client, error) {
+		if string(c) == string(badCreds) {
+			return nil, errBoom
+		}
+		return &Client{}, nil
+	}
+
+	type fields struct {
+		kube     client.Client
+		newFn    func([]byte) (*Client, error)
+		validate func(context.Context, *Client) error
+	}
+
+	type want struct {
+		err    error
+		names  []string
+		events []event.Event
+	}
+
+	cases := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"ListProviderConfigsFailed": {
+			fields: fields{
+				kube: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					if _, ok := obj.(*v1beta1.ProviderConfigList); ok {
+						return errBoom
+					}
+					return nil
+				}},
+			},
+			want: want{err: errors.Wrap(errBoom, errListProviderConfigs)},
+		},
+		"ListProvidersFailed": {
+			fields: fields{
+				kube: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					if _, ok := obj.(*v1alpha3.ProviderList); ok {
+						return errBoom
+					}
+					return nil
+				}},
+			},
+			want: want{err: errors.Wrap(errBoom, errListProviders)},
+		},
+		"NoProviderConfigs": {
+			fields: fields{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil)},
+			},
+		},
+		"UnsupportedSource": {
+			fields: fields{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSource("InjectedIdentity"), "cool-pc"))},
+			},
+		},
+		"GetSecretFailed": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "cool-pc")),
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err:    errors.Wrap(errors.Wrapf(errors.Wrap(errBoom, errGetCredentials), errFmtCheckCredentials, v1beta1.ProviderConfigKind, "cool-pc"), errNoneAuthenticate),
+				names:  []string{"cool-pc"},
+				events: []event.Event{event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errors.Wrap(errBoom, errGetCredentials), errFmtCheckCredentials, v1beta1.ProviderConfigKind, "cool-pc"))},
+			},
+		},
+		"ValidateFailed": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "cool-pc")),
+					MockGet:  test.NewMockGetFn(nil, withCreds),
+				},
+				newFn:    newFn,
+				validate: func(_ context.Context, _ *Client) error { return errBoom },
+			},
+			want: want{
+				err:    errors.Wrap(errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "cool-pc"), errNoneAuthenticate),
+				names:  []string{"cool-pc"},
+				events: []event.Event{event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "cool-pc"))},
+			},
+		},
+		"SomeFailed": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "bad-pc", "cool-pc", "bad-pc-2")),
+					MockGet:  getCreds,
+				},
+				newFn:    newFn,
+				validate: func(_ context.Context, _ *Client) error { return nil },
+			},
+			want: want{
+				names: []string{"bad-pc", "bad-pc-2"},
+				events: []event.Event{
+					event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "bad-pc")),
+					event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "bad-pc-2")),
+				},
+			},
+		},
+		"AllFailed": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "bad-pc"), withProviders("bad-p")),
+					MockGet:  getCreds,
+				},
+				newFn:    newFn,
+				validate: func(_ context.Context, _ *Client) error { return nil },
+			},
+			want: want{
+				err:   errors.Wrap(errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "bad-pc"), errNoneAuthenticate),
+				names: []string{"bad-pc", "bad-p"},
+				events: []event.Event{
+					event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "bad-pc")),
+					event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1alpha3.ProviderKind, "bad-p")),
+				},
+			},
+		},
+		"ProviderSucceeded": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "bad-pc"), withProviders("cool-p")),
+					MockGet:  getCreds,
+				},
+				newFn:    newFn,
+				validate: func(_ context.Context, _ *Client) error { return nil },
+			},
+			want: want{
+				names:  []string{"bad-pc"},
+				events: []event.Event{event.Warning(ReasonCannotAuthenticate, errors.Wrapf(errBoom, errFmtCheckCredentials, v1beta1.ProviderConfigKind, "bad-pc"))},
+			},
+		},
+		"Success": {
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, withPCs(runtimev1alpha1.CredentialsSourceSecret, "cool-pc")),
+					MockGet:  test.NewMockGetFn(nil, withCreds),
+				},
+				newFn: func(c []byte) (*Client, error) {
+					if diff := cmp.Diff(goodCreds, c); diff != "" {
+						t.Errorf("newFn(...): -want, +got:\n%s", diff)
+					}
+					return &Client{}, nil
+				},
+				validate: func(_ context.Context, _ *Client) error { return nil },
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &eventRecorder{}
+			cc := &CredentialsChecker{kube: tc.fields.kube, record: r, timeout: credentialsCheckTimeout, newFn: tc.fields.newFn, validate: tc.fields.validate}
+			err := cc.check(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("check(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.names, r.names); diff != "" {
+				t.Errorf("check(...): -want event objects, +got event objects:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("check(...): -want events, +got events:\n%s", diff)
+			}
+		})
+	}
+}
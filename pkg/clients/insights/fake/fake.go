@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights/insightsapi"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+var _ insightsapi.DiagnosticSettingsClientAPI = &MockDiagnosticSettingsClient{}
+
+// MockDiagnosticSettingsClient is a fake implementation of
+// insights.DiagnosticSettingsClient.
+type MockDiagnosticSettingsClient struct {
+	insightsapi.DiagnosticSettingsClientAPI
+
+	MockCreateOrUpdate func(ctx context.Context, resourceURI string, parameters insights.DiagnosticSettingsResource, name string) (result insights.DiagnosticSettingsResource, err error)
+	MockDelete         func(ctx context.Context, resourceURI string, name string) (result autorest.Response, err error)
+	MockGet            func(ctx context.Context, resourceURI string, name string) (result insights.DiagnosticSettingsResource, err error)
+}
+
+// CreateOrUpdate calls the MockDiagnosticSettingsClient's MockCreateOrUpdate
+// method.
+func (c *MockDiagnosticSettingsClient) CreateOrUpdate(ctx context.Context, resourceURI string, parameters insights.DiagnosticSettingsResource, name string) (result insights.DiagnosticSettingsResource, err error) {
+	return c.MockCreateOrUpdate(ctx, resourceURI, parameters, name)
+}
+
+// Delete calls the MockDiagnosticSettingsClient's MockDelete method.
+func (c *MockDiagnosticSettingsClient) Delete(ctx context.Context, resourceURI string, name string) (result autorest.Response, err error) {
+	return c.MockDelete(ctx, resourceURI, name)
+}
+
+// Get calls the MockDiagnosticSettingsClient's MockGet method.
+func (c *MockDiagnosticSettingsClient) Get(ctx context.Context, resourceURI string, name string) (result insights.DiagnosticSettingsResource, err error) {
+	return c.MockGet(ctx, resourceURI, name)
+}
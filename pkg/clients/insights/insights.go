@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package insights
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/insights/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// Error strings.
+const (
+	errNoDestination = "at least one of workspaceId, storageAccountId, or eventHubAuthorizationRuleId must be specified"
+)
+
+// ValidateDiagnosticSetting returns an error if the supplied diagnostic
+// setting does not specify a destination for its logs and metrics.
+func ValidateDiagnosticSetting(d *v1alpha3.DiagnosticSetting) error {
+	p := d.Spec.DiagnosticSettingProperties
+	if p.WorkspaceID == nil && p.StorageAccountID == nil && p.EventHubAuthorizationRuleID == nil {
+		return errors.New(errNoDestination)
+	}
+	return nil
+}
+
+// NewDiagnosticSettingParameters returns an Azure diagnostic settings resource
+// from the supplied DiagnosticSetting.
+func NewDiagnosticSettingParameters(d *v1alpha3.DiagnosticSetting) insights.DiagnosticSettingsResource {
+	p := d.Spec.DiagnosticSettingProperties
+	logs := make([]insights.LogSettings, len(p.Logs))
+	for i, l := range p.Logs {
+		logs[i] = insights.LogSettings{
+			Category:        azure.ToStringPtr(l.Category),
+			Enabled:         to.BoolPtr(l.Enabled),
+			RetentionPolicy: newRetentionPolicy(l.RetentionPolicy),
+		}
+	}
+	metrics := make([]insights.MetricSettings, len(p.Metrics))
+	for i, m := range p.Metrics {
+		metrics[i] = insights.MetricSettings{
+			Category:        azure.ToStringPtr(m.Category),
+			Enabled:         to.BoolPtr(m.Enabled),
+			TimeGrain:       m.TimeGrain,
+			RetentionPolicy: newRetentionPolicy(m.RetentionPolicy),
+		}
+	}
+	return insights.DiagnosticSettingsResource{
+		DiagnosticSettings: &insights.DiagnosticSettings{
+			WorkspaceID:                 p.WorkspaceID,
+			LogAnalyticsDestinationType: p.LogAnalyticsDestinationType,
+			StorageAccountID:            p.StorageAccountID,
+			EventHubAuthorizationRuleID: p.EventHubAuthorizationRuleID,
+			EventHubName:                p.EventHubName,
+			Logs:                        &logs,
+			Metrics:                     &metrics,
+		},
+	}
+}
+
+func newRetentionPolicy(p *v1alpha3.RetentionPolicy) *insights.RetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &insights.RetentionPolicy{Enabled: to.BoolPtr(p.Enabled), Days: p.Days}
+}
+
+// category is the observable state of a log or metric category.
+type category struct {
+	enabled   bool
+	timeGrain string
+	retention *insights.RetentionPolicy
+}
+
+func logCategories(s *[]insights.LogSettings) map[string]category {
+	c := map[string]category{}
+	if s == nil {
+		return c
+	}
+	for _, l := range *s {
+		c[strings.ToLower(to.String(l.Category))] = category{enabled: to.Bool(l.Enabled), retention: l.RetentionPolicy}
+	}
+	return c
+}
+
+func metricCategories(s *[]insights.MetricSettings) map[string]category {
+	c := map[string]category{}
+	if s == nil {
+		return c
+	}
+	for _, m := range *s {
+		c[strings.ToLower(to.String(m.Category))] = category{enabled: to.Bool(m.Enabled), timeGrain: to.String(m.TimeGrain), retention: m.RetentionPolicy}
+	}
+	return c
+}
+
+// categoriesNeedUpdate returns true if the observed categories differ from
+// the desired categories. Azure reports every category supported by a
+// resource, so observed categories that are not desired must be disabled.
+func categoriesNeedUpdate(desired, observed map[string]category) bool {
+	for name, o := range observed {
+		if _, ok := desired[name]; !ok && o.enabled {
+			return true
+		}
+	}
+	for name, d := range desired {
+		o, ok := observed[name]
+		if !ok {
+			if d.enabled {
+				return true
+			}
+			continue
+		}
+		if d.enabled != o.enabled {
+			return true
+		}
+		if d.timeGrain != "" && !strings.EqualFold(d.timeGrain, o.timeGrain) {
+			return true
+		}
+		if retentionNeedsUpdate(d.retention, o.retention) {
+			return true
+		}
+	}
+	return false
+}
+
+func retentionNeedsUpdate(desired, observed *insights.RetentionPolicy) bool {
+	if desired == nil {
+		return false
+	}
+	if observed == nil || to.Bool(desired.Enabled) != to.Bool(observed.Enabled) {
+		return true
+	}
+	return desired.Days != nil && to.Int32(desired.Days) != to.Int32(observed.Days)
+}
+
+// DiagnosticSettingNeedsUpdate returns true if the supplied Azure diagnostic
+// settings resource differs from the supplied DiagnosticSetting.
+func DiagnosticSettingNeedsUpdate(d *v1alpha3.DiagnosticSetting, az insights.DiagnosticSettingsResource) bool {
+	if az.DiagnosticSettings == nil {
+		return true
+	}
+	up := NewDiagnosticSettingParameters(d).DiagnosticSettings
+
+	switch {
+	case !strings.EqualFold(to.String(up.WorkspaceID), to.String(az.WorkspaceID)):
+		return true
+	case !strings.EqualFold(to.String(up.StorageAccountID), to.String(az.StorageAccountID)):
+		return true
+	case !strings.EqualFold(to.String(up.EventHubAuthorizationRuleID), to.String(az.EventHubAuthorizationRuleID)):
+		return true
+	case to.String(up.EventHubName) != to.String(az.EventHubName):
+		return true
+	case to.String(up.LogAnalyticsDestinationType) != to.String(az.LogAnalyticsDestinationType):
+		return true
+	case categoriesNeedUpdate(logCategories(up.Logs), logCategories(az.Logs)):
+		return true
+	case categoriesNeedUpdate(metricCategories(up.Metrics), metricCategories(az.Metrics)):
+		return true
+	}
+
+	return false
+}
+
+// UpdateDiagnosticSettingStatusFromAzure updates the status related to the
+// external Azure diagnostic setting in the DiagnosticSettingStatus.
+func UpdateDiagnosticSettingStatusFromAzure(d *v1alpha3.DiagnosticSetting, az insights.DiagnosticSettingsResource) {
+	d.Status.ID = azure.ToString(az.ID)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package insights
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/insights/v1alpha3"
+)
+
+const (
+	workspaceID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.OperationalInsights/workspaces/ws"
+	storageID   = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa"
+)
+
+func diagnosticSetting(p v1alpha3.DiagnosticSettingProperties) *v1alpha3.DiagnosticSetting {
+	return &v1alpha3.DiagnosticSetting{Spec: v1alpha3.DiagnosticSettingSpec{DiagnosticSettingProperties: p}}
+}
+
+func TestValidateDiagnosticSetting(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.DiagnosticSettingProperties
+		want error
+	}{
+		"NoDestination": {
+			p:    v1alpha3.DiagnosticSettingProperties{Logs: []v1alpha3.LogSetting{{Category: "MySqlSlowLogs", Enabled: true}}},
+			want: errors.New(errNoDestination),
+		},
+		"Workspace": {
+			p: v1alpha3.DiagnosticSettingProperties{WorkspaceID: to.StringPtr(workspaceID)},
+		},
+		"StorageAccount": {
+			p: v1alpha3.DiagnosticSettingProperties{StorageAccountID: to.StringPtr(storageID)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateDiagnosticSetting(diagnosticSetting(tc.p))
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateDiagnosticSetting(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewDiagnosticSettingParameters(t *testing.T) {
+	days := int32(30)
+	p := v1alpha3.DiagnosticSettingProperties{
+		StorageAccountID: to.StringPtr(storageID),
+		Logs: []v1alpha3.LogSetting{{
+			Category:        "MySqlSlowLogs",
+			Enabled:         true,
+			RetentionPolicy: &v1alpha3.RetentionPolicy{Enabled: true, Days: &days},
+		}},
+		Metrics: []v1alpha3.MetricSetting{{Category: "AllMetrics", Enabled: true, TimeGrain: to.StringPtr("PT1M")}},
+	}
+	want := insights.DiagnosticSettingsResource{
+		DiagnosticSettings: &insights.DiagnosticSettings{
+			StorageAccountID: to.StringPtr(storageID),
+			Logs: &[]insights.LogSettings{{
+				Category:        to.StringPtr("MySqlSlowLogs"),
+				Enabled:         to.BoolPtr(true),
+				RetentionPolicy: &insights.RetentionPolicy{Enabled: to.BoolPtr(true), Days: &days},
+			}},
+			Metrics: &[]insights.MetricSettings{{
+				Category:  to.StringPtr("AllMetrics"),
+				Enabled:   to.BoolPtr(true),
+				TimeGrain: to.StringPtr("PT1M"),
+			}},
+		},
+	}
+
+	got := NewDiagnosticSettingParameters(diagnosticSetting(p))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewDiagnosticSettingParameters(...): -want, +got\n%s", diff)
+	}
+}
+
+func TestDiagnosticSettingNeedsUpdate(t *testing.T) {
+	d := diagnosticSetting(v1alpha3.DiagnosticSettingProperties{
+		WorkspaceID: to.StringPtr(workspaceID),
+		Logs:        []v1alpha3.LogSetting{{Category: "MySqlSlowLogs", Enabled: true}},
+		Metrics:     []v1alpha3.MetricSetting{{Category: "AllMetrics", Enabled: true}},
+	})
+
+	cases := map[string]struct {
+		az   insights.DiagnosticSettingsResource
+		want bool
+	}{
+		"UpToDate": {
+			az: insights.DiagnosticSettingsResource{DiagnosticSettings: &insights.DiagnosticSettings{
+				WorkspaceID: to.StringPtr(strings.ToLower(workspaceID)),
+				Logs: &[]insights.LogSettings{
+					{Category: to.StringPtr("MySqlSlowLogs"), Enabled: to.BoolPtr(true)},
+					{Category: to.StringPtr("MySqlAuditLogs"), Enabled: to.BoolPtr(false)},
+				},
+				Metrics: &[]insights.MetricSettings{{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(true), TimeGrain: to.StringPtr("PT1M")}},
+			}},
+			want: false,
+		},
+		"NoProperties": {
+			az:   insights.DiagnosticSettingsResource{},
+			want: true,
+		},
+		"DestinationChanged": {
+			az: insights.DiagnosticSettingsResource{DiagnosticSettings: &insights.DiagnosticSettings{
+				StorageAccountID: to.StringPtr(storageID),
+				Logs:             &[]insights.LogSettings{{Category: to.StringPtr("MySqlSlowLogs"), Enabled: to.BoolPtr(true)}},
+				Metrics:          &[]insights.MetricSettings{{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(true)}},
+			}},
+			want: true,
+		},
+		"CategoryDisabled": {
+			az: insights.DiagnosticSettingsResource{DiagnosticSettings: &insights.DiagnosticSettings{
+				WorkspaceID: to.StringPtr(workspaceID),
+				Logs:        &[]insights.LogSettings{{Category: to.StringPtr("MySqlSlowLogs"), Enabled: to.BoolPtr(false)}},
+				Metrics:     &[]insights.MetricSettings{{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(true)}},
+			}},
+			want: true,
+		},
+		"UndesiredCategoryEnabled": {
+			az: insights.DiagnosticSettingsResource{DiagnosticSettings: &insights.DiagnosticSettings{
+				WorkspaceID: to.StringPtr(workspaceID),
+				Logs: &[]insights.LogSettings{
+					{Category: to.StringPtr("MySqlSlowLogs"), Enabled: to.BoolPtr(true)},
+					{Category: to.StringPtr("MySqlAuditLogs"), Enabled: to.BoolPtr(true)},
+				},
+				Metrics: &[]insights.MetricSettings{{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(true)}},
+			}},
+			want: true,
+		},
+		"DesiredCategoryMissing": {
+			az: insights.DiagnosticSettingsResource{DiagnosticSettings: &insights.DiagnosticSettings{
+				WorkspaceID: to.StringPtr(workspaceID),
+				Logs:        &[]insights.LogSettings{{Category: to.StringPtr("MySqlSlowLogs"), Enabled: to.BoolPtr(true)}},
+			}},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DiagnosticSettingNeedsUpdate(d, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DiagnosticSettingNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
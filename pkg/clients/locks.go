@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// TypeLocked resources have Azure management locks that prevent them from
+// being updated or deleted.
+const TypeLocked runtimev1alpha1.ConditionType = "Locked"
+
+// Reasons a resource is or is not locked.
+const (
+	ReasonLocked   runtimev1alpha1.ConditionReason = "ManagementLocked"
+	ReasonUnlocked runtimev1alpha1.ConditionReason = "ManagementUnlocked"
+)
+
+// Error strings.
+const (
+	errListLocks = "cannot list management locks"
+	errFmtLocked = "cannot %s Azure resource while it has management locks: %s"
+)
+
+const msgFmtLocked = "Azure resource cannot be %s while it has management locks: %s"
+
+// Locked returns a condition that indicates the Azure resource is prevented
+// from being updated or deleted by the supplied management locks.
+func Locked(l []locks.ManagementLockObject) runtimev1alpha1.Condition {
+	verb := "deleted"
+	if lockLevel(l) == locks.ReadOnly {
+		verb = "updated or deleted"
+	}
+	return runtimev1alpha1.Condition{
+		Type:               TypeLocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLocked,
+		Message:            fmt.Sprintf(msgFmtLocked, verb, describeLocks(l)),
+	}
+}
+
+// Unlocked returns a condition that indicates the Azure resource is no longer
+// prevented from being updated or deleted by management locks.
+func Unlocked() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeLocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnlocked,
+	}
+}
+
+// SetLockedCondition sets the Locked condition of the supplied resource to
+// reflect the supplied management locks. A resource that was never locked is
+// not given a Locked condition.
+func SetLockedCondition(cr resource.Conditioned, l []locks.ManagementLockObject) {
+	switch {
+	case IsLocked(l):
+		cr.SetConditions(Locked(l))
+	case cr.GetCondition(TypeLocked).Status == corev1.ConditionTrue:
+		cr.SetConditions(Unlocked())
+	}
+}
+
+// IsLocked returns true if any of the supplied locks prevent an Azure resource
+// from being deleted.
+func IsLocked(l []locks.ManagementLockObject) bool {
+	return lockLevel(l) != locks.NotSpecified
+}
+
+// IsReadOnly returns true if any of the supplied locks prevent an Azure
+// resource from being updated.
+func IsReadOnly(l []locks.ManagementLockObject) bool {
+	return lockLevel(l) == locks.ReadOnly
+}
+
+// NewLockedError returns an error that explains the supplied operation cannot
+// be performed because of the supplied locks.
+func NewLockedError(op string, l []locks.ManagementLockObject) error {
+	return errors.Errorf(errFmtLocked, op, describeLocks(l))
+}
+
+// lockLevel returns the most restrictive level of the supplied locks.
+func lockLevel(l []locks.ManagementLockObject) locks.LockLevel {
+	level := locks.NotSpecified
+	for _, lock := range l {
+		if lock.ManagementLockProperties == nil {
+			continue
+		}
+		switch lock.Level {
+		case locks.ReadOnly:
+			return locks.ReadOnly
+		case locks.CanNotDelete:
+			level = locks.CanNotDelete
+		}
+	}
+	return level
+}
+
+// describeLocks returns a stable, human readable description of the supplied
+// locks, e.g. "do-not-delete (CanNotDelete)".
+func describeLocks(l []locks.ManagementLockObject) string {
+	d := make([]string, 0, len(l))
+	for _, lock := range l {
+		level := locks.NotSpecified
+		if lock.ManagementLockProperties != nil {
+			level = lock.Level
+		}
+		d = append(d, fmt.Sprintf("%s (%s)", to.String(lock.Name), level))
+	}
+	sort.Strings(d)
+	return strings.Join(d, ", ")
+}
+
+// A LockLister lists the management locks that apply to an Azure resource,
+// including those inherited from its resource group or subscription.
+type LockLister interface {
+	ListLocks(ctx context.Context, id string) ([]locks.ManagementLockObject, error)
+}
+
+// A LockListerFn is a function that satisfies the LockLister interface.
+type LockListerFn func(ctx context.Context, id string) ([]locks.ManagementLockObject, error)
+
+// ListLocks that apply to the supplied Azure resource.
+func (fn LockListerFn) ListLocks(ctx context.Context, id string) ([]locks.ManagementLockObject, error) {
+	return fn(ctx, id)
+}
+
+// NewLockLister returns a LockLister that uses the supplied Azure management
+// locks client.
+func NewLockLister(c locks.ManagementLocksClient) LockLister {
+	return LockListerFn(func(ctx context.Context, id string) ([]locks.ManagementLockObject, error) {
+		it, err := c.ListByScopeComplete(ctx, id, "atScope()")
+		if err != nil {
+			return nil, err
+		}
+		var l []locks.ManagementLockObject
+		for ; it.NotDone(); err = it.NextWithContext(ctx) {
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, it.Value())
+		}
+		return l, nil
+	})
+}
+
+// NewLockAwareConnecter returns a managed.ExternalConnecter whose clients
+// observe the management locks that apply to the Azure resource identified by
+// the supplied function, if any. The clients report locks via the Locked
+// condition. They return an error rather than asking Azure to update a read
+// only resource, and do not ask Azure to delete a locked resource.
+func NewLockAwareConnecter(c managed.ExternalConnecter, kube client.Client, id func(resource.Managed) string) managed.ExternalConnecter {
+	return &lockAwareConnecter{ExternalConnecter: c, kube: kube, id: id}
+}
+
+type lockAwareConnecter struct {
+	managed.ExternalConnecter
+	kube client.Client
+	id   func(resource.Managed) string
+}
+
+func (c *lockAwareConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	creds, auth, err := GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	lc := locks.NewManagementLocksClient(creds[CredentialsKeySubscriptionID])
+	lc.Authorizer = auth
+	return &lockAwareExternal{ExternalClient: e, locks: NewLockLister(lc), id: c.id}, nil
+}
+
+type lockAwareExternal struct {
+	managed.ExternalClient
+	locks LockLister
+	id    func(resource.Managed) string
+
+	// observed are the locks that applied to the Azure resource when it was
+	// last observed.
+	observed []locks.ManagementLockObject
+}
+
+func (e *lockAwareExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	var l []locks.ManagementLockObject
+	if id := e.id(mg); o.ResourceExists && id != "" {
+		if l, err = e.locks.ListLocks(ctx, id); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errListLocks)
+		}
+	}
+	e.observed = l
+	SetLockedCondition(mg, l)
+	return o, nil
+}
+
+func (e *lockAwareExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if IsReadOnly(e.observed) {
+		return managed.ExternalUpdate{}, NewLockedError("update", e.observed)
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+// Delete does not attempt to delete a locked Azure resource. The Locked
+// condition explains why it has not been deleted, and the resource will be
+// deleted once it is unlocked.
+func (e *lockAwareExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsLocked(e.observed) {
+		return nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
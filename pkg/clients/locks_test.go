@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func lock(name string, level locks.LockLevel) locks.ManagementLockObject {
+	return locks.ManagementLockObject{Name: to.StringPtr(name), ManagementLockProperties: &locks.ManagementLockProperties{Level: level}}
+}
+
+func TestLockAwareObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	readOnly := []locks.ManagementLockObject{lock("frozen", locks.ReadOnly), lock("keep", locks.CanNotDelete)}
+	canNotDelete := []locks.ManagementLockObject{lock("keep", locks.CanNotDelete)}
+	id := func(resource.Managed) string {
+		return "/subscriptions/cool/resourceGroups/group/providers/Microsoft.Cache/Redis/cool"
+	}
+	observe := func(o managed.ExternalObservation) func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+		return func(context.Context, resource.Managed) (managed.ExternalObservation, error) { return o, nil }
+	}
+	listed := func(l []locks.ManagementLockObject, err error) LockLister {
+		return LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return l, err })
+	}
+
+	type want struct {
+		o          managed.ExternalObservation
+		err        error
+		conditions []runtimev1alpha1.Condition
+	}
+
+	cases := map[string]struct {
+		observe func(context.Context, resource.Managed) (managed.ExternalObservation, error)
+		locks   LockLister
+		id      func(resource.Managed) string
+		mg      *fake.Managed
+		want    want
+	}{
+		"DoesNotExist": {
+			observe: observe(managed.ExternalObservation{}),
+			id:      id,
+			mg:      &fake.Managed{},
+		},
+		"UnknownID": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			id:      func(resource.Managed) string { return "" },
+			mg:      &fake.Managed{},
+			want:    want{o: managed.ExternalObservation{ResourceExists: true}},
+		},
+		"ListLocksError": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			locks:   listed(nil, errBoom),
+			id:      id,
+			mg:      &fake.Managed{},
+			want:    want{err: errors.Wrap(errBoom, errListLocks)},
+		},
+		"NotLocked": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			locks:   listed(nil, nil),
+			id:      id,
+			mg:      &fake.Managed{},
+			want:    want{o: managed.ExternalObservation{ResourceExists: true}},
+		},
+		"Unlocked": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			locks:   listed(nil, nil),
+			id:      id,
+			mg:      &fake.Managed{ConditionedStatus: *runtimev1alpha1.NewConditionedStatus(Locked(canNotDelete))},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true},
+				conditions: []runtimev1alpha1.Condition{Unlocked()},
+			},
+		},
+		"CanNotDelete": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			locks:   listed(canNotDelete, nil),
+			id:      id,
+			mg:      &fake.Managed{},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true},
+				conditions: []runtimev1alpha1.Condition{Locked(canNotDelete)},
+			},
+		},
+		"DeletedWhileLocked": {
+			observe: observe(managed.ExternalObservation{}),
+			id:      id,
+			mg:      &fake.Managed{ConditionedStatus: *runtimev1alpha1.NewConditionedStatus(Locked(canNotDelete))},
+			want:    want{conditions: []runtimev1alpha1.Condition{Unlocked()}},
+		},
+		"ReadOnly": {
+			observe: observe(managed.ExternalObservation{ResourceExists: true}),
+			locks:   listed(readOnly, nil),
+			id:      id,
+			mg:      &fake.Managed{},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true},
+				conditions: []runtimev1alpha1.Condition{Locked(readOnly)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &lockAwareExternal{ExternalClient: &managed.ExternalClientFns{ObserveFn: tc.observe}, locks: tc.locks, id: tc.id}
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, tc.mg.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want conditions, +got conditions\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLockAwareUpdateAndDelete(t *testing.T) {
+	readOnly := []locks.ManagementLockObject{lock("frozen", locks.ReadOnly)}
+	canNotDelete := []locks.ManagementLockObject{lock("keep", locks.CanNotDelete)}
+
+	type want struct {
+		update  error
+		deleted bool
+	}
+
+	cases := map[string]struct {
+		observed []locks.ManagementLockObject
+		want     want
+	}{
+		"NotLocked": {
+			want: want{deleted: true},
+		},
+		"CanNotDelete": {
+			observed: canNotDelete,
+		},
+		"ReadOnly": {
+			observed: readOnly,
+			want: want{
+				update: errors.Errorf(errFmtLocked, "update", "frozen (ReadOnly)"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			e := &lockAwareExternal{
+				ExternalClient: &managed.ExternalClientFns{
+					UpdateFn: func(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+						return managed.ExternalUpdate{}, nil
+					},
+					DeleteFn: func(context.Context, resource.Managed) error {
+						deleted = true
+						return nil
+					},
+				},
+				observed: tc.observed,
+			}
+			_, err := e.Update(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want.update, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error\n%s", diff)
+			}
+			if err := e.Delete(context.Background(), &fake.Managed{}); err != nil {
+				t.Errorf("Delete(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("Delete(...): -want deleted, +got deleted\n%s", diff)
+			}
+		})
+	}
+}
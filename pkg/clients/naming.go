@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtInvalidName = "%q is not a valid Azure %s name: %s"
+)
+
+// A nameRule describes the names Azure accepts for a kind of resource.
+type nameRule struct {
+	min         int
+	max         int
+	pattern     *regexp.Regexp
+	description string
+}
+
+// nameRules are the naming constraints Azure imposes on each kind of resource
+// whose name may be set via the external name annotation. See
+// https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+var nameRules = map[string]nameRule{
+	"VirtualNetwork": {
+		min:         2,
+		max:         64,
+		pattern:     regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9_])?$`),
+		description: "names must be 2-64 alphanumerics, underscores, periods, or hyphens, start with an alphanumeric, and end with an alphanumeric or underscore",
+	},
+	"Subnet": {
+		min:         1,
+		max:         80,
+		pattern:     regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9_])?$`),
+		description: "names must be 1-80 alphanumerics, underscores, periods, or hyphens, start with an alphanumeric, and end with an alphanumeric or underscore",
+	},
+	"Account": {
+		min:         3,
+		max:         24,
+		pattern:     regexp.MustCompile(`^[a-z0-9]+$`),
+		description: "names must be 3-24 lowercase letters or numbers",
+	},
+	"Redis": {
+		min:         1,
+		max:         63,
+		pattern:     regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`),
+		description: "names must be 1-63 alphanumerics or hyphens, start and end with an alphanumeric, and not contain consecutive hyphens",
+	},
+}
+
+// ValidateName returns an error if Azure would not accept the supplied name for
+// the supplied kind of resource. Names of kinds without known constraints are
+// always considered valid.
+func ValidateName(kind, name string) error {
+	r, ok := nameRules[kind]
+	if !ok {
+		return nil
+	}
+	if len(name) < r.min || len(name) > r.max || !r.pattern.MatchString(name) {
+		return errors.Errorf(errFmtInvalidName, name, kind, r.description)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestValidateName(t *testing.T) {
+	cases := map[string]struct {
+		kind string
+		name string
+		want error
+	}{
+		"UnknownKind": {
+			kind: "Unknown",
+			name: "-_-",
+		},
+		"ValidVirtualNetwork": {
+			kind: "VirtualNetwork",
+			name: "prod.vnet_01",
+		},
+		"VirtualNetworkEndsWithPeriod": {
+			kind: "VirtualNetwork",
+			name: "vnet.",
+			want: errors.Errorf(errFmtInvalidName, "vnet.", "VirtualNetwork", nameRules["VirtualNetwork"].description),
+		},
+		"ValidSubnet": {
+			kind: "Subnet",
+			name: "a",
+		},
+		"SubnetTooLong": {
+			kind: "Subnet",
+			name: strings.Repeat("a", 81),
+			want: errors.Errorf(errFmtInvalidName, strings.Repeat("a", 81), "Subnet", nameRules["Subnet"].description),
+		},
+		"ValidAccount": {
+			kind: "Account",
+			name: "coolaccount01",
+		},
+		"AccountUppercase": {
+			kind: "Account",
+			name: "CoolAccount",
+			want: errors.Errorf(errFmtInvalidName, "CoolAccount", "Account", nameRules["Account"].description),
+		},
+		"AccountTooShort": {
+			kind: "Account",
+			name: "ab",
+			want: errors.Errorf(errFmtInvalidName, "ab", "Account", nameRules["Account"].description),
+		},
+		"ValidRedis": {
+			kind: "Redis",
+			name: "cool-redis-1",
+		},
+		"RedisConsecutiveHyphens": {
+			kind: "Redis",
+			name: "cool--redis",
+			want: errors.Errorf(errFmtInvalidName, "cool--redis", "Redis", nameRules["Redis"].description),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateName(tc.kind, tc.name)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateName(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -86,3 +86,57 @@ func (c *MockSubnetsClient) Get(ctx context.Context, resourceGroupName string, v
 func (c *MockSubnetsClient) List(ctx context.Context, resourceGroupName string, virtualNetworkName string) (result network.SubnetListResultPage, err error) {
 	return c.MockList(ctx, resourceGroupName, virtualNetworkName)
 }
+
+var _ networkapi.NatGatewaysClientAPI = &MockNatGatewaysClient{}
+
+// MockNatGatewaysClient is a fake implementation of network.NatGatewaysClient.
+type MockNatGatewaysClient struct {
+	networkapi.NatGatewaysClientAPI
+
+	MockCreateOrUpdate func(ctx context.Context, resourceGroupName string, natGatewayName string, parameters network.NatGateway) (result network.NatGatewaysCreateOrUpdateFuture, err error)
+	MockDelete         func(ctx context.Context, resourceGroupName string, natGatewayName string) (result network.NatGatewaysDeleteFuture, err error)
+	MockGet            func(ctx context.Context, resourceGroupName string, natGatewayName string, expand string) (result network.NatGateway, err error)
+}
+
+// CreateOrUpdate calls the MockNatGatewaysClient's MockCreateOrUpdate method.
+func (c *MockNatGatewaysClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, natGatewayName string, parameters network.NatGateway) (result network.NatGatewaysCreateOrUpdateFuture, err error) {
+	return c.MockCreateOrUpdate(ctx, resourceGroupName, natGatewayName, parameters)
+}
+
+// Delete calls the MockNatGatewaysClient's MockDelete method.
+func (c *MockNatGatewaysClient) Delete(ctx context.Context, resourceGroupName string, natGatewayName string) (result network.NatGatewaysDeleteFuture, err error) {
+	return c.MockDelete(ctx, resourceGroupName, natGatewayName)
+}
+
+// Get calls the MockNatGatewaysClient's MockGet method.
+func (c *MockNatGatewaysClient) Get(ctx context.Context, resourceGroupName string, natGatewayName string, expand string) (result network.NatGateway, err error) {
+	return c.MockGet(ctx, resourceGroupName, natGatewayName, expand)
+}
+
+var _ networkapi.ServiceEndpointPoliciesClientAPI = &MockServiceEndpointPoliciesClient{}
+
+// MockServiceEndpointPoliciesClient is a fake implementation of
+// network.ServiceEndpointPoliciesClient.
+type MockServiceEndpointPoliciesClient struct {
+	networkapi.ServiceEndpointPoliciesClientAPI
+
+	MockCreateOrUpdate func(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string, parameters network.ServiceEndpointPolicy) (result network.ServiceEndpointPoliciesCreateOrUpdateFuture, err error)
+	MockDelete         func(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string) (result network.ServiceEndpointPoliciesDeleteFuture, err error)
+	MockGet            func(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string, expand string) (result network.ServiceEndpointPolicy, err error)
+}
+
+// CreateOrUpdate calls the MockServiceEndpointPoliciesClient's
+// MockCreateOrUpdate method.
+func (c *MockServiceEndpointPoliciesClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string, parameters network.ServiceEndpointPolicy) (result network.ServiceEndpointPoliciesCreateOrUpdateFuture, err error) {
+	return c.MockCreateOrUpdate(ctx, resourceGroupName, serviceEndpointPolicyName, parameters)
+}
+
+// Delete calls the MockServiceEndpointPoliciesClient's MockDelete method.
+func (c *MockServiceEndpointPoliciesClient) Delete(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string) (result network.ServiceEndpointPoliciesDeleteFuture, err error) {
+	return c.MockDelete(ctx, resourceGroupName, serviceEndpointPolicyName)
+}
+
+// Get calls the MockServiceEndpointPoliciesClient's MockGet method.
+func (c *MockServiceEndpointPoliciesClient) Get(ctx context.Context, resourceGroupName string, serviceEndpointPolicyName string, expand string) (result network.ServiceEndpointPolicy, err error) {
+	return c.MockGet(ctx, resourceGroupName, serviceEndpointPolicyName, expand)
+}
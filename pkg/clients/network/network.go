@@ -17,47 +17,400 @@ limitations under the License.
 package network
 
 import (
+	"fmt"
+	"net"
+	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	networkmgmt "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 )
 
+// Error strings.
+const (
+	errEncryptionEnforcementDisabled = "encryption enforcement may only be set when encryption is enabled"
+	errNoAddressPrefix               = "one of addressPrefix or addressPrefixes must be set"
+	errBothAddressPrefixes           = "addressPrefix and addressPrefixes may not both be set"
+	errFmtInlineSubnet               = "invalid inline subnet %q"
+	errFmtInvalidBGPCommunity        = "invalid BGP community %q: must be of the form 12076:<value>, where value is between 20000 and 49999"
+)
+
 // NewVirtualNetworkParameters returns an Azure VirtualNetwork object from a virtual network spec
 func NewVirtualNetworkParameters(v *v1alpha3.VirtualNetwork) networkmgmt.VirtualNetwork {
+	var plan *networkmgmt.SubResource
+	if v.Spec.VirtualNetworkPropertiesFormat.DDOSProtectionPlanID != nil {
+		plan = &networkmgmt.SubResource{ID: v.Spec.VirtualNetworkPropertiesFormat.DDOSProtectionPlanID}
+	}
 	return networkmgmt.VirtualNetwork{
 		Location: azure.ToStringPtr(v.Spec.Location),
 		Tags:     azure.ToStringPtrMap(v.Spec.Tags),
 		VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
 			EnableDdosProtection: azure.ToBoolPtr(v.Spec.VirtualNetworkPropertiesFormat.EnableDDOSProtection, azure.FieldRequired),
 			EnableVMProtection:   azure.ToBoolPtr(v.Spec.VirtualNetworkPropertiesFormat.EnableVMProtection, azure.FieldRequired),
+			DdosProtectionPlan:   plan,
 			AddressSpace: &networkmgmt.AddressSpace{
-				AddressPrefixes: &v.Spec.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes,
+				AddressPrefixes: azure.ToStringArrayPtr(v.Spec.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes),
 			},
+			Subnets: NewInlineSubnets(v.Spec.VirtualNetworkPropertiesFormat.Subnets),
 		},
 	}
 }
 
+// NewInlineSubnets converts the supplied inline subnets to Azure Subnets.
+func NewInlineSubnets(in []v1alpha3.InlineSubnet) *[]networkmgmt.Subnet {
+	if len(in) == 0 {
+		return nil
+	}
+	subnets := make([]networkmgmt.Subnet, len(in))
+	for i, s := range in {
+		subnets[i] = networkmgmt.Subnet{
+			Name:                   azure.ToStringPtr(s.Name),
+			SubnetPropertiesFormat: newSubnetPropertiesFormat(s.SubnetPropertiesFormat),
+		}
+	}
+	return &subnets
+}
+
+// PreserveSubnets adds the subnets of the supplied Azure virtual network that
+// are not declared inline to the supplied virtual network parameters. Azure
+// deletes any subnet that is omitted when a virtual network is updated, which
+// would otherwise delete subnets managed by Subnet resources. The NAT gateway
+// associations of inline subnets that don't specify one are also preserved.
+func PreserveSubnets(up *networkmgmt.VirtualNetwork, az networkmgmt.VirtualNetwork) {
+	if az.VirtualNetworkPropertiesFormat == nil || az.Subnets == nil {
+		return
+	}
+	var subnets []networkmgmt.Subnet
+	if up.Subnets != nil {
+		subnets = *up.Subnets
+	}
+	for i := range subnets {
+		if existing := findSubnet(*az.Subnets, azure.ToString(subnets[i].Name)); existing != nil {
+			PreserveSubnetNATGateway(subnets[i].SubnetPropertiesFormat, existing.SubnetPropertiesFormat)
+		}
+	}
+	for _, existing := range *az.Subnets {
+		if findSubnet(subnets, azure.ToString(existing.Name)) == nil {
+			subnets = append(subnets, existing)
+		}
+	}
+	up.Subnets = &subnets
+}
+
+// PreserveSubnetNATGateway associates the supplied desired subnet properties
+// with the NAT gateway of the supplied observed subnet properties, unless the
+// desired properties specify a NAT gateway. A subnet's NAT gateway association
+// is only managed when it is specified.
+func PreserveSubnetNATGateway(up, az *networkmgmt.SubnetPropertiesFormat) {
+	if up == nil || az == nil || up.NatGateway != nil {
+		return
+	}
+	up.NatGateway = az.NatGateway
+}
+
+// InlineSubnetConflicts returns true if the supplied Subnet manages a subnet
+// that is also declared inline by the supplied VirtualNetwork.
+func InlineSubnetConflicts(v *v1alpha3.VirtualNetwork, s *v1alpha3.Subnet) bool {
+	if s.Spec.ResourceGroupName != v.Spec.ResourceGroupName || s.Spec.VirtualNetworkName != meta.GetExternalName(v) {
+		return false
+	}
+	for _, in := range v.Spec.VirtualNetworkPropertiesFormat.Subnets {
+		if strings.EqualFold(in.Name, meta.GetExternalName(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+func findSubnet(subnets []networkmgmt.Subnet, name string) *networkmgmt.Subnet {
+	for i := range subnets {
+		if strings.EqualFold(azure.ToString(subnets[i].Name), name) {
+			return &subnets[i]
+		}
+	}
+	return nil
+}
+
 // VirtualNetworkNeedsUpdate determines if a virtual network need to be updated
-func VirtualNetworkNeedsUpdate(kube *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork) bool {
+func VirtualNetworkNeedsUpdate(kube *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork, ext VirtualNetworkExtensions, ignored azure.IgnoredTags) bool {
+	return len(VirtualNetworkDrift(kube, az, ext, ignored)) > 0
+}
+
+// VirtualNetworkDrift returns the fields of the supplied Azure virtual network
+// that differ from those declared by the supplied VirtualNetwork, disregarding
+// the supplied ignored tags.
+func VirtualNetworkDrift(kube *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork, ext VirtualNetworkExtensions, ignored azure.IgnoredTags) []string {
 	up := NewVirtualNetworkParameters(kube)
+	var drift []string
+
+	if addressSpaceNeedsUpdate(up.VirtualNetworkPropertiesFormat.AddressSpace, az.VirtualNetworkPropertiesFormat.AddressSpace) {
+		drift = append(drift, "addressSpace")
+	}
+	if !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableDdosProtection, az.VirtualNetworkPropertiesFormat.EnableDdosProtection) {
+		drift = append(drift, "enableDdosProtection")
+	}
+	if !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableVMProtection, az.VirtualNetworkPropertiesFormat.EnableVMProtection) {
+		drift = append(drift, "enableVmProtection")
+	}
+	if !strings.EqualFold(subResourceID(up.VirtualNetworkPropertiesFormat.DdosProtectionPlan), subResourceID(az.VirtualNetworkPropertiesFormat.DdosProtectionPlan)) {
+		drift = append(drift, "ddosProtectionPlanId")
+	}
+	if inlineSubnetsNeedUpdate(up.Subnets, az.Subnets) {
+		drift = append(drift, "subnets")
+	}
+	drift = append(drift, virtualNetworkExtensionsDrift(NewVirtualNetworkExtensions(kube), ext)...)
+	if ignored.NeedUpdate(up.Tags, az.Tags) {
+		drift = append(drift, "tags")
+	}
+
+	return drift
+}
+
+// VirtualNetworkExtensionsAPIVersion is the API version that must be used to
+// read and write VirtualNetworkExtensions.
+const VirtualNetworkExtensionsAPIVersion = "2021-05-01"
+
+// VirtualNetworkExtensions are the properties of a VirtualNetwork that are
+// newer than our Azure SDK version, and thus absent from its models.
+type VirtualNetworkExtensions struct {
+	Encryption           *VirtualNetworkEncryption     `json:"encryption,omitempty"`
+	FlowTimeoutInMinutes *int32                        `json:"flowTimeoutInMinutes,omitempty"`
+	BGPCommunities       *VirtualNetworkBGPCommunities `json:"bgpCommunities,omitempty"`
+}
+
+// VirtualNetworkBGPCommunities are the BGP communities of a VirtualNetwork. The
+// regional community is read-only, and set by Azure.
+type VirtualNetworkBGPCommunities struct {
+	VirtualNetworkCommunity *string `json:"virtualNetworkCommunity,omitempty"`
+	RegionalCommunity       *string `json:"regionalCommunity,omitempty"`
+}
+
+// VirtualNetworkEncryption is the encryption configuration of a VirtualNetwork.
+type VirtualNetworkEncryption struct {
+	Enabled     *bool   `json:"enabled,omitempty"`
+	Enforcement *string `json:"enforcement,omitempty"`
+}
+
+// IsZero returns true if no extensions are set.
+func (e VirtualNetworkExtensions) IsZero() bool {
+	return e.Encryption == nil && e.FlowTimeoutInMinutes == nil && e.BGPCommunities == nil
+}
 
+// Properties returns the extensions as VirtualNetwork request properties.
+func (e VirtualNetworkExtensions) Properties() map[string]interface{} {
+	p := map[string]interface{}{}
+	if e.Encryption != nil {
+		p["encryption"] = e.Encryption
+	}
+	if e.FlowTimeoutInMinutes != nil {
+		p["flowTimeoutInMinutes"] = *e.FlowTimeoutInMinutes
+	}
+	if e.BGPCommunities != nil {
+		p["bgpCommunities"] = e.BGPCommunities
+	}
+	return p
+}
+
+// WithObserved returns the extensions, with any that are not set taken from the
+// supplied observed extensions. VirtualNetworks are updated by replacing them,
+// so extensions that were set outside of Crossplane would otherwise be reset
+// by any update that set other extensions. Read-only extensions are not taken.
+func (e VirtualNetworkExtensions) WithObserved(az VirtualNetworkExtensions) VirtualNetworkExtensions {
+	if e.FlowTimeoutInMinutes == nil {
+		e.FlowTimeoutInMinutes = az.FlowTimeoutInMinutes
+	}
 	switch {
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.AddressSpace, az.VirtualNetworkPropertiesFormat.AddressSpace):
-		return true
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableDdosProtection, az.VirtualNetworkPropertiesFormat.EnableDdosProtection):
-		return true
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableVMProtection, az.VirtualNetworkPropertiesFormat.EnableVMProtection):
+	case e.Encryption == nil && az.Encryption != nil:
+		enc := *az.Encryption
+		e.Encryption = &enc
+	case e.Encryption != nil && e.Encryption.Enforcement == nil && az.Encryption != nil:
+		enc := *e.Encryption
+		enc.Enforcement = az.Encryption.Enforcement
+		e.Encryption = &enc
+	}
+	if e.BGPCommunities == nil && az.BGPCommunities != nil && az.BGPCommunities.VirtualNetworkCommunity != nil {
+		e.BGPCommunities = &VirtualNetworkBGPCommunities{VirtualNetworkCommunity: az.BGPCommunities.VirtualNetworkCommunity}
+	}
+	return e
+}
+
+// NewVirtualNetworkExtensions returns the VirtualNetworkExtensions requested
+// by the supplied VirtualNetwork.
+func NewVirtualNetworkExtensions(v *v1alpha3.VirtualNetwork) VirtualNetworkExtensions {
+	e := VirtualNetworkExtensions{
+		FlowTimeoutInMinutes: azure.ToInt32(v.Spec.VirtualNetworkPropertiesFormat.FlowTimeoutInMinutes),
+	}
+	if enc := v.Spec.VirtualNetworkPropertiesFormat.Encryption; enc != nil {
+		e.Encryption = &VirtualNetworkEncryption{
+			Enabled:     azure.ToBoolPtr(enc.Enabled, azure.FieldRequired),
+			Enforcement: enc.Enforcement,
+		}
+	}
+	if c := v.Spec.VirtualNetworkPropertiesFormat.BGPCommunities; c != nil {
+		e.BGPCommunities = &VirtualNetworkBGPCommunities{
+			VirtualNetworkCommunity: azure.ToStringPtr(c.VirtualNetworkCommunity),
+		}
+	}
+	return e
+}
+
+// virtualNetworkExtensionsDrift returns the fields of the supplied observed
+// extensions that differ from the supplied desired extensions. Extensions that
+// are not desired are ignored.
+func virtualNetworkExtensionsDrift(up, az VirtualNetworkExtensions) []string {
+	var drift []string
+	if up.FlowTimeoutInMinutes != nil && !reflect.DeepEqual(up.FlowTimeoutInMinutes, az.FlowTimeoutInMinutes) {
+		drift = append(drift, "flowTimeoutInMinutes")
+	}
+	if encryptionNeedsUpdate(up.Encryption, az.Encryption) {
+		drift = append(drift, "encryption")
+	}
+	if up.BGPCommunities != nil && (az.BGPCommunities == nil || azure.ToString(up.BGPCommunities.VirtualNetworkCommunity) != azure.ToString(az.BGPCommunities.VirtualNetworkCommunity)) {
+		drift = append(drift, "bgpCommunities")
+	}
+	return drift
+}
+
+func encryptionNeedsUpdate(up, az *VirtualNetworkEncryption) bool {
+	if up == nil {
+		return false
+	}
+	if az == nil {
 		return true
-	case !reflect.DeepEqual(up.Tags, az.Tags):
+	}
+	if azure.ToBool(up.Enabled) != azure.ToBool(az.Enabled) {
 		return true
 	}
+	return up.Enforcement != nil && !strings.EqualFold(*up.Enforcement, azure.ToString(az.Enforcement))
+}
+
+// ValidateVirtualNetworkEncryption returns an error if the supplied
+// VirtualNetwork's encryption configuration is invalid.
+func ValidateVirtualNetworkEncryption(v *v1alpha3.VirtualNetwork) error {
+	enc := v.Spec.VirtualNetworkPropertiesFormat.Encryption
+	if enc != nil && !enc.Enabled && enc.Enforcement != nil {
+		return errors.New(errEncryptionEnforcementDisabled)
+	}
+	return nil
+}
+
+// BGP communities of virtual networks must use the Microsoft ASN, and a value
+// from the range Azure reserves for customer use.
+const (
+	bgpCommunityASN      = 12076
+	bgpCommunityValueMin = 20000
+	bgpCommunityValueMax = 49999
+)
 
+// ValidateVirtualNetworkBGPCommunities returns an error if the supplied
+// VirtualNetwork's BGP community is not of the form 12076:<value>, where value
+// is between 20000 and 49999.
+func ValidateVirtualNetworkBGPCommunities(v *v1alpha3.VirtualNetwork) error {
+	c := v.Spec.VirtualNetworkPropertiesFormat.BGPCommunities
+	if c == nil {
+		return nil
+	}
+	parts := strings.Split(c.VirtualNetworkCommunity, ":")
+	if len(parts) != 2 {
+		return errors.Errorf(errFmtInvalidBGPCommunity, c.VirtualNetworkCommunity)
+	}
+	asn, err := strconv.Atoi(parts[0])
+	if err != nil || asn != bgpCommunityASN {
+		return errors.Errorf(errFmtInvalidBGPCommunity, c.VirtualNetworkCommunity)
+	}
+	value, err := strconv.Atoi(parts[1])
+	if err != nil || value < bgpCommunityValueMin || value > bgpCommunityValueMax {
+		return errors.Errorf(errFmtInvalidBGPCommunity, c.VirtualNetworkCommunity)
+	}
+	return nil
+}
+
+// ValidateInlineSubnets returns an error if any of the subnets declared inline
+// by the supplied VirtualNetwork is invalid.
+func ValidateInlineSubnets(v *v1alpha3.VirtualNetwork) error {
+	for _, sn := range v.Spec.VirtualNetworkPropertiesFormat.Subnets {
+		if err := ValidateSubnetAddressPrefixes(sn.SubnetPropertiesFormat); err != nil {
+			return errors.Wrapf(err, errFmtInlineSubnet, sn.Name)
+		}
+	}
+	return nil
+}
+
+// IsEncryptionNotSupported returns true if the supplied error indicates that
+// Azure rejected a request because virtual network encryption is not supported
+// in the requested location, or by the requested configuration.
+func IsEncryptionNotSupported(err error) bool {
+	var de autorest.DetailedError
+	if !errors.As(err, &de) {
+		return false
+	}
+	if de.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	re, ok := de.Original.(*azureautorest.RequestError)
+	if !ok || re.ServiceError == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(re.ServiceError.Code), "encryption")
+}
+
+// errCodeInUseSubnetCannotBeUpdated is the code of the error Azure returns when
+// asked to change the address prefix of a subnet in a way that conflicts with
+// the IP addresses allocated within it.
+const errCodeInUseSubnetCannotBeUpdated = "InUseSubnetCannotBeUpdated"
+
+// IsSubnetInUse returns true if the supplied error indicates that Azure
+// rejected an update because the subnet is in use.
+func IsSubnetInUse(err error) bool {
+	var de autorest.DetailedError
+	if !errors.As(err, &de) {
+		return false
+	}
+	re, ok := de.Original.(*azureautorest.RequestError)
+	if !ok || re.ServiceError == nil {
+		return false
+	}
+	return re.ServiceError.Code == errCodeInUseSubnetCannotBeUpdated
+}
+
+// inlineSubnetsNeedUpdate returns true if any of the supplied desired subnets
+// is missing from or differs from the supplied observed subnets.
+func inlineSubnetsNeedUpdate(up, az *[]networkmgmt.Subnet) bool {
+	if up == nil {
+		return false
+	}
+	var observed []networkmgmt.Subnet
+	if az != nil {
+		observed = *az
+	}
+	for _, s := range *up {
+		o := findSubnet(observed, azure.ToString(s.Name))
+		if o == nil || o.SubnetPropertiesFormat == nil || subnetPropertiesNeedUpdate(s.SubnetPropertiesFormat, o.SubnetPropertiesFormat) {
+			return true
+		}
+	}
 	return false
 }
 
+// subResourceID returns the ID of the supplied SubResource, or the empty string
+// if it is nil. Azure does not preserve the case of resource IDs.
+func subResourceID(r *networkmgmt.SubResource) string {
+	if r == nil {
+		return ""
+	}
+	return azure.ToString(r.ID)
+}
+
 // UpdateVirtualNetworkStatusFromAzure updates the status related to the external
 // Azure virtual network in the VirtualNetworkStatus
 func UpdateVirtualNetworkStatusFromAzure(v *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork) {
@@ -66,16 +419,126 @@ func UpdateVirtualNetworkStatusFromAzure(v *v1alpha3.VirtualNetwork, az networkm
 	v.Status.Etag = azure.ToString(az.Etag)
 	v.Status.ResourceGUID = azure.ToString(az.ResourceGUID)
 	v.Status.Type = azure.ToString(az.Type)
+	v.Status.Location = azure.ToString(az.Location)
+	v.Status.Subnets = nil
+	if az.Subnets == nil {
+		return
+	}
+	for _, s := range *az.Subnets {
+		v.Status.Subnets = append(v.Status.Subnets, v1alpha3.VirtualNetworkSubnet{
+			Name: azure.ToString(s.Name),
+			ID:   azure.ToString(s.ID),
+		})
+	}
 }
 
 // NewSubnetParameters returns an Azure Subnet object from a subnet spec
 func NewSubnetParameters(s *v1alpha3.Subnet) networkmgmt.Subnet {
 	return networkmgmt.Subnet{
-		SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
-			AddressPrefix:    azure.ToStringPtr(s.Spec.SubnetPropertiesFormat.AddressPrefix),
-			ServiceEndpoints: NewServiceEndpoints(s.Spec.SubnetPropertiesFormat.ServiceEndpoints),
-		},
+		SubnetPropertiesFormat: newSubnetPropertiesFormat(s.Spec.SubnetPropertiesFormat),
+	}
+}
+
+func newSubnetPropertiesFormat(p v1alpha3.SubnetPropertiesFormat) *networkmgmt.SubnetPropertiesFormat {
+	var gw *networkmgmt.SubResource
+	if p.NATGatewayID != nil {
+		gw = &networkmgmt.SubResource{ID: p.NATGatewayID}
+	}
+	var policies *[]networkmgmt.ServiceEndpointPolicy
+	if p.ServiceEndpointPolicyID != nil {
+		policies = &[]networkmgmt.ServiceEndpointPolicy{{ID: p.ServiceEndpointPolicyID}}
+	}
+	f := &networkmgmt.SubnetPropertiesFormat{
+		AddressPrefix:           azure.ToStringPtr(p.AddressPrefix),
+		ServiceEndpoints:        NewServiceEndpoints(p.ServiceEndpoints),
+		ServiceEndpointPolicies: policies,
+		NatGateway:              gw,
+
+		PrivateEndpointNetworkPolicies:    p.PrivateEndpointNetworkPolicies,
+		PrivateLinkServiceNetworkPolicies: p.PrivateLinkServiceNetworkPolicies,
+	}
+	if len(p.AddressPrefixes) > 0 {
+		prefixes := make([]string, len(p.AddressPrefixes))
+		copy(prefixes, p.AddressPrefixes)
+		f.AddressPrefix = nil
+		f.AddressPrefixes = &prefixes
+	}
+	return f
+}
+
+// ValidateSubnetAddressPrefixes returns an error if the supplied subnet
+// properties do not set exactly one of addressPrefix and addressPrefixes.
+func ValidateSubnetAddressPrefixes(p v1alpha3.SubnetPropertiesFormat) error {
+	switch {
+	case p.AddressPrefix == "" && len(p.AddressPrefixes) == 0:
+		return errors.New(errNoAddressPrefix)
+	case p.AddressPrefix != "" && len(p.AddressPrefixes) > 0:
+		return errors.New(errBothAddressPrefixes)
+	}
+	return nil
+}
+
+// SubnetAddressPrefixes returns the address prefixes of the supplied Azure
+// subnet properties. Azure reports subnets with a single address prefix using
+// addressPrefix, and subnets with several using addressPrefixes.
+func SubnetAddressPrefixes(f *networkmgmt.SubnetPropertiesFormat) []string {
+	if f == nil {
+		return nil
+	}
+	if f.AddressPrefixes != nil && len(*f.AddressPrefixes) > 0 {
+		return *f.AddressPrefixes
+	}
+	if azure.ToString(f.AddressPrefix) != "" {
+		return []string{*f.AddressPrefix}
+	}
+	return nil
+}
+
+// addressPrefixesNeedUpdate returns true if the supplied desired and observed
+// subnet properties have different sets of address prefixes.
+func addressPrefixesNeedUpdate(up, az *networkmgmt.SubnetPropertiesFormat) bool {
+	return prefixesDiffer(SubnetAddressPrefixes(up), SubnetAddressPrefixes(az))
+}
+
+// addressSpaceNeedsUpdate returns true if the supplied desired and observed
+// address spaces have different sets of address prefixes.
+func addressSpaceNeedsUpdate(up, az *networkmgmt.AddressSpace) bool {
+	var want, got []string
+	if up != nil && up.AddressPrefixes != nil {
+		want = *up.AddressPrefixes
+	}
+	if az != nil && az.AddressPrefixes != nil {
+		got = *az.AddressPrefixes
 	}
+	return prefixesDiffer(want, got)
+}
+
+// prefixesDiffer returns true if the supplied sets of CIDR address prefixes
+// differ. Azure does not preserve the order of address prefixes, and may
+// return IPv6 prefixes in a different (but equivalent) notation to the one
+// they were declared in, so prefixes are compared in canonical form.
+func prefixesDiffer(a, b []string) bool {
+	want := map[string]bool{}
+	for _, p := range a {
+		want[canonicalPrefix(p)] = true
+	}
+	got := map[string]bool{}
+	for _, p := range b {
+		got[canonicalPrefix(p)] = true
+	}
+	return !reflect.DeepEqual(want, got)
+}
+
+// canonicalPrefix returns the canonical form of the supplied CIDR address
+// prefix, e.g. fd00:db8::/64 for FD00:0DB8:0000::/64. Prefixes that cannot be
+// parsed are returned as is.
+func canonicalPrefix(p string) string {
+	ip, n, err := net.ParseCIDR(p)
+	if err != nil {
+		return p
+	}
+	ones, _ := n.Mask.Size()
+	return fmt.Sprintf("%s/%d", ip, ones)
 }
 
 // NewServiceEndpoints converts to Azure ServiceEndpointPropertiesFormat
@@ -93,9 +556,99 @@ func NewServiceEndpoints(e []v1alpha3.ServiceEndpointPropertiesFormat) *[]networ
 
 // SubnetNeedsUpdate determines if a virtual network need to be updated
 func SubnetNeedsUpdate(kube *v1alpha3.Subnet, az networkmgmt.Subnet) bool {
+	return len(SubnetDrift(kube, az)) > 0
+}
+
+// SubnetDrift returns the fields of the supplied Azure subnet that differ from
+// those declared by the supplied Subnet.
+func SubnetDrift(kube *v1alpha3.Subnet, az networkmgmt.Subnet) []string {
 	up := NewSubnetParameters(kube)
 
-	return !reflect.DeepEqual(up.SubnetPropertiesFormat.AddressPrefix, az.SubnetPropertiesFormat.AddressPrefix)
+	return subnetPropertiesDrift(up.SubnetPropertiesFormat, az.SubnetPropertiesFormat)
+}
+
+func subnetPropertiesNeedUpdate(up, az *networkmgmt.SubnetPropertiesFormat) bool {
+	return len(subnetPropertiesDrift(up, az)) > 0
+}
+
+func subnetPropertiesDrift(up, az *networkmgmt.SubnetPropertiesFormat) []string {
+	var drift []string
+	if addressPrefixesNeedUpdate(up, az) {
+		field := "addressPrefix"
+		if up.AddressPrefixes != nil {
+			field = "addressPrefixes"
+		}
+		drift = append(drift, field)
+	}
+	if serviceEndpointsNeedUpdate(up.ServiceEndpoints, az.ServiceEndpoints) {
+		drift = append(drift, "serviceEndpoints")
+	}
+	if up.PrivateEndpointNetworkPolicies != nil && !reflect.DeepEqual(up.PrivateEndpointNetworkPolicies, az.PrivateEndpointNetworkPolicies) {
+		drift = append(drift, "privateEndpointNetworkPolicies")
+	}
+	if up.PrivateLinkServiceNetworkPolicies != nil && !reflect.DeepEqual(up.PrivateLinkServiceNetworkPolicies, az.PrivateLinkServiceNetworkPolicies) {
+		drift = append(drift, "privateLinkServiceNetworkPolicies")
+	}
+	if up.NatGateway != nil && !strings.EqualFold(subResourceID(up.NatGateway), subResourceID(az.NatGateway)) {
+		drift = append(drift, "natGatewayId")
+	}
+	if !reflect.DeepEqual(serviceEndpointPolicyIDs(up.ServiceEndpointPolicies), serviceEndpointPolicyIDs(az.ServiceEndpointPolicies)) {
+		drift = append(drift, "serviceEndpointPolicyId")
+	}
+
+	return drift
+}
+
+// serviceEndpointsNeedUpdate returns true if the supplied desired and observed
+// service endpoints are not for the same set of services. Azure does not
+// preserve the order or case of service endpoints.
+func serviceEndpointsNeedUpdate(up, az *[]networkmgmt.ServiceEndpointPropertiesFormat) bool {
+	want := map[string]bool{}
+	if up != nil {
+		for _, e := range *up {
+			want[strings.ToLower(azure.ToString(e.Service))] = true
+		}
+	}
+	got := map[string]bool{}
+	if az != nil {
+		for _, e := range *az {
+			got[strings.ToLower(azure.ToString(e.Service))] = true
+		}
+	}
+	return !reflect.DeepEqual(want, got)
+}
+
+// serviceEndpointPolicyIDs returns the sorted, lower case IDs of the supplied
+// service endpoint policies, since Azure does not preserve the case of
+// resource IDs.
+func serviceEndpointPolicyIDs(p *[]networkmgmt.ServiceEndpointPolicy) []string {
+	if p == nil || len(*p) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(*p))
+	for i := range *p {
+		ids = append(ids, strings.ToLower(azure.ToString((*p)[i].ID)))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SubnetAddressPrefixChanged returns true if the address prefixes of the
+// supplied subnet differ from those of the supplied Azure subnet.
+func SubnetAddressPrefixChanged(kube *v1alpha3.Subnet, az networkmgmt.Subnet) bool {
+	if az.SubnetPropertiesFormat == nil {
+		return false
+	}
+	return addressPrefixesNeedUpdate(newSubnetPropertiesFormat(kube.Spec.SubnetPropertiesFormat), az.SubnetPropertiesFormat)
+}
+
+// AllocatedIPConfigurations returns the number of IP configurations allocated
+// within the supplied Azure subnet.
+func AllocatedIPConfigurations(az networkmgmt.Subnet) int {
+	if az.SubnetPropertiesFormat == nil || az.IPConfigurations == nil {
+		return 0
+	}
+	return len(*az.IPConfigurations)
 }
 
 // UpdateSubnetStatusFromAzure updates the status related to the external
@@ -105,4 +658,279 @@ func UpdateSubnetStatusFromAzure(v *v1alpha3.Subnet, az networkmgmt.Subnet) {
 	v.Status.Etag = azure.ToString(az.Etag)
 	v.Status.ID = azure.ToString(az.ID)
 	v.Status.Purpose = azure.ToString(az.Purpose)
+	v.Status.AddressPrefix = azure.ToString(az.AddressPrefix)
+	v.Status.AddressPrefixes = nil
+	if az.SubnetPropertiesFormat != nil && az.AddressPrefixes != nil && len(*az.AddressPrefixes) > 0 {
+		v.Status.AddressPrefixes = *az.AddressPrefixes
+	}
+	v.Status.AvailableIPAddressCount = AvailableIPAddressCount(az)
+}
+
+// AzureReservedIPAddresses is the number of IP addresses Azure reserves in
+// every subnet: the network and broadcast addresses, the default gateway, and
+// two addresses used to map Azure DNS.
+const AzureReservedIPAddresses = 5
+
+// AvailableIPAddressCount returns the number of IP addresses in the IPv4
+// address prefixes of the supplied Azure subnet that are neither reserved by
+// Azure nor allocated to an IPv4 IP configuration. Azure reserves addresses in
+// each prefix. IP configurations whose address Azure does not report are
+// assumed to be IPv4, so the count may be low for a subnet that also has an
+// IPv6 prefix. It returns nil if the subnet has no IPv4 address prefix.
+func AvailableIPAddressCount(az networkmgmt.Subnet) *int {
+	var available *int
+	for _, p := range SubnetAddressPrefixes(az.SubnetPropertiesFormat) {
+		n := AvailableIPAddresses(p, 0)
+		if n == nil {
+			continue
+		}
+		if available == nil {
+			available = new(int)
+		}
+		*available += *n
+	}
+	if available == nil {
+		return nil
+	}
+	*available -= allocatedIPv4Configurations(az)
+	if *available < 0 {
+		*available = 0
+	}
+	return available
+}
+
+// allocatedIPv4Configurations returns the number of IP configurations
+// allocated within the supplied Azure subnet that are not known to have an
+// IPv6 address.
+func allocatedIPv4Configurations(az networkmgmt.Subnet) int {
+	if az.SubnetPropertiesFormat == nil || az.IPConfigurations == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range *az.IPConfigurations {
+		if c.IPConfigurationPropertiesFormat != nil {
+			if ip := net.ParseIP(azure.ToString(c.PrivateIPAddress)); ip != nil && ip.To4() == nil {
+				continue
+			}
+		}
+		n++
+	}
+	return n
+}
+
+// AvailableIPAddresses returns the number of IP addresses in the supplied IPv4
+// address prefix that are neither reserved by Azure nor among the supplied
+// number of allocated addresses. It returns nil if the prefix is not a valid
+// IPv4 CIDR.
+func AvailableIPAddresses(prefix string, allocated int) *int {
+	_, n, err := net.ParseCIDR(prefix)
+	if err != nil || n.IP.To4() == nil {
+		return nil
+	}
+	ones, bits := n.Mask.Size()
+	available := (1 << uint(bits-ones)) - AzureReservedIPAddresses - allocated
+	if available < 0 {
+		available = 0
+	}
+	return &available
+}
+
+// NewNATGatewayParameters returns an Azure NatGateway object from a NAT gateway
+// spec.
+func NewNATGatewayParameters(g *v1alpha3.NATGateway) networkmgmt.NatGateway {
+	return networkmgmt.NatGateway{
+		Location: azure.ToStringPtr(g.Spec.Location),
+		Zones:    azure.ToStringArrayPtr(g.Spec.Zones),
+		Tags:     azure.ToStringPtrMap(g.Spec.Tags),
+		Sku:      &networkmgmt.NatGatewaySku{Name: networkmgmt.Standard},
+		NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+			IdleTimeoutInMinutes: azure.ToInt32(g.Spec.IdleTimeoutInMinutes),
+			PublicIPAddresses:    newSubResources(g.Spec.PublicIPAddressIDs),
+			PublicIPPrefixes:     newSubResources(g.Spec.PublicIPPrefixIDs),
+		},
+	}
+}
+
+func newSubResources(ids []string) *[]networkmgmt.SubResource {
+	if len(ids) == 0 {
+		return nil
+	}
+	r := make([]networkmgmt.SubResource, len(ids))
+	for i := range ids {
+		r[i] = networkmgmt.SubResource{ID: azure.ToStringPtr(ids[i])}
+	}
+	return &r
+}
+
+// subResourceIDs returns the lower case IDs of the supplied SubResources,
+// since Azure does not preserve the case of resource IDs.
+func subResourceIDs(r *[]networkmgmt.SubResource) []string {
+	if r == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(*r))
+	for i := range *r {
+		ids = append(ids, strings.ToLower(subResourceID(&(*r)[i])))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// NATGatewayNeedsUpdate determines if a NAT gateway needs to be updated.
+func NATGatewayNeedsUpdate(g *v1alpha3.NATGateway, az networkmgmt.NatGateway, ignored azure.IgnoredTags) bool {
+	up := NewNATGatewayParameters(g)
+	if az.NatGatewayPropertiesFormat == nil {
+		return true
+	}
+
+	switch {
+	case up.IdleTimeoutInMinutes != nil && !reflect.DeepEqual(up.IdleTimeoutInMinutes, az.IdleTimeoutInMinutes):
+		return true
+	case !reflect.DeepEqual(subResourceIDs(up.PublicIPAddresses), subResourceIDs(az.PublicIPAddresses)):
+		return true
+	case !reflect.DeepEqual(subResourceIDs(up.PublicIPPrefixes), subResourceIDs(az.PublicIPPrefixes)):
+		return true
+	case ignored.NeedUpdate(up.Tags, az.Tags):
+		return true
+	}
+
+	return false
+}
+
+// UpdateNATGatewayStatusFromAzure updates the status related to the external
+// Azure NAT gateway in the NATGatewayStatus.
+func UpdateNATGatewayStatusFromAzure(g *v1alpha3.NATGateway, az networkmgmt.NatGateway) {
+	g.Status.ID = azure.ToString(az.ID)
+	g.Status.Etag = azure.ToString(az.Etag)
+	g.Status.State = ""
+	g.Status.ResourceGUID = ""
+	g.Status.SubnetIDs = nil
+	if az.NatGatewayPropertiesFormat == nil {
+		return
+	}
+	g.Status.State = azure.ToString(az.ProvisioningState)
+	g.Status.ResourceGUID = azure.ToString(az.ResourceGUID)
+	if az.Subnets == nil {
+		return
+	}
+	for _, s := range *az.Subnets {
+		g.Status.SubnetIDs = append(g.Status.SubnetIDs, azure.ToString(s.ID))
+	}
+}
+
+// NewServiceEndpointPolicyParameters returns an Azure ServiceEndpointPolicy
+// object from a service endpoint policy spec.
+func NewServiceEndpointPolicyParameters(p *v1alpha3.ServiceEndpointPolicy) networkmgmt.ServiceEndpointPolicy {
+	return networkmgmt.ServiceEndpointPolicy{
+		Location: azure.ToStringPtr(p.Spec.Location),
+		Tags:     azure.ToStringPtrMap(p.Spec.Tags),
+		ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+			ServiceEndpointPolicyDefinitions: newServiceEndpointPolicyDefinitions(p.Spec.ServiceEndpointPolicyDefinitions),
+		},
+	}
+}
+
+func newServiceEndpointPolicyDefinitions(in []v1alpha3.ServiceEndpointPolicyDefinition) *[]networkmgmt.ServiceEndpointPolicyDefinition {
+	d := make([]networkmgmt.ServiceEndpointPolicyDefinition, len(in))
+	for i, def := range in {
+		d[i] = networkmgmt.ServiceEndpointPolicyDefinition{
+			Name: azure.ToStringPtr(def.Name),
+			ServiceEndpointPolicyDefinitionPropertiesFormat: &networkmgmt.ServiceEndpointPolicyDefinitionPropertiesFormat{
+				Description:      def.Description,
+				Service:          azure.ToStringPtr(def.Service),
+				ServiceResources: azure.ToStringArrayPtr(def.ServiceResources),
+			},
+		}
+	}
+	return &d
+}
+
+// ServiceEndpointPolicyNeedsUpdate determines if a service endpoint policy
+// needs to be updated.
+func ServiceEndpointPolicyNeedsUpdate(p *v1alpha3.ServiceEndpointPolicy, az networkmgmt.ServiceEndpointPolicy, ignored azure.IgnoredTags) bool {
+	up := NewServiceEndpointPolicyParameters(p)
+	if az.ServiceEndpointPolicyPropertiesFormat == nil {
+		return true
+	}
+
+	switch {
+	case serviceEndpointPolicyDefinitionsNeedUpdate(up.ServiceEndpointPolicyDefinitions, az.ServiceEndpointPolicyDefinitions):
+		return true
+	case ignored.NeedUpdate(up.Tags, az.Tags):
+		return true
+	}
+
+	return false
+}
+
+// serviceEndpointPolicyDefinitionsNeedUpdate returns true if the supplied
+// desired and observed definitions differ. Definitions are matched by name,
+// since Azure does not preserve their order. Azure does not preserve the case
+// of services or resource IDs either.
+func serviceEndpointPolicyDefinitionsNeedUpdate(up, az *[]networkmgmt.ServiceEndpointPolicyDefinition) bool {
+	want := map[string]networkmgmt.ServiceEndpointPolicyDefinition{}
+	if up != nil {
+		for _, d := range *up {
+			want[strings.ToLower(azure.ToString(d.Name))] = d
+		}
+	}
+	got := map[string]networkmgmt.ServiceEndpointPolicyDefinition{}
+	if az != nil {
+		for _, d := range *az {
+			got[strings.ToLower(azure.ToString(d.Name))] = d
+		}
+	}
+	if len(want) != len(got) {
+		return true
+	}
+	for name, w := range want {
+		g, ok := got[name]
+		if !ok || g.ServiceEndpointPolicyDefinitionPropertiesFormat == nil {
+			return true
+		}
+		if !strings.EqualFold(azure.ToString(w.Service), azure.ToString(g.Service)) {
+			return true
+		}
+		if azure.ToString(w.Description) != azure.ToString(g.Description) {
+			return true
+		}
+		if !reflect.DeepEqual(lowerSorted(w.ServiceResources), lowerSorted(g.ServiceResources)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerSorted returns a sorted, lower case copy of the supplied strings.
+func lowerSorted(s *[]string) []string {
+	if s == nil || len(*s) == 0 {
+		return nil
+	}
+	out := make([]string, len(*s))
+	for i := range *s {
+		out[i] = strings.ToLower((*s)[i])
+	}
+	sort.Strings(out)
+	return out
+}
+
+// UpdateServiceEndpointPolicyStatusFromAzure updates the status related to the
+// external Azure service endpoint policy in the ServiceEndpointPolicyStatus.
+func UpdateServiceEndpointPolicyStatusFromAzure(p *v1alpha3.ServiceEndpointPolicy, az networkmgmt.ServiceEndpointPolicy) {
+	p.Status.ID = azure.ToString(az.ID)
+	p.Status.Etag = azure.ToString(az.Etag)
+	p.Status.State = ""
+	p.Status.ResourceGUID = ""
+	p.Status.SubnetIDs = nil
+	if az.ServiceEndpointPolicyPropertiesFormat == nil {
+		return
+	}
+	p.Status.State = azure.ToString(az.ProvisioningState)
+	p.Status.ResourceGUID = azure.ToString(az.ResourceGUID)
+	if az.Subnets == nil {
+		return
+	}
+	for _, s := range *az.Subnets {
+		p.Status.SubnetIDs = append(p.Status.SubnetIDs, azure.ToString(s.ID))
+	}
 }
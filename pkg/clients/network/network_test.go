@@ -17,15 +17,22 @@ limitations under the License.
 package network
 
 import (
+	"net/http"
+	"strings"
 	"testing"
 
 	networkmgmt "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
@@ -45,8 +52,22 @@ var (
 	etag         = "a-very-cool-etag"
 	resourceType = "resource-type"
 	purpose      = "cool-purpose"
+
+	policiesEnabled  = "Enabled"
+	policiesDisabled = "Disabled"
+
+	ddosPlanID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/ddosProtectionPlans/cool-plan"
+
+	natGatewayID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/natGateways/cool-gateway"
+	publicIPID   = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/cool-ip"
+	idleTimeout  = 10
+
+	policyID  = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/serviceEndpointPolicies/cool-policy"
+	accountID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/coolaccount"
 )
 
+func intPtr(i int) *int { return &i }
+
 func TestNewVirtualNetworkParameters(t *testing.T) {
 	cases := []struct {
 		name string
@@ -80,6 +101,34 @@ func TestNewVirtualNetworkParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SuccessfulDDOSProtectionPlan",
+			r: &v1alpha3.VirtualNetwork{
+				ObjectMeta: metav1.ObjectMeta{UID: uid},
+				Spec: v1alpha3.VirtualNetworkSpec{
+					Location: location,
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						DDOSProtectionPlanID: azure.ToStringPtr(ddosPlanID),
+					},
+				},
+			},
+			want: networkmgmt.VirtualNetwork{
+				Location: azure.ToStringPtr(location),
+				Tags:     azure.ToStringPtrMap(nil),
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(false),
+					DdosProtectionPlan:   &networkmgmt.SubResource{ID: azure.ToStringPtr(ddosPlanID)},
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+				},
+			},
+		},
 		{
 			name: "SuccessfulPartial",
 			r: &v1alpha3.VirtualNetwork{
@@ -106,6 +155,31 @@ func TestNewVirtualNetworkParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SuccessfulDualStack",
+			r: &v1alpha3.VirtualNetwork{
+				ObjectMeta: metav1.ObjectMeta{UID: uid},
+				Spec: v1alpha3.VirtualNetworkSpec{
+					Location: location,
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: []string{"10.0.0.0/16", "fd00:db8::/48"},
+						},
+					},
+				},
+			},
+			want: networkmgmt.VirtualNetwork{
+				Location: azure.ToStringPtr(location),
+				Tags:     azure.ToStringPtrMap(nil),
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					EnableDdosProtection: to.BoolPtr(false),
+					EnableVMProtection:   to.BoolPtr(false),
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -123,6 +197,7 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 		name string
 		kube *v1alpha3.VirtualNetwork
 		az   networkmgmt.VirtualNetwork
+		ext  VirtualNetworkExtensions
 		want bool
 	}{
 		{
@@ -152,14 +227,14 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 			want: true,
 		},
 		{
-			name: "NeedsUpdateDdosProtection",
+			name: "IgnoredTagInjected",
 			kube: &v1alpha3.VirtualNetwork{
 				Spec: v1alpha3.VirtualNetworkSpec{
 					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
 						AddressSpace: v1alpha3.AddressSpace{
 							AddressPrefixes: addressPrefixes,
 						},
-						EnableDDOSProtection: !enableDDOSProtection,
+						EnableDDOSProtection: enableDDOSProtection,
 						EnableVMProtection:   enableVMProtection,
 					},
 					Tags: tags,
@@ -173,12 +248,12 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
 					EnableVMProtection:   to.BoolPtr(enableVMProtection),
 				},
-				Tags: azure.ToStringPtrMap(tags),
+				Tags: azure.IgnoredTags(azure.DefaultIgnoredTagPrefixes).Preserve(azure.ToStringPtrMap(tags), map[string]*string{"hidden-link:/cool": to.StringPtr("Resource")}),
 			},
-			want: true,
+			want: false,
 		},
 		{
-			name: "NeedsUpdateVMProtection",
+			name: "NeedsUpdateFlowTimeout",
 			kube: &v1alpha3.VirtualNetwork{
 				Spec: v1alpha3.VirtualNetworkSpec{
 					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
@@ -186,7 +261,8 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 							AddressPrefixes: addressPrefixes,
 						},
 						EnableDDOSProtection: enableDDOSProtection,
-						EnableVMProtection:   !enableVMProtection,
+						EnableVMProtection:   enableVMProtection,
+						FlowTimeoutInMinutes: intPtr(10),
 					},
 					Tags: tags,
 				},
@@ -201,10 +277,11 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 				},
 				Tags: azure.ToStringPtrMap(tags),
 			},
+			ext:  VirtualNetworkExtensions{FlowTimeoutInMinutes: to.Int32Ptr(4)},
 			want: true,
 		},
 		{
-			name: "NeedsUpdateTags",
+			name: "NeedsUpdateEncryption",
 			kube: &v1alpha3.VirtualNetwork{
 				Spec: v1alpha3.VirtualNetworkSpec{
 					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
@@ -213,8 +290,9 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 						},
 						EnableDDOSProtection: enableDDOSProtection,
 						EnableVMProtection:   enableVMProtection,
+						Encryption:           &v1alpha3.VirtualNetworkEncryption{Enabled: true},
 					},
-					Tags: map[string]string{"three": "test"},
+					Tags: tags,
 				},
 			},
 			az: networkmgmt.VirtualNetwork{
@@ -227,10 +305,11 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 				},
 				Tags: azure.ToStringPtrMap(tags),
 			},
+			ext:  VirtualNetworkExtensions{},
 			want: true,
 		},
 		{
-			name: "NoUpdate",
+			name: "NeedsUpdateEncryptionEnforcement",
 			kube: &v1alpha3.VirtualNetwork{
 				Spec: v1alpha3.VirtualNetworkSpec{
 					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
@@ -239,6 +318,7 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 						},
 						EnableDDOSProtection: enableDDOSProtection,
 						EnableVMProtection:   enableVMProtection,
+						Encryption:           &v1alpha3.VirtualNetworkEncryption{Enabled: true, Enforcement: to.StringPtr("DropUnencrypted")},
 					},
 					Tags: tags,
 				},
@@ -253,160 +333,822 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 				},
 				Tags: azure.ToStringPtrMap(tags),
 			},
-			want: false,
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := VirtualNetworkNeedsUpdate(tc.kube, tc.az)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("VirtualNetworkNeedsUpdate(...): -want, +got\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestUpdateVirtualNetworkStatusFromAzure(t *testing.T) {
-	mockCondition := runtimev1alpha1.Condition{Message: "mockMessage"}
-	resourceStatus := runtimev1alpha1.ResourceStatus{
-		ConditionedStatus: runtimev1alpha1.ConditionedStatus{
-			Conditions: []runtimev1alpha1.Condition{mockCondition},
+			ext:  VirtualNetworkExtensions{Encryption: &VirtualNetworkEncryption{Enabled: to.BoolPtr(true), Enforcement: to.StringPtr("AllowUnencrypted")}},
+			want: true,
 		},
-	}
-
-	cases := []struct {
-		name string
-		r    networkmgmt.VirtualNetwork
-		want v1alpha3.VirtualNetworkStatus
-	}{
 		{
-			name: "SuccessfulFull",
-			r: networkmgmt.VirtualNetwork{
-				Location: azure.ToStringPtr(location),
-				Etag:     azure.ToStringPtr(etag),
-				ID:       azure.ToStringPtr(id),
-				Type:     azure.ToStringPtr(resourceType),
-				Tags:     azure.ToStringPtrMap(nil),
+			name: "NeedsUpdateBGPCommunity",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						BGPCommunities:       &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "12076:20000"},
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
 				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
-					EnableDdosProtection: azure.ToBoolPtr(enableDDOSProtection),
-					EnableVMProtection:   azure.ToBoolPtr(enableVMProtection),
 					AddressSpace: &networkmgmt.AddressSpace{
 						AddressPrefixes: &addressPrefixes,
 					},
-					ProvisioningState: azure.ToStringPtr("Succeeded"),
-					ResourceGUID:      azure.ToStringPtr(string(uid)),
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
 				},
+				Tags: azure.ToStringPtrMap(tags),
 			},
-			want: v1alpha3.VirtualNetworkStatus{
-				State:        string(networkmgmt.Succeeded),
-				ID:           id,
-				Etag:         etag,
-				Type:         resourceType,
-				ResourceGUID: string(uid),
-			},
+			ext:  VirtualNetworkExtensions{BGPCommunities: &VirtualNetworkBGPCommunities{VirtualNetworkCommunity: to.StringPtr("12076:20001")}},
+			want: true,
 		},
 		{
-			name: "SuccessfulPartial",
-			r: networkmgmt.VirtualNetwork{
-				Location: azure.ToStringPtr(location),
-				Type:     azure.ToStringPtr(resourceType),
-				Tags:     azure.ToStringPtrMap(nil),
+			name: "BGPCommunityUpToDate",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						BGPCommunities:       &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "12076:20000"},
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
 				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
-					EnableDdosProtection: azure.ToBoolPtr(enableDDOSProtection),
-					EnableVMProtection:   azure.ToBoolPtr(enableVMProtection),
 					AddressSpace: &networkmgmt.AddressSpace{
 						AddressPrefixes: &addressPrefixes,
 					},
-					ProvisioningState: azure.ToStringPtr("Succeeded"),
-					ResourceGUID:      azure.ToStringPtr(string(uid)),
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
 				},
+				Tags: azure.ToStringPtrMap(tags),
 			},
-			want: v1alpha3.VirtualNetworkStatus{
-				State:        string(networkmgmt.Succeeded),
-				ResourceGUID: string(uid),
-				Type:         resourceType,
-			},
+			ext:  VirtualNetworkExtensions{BGPCommunities: &VirtualNetworkBGPCommunities{VirtualNetworkCommunity: to.StringPtr("12076:20000"), RegionalCommunity: to.StringPtr("12076:50004")}},
+			want: false,
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-
-			v := &v1alpha3.VirtualNetwork{
-				Status: v1alpha3.VirtualNetworkStatus{
-					ResourceStatus: resourceStatus,
-				},
-			}
-
-			UpdateVirtualNetworkStatusFromAzure(v, tc.r)
-
-			// make sure that internal resource status hasn't changed
-			if diff := cmp.Diff(mockCondition, v.Status.ResourceStatus.Conditions[0]); diff != "" {
-				t.Errorf("UpdateVirtualNetworkStatusFromAzure(...): -want, +got\n%s", diff)
-			}
-
-			// make sure that other resource parameters are updated
-			tc.want.ResourceStatus = resourceStatus
-			if diff := cmp.Diff(tc.want, v.Status); diff != "" {
-				t.Errorf("UpdateVirtualNetworkStatusFromAzure(...): -want, +got\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestNewSubnetParameters(t *testing.T) {
-	cases := []struct {
-		name string
-		r    *v1alpha3.Subnet
-		want networkmgmt.Subnet
-	}{
 		{
-			name: "Successful",
-			r: &v1alpha3.Subnet{
-				ObjectMeta: metav1.ObjectMeta{UID: uid},
-				Spec: v1alpha3.SubnetSpec{
-					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
-						AddressPrefix: addressPrefix,
+			name: "ExtensionsUpToDate",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						Encryption:           &v1alpha3.VirtualNetworkEncryption{Enabled: true},
+						FlowTimeoutInMinutes: intPtr(10),
 					},
+					Tags: tags,
 				},
 			},
-			want: networkmgmt.Subnet{
-				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
-					AddressPrefix:    azure.ToStringPtr(addressPrefix),
-					ServiceEndpoints: NewServiceEndpoints(nil),
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
 				},
+				Tags: azure.ToStringPtrMap(tags),
 			},
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := NewSubnetParameters(tc.r)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("NewSubnetParameters(...): -want, +got\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestNewServiceEndpoints(t *testing.T) {
-	cases := []struct {
-		name string
-		r    []v1alpha3.ServiceEndpointPropertiesFormat
-		want *[]networkmgmt.ServiceEndpointPropertiesFormat
-	}{
-		{
-			name: "SuccessfulNotSet",
-			r:    []v1alpha3.ServiceEndpointPropertiesFormat{},
-			want: &[]networkmgmt.ServiceEndpointPropertiesFormat{},
+			ext:  VirtualNetworkExtensions{Encryption: &VirtualNetworkEncryption{Enabled: to.BoolPtr(true), Enforcement: to.StringPtr("AllowUnencrypted")}, FlowTimeoutInMinutes: to.Int32Ptr(10)},
+			want: false,
 		},
 		{
-			name: "SuccessfulSet",
-			r: []v1alpha3.ServiceEndpointPropertiesFormat{
-				{Service: serviceEndpoint},
+			name: "NeedsUpdateDdosProtection",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: !enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+					},
+					Tags: tags,
+				},
 			},
-			want: &[]networkmgmt.ServiceEndpointPropertiesFormat{
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdateVMProtection",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   !enableVMProtection,
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdateDdosProtectionPlan",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						DDOSProtectionPlanID: azure.ToStringPtr(ddosPlanID),
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: true,
+		},
+		{
+			name: "NoUpdateDdosProtectionPlanCase",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						DDOSProtectionPlanID: azure.ToStringPtr(ddosPlanID),
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+					DdosProtectionPlan:   &networkmgmt.SubResource{ID: azure.ToStringPtr(strings.ToLower(ddosPlanID))},
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: false,
+		},
+		{
+			name: "NeedsUpdateInlineSubnet",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+						Subnets: []v1alpha3.InlineSubnet{
+							{Name: "cool", SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: "10.0.1.0/24"}},
+						},
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+					Subnets: &[]networkmgmt.Subnet{
+						{Name: azure.ToStringPtr("cool"), SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.2.0/24")}},
+					},
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdateTags",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+					},
+					Tags: map[string]string{"three": "test"},
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: true,
+		},
+		{
+			name: "NoUpdate",
+			kube: &v1alpha3.VirtualNetwork{
+				Spec: v1alpha3.VirtualNetworkSpec{
+					VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+						AddressSpace: v1alpha3.AddressSpace{
+							AddressPrefixes: addressPrefixes,
+						},
+						EnableDDOSProtection: enableDDOSProtection,
+						EnableVMProtection:   enableVMProtection,
+					},
+					Tags: tags,
+				},
+			},
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := VirtualNetworkNeedsUpdate(tc.kube, tc.az, tc.ext, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("VirtualNetworkNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVirtualNetworkDrift(t *testing.T) {
+	kube := &v1alpha3.VirtualNetwork{
+		Spec: v1alpha3.VirtualNetworkSpec{
+			VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{
+				AddressSpace: v1alpha3.AddressSpace{
+					AddressPrefixes: addressPrefixes,
+				},
+				EnableDDOSProtection: enableDDOSProtection,
+				EnableVMProtection:   enableVMProtection,
+				FlowTimeoutInMinutes: intPtr(10),
+			},
+			Tags: tags,
+		},
+	}
+	cases := map[string]struct {
+		az   networkmgmt.VirtualNetwork
+		ext  VirtualNetworkExtensions
+		want []string
+	}{
+		"NoDrift": {
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+				},
+				Tags: azure.ToStringPtrMap(tags),
+			},
+			ext: VirtualNetworkExtensions{FlowTimeoutInMinutes: to.Int32Ptr(10)},
+		},
+		"SeveralFields": {
+			az: networkmgmt.VirtualNetwork{
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					EnableDdosProtection: to.BoolPtr(!enableDDOSProtection),
+					EnableVMProtection:   to.BoolPtr(enableVMProtection),
+					DdosProtectionPlan:   &networkmgmt.SubResource{ID: to.StringPtr("/a/ddos/plan")},
+				},
+				Tags: map[string]*string{"added": to.StringPtr("in-portal")},
+			},
+			ext:  VirtualNetworkExtensions{FlowTimeoutInMinutes: to.Int32Ptr(4)},
+			want: []string{"enableDdosProtection", "ddosProtectionPlanId", "flowTimeoutInMinutes", "tags"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualNetworkDrift(kube, tc.az, tc.ext, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("VirtualNetworkDrift(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddressSpaceNeedsUpdate(t *testing.T) {
+	cases := map[string]struct {
+		up   *networkmgmt.AddressSpace
+		az   *networkmgmt.AddressSpace
+		want bool
+	}{
+		"Identical": {
+			up: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+			az: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+		},
+		"Reordered": {
+			up: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+			az: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"fd00:db8::/48", "10.0.0.0/16"}},
+		},
+		"EquivalentIPv6Notation": {
+			up: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "FD00:0DB8:0000::/48"}},
+			az: &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+		},
+		"IPv6PrefixAdded": {
+			up:   &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+			az:   &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16"}},
+			want: true,
+		},
+		"IPv6PrefixChanged": {
+			up:   &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db8::/48"}},
+			az:   &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16", "fd00:db9::/48"}},
+			want: true,
+		},
+		"NotObserved": {
+			up:   &networkmgmt.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/16"}},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := addressSpaceNeedsUpdate(tc.up, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("addressSpaceNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateSubnetAddressPrefixes(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.SubnetPropertiesFormat
+		want error
+	}{
+		"AddressPrefix": {
+			p: v1alpha3.SubnetPropertiesFormat{AddressPrefix: addressPrefix},
+		},
+		"AddressPrefixes": {
+			p: v1alpha3.SubnetPropertiesFormat{AddressPrefixes: []string{addressPrefix, "fd00:db8::/64"}},
+		},
+		"Neither": {
+			want: errors.New(errNoAddressPrefix),
+		},
+		"Both": {
+			p:    v1alpha3.SubnetPropertiesFormat{AddressPrefix: addressPrefix, AddressPrefixes: []string{"fd00:db8::/64"}},
+			want: errors.New(errBothAddressPrefixes),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateSubnetAddressPrefixes(tc.p)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateSubnetAddressPrefixes(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateVirtualNetworkEncryption(t *testing.T) {
+	cases := map[string]struct {
+		enc  *v1alpha3.VirtualNetworkEncryption
+		want error
+	}{
+		"Unset": {},
+		"Enforced": {
+			enc: &v1alpha3.VirtualNetworkEncryption{Enabled: true, Enforcement: to.StringPtr("DropUnencrypted")},
+		},
+		"EnforcedButDisabled": {
+			enc:  &v1alpha3.VirtualNetworkEncryption{Enabled: false, Enforcement: to.StringPtr("DropUnencrypted")},
+			want: errors.New(errEncryptionEnforcementDisabled),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &v1alpha3.VirtualNetwork{Spec: v1alpha3.VirtualNetworkSpec{
+				VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{Encryption: tc.enc},
+			}}
+			got := ValidateVirtualNetworkEncryption(v)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateVirtualNetworkEncryption(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateVirtualNetworkBGPCommunities(t *testing.T) {
+	cases := map[string]struct {
+		c    *v1alpha3.VirtualNetworkBGPCommunities
+		want error
+	}{
+		"Unset": {},
+		"Valid": {
+			c: &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "12076:20000"},
+		},
+		"WrongASN": {
+			c:    &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "65000:20000"},
+			want: errors.Errorf(errFmtInvalidBGPCommunity, "65000:20000"),
+		},
+		"ValueOutOfRange": {
+			c:    &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "12076:50000"},
+			want: errors.Errorf(errFmtInvalidBGPCommunity, "12076:50000"),
+		},
+		"NotACommunity": {
+			c:    &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: "cool"},
+			want: errors.Errorf(errFmtInvalidBGPCommunity, "cool"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &v1alpha3.VirtualNetwork{Spec: v1alpha3.VirtualNetworkSpec{
+				VirtualNetworkPropertiesFormat: v1alpha3.VirtualNetworkPropertiesFormat{BGPCommunities: tc.c},
+			}}
+			got := ValidateVirtualNetworkBGPCommunities(v)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateVirtualNetworkBGPCommunities(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVirtualNetworkExtensionsWithObserved(t *testing.T) {
+	cases := map[string]struct {
+		e    VirtualNetworkExtensions
+		az   VirtualNetworkExtensions
+		want VirtualNetworkExtensions
+	}{
+		"NoneObserved": {
+			e:    VirtualNetworkExtensions{FlowTimeoutInMinutes: azure.ToInt32Ptr(10)},
+			want: VirtualNetworkExtensions{FlowTimeoutInMinutes: azure.ToInt32Ptr(10)},
+		},
+		"UnsetTakenFromObserved": {
+			e: VirtualNetworkExtensions{FlowTimeoutInMinutes: azure.ToInt32Ptr(10)},
+			az: VirtualNetworkExtensions{
+				FlowTimeoutInMinutes: azure.ToInt32Ptr(4),
+				Encryption:           &VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("DropUnencrypted")},
+				BGPCommunities:       &VirtualNetworkBGPCommunities{VirtualNetworkCommunity: azure.ToStringPtr("12076:20000"), RegionalCommunity: azure.ToStringPtr("12076:50004")},
+			},
+			want: VirtualNetworkExtensions{
+				FlowTimeoutInMinutes: azure.ToInt32Ptr(10),
+				Encryption:           &VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("DropUnencrypted")},
+				BGPCommunities:       &VirtualNetworkBGPCommunities{VirtualNetworkCommunity: azure.ToStringPtr("12076:20000")},
+			},
+		},
+		"EncryptionEnforcementTakenFromObserved": {
+			e:    VirtualNetworkExtensions{Encryption: &VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true)}},
+			az:   VirtualNetworkExtensions{Encryption: &VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(false), Enforcement: azure.ToStringPtr("AllowUnencrypted")}},
+			want: VirtualNetworkExtensions{Encryption: &VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("AllowUnencrypted")}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.e.WithObserved(tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("WithObserved(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsEncryptionNotSupported(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"NotSupported": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusBadRequest,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "VirtualNetworkEncryptionNotSupported"}},
+			},
+			want: true,
+		},
+		"OtherBadRequest": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusBadRequest,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "InvalidAddressPrefix"}},
+			},
+			want: false,
+		},
+		"NotDetailed": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsEncryptionNotSupported(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsEncryptionNotSupported(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsSubnetInUse(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"InUse": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusBadRequest,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "InUseSubnetCannotBeUpdated"}},
+			},
+			want: true,
+		},
+		"OtherBadRequest": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusBadRequest,
+				Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "InvalidAddressPrefix"}},
+			},
+			want: false,
+		},
+		"NotDetailed": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsSubnetInUse(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsSubnetInUse(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSubnetAddressPrefixChanged(t *testing.T) {
+	cases := map[string]struct {
+		kube *v1alpha3.Subnet
+		az   networkmgmt.Subnet
+		want bool
+	}{
+		"Changed": {
+			kube: &v1alpha3.Subnet{Spec: v1alpha3.SubnetSpec{SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: "10.0.0.0/24"}}},
+			az:   networkmgmt.Subnet{SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.1.0/24")}},
+			want: true,
+		},
+		"Unchanged": {
+			kube: &v1alpha3.Subnet{Spec: v1alpha3.SubnetSpec{SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: "10.0.0.0/24"}}},
+			az:   networkmgmt.Subnet{SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.0.0/24")}},
+			want: false,
+		},
+		"NotObserved": {
+			kube: &v1alpha3.Subnet{Spec: v1alpha3.SubnetSpec{SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: "10.0.0.0/24"}}},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SubnetAddressPrefixChanged(tc.kube, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("SubnetAddressPrefixChanged(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateVirtualNetworkStatusFromAzure(t *testing.T) {
+	mockCondition := runtimev1alpha1.Condition{Message: "mockMessage"}
+	resourceStatus := runtimev1alpha1.ResourceStatus{
+		ConditionedStatus: runtimev1alpha1.ConditionedStatus{
+			Conditions: []runtimev1alpha1.Condition{mockCondition},
+		},
+	}
+
+	cases := []struct {
+		name string
+		r    networkmgmt.VirtualNetwork
+		want v1alpha3.VirtualNetworkStatus
+	}{
+		{
+			name: "SuccessfulFull",
+			r: networkmgmt.VirtualNetwork{
+				Location: azure.ToStringPtr(location),
+				Etag:     azure.ToStringPtr(etag),
+				ID:       azure.ToStringPtr(id),
+				Type:     azure.ToStringPtr(resourceType),
+				Tags:     azure.ToStringPtrMap(nil),
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					EnableDdosProtection: azure.ToBoolPtr(enableDDOSProtection),
+					EnableVMProtection:   azure.ToBoolPtr(enableVMProtection),
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					ResourceGUID:      azure.ToStringPtr(string(uid)),
+					Subnets: &[]networkmgmt.Subnet{
+						{Name: azure.ToStringPtr("cool"), ID: azure.ToStringPtr(id + "/subnets/cool")},
+					},
+				},
+			},
+			want: v1alpha3.VirtualNetworkStatus{
+				State:        string(networkmgmt.Succeeded),
+				ID:           id,
+				Etag:         etag,
+				Type:         resourceType,
+				Location:     location,
+				ResourceGUID: string(uid),
+				Subnets: []v1alpha3.VirtualNetworkSubnet{
+					{Name: "cool", ID: id + "/subnets/cool"},
+				},
+			},
+		},
+		{
+			name: "SuccessfulPartial",
+			r: networkmgmt.VirtualNetwork{
+				Location: azure.ToStringPtr(location),
+				Type:     azure.ToStringPtr(resourceType),
+				Tags:     azure.ToStringPtrMap(nil),
+				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+					EnableDdosProtection: azure.ToBoolPtr(enableDDOSProtection),
+					EnableVMProtection:   azure.ToBoolPtr(enableVMProtection),
+					AddressSpace: &networkmgmt.AddressSpace{
+						AddressPrefixes: &addressPrefixes,
+					},
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					ResourceGUID:      azure.ToStringPtr(string(uid)),
+				},
+			},
+			want: v1alpha3.VirtualNetworkStatus{
+				State:        string(networkmgmt.Succeeded),
+				ResourceGUID: string(uid),
+				Type:         resourceType,
+				Location:     location,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			v := &v1alpha3.VirtualNetwork{
+				Status: v1alpha3.VirtualNetworkStatus{
+					ResourceStatus: resourceStatus,
+				},
+			}
+
+			UpdateVirtualNetworkStatusFromAzure(v, tc.r)
+
+			// make sure that internal resource status hasn't changed
+			if diff := cmp.Diff(mockCondition, v.Status.ResourceStatus.Conditions[0]); diff != "" {
+				t.Errorf("UpdateVirtualNetworkStatusFromAzure(...): -want, +got\n%s", diff)
+			}
+
+			// make sure that other resource parameters are updated
+			tc.want.ResourceStatus = resourceStatus
+			if diff := cmp.Diff(tc.want, v.Status); diff != "" {
+				t.Errorf("UpdateVirtualNetworkStatusFromAzure(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewSubnetParameters(t *testing.T) {
+	cases := []struct {
+		name string
+		r    *v1alpha3.Subnet
+		want networkmgmt.Subnet
+	}{
+		{
+			name: "Successful",
+			r: &v1alpha3.Subnet{
+				ObjectMeta: metav1.ObjectMeta{UID: uid},
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+					},
+				},
+			},
+			want: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:    azure.ToStringPtr(addressPrefix),
+					ServiceEndpoints: NewServiceEndpoints(nil),
+				},
+			},
+		},
+		{
+			name: "SuccessfulNetworkPolicies",
+			r: &v1alpha3.Subnet{
+				ObjectMeta: metav1.ObjectMeta{UID: uid},
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:                     addressPrefix,
+						PrivateEndpointNetworkPolicies:    azure.ToStringPtr(policiesDisabled),
+						PrivateLinkServiceNetworkPolicies: azure.ToStringPtr(policiesDisabled),
+					},
+				},
+			},
+			want: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:                     azure.ToStringPtr(addressPrefix),
+					ServiceEndpoints:                  NewServiceEndpoints(nil),
+					PrivateEndpointNetworkPolicies:    azure.ToStringPtr(policiesDisabled),
+					PrivateLinkServiceNetworkPolicies: azure.ToStringPtr(policiesDisabled),
+				},
+			},
+		},
+		{
+			name: "SuccessfulMultiplePrefixes",
+			r: &v1alpha3.Subnet{
+				ObjectMeta: metav1.ObjectMeta{UID: uid},
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefixes: []string{addressPrefix, "fd00:db8::/64"},
+					},
+				},
+			},
+			want: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefixes:  &[]string{addressPrefix, "fd00:db8::/64"},
+					ServiceEndpoints: NewServiceEndpoints(nil),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewSubnetParameters(tc.r)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewSubnetParameters(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewServiceEndpoints(t *testing.T) {
+	cases := []struct {
+		name string
+		r    []v1alpha3.ServiceEndpointPropertiesFormat
+		want *[]networkmgmt.ServiceEndpointPropertiesFormat
+	}{
+		{
+			name: "SuccessfulNotSet",
+			r:    []v1alpha3.ServiceEndpointPropertiesFormat{},
+			want: &[]networkmgmt.ServiceEndpointPropertiesFormat{},
+		},
+		{
+			name: "SuccessfulSet",
+			r: []v1alpha3.ServiceEndpointPropertiesFormat{
+				{Service: serviceEndpoint},
+			},
+			want: &[]networkmgmt.ServiceEndpointPropertiesFormat{
 				{Service: &serviceEndpoint},
 			},
 		},
@@ -445,6 +1187,163 @@ func TestSubnetNeedsUpdate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "NeedsUpdatePrivateEndpointNetworkPolicies",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:                  addressPrefix,
+						PrivateEndpointNetworkPolicies: azure.ToStringPtr(policiesDisabled),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:                  &addressPrefix,
+					PrivateEndpointNetworkPolicies: azure.ToStringPtr(policiesEnabled),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdatePrivateLinkServiceNetworkPolicies",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:                     addressPrefix,
+						PrivateLinkServiceNetworkPolicies: azure.ToStringPtr(policiesDisabled),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:                     &addressPrefix,
+					PrivateLinkServiceNetworkPolicies: azure.ToStringPtr(policiesEnabled),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdateNATGatewayAssociated",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+						NATGatewayID:  azure.ToStringPtr(natGatewayID),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "NoUpdateNATGatewayUnmanaged",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+					NatGateway:    &networkmgmt.SubResource{ID: azure.ToStringPtr(natGatewayID)},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "NoUpdateNATGatewayCaseDiffers",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+						NATGatewayID:  azure.ToStringPtr(natGatewayID),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+					NatGateway:    &networkmgmt.SubResource{ID: azure.ToStringPtr(strings.ToUpper(natGatewayID))},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "NeedsUpdateServiceEndpointPolicyAdded",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:           addressPrefix,
+						ServiceEndpointPolicyID: azure.ToStringPtr(policyID),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "NeedsUpdateServiceEndpointPolicyRemoved",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:           &addressPrefix,
+					ServiceEndpointPolicies: &[]networkmgmt.ServiceEndpointPolicy{{ID: azure.ToStringPtr(policyID)}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "NoUpdateServiceEndpointPoliciesEmpty",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:           &addressPrefix,
+					ServiceEndpointPolicies: &[]networkmgmt.ServiceEndpointPolicy{},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "NoUpdateServiceEndpointPolicyCaseDiffers",
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:           addressPrefix,
+						ServiceEndpointPolicyID: azure.ToStringPtr(policyID),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:           &addressPrefix,
+					ServiceEndpointPolicies: &[]networkmgmt.ServiceEndpointPolicy{{ID: azure.ToStringPtr(strings.ToUpper(policyID))}},
+				},
+			},
+			want: false,
+		},
 		{
 			name: "NoUpdate",
 			kube: &v1alpha3.Subnet{
@@ -463,11 +1362,151 @@ func TestSubnetNeedsUpdate(t *testing.T) {
 		},
 	}
 
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := SubnetNeedsUpdate(tc.kube, tc.az)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SubnetNeedsUpdate(tc.kube, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("SubnetNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSubnetDrift(t *testing.T) {
+	cases := map[string]struct {
+		kube *v1alpha3.Subnet
+		az   networkmgmt.Subnet
+		want []string
+	}{
+		"NoDrift": {
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:    addressPrefix,
+						ServiceEndpoints: []v1alpha3.ServiceEndpointPropertiesFormat{{Service: "Microsoft.Sql"}, {Service: "Microsoft.Storage"}},
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+					ServiceEndpoints: &[]networkmgmt.ServiceEndpointPropertiesFormat{
+						{Service: azure.ToStringPtr("microsoft.storage"), Locations: &[]string{"westus"}},
+						{Service: azure.ToStringPtr("Microsoft.Sql")},
+					},
+				},
+			},
+		},
+		"ServiceEndpointAdded": {
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix: addressPrefix,
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:    &addressPrefix,
+					ServiceEndpoints: &[]networkmgmt.ServiceEndpointPropertiesFormat{{Service: azure.ToStringPtr("Microsoft.Sql")}},
+				},
+			},
+			want: []string{"serviceEndpoints"},
+		},
+		"SeveralFields": {
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefix:                  "10.1.0.0/16",
+						PrivateEndpointNetworkPolicies: azure.ToStringPtr(policiesDisabled),
+						NATGatewayID:                   azure.ToStringPtr("/b/nat/gateway"),
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:                  &addressPrefix,
+					PrivateEndpointNetworkPolicies: azure.ToStringPtr(policiesEnabled),
+					NatGateway:                     &networkmgmt.SubResource{ID: azure.ToStringPtr("/a/nat/gateway")},
+				},
+			},
+			want: []string{"addressPrefix", "privateEndpointNetworkPolicies", "natGatewayId"},
+		},
+		"NoDriftMultiplePrefixes": {
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefixes: []string{addressPrefix, "FD00:DB8::/64"},
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefixes: &[]string{"fd00:db8::/64", addressPrefix},
+				},
+			},
+		},
+		"AddressPrefixesChanged": {
+			kube: &v1alpha3.Subnet{
+				Spec: v1alpha3.SubnetSpec{
+					SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{
+						AddressPrefixes: []string{addressPrefix, "fd00:db8::/64"},
+					},
+				},
+			},
+			az: networkmgmt.Subnet{
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: &addressPrefix,
+				},
+			},
+			want: []string{"addressPrefixes"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SubnetDrift(tc.kube, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("SubnetDrift(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAvailableIPAddresses(t *testing.T) {
+	cases := map[string]struct {
+		prefix    string
+		allocated int
+		want      *int
+	}{
+		"Empty": {
+			prefix:    "10.0.0.0/24",
+			allocated: 0,
+			want:      intPtr(251),
+		},
+		"Allocated": {
+			prefix:    "10.0.0.0/28",
+			allocated: 3,
+			want:      intPtr(8),
+		},
+		"Full": {
+			prefix:    "10.0.0.0/29",
+			allocated: 10,
+			want:      intPtr(0),
+		},
+		"IPv6": {
+			prefix: "fd00::/64",
+		},
+		"Invalid": {
+			prefix: "wat",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := AvailableIPAddresses(tc.prefix, tc.allocated)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("SubnetNeedsUpdate(...): -want, +got\n%s", diff)
+				t.Errorf("AvailableIPAddresses(...): -want, +got\n%s", diff)
 			}
 		})
 	}
@@ -492,15 +1531,22 @@ func TestUpdateSubnetStatusFromAzure(t *testing.T) {
 				Etag: azure.ToStringPtr(etag),
 				ID:   azure.ToStringPtr(id),
 				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix:     azure.ToStringPtr("10.0.0.0/24"),
 					Purpose:           azure.ToStringPtr(purpose),
 					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					IPConfigurations: &[]networkmgmt.IPConfiguration{
+						{ID: azure.ToStringPtr("a")},
+						{ID: azure.ToStringPtr("b")},
+					},
 				},
 			},
 			want: v1alpha3.SubnetStatus{
-				State:   string(networkmgmt.Succeeded),
-				ID:      id,
-				Etag:    etag,
-				Purpose: purpose,
+				State:                   string(networkmgmt.Succeeded),
+				ID:                      id,
+				Etag:                    etag,
+				Purpose:                 purpose,
+				AddressPrefix:           "10.0.0.0/24",
+				AvailableIPAddressCount: intPtr(249),
 			},
 		},
 		{
@@ -516,6 +1562,46 @@ func TestUpdateSubnetStatusFromAzure(t *testing.T) {
 				ID:    id,
 			},
 		},
+		{
+			name: "SuccessfulMultiplePrefixes",
+			r: networkmgmt.Subnet{
+				ID: azure.ToStringPtr(id),
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefixes:   &[]string{"fd00:db8::/64", "10.0.0.0/24"},
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					IPConfigurations:  &[]networkmgmt.IPConfiguration{{ID: azure.ToStringPtr("a")}},
+				},
+			},
+			want: v1alpha3.SubnetStatus{
+				State:                   string(networkmgmt.Succeeded),
+				ID:                      id,
+				AddressPrefixes:         []string{"fd00:db8::/64", "10.0.0.0/24"},
+				AvailableIPAddressCount: intPtr(250),
+			},
+		},
+		{
+			name: "SuccessfulMultipleIPv4Prefixes",
+			r: networkmgmt.Subnet{
+				ID: azure.ToStringPtr(id),
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefixes:   &[]string{"10.0.0.0/24", "10.0.1.0/28", "fd00:db8::/64"},
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					IPConfigurations: &[]networkmgmt.IPConfiguration{
+						{ID: azure.ToStringPtr("a")},
+						{ID: azure.ToStringPtr("b"), IPConfigurationPropertiesFormat: &networkmgmt.IPConfigurationPropertiesFormat{PrivateIPAddress: azure.ToStringPtr("10.0.1.4")}},
+						{ID: azure.ToStringPtr("c"), IPConfigurationPropertiesFormat: &networkmgmt.IPConfigurationPropertiesFormat{PrivateIPAddress: azure.ToStringPtr("fd00:db8::4")}},
+					},
+				},
+			},
+			want: v1alpha3.SubnetStatus{
+				State:           string(networkmgmt.Succeeded),
+				ID:              id,
+				AddressPrefixes: []string{"10.0.0.0/24", "10.0.1.0/28", "fd00:db8::/64"},
+				// 251 + 11 available addresses, less the two IPv4
+				// configurations.
+				AvailableIPAddressCount: intPtr(260),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -542,3 +1628,504 @@ func TestUpdateSubnetStatusFromAzure(t *testing.T) {
 		})
 	}
 }
+
+func TestNewInlineSubnets(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []v1alpha3.InlineSubnet
+		want *[]networkmgmt.Subnet
+	}{
+		{
+			name: "NotSet",
+			in:   nil,
+			want: nil,
+		},
+		{
+			name: "Set",
+			in: []v1alpha3.InlineSubnet{
+				{Name: "cool", SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: addressPrefix}},
+			},
+			want: &[]networkmgmt.Subnet{
+				{
+					Name: azure.ToStringPtr("cool"),
+					SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+						AddressPrefix:    azure.ToStringPtr(addressPrefix),
+						ServiceEndpoints: NewServiceEndpoints(nil),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewInlineSubnets(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewInlineSubnets(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreserveSubnets(t *testing.T) {
+	inline := networkmgmt.Subnet{
+		Name:                   azure.ToStringPtr("inline"),
+		SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.1.0/24")},
+	}
+	standalone := networkmgmt.Subnet{
+		Name:                   azure.ToStringPtr("standalone"),
+		SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.2.0/24")},
+	}
+	staleInline := networkmgmt.Subnet{
+		Name:                   azure.ToStringPtr("INLINE"),
+		SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.3.0/24")},
+	}
+
+	cases := []struct {
+		name string
+		up   networkmgmt.VirtualNetwork
+		az   networkmgmt.VirtualNetwork
+		want *[]networkmgmt.Subnet
+	}{
+		{
+			name: "NoObservedSubnets",
+			up:   networkmgmt.VirtualNetwork{VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{}},
+			az:   networkmgmt.VirtualNetwork{},
+			want: nil,
+		},
+		{
+			name: "PreservesStandaloneSubnets",
+			up: networkmgmt.VirtualNetwork{VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+				Subnets: &[]networkmgmt.Subnet{inline},
+			}},
+			az: networkmgmt.VirtualNetwork{VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+				Subnets: &[]networkmgmt.Subnet{staleInline, standalone},
+			}},
+			want: &[]networkmgmt.Subnet{inline, standalone},
+		},
+		{
+			name: "PreservesInlineNATGateway",
+			up: networkmgmt.VirtualNetwork{VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+				Subnets: &[]networkmgmt.Subnet{{
+					Name:                   azure.ToStringPtr("inline"),
+					SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{AddressPrefix: azure.ToStringPtr("10.0.1.0/24")},
+				}},
+			}},
+			az: networkmgmt.VirtualNetwork{VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
+				Subnets: &[]networkmgmt.Subnet{{
+					Name: azure.ToStringPtr("inline"),
+					SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+						AddressPrefix: azure.ToStringPtr("10.0.1.0/24"),
+						NatGateway:    &networkmgmt.SubResource{ID: azure.ToStringPtr("/a/nat/gateway")},
+					},
+				}},
+			}},
+			want: &[]networkmgmt.Subnet{{
+				Name: azure.ToStringPtr("inline"),
+				SubnetPropertiesFormat: &networkmgmt.SubnetPropertiesFormat{
+					AddressPrefix: azure.ToStringPtr("10.0.1.0/24"),
+					NatGateway:    &networkmgmt.SubResource{ID: azure.ToStringPtr("/a/nat/gateway")},
+				},
+			}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			PreserveSubnets(&tc.up, tc.az)
+			if diff := cmp.Diff(tc.want, tc.up.Subnets); diff != "" {
+				t.Errorf("PreserveSubnets(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineSubnetConflicts(t *testing.T) {
+	vnet := func(subnets ...string) *v1alpha3.VirtualNetwork {
+		v := &v1alpha3.VirtualNetwork{Spec: v1alpha3.VirtualNetworkSpec{ResourceGroupName: "rg"}}
+		meta.SetExternalName(v, "vnet")
+		for _, s := range subnets {
+			v.Spec.VirtualNetworkPropertiesFormat.Subnets = append(v.Spec.VirtualNetworkPropertiesFormat.Subnets, v1alpha3.InlineSubnet{Name: s})
+		}
+		return v
+	}
+	subnet := func(rg, vnet, name string) *v1alpha3.Subnet {
+		s := &v1alpha3.Subnet{Spec: v1alpha3.SubnetSpec{ResourceGroupName: rg, VirtualNetworkName: vnet}}
+		meta.SetExternalName(s, name)
+		return s
+	}
+
+	cases := []struct {
+		name string
+		v    *v1alpha3.VirtualNetwork
+		s    *v1alpha3.Subnet
+		want bool
+	}{
+		{
+			name: "Conflict",
+			v:    vnet("cool"),
+			s:    subnet("rg", "vnet", "cool"),
+			want: true,
+		},
+		{
+			name: "DifferentName",
+			v:    vnet("cool"),
+			s:    subnet("rg", "vnet", "other"),
+			want: false,
+		},
+		{
+			name: "DifferentVirtualNetwork",
+			v:    vnet("cool"),
+			s:    subnet("rg", "othervnet", "cool"),
+			want: false,
+		},
+		{
+			name: "DifferentResourceGroup",
+			v:    vnet("cool"),
+			s:    subnet("otherrg", "vnet", "cool"),
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := InlineSubnetConflicts(tc.v, tc.s)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("InlineSubnetConflicts(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewNATGatewayParameters(t *testing.T) {
+	cases := map[string]struct {
+		g    *v1alpha3.NATGateway
+		want networkmgmt.NatGateway
+	}{
+		"Full": {
+			g: &v1alpha3.NATGateway{
+				Spec: v1alpha3.NATGatewaySpec{
+					Location: location,
+					Zones:    []string{"1"},
+					Tags:     tags,
+					NATGatewayPropertiesFormat: v1alpha3.NATGatewayPropertiesFormat{
+						IdleTimeoutInMinutes: &idleTimeout,
+						PublicIPAddressIDs:   []string{publicIPID},
+					},
+				},
+			},
+			want: networkmgmt.NatGateway{
+				Location: azure.ToStringPtr(location),
+				Zones:    &[]string{"1"},
+				Tags:     azure.ToStringPtrMap(tags),
+				Sku:      &networkmgmt.NatGatewaySku{Name: networkmgmt.Standard},
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+					IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+					PublicIPAddresses:    &[]networkmgmt.SubResource{{ID: azure.ToStringPtr(publicIPID)}},
+				},
+			},
+		},
+		"Minimal": {
+			g: &v1alpha3.NATGateway{
+				Spec: v1alpha3.NATGatewaySpec{Location: location},
+			},
+			want: networkmgmt.NatGateway{
+				Location:                   azure.ToStringPtr(location),
+				Sku:                        &networkmgmt.NatGatewaySku{Name: networkmgmt.Standard},
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewNATGatewayParameters(tc.g)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewNATGatewayParameters(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNATGatewayNeedsUpdate(t *testing.T) {
+	g := &v1alpha3.NATGateway{
+		Spec: v1alpha3.NATGatewaySpec{
+			Location: location,
+			NATGatewayPropertiesFormat: v1alpha3.NATGatewayPropertiesFormat{
+				IdleTimeoutInMinutes: &idleTimeout,
+				PublicIPAddressIDs:   []string{publicIPID},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		az   networkmgmt.NatGateway
+		want bool
+	}{
+		"UpToDate": {
+			az: networkmgmt.NatGateway{
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+					IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+					PublicIPAddresses:    &[]networkmgmt.SubResource{{ID: azure.ToStringPtr(strings.ToUpper(publicIPID))}},
+				},
+			},
+			want: false,
+		},
+		"IdleTimeoutChanged": {
+			az: networkmgmt.NatGateway{
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+					IdleTimeoutInMinutes: to.Int32Ptr(4),
+					PublicIPAddresses:    &[]networkmgmt.SubResource{{ID: azure.ToStringPtr(publicIPID)}},
+				},
+			},
+			want: true,
+		},
+		"PublicIPAddressRemoved": {
+			az: networkmgmt.NatGateway{
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+					IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+				},
+			},
+			want: true,
+		},
+		"NoProperties": {
+			az:   networkmgmt.NatGateway{},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NATGatewayNeedsUpdate(g, tc.az, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NATGatewayNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateNATGatewayStatusFromAzure(t *testing.T) {
+	subnetID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cool-subnet"
+
+	cases := map[string]struct {
+		az   networkmgmt.NatGateway
+		want v1alpha3.NATGatewayStatus
+	}{
+		"Full": {
+			az: networkmgmt.NatGateway{
+				ID:   azure.ToStringPtr(id),
+				Etag: azure.ToStringPtr(etag),
+				NatGatewayPropertiesFormat: &networkmgmt.NatGatewayPropertiesFormat{
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					ResourceGUID:      azure.ToStringPtr(string(uid)),
+					Subnets:           &[]networkmgmt.SubResource{{ID: azure.ToStringPtr(subnetID)}},
+				},
+			},
+			want: v1alpha3.NATGatewayStatus{
+				State:        "Succeeded",
+				ID:           id,
+				Etag:         etag,
+				ResourceGUID: string(uid),
+				SubnetIDs:    []string{subnetID},
+			},
+		},
+		"NoProperties": {
+			az: networkmgmt.NatGateway{ID: azure.ToStringPtr(id)},
+			want: v1alpha3.NATGatewayStatus{
+				ID: id,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := &v1alpha3.NATGateway{}
+			UpdateNATGatewayStatusFromAzure(g, tc.az)
+			if diff := cmp.Diff(tc.want, g.Status); diff != "" {
+				t.Errorf("UpdateNATGatewayStatusFromAzure(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewServiceEndpointPolicyParameters(t *testing.T) {
+	cases := map[string]struct {
+		p    *v1alpha3.ServiceEndpointPolicy
+		want networkmgmt.ServiceEndpointPolicy
+	}{
+		"Full": {
+			p: &v1alpha3.ServiceEndpointPolicy{
+				Spec: v1alpha3.ServiceEndpointPolicySpec{
+					Location: location,
+					Tags:     tags,
+					ServiceEndpointPolicyPropertiesFormat: v1alpha3.ServiceEndpointPolicyPropertiesFormat{
+						ServiceEndpointPolicyDefinitions: []v1alpha3.ServiceEndpointPolicyDefinition{{
+							Name:             "cool-definition",
+							Description:      azure.ToStringPtr("cool"),
+							Service:          "Microsoft.Storage",
+							ServiceResources: []string{accountID},
+						}},
+					},
+				},
+			},
+			want: networkmgmt.ServiceEndpointPolicy{
+				Location: azure.ToStringPtr(location),
+				Tags:     azure.ToStringPtrMap(tags),
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ServiceEndpointPolicyDefinitions: &[]networkmgmt.ServiceEndpointPolicyDefinition{{
+						Name: azure.ToStringPtr("cool-definition"),
+						ServiceEndpointPolicyDefinitionPropertiesFormat: &networkmgmt.ServiceEndpointPolicyDefinitionPropertiesFormat{
+							Description:      azure.ToStringPtr("cool"),
+							Service:          azure.ToStringPtr("Microsoft.Storage"),
+							ServiceResources: &[]string{accountID},
+						},
+					}},
+				},
+			},
+		},
+		"Minimal": {
+			p: &v1alpha3.ServiceEndpointPolicy{
+				Spec: v1alpha3.ServiceEndpointPolicySpec{Location: location},
+			},
+			want: networkmgmt.ServiceEndpointPolicy{
+				Location: azure.ToStringPtr(location),
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ServiceEndpointPolicyDefinitions: &[]networkmgmt.ServiceEndpointPolicyDefinition{},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewServiceEndpointPolicyParameters(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewServiceEndpointPolicyParameters(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServiceEndpointPolicyNeedsUpdate(t *testing.T) {
+	p := &v1alpha3.ServiceEndpointPolicy{
+		Spec: v1alpha3.ServiceEndpointPolicySpec{
+			Location: location,
+			ServiceEndpointPolicyPropertiesFormat: v1alpha3.ServiceEndpointPolicyPropertiesFormat{
+				ServiceEndpointPolicyDefinitions: []v1alpha3.ServiceEndpointPolicyDefinition{{
+					Name:             "cool-definition",
+					Service:          "Microsoft.Storage",
+					ServiceResources: []string{accountID},
+				}},
+			},
+		},
+	}
+	definition := func(name, service string, resources ...string) networkmgmt.ServiceEndpointPolicyDefinition {
+		return networkmgmt.ServiceEndpointPolicyDefinition{
+			Name: azure.ToStringPtr(name),
+			ServiceEndpointPolicyDefinitionPropertiesFormat: &networkmgmt.ServiceEndpointPolicyDefinitionPropertiesFormat{
+				Service:          azure.ToStringPtr(service),
+				ServiceResources: &resources,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		az   networkmgmt.ServiceEndpointPolicy
+		want bool
+	}{
+		"UpToDate": {
+			az: networkmgmt.ServiceEndpointPolicy{
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ServiceEndpointPolicyDefinitions: &[]networkmgmt.ServiceEndpointPolicyDefinition{
+						definition("Cool-Definition", "microsoft.storage", strings.ToUpper(accountID)),
+					},
+				},
+			},
+			want: false,
+		},
+		"ServiceResourceAdded": {
+			az: networkmgmt.ServiceEndpointPolicy{
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ServiceEndpointPolicyDefinitions: &[]networkmgmt.ServiceEndpointPolicyDefinition{
+						definition("cool-definition", "Microsoft.Storage", accountID, "/subscriptions/sub"),
+					},
+				},
+			},
+			want: true,
+		},
+		"DefinitionAdded": {
+			az: networkmgmt.ServiceEndpointPolicy{
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ServiceEndpointPolicyDefinitions: &[]networkmgmt.ServiceEndpointPolicyDefinition{
+						definition("cool-definition", "Microsoft.Storage", accountID),
+						definition("other-definition", "Microsoft.Storage", accountID),
+					},
+				},
+			},
+			want: true,
+		},
+		"DefinitionRemoved": {
+			az: networkmgmt.ServiceEndpointPolicy{
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{},
+			},
+			want: true,
+		},
+		"NoProperties": {
+			az:   networkmgmt.ServiceEndpointPolicy{},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ServiceEndpointPolicyNeedsUpdate(p, tc.az, azure.DefaultIgnoredTagPrefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ServiceEndpointPolicyNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateServiceEndpointPolicyStatusFromAzure(t *testing.T) {
+	subnetID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cool-subnet"
+
+	cases := map[string]struct {
+		az   networkmgmt.ServiceEndpointPolicy
+		want v1alpha3.ServiceEndpointPolicyStatus
+	}{
+		"Full": {
+			az: networkmgmt.ServiceEndpointPolicy{
+				ID:   azure.ToStringPtr(id),
+				Etag: azure.ToStringPtr(etag),
+				ServiceEndpointPolicyPropertiesFormat: &networkmgmt.ServiceEndpointPolicyPropertiesFormat{
+					ProvisioningState: azure.ToStringPtr("Succeeded"),
+					ResourceGUID:      azure.ToStringPtr(string(uid)),
+					Subnets:           &[]networkmgmt.Subnet{{ID: azure.ToStringPtr(subnetID)}},
+				},
+			},
+			want: v1alpha3.ServiceEndpointPolicyStatus{
+				State:        "Succeeded",
+				ID:           id,
+				Etag:         etag,
+				ResourceGUID: string(uid),
+				SubnetIDs:    []string{subnetID},
+			},
+		},
+		"NoProperties": {
+			az: networkmgmt.ServiceEndpointPolicy{ID: azure.ToStringPtr(id)},
+			want: v1alpha3.ServiceEndpointPolicyStatus{
+				ID: id,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &v1alpha3.ServiceEndpointPolicy{}
+			UpdateServiceEndpointPolicyStatusFromAzure(p, tc.az)
+			if diff := cmp.Diff(tc.want, p.Status); diff != "" {
+				t.Errorf("UpdateServiceEndpointPolicyStatusFromAzure(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	networkmgmt "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// Provisioning states of network resources.
+const (
+	ProvisioningStateDeleting  = string(networkmgmt.Deleting)
+	ProvisioningStateFailed    = string(networkmgmt.Failed)
+	ProvisioningStateSucceeded = string(networkmgmt.Succeeded)
+	ProvisioningStateUpdating  = string(networkmgmt.Updating)
+)
+
+// Condition messages.
+const (
+	msgUpdating       = "Azure reports the resource is being updated"
+	msgFailed         = "Azure reports the resource is in a failed state"
+	msgFmtUnavailable = "Azure reports the resource's provisioning state is %q"
+)
+
+const errFailed = "Azure reports the resource's last operation failed; it was resubmitted to retry the operation"
+
+// Condition returns the condition that corresponds to the supplied provisioning
+// state. Only resources that Azure reports have succeeded are available.
+func Condition(state string) runtimev1alpha1.Condition {
+	switch state {
+	case ProvisioningStateSucceeded:
+		return runtimev1alpha1.Available()
+	case ProvisioningStateUpdating:
+		return runtimev1alpha1.Unavailable().WithMessage(msgUpdating)
+	case ProvisioningStateDeleting:
+		return runtimev1alpha1.Deleting()
+	case ProvisioningStateFailed:
+		return runtimev1alpha1.Unavailable().WithMessage(msgFailed)
+	}
+	return runtimev1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtUnavailable, state))
+}
+
+// Failed returns an error if Azure reports that the last operation on a
+// resource in the supplied provisioning state failed. Such resources should be
+// submitted to Azure again, which retries the failed operation.
+func Failed(state string) error {
+	if state != ProvisioningStateFailed {
+		return nil
+	}
+	return errors.New(errFailed)
+}
+
+// IsOperationInProgress returns true if Azure is operating on a resource in
+// the supplied provisioning state, and will thus reject requests to update it.
+func IsOperationInProgress(state string) bool {
+	return state == ProvisioningStateUpdating || state == ProvisioningStateDeleting
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCondition(t *testing.T) {
+	cases := map[string]struct {
+		state string
+		want  runtimev1alpha1.Condition
+	}{
+		"Succeeded": {
+			state: ProvisioningStateSucceeded,
+			want:  runtimev1alpha1.Available(),
+		},
+		"Updating": {
+			state: ProvisioningStateUpdating,
+			want:  runtimev1alpha1.Unavailable().WithMessage(msgUpdating),
+		},
+		"Deleting": {
+			state: ProvisioningStateDeleting,
+			want:  runtimev1alpha1.Deleting(),
+		},
+		"Failed": {
+			state: ProvisioningStateFailed,
+			want:  runtimev1alpha1.Unavailable().WithMessage(msgFailed),
+		},
+		"Unknown": {
+			state: "Wat",
+			want:  runtimev1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtUnavailable, "Wat")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Condition(tc.state)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Condition(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsOperationInProgress(t *testing.T) {
+	cases := map[string]struct {
+		state string
+		want  bool
+	}{
+		"Succeeded": {state: ProvisioningStateSucceeded, want: false},
+		"Failed":    {state: ProvisioningStateFailed, want: false},
+		"Updating":  {state: ProvisioningStateUpdating, want: true},
+		"Deleting":  {state: ProvisioningStateDeleting, want: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsOperationInProgress(tc.state)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsOperationInProgress(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFailed(t *testing.T) {
+	cases := map[string]struct {
+		state string
+		want  error
+	}{
+		"Succeeded": {state: ProvisioningStateSucceeded},
+		"Updating":  {state: ProvisioningStateUpdating},
+		"Failed":    {state: ProvisioningStateFailed, want: errors.New(errFailed)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Failed(tc.state)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("Failed(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errObserveOnlyCreate = "cannot create external resource: the provider is running in observe-only mode"
+	errObserveOnlyUpdate = "cannot update external resource: the provider is running in observe-only mode"
+	errObserveOnlyDelete = "cannot delete external resource: the provider is running in observe-only mode"
+)
+
+// NewObserveOnlyConnecter returns a managed.ExternalConnecter whose clients
+// never create, update, or delete external resources if observeOnly is true.
+// External resources are still observed, so that the status of their
+// managed resources is populated. Managed resources that are deleted orphan
+// their external resources.
+func NewObserveOnlyConnecter(c managed.ExternalConnecter, observeOnly bool) managed.ExternalConnecter {
+	return &observeOnlyConnecter{ExternalConnecter: c, observeOnly: observeOnly}
+}
+
+type observeOnlyConnecter struct {
+	managed.ExternalConnecter
+	observeOnly bool
+}
+
+func (c *observeOnlyConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil || !c.observeOnly {
+		return e, err
+	}
+	return &observeOnlyExternal{ExternalClient: e}, nil
+}
+
+type observeOnlyExternal struct {
+	managed.ExternalClient
+}
+
+func (e *observeOnlyExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+
+	// Reporting that the external resource is up to date ensures the managed
+	// reconciler never tries to update it.
+	o.ResourceUpToDate = true
+
+	// Reporting that the external resource of a deleted managed resource does
+	// not exist ensures the managed reconciler finalizes the managed resource
+	// without trying to delete it.
+	if meta.WasDeleted(mg) {
+		o.ResourceExists = false
+	}
+	return o, nil
+}
+
+func (e *observeOnlyExternal) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, errors.New(errObserveOnlyCreate)
+}
+
+func (e *observeOnlyExternal) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, errors.New(errObserveOnlyUpdate)
+}
+
+func (e *observeOnlyExternal) Delete(_ context.Context, _ resource.Managed) error {
+	return errors.New(errObserveOnlyDelete)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestObserveOnlyConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+	ec := &managed.ExternalClientFns{}
+
+	type want struct {
+		e   managed.ExternalClient
+		err error
+	}
+
+	cases := map[string]struct {
+		observeOnly bool
+		c           managed.ExternalConnecter
+		want        want
+	}{
+		"Disabled": {
+			c: managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}),
+			want: want{e: ec},
+		},
+		"Enabled": {
+			observeOnly: true,
+			c: managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}),
+			want: want{e: &observeOnlyExternal{ExternalClient: ec}},
+		},
+		"ConnectError": {
+			observeOnly: true,
+			c: managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return nil, errBoom
+			}),
+			want: want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewObserveOnlyConnecter(tc.c, tc.observeOnly).Connect(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Connect(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.e, e, cmp.AllowUnexported(observeOnlyExternal{})); diff != "" {
+				t.Errorf("Connect(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnlyExternalObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.NewTime(time.Now())
+
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		mg      resource.Managed
+		observe func(context.Context, resource.Managed) (managed.ExternalObservation, error)
+		want    want
+	}{
+		"NeedsUpdate": {
+			mg: &fake.Managed{},
+			observe: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Deleted": {
+			mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			observe: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}},
+		},
+		"Error": {
+			mg: &fake.Managed{},
+			observe: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{}, errBoom
+			},
+			want: want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &observeOnlyExternal{ExternalClient: &managed.ExternalClientFns{ObserveFn: tc.observe}}
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnlyExternalMutations(t *testing.T) {
+	called := false
+	e := &observeOnlyExternal{ExternalClient: &managed.ExternalClientFns{
+		CreateFn: func(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+			called = true
+			return managed.ExternalCreation{}, nil
+		},
+		UpdateFn: func(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+			called = true
+			return managed.ExternalUpdate{}, nil
+		},
+		DeleteFn: func(context.Context, resource.Managed) error {
+			called = true
+			return nil
+		},
+	}}
+
+	if _, err := e.Create(context.Background(), &fake.Managed{}); err == nil || err.Error() != errObserveOnlyCreate {
+		t.Errorf("Create(...): want error %q, got %v", errObserveOnlyCreate, err)
+	}
+	if _, err := e.Update(context.Background(), &fake.Managed{}); err == nil || err.Error() != errObserveOnlyUpdate {
+		t.Errorf("Update(...): want error %q, got %v", errObserveOnlyUpdate, err)
+	}
+	if err := e.Delete(context.Background(), &fake.Managed{}); err == nil || err.Error() != errObserveOnlyDelete {
+		t.Errorf("Delete(...): want error %q, got %v", errObserveOnlyDelete, err)
+	}
+	if called {
+		t.Errorf("observeOnlyExternal called the external client it wraps to mutate an external resource")
+	}
+}
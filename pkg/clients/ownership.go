@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Ownership tags are written to the external resources created by a managed
+// resource, and are used to determine whether an existing external resource
+// may be managed.
+const (
+	TagKeyKind = "crossplane-kind"
+	TagKeyUID  = "crossplane-uid"
+)
+
+// AnnotationKeyAdopt may be set to "true" on a managed resource to allow it
+// to manage an existing external resource that it did not create.
+const AnnotationKeyAdopt = "azure.crossplane.io/adopt"
+
+const (
+	errFmtNotOwned     = "external resource is not tagged as owned by this managed resource; set annotation %s to \"true\" to adopt it"
+	errFmtOwnedByOther = "external resource is owned by %s with UID %s"
+	errFmtExists       = "external resource already exists; refusing to overwrite it unless annotation %s is set to \"true\""
+)
+
+// IsOwnershipTag returns true if the supplied tag key is one of the tags used
+// to record ownership of an external resource.
+func IsOwnershipTag(key string) bool {
+	return strings.EqualFold(key, TagKeyKind) || strings.EqualFold(key, TagKeyUID)
+}
+
+// WithOwnershipTags returns a copy of the supplied tags, plus the ownership
+// tags of the supplied managed resource of the supplied kind.
+func WithOwnershipTags(tags map[string]*string, kind string, mg resource.Managed) map[string]*string {
+	out := make(map[string]*string, len(tags)+2)
+	for k, v := range tags {
+		out[k] = v
+	}
+	out[TagKeyKind] = ToStringPtr(kind)
+	out[TagKeyUID] = ToStringPtr(string(mg.GetUID()))
+	return out
+}
+
+// IsAdoptable returns true if the supplied managed resource may manage an
+// existing external resource that it did not create.
+func IsAdoptable(mg resource.Managed) bool {
+	return strings.EqualFold(mg.GetAnnotations()[AnnotationKeyAdopt], "true")
+}
+
+// ValidateCreate returns an error if the supplied managed resource is about to
+// create an external resource that already exists, unless it is annotated for
+// adoption. It protects external resources that cannot be tagged with their
+// owner from being overwritten by an unrelated managed resource.
+func ValidateCreate(mg resource.Managed, exists bool) error {
+	if !exists || IsAdoptable(mg) {
+		return nil
+	}
+	return errors.Errorf(errFmtExists, AnnotationKeyAdopt)
+}
+
+// ValidateOwnership returns an error if the supplied managed resource may not
+// manage the external resource with the supplied ID and tags. An external
+// resource may be managed if it is tagged with the UID of the managed resource,
+// or if the managed resource is annotated for adoption. External resources
+// without ownership tags are also tolerated if their ID is the supplied ID that
+// the managed resource recorded when it last observed its external resource,
+// so that resources created before ownership tags were written continue to be
+// managed. Callers should report that the external resource of a managed
+// resource that is being deleted does not exist if this returns an error, so
+// that the managed resource is released without deleting an external resource
+// it does not own. Ownership need not be validated in observe-only mode, in
+// which external resources are never mutated.
+func ValidateOwnership(mg resource.Managed, recordedID, id string, tags map[string]*string) error {
+	if IsAdoptable(mg) {
+		return nil
+	}
+	uid, ok := tags[TagKeyUID]
+	if ok && ToString(uid) == string(mg.GetUID()) {
+		return nil
+	}
+	if ok {
+		return errors.Errorf(errFmtOwnedByOther, ToString(tags[TagKeyKind]), ToString(uid))
+	}
+	if recordedID != "" && strings.EqualFold(recordedID, id) {
+		return nil
+	}
+	return errors.Errorf(errFmtNotOwned, AnnotationKeyAdopt)
+}
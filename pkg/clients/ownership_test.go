@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestWithOwnershipTags(t *testing.T) {
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: types.UID("cool-uid")}}
+	tags := map[string]*string{"cool": ToStringPtr("tag")}
+
+	want := map[string]*string{
+		"cool":     ToStringPtr("tag"),
+		TagKeyKind: ToStringPtr("Cool"),
+		TagKeyUID:  ToStringPtr("cool-uid"),
+	}
+	got := WithOwnershipTags(tags, "Cool", mg)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WithOwnershipTags(...): -want, +got:\n%s", diff)
+	}
+	if _, ok := tags[TagKeyUID]; ok {
+		t.Errorf("WithOwnershipTags(...): modified the supplied tags")
+	}
+}
+
+func TestValidateOwnership(t *testing.T) {
+	uid := types.UID("cool-uid")
+	owned := map[string]*string{TagKeyKind: ToStringPtr("Cool"), TagKeyUID: ToStringPtr(string(uid))}
+	other := map[string]*string{TagKeyKind: ToStringPtr("Cool"), TagKeyUID: ToStringPtr("other-uid")}
+
+	id := "/subscriptions/cool/resourceGroups/cool/providers/Microsoft.Cool/cools/cool"
+
+	cases := map[string]struct {
+		mg         *fake.Managed
+		recordedID string
+		tags       map[string]*string
+		want       error
+	}{
+		"Owned": {
+			mg:   &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}},
+			tags: owned,
+		},
+		"NotTagged": {
+			mg:   &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}},
+			want: errors.Errorf(errFmtNotOwned, AnnotationKeyAdopt),
+		},
+		"NotTaggedButPreviouslyObserved": {
+			mg:         &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}},
+			recordedID: strings.ToLower(id),
+		},
+		"NotTaggedAndPreviouslyObservedAnother": {
+			mg:         &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}},
+			recordedID: id + "-other",
+			want:       errors.Errorf(errFmtNotOwned, AnnotationKeyAdopt),
+		},
+		"NotTaggedButReconciled": {
+			mg: &fake.Managed{
+				ObjectMeta:        metav1.ObjectMeta{UID: uid},
+				ConditionedStatus: runtimev1alpha1.ConditionedStatus{Conditions: []runtimev1alpha1.Condition{runtimev1alpha1.ReconcileError(errors.New("boom"))}},
+			},
+			want: errors.Errorf(errFmtNotOwned, AnnotationKeyAdopt),
+		},
+		"OwnedByOther": {
+			mg:         &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}},
+			recordedID: id,
+			tags:       other,
+			want:       errors.Errorf(errFmtOwnedByOther, "Cool", "other-uid"),
+		},
+		"Adopted": {
+			mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+				UID:         uid,
+				Annotations: map[string]string{AnnotationKeyAdopt: "true"},
+			}},
+			tags: other,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateOwnership(tc.mg, tc.recordedID, id, tc.tags)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateOwnership(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		mg     *fake.Managed
+		exists bool
+		want   error
+	}{
+		"DoesNotExist": {
+			mg: &fake.Managed{},
+		},
+		"Exists": {
+			mg:     &fake.Managed{},
+			exists: true,
+			want:   errors.Errorf(errFmtExists, AnnotationKeyAdopt),
+		},
+		"ExistsButAdopted": {
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyAdopt: "true"}}},
+			exists: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateCreate(tc.mg, tc.exists)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateCreate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
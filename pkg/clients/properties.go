@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+)
+
+// Error strings.
+const (
+	errReadRequestBody    = "cannot read request body"
+	errDecodeRequestBody  = "cannot decode request body"
+	errEncodeRequestBody  = "cannot encode request body"
+	errReadResponseBody   = "cannot read response body"
+	errDecodeResponseBody = "cannot decode response body"
+)
+
+// WithProperties returns a PrepareDecorator that merges the supplied
+// properties into the properties of create and update (i.e. PUT and PATCH)
+// request bodies. It allows us to set properties that are newer than the Azure
+// SDK version we use, and thus cannot be set via its models.
+func WithProperties(props map[string]interface{}) autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil || len(props) == 0 || r.Body == nil {
+				return r, err
+			}
+			if r.Method != http.MethodPut && r.Method != http.MethodPatch {
+				return r, nil
+			}
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return r, errors.Wrap(err, errReadRequestBody)
+			}
+			body := map[string]interface{}{}
+			if err := json.Unmarshal(b, &body); err != nil {
+				return r, errors.Wrap(err, errDecodeRequestBody)
+			}
+			existing, ok := body["properties"].(map[string]interface{})
+			if !ok {
+				existing = map[string]interface{}{}
+			}
+			for k, v := range props {
+				existing[k] = v
+			}
+			body["properties"] = existing
+			b, err = json.Marshal(body)
+			if err != nil {
+				return r, errors.Wrap(err, errEncodeRequestBody)
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(b))
+			r.ContentLength = int64(len(b))
+			return r, nil
+		})
+	}
+}
+
+// WithAPIVersion returns a PrepareDecorator that overrides the API version of
+// requests, unless the supplied version is empty. Properties that are newer
+// than the Azure SDK version we use are typically ignored by Azure unless they
+// are sent to, or read from, a newer API version.
+func WithAPIVersion(v string) autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil || v == "" {
+				return r, err
+			}
+			q := r.URL.Query()
+			q.Set("api-version", v)
+			r.URL.RawQuery = q.Encode()
+			return r, nil
+		})
+	}
+}
+
+// ByDecodingProperties returns a RespondDecorator that decodes the properties
+// of successful GET response bodies into the supplied value, leaving the body
+// intact for subsequent decorators. It is the counterpart of WithProperties.
+func ByDecodingProperties(into interface{}) autorest.RespondDecorator {
+	return func(r autorest.Responder) autorest.Responder {
+		return autorest.ResponderFunc(func(resp *http.Response) error {
+			if resp == nil || resp.Body == nil || resp.Request == nil || resp.Request.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+				return r.Respond(resp)
+			}
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return errors.Wrap(err, errReadResponseBody)
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			body := struct {
+				Properties interface{} `json:"properties"`
+			}{Properties: into}
+			if err := json.Unmarshal(b, &body); err != nil {
+				return errors.Wrap(err, errDecodeResponseBody)
+			}
+			return r.Respond(resp)
+		})
+	}
+}
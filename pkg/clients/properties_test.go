@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithProperties(t *testing.T) {
+	cases := map[string]struct {
+		props  map[string]interface{}
+		method string
+		body   string
+		want   string
+	}{
+		"NoProperties": {
+			method: http.MethodPut,
+			body:   `{"location":"westus"}`,
+			want:   `{"location":"westus"}`,
+		},
+		"Create": {
+			props:  map[string]interface{}{"flowTimeoutInMinutes": 10},
+			method: http.MethodPut,
+			body:   `{"location":"westus","properties":{"enableVmProtection":false}}`,
+			want:   `{"location":"westus","properties":{"enableVmProtection":false,"flowTimeoutInMinutes":10}}`,
+		},
+		"Update": {
+			props:  map[string]interface{}{"redisVersion": "6"},
+			method: http.MethodPatch,
+			body:   `{}`,
+			want:   `{"properties":{"redisVersion":"6"}}`,
+		},
+		"OtherMethod": {
+			props:  map[string]interface{}{"redisVersion": "6"},
+			method: http.MethodPost,
+			body:   `{}`,
+			want:   `{}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(tc.method, "https://example.org", ioutil.NopCloser(bytes.NewBufferString(tc.body)))
+			r, err := autorest.CreatePreparer(WithProperties(tc.props)).Prepare(r)
+			if err != nil {
+				t.Fatalf("Prepare(...): %s", err)
+			}
+			got, _ := ioutil.ReadAll(r.Body)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("WithProperties(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	cases := map[string]struct {
+		version string
+		want    string
+	}{
+		"Unset": {
+			want: "https://example.org/vnet?api-version=2019-06-01",
+		},
+		"Override": {
+			version: "2021-05-01",
+			want:    "https://example.org/vnet?api-version=2021-05-01",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "https://example.org/vnet?api-version=2019-06-01", nil)
+			r, err := autorest.CreatePreparer(WithAPIVersion(tc.version)).Prepare(r)
+			if err != nil {
+				t.Fatalf("Prepare(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, r.URL.String()); diff != "" {
+				t.Errorf("WithAPIVersion(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestByDecodingProperties(t *testing.T) {
+	type properties struct {
+		FlowTimeoutInMinutes *int32 `json:"flowTimeoutInMinutes,omitempty"`
+	}
+	timeout := int32(10)
+
+	cases := map[string]struct {
+		method string
+		status int
+		body   string
+		want   properties
+	}{
+		"Get": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			body:   `{"name":"vnet","properties":{"flowTimeoutInMinutes":10}}`,
+			want:   properties{FlowTimeoutInMinutes: &timeout},
+		},
+		"NotFound": {
+			method: http.MethodGet,
+			status: http.StatusNotFound,
+			body:   `{"error":{"code":"NotFound"}}`,
+		},
+		"Put": {
+			method: http.MethodPut,
+			status: http.StatusOK,
+			body:   `{"name":"vnet","properties":{"flowTimeoutInMinutes":10}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, "https://example.org", nil)
+			resp := &http.Response{Request: req, StatusCode: tc.status, Body: ioutil.NopCloser(bytes.NewBufferString(tc.body))}
+			got := properties{}
+			if err := autorest.Respond(resp, ByDecodingProperties(&got)); err != nil {
+				t.Fatalf("Respond(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ByDecodingProperties(...): -want, +got\n%s", diff)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if diff := cmp.Diff(tc.body, string(body)); diff != "" {
+				t.Errorf("ByDecodingProperties(...): -want body, +got body\n%s", diff)
+			}
+		})
+	}
+}
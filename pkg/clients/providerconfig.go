@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+type providerConfigKey struct{}
+
+// A providerConfig is the ProviderConfig or the deprecated Provider that a
+// managed resource references. Exactly one of its fields is set.
+type providerConfig struct {
+	pc *v1beta1.ProviderConfig
+	p  *v1alpha3.Provider
+}
+
+// WithProviderConfig returns a copy of the supplied context that carries the
+// ProviderConfig (or Provider) of the supplied managed resource. Functions
+// that read the ProviderConfig of a managed resource, such as GetAuthInfo,
+// use the one carried by their context rather than reading it again.
+func WithProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (context.Context, error) {
+	cfg, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, providerConfigKey{}, cfg), nil
+}
+
+// getProviderConfig returns the ProviderConfig (or Provider) of the supplied
+// managed resource, preferring one carried by the supplied context.
+func getProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (providerConfig, error) {
+	cfg, _ := ctx.Value(providerConfigKey{}).(providerConfig)
+	switch {
+	case mg.GetProviderConfigReference() != nil:
+		name := mg.GetProviderConfigReference().Name
+		if cfg.pc != nil && cfg.pc.GetName() == name {
+			return cfg, nil
+		}
+		pc := &v1beta1.ProviderConfig{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+			return providerConfig{}, errors.Wrap(err, errGetProviderConfig)
+		}
+		return providerConfig{pc: pc}, nil
+	case mg.GetProviderReference() != nil:
+		name := mg.GetProviderReference().Name
+		if cfg.p != nil && cfg.p.GetName() == name {
+			return cfg, nil
+		}
+		p := &v1alpha3.Provider{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, p); err != nil {
+			return providerConfig{}, errors.Wrap(err, errGetProvider)
+		}
+		return providerConfig{p: p}, nil
+	default:
+		return providerConfig{}, errors.New(errNeitherPCNorPGiven)
+	}
+}
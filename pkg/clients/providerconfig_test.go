@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+func TestWithProviderConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// getOnce returns a client that can get the supplied object only once.
+	getOnce := func(o runtime.Object) client.Client {
+		got := false
+		return &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			if got {
+				return errBoom
+			}
+			got = true
+			switch o := o.(type) {
+			case *v1beta1.ProviderConfig:
+				*obj.(*v1beta1.ProviderConfig) = *o
+			case *v1alpha3.Provider:
+				*obj.(*v1alpha3.Provider) = *o
+			}
+			return nil
+		}}
+	}
+	timeout := &metav1.Duration{Duration: time.Minute}
+
+	type want struct {
+		timeout time.Duration
+		err     error
+	}
+
+	cases := map[string]struct {
+		c    client.Client
+		mg   resource.Managed
+		want want
+	}{
+		"ProviderConfig": {
+			c: getOnce(&v1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec:       v1beta1.ProviderConfigSpec{OperationTimeout: timeout},
+			}),
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{timeout: time.Minute},
+		},
+		"Provider": {
+			c: getOnce(&v1alpha3.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec:       v1alpha3.ProviderSpec{OperationTimeout: timeout},
+			}),
+			mg:   &fake.Managed{ProviderReferencer: fake.ProviderReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{timeout: time.Minute},
+		},
+		"DifferentProviderConfig": {
+			c: getOnce(&v1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       v1beta1.ProviderConfigSpec{OperationTimeout: timeout},
+			}),
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+		"GetProviderConfigError": {
+			c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, err := WithProviderConfig(context.Background(), tc.c, tc.mg)
+			if err == nil {
+				// The ProviderConfig carried by the context must be used
+				// rather than being read again.
+				var got time.Duration
+				got, err = GetOperationTimeout(ctx, tc.c, tc.mg)
+				if diff := cmp.Diff(tc.want.timeout, got); diff != "" {
+					t.Errorf("GetOperationTimeout(...): -want, +got\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("WithProviderConfig(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/redis/mgmt/redis"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// Public network access values.
+const (
+	PublicNetworkAccessEnabled  = "Enabled"
+	PublicNetworkAccessDisabled = "Disabled"
+)
+
+// PublicNetworkAccessAPIVersion is the oldest API version that may be used to
+// read and write the publicNetworkAccess property of a Redis.
+const PublicNetworkAccessAPIVersion = "2020-06-01"
+
+// ObservedProperties are the properties of a Redis that are newer than the
+// Azure SDK version we use, and are thus decoded from response bodies using
+// azure.ByDecodingProperties.
+type ObservedProperties struct {
+	PublicNetworkAccess *string `json:"publicNetworkAccess,omitempty"`
+	ReplicasPerMaster   *int32  `json:"replicasPerMaster,omitempty"`
+}
+
+// WithPublicNetworkAccess returns a PrepareDecorator that sets the
+// publicNetworkAccess property of Redis create and update requests to the
+// supplied value.
+func WithPublicNetworkAccess(v *string) autorest.PrepareDecorator {
+	if v == nil {
+		return azure.WithProperties(nil)
+	}
+	return azure.WithProperties(map[string]interface{}{"publicNetworkAccess": *v})
+}
+
+// PublicNetworkAccessNeedsUpdate returns true if the supplied spec requests a
+// different publicNetworkAccess than the supplied observed properties.
+func PublicNetworkAccessNeedsUpdate(spec v1beta1.RedisParameters, observed ObservedProperties) bool {
+	if spec.PublicNetworkAccess == nil {
+		return false
+	}
+	return !strings.EqualFold(*spec.PublicNetworkAccess, azure.ToString(observed.PublicNetworkAccess))
+}
+
+// IsPubliclyInaccessible returns true if the supplied spec disables public
+// network access to a cache that is not deployed in a subnet, and which may
+// thus be unreachable unless a private endpoint is configured out of band.
+func IsPubliclyInaccessible(spec v1beta1.RedisParameters) bool {
+	return strings.EqualFold(azure.ToString(spec.PublicNetworkAccess), PublicNetworkAccessDisabled) && azure.ToString(spec.SubnetID) == ""
+}
+
+// IneffectiveFirewallRules returns the names of the supplied firewall rules if
+// the supplied spec deploys the cache in a subnet. Firewall rules only apply
+// to a cache's public endpoint, which a cache deployed in a subnet does not
+// have, so such rules suggest a misunderstanding of how the cache is reached.
+func IneffectiveFirewallRules(spec v1beta1.RedisParameters, rules []redis.FirewallRule) []string {
+	if azure.ToString(spec.SubnetID) == "" {
+		return nil
+	}
+	var names []string
+	for _, r := range rules {
+		names = append(names, azure.ToString(r.Name))
+	}
+	return names
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/redis/mgmt/redis"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestWithPublicNetworkAccess(t *testing.T) {
+	cases := map[string]struct {
+		access *string
+		method string
+		body   string
+		want   string
+	}{
+		"Unset": {
+			method: http.MethodPut,
+			body:   `{"location":"westus"}`,
+			want:   `{"location":"westus"}`,
+		},
+		"Create": {
+			access: azure.ToStringPtr(PublicNetworkAccessDisabled),
+			method: http.MethodPut,
+			body:   `{"location":"westus","properties":{"enableNonSslPort":false}}`,
+			want:   `{"location":"westus","properties":{"enableNonSslPort":false,"publicNetworkAccess":"Disabled"}}`,
+		},
+		"Update": {
+			access: azure.ToStringPtr(PublicNetworkAccessEnabled),
+			method: http.MethodPatch,
+			body:   `{}`,
+			want:   `{"properties":{"publicNetworkAccess":"Enabled"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(tc.method, "https://example.org", ioutil.NopCloser(bytes.NewBufferString(tc.body)))
+			r, err := autorest.CreatePreparer(WithPublicNetworkAccess(tc.access)).Prepare(r)
+			if err != nil {
+				t.Fatalf("Prepare(...): %s", err)
+			}
+			got, _ := ioutil.ReadAll(r.Body)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("WithPublicNetworkAccess(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPublicNetworkAccessNeedsUpdate(t *testing.T) {
+	cases := map[string]struct {
+		spec     v1beta1.RedisParameters
+		observed ObservedProperties
+		want     bool
+	}{
+		"Unset": {
+			observed: ObservedProperties{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessEnabled)},
+			want:     false,
+		},
+		"UpToDate": {
+			spec:     v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled)},
+			observed: ObservedProperties{PublicNetworkAccess: azure.ToStringPtr("disabled")},
+			want:     false,
+		},
+		"Drifted": {
+			spec:     v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled)},
+			observed: ObservedProperties{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessEnabled)},
+			want:     true,
+		},
+		"NotObserved": {
+			spec: v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled)},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PublicNetworkAccessNeedsUpdate(tc.spec, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PublicNetworkAccessNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPubliclyInaccessible(t *testing.T) {
+	cases := map[string]struct {
+		spec v1beta1.RedisParameters
+		want bool
+	}{
+		"Unset": {
+			spec: v1beta1.RedisParameters{},
+			want: false,
+		},
+		"Enabled": {
+			spec: v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessEnabled)},
+			want: false,
+		},
+		"DisabledInSubnet": {
+			spec: v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled), SubnetID: azure.ToStringPtr("subnet")},
+			want: false,
+		},
+		"DisabledWithoutSubnet": {
+			spec: v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled)},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsPubliclyInaccessible(tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsPubliclyInaccessible(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIneffectiveFirewallRules(t *testing.T) {
+	rules := []redis.FirewallRule{
+		{Name: azure.ToStringPtr("office")},
+		{Name: azure.ToStringPtr("vpn")},
+	}
+
+	cases := map[string]struct {
+		spec  v1beta1.RedisParameters
+		rules []redis.FirewallRule
+		want  []string
+	}{
+		"NoSubnet": {
+			spec:  v1beta1.RedisParameters{},
+			rules: rules,
+		},
+		"SubnetWithoutRules": {
+			spec: v1beta1.RedisParameters{SubnetID: azure.ToStringPtr("subnet")},
+		},
+		"SubnetWithRules": {
+			spec:  v1beta1.RedisParameters{SubnetID: azure.ToStringPtr("subnet")},
+			rules: rules,
+			want:  []string{"office", "vpn"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IneffectiveFirewallRules(tc.spec, tc.rules)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IneffectiveFirewallRules(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ import (
 )
 
 var _ redisapi.ClientAPI = &MockClient{}
+var _ redisapi.LinkedServerClientAPI = &MockLinkedServerClient{}
+var _ redisapi.FirewallRulesClientAPI = &MockFirewallRulesClient{}
 
 // MockClient is a fake implementation of cloudmemorystore.Client.
 type MockClient struct {
@@ -34,6 +36,8 @@ type MockClient struct {
 	MockGet      func(ctx context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error)
 	MockListKeys func(ctx context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error)
 	MockUpdate   func(ctx context.Context, resourceGroupName string, name string, parameters redis.UpdateParameters) (result redis.ResourceType, err error)
+
+	MockListUpgradeNotifications func(ctx context.Context, resourceGroupName string, name string, history float64) (result redis.NotificationListResponse, err error)
 }
 
 // Create calls the MockClient's MockCreate method.
@@ -56,7 +60,39 @@ func (c *MockClient) ListKeys(ctx context.Context, resourceGroupName string, nam
 	return c.MockListKeys(ctx, resourceGroupName, name)
 }
 
+// ListUpgradeNotifications calls the MockClient's MockListUpgradeNotifications
+// method.
+func (c *MockClient) ListUpgradeNotifications(ctx context.Context, resourceGroupName string, name string, history float64) (result redis.NotificationListResponse, err error) {
+	return c.MockListUpgradeNotifications(ctx, resourceGroupName, name, history)
+}
+
 // Update calls the MockClient's MockUpdate method.
 func (c *MockClient) Update(ctx context.Context, resourceGroupName string, name string, parameters redis.UpdateParameters) (result redis.ResourceType, err error) {
 	return c.MockUpdate(ctx, resourceGroupName, name, parameters)
 }
+
+// MockLinkedServerClient is a fake implementation of redisapi.LinkedServerClientAPI.
+type MockLinkedServerClient struct {
+	redisapi.LinkedServerClientAPI
+
+	MockGet func(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (result redis.LinkedServerWithProperties, err error)
+}
+
+// Get calls the MockLinkedServerClient's MockGet method.
+func (c *MockLinkedServerClient) Get(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (result redis.LinkedServerWithProperties, err error) {
+	return c.MockGet(ctx, resourceGroupName, name, linkedServerName)
+}
+
+// MockFirewallRulesClient is a fake implementation of
+// redisapi.FirewallRulesClientAPI.
+type MockFirewallRulesClient struct {
+	redisapi.FirewallRulesClientAPI
+
+	MockListByRedisResourceComplete func(ctx context.Context, resourceGroupName string, cacheName string) (result redis.FirewallRuleListResultIterator, err error)
+}
+
+// ListByRedisResourceComplete calls the MockFirewallRulesClient's
+// MockListByRedisResourceComplete method.
+func (c *MockFirewallRulesClient) ListByRedisResourceComplete(ctx context.Context, resourceGroupName string, cacheName string) (result redis.FirewallRuleListResultIterator, err error) {
+	return c.MockListByRedisResourceComplete(ctx, resourceGroupName, cacheName)
+}
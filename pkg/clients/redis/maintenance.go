@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// UpgradeNotificationHistory is how many minutes in the past to look for
+// upgrade notifications about a Redis.
+const UpgradeNotificationHistory float64 = 7 * 24 * 60
+
+// GenerateUpgradeNotifications returns the upgrade notifications in the
+// supplied Azure response, oldest first.
+func GenerateUpgradeNotifications(l redis.NotificationListResponse) []v1beta1.UpgradeNotification {
+	if l.Value == nil || len(*l.Value) == 0 {
+		return nil
+	}
+	out := make([]v1beta1.UpgradeNotification, 0, len(*l.Value))
+	for _, n := range *l.Value {
+		un := v1beta1.UpgradeNotification{Name: azure.ToString(n.Name)}
+		if n.Timestamp != nil {
+			t := metav1.NewTime(n.Timestamp.Time)
+			un.Timestamp = &t
+		}
+		if len(n.UpsellNotification) > 0 {
+			un.Details = make(map[string]string, len(n.UpsellNotification))
+			for k, v := range n.UpsellNotification {
+				un.Details[k] = azure.ToString(v)
+			}
+		}
+		out = append(out, un)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		switch {
+		case out[i].Timestamp == nil:
+			return out[j].Timestamp != nil
+		case out[j].Timestamp == nil:
+			return false
+		}
+		return out[i].Timestamp.Before(out[j].Timestamp)
+	})
+	return out
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestGenerateUpgradeNotifications(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC))
+
+	cases := map[string]struct {
+		l    redis.NotificationListResponse
+		want []v1beta1.UpgradeNotification
+	}{
+		"NoNotifications": {
+			l: redis.NotificationListResponse{Value: &[]redis.UpgradeNotification{}},
+		},
+		"OldestFirst": {
+			l: redis.NotificationListResponse{Value: &[]redis.UpgradeNotification{
+				{
+					Name:      azure.ToStringPtr("later"),
+					Timestamp: &date.Time{Time: later.Time},
+				},
+				{
+					Name:               azure.ToStringPtr("earlier"),
+					Timestamp:          &date.Time{Time: earlier.Time},
+					UpsellNotification: map[string]*string{"impact": azure.ToStringPtr("failover")},
+				},
+				{
+					Name: azure.ToStringPtr("undated"),
+				},
+			}},
+			want: []v1beta1.UpgradeNotification{
+				{Name: "undated"},
+				{Name: "earlier", Timestamp: &earlier, Details: map[string]string{"impact": "failover"}},
+				{Name: "later", Timestamp: &later},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateUpgradeNotifications(tc.l)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GenerateUpgradeNotifications(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
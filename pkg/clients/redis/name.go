@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+)
+
+const errUpdateExternalName = "cannot update Redis with generated external name"
+
+// ExternalName returns the Azure name of a Redis with the supplied name prefix
+// and UID. The UID ensures the name is unique, which it must be across Azure.
+func ExternalName(prefix, uid string) string {
+	return prefix + "-" + uid
+}
+
+// An ExternalNamer sets the external name of a Redis that does not have one.
+// Redis resources with a name prefix are named according to ExternalName,
+// while all others use their metadata name as their external name.
+type ExternalNamer struct {
+	client client.Client
+}
+
+// NewExternalNamer returns an ExternalNamer that persists the external names it
+// sets using the supplied client.
+func NewExternalNamer(c client.Client) *ExternalNamer {
+	return &ExternalNamer{client: c}
+}
+
+// Initialize sets the external name of the supplied Redis, if it does not have
+// one.
+func (n *ExternalNamer) Initialize(ctx context.Context, mg resource.Managed) error {
+	if meta.GetExternalName(mg) != "" {
+		return nil
+	}
+	name := mg.GetName()
+	if cr, ok := mg.(*v1beta1.Redis); ok && cr.Spec.ForProvider.NamePrefix != nil {
+		name = ExternalName(*cr.Spec.ForProvider.NamePrefix, string(cr.GetUID()))
+	}
+	meta.SetExternalName(mg, name)
+	return errors.Wrap(n.client.Update(ctx, mg), errUpdateExternalName)
+}
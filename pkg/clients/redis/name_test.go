@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestExternalNamerInitialize(t *testing.T) {
+	errBoom := errors.New("boom")
+	redis := func(prefix *string, external string) *v1beta1.Redis {
+		cr := &v1beta1.Redis{}
+		cr.SetName("cool-redis")
+		cr.SetUID(types.UID("5d3c1ea3-08b6-4ff9-9c4b-6e0b4a8f2f7e"))
+		cr.Spec.ForProvider.NamePrefix = prefix
+		if external != "" {
+			meta.SetExternalName(cr, external)
+		}
+		return cr
+	}
+
+	cases := map[string]struct {
+		client   *test.MockClient
+		mg       resource.Managed
+		wantName string
+		wantErr  error
+	}{
+		"AlreadyNamed": {
+			mg:       redis(azure.ToStringPtr("myorg"), "existing"),
+			wantName: "existing",
+		},
+		"NoPrefix": {
+			client:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			mg:       redis(nil, ""),
+			wantName: "cool-redis",
+		},
+		"Prefix": {
+			client:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			mg:       redis(azure.ToStringPtr("myorg"), ""),
+			wantName: "myorg-5d3c1ea3-08b6-4ff9-9c4b-6e0b4a8f2f7e",
+		},
+		"UpdateFailed": {
+			client:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			mg:       redis(azure.ToStringPtr("myorg"), ""),
+			wantName: "myorg-5d3c1ea3-08b6-4ff9-9c4b-6e0b4a8f2f7e",
+			wantErr:  errors.Wrap(errBoom, errUpdateExternalName),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewExternalNamer(tc.client).Initialize(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Initialize(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("Initialize(...): -want name, +got name:\n%s", diff)
+			}
+		})
+	}
+}
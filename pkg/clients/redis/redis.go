@@ -17,9 +17,20 @@ limitations under the License.
 package redis
 
 import (
+	"fmt"
+	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 
 	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
@@ -27,12 +38,92 @@ import (
 
 // Resource states
 const (
-	ProvisioningStateCreating  = string(redis.Creating)
-	ProvisioningStateDeleting  = string(redis.Deleting)
-	ProvisioningStateFailed    = string(redis.Failed)
-	ProvisioningStateSucceeded = string(redis.Succeeded)
+	ProvisioningStateCreating               = string(redis.Creating)
+	ProvisioningStateDeleting               = string(redis.Deleting)
+	ProvisioningStateDisabled               = string(redis.Disabled)
+	ProvisioningStateFailed                 = string(redis.Failed)
+	ProvisioningStateLinking                = string(redis.Linking)
+	ProvisioningStateProvisioning           = string(redis.Provisioning)
+	ProvisioningStateRecoveringScaleFailure = string(redis.RecoveringScaleFailure)
+	ProvisioningStateScaling                = string(redis.Scaling)
+	ProvisioningStateSucceeded              = string(redis.Succeeded)
+	ProvisioningStateUnlinking              = string(redis.Unlinking)
+	ProvisioningStateUnprovisioning         = string(redis.Unprovisioning)
+	ProvisioningStateUpdating               = string(redis.Updating)
+)
+
+// Condition messages.
+const (
+	msgFmtTransitioning       = "cache is available while Azure reports it is %s"
+	msgFailed                 = "Azure reports the cache is in a failed state"
+	msgDisabled               = "Azure reports the cache is disabled"
+	msgRecoveringScaleFailure = "Azure reports the cache is recovering from a failed scaling operation"
+)
+
+// Error strings.
+const (
+	errFmtImmutableField = "%s cannot be changed after the cache is created"
+)
+
+// Connection secret keys.
+const (
+	ConnectionSecretPrivateIPKey = "privateIp"
 )
 
+// IsAvailable returns true if a cache in the supplied provisioning state can
+// serve requests. Caches continue to serve requests while they are updated,
+// scaled, or linked to or unlinked from a geo-replication partner.
+func IsAvailable(state string) bool {
+	switch state {
+	case ProvisioningStateSucceeded,
+		ProvisioningStateUpdating,
+		ProvisioningStateScaling,
+		ProvisioningStateLinking,
+		ProvisioningStateUnlinking:
+		return true
+	}
+	return false
+}
+
+// Condition returns the condition that corresponds to the supplied provisioning
+// state.
+func Condition(state string) runtimev1alpha1.Condition {
+	switch state {
+	case ProvisioningStateSucceeded:
+		return runtimev1alpha1.Available()
+	case ProvisioningStateUpdating, ProvisioningStateScaling, ProvisioningStateLinking, ProvisioningStateUnlinking:
+		return runtimev1alpha1.Available().WithMessage(fmt.Sprintf(msgFmtTransitioning, state))
+	case ProvisioningStateCreating, ProvisioningStateProvisioning:
+		return runtimev1alpha1.Creating()
+	case ProvisioningStateDeleting, ProvisioningStateUnprovisioning:
+		return runtimev1alpha1.Deleting()
+	case ProvisioningStateFailed:
+		return runtimev1alpha1.Unavailable().WithMessage(msgFailed)
+	case ProvisioningStateDisabled:
+		return runtimev1alpha1.Unavailable().WithMessage(msgDisabled)
+	case ProvisioningStateRecoveringScaleFailure:
+		return runtimev1alpha1.Unavailable().WithMessage(msgRecoveringScaleFailure)
+	}
+	return runtimev1alpha1.Unavailable()
+}
+
+// IsTerminalCreateError returns true if the supplied error was returned by a
+// create request that will keep failing until its parameters change, for
+// example because they are invalid or would exceed the subscription's quota.
+func IsTerminalCreateError(err error) bool {
+	de, ok := err.(autorest.DetailedError)
+	if !ok {
+		return false
+	}
+	if re, ok := de.Original.(*autorestazure.RequestError); ok && re.ServiceError != nil {
+		if strings.Contains(re.ServiceError.Code, "QuotaExceeded") {
+			return true
+		}
+	}
+	code, ok := de.StatusCode.(int)
+	return ok && code == http.StatusBadRequest
+}
+
 // NewCreateParameters returns Redis resource creation parameters suitable for
 // use with the Azure API.
 func NewCreateParameters(cr *v1beta1.Redis) redis.CreateParameters {
@@ -55,14 +146,16 @@ func NewCreateParameters(cr *v1beta1.Redis) redis.CreateParameters {
 
 // NewUpdateParameters returns a redis.UpdateParameters object only with changed
 // fields.
-// TODO(muvaf): Removal of an entry from the maps such as RedisConfiguration and
-// TenantSettings is not properly supported. The user has to give empty string
-// for deletion instead of just deleting the whole entry.
+// Tags and TenantSettings are replaced in their entirety when they change,
+// preserving any of the supplied ignored tags.
+// TODO(muvaf): Removal of an entry from RedisConfiguration is not properly
+// supported. The user has to give empty string for deletion instead of just
+// deleting the whole entry.
 // NOTE(muvaf): This is barely a comparison function with almost identical if
 // statements which increase the cyclomatic complexity even though it's actually
 // easier to maintain all this in one function.
 // nolint:gocyclo
-func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType) redis.UpdateParameters {
+func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType, ignored azure.IgnoredTags) redis.UpdateParameters {
 	patch := redis.UpdateParameters{
 		Tags: azure.ToStringPtrMap(spec.Tags),
 		UpdateProperties: &redis.UpdateProperties{
@@ -78,13 +171,9 @@ func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType)
 	// ResourceType and extract a JSON patch. But since the number of fields
 	// are not that many, I wanted to go with if statements. Hopefully, we'll
 	// generate this code in the future.
-	for k, v := range state.Tags {
-		if patch.Tags[k] == v {
-			delete(patch.Tags, k)
-		}
-	}
-	if len(patch.Tags) == 0 {
-		patch.Tags = nil
+	patch.Tags = nil
+	if ignored.NeedUpdate(azure.ToStringPtrMap(spec.Tags), state.Tags) {
+		patch.Tags = ignored.Preserve(azure.ToStringPtrMap(spec.Tags), state.Tags)
 	}
 	if state.Properties == nil {
 		return patch
@@ -100,18 +189,15 @@ func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType)
 	if len(patch.RedisConfiguration) == 0 {
 		patch.RedisConfiguration = nil
 	}
-	if reflect.DeepEqual(patch.EnableNonSslPort, state.EnableNonSslPort) {
+	// NOTE(negz): Azure may omit enableNonSslPort when the non-SSL port is
+	// disabled, so we treat an omitted value as false to avoid flapping.
+	if patch.EnableNonSslPort == nil || *patch.EnableNonSslPort == azure.ToBool(state.EnableNonSslPort) {
 		patch.EnableNonSslPort = nil
 	}
 	if reflect.DeepEqual(patch.ShardCount, state.ShardCount) {
 		patch.ShardCount = nil
 	}
-	for k, v := range state.TenantSettings {
-		if reflect.DeepEqual(patch.TenantSettings[k], v) {
-			delete(patch.TenantSettings, k)
-		}
-	}
-	if len(patch.TenantSettings) == 0 {
+	if !tenantSettingsNeedUpdate(spec.TenantSettings, state.TenantSettings) {
 		patch.TenantSettings = nil
 	}
 	if reflect.DeepEqual(patch.MinimumTLSVersion, state.MinimumTLSVersion) {
@@ -120,6 +206,15 @@ func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType)
 	return patch
 }
 
+// tenantSettingsNeedUpdate returns true if the supplied desired tenant
+// settings differ from the supplied observed tenant settings.
+func tenantSettingsNeedUpdate(desired map[string]string, observed map[string]*string) bool {
+	if desired == nil {
+		return false
+	}
+	return !reflect.DeepEqual(desired, azure.ToStringMap(observed)) && (len(desired) != 0 || len(observed) != 0)
+}
+
 // NewSKU returns a Redis resource SKU suitable for use with the Azure API.
 func NewSKU(s v1beta1.SKU) *redis.Sku {
 	return &redis.Sku{
@@ -129,14 +224,37 @@ func NewSKU(s v1beta1.SKU) *redis.Sku {
 	}
 }
 
+// ValidateImmutableFields returns an error if the supplied spec object
+// changes any fields of the supplied Azure resource that cannot be modified
+// once the cache has been created.
+func ValidateImmutableFields(spec v1beta1.RedisParameters, az redis.ResourceType) error {
+	if az.Properties == nil {
+		return nil
+	}
+	if spec.SubnetID != nil && !strings.EqualFold(*spec.SubnetID, azure.ToString(az.Properties.SubnetID)) {
+		return errors.Errorf(errFmtImmutableField, "subnetId")
+	}
+	if spec.StaticIP != nil && *spec.StaticIP != azure.ToString(az.Properties.StaticIP) {
+		return errors.Errorf(errFmtImmutableField, "staticIp")
+	}
+	return nil
+}
+
 // NeedsUpdate returns true if the supplied spec object differs from the
 // supplied Azure resource. It considers only fields that can be modified in
-// place without deleting and recreating the instance.
-func NeedsUpdate(spec v1beta1.RedisParameters, az redis.ResourceType) bool {
+// place without deleting and recreating the instance, and any immutable
+// fields so that attempts to change them are surfaced by Update.
+func NeedsUpdate(spec v1beta1.RedisParameters, az redis.ResourceType, ignored azure.IgnoredTags) bool {
 	if az.Properties == nil {
 		return true
 	}
-	patch := NewUpdateParameters(spec, az)
+	if ValidateImmutableFields(spec, az) != nil {
+		return true
+	}
+	if VersionNeedsUpdate(spec, azure.ToString(az.Properties.RedisVersion)) {
+		return true
+	}
+	patch := NewUpdateParameters(spec, az, ignored)
 	empty := redis.UpdateParameters{UpdateProperties: &redis.UpdateProperties{}}
 	return !reflect.DeepEqual(empty, patch)
 }
@@ -156,6 +274,9 @@ func GenerateObservation(az redis.ResourceType) v1beta1.RedisObservation {
 	o.HostName = azure.ToString(az.Properties.HostName)
 	o.Port = azure.ToInt(az.Properties.Port)
 	o.SSLPort = azure.ToInt(az.Properties.SslPort)
+	// The 2018-03-01 API reports no address for caches whose private IP was
+	// dynamically assigned, so they have no PrivateIP.
+	o.PrivateIP = azure.ToString(az.Properties.StaticIP)
 	if az.LinkedServers != nil {
 		o.LinkedServers = make([]string, len(*az.Properties.LinkedServers))
 		for i, val := range *az.Properties.LinkedServers {
@@ -165,11 +286,36 @@ func GenerateObservation(az redis.ResourceType) v1beta1.RedisObservation {
 	return o
 }
 
+// LinkedServerName returns the name of the linked server with the supplied
+// resource ID.
+func LinkedServerName(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
+// UpdateReplicationObservation updates the supplied observation with the
+// geo-replication role of the cache and the ID of the cache it is linked to,
+// according to the supplied linked server. Azure reports the role of the
+// linked server, so the role of the observed cache is its opposite.
+func UpdateReplicationObservation(o *v1beta1.RedisObservation, ls redis.LinkedServerWithProperties) {
+	o.ReplicationRole, o.LinkedCacheID = "", ""
+	if ls.LinkedServerProperties == nil {
+		return
+	}
+	o.LinkedCacheID = azure.ToString(ls.LinkedRedisCacheID)
+	switch ls.ServerRole {
+	case redis.ReplicationRoleSecondary:
+		o.ReplicationRole = string(redis.ReplicationRolePrimary)
+	case redis.ReplicationRolePrimary:
+		o.ReplicationRole = string(redis.ReplicationRoleSecondary)
+	}
+}
+
 // LateInitialize fills the spec values that user did not fill with their
-// corresponding value in the Azure, if there is any.
-func LateInitialize(spec *v1beta1.RedisParameters, az redis.ResourceType) {
+// corresponding value in the Azure, if there is any. The supplied ignored tags
+// are not late initialized.
+func LateInitialize(spec *v1beta1.RedisParameters, az redis.ResourceType, ignored azure.IgnoredTags) {
 	spec.Zones = azure.LateInitializeStringValArrFromArrPtr(spec.Zones, az.Zones)
-	spec.Tags = azure.LateInitializeStringMap(spec.Tags, az.Tags)
+	spec.Tags = azure.LateInitializeStringMap(spec.Tags, azure.ToStringPtrMap(ignored.Filter(azure.ToStringMap(az.Tags))))
 	if az.Properties == nil {
 		return
 	}
@@ -182,3 +328,83 @@ func LateInitialize(spec *v1beta1.RedisParameters, az redis.ResourceType) {
 	minTLS := string(az.Properties.MinimumTLSVersion)
 	spec.MinimumTLSVersion = azure.LateInitializeStringPtrFromPtr(spec.MinimumTLSVersion, &minTLS)
 }
+
+// LastSyncTimeResolution is the minimum interval between updates to the
+// LastSyncTime of a Redis. Updating it more often would cause every reconcile
+// to trigger another, since each status update results in a watch event.
+const LastSyncTimeResolution = 1 * time.Minute
+
+// UpdateLastSyncTime records that the supplied Redis status was in sync at the
+// supplied time, unless it was recorded as being in sync within the last
+// LastSyncTimeResolution.
+func UpdateLastSyncTime(s *v1beta1.RedisStatus, now time.Time) {
+	if s.LastSyncTime != nil && now.Sub(s.LastSyncTime.Time) < LastSyncTimeResolution {
+		return
+	}
+	t := metav1.NewTime(now)
+	s.LastSyncTime = &t
+}
+
+// GenerateConnectionDetails returns the connection details of the cache
+// described by the supplied observation that are known, omitting any that are
+// not yet known (e.g. while the cache is being created). It never includes
+// the cache's access keys.
+func GenerateConnectionDetails(o v1beta1.RedisObservation) map[string][]byte {
+	cd := map[string][]byte{}
+	if o.HostName != "" {
+		cd[runtimev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(o.HostName)
+	}
+	if o.Port != 0 {
+		cd[runtimev1alpha1.ResourceCredentialsSecretPortKey] = []byte(strconv.Itoa(o.Port))
+	}
+	if o.PrivateIP != "" {
+		cd[ConnectionSecretPrivateIPKey] = []byte(o.PrivateIP)
+	}
+	return cd
+}
+
+// Clustered Premium caches expose every shard's primary and replica node on a
+// dedicated port, starting from these bases. Shard n's primary listens on
+// base+2n and its replica on base+2n+1.
+const (
+	clusterPortBase    = 13000
+	clusterSSLPortBase = 15000
+)
+
+// Connection secret keys for clustered caches.
+const (
+	ConnectionSecretShardCountKey = "shardCount"
+)
+
+// ShardPortKey returns the connection secret key of the non-SSL port of the
+// supplied shard's primary node.
+func ShardPortKey(shard int) string {
+	return fmt.Sprintf("shard%dPort", shard)
+}
+
+// ShardSSLPortKey returns the connection secret key of the SSL port of the
+// supplied shard's primary node.
+func ShardSSLPortKey(shard int) string {
+	return fmt.Sprintf("shard%dSslPort", shard)
+}
+
+// GenerateShardConnectionDetails returns the per-shard port mapping of a
+// clustered cache so that Redis cluster clients can discover its nodes. It
+// returns nil for caches that are not clustered. Non-SSL ports are included
+// only when the non-SSL port is enabled.
+func GenerateShardConnectionDetails(az redis.ResourceType) map[string][]byte {
+	if az.Properties == nil || azure.ToInt(az.Properties.ShardCount) == 0 {
+		return nil
+	}
+	count := azure.ToInt(az.Properties.ShardCount)
+	cd := map[string][]byte{
+		ConnectionSecretShardCountKey: []byte(strconv.Itoa(count)),
+	}
+	for i := 0; i < count; i++ {
+		cd[ShardSSLPortKey(i)] = []byte(strconv.Itoa(clusterSSLPortBase + 2*i))
+		if azure.ToBool(az.Properties.EnableNonSslPort) {
+			cd[ShardPortKey(i)] = []byte(strconv.Itoa(clusterPortBase + 2*i))
+		}
+	}
+	return cd
+}
@@ -17,10 +17,21 @@ limitations under the License.
 package redis
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	redismgmt "github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
@@ -190,6 +201,61 @@ func TestNewUpdateParameters(t *testing.T) {
 				Tags:             azure.ToStringPtrMap(tags),
 			},
 		},
+		{
+			name: "ReplaceTagsPreservingIgnoredTags",
+			spec: v1beta1.RedisParameters{
+				Tags: map[string]string{"key2": "val2"},
+			},
+			current: redismgmt.ResourceType{
+				Tags: azure.ToStringPtrMap(map[string]string{"key1": "val1", "key2": "val2", "hidden-link": "linked"}),
+			},
+			want: redismgmt.UpdateParameters{
+				Tags: azure.ToStringPtrMap(map[string]string{"key2": "val2", "hidden-link": "linked"}),
+				UpdateProperties: &redismgmt.UpdateProperties{
+					Sku: &redismgmt.Sku{Capacity: azure.ToInt32Ptr(0, azure.FieldRequired)},
+				},
+			},
+		},
+		{
+			name: "IgnoredTagsOnly",
+			spec: v1beta1.RedisParameters{
+				Tags: tags,
+			},
+			current: redismgmt.ResourceType{
+				Tags: azure.ToStringPtrMap(map[string]string{"key1": "val1", "hidden-link": "linked"}),
+			},
+			want: redismgmt.UpdateParameters{
+				UpdateProperties: &redismgmt.UpdateProperties{
+					Sku: &redismgmt.Sku{Capacity: azure.ToInt32Ptr(0, azure.FieldRequired)},
+				},
+			},
+		},
+		{
+			name: "ReplaceTenantSettings",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+				TenantSettings: map[string]string{"tenant2": "is-calm"},
+			},
+			current: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+					TenantSettings: azure.ToStringPtrMap(map[string]string{"tenant1": "is-crazy", "tenant2": "is-calm"}),
+				},
+			},
+			want: redismgmt.UpdateParameters{
+				UpdateProperties: &redismgmt.UpdateProperties{
+					TenantSettings: azure.ToStringPtrMap(map[string]string{"tenant2": "is-calm"}),
+				},
+			},
+		},
 		{
 			name: "PatchRedisConfig",
 			spec: v1beta1.RedisParameters{
@@ -224,11 +290,60 @@ func TestNewUpdateParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "PatchEnableNonSSLPort",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+				EnableNonSSLPort: azure.ToBoolPtr(true),
+			},
+			current: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+					EnableNonSslPort: azure.ToBoolPtr(false, azure.FieldRequired),
+				},
+			},
+			want: redismgmt.UpdateParameters{
+				UpdateProperties: &redismgmt.UpdateProperties{
+					EnableNonSslPort: azure.ToBoolPtr(true),
+				},
+			},
+		},
+		{
+			name: "EnableNonSSLPortOmittedByAzure",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+				EnableNonSSLPort: azure.ToBoolPtr(false, azure.FieldRequired),
+			},
+			current: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+				},
+			},
+			want: redismgmt.UpdateParameters{
+				UpdateProperties: &redismgmt.UpdateProperties{},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewUpdateParameters(tc.spec, tc.current)
+			got := NewUpdateParameters(tc.spec, tc.current, azure.DefaultIgnoredTagPrefixes)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewUpdateParameters(...): -want, +got\n%s", diff)
 			}
@@ -319,7 +434,7 @@ func TestNeedsUpdate(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NeedsUpdate(tc.spec, tc.az)
+			got := NeedsUpdate(tc.spec, tc.az, azure.DefaultIgnoredTagPrefixes)
 			if got != tc.want {
 				t.Errorf("NeedsUpdate(...): want %t, got %t", tc.want, got)
 			}
@@ -327,6 +442,56 @@ func TestNeedsUpdate(t *testing.T) {
 	}
 }
 
+func TestValidateImmutableFields(t *testing.T) {
+	otherSubnetID := "othersubnet"
+	otherStaticIP := "172.16.0.2"
+	az := redismgmt.ResourceType{
+		Properties: &redismgmt.Properties{
+			SubnetID: azure.ToStringPtr(subnetID),
+			StaticIP: azure.ToStringPtr(staticIP),
+		},
+	}
+
+	cases := map[string]struct {
+		spec v1beta1.RedisParameters
+		az   redismgmt.ResourceType
+		want error
+	}{
+		"NoProperties": {
+			spec: v1beta1.RedisParameters{SubnetID: &otherSubnetID},
+			want: nil,
+		},
+		"Unset": {
+			az:   az,
+			want: nil,
+		},
+		"Unchanged": {
+			spec: v1beta1.RedisParameters{SubnetID: &subnetID, StaticIP: &staticIP},
+			az:   az,
+			want: nil,
+		},
+		"SubnetIDChanged": {
+			spec: v1beta1.RedisParameters{SubnetID: &otherSubnetID, StaticIP: &staticIP},
+			az:   az,
+			want: errors.Errorf(errFmtImmutableField, "subnetId"),
+		},
+		"StaticIPChanged": {
+			spec: v1beta1.RedisParameters{SubnetID: &subnetID, StaticIP: &otherStaticIP},
+			az:   az,
+			want: errors.Errorf(errFmtImmutableField, "staticIp"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateImmutableFields(tc.spec, tc.az)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateImmutableFields(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestGenerateObservation(t *testing.T) {
 	cases := map[string]struct {
 		arg  redismgmt.ResourceType
@@ -366,6 +531,7 @@ func TestGenerateObservation(t *testing.T) {
 				HostName:          hostName,
 				Port:              port,
 				SSLPort:           sslPort,
+				PrivateIP:         staticIP,
 				LinkedServers:     linkedServers,
 				Name:              resourceName,
 				ID:                resourceID,
@@ -439,13 +605,273 @@ func TestLateInitialize(t *testing.T) {
 				},
 			},
 		},
+		"IgnoredTagsNotLateInitialized": {
+			args: args{
+				az: redismgmt.ResourceType{
+					Tags: azure.ToStringPtrMap(map[string]string{"key1": "val1", "hidden-link": "linked"}),
+				},
+				spec: &v1beta1.RedisParameters{},
+			},
+			want: want{
+				spec: &v1beta1.RedisParameters{
+					Tags: tags,
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			LateInitialize(tc.args.spec, tc.args.az)
+			LateInitialize(tc.args.spec, tc.args.az, azure.DefaultIgnoredTagPrefixes)
 			if diff := cmp.Diff(tc.want.spec, tc.args.spec); diff != "" {
 				t.Errorf("LateInitialize(...): -want, +got\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestGenerateConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		o    v1beta1.RedisObservation
+		want map[string][]byte
+	}{
+		"NothingKnown": {
+			want: map[string][]byte{},
+		},
+		"EndpointKnown": {
+			o: v1beta1.RedisObservation{HostName: hostName, Port: port, PrivateIP: staticIP},
+			want: map[string][]byte{
+				runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+				runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+				ConnectionSecretPrivateIPKey:                         []byte(staticIP),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateConnectionDetails(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GenerateConnectionDetails(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateShardConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		arg  redismgmt.ResourceType
+		want map[string][]byte
+	}{
+		"NoProperties": {
+			arg:  redismgmt.ResourceType{},
+			want: nil,
+		},
+		"NotClustered": {
+			arg: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{},
+			},
+			want: nil,
+		},
+		"ClusteredSSLOnly": {
+			arg: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					ShardCount: azure.ToInt32Ptr(2),
+				},
+			},
+			want: map[string][]byte{
+				ConnectionSecretShardCountKey: []byte("2"),
+				"shard0SslPort":               []byte("15000"),
+				"shard1SslPort":               []byte("15002"),
+			},
+		},
+		"ClusteredWithNonSSLPort": {
+			arg: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					ShardCount:       azure.ToInt32Ptr(2),
+					EnableNonSslPort: azure.ToBoolPtr(true),
+				},
+			},
+			want: map[string][]byte{
+				ConnectionSecretShardCountKey: []byte("2"),
+				"shard0SslPort":               []byte("15000"),
+				"shard1SslPort":               []byte("15002"),
+				"shard0Port":                  []byte("13000"),
+				"shard1Port":                  []byte("13002"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateShardConnectionDetails(tc.arg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GenerateShardConnectionDetails(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsTerminalCreateError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"NotDetailedError": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+		"QuotaExceeded": {
+			err: autorest.DetailedError{
+				StatusCode: http.StatusConflict,
+				Original:   &autorestazure.RequestError{ServiceError: &autorestazure.ServiceError{Code: "QuotaExceeded"}},
+			},
+			want: true,
+		},
+		"BadRequest": {
+			err:  autorest.DetailedError{StatusCode: http.StatusBadRequest},
+			want: true,
+		},
+		"InternalServerError": {
+			err:  autorest.DetailedError{StatusCode: http.StatusInternalServerError},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsTerminalCreateError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsTerminalCreateError(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	cases := map[string]struct {
+		state     string
+		want      runtimev1alpha1.Condition
+		available bool
+	}{
+		"Succeeded": {
+			state:     ProvisioningStateSucceeded,
+			want:      runtimev1alpha1.Available(),
+			available: true,
+		},
+		"Scaling": {
+			state:     ProvisioningStateScaling,
+			want:      runtimev1alpha1.Available().WithMessage(fmt.Sprintf(msgFmtTransitioning, ProvisioningStateScaling)),
+			available: true,
+		},
+		"Linking": {
+			state:     ProvisioningStateLinking,
+			want:      runtimev1alpha1.Available().WithMessage(fmt.Sprintf(msgFmtTransitioning, ProvisioningStateLinking)),
+			available: true,
+		},
+		"Provisioning": {
+			state: ProvisioningStateProvisioning,
+			want:  runtimev1alpha1.Creating(),
+		},
+		"Unprovisioning": {
+			state: ProvisioningStateUnprovisioning,
+			want:  runtimev1alpha1.Deleting(),
+		},
+		"Failed": {
+			state: ProvisioningStateFailed,
+			want:  runtimev1alpha1.Unavailable().WithMessage(msgFailed),
+		},
+		"Disabled": {
+			state: ProvisioningStateDisabled,
+			want:  runtimev1alpha1.Unavailable().WithMessage(msgDisabled),
+		},
+		"RecoveringScaleFailure": {
+			state: ProvisioningStateRecoveringScaleFailure,
+			want:  runtimev1alpha1.Unavailable().WithMessage(msgRecoveringScaleFailure),
+		},
+		"Unknown": {
+			want: runtimev1alpha1.Unavailable(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Condition(tc.state)
+			if !tc.want.Equal(got) {
+				t.Errorf("Condition(...): want %+v, got %+v", tc.want, got)
+			}
+			if diff := cmp.Diff(tc.available, IsAvailable(tc.state)); diff != "" {
+				t.Errorf("IsAvailable(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateLastSyncTime(t *testing.T) {
+	now := time.Now()
+	recent := metav1.NewTime(now.Add(-LastSyncTimeResolution / 2))
+	stale := metav1.NewTime(now.Add(-LastSyncTimeResolution))
+	updated := metav1.NewTime(now)
+
+	cases := map[string]struct {
+		s    *v1beta1.RedisStatus
+		want *metav1.Time
+	}{
+		"NeverSynced": {
+			s:    &v1beta1.RedisStatus{},
+			want: &updated,
+		},
+		"RecentlySynced": {
+			s:    &v1beta1.RedisStatus{LastSyncTime: &recent},
+			want: &recent,
+		},
+		"Stale": {
+			s:    &v1beta1.RedisStatus{LastSyncTime: &stale},
+			want: &updated,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			UpdateLastSyncTime(tc.s, now)
+			if diff := cmp.Diff(tc.want, tc.s.LastSyncTime); diff != "" {
+				t.Errorf("UpdateLastSyncTime(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateReplicationObservation(t *testing.T) {
+	cacheID := "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Cache/Redis/other"
+
+	cases := map[string]struct {
+		ls   redismgmt.LinkedServerWithProperties
+		want v1beta1.RedisObservation
+	}{
+		"LinkedToSecondary": {
+			ls: redismgmt.LinkedServerWithProperties{LinkedServerProperties: &redismgmt.LinkedServerProperties{
+				LinkedRedisCacheID: azure.ToStringPtr(cacheID),
+				ServerRole:         redismgmt.ReplicationRoleSecondary,
+			}},
+			want: v1beta1.RedisObservation{ReplicationRole: "Primary", LinkedCacheID: cacheID},
+		},
+		"LinkedToPrimary": {
+			ls: redismgmt.LinkedServerWithProperties{LinkedServerProperties: &redismgmt.LinkedServerProperties{
+				LinkedRedisCacheID: azure.ToStringPtr(cacheID),
+				ServerRole:         redismgmt.ReplicationRolePrimary,
+			}},
+			want: v1beta1.RedisObservation{ReplicationRole: "Secondary", LinkedCacheID: cacheID},
+		},
+		"NoProperties": {
+			ls:   redismgmt.LinkedServerWithProperties{},
+			want: v1beta1.RedisObservation{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := v1beta1.RedisObservation{ReplicationRole: "Secondary", LinkedCacheID: "stale"}
+			UpdateReplicationObservation(&got, tc.ls)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UpdateReplicationObservation(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
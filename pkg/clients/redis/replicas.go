@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// ReplicasPerMasterAPIVersion is the oldest API version that may be used to
+// read and write the replicasPerMaster property of a Redis.
+const ReplicasPerMasterAPIVersion = "2020-06-01"
+
+// Error strings.
+const (
+	errReplicasNotPremiumClustered = "replicasPerMaster may only be set for Premium caches with a shardCount"
+)
+
+// ValidateReplicasPerMaster returns an error if the supplied spec sets
+// replicasPerMaster for a cache that is not a Premium clustered cache.
+func ValidateReplicasPerMaster(spec v1beta1.RedisParameters) error {
+	if spec.ReplicasPerMaster == nil {
+		return nil
+	}
+	if !strings.EqualFold(spec.SKU.Name, string(redis.Premium)) || spec.ShardCount == nil || *spec.ShardCount == 0 {
+		return errors.New(errReplicasNotPremiumClustered)
+	}
+	return nil
+}
+
+// ValidateReplicasPerMasterChange returns an error if the supplied spec
+// requests a different replicasPerMaster than the supplied observed
+// properties. replicasPerMaster cannot be changed once a cache is created.
+func ValidateReplicasPerMasterChange(spec v1beta1.RedisParameters, observed ObservedProperties) error {
+	if spec.ReplicasPerMaster == nil || observed.ReplicasPerMaster == nil {
+		return nil
+	}
+	if int32(*spec.ReplicasPerMaster) != *observed.ReplicasPerMaster {
+		return errors.Errorf(errFmtImmutableField, "replicasPerMaster")
+	}
+	return nil
+}
+
+// WithReplicasPerMaster returns a PrepareDecorator that sets the
+// replicasPerMaster property of Redis create and update requests to the
+// supplied value.
+func WithReplicasPerMaster(v *int) autorest.PrepareDecorator {
+	if v == nil {
+		return azure.WithProperties(nil)
+	}
+	return azure.WithProperties(map[string]interface{}{"replicasPerMaster": *v})
+}
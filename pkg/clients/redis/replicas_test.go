@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestValidateReplicasPerMaster(t *testing.T) {
+	two := 2
+	cases := map[string]struct {
+		spec v1beta1.RedisParameters
+		want error
+	}{
+		"Unset": {
+			spec: v1beta1.RedisParameters{SKU: v1beta1.SKU{Name: "Standard"}},
+		},
+		"PremiumClustered": {
+			spec: v1beta1.RedisParameters{SKU: v1beta1.SKU{Name: "Premium"}, ShardCount: &two, ReplicasPerMaster: &two},
+		},
+		"NotPremium": {
+			spec: v1beta1.RedisParameters{SKU: v1beta1.SKU{Name: "Standard"}, ShardCount: &two, ReplicasPerMaster: &two},
+			want: errors.New(errReplicasNotPremiumClustered),
+		},
+		"NotClustered": {
+			spec: v1beta1.RedisParameters{SKU: v1beta1.SKU{Name: "Premium"}, ReplicasPerMaster: &two},
+			want: errors.New(errReplicasNotPremiumClustered),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateReplicasPerMaster(tc.spec)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateReplicasPerMaster(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateReplicasPerMasterChange(t *testing.T) {
+	two := 2
+	cases := map[string]struct {
+		spec     v1beta1.RedisParameters
+		observed ObservedProperties
+		want     error
+	}{
+		"Unset": {
+			observed: ObservedProperties{ReplicasPerMaster: azure.ToInt32Ptr(1)},
+		},
+		"NotObserved": {
+			spec: v1beta1.RedisParameters{ReplicasPerMaster: &two},
+		},
+		"Unchanged": {
+			spec:     v1beta1.RedisParameters{ReplicasPerMaster: &two},
+			observed: ObservedProperties{ReplicasPerMaster: azure.ToInt32Ptr(2)},
+		},
+		"Changed": {
+			spec:     v1beta1.RedisParameters{ReplicasPerMaster: &two},
+			observed: ObservedProperties{ReplicasPerMaster: azure.ToInt32Ptr(1)},
+			want:     errors.Errorf(errFmtImmutableField, "replicasPerMaster"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateReplicasPerMasterChange(tc.spec, tc.observed)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateReplicasPerMasterChange(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithReplicasPerMaster(t *testing.T) {
+	two := 2
+	cases := map[string]struct {
+		replicas *int
+		body     string
+		want     string
+	}{
+		"Unset": {
+			body: `{"location":"westus"}`,
+			want: `{"location":"westus"}`,
+		},
+		"Set": {
+			replicas: &two,
+			body:     `{"location":"westus","properties":{"shardCount":2}}`,
+			want:     `{"location":"westus","properties":{"replicasPerMaster":2,"shardCount":2}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPut, "https://example.org", ioutil.NopCloser(bytes.NewBufferString(tc.body)))
+			r, err := autorest.CreatePreparer(WithReplicasPerMaster(tc.replicas)).Prepare(r)
+			if err != nil {
+				t.Fatalf("Prepare(...): %s", err)
+			}
+			got, _ := ioutil.ReadAll(r.Body)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("WithReplicasPerMaster(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// Error strings.
+const (
+	errFmtVersionDowngrade = "redisVersion cannot be downgraded from %s to %s"
+)
+
+// RedisVersionAPIVersion is the oldest API version that may be used to write
+// the redisVersion property of a Redis. Older API versions ignore it.
+const RedisVersionAPIVersion = "2020-06-01"
+
+// APIVersion returns the API version that should be used to create, read, and
+// update the supplied Redis. Our Azure SDK predates the redisVersion,
+// publicNetworkAccess, and replicasPerMaster properties, so we send and receive
+// them using a newer API version unless one was configured explicitly. An
+// empty string is returned if the SDK's API version will do.
+func APIVersion(spec v1beta1.RedisParameters, configured string) string {
+	switch {
+	case configured != "":
+		return configured
+	case spec.RedisVersion != nil:
+		return RedisVersionAPIVersion
+	case spec.PublicNetworkAccess != nil:
+		return PublicNetworkAccessAPIVersion
+	case spec.ReplicasPerMaster != nil:
+		return ReplicasPerMasterAPIVersion
+	}
+	return ""
+}
+
+// MajorVersion returns the major component of the supplied Redis version, for
+// example 6 for 6.0.14. Azure reports full versions, but only major versions
+// may be requested.
+func MajorVersion(v string) string {
+	return strings.SplitN(v, ".", 2)[0]
+}
+
+// ValidateVersionChange returns an error if the supplied spec would downgrade
+// the supplied Azure resource's Redis version. Azure supports upgrading caches
+// in place, but not downgrading them.
+func ValidateVersionChange(spec v1beta1.RedisParameters, observed string) error {
+	if spec.RedisVersion == nil || observed == "" {
+		return nil
+	}
+	want, err := strconv.Atoi(MajorVersion(*spec.RedisVersion))
+	if err != nil {
+		return nil
+	}
+	got, err := strconv.Atoi(MajorVersion(observed))
+	if err != nil {
+		return nil
+	}
+	if want < got {
+		return errors.Errorf(errFmtVersionDowngrade, MajorVersion(observed), *spec.RedisVersion)
+	}
+	return nil
+}
+
+// VersionNeedsUpdate returns true if the supplied spec requests a different
+// major Redis version than the supplied observed version.
+func VersionNeedsUpdate(spec v1beta1.RedisParameters, observed string) bool {
+	return spec.RedisVersion != nil && observed != "" && MajorVersion(*spec.RedisVersion) != MajorVersion(observed)
+}
+
+// WithRedisVersion returns a PrepareDecorator that sets the redisVersion
+// property of Redis create and update requests to the supplied version. The
+// Azure SDK version we use predates the property, so it cannot be set via
+// redis.CreateParameters or redis.UpdateParameters.
+func WithRedisVersion(v *string) autorest.PrepareDecorator {
+	if v == nil {
+		return azure.WithProperties(nil)
+	}
+	return azure.WithProperties(map[string]interface{}{"redisVersion": *v})
+}
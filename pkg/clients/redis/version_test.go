@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestAPIVersion(t *testing.T) {
+	two := 2
+
+	cases := map[string]struct {
+		spec       v1beta1.RedisParameters
+		configured string
+		want       string
+	}{
+		"SDKVersion": {
+			spec: v1beta1.RedisParameters{},
+			want: "",
+		},
+		"Configured": {
+			spec:       v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("6")},
+			configured: "2021-06-01",
+			want:       "2021-06-01",
+		},
+		"RedisVersion": {
+			spec: v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("6")},
+			want: RedisVersionAPIVersion,
+		},
+		"PublicNetworkAccess": {
+			spec: v1beta1.RedisParameters{PublicNetworkAccess: azure.ToStringPtr(PublicNetworkAccessDisabled)},
+			want: PublicNetworkAccessAPIVersion,
+		},
+		"ReplicasPerMaster": {
+			spec: v1beta1.RedisParameters{ReplicasPerMaster: &two},
+			want: ReplicasPerMasterAPIVersion,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := APIVersion(tc.spec, tc.configured)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("APIVersion(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateVersionChange(t *testing.T) {
+	cases := map[string]struct {
+		spec     v1beta1.RedisParameters
+		observed string
+		want     error
+	}{
+		"Unset": {
+			observed: "6.0.14",
+		},
+		"NotYetObserved": {
+			spec: v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("4")},
+		},
+		"Unchanged": {
+			spec:     v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("6")},
+			observed: "6.0.14",
+		},
+		"Upgrade": {
+			spec:     v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("6")},
+			observed: "4.0.14",
+		},
+		"Downgrade": {
+			spec:     v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("4")},
+			observed: "6.0.14",
+			want:     errors.Errorf(errFmtVersionDowngrade, "6", "4"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateVersionChange(tc.spec, tc.observed)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateVersionChange(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVersionNeedsUpdate(t *testing.T) {
+	cases := map[string]struct {
+		spec     v1beta1.RedisParameters
+		observed string
+		want     bool
+	}{
+		"Unset": {
+			observed: "4.0.14",
+			want:     false,
+		},
+		"SameMajorVersion": {
+			spec:     v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("4")},
+			observed: "4.0.14",
+			want:     false,
+		},
+		"DifferentMajorVersion": {
+			spec:     v1beta1.RedisParameters{RedisVersion: azure.ToStringPtr("6")},
+			observed: "4.0.14",
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := VersionNeedsUpdate(tc.spec, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("VersionNeedsUpdate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithRedisVersion(t *testing.T) {
+	cases := map[string]struct {
+		version *string
+		method  string
+		body    string
+		want    string
+	}{
+		"Unset": {
+			method: http.MethodPut,
+			body:   `{"location":"westus"}`,
+			want:   `{"location":"westus"}`,
+		},
+		"Create": {
+			version: azure.ToStringPtr("6"),
+			method:  http.MethodPut,
+			body:    `{"location":"westus","properties":{"enableNonSslPort":false}}`,
+			want:    `{"location":"westus","properties":{"enableNonSslPort":false,"redisVersion":"6"}}`,
+		},
+		"Update": {
+			version: azure.ToStringPtr("6"),
+			method:  http.MethodPatch,
+			body:    `{}`,
+			want:    `{"properties":{"redisVersion":"6"}}`,
+		},
+		"OtherMethod": {
+			version: azure.ToStringPtr("6"),
+			method:  http.MethodPost,
+			body:    `{}`,
+			want:    `{}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(tc.method, "https://example.org", ioutil.NopCloser(bytes.NewBufferString(tc.body)))
+			r, err := autorest.CreatePreparer(WithRedisVersion(tc.version)).Prepare(r)
+			if err != nil {
+				t.Fatalf("Prepare(...): %s", err)
+			}
+			got, _ := ioutil.ReadAll(r.Body)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("WithRedisVersion(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
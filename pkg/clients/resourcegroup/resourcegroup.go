@@ -67,5 +67,6 @@ func NewParameters(r *v1alpha3.ResourceGroup) resources.Group {
 	return resources.Group{
 		Name:     azure.ToStringPtr(meta.GetExternalName(r)),
 		Location: azure.ToStringPtr(r.Spec.Location),
+		Tags:     azure.WithOwnershipTags(nil, v1alpha3.ResourceGroupKind, r),
 	}
 }
@@ -21,6 +21,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -29,6 +31,7 @@ import (
 )
 
 const (
+	uid      = types.UID("definitely-a-uuid")
 	name     = "cool-rg"
 	location = "us-west-1"
 )
@@ -43,6 +46,7 @@ func TestNewParameters(t *testing.T) {
 			name: "Successful",
 			r: func() *v1alpha3.ResourceGroup {
 				r := &v1alpha3.ResourceGroup{
+					ObjectMeta: metav1.ObjectMeta{UID: uid},
 					Spec: v1alpha3.ResourceGroupSpec{
 						Location: location,
 					},
@@ -53,6 +57,10 @@ func TestNewParameters(t *testing.T) {
 			want: resources.Group{
 				Name:     azure.ToStringPtr(name),
 				Location: azure.ToStringPtr(location),
+				Tags: map[string]*string{
+					azure.TagKeyKind: azure.ToStringPtr(v1alpha3.ResourceGroupKind),
+					azure.TagKeyUID:  azure.ToStringPtr(string(uid)),
+				},
 			},
 		},
 	}
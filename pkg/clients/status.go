@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errGetLatest = "cannot get latest version of object to retry status update"
+
+// A ConditionedObject is an object with conditions.
+type ConditionedObject interface {
+	resource.Object
+	resource.Conditioned
+}
+
+// UpdateStatus updates the status of the supplied object. If the update fails
+// because the object has changed since it was read, the latest version of the
+// object is read using the supplied reader and the update is retried. The
+// supplied object's status is applied to the latest version, except that
+// conditions other than Ready and Synced are taken from the latest version.
+// UpdateStatus should thus be used by reconcilers that own all of an object's
+// status but for the conditions of any reconcilers that wrap them. The reader
+// should read from the API server rather than a cache, which may not yet
+// reflect the change that caused the conflict.
+func UpdateStatus(ctx context.Context, kube client.Client, r client.Reader, o ConditionedObject) error {
+	latest, ok := o.DeepCopyObject().(ConditionedObject)
+	if !ok {
+		return kube.Status().Update(ctx, o)
+	}
+	owned := ownedConditions(o)
+	return updateStatus(ctx, kube, r, o, latest, func() {
+		// Updates to the status subresource ignore everything but status, so
+		// our status may be applied to the latest version of the object by
+		// updating it with the latest resource version.
+		o.SetResourceVersion(latest.GetResourceVersion())
+		cs := runtimev1alpha1.ConditionedStatus{Conditions: conditions(latest)}
+		cs.SetConditions(owned...)
+		setConditions(o, cs.Conditions)
+	})
+}
+
+// UpdateConditions sets the supplied conditions on the supplied object and
+// updates its status. If the update fails because the object has changed
+// since it was read, the latest version of the object is read using the
+// supplied reader, the conditions are set on it, and the update is retried.
+// Unlike UpdateStatus it preserves any other status changes made
+// concurrently, and should thus be used by reconcilers that own only some of
+// an object's conditions.
+func UpdateConditions(ctx context.Context, kube client.Client, r client.Reader, o ConditionedObject, c ...runtimev1alpha1.Condition) error {
+	o.SetConditions(c...)
+	return updateStatus(ctx, kube, r, o, o, func() { o.SetConditions(c...) })
+}
+
+// updateStatus updates the status of the supplied object, retrying on
+// conflict. Each time the update conflicts the latest version of the object is
+// read into latest, and reapply is called to reapply the desired status to it.
+func updateStatus(ctx context.Context, kube client.Client, r client.Reader, o, latest resource.Object, reapply func()) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := kube.Status().Update(ctx, o)
+		if !kerrors.IsConflict(err) {
+			return err
+		}
+		if gerr := r.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, latest); gerr != nil {
+			return errors.Wrap(gerr, errGetLatest)
+		}
+		reapply()
+		// Return the conflict so that the update is retried.
+		return err
+	})
+}
+
+// ownedConditions returns the Ready and Synced conditions of the supplied
+// object, if it has them.
+func ownedConditions(o resource.Conditioned) []runtimev1alpha1.Condition {
+	var owned []runtimev1alpha1.Condition
+	for _, c := range conditions(o) {
+		if c.Type == runtimev1alpha1.TypeReady || c.Type == runtimev1alpha1.TypeSynced {
+			owned = append(owned, c)
+		}
+	}
+	return owned
+}
+
+// The Conditioned interface only allows conditions to be read and written by
+// type, so we read and write all of an object's conditions via its serialised
+// status instead.
+type conditionedStatus struct {
+	Status runtimev1alpha1.ConditionedStatus `json:"status"`
+}
+
+// conditions returns all of the conditions of the supplied object.
+func conditions(o interface{}) []runtimev1alpha1.Condition {
+	s := &conditionedStatus{}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil
+	}
+	return s.Status.Conditions
+}
+
+// setConditions replaces all of the conditions of the supplied object.
+func setConditions(o interface{}, c []runtimev1alpha1.Condition) {
+	b, err := json.Marshal(&conditionedStatus{Status: runtimev1alpha1.ConditionedStatus{Conditions: c}})
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(b, o)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	storagev1alpha3 "github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// conflictOnce returns a MockStatusUpdateFn that returns a conflict the first
+// time it is called, then returns the supplied error. Each object it is called
+// with is appended to updated.
+func conflictOnce(err error, updated *[]runtime.Object) test.MockStatusUpdateFn {
+	conflict := kerrors.NewConflict(schema.GroupResource{}, "cool", errors.New("changed"))
+	return func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+		*updated = append(*updated, obj.DeepCopyObject())
+		if len(*updated) == 1 {
+			return conflict
+		}
+		return err
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	creating := runtimev1alpha1.Creating()
+	synced := runtimev1alpha1.ReconcileSuccess()
+	failed := runtimev1alpha1.ReconcileError(errBoom)
+	paused := runtimev1alpha1.Condition{Type: "Paused", Status: corev1.ConditionTrue, Reason: "Paused"}
+
+	container := func(version string, c ...runtimev1alpha1.Condition) *storagev1alpha3.Container {
+		cr := &storagev1alpha3.Container{ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: version}}
+		cr.SetConditions(c...)
+		return cr
+	}
+	latest := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		*obj.(*storagev1alpha3.Container) = *container("2", failed, paused)
+		return nil
+	})
+
+	type want struct {
+		updated []runtime.Object
+		err     error
+	}
+
+	cases := map[string]struct {
+		kube *test.MockClient
+		want want
+	}{
+		"Updated": {
+			kube: &test.MockClient{MockStatusUpdate: test.NewMockStatusUpdateFn(nil)},
+			want: want{},
+		},
+		"UpdateFailed": {
+			kube: &test.MockClient{MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom)},
+			want: want{err: errBoom},
+		},
+		"ConflictRetried": {
+			kube: &test.MockClient{MockGet: latest},
+			want: want{updated: []runtime.Object{
+				container("1", creating, synced),
+				container("2", synced, paused, creating),
+			}},
+		},
+		"GetLatestFailed": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				updated: []runtime.Object{container("1", creating, synced)},
+				err:     errors.Wrap(errBoom, errGetLatest),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated []runtime.Object
+			if tc.kube.MockStatusUpdate == nil {
+				tc.kube.MockStatusUpdate = conflictOnce(nil, &updated)
+			}
+			err := UpdateStatus(context.Background(), tc.kube, tc.kube, container("1", creating, synced))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UpdateStatus(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("UpdateStatus(...): -want updated, +got updated:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateConditions(t *testing.T) {
+	errBoom := errors.New("boom")
+	creating := runtimev1alpha1.Creating()
+	synced := runtimev1alpha1.ReconcileSuccess()
+
+	managed := func(c ...runtimev1alpha1.Condition) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+		mg.SetConditions(c...)
+		return mg
+	}
+
+	type want struct {
+		updated []runtime.Object
+		err     error
+	}
+
+	cases := map[string]struct {
+		err  error
+		get  test.MockGetFn
+		want want
+	}{
+		"ConflictRetried": {
+			get: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				*obj.(*fake.Managed) = *managed(synced)
+				return nil
+			}),
+			want: want{updated: []runtime.Object{
+				managed(creating),
+				managed(synced, creating),
+			}},
+		},
+		"RetryFailed": {
+			err: errBoom,
+			get: test.NewMockGetFn(nil),
+			want: want{
+				updated: []runtime.Object{managed(creating), managed(creating)},
+				err:     errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated []runtime.Object
+			kube := &test.MockClient{MockGet: tc.get, MockStatusUpdate: conflictOnce(tc.err, &updated)}
+			err := UpdateConditions(context.Background(), kube, kube, managed(), creating)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UpdateConditions(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("UpdateConditions(...): -want updated, +got updated:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// AccountAccessAPIVersion is the API version that must be used to read and
+// write the AccountAccess of a storage account.
+const AccountAccessAPIVersion = "2021-04-01"
+
+// AccountAccess is the public and shared key access of a storage account. Our
+// Azure SDK predates it, and thus omits it from its models.
+type AccountAccess struct {
+	AllowBlobPublicAccess *bool `json:"allowBlobPublicAccess,omitempty"`
+	AllowSharedKeyAccess  *bool `json:"allowSharedKeyAccess,omitempty"`
+}
+
+// IsZero returns true if no access is specified.
+func (a AccountAccess) IsZero() bool {
+	return a.AllowBlobPublicAccess == nil && a.AllowSharedKeyAccess == nil
+}
+
+// Properties returns the specified access as storage account properties.
+func (a AccountAccess) Properties() map[string]interface{} {
+	p := map[string]interface{}{}
+	if a.AllowBlobPublicAccess != nil {
+		p["allowBlobPublicAccess"] = *a.AllowBlobPublicAccess
+	}
+	if a.AllowSharedKeyAccess != nil {
+		p["allowSharedKeyAccess"] = *a.AllowSharedKeyAccess
+	}
+	return p
+}
+
+// NewAccountAccess returns the AccountAccess requested by the supplied
+// AccountParameters.
+func NewAccountAccess(p v1alpha3.AccountParameters) AccountAccess {
+	return AccountAccess{
+		AllowBlobPublicAccess: p.AllowBlobPublicAccess,
+		AllowSharedKeyAccess:  p.AllowSharedKeyAccess,
+	}
+}
+
+// IsAccountAccessUpToDate returns true if the supplied observed access matches
+// the supplied desired access. Access that is not desired is ignored. Azure
+// allows access that has never been configured.
+func IsAccountAccessUpToDate(desired, observed AccountAccess) bool {
+	if d := desired.AllowBlobPublicAccess; d != nil && *d != boolOrTrue(observed.AllowBlobPublicAccess) {
+		return false
+	}
+	if d := desired.AllowSharedKeyAccess; d != nil && *d != boolOrTrue(observed.AllowSharedKeyAccess) {
+		return false
+	}
+	return true
+}
+
+// IsSharedKeyAccessDisabled returns true if the supplied AccountParameters
+// disallow requests authorized with the storage account's access keys.
+func IsSharedKeyAccessDisabled(p v1alpha3.AccountParameters) bool {
+	return p.AllowSharedKeyAccess != nil && !*p.AllowSharedKeyAccess
+}
+
+func boolOrTrue(b *bool) bool {
+	return b == nil || *b
+}
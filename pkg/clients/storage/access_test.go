@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAccountAccessProperties(t *testing.T) {
+	cases := map[string]struct {
+		a    AccountAccess
+		want map[string]interface{}
+	}{
+		"Unspecified": {
+			want: map[string]interface{}{},
+		},
+		"Specified": {
+			a:    AccountAccess{AllowBlobPublicAccess: to.BoolPtr(false), AllowSharedKeyAccess: to.BoolPtr(true)},
+			want: map[string]interface{}{"allowBlobPublicAccess": false, "allowSharedKeyAccess": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.a.Properties()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Properties(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsAccountAccessUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		desired  AccountAccess
+		observed AccountAccess
+		want     bool
+	}{
+		"NothingDesired": {
+			observed: AccountAccess{AllowBlobPublicAccess: to.BoolPtr(false)},
+			want:     true,
+		},
+		"UpToDate": {
+			desired:  AccountAccess{AllowBlobPublicAccess: to.BoolPtr(false), AllowSharedKeyAccess: to.BoolPtr(false)},
+			observed: AccountAccess{AllowBlobPublicAccess: to.BoolPtr(false), AllowSharedKeyAccess: to.BoolPtr(false)},
+			want:     true,
+		},
+		"NeverConfiguredIsAllowed": {
+			desired: AccountAccess{AllowSharedKeyAccess: to.BoolPtr(true)},
+			want:    true,
+		},
+		"SharedKeyAccessDrifted": {
+			desired:  AccountAccess{AllowSharedKeyAccess: to.BoolPtr(false)},
+			observed: AccountAccess{AllowSharedKeyAccess: to.BoolPtr(true)},
+			want:     false,
+		},
+		"BlobPublicAccessNeverConfigured": {
+			desired: AccountAccess{AllowBlobPublicAccess: to.BoolPtr(false)},
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsAccountAccessUpToDate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsAccountAccessUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// NewSignedIdentifiers returns the signed identifiers that represent the
+// supplied stored access policies.
+func NewSignedIdentifiers(p []v1alpha3.StoredAccessPolicy) []azblob.SignedIdentifier {
+	if len(p) == 0 {
+		return nil
+	}
+	ids := make([]azblob.SignedIdentifier, len(p))
+	for i, sap := range p {
+		ids[i] = azblob.SignedIdentifier{
+			ID: sap.ID,
+			AccessPolicy: azblob.AccessPolicy{
+				Start:      sap.Start.UTC(),
+				Expiry:     sap.Expiry.UTC(),
+				Permission: normalizePermissions(sap.Permissions),
+			},
+		}
+	}
+	return ids
+}
+
+// SignedIdentifiersNeedUpdate returns true if the supplied desired signed
+// identifiers differ from the supplied observed signed identifiers. Azure
+// reports times with second precision, and permissions in a canonical order.
+func SignedIdentifiersNeedUpdate(desired, observed []azblob.SignedIdentifier) bool {
+	if len(desired) != len(observed) {
+		return true
+	}
+	d, o := sortedIdentifiers(desired), sortedIdentifiers(observed)
+	for i := range d {
+		if d[i].ID != o[i].ID {
+			return true
+		}
+		dp, op := d[i].AccessPolicy, o[i].AccessPolicy
+		if normalizePermissions(dp.Permission) != normalizePermissions(op.Permission) {
+			return true
+		}
+		if !dp.Start.Truncate(time.Second).Equal(op.Start.Truncate(time.Second)) {
+			return true
+		}
+		if !dp.Expiry.Truncate(time.Second).Equal(op.Expiry.Truncate(time.Second)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIdentifiers(ids []azblob.SignedIdentifier) []azblob.SignedIdentifier {
+	out := make([]azblob.SignedIdentifier, len(ids))
+	copy(out, ids)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// normalizePermissions returns the supplied permissions in the canonical order
+// in which Azure reports them. Unknown permissions are returned unchanged.
+func normalizePermissions(p string) string {
+	perm := azblob.AccessPolicyPermission{}
+	if err := perm.Parse(p); err != nil {
+		return p
+	}
+	return perm.String()
+}
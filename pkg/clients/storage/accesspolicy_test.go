@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+func TestNewSignedIdentifiers(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := start.Add(24 * time.Hour)
+
+	cases := map[string]struct {
+		p    []v1alpha3.StoredAccessPolicy
+		want []azblob.SignedIdentifier
+	}{
+		"Nil": {},
+		"Policies": {
+			p: []v1alpha3.StoredAccessPolicy{{
+				ID:          "readers",
+				Permissions: "lr",
+				Start:       metav1.NewTime(start.In(time.FixedZone("PST", -8*60*60))),
+				Expiry:      metav1.NewTime(expiry),
+			}},
+			want: []azblob.SignedIdentifier{{
+				ID:           "readers",
+				AccessPolicy: azblob.AccessPolicy{Start: start, Expiry: expiry, Permission: "rl"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewSignedIdentifiers(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewSignedIdentifiers(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSignedIdentifiersNeedUpdate(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := start.Add(24 * time.Hour)
+	readers := azblob.SignedIdentifier{ID: "readers", AccessPolicy: azblob.AccessPolicy{Start: start, Expiry: expiry, Permission: "rl"}}
+	writers := azblob.SignedIdentifier{ID: "writers", AccessPolicy: azblob.AccessPolicy{Start: start, Expiry: expiry, Permission: "racwdl"}}
+
+	cases := map[string]struct {
+		desired  []azblob.SignedIdentifier
+		observed []azblob.SignedIdentifier
+		want     bool
+	}{
+		"UpToDate": {
+			desired:  []azblob.SignedIdentifier{readers, writers},
+			observed: []azblob.SignedIdentifier{writers, readers},
+			want:     false,
+		},
+		"SubSecondPrecision": {
+			desired: []azblob.SignedIdentifier{{
+				ID:           "readers",
+				AccessPolicy: azblob.AccessPolicy{Start: start.Add(500 * time.Millisecond), Expiry: expiry, Permission: "lr"},
+			}},
+			observed: []azblob.SignedIdentifier{readers},
+			want:     false,
+		},
+		"PolicyAdded": {
+			desired:  []azblob.SignedIdentifier{readers, writers},
+			observed: []azblob.SignedIdentifier{readers},
+			want:     true,
+		},
+		"PolicyRemoved": {
+			observed: []azblob.SignedIdentifier{readers},
+			want:     true,
+		},
+		"PermissionsChanged": {
+			desired:  []azblob.SignedIdentifier{{ID: "readers", AccessPolicy: azblob.AccessPolicy{Start: start, Expiry: expiry, Permission: "r"}}},
+			observed: []azblob.SignedIdentifier{readers},
+			want:     true,
+		},
+		"ExpiryChanged": {
+			desired:  []azblob.SignedIdentifier{{ID: "readers", AccessPolicy: azblob.AccessPolicy{Start: start, Expiry: start, Permission: "rl"}}},
+			observed: []azblob.SignedIdentifier{readers},
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SignedIdentifiersNeedUpdate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("SignedIdentifiersNeedUpdate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,12 +20,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 )
 
@@ -54,6 +58,75 @@ func NewStorageAccountClient(data []byte) (*storage.AccountsClient, error) {
 	return &client, nil
 }
 
+const errFmtSkuChange = "cannot change storage account SKU from %s to %s"
+
+// replicationSkuNames are the SKUs between which Azure can convert a storage
+// account in place. Accounts cannot be converted to or from zone-redundant
+// or premium storage.
+var replicationSkuNames = map[storage.SkuName]bool{
+	storage.StandardLRS:   true,
+	storage.StandardGRS:   true,
+	storage.StandardRAGRS: true,
+}
+
+// ValidateSkuChange returns an error if a storage account with the supplied
+// observed SKU cannot be converted to the supplied desired SKU.
+func ValidateSkuChange(observed, desired storage.SkuName) error {
+	if observed == desired || observed == "" || desired == "" {
+		return nil
+	}
+	if !replicationSkuNames[observed] || !replicationSkuNames[desired] {
+		return errors.Errorf(errFmtSkuChange, observed, desired)
+	}
+	return nil
+}
+
+const errFmtKindChange = "cannot change storage account kind from %s to %s"
+
+// KindStorageV2 is the general purpose v2 storage account kind. It is newer
+// than the storage API version we use for most account operations.
+const KindStorageV2 storage.Kind = storage.Kind(mgmtstorage.StorageV2)
+
+// ExtensionsAPIVersion is the API version used to read the AccountExtensions
+// of a storage account. It must be the newest of ImmutableStorageAPIVersion,
+// AccountAccessAPIVersion, and the API version of the routing preference.
+const ExtensionsAPIVersion = ImmutableStorageAPIVersion
+
+// AccountExtensions are the properties of a storage account that the API
+// version we use for most account operations does not support.
+type AccountExtensions struct {
+	// Properties include the routing preference of the storage account and
+	// the routing specific endpoints it publishes.
+	Properties mgmtstorage.AccountProperties
+
+	ImmutableStorage ImmutableStorageWithVersioning
+	Access           AccountAccess
+}
+
+// StorageV2APIVersion is the API version used to create general purpose v2
+// storage accounts, which the API version we use for most account operations
+// does not support.
+const StorageV2APIVersion = "2019-06-01"
+
+// upgradableKinds are the kinds of storage account that Azure can upgrade in
+// place to a general purpose v2 account. No other kind changes are supported.
+var upgradableKinds = map[storage.Kind]bool{
+	storage.Storage:     true,
+	storage.BlobStorage: true,
+}
+
+// ValidateKindChange returns an error if a storage account of the supplied
+// observed kind cannot be upgraded to the supplied desired kind.
+func ValidateKindChange(observed, desired storage.Kind) error {
+	if observed == desired || observed == "" || desired == "" {
+		return nil
+	}
+	if desired != KindStorageV2 || !upgradableKinds[observed] {
+		return errors.Errorf(errFmtKindChange, observed, desired)
+	}
+	return nil
+}
+
 // AccountOperations Azure storate account interface
 type AccountOperations interface {
 	Create(context.Context, storage.AccountCreateParameters) (*storage.Account, error)
@@ -62,6 +135,15 @@ type AccountOperations interface {
 	Delete(ctx context.Context) error
 	IsAccountNameAvailable(context.Context, string) error
 	ListKeys(context.Context) ([]storage.AccountKey, error)
+	GetBlobServiceProperties(context.Context) (*mgmtstorage.BlobServiceProperties, error)
+	SetBlobServiceProperties(context.Context, mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error)
+	SetRoutingPreference(context.Context, mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error)
+	UpgradeKind(context.Context, storage.Kind) error
+	CreateWithImmutableStorage(context.Context, storage.AccountCreateParameters, ImmutableStorageWithVersioning) (*storage.Account, error)
+	GetExtensions(context.Context) (*AccountExtensions, error)
+	SetAccess(context.Context, AccountAccess) (*AccountAccess, error)
+	Failover(context.Context) (*v1alpha3.AsyncOperation, error)
+	FetchOperation(context.Context, *v1alpha3.AsyncOperation) error
 }
 
 // AccountHandle implements AccountOperations interface
@@ -84,11 +166,31 @@ func NewAccountHandle(client *storage.AccountsClient, groupName, accountName str
 
 // Create create new storage account with given location
 func (a *AccountHandle) Create(ctx context.Context, params storage.AccountCreateParameters) (*storage.Account, error) {
+	c := *a.client
+	if params.Kind == KindStorageV2 {
+		c.RequestInspector = azure.WithAPIVersion(StorageV2APIVersion)
+	}
+	return a.create(ctx, &c, params)
+}
+
+// CreateWithImmutableStorage creates a new storage account with the supplied
+// account-level immutable storage, which may only be configured at creation.
+func (a *AccountHandle) CreateWithImmutableStorage(ctx context.Context, params storage.AccountCreateParameters, s ImmutableStorageWithVersioning) (*storage.Account, error) {
+	c := *a.client
+	c.RequestInspector = func(p autorest.Preparer) autorest.Preparer {
+		return autorest.DecoratePreparer(p,
+			azure.WithAPIVersion(ImmutableStorageAPIVersion),
+			azure.WithProperties(map[string]interface{}{"immutableStorageWithVersioning": s}))
+	}
+	return a.create(ctx, &c, params)
+}
+
+func (a *AccountHandle) create(ctx context.Context, client *storage.AccountsClient, params storage.AccountCreateParameters) (*storage.Account, error) {
 	if err := a.IsAccountNameAvailable(ctx, a.accountName); err != nil {
 		return nil, errors.Wrapf(err, "failed to check account name availability")
 	}
 
-	future, err := a.client.Create(ctx, a.groupName, a.accountName, params)
+	future, err := client.Create(ctx, a.groupName, a.accountName, params)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to start creating storage account")
 	}
@@ -157,3 +259,123 @@ func (a *AccountHandle) ListKeys(ctx context.Context) ([]storage.AccountKey, err
 
 	return *rs.Keys, nil
 }
+
+// GetBlobServiceProperties of this storage account
+func (a *AccountHandle) GetBlobServiceProperties(ctx context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+	p, err := a.blobServices().GetServiceProperties(ctx, a.groupName, a.accountName)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetBlobServiceProperties of this storage account
+func (a *AccountHandle) SetBlobServiceProperties(ctx context.Context, params mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error) {
+	p, err := a.blobServices().SetServiceProperties(ctx, a.groupName, a.accountName, params)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetRoutingPreference of this storage account
+func (a *AccountHandle) SetRoutingPreference(ctx context.Context, rp mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+	params := mgmtstorage.AccountUpdateParameters{
+		AccountPropertiesUpdateParameters: &mgmtstorage.AccountPropertiesUpdateParameters{RoutingPreference: &rp},
+	}
+	acct, err := a.accounts().Update(ctx, a.groupName, a.accountName, params)
+	if err != nil {
+		return nil, err
+	}
+	if acct.AccountProperties == nil {
+		return &mgmtstorage.AccountProperties{}, nil
+	}
+	return acct.AccountProperties, nil
+}
+
+// UpgradeKind of this storage account in place, e.g. from a general purpose
+// v1 account to a general purpose v2 account.
+func (a *AccountHandle) UpgradeKind(ctx context.Context, kind storage.Kind) error {
+	_, err := a.accounts().Update(ctx, a.groupName, a.accountName, mgmtstorage.AccountUpdateParameters{Kind: mgmtstorage.Kind(kind)})
+	return err
+}
+
+// GetExtensions returns the routing preference, account-level immutable
+// storage, and access of this storage account. They are decoded from a single
+// GET using the newest API version any of them requires.
+func (a *AccountHandle) GetExtensions(ctx context.Context) (*AccountExtensions, error) {
+	ext := &AccountExtensions{}
+	immutable := struct {
+		ImmutableStorageWithVersioning *ImmutableStorageWithVersioning `json:"immutableStorageWithVersioning,omitempty"`
+	}{}
+	c := *a.client
+	c.RequestInspector = azure.WithAPIVersion(ExtensionsAPIVersion)
+	c.ResponseInspector = func(r autorest.Responder) autorest.Responder {
+		return azure.ByDecodingProperties(&ext.Properties)(
+			azure.ByDecodingProperties(&immutable)(
+				azure.ByDecodingProperties(&ext.Access)(r)))
+	}
+	if _, err := c.GetProperties(ctx, a.groupName, a.accountName); err != nil {
+		return nil, err
+	}
+	if immutable.ImmutableStorageWithVersioning != nil {
+		ext.ImmutableStorage = *immutable.ImmutableStorageWithVersioning
+	}
+	return ext, nil
+}
+
+// SetAccess of this storage account. Access that is not specified is left
+// unchanged.
+func (a *AccountHandle) SetAccess(ctx context.Context, access AccountAccess) (*AccountAccess, error) {
+	c := *a.client
+	c.RequestInspector = func(p autorest.Preparer) autorest.Preparer {
+		return autorest.DecoratePreparer(p,
+			azure.WithAPIVersion(AccountAccessAPIVersion),
+			azure.WithProperties(access.Properties()))
+	}
+	if _, err := c.Update(ctx, a.groupName, a.accountName, storage.AccountUpdateParameters{}); err != nil {
+		return nil, err
+	}
+	ext, err := a.GetExtensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ext.Access, nil
+}
+
+// Failover this storage account to its secondary region. Failover is a long
+// running operation; the returned operation may be used to fetch its status.
+func (a *AccountHandle) Failover(ctx context.Context) (*v1alpha3.AsyncOperation, error) {
+	f, err := a.accounts().Failover(ctx, a.groupName, a.accountName)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha3.AsyncOperation{
+		Method:     http.MethodPost,
+		PollingURL: f.PollingURL(),
+		Status:     azure.AsyncOperationStatusInProgress,
+	}, nil
+}
+
+// FetchOperation updates the status of the supplied long running operation.
+func (a *AccountHandle) FetchOperation(ctx context.Context, op *v1alpha3.AsyncOperation) error {
+	return azure.FetchAsyncOperation(ctx, a.client.Client, op)
+}
+
+// accounts returns an accounts client that shares the configuration of the
+// accounts client, but uses the newer API version that routing preferences
+// require.
+func (a *AccountHandle) accounts() mgmtstorage.AccountsClient {
+	c := mgmtstorage.NewAccountsClientWithBaseURI(a.client.BaseURI, a.client.SubscriptionID)
+	c.Client = a.client.Client
+	return c
+}
+
+// blobServices returns a blob services client that shares the configuration
+// of the accounts client. Blob service properties such as point-in-time
+// restore require a newer API version than the accounts client uses.
+func (a *AccountHandle) blobServices() mgmtstorage.BlobServicesClient {
+	c := mgmtstorage.NewBlobServicesClientWithBaseURI(a.client.BaseURI, a.client.SubscriptionID)
+	c.Client = a.client.Client
+	return c
+}
@@ -105,3 +105,94 @@ func TestNewAccountHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSkuChange(t *testing.T) {
+	cases := map[string]struct {
+		observed storage.SkuName
+		desired  storage.SkuName
+		want     error
+	}{
+		"Unchanged": {
+			observed: storage.StandardZRS,
+			desired:  storage.StandardZRS,
+		},
+		"NotYetObserved": {
+			desired: storage.StandardZRS,
+		},
+		"LRSToGRS": {
+			observed: storage.StandardLRS,
+			desired:  storage.StandardGRS,
+		},
+		"RAGRSToLRS": {
+			observed: storage.StandardRAGRS,
+			desired:  storage.StandardLRS,
+		},
+		"LRSToZRS": {
+			observed: storage.StandardLRS,
+			desired:  storage.StandardZRS,
+			want:     errors.Errorf(errFmtSkuChange, storage.StandardLRS, storage.StandardZRS),
+		},
+		"ZRSToGRS": {
+			observed: storage.StandardZRS,
+			desired:  storage.StandardGRS,
+			want:     errors.Errorf(errFmtSkuChange, storage.StandardZRS, storage.StandardGRS),
+		},
+		"PremiumToStandard": {
+			observed: storage.PremiumLRS,
+			desired:  storage.StandardLRS,
+			want:     errors.Errorf(errFmtSkuChange, storage.PremiumLRS, storage.StandardLRS),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateSkuChange(tc.observed, tc.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateSkuChange(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateKindChange(t *testing.T) {
+	cases := map[string]struct {
+		observed storage.Kind
+		desired  storage.Kind
+		want     error
+	}{
+		"Unchanged": {
+			observed: storage.BlobStorage,
+			desired:  storage.BlobStorage,
+		},
+		"NotYetObserved": {
+			desired: KindStorageV2,
+		},
+		"StorageToStorageV2": {
+			observed: storage.Storage,
+			desired:  KindStorageV2,
+		},
+		"BlobStorageToStorageV2": {
+			observed: storage.BlobStorage,
+			desired:  KindStorageV2,
+		},
+		"StorageV2ToStorage": {
+			observed: KindStorageV2,
+			desired:  storage.Storage,
+			want:     errors.Errorf(errFmtKindChange, KindStorageV2, storage.Storage),
+		},
+		"StorageToBlobStorage": {
+			observed: storage.Storage,
+			desired:  storage.BlobStorage,
+			want:     errors.Errorf(errFmtKindChange, storage.Storage, storage.BlobStorage),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateKindChange(tc.observed, tc.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateKindChange(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// Error strings.
+const (
+	errRestoreDaysRequired         = "restore policy days must be specified when restore is enabled"
+	errRestoreRequiresBlobDeletion = "restore policy requires blob delete retention to be enabled"
+	errFmtRestoreDaysTooLong       = "restore policy days (%d) must be less than blob delete retention days (%d)"
+)
+
+// NewBlobServiceProperties returns the Azure blob service properties that
+// correspond to the supplied desired properties. Blob versioning and change
+// feed are enabled when point-in-time restore is enabled, as are blob delete
+// retention policies if none are specified.
+func NewBlobServiceProperties(p *v1alpha3.BlobServiceProperties) mgmtstorage.BlobServiceProperties {
+	if p == nil {
+		return mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{}}
+	}
+	props := &mgmtstorage.BlobServicePropertiesProperties{
+		DeleteRetentionPolicy:          newDeleteRetentionPolicy(p.DeleteRetentionPolicy),
+		ContainerDeleteRetentionPolicy: newDeleteRetentionPolicy(p.ContainerDeleteRetentionPolicy),
+	}
+	if r := p.RestorePolicy; r != nil {
+		props.RestorePolicy = &mgmtstorage.RestorePolicyProperties{Enabled: to.BoolPtr(r.Enabled)}
+		if r.Enabled {
+			props.RestorePolicy.Days = r.Days
+			props.IsVersioningEnabled = to.BoolPtr(true)
+			props.ChangeFeed = &mgmtstorage.ChangeFeed{Enabled: to.BoolPtr(true)}
+			if props.DeleteRetentionPolicy == nil && r.Days != nil {
+				props.DeleteRetentionPolicy = &mgmtstorage.DeleteRetentionPolicy{
+					Enabled: to.BoolPtr(true),
+					Days:    to.Int32Ptr(*r.Days + 1),
+				}
+			}
+		}
+	}
+	return mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: props}
+}
+
+func newDeleteRetentionPolicy(p *v1alpha3.DeleteRetentionPolicy) *mgmtstorage.DeleteRetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(p.Enabled)}
+	if p.Enabled {
+		out.Days = p.Days
+	}
+	return out
+}
+
+// ValidateBlobServiceProperties returns an error if the supplied blob service
+// properties do not satisfy the prerequisites of point-in-time restore.
+func ValidateBlobServiceProperties(p *v1alpha3.BlobServiceProperties) error {
+	if p == nil || p.RestorePolicy == nil || !p.RestorePolicy.Enabled {
+		return nil
+	}
+	if p.RestorePolicy.Days == nil {
+		return errors.New(errRestoreDaysRequired)
+	}
+	d := p.DeleteRetentionPolicy
+	if d == nil {
+		// We'll enable a suitable delete retention policy.
+		return nil
+	}
+	if !d.Enabled || d.Days == nil {
+		return errors.New(errRestoreRequiresBlobDeletion)
+	}
+	if *p.RestorePolicy.Days >= *d.Days {
+		return errors.Errorf(errFmtRestoreDaysTooLong, *p.RestorePolicy.Days, *d.Days)
+	}
+	return nil
+}
+
+// IsBlobServiceUpToDate returns true if the supplied observed blob service
+// properties match the supplied desired properties. Properties that are not
+// desired are ignored.
+func IsBlobServiceUpToDate(desired, observed mgmtstorage.BlobServiceProperties) bool {
+	d, o := desired.BlobServicePropertiesProperties, observed.BlobServicePropertiesProperties
+	if d == nil {
+		return true
+	}
+	if o == nil {
+		o = &mgmtstorage.BlobServicePropertiesProperties{}
+	}
+	if !deleteRetentionPolicyUpToDate(d.DeleteRetentionPolicy, o.DeleteRetentionPolicy) {
+		return false
+	}
+	if !deleteRetentionPolicyUpToDate(d.ContainerDeleteRetentionPolicy, o.ContainerDeleteRetentionPolicy) {
+		return false
+	}
+	if d.IsVersioningEnabled != nil && to.Bool(d.IsVersioningEnabled) != to.Bool(o.IsVersioningEnabled) {
+		return false
+	}
+	if d.ChangeFeed != nil && (o.ChangeFeed == nil || to.Bool(d.ChangeFeed.Enabled) != to.Bool(o.ChangeFeed.Enabled)) {
+		return false
+	}
+	if d.RestorePolicy != nil {
+		if o.RestorePolicy == nil || to.Bool(d.RestorePolicy.Enabled) != to.Bool(o.RestorePolicy.Enabled) {
+			return false
+		}
+		if d.RestorePolicy.Days != nil && to.Int32(d.RestorePolicy.Days) != to.Int32(o.RestorePolicy.Days) {
+			return false
+		}
+	}
+	return true
+}
+
+func deleteRetentionPolicyUpToDate(desired, observed *mgmtstorage.DeleteRetentionPolicy) bool {
+	if desired == nil {
+		return true
+	}
+	if observed == nil || to.Bool(desired.Enabled) != to.Bool(observed.Enabled) {
+		return false
+	}
+	return desired.Days == nil || to.Int32(desired.Days) == to.Int32(observed.Days)
+}
+
+// NewBlobServiceStatus returns the status of the supplied blob service
+// properties at the supplied time. Blobs may be restored to any time since the
+// restore policy was last enabled, within the policy's days of now.
+func NewBlobServiceStatus(p mgmtstorage.BlobServiceProperties, now time.Time) *v1alpha3.BlobServiceStatus {
+	props := p.BlobServicePropertiesProperties
+	if props == nil {
+		return &v1alpha3.BlobServiceStatus{}
+	}
+	s := &v1alpha3.BlobServiceStatus{IsVersioningEnabled: to.Bool(props.IsVersioningEnabled)}
+	if props.ChangeFeed != nil {
+		s.ChangeFeedEnabled = to.Bool(props.ChangeFeed.Enabled)
+	}
+	r := props.RestorePolicy
+	if r == nil || r.LastEnabledTime == nil {
+		return s
+	}
+	enabled := metav1.NewTime(r.LastEnabledTime.ToTime())
+	s.RestorePolicyLastEnabledTime = &enabled
+	if !to.Bool(r.Enabled) {
+		return s
+	}
+	min := enabled
+	if earliest := now.AddDate(0, 0, -int(to.Int32(r.Days))); earliest.After(min.Time) {
+		min = metav1.NewTime(earliest)
+	}
+	s.MinRestoreTime = &min
+	return s
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestNewBlobServiceProperties(t *testing.T) {
+	cases := map[string]struct {
+		p    *v1alpha3.BlobServiceProperties
+		want mgmtstorage.BlobServiceProperties
+	}{
+		"Nil": {
+			want: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{}},
+		},
+		"SoftDelete": {
+			p: &v1alpha3.BlobServiceProperties{
+				DeleteRetentionPolicy:          &v1alpha3.DeleteRetentionPolicy{Enabled: false, Days: azure.ToInt32Ptr(7)},
+				ContainerDeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+			want: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				DeleteRetentionPolicy:          &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(false)},
+				ContainerDeleteRetentionPolicy: &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(7)},
+			}},
+		},
+		"RestoreEnablesPrerequisites": {
+			p: &v1alpha3.BlobServiceProperties{
+				RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+			want: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				DeleteRetentionPolicy: &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(8)},
+				RestorePolicy:         &mgmtstorage.RestorePolicyProperties{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(7)},
+				IsVersioningEnabled:   to.BoolPtr(true),
+				ChangeFeed:            &mgmtstorage.ChangeFeed{Enabled: to.BoolPtr(true)},
+			}},
+		},
+		"RestoreKeepsDeleteRetention": {
+			p: &v1alpha3.BlobServiceProperties{
+				DeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: true, Days: azure.ToInt32Ptr(30)},
+				RestorePolicy:         &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+			want: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				DeleteRetentionPolicy: &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(30)},
+				RestorePolicy:         &mgmtstorage.RestorePolicyProperties{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(7)},
+				IsVersioningEnabled:   to.BoolPtr(true),
+				ChangeFeed:            &mgmtstorage.ChangeFeed{Enabled: to.BoolPtr(true)},
+			}},
+		},
+		"RestoreDisabled": {
+			p: &v1alpha3.BlobServiceProperties{
+				RestorePolicy: &v1alpha3.RestorePolicy{Enabled: false, Days: azure.ToInt32Ptr(7)},
+			},
+			want: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				RestorePolicy: &mgmtstorage.RestorePolicyProperties{Enabled: to.BoolPtr(false)},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewBlobServiceProperties(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewBlobServiceProperties(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateBlobServiceProperties(t *testing.T) {
+	cases := map[string]struct {
+		p    *v1alpha3.BlobServiceProperties
+		want error
+	}{
+		"Nil": {},
+		"RestoreDisabled": {
+			p: &v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: false}},
+		},
+		"RestoreWithoutDays": {
+			p:    &v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true}},
+			want: errors.New(errRestoreDaysRequired),
+		},
+		"RestoreWithDefaultDeleteRetention": {
+			p: &v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)}},
+		},
+		"RestoreWithDeleteRetentionDisabled": {
+			p: &v1alpha3.BlobServiceProperties{
+				DeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: false},
+				RestorePolicy:         &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+			want: errors.New(errRestoreRequiresBlobDeletion),
+		},
+		"RestoreDaysTooLong": {
+			p: &v1alpha3.BlobServiceProperties{
+				DeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+				RestorePolicy:         &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+			want: errors.Errorf(errFmtRestoreDaysTooLong, 7, 7),
+		},
+		"Valid": {
+			p: &v1alpha3.BlobServiceProperties{
+				DeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: true, Days: azure.ToInt32Ptr(8)},
+				RestorePolicy:         &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBlobServiceProperties(tc.p)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateBlobServiceProperties(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsBlobServiceUpToDate(t *testing.T) {
+	restore := &v1alpha3.BlobServiceProperties{
+		ContainerDeleteRetentionPolicy: &v1alpha3.DeleteRetentionPolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+		RestorePolicy:                  &v1alpha3.RestorePolicy{Enabled: true, Days: azure.ToInt32Ptr(7)},
+	}
+	observed := func(m ...func(*mgmtstorage.BlobServicePropertiesProperties)) mgmtstorage.BlobServiceProperties {
+		p := &mgmtstorage.BlobServicePropertiesProperties{
+			DeleteRetentionPolicy:          &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(8)},
+			ContainerDeleteRetentionPolicy: &mgmtstorage.DeleteRetentionPolicy{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(7)},
+			RestorePolicy:                  &mgmtstorage.RestorePolicyProperties{Enabled: to.BoolPtr(true), Days: azure.ToInt32Ptr(7)},
+			IsVersioningEnabled:            to.BoolPtr(true),
+			ChangeFeed:                     &mgmtstorage.ChangeFeed{Enabled: to.BoolPtr(true)},
+			DefaultServiceVersion:          to.StringPtr("2019-07-07"),
+		}
+		for _, fn := range m {
+			fn(p)
+		}
+		return mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: p}
+	}
+
+	cases := map[string]struct {
+		desired  *v1alpha3.BlobServiceProperties
+		observed mgmtstorage.BlobServiceProperties
+		want     bool
+	}{
+		"NothingDesired": {
+			observed: mgmtstorage.BlobServiceProperties{},
+			want:     true,
+		},
+		"UpToDate": {
+			desired:  restore,
+			observed: observed(),
+			want:     true,
+		},
+		"NoObservedProperties": {
+			desired:  restore,
+			observed: mgmtstorage.BlobServiceProperties{},
+			want:     false,
+		},
+		"ContainerRetentionDaysDiffer": {
+			desired: restore,
+			observed: observed(func(p *mgmtstorage.BlobServicePropertiesProperties) {
+				p.ContainerDeleteRetentionPolicy.Days = azure.ToInt32Ptr(14)
+			}),
+			want: false,
+		},
+		"VersioningDisabled": {
+			desired: restore,
+			observed: observed(func(p *mgmtstorage.BlobServicePropertiesProperties) {
+				p.IsVersioningEnabled = to.BoolPtr(false)
+			}),
+			want: false,
+		},
+		"ChangeFeedDisabled": {
+			desired: restore,
+			observed: observed(func(p *mgmtstorage.BlobServicePropertiesProperties) {
+				p.ChangeFeed = nil
+			}),
+			want: false,
+		},
+		"RestoreDaysDiffer": {
+			desired: restore,
+			observed: observed(func(p *mgmtstorage.BlobServicePropertiesProperties) {
+				p.RestorePolicy.Days = azure.ToInt32Ptr(3)
+			}),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsBlobServiceUpToDate(NewBlobServiceProperties(tc.desired), tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsBlobServiceUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewBlobServiceStatus(t *testing.T) {
+	now := time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)
+	recently := now.AddDate(0, 0, -2)
+	longAgo := now.AddDate(0, -1, 0)
+	metaTime := func(t time.Time) *metav1.Time { mt := metav1.NewTime(t); return &mt }
+
+	cases := map[string]struct {
+		p    mgmtstorage.BlobServiceProperties
+		want *v1alpha3.BlobServiceStatus
+	}{
+		"NoProperties": {
+			want: &v1alpha3.BlobServiceStatus{},
+		},
+		"RestoreNeverEnabled": {
+			p: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				IsVersioningEnabled: to.BoolPtr(true),
+				ChangeFeed:          &mgmtstorage.ChangeFeed{Enabled: to.BoolPtr(true)},
+			}},
+			want: &v1alpha3.BlobServiceStatus{IsVersioningEnabled: true, ChangeFeedEnabled: true},
+		},
+		"RecentlyEnabled": {
+			p: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				RestorePolicy: &mgmtstorage.RestorePolicyProperties{
+					Enabled:         to.BoolPtr(true),
+					Days:            azure.ToInt32Ptr(7),
+					LastEnabledTime: &date.Time{Time: recently},
+				},
+			}},
+			want: &v1alpha3.BlobServiceStatus{
+				RestorePolicyLastEnabledTime: metaTime(recently),
+				MinRestoreTime:               metaTime(recently),
+			},
+		},
+		"EnabledLongAgo": {
+			p: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				RestorePolicy: &mgmtstorage.RestorePolicyProperties{
+					Enabled:         to.BoolPtr(true),
+					Days:            azure.ToInt32Ptr(7),
+					LastEnabledTime: &date.Time{Time: longAgo},
+				},
+			}},
+			want: &v1alpha3.BlobServiceStatus{
+				RestorePolicyLastEnabledTime: metaTime(longAgo),
+				MinRestoreTime:               metaTime(now.AddDate(0, 0, -7)),
+			},
+		},
+		"Disabled": {
+			p: mgmtstorage.BlobServiceProperties{BlobServicePropertiesProperties: &mgmtstorage.BlobServicePropertiesProperties{
+				RestorePolicy: &mgmtstorage.RestorePolicyProperties{
+					Enabled:         to.BoolPtr(false),
+					LastEnabledTime: &date.Time{Time: longAgo},
+				},
+			}},
+			want: &v1alpha3.BlobServiceStatus{RestorePolicyLastEnabledTime: metaTime(longAgo)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewBlobServiceStatus(tc.p, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewBlobServiceStatus(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/Azure/azure-pipeline-go/pipeline"
 	"github.com/Azure/azure-storage-blob-go/azblob"
 
 	azure "github.com/crossplane/provider-azure/pkg/clients"
@@ -29,16 +30,41 @@ import (
 
 // ContainerOperations interface to perform operations on Container resources
 type ContainerOperations interface {
-	Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error
-	Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error
+	Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error
+	Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error
 	Get(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error)
+	GetAccessPolicy(ctx context.Context) ([]azblob.SignedIdentifier, error)
 	Delete(ctx context.Context) error
+	IsEmpty(ctx context.Context) (bool, error)
 }
 
 // ContainerHandle implements ContainerOperations
 type ContainerHandle struct {
 	azblob.ContainerURL
 	PublicAccessType azblob.PublicAccessType
+
+	pipeline pipeline.Pipeline
+}
+
+// listVersionsAPIVersion is the first version of the Blob service API that
+// can list previous versions of blobs. azblob does not yet support it.
+const listVersionsAPIVersion = "2019-12-12"
+
+// A listVersionsPipeline sends each request through the pipeline it wraps,
+// after asking the Blob service to include previous versions of blobs in any
+// list of blobs it returns. The request is rewritten before it is signed.
+type listVersionsPipeline struct {
+	pipeline.Pipeline
+}
+
+func (p listVersionsPipeline) Do(ctx context.Context, f pipeline.Factory, r pipeline.Request) (pipeline.Response, error) {
+	q := r.URL.Query()
+	if i := q.Get("include"); i != "" {
+		q.Set("include", i+",versions")
+		r.URL.RawQuery = q.Encode()
+		r.Header.Set("x-ms-version", listVersionsAPIVersion)
+	}
+	return p.Pipeline.Do(ctx, f, r)
 }
 
 var _ ContainerOperations = &ContainerHandle{}
@@ -61,21 +87,31 @@ func NewContainerHandle(accountName, accountKey, containerName string) (*Contain
 
 	return &ContainerHandle{
 		ContainerURL: service.NewContainerURL(containerName),
+		pipeline:     p,
 	}, nil
 }
 
-// Create container resource
-func (a *ContainerHandle) Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error {
-	_, err := a.ContainerURL.Create(ctx, azblob.Metadata{}, publicAccessType)
+// Create container resource, with the supplied stored access policies (i.e.
+// signed identifiers), if any.
+func (a *ContainerHandle) Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error {
+	if _, err := a.ContainerURL.Create(ctx, azblob.Metadata{}, publicAccessType); err != nil {
+		return err
+	}
+	if len(identifiers) == 0 {
+		return nil
+	}
+	_, err := a.ContainerURL.SetAccessPolicy(ctx, publicAccessType, identifiers, azblob.ContainerAccessConditions{})
 	return err
 }
 
-// Update container resource
-func (a *ContainerHandle) Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error {
+// Update container resource. The container's public access type and stored
+// access policies are set together, so any stored access policies that are
+// not supplied are removed.
+func (a *ContainerHandle) Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error {
 	if _, err := a.ContainerURL.SetMetadata(ctx, metadata, azblob.ContainerAccessConditions{}); err != nil {
 		return err
 	}
-	_, err := a.ContainerURL.SetAccessPolicy(ctx, publicAccessType, nil, azblob.ContainerAccessConditions{})
+	_, err := a.ContainerURL.SetAccessPolicy(ctx, publicAccessType, identifiers, azblob.ContainerAccessConditions{})
 	return err
 }
 
@@ -89,12 +125,40 @@ func (a *ContainerHandle) Get(ctx context.Context) (*azblob.PublicAccessType, az
 	return &publicAccess, emtpyMetaToNil(rs.NewMetadata()), nil
 }
 
+// GetAccessPolicy returns the container's stored access policies (i.e. signed
+// identifiers).
+func (a *ContainerHandle) GetAccessPolicy(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+	rs, err := a.ContainerURL.GetAccessPolicy(ctx, azblob.LeaseAccessConditions{})
+	if err != nil {
+		return nil, err
+	}
+	return rs.Items, nil
+}
+
 // Delete deletes the named container.
 func (a *ContainerHandle) Delete(ctx context.Context) error {
 	_, err := a.ContainerURL.Delete(ctx, azblob.ContainerAccessConditions{})
 	return err
 }
 
+// IsEmpty returns true if the container contains no blobs. Soft deleted blobs
+// and previous versions of blobs count, because deleting the container would
+// delete them too.
+func (a *ContainerHandle) IsEmpty(ctx context.Context) (bool, error) {
+	u := a.ContainerURL
+	if a.pipeline != nil {
+		u = u.WithPipeline(listVersionsPipeline{Pipeline: a.pipeline})
+	}
+	rs, err := u.ListBlobsFlatSegment(ctx, azblob.Marker{}, azblob.ListBlobsSegmentOptions{
+		MaxResults: 1,
+		Details:    azblob.BlobListingDetails{Deleted: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(rs.Segment.BlobItems) == 0, nil
+}
+
 func emtpyMetaToNil(m azblob.Metadata) azblob.Metadata {
 	if len(m) == 0 {
 		return nil
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainerHandleIsEmpty(t *testing.T) {
+	cases := map[string]struct {
+		blobs string
+		want  bool
+	}{
+		"Empty": {
+			want: true,
+		},
+		"SoftDeletedBlob": {
+			blobs: "<Blob><Name>a</Name><Deleted>true</Deleted><Properties></Properties></Blob>",
+			want:  false,
+		},
+		"PreviousVersion": {
+			blobs: "<Blob><Name>a</Name><VersionId>2020-10-15T00:00:00.0000000Z</VersionId><Properties></Properties></Blob>",
+			want:  false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The Blob service omits soft deleted blobs and previous
+				// versions unless they're explicitly included.
+				if got := r.URL.Query().Get("include"); got != "deleted,versions" {
+					http.Error(w, "include: "+got, http.StatusBadRequest)
+					return
+				}
+				if got := r.Header.Get("x-ms-version"); got != listVersionsAPIVersion {
+					http.Error(w, "x-ms-version: "+got, http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/xml")
+				fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults ContainerName="c"><Blobs>%s</Blobs><NextMarker /></EnumerationResults>`, tc.blobs)
+			}))
+			defer srv.Close()
+
+			u, _ := url.Parse(srv.URL + "/c")
+			p := azblob.NewPipeline(azblob.NewAnonymousCredential(), azblob.PipelineOptions{})
+			h := &ContainerHandle{ContainerURL: azblob.NewContainerURL(*u, p), pipeline: p}
+
+			got, err := h.IsEmpty(context.Background())
+			if err != nil {
+				t.Fatalf("h.IsEmpty(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("h.IsEmpty(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// AnnotationKeyFailover may be added to a storage account in order to request
+// that it fail over to its secondary region, for example to test disaster
+// recovery. The annotation is removed once the failover has completed.
+const AnnotationKeyFailover = "storage.azure.crossplane.io/failover"
+
+// Event reasons for storage account failovers.
+const (
+	ReasonFailoverStarted   event.Reason = "StartedFailover"
+	ReasonFailoverCompleted event.Reason = "CompletedFailover"
+	ReasonFailoverFailed    event.Reason = "FailedFailover"
+)
+
+const errFmtFailoverNotGeoReplicated = "cannot fail over storage account with SKU %s; only geo-replicated storage accounts can fail over"
+
+// geoReplicatedSkuNames are the SKUs of storage accounts that replicate to a
+// secondary region, and can thus fail over to it.
+var geoReplicatedSkuNames = map[storage.SkuName]bool{
+	storage.StandardGRS:                true,
+	storage.StandardRAGRS:              true,
+	storage.SkuName("Standard_GZRS"):   true,
+	storage.SkuName("Standard_RAGZRS"): true,
+}
+
+// FailoverRequested returns true if a failover of the supplied storage account
+// has been requested.
+func FailoverRequested(o metav1.Object) bool {
+	_, ok := o.GetAnnotations()[AnnotationKeyFailover]
+	return ok
+}
+
+// ValidateFailover returns an error if a storage account with the supplied
+// SKU cannot fail over.
+func ValidateFailover(sku storage.SkuName) error {
+	if !geoReplicatedSkuNames[sku] {
+		return errors.Errorf(errFmtFailoverNotGeoReplicated, sku)
+	}
+	return nil
+}
+
+// FailoverInProgress returns true if the supplied failover operation has been
+// started and has not yet completed.
+func FailoverInProgress(op *v1alpha3.AsyncOperation) bool {
+	return op != nil && op.Status == azure.AsyncOperationStatusInProgress
+}
+
+// FailoverCompletedEvent returns an event describing the outcome of the
+// supplied completed failover operation.
+func FailoverCompletedEvent(op v1alpha3.AsyncOperation) event.Event {
+	if op.ErrorMessage != "" || strings.EqualFold(op.Status, "Failed") || strings.EqualFold(op.Status, "Canceled") {
+		err := errors.Errorf("storage account failover did not succeed: status %s", op.Status)
+		if op.ErrorMessage != "" {
+			err = errors.Wrap(errors.New(op.ErrorMessage), err.Error())
+		}
+		return event.Warning(ReasonFailoverFailed, err)
+	}
+	return event.Normal(ReasonFailoverCompleted, "Successfully failed over storage account to its secondary region")
+}
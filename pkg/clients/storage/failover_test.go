@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+)
+
+func TestValidateFailover(t *testing.T) {
+	cases := map[string]struct {
+		sku  storage.SkuName
+		want error
+	}{
+		"GeoRedundant": {
+			sku: storage.StandardGRS,
+		},
+		"ReadAccessGeoZoneRedundant": {
+			sku: storage.SkuName("Standard_RAGZRS"),
+		},
+		"LocallyRedundant": {
+			sku:  storage.StandardLRS,
+			want: errors.Errorf(errFmtFailoverNotGeoReplicated, storage.StandardLRS),
+		},
+		"Unknown": {
+			want: errors.Errorf(errFmtFailoverNotGeoReplicated, ""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateFailover(tc.sku)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateFailover(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFailoverCompletedEvent(t *testing.T) {
+	cases := map[string]struct {
+		op   v1alpha3.AsyncOperation
+		want event.Event
+	}{
+		"Succeeded": {
+			op:   v1alpha3.AsyncOperation{Status: "Succeeded"},
+			want: event.Normal(ReasonFailoverCompleted, "Successfully failed over storage account to its secondary region"),
+		},
+		"Failed": {
+			op:   v1alpha3.AsyncOperation{Status: "Failed", ErrorMessage: "boom"},
+			want: event.Warning(ReasonFailoverFailed, errors.New("storage account failover did not succeed: status Failed: boom")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := FailoverCompletedEvent(tc.op)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FailoverCompletedEvent(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
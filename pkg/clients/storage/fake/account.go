@@ -20,18 +20,29 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
 	azurestorage "github.com/crossplane/provider-azure/pkg/clients/storage"
 )
 
 // MockAccountOperations mock implementation of AccountOperations
 type MockAccountOperations struct {
-	MockCreate                 func(context.Context, storage.AccountCreateParameters) (*storage.Account, error)
-	MockUpdate                 func(context.Context, storage.AccountUpdateParameters) (*storage.Account, error)
-	MockGet                    func(ctx context.Context) (*storage.Account, error)
-	MockDelete                 func(ctx context.Context) error
-	MockIsAccountNameAvailable func(context.Context, string) error
-	MockListKeys               func(context.Context) ([]storage.AccountKey, error)
+	MockCreate                     func(context.Context, storage.AccountCreateParameters) (*storage.Account, error)
+	MockUpdate                     func(context.Context, storage.AccountUpdateParameters) (*storage.Account, error)
+	MockGet                        func(ctx context.Context) (*storage.Account, error)
+	MockDelete                     func(ctx context.Context) error
+	MockIsAccountNameAvailable     func(context.Context, string) error
+	MockListKeys                   func(context.Context) ([]storage.AccountKey, error)
+	MockGetBlobServiceProperties   func(context.Context) (*mgmtstorage.BlobServiceProperties, error)
+	MockSetBlobServiceProperties   func(context.Context, mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error)
+	MockSetRoutingPreference       func(context.Context, mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error)
+	MockUpgradeKind                func(context.Context, storage.Kind) error
+	MockCreateWithImmutableStorage func(context.Context, storage.AccountCreateParameters, azurestorage.ImmutableStorageWithVersioning) (*storage.Account, error)
+	MockGetExtensions              func(context.Context) (*azurestorage.AccountExtensions, error)
+	MockSetAccess                  func(context.Context, azurestorage.AccountAccess) (*azurestorage.AccountAccess, error)
+	MockFailover                   func(context.Context) (*v1alpha3.AsyncOperation, error)
+	MockFetchOperation             func(context.Context, *v1alpha3.AsyncOperation) error
 }
 
 var _ azurestorage.AccountOperations = &MockAccountOperations{}
@@ -57,6 +68,33 @@ func NewMockAccountOperations() *MockAccountOperations {
 		MockListKeys: func(i context.Context) ([]storage.AccountKey, error) {
 			return nil, nil
 		},
+		MockGetBlobServiceProperties: func(i context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+			return &mgmtstorage.BlobServiceProperties{}, nil
+		},
+		MockSetBlobServiceProperties: func(i context.Context, p mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error) {
+			return &p, nil
+		},
+		MockSetRoutingPreference: func(i context.Context, rp mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+			return &mgmtstorage.AccountProperties{RoutingPreference: &rp}, nil
+		},
+		MockUpgradeKind: func(i context.Context, kind storage.Kind) error {
+			return nil
+		},
+		MockCreateWithImmutableStorage: func(i context.Context, parameters storage.AccountCreateParameters, s azurestorage.ImmutableStorageWithVersioning) (*storage.Account, error) {
+			return nil, nil
+		},
+		MockGetExtensions: func(i context.Context) (*azurestorage.AccountExtensions, error) {
+			return &azurestorage.AccountExtensions{}, nil
+		},
+		MockSetAccess: func(i context.Context, a azurestorage.AccountAccess) (*azurestorage.AccountAccess, error) {
+			return &a, nil
+		},
+		MockFailover: func(i context.Context) (*v1alpha3.AsyncOperation, error) {
+			return &v1alpha3.AsyncOperation{}, nil
+		},
+		MockFetchOperation: func(i context.Context, op *v1alpha3.AsyncOperation) error {
+			return nil
+		},
 	}
 }
 
@@ -89,3 +127,48 @@ func (m *MockAccountOperations) IsAccountNameAvailable(ctx context.Context, name
 func (m *MockAccountOperations) ListKeys(ctx context.Context) ([]storage.AccountKey, error) {
 	return m.MockListKeys(ctx)
 }
+
+// GetBlobServiceProperties mock get blob service properties
+func (m *MockAccountOperations) GetBlobServiceProperties(ctx context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+	return m.MockGetBlobServiceProperties(ctx)
+}
+
+// SetBlobServiceProperties mock set blob service properties
+func (m *MockAccountOperations) SetBlobServiceProperties(ctx context.Context, params mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error) {
+	return m.MockSetBlobServiceProperties(ctx, params)
+}
+
+// SetRoutingPreference mock set routing preference
+func (m *MockAccountOperations) SetRoutingPreference(ctx context.Context, rp mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+	return m.MockSetRoutingPreference(ctx, rp)
+}
+
+// UpgradeKind mock upgrade kind
+func (m *MockAccountOperations) UpgradeKind(ctx context.Context, kind storage.Kind) error {
+	return m.MockUpgradeKind(ctx, kind)
+}
+
+// CreateWithImmutableStorage mock create with immutable storage
+func (m *MockAccountOperations) CreateWithImmutableStorage(ctx context.Context, params storage.AccountCreateParameters, s azurestorage.ImmutableStorageWithVersioning) (*storage.Account, error) {
+	return m.MockCreateWithImmutableStorage(ctx, params, s)
+}
+
+// GetExtensions mock get extensions
+func (m *MockAccountOperations) GetExtensions(ctx context.Context) (*azurestorage.AccountExtensions, error) {
+	return m.MockGetExtensions(ctx)
+}
+
+// SetAccess mock set access
+func (m *MockAccountOperations) SetAccess(ctx context.Context, a azurestorage.AccountAccess) (*azurestorage.AccountAccess, error) {
+	return m.MockSetAccess(ctx, a)
+}
+
+// Failover mock failover
+func (m *MockAccountOperations) Failover(ctx context.Context) (*v1alpha3.AsyncOperation, error) {
+	return m.MockFailover(ctx)
+}
+
+// FetchOperation mock fetch operation
+func (m *MockAccountOperations) FetchOperation(ctx context.Context, op *v1alpha3.AsyncOperation) error {
+	return m.MockFetchOperation(ctx, op)
+}
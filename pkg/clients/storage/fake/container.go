@@ -26,10 +26,12 @@ import (
 
 // MockContainerOperations mock implementation of ContainerOperations
 type MockContainerOperations struct {
-	MockCreate func(context.Context, azblob.PublicAccessType, azblob.Metadata) error
-	MockUpdate func(context.Context, azblob.PublicAccessType, azblob.Metadata) error
-	MockGet    func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error)
-	MockDelete func(ctx context.Context) error
+	MockCreate          func(context.Context, azblob.PublicAccessType, azblob.Metadata, []azblob.SignedIdentifier) error
+	MockUpdate          func(context.Context, azblob.PublicAccessType, azblob.Metadata, []azblob.SignedIdentifier) error
+	MockGet             func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error)
+	MockGetAccessPolicy func(ctx context.Context) ([]azblob.SignedIdentifier, error)
+	MockDelete          func(ctx context.Context) error
+	MockIsEmpty         func(ctx context.Context) (bool, error)
 }
 
 var _ azurestorage.ContainerOperations = &MockContainerOperations{}
@@ -37,29 +39,35 @@ var _ azurestorage.ContainerOperations = &MockContainerOperations{}
 // NewMockContainerOperations create new mock instance with default mocks
 func NewMockContainerOperations() *MockContainerOperations {
 	return &MockContainerOperations{
-		MockCreate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
+		MockCreate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
 			return nil
 		},
-		MockUpdate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
+		MockUpdate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
 			return nil
 		},
 		MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
 			return nil, nil, nil
 		},
+		MockGetAccessPolicy: func(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+			return nil, nil
+		},
 		MockDelete: func(ctx context.Context) error {
 			return nil
 		},
+		MockIsEmpty: func(ctx context.Context) (bool, error) {
+			return true, nil
+		},
 	}
 }
 
 // Create mock create function
-func (m *MockContainerOperations) Create(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-	return m.MockCreate(ctx, pat, meta)
+func (m *MockContainerOperations) Create(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
+	return m.MockCreate(ctx, pat, meta, ids)
 }
 
 // Update mock update function
-func (m *MockContainerOperations) Update(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-	return m.MockUpdate(ctx, pat, meta)
+func (m *MockContainerOperations) Update(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
+	return m.MockUpdate(ctx, pat, meta, ids)
 }
 
 // Get mock get function
@@ -67,12 +75,46 @@ func (m *MockContainerOperations) Get(ctx context.Context) (*azblob.PublicAccess
 	return m.MockGet(ctx)
 }
 
+// GetAccessPolicy mock get access policy function
+func (m *MockContainerOperations) GetAccessPolicy(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+	return m.MockGetAccessPolicy(ctx)
+}
+
 // Delete mock delete function
 func (m *MockContainerOperations) Delete(ctx context.Context) error {
 	return m.MockDelete(ctx)
 }
 
+// IsEmpty mock is empty function
+func (m *MockContainerOperations) IsEmpty(ctx context.Context) (bool, error) {
+	return m.MockIsEmpty(ctx)
+}
+
 // PublicAccessTypePtr returns pointer of the PublicAccessType value
 func PublicAccessTypePtr(pab azblob.PublicAccessType) *azblob.PublicAccessType {
 	return &pab
 }
+
+// MockLegalHoldOperations mock implementation of LegalHoldOperations
+type MockLegalHoldOperations struct {
+	MockGetLegalHoldTags func(ctx context.Context) ([]string, error)
+	MockSetLegalHold     func(ctx context.Context, tags []string) error
+	MockClearLegalHold   func(ctx context.Context, tags []string) error
+}
+
+var _ azurestorage.LegalHoldOperations = &MockLegalHoldOperations{}
+
+// GetLegalHoldTags mock get legal hold tags function
+func (m *MockLegalHoldOperations) GetLegalHoldTags(ctx context.Context) ([]string, error) {
+	return m.MockGetLegalHoldTags(ctx)
+}
+
+// SetLegalHold mock set legal hold function
+func (m *MockLegalHoldOperations) SetLegalHold(ctx context.Context, tags []string) error {
+	return m.MockSetLegalHold(ctx, tags)
+}
+
+// ClearLegalHold mock clear legal hold function
+func (m *MockLegalHoldOperations) ClearLegalHold(ctx context.Context, tags []string) error {
+	return m.MockClearLegalHold(ctx, tags)
+}
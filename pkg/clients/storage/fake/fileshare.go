@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage/storageapi"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+var _ storageapi.FileSharesClientAPI = &MockFileSharesClient{}
+
+// MockFileSharesClient is a fake implementation of storage.FileSharesClient.
+type MockFileSharesClient struct {
+	storageapi.FileSharesClientAPI
+
+	MockCreate func(ctx context.Context, resourceGroupName string, accountName string, shareName string, fileShare storage.FileShare) (result storage.FileShare, err error)
+	MockUpdate func(ctx context.Context, resourceGroupName string, accountName string, shareName string, fileShare storage.FileShare) (result storage.FileShare, err error)
+	MockGet    func(ctx context.Context, resourceGroupName string, accountName string, shareName string, expand storage.GetShareExpand) (result storage.FileShare, err error)
+	MockDelete func(ctx context.Context, resourceGroupName string, accountName string, shareName string) (result autorest.Response, err error)
+}
+
+// Create calls the MockFileSharesClient's MockCreate method.
+func (c *MockFileSharesClient) Create(ctx context.Context, resourceGroupName string, accountName string, shareName string, fileShare storage.FileShare) (result storage.FileShare, err error) {
+	return c.MockCreate(ctx, resourceGroupName, accountName, shareName, fileShare)
+}
+
+// Update calls the MockFileSharesClient's MockUpdate method.
+func (c *MockFileSharesClient) Update(ctx context.Context, resourceGroupName string, accountName string, shareName string, fileShare storage.FileShare) (result storage.FileShare, err error) {
+	return c.MockUpdate(ctx, resourceGroupName, accountName, shareName, fileShare)
+}
+
+// Get calls the MockFileSharesClient's MockGet method.
+func (c *MockFileSharesClient) Get(ctx context.Context, resourceGroupName string, accountName string, shareName string, expand storage.GetShareExpand) (result storage.FileShare, err error) {
+	return c.MockGet(ctx, resourceGroupName, accountName, shareName, expand)
+}
+
+// Delete calls the MockFileSharesClient's MockDelete method.
+func (c *MockFileSharesClient) Delete(ctx context.Context, resourceGroupName string, accountName string, shareName string) (result autorest.Response, err error) {
+	return c.MockDelete(ctx, resourceGroupName, accountName, shareName)
+}
+
+var _ storageapi.AccountsClientAPI = &MockAccountsClient{}
+
+// MockAccountsClient is a fake implementation of storage.AccountsClient.
+type MockAccountsClient struct {
+	storageapi.AccountsClientAPI
+
+	MockGetProperties func(ctx context.Context, resourceGroupName string, accountName string, expand storage.AccountExpand) (result storage.Account, err error)
+}
+
+// GetProperties calls the MockAccountsClient's MockGetProperties method.
+func (c *MockAccountsClient) GetProperties(ctx context.Context, resourceGroupName string, accountName string, expand storage.AccountExpand) (result storage.Account, err error) {
+	return c.MockGetProperties(ctx, resourceGroupName, accountName, expand)
+}
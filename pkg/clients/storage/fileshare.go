@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+const (
+	fileShareUNCFormatString = `\\%s\%s`
+	fileShareNFSFormatString = `%s:/%s/%s`
+
+	errFmtParseFileEndpoint = "cannot parse file endpoint %q"
+)
+
+// FileShareEndpoint returns the HTTPS endpoint of the supplied share of an
+// account with the supplied primary file endpoint, for example
+// https://account.file.core.windows.net/.
+func FileShareEndpoint(fileEndpoint, shareName string) string {
+	return strings.TrimSuffix(fileEndpoint, "/") + "/" + shareName
+}
+
+// FileShareUNCPath returns the UNC path used to mount the supplied share of an
+// account with the supplied primary file endpoint via SMB.
+func FileShareUNCPath(fileEndpoint, shareName string) (string, error) {
+	h, err := fileEndpointHost(fileEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(fileShareUNCFormatString, h, shareName), nil
+}
+
+// FileShareNFSMountTarget returns the target used to mount the supplied share
+// of the supplied account, which has the supplied primary file endpoint, via
+// NFS.
+func FileShareNFSMountTarget(fileEndpoint, accountName, shareName string) (string, error) {
+	h, err := fileEndpointHost(fileEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(fileShareNFSFormatString, h, accountName, shareName), nil
+}
+
+// IsNFSFileShare returns true if the supplied Azure FileShare is mounted via
+// NFS rather than SMB, which Azure uses if no protocol is specified.
+func IsNFSFileShare(az storage.FileShare) bool {
+	return az.FileShareProperties != nil && strings.EqualFold(string(az.EnabledProtocols), string(storage.NFS))
+}
+
+// fileEndpointHost returns the host of the supplied file endpoint. The host's
+// DNS suffix differs between the public and sovereign Azure clouds.
+func fileEndpointHost(fileEndpoint string) (string, error) {
+	u, err := url.Parse(fileEndpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtParseFileEndpoint, fileEndpoint)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf(errFmtParseFileEndpoint, fileEndpoint)
+	}
+	return u.Host, nil
+}
+
+// NewFileShareParameters returns an Azure FileShare object from a FileShare
+// spec.
+func NewFileShareParameters(p v1alpha3.FileShareParameters) storage.FileShare {
+	return storage.FileShare{
+		FileShareProperties: &storage.FileShareProperties{
+			Metadata:         azure.ToStringPtrMap(p.Metadata),
+			ShareQuota:       p.ShareQuota,
+			EnabledProtocols: storage.EnabledProtocols(azure.ToString(p.EnabledProtocols)),
+			AccessTier:       storage.ShareAccessTier(azure.ToString(p.AccessTier)),
+		},
+	}
+}
+
+// NewFileShareUpdateParameters returns an Azure FileShare object containing
+// only the mutable fields of a FileShare spec.
+func NewFileShareUpdateParameters(p v1alpha3.FileShareParameters) storage.FileShare {
+	return storage.FileShare{
+		FileShareProperties: &storage.FileShareProperties{
+			Metadata:   azure.ToStringPtrMap(p.Metadata),
+			ShareQuota: p.ShareQuota,
+			AccessTier: storage.ShareAccessTier(azure.ToString(p.AccessTier)),
+		},
+	}
+}
+
+// GenerateFileShareObservation produces a FileShareObservation from the
+// supplied Azure FileShare.
+func GenerateFileShareObservation(az storage.FileShare) v1alpha3.FileShareObservation {
+	o := v1alpha3.FileShareObservation{
+		ID:   azure.ToString(az.ID),
+		Name: azure.ToString(az.Name),
+		Type: azure.ToString(az.Type),
+	}
+	if az.FileShareProperties == nil {
+		return o
+	}
+	o.LastModifiedTime = toMetaTime(az.LastModifiedTime)
+	o.AccessTierChangeTime = toMetaTime(az.AccessTierChangeTime)
+	o.AccessTierStatus = azure.ToString(az.AccessTierStatus)
+	if az.ShareUsageBytes != nil {
+		b := int64(*az.ShareUsageBytes)
+		o.ShareUsageBytes = &b
+	}
+	return o
+}
+
+// LateInitializeFileShare fills the empty fields of the supplied
+// FileShareParameters with the values seen in the Azure FileShare.
+func LateInitializeFileShare(p *v1alpha3.FileShareParameters, az storage.FileShare) {
+	if az.FileShareProperties == nil {
+		return
+	}
+	if p.ShareQuota == nil {
+		p.ShareQuota = az.ShareQuota
+	}
+	if p.AccessTier == nil && az.AccessTier != "" {
+		p.AccessTier = azure.ToStringPtr(string(az.AccessTier))
+	}
+	if p.EnabledProtocols == nil && az.EnabledProtocols != "" {
+		p.EnabledProtocols = azure.ToStringPtr(string(az.EnabledProtocols))
+	}
+	p.Metadata = azure.LateInitializeStringMap(p.Metadata, az.Metadata)
+}
+
+// IsFileShareUpToDate returns true if the mutable fields of the supplied
+// FileShareParameters match the supplied Azure FileShare.
+func IsFileShareUpToDate(p v1alpha3.FileShareParameters, az storage.FileShare) bool {
+	if az.FileShareProperties == nil {
+		return false
+	}
+	switch {
+	case p.ShareQuota != nil && !reflect.DeepEqual(p.ShareQuota, az.ShareQuota):
+		return false
+	case p.AccessTier != nil && *p.AccessTier != string(az.AccessTier):
+		return false
+	case p.Metadata != nil && !reflect.DeepEqual(p.Metadata, azure.ToStringMap(az.Metadata)):
+		return false
+	}
+	return true
+}
+
+func toMetaTime(t *date.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	m := metav1.NewTime(t.Time)
+	return &m
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+func TestLateInitializeFileShare(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.FileShareParameters
+		az   storage.FileShare
+		want v1alpha3.FileShareParameters
+	}{
+		"NoProperties": {
+			p:    v1alpha3.FileShareParameters{AccountName: "acct"},
+			az:   storage.FileShare{},
+			want: v1alpha3.FileShareParameters{AccountName: "acct"},
+		},
+		"FillsEmptyFields": {
+			p: v1alpha3.FileShareParameters{},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				ShareQuota:       azure.ToInt32Ptr(5120),
+				AccessTier:       storage.ShareAccessTierTransactionOptimized,
+				EnabledProtocols: storage.SMB,
+			}},
+			want: v1alpha3.FileShareParameters{
+				ShareQuota:       azure.ToInt32Ptr(5120),
+				AccessTier:       azure.ToStringPtr("TransactionOptimized"),
+				EnabledProtocols: azure.ToStringPtr("SMB"),
+			},
+		},
+		"KeepsSetFields": {
+			p: v1alpha3.FileShareParameters{
+				ShareQuota: azure.ToInt32Ptr(10),
+				AccessTier: azure.ToStringPtr("Cool"),
+			},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				ShareQuota: azure.ToInt32Ptr(5120),
+				AccessTier: storage.ShareAccessTierHot,
+			}},
+			want: v1alpha3.FileShareParameters{
+				ShareQuota: azure.ToInt32Ptr(10),
+				AccessTier: azure.ToStringPtr("Cool"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			LateInitializeFileShare(&tc.p, tc.az)
+			if diff := cmp.Diff(tc.want, tc.p); diff != "" {
+				t.Errorf("LateInitializeFileShare(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsFileShareUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		p    v1alpha3.FileShareParameters
+		az   storage.FileShare
+		want bool
+	}{
+		"UpToDate": {
+			p: v1alpha3.FileShareParameters{
+				ShareQuota: azure.ToInt32Ptr(10),
+				AccessTier: azure.ToStringPtr("Hot"),
+				Metadata:   map[string]string{"k": "v"},
+			},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				ShareQuota: azure.ToInt32Ptr(10),
+				AccessTier: storage.ShareAccessTierHot,
+				Metadata:   map[string]*string{"k": azure.ToStringPtr("v")},
+			}},
+			want: true,
+		},
+		"QuotaChanged": {
+			p: v1alpha3.FileShareParameters{ShareQuota: azure.ToInt32Ptr(20)},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				ShareQuota: azure.ToInt32Ptr(10),
+			}},
+			want: false,
+		},
+		"AccessTierChanged": {
+			p: v1alpha3.FileShareParameters{AccessTier: azure.ToStringPtr("Cool")},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				AccessTier: storage.ShareAccessTierHot,
+			}},
+			want: false,
+		},
+		"MetadataChanged": {
+			p: v1alpha3.FileShareParameters{Metadata: map[string]string{"k": "v2"}},
+			az: storage.FileShare{FileShareProperties: &storage.FileShareProperties{
+				Metadata: map[string]*string{"k": azure.ToStringPtr("v")},
+			}},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsFileShareUpToDate(tc.p, tc.az)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsFileShareUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileShareMountPaths(t *testing.T) {
+	type want struct {
+		endpoint string
+		unc      string
+		nfs      string
+		err      bool
+	}
+
+	cases := map[string]struct {
+		fileEndpoint string
+		want         want
+	}{
+		"Public": {
+			fileEndpoint: "https://acct.file.core.windows.net/",
+			want: want{
+				endpoint: "https://acct.file.core.windows.net/share",
+				unc:      `\\acct.file.core.windows.net\share`,
+				nfs:      "acct.file.core.windows.net:/acct/share",
+			},
+		},
+		"China": {
+			fileEndpoint: "https://acct.file.core.chinacloudapi.cn/",
+			want: want{
+				endpoint: "https://acct.file.core.chinacloudapi.cn/share",
+				unc:      `\\acct.file.core.chinacloudapi.cn\share`,
+				nfs:      "acct.file.core.chinacloudapi.cn:/acct/share",
+			},
+		},
+		"NoHost": {
+			fileEndpoint: "acct",
+			want: want{
+				endpoint: "acct/share",
+				err:      true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want.endpoint, FileShareEndpoint(tc.fileEndpoint, "share")); diff != "" {
+				t.Errorf("FileShareEndpoint(...): -want, +got:\n%s", diff)
+			}
+			unc, err := FileShareUNCPath(tc.fileEndpoint, "share")
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("FileShareUNCPath(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.unc, unc); diff != "" {
+				t.Errorf("FileShareUNCPath(...): -want, +got:\n%s", diff)
+			}
+			nfs, err := FileShareNFSMountTarget(tc.fileEndpoint, "acct", "share")
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("FileShareNFSMountTarget(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.nfs, nfs); diff != "" {
+				t.Errorf("FileShareNFSMountTarget(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
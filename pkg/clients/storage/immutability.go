@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// ImmutableStorageAPIVersion is the API version that must be used to read
+// and write the account-level immutable storage of a storage account.
+const ImmutableStorageAPIVersion = "2021-06-01"
+
+// Error strings.
+const (
+	errImmutableStorageChange = "account-level immutable storage can only be configured when a storage account is created"
+)
+
+// ImmutableStorageWithVersioning is the account-level immutable storage of a
+// storage account. Our Azure SDK predates it, and thus omits it from its
+// models.
+type ImmutableStorageWithVersioning struct {
+	Enabled            *bool                      `json:"enabled,omitempty"`
+	ImmutabilityPolicy *AccountImmutabilityPolicy `json:"immutabilityPolicy,omitempty"`
+}
+
+// AccountImmutabilityPolicy is the default time-based retention policy of a
+// storage account with immutable storage.
+type AccountImmutabilityPolicy struct {
+	ImmutabilityPeriodSinceCreationInDays *int32  `json:"immutabilityPeriodSinceCreationInDays,omitempty"`
+	State                                 *string `json:"state,omitempty"`
+	AllowProtectedAppendWrites            *bool   `json:"allowProtectedAppendWrites,omitempty"`
+}
+
+// NewImmutableStorageWithVersioning returns the Azure account-level immutable
+// storage that corresponds to the supplied desired immutable storage.
+func NewImmutableStorageWithVersioning(p *v1alpha3.ImmutableStorageWithVersioning) ImmutableStorageWithVersioning {
+	s := ImmutableStorageWithVersioning{Enabled: to.BoolPtr(p.Enabled)}
+	if ip := p.ImmutabilityPolicy; ip != nil {
+		s.ImmutabilityPolicy = &AccountImmutabilityPolicy{
+			ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(ip.ImmutabilityPeriodSinceCreationInDays),
+			AllowProtectedAppendWrites:            to.BoolPtr(ip.AllowProtectedAppendWrites),
+		}
+		if ip.State != "" {
+			s.ImmutabilityPolicy.State = to.StringPtr(ip.State)
+		}
+	}
+	return s
+}
+
+// ValidateImmutableStorageChange returns an error if the supplied observed
+// account-level immutable storage does not match the supplied desired
+// immutable storage. Azure only allows immutable storage to be configured
+// when a storage account is created, so we refuse to attempt to change it.
+// The policy state is ignored if none is desired.
+func ValidateImmutableStorageChange(desired *v1alpha3.ImmutableStorageWithVersioning, observed *ImmutableStorageWithVersioning) error {
+	if desired == nil {
+		return nil
+	}
+	if observed == nil {
+		observed = &ImmutableStorageWithVersioning{}
+	}
+	if desired.Enabled != to.Bool(observed.Enabled) {
+		return errors.New(errImmutableStorageChange)
+	}
+	d, o := desired.ImmutabilityPolicy, observed.ImmutabilityPolicy
+	if d == nil {
+		return nil
+	}
+	if o == nil {
+		o = &AccountImmutabilityPolicy{}
+	}
+	switch {
+	case d.ImmutabilityPeriodSinceCreationInDays != to.Int32(o.ImmutabilityPeriodSinceCreationInDays),
+		d.AllowProtectedAppendWrites != to.Bool(o.AllowProtectedAppendWrites),
+		d.State != "" && d.State != to.String(o.State):
+		return errors.New(errImmutableStorageChange)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+func TestNewImmutableStorageWithVersioning(t *testing.T) {
+	cases := map[string]struct {
+		p    *v1alpha3.ImmutableStorageWithVersioning
+		want ImmutableStorageWithVersioning
+	}{
+		"NoPolicy": {
+			p:    &v1alpha3.ImmutableStorageWithVersioning{Enabled: true},
+			want: ImmutableStorageWithVersioning{Enabled: to.BoolPtr(true)},
+		},
+		"Policy": {
+			p: &v1alpha3.ImmutableStorageWithVersioning{
+				Enabled: true,
+				ImmutabilityPolicy: &v1alpha3.AccountImmutabilityPolicy{
+					ImmutabilityPeriodSinceCreationInDays: 7,
+					State:                                 "Unlocked",
+				},
+			},
+			want: ImmutableStorageWithVersioning{
+				Enabled: to.BoolPtr(true),
+				ImmutabilityPolicy: &AccountImmutabilityPolicy{
+					ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+					State:                                 to.StringPtr("Unlocked"),
+					AllowProtectedAppendWrites:            to.BoolPtr(false),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewImmutableStorageWithVersioning(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewImmutableStorageWithVersioning(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateImmutableStorageChange(t *testing.T) {
+	policy := &AccountImmutabilityPolicy{
+		ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+		State:                                 to.StringPtr("Unlocked"),
+		AllowProtectedAppendWrites:            to.BoolPtr(false),
+	}
+
+	cases := map[string]struct {
+		desired  *v1alpha3.ImmutableStorageWithVersioning
+		observed *ImmutableStorageWithVersioning
+		want     error
+	}{
+		"NotDesired": {
+			observed: &ImmutableStorageWithVersioning{Enabled: to.BoolPtr(true)},
+		},
+		"Unchanged": {
+			desired: &v1alpha3.ImmutableStorageWithVersioning{
+				Enabled:            true,
+				ImmutabilityPolicy: &v1alpha3.AccountImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7},
+			},
+			observed: &ImmutableStorageWithVersioning{Enabled: to.BoolPtr(true), ImmutabilityPolicy: policy},
+		},
+		"EnabledChanged": {
+			desired:  &v1alpha3.ImmutableStorageWithVersioning{Enabled: true},
+			observed: &ImmutableStorageWithVersioning{},
+			want:     errors.New(errImmutableStorageChange),
+		},
+		"PeriodChanged": {
+			desired: &v1alpha3.ImmutableStorageWithVersioning{
+				Enabled:            true,
+				ImmutabilityPolicy: &v1alpha3.AccountImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 30},
+			},
+			observed: &ImmutableStorageWithVersioning{Enabled: to.BoolPtr(true), ImmutabilityPolicy: policy},
+			want:     errors.New(errImmutableStorageChange),
+		},
+		"StateChanged": {
+			desired: &v1alpha3.ImmutableStorageWithVersioning{
+				Enabled:            true,
+				ImmutabilityPolicy: &v1alpha3.AccountImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7, State: "Locked"},
+			},
+			observed: &ImmutableStorageWithVersioning{Enabled: to.BoolPtr(true), ImmutabilityPolicy: policy},
+			want:     errors.New(errImmutableStorageChange),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateImmutableStorageChange(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateImmutableStorageChange(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// LegalHoldOperations manages the legal hold of a blob container.
+type LegalHoldOperations interface {
+	GetLegalHoldTags(ctx context.Context) ([]string, error)
+	SetLegalHold(ctx context.Context, tags []string) error
+	ClearLegalHold(ctx context.Context, tags []string) error
+}
+
+// LegalHoldHandle implements LegalHoldOperations using the Azure Resource
+// Manager blob containers API. Legal holds cannot be managed using the blob
+// data plane API that the ContainerHandle uses.
+type LegalHoldHandle struct {
+	client        mgmtstorage.BlobContainersClient
+	groupName     string
+	accountName   string
+	containerName string
+}
+
+var _ LegalHoldOperations = &LegalHoldHandle{}
+
+// NewLegalHoldHandle returns a LegalHoldHandle for the supplied container.
+func NewLegalHoldHandle(client mgmtstorage.BlobContainersClient, groupName, accountName, containerName string) *LegalHoldHandle {
+	return &LegalHoldHandle{
+		client:        client,
+		groupName:     groupName,
+		accountName:   accountName,
+		containerName: containerName,
+	}
+}
+
+// GetLegalHoldTags returns the container's legal hold tags.
+func (h *LegalHoldHandle) GetLegalHoldTags(ctx context.Context) ([]string, error) {
+	c, err := h.client.Get(ctx, h.groupName, h.accountName, h.containerName)
+	if err != nil {
+		return nil, err
+	}
+	if c.ContainerProperties == nil || c.LegalHold == nil || c.LegalHold.Tags == nil {
+		return nil, nil
+	}
+	tags := make([]string, 0, len(*c.LegalHold.Tags))
+	for _, t := range *c.LegalHold.Tags {
+		tags = append(tags, to.String(t.Tag))
+	}
+	return tags, nil
+}
+
+// SetLegalHold adds the supplied tags to the container's legal hold.
+func (h *LegalHoldHandle) SetLegalHold(ctx context.Context, tags []string) error {
+	_, err := h.client.SetLegalHold(ctx, h.groupName, h.accountName, h.containerName, mgmtstorage.LegalHold{Tags: &tags})
+	return err
+}
+
+// ClearLegalHold removes the supplied tags from the container's legal hold.
+func (h *LegalHoldHandle) ClearLegalHold(ctx context.Context, tags []string) error {
+	_, err := h.client.ClearLegalHold(ctx, h.groupName, h.accountName, h.containerName, mgmtstorage.LegalHold{Tags: &tags})
+	return err
+}
+
+// LegalHoldTagsToUpdate returns the legal hold tags that must be set and
+// cleared in order for the supplied observed tags to match the supplied
+// desired tags. Azure normalizes tags to lower case, so tags are compared
+// case-insensitively.
+func LegalHoldTagsToUpdate(desired, observed []string) (add, remove []string) {
+	want := make(map[string]bool, len(desired))
+	for _, t := range desired {
+		want[strings.ToLower(t)] = true
+	}
+	got := make(map[string]bool, len(observed))
+	for _, t := range observed {
+		got[strings.ToLower(t)] = true
+	}
+	for t := range want {
+		if !got[t] {
+			add = append(add, t)
+		}
+	}
+	for t := range got {
+		if !want[t] {
+			remove = append(remove, t)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+// NormalizeLegalHoldTags returns the supplied legal hold tags as Azure would
+// report them; lower case and without duplicates. It returns nil if no tags
+// are supplied.
+func NormalizeLegalHoldTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
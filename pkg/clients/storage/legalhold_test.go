@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLegalHoldTagsToUpdate(t *testing.T) {
+	type want struct {
+		add    []string
+		remove []string
+	}
+
+	cases := map[string]struct {
+		desired  []string
+		observed []string
+		want     want
+	}{
+		"UpToDate": {
+			desired:  []string{"Litigation", "audit"},
+			observed: []string{"audit", "litigation"},
+			want:     want{},
+		},
+		"SetAndClear": {
+			desired:  []string{"litigation", "audit"},
+			observed: []string{"audit", "investigation"},
+			want: want{
+				add:    []string{"litigation"},
+				remove: []string{"investigation"},
+			},
+		},
+		"ClearAll": {
+			observed: []string{"investigation", "audit"},
+			want: want{
+				remove: []string{"audit", "investigation"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			add, remove := LegalHoldTagsToUpdate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want.add, add); diff != "" {
+				t.Errorf("LegalHoldTagsToUpdate(...) add: -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.remove, remove); diff != "" {
+				t.Errorf("LegalHoldTagsToUpdate(...) remove: -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeLegalHoldTags(t *testing.T) {
+	cases := map[string]struct {
+		tags []string
+		want []string
+	}{
+		"Empty": {
+			tags: []string{},
+			want: nil,
+		},
+		"Normalized": {
+			tags: []string{"Litigation", "audit", "litigation"},
+			want: []string{"audit", "litigation"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NormalizeLegalHoldTags(tc.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NormalizeLegalHoldTags(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
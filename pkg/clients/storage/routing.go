@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+// Connection secret keys.
+const (
+	ConnectionSecretMicrosoftEndpointKey = "microsoftEndpoint"
+	ConnectionSecretInternetEndpointKey  = "internetEndpoint"
+)
+
+// NewRoutingPreference returns the Azure routing preference that corresponds
+// to the supplied desired routing preference.
+func NewRoutingPreference(p *v1alpha3.RoutingPreference) mgmtstorage.RoutingPreference {
+	return mgmtstorage.RoutingPreference{
+		RoutingChoice:             mgmtstorage.RoutingChoice(p.RoutingChoice),
+		PublishMicrosoftEndpoints: to.BoolPtr(p.PublishMicrosoftEndpoints),
+		PublishInternetEndpoints:  to.BoolPtr(p.PublishInternetEndpoints),
+	}
+}
+
+// IsRoutingPreferenceUpToDate returns true if the supplied observed routing
+// preference matches the supplied desired routing preference. The routing
+// choice is ignored if none is desired.
+func IsRoutingPreferenceUpToDate(desired *v1alpha3.RoutingPreference, observed *mgmtstorage.RoutingPreference) bool {
+	if observed == nil {
+		observed = &mgmtstorage.RoutingPreference{}
+	}
+	if desired.RoutingChoice != "" && desired.RoutingChoice != string(observed.RoutingChoice) {
+		return false
+	}
+	return desired.PublishMicrosoftEndpoints == to.Bool(observed.PublishMicrosoftEndpoints) &&
+		desired.PublishInternetEndpoints == to.Bool(observed.PublishInternetEndpoints)
+}
+
+// NewMicrosoftEndpoints returns the published Microsoft routing endpoints of
+// the supplied account properties, if any.
+func NewMicrosoftEndpoints(p mgmtstorage.AccountProperties) *v1alpha3.RoutingEndpoints {
+	e := p.PrimaryEndpoints
+	if e == nil || e.MicrosoftEndpoints == nil {
+		return nil
+	}
+	return &v1alpha3.RoutingEndpoints{
+		Blob:  to.String(e.MicrosoftEndpoints.Blob),
+		Queue: to.String(e.MicrosoftEndpoints.Queue),
+		Table: to.String(e.MicrosoftEndpoints.Table),
+		File:  to.String(e.MicrosoftEndpoints.File),
+		Web:   to.String(e.MicrosoftEndpoints.Web),
+		Dfs:   to.String(e.MicrosoftEndpoints.Dfs),
+	}
+}
+
+// NewInternetEndpoints returns the published internet routing endpoints of
+// the supplied account properties, if any.
+func NewInternetEndpoints(p mgmtstorage.AccountProperties) *v1alpha3.RoutingEndpoints {
+	e := p.PrimaryEndpoints
+	if e == nil || e.InternetEndpoints == nil {
+		return nil
+	}
+	return &v1alpha3.RoutingEndpoints{
+		Blob: to.String(e.InternetEndpoints.Blob),
+		File: to.String(e.InternetEndpoints.File),
+		Web:  to.String(e.InternetEndpoints.Web),
+		Dfs:  to.String(e.InternetEndpoints.Dfs),
+	}
+}
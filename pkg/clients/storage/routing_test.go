@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+)
+
+func TestIsRoutingPreferenceUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		desired  *v1alpha3.RoutingPreference
+		observed *mgmtstorage.RoutingPreference
+		want     bool
+	}{
+		"NeverObserved": {
+			desired: &v1alpha3.RoutingPreference{},
+			want:    true,
+		},
+		"UpToDate": {
+			desired: &v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.InternetRouting), PublishInternetEndpoints: true},
+			observed: &mgmtstorage.RoutingPreference{
+				RoutingChoice:             mgmtstorage.InternetRouting,
+				PublishMicrosoftEndpoints: to.BoolPtr(false),
+				PublishInternetEndpoints:  to.BoolPtr(true),
+			},
+			want: true,
+		},
+		"ChoiceUnmanaged": {
+			desired:  &v1alpha3.RoutingPreference{},
+			observed: &mgmtstorage.RoutingPreference{RoutingChoice: mgmtstorage.InternetRouting},
+			want:     true,
+		},
+		"ChoiceChanged": {
+			desired:  &v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.MicrosoftRouting)},
+			observed: &mgmtstorage.RoutingPreference{RoutingChoice: mgmtstorage.InternetRouting},
+			want:     false,
+		},
+		"PublishChanged": {
+			desired:  &v1alpha3.RoutingPreference{PublishMicrosoftEndpoints: true},
+			observed: &mgmtstorage.RoutingPreference{PublishMicrosoftEndpoints: to.BoolPtr(false)},
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsRoutingPreferenceUpToDate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsRoutingPreferenceUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewRoutingEndpoints(t *testing.T) {
+	type want struct {
+		microsoft *v1alpha3.RoutingEndpoints
+		internet  *v1alpha3.RoutingEndpoints
+	}
+
+	cases := map[string]struct {
+		p    mgmtstorage.AccountProperties
+		want want
+	}{
+		"NoEndpoints": {
+			p: mgmtstorage.AccountProperties{},
+		},
+		"Published": {
+			p: mgmtstorage.AccountProperties{
+				PrimaryEndpoints: &mgmtstorage.Endpoints{
+					MicrosoftEndpoints: &mgmtstorage.AccountMicrosoftEndpoints{
+						Blob:  to.StringPtr("https://coolaccount-microsoftrouting.blob.core.windows.net/"),
+						Queue: to.StringPtr("https://coolaccount-microsoftrouting.queue.core.windows.net/"),
+					},
+					InternetEndpoints: &mgmtstorage.AccountInternetEndpoints{
+						Blob: to.StringPtr("https://coolaccount-internetrouting.blob.core.windows.net/"),
+					},
+				},
+			},
+			want: want{
+				microsoft: &v1alpha3.RoutingEndpoints{
+					Blob:  "https://coolaccount-microsoftrouting.blob.core.windows.net/",
+					Queue: "https://coolaccount-microsoftrouting.queue.core.windows.net/",
+				},
+				internet: &v1alpha3.RoutingEndpoints{
+					Blob: "https://coolaccount-internetrouting.blob.core.windows.net/",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want.microsoft, NewMicrosoftEndpoints(tc.p)); diff != "" {
+				t.Errorf("NewMicrosoftEndpoints(...): -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.internet, NewInternetEndpoints(tc.p)); diff != "" {
+				t.Errorf("NewInternetEndpoints(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
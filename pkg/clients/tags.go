@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+)
+
+// DefaultIgnoredTagPrefixes are the prefixes of tag keys that Azure and its
+// policy add-ons are known to inject into resources.
+var DefaultIgnoredTagPrefixes = []string{
+	"hidden-",
+	"aks-managed-",
+	"k8s-azure-",
+	"ms-resource-usage",
+}
+
+// IgnoredTags are the prefixes of tag keys that are ignored when determining
+// whether the tags of an external resource have drifted from those of its
+// managed resource. Ownership tags are always ignored.
+type IgnoredTags []string
+
+// Ignored returns true if the supplied tag key has one of the ignored
+// prefixes, or is an ownership tag.
+func (i IgnoredTags) Ignored(key string) bool {
+	if IsOwnershipTag(key) {
+		return true
+	}
+	for _, p := range i {
+		if strings.HasPrefix(strings.ToLower(key), strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns a copy of the supplied tags without any ignored tags. It
+// returns nil if no tags remain.
+func (i IgnoredTags) Filter(tags map[string]string) map[string]string {
+	var out map[string]string
+	for k, v := range tags {
+		if i.Ignored(k) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(tags))
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// NeedUpdate returns true if the supplied desired tags differ from the
+// supplied observed tags, disregarding any ignored tags.
+func (i IgnoredTags) NeedUpdate(desired, observed map[string]*string) bool {
+	want := i.Filter(ToStringMap(desired))
+	got := i.Filter(ToStringMap(observed))
+	if len(want) != len(got) {
+		return true
+	}
+	for k, v := range want {
+		if ov, ok := got[k]; !ok || ov != v {
+			return true
+		}
+	}
+	return false
+}
+
+// Preserve returns the supplied desired tags, plus any ignored tags found in
+// the supplied observed tags. It allows an external resource to be updated
+// without removing tags that were injected by Azure.
+func (i IgnoredTags) Preserve(desired, observed map[string]*string) map[string]*string {
+	for k, v := range observed {
+		if !i.Ignored(k) {
+			continue
+		}
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if desired == nil {
+			desired = make(map[string]*string)
+		}
+		desired[k] = v
+	}
+	return desired
+}
+
+// FoldTagKeys returns a copy of the supplied tags with lower case keys, for
+// comparing tags without regard to the casing of their keys.
+func FoldTagKeys(tags map[string]string) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for k, v := range tags {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// PreserveTagKeyCase returns a copy of the supplied desired tags in which any
+// key that matches the key of an observed tag case-insensitively uses the
+// casing of the observed key. It allows an external resource's tags to be
+// updated without changing the casing of keys that were normalized in Azure.
+func PreserveTagKeyCase(desired, observed map[string]string) map[string]string {
+	if desired == nil {
+		return nil
+	}
+	keys := make(map[string]string, len(observed))
+	for k := range observed {
+		keys[strings.ToLower(k)] = k
+	}
+	out := make(map[string]string, len(desired))
+	for k, v := range desired {
+		if ok, exists := keys[strings.ToLower(k)]; exists {
+			k = ok
+		}
+		out[k] = v
+	}
+	return out
+}
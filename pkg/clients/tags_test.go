@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIgnoredTagsNeedUpdate(t *testing.T) {
+	cases := map[string]struct {
+		ignored  IgnoredTags
+		desired  map[string]*string
+		observed map[string]*string
+		want     bool
+	}{
+		"BothNil": {
+			want: false,
+		},
+		"Equal": {
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"foo": ToStringPtr("bar")},
+			want:     false,
+		},
+		"IgnoredTagObserved": {
+			ignored:  DefaultIgnoredTagPrefixes,
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"foo": ToStringPtr("bar"), "hidden-link:/cool": ToStringPtr("Resource")},
+			want:     false,
+		},
+		"IgnoredTagOnlyObserved": {
+			ignored:  DefaultIgnoredTagPrefixes,
+			observed: map[string]*string{"Hidden-Title": ToStringPtr("cool")},
+			want:     false,
+		},
+		"ValueDiffers": {
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"foo": ToStringPtr("baz")},
+			want:     true,
+		},
+		"NoIgnoredPrefixes": {
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"foo": ToStringPtr("bar"), "hidden-link:/cool": ToStringPtr("Resource")},
+			want:     true,
+		},
+		"TagMissing": {
+			ignored:  DefaultIgnoredTagPrefixes,
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"hidden-link:/cool": ToStringPtr("Resource")},
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.ignored.NeedUpdate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NeedUpdate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoredTagsPreserve(t *testing.T) {
+	cases := map[string]struct {
+		ignored  IgnoredTags
+		desired  map[string]*string
+		observed map[string]*string
+		want     map[string]*string
+	}{
+		"NoIgnoredTags": {
+			desired:  map[string]*string{"foo": ToStringPtr("bar")},
+			observed: map[string]*string{"foo": ToStringPtr("baz")},
+			want:     map[string]*string{"foo": ToStringPtr("bar")},
+		},
+		"NilDesired": {
+			ignored:  DefaultIgnoredTagPrefixes,
+			observed: map[string]*string{"foo": ToStringPtr("baz"), "hidden-link:/cool": ToStringPtr("Resource")},
+			want:     map[string]*string{"hidden-link:/cool": ToStringPtr("Resource")},
+		},
+		"DesiredWins": {
+			ignored:  DefaultIgnoredTagPrefixes,
+			desired:  map[string]*string{"hidden-title": ToStringPtr("mine")},
+			observed: map[string]*string{"hidden-title": ToStringPtr("theirs")},
+			want:     map[string]*string{"hidden-title": ToStringPtr("mine")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.ignored.Preserve(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Preserve(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFoldTagKeys(t *testing.T) {
+	cases := map[string]struct {
+		tags map[string]string
+		want map[string]string
+	}{
+		"Nil": {},
+		"MixedCase": {
+			tags: map[string]string{"CostCenter": "Cool", "env": "prod"},
+			want: map[string]string{"costcenter": "Cool", "env": "prod"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := FoldTagKeys(tc.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FoldTagKeys(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreserveTagKeyCase(t *testing.T) {
+	cases := map[string]struct {
+		desired  map[string]string
+		observed map[string]string
+		want     map[string]string
+	}{
+		"NilDesired": {
+			observed: map[string]string{"CostCenter": "cool"},
+		},
+		"ObservedCasingWins": {
+			desired:  map[string]string{"costcenter": "cool", "env": "prod"},
+			observed: map[string]string{"CostCenter": "old"},
+			want:     map[string]string{"CostCenter": "cool", "env": "prod"},
+		},
+		"NewTag": {
+			desired:  map[string]string{"Owner": "negz"},
+			observed: map[string]string{"CostCenter": "cool"},
+			want:     map[string]string{"Owner": "negz"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PreserveTagKeyCase(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PreserveTagKeyCase(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
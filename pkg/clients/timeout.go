@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtOperationTimeout = "Azure operation did not complete within %s"
+
+// GetOperationTimeout returns the operation timeout that the ProviderConfig (or
+// Provider) of the supplied managed resource configures. It returns zero if no
+// timeout is configured.
+func GetOperationTimeout(ctx context.Context, c client.Client, mg resource.Managed) (time.Duration, error) {
+	cfg, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case cfg.pc != nil && cfg.pc.Spec.OperationTimeout != nil:
+		return cfg.pc.Spec.OperationTimeout.Duration, nil
+	case cfg.p != nil && cfg.p.Spec.OperationTimeout != nil:
+		return cfg.p.Spec.OperationTimeout.Duration, nil
+	}
+	return 0, nil
+}
+
+// NewTimeoutConnecter returns a managed.ExternalConnecter that bounds each
+// operation of the supplied connecter's clients by the operation timeout of
+// the managed resource's ProviderConfig (or Provider), so that a hung Azure
+// API call fails and is retried rather than blocking a worker. Operations are
+// also bounded by the managed reconciler's own timeout. The ProviderConfig is
+// passed to the supplied connecter so that it need not read it again.
+func NewTimeoutConnecter(kube client.Client, c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &timeoutConnecter{ExternalConnecter: c, kube: kube}
+}
+
+type timeoutConnecter struct {
+	managed.ExternalConnecter
+	kube client.Client
+}
+
+func (c *timeoutConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ctx, err := WithProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	t, err := GetOperationTimeout(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil || t <= 0 {
+		return e, err
+	}
+	return &timeoutExternal{ExternalClient: e, timeout: t}, nil
+}
+
+type timeoutExternal struct {
+	managed.ExternalClient
+	timeout time.Duration
+}
+
+// withTimeout annotates the supplied error if the supplied context's deadline
+// was exceeded.
+func (e *timeoutExternal) withTimeout(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return errors.Wrapf(err, errFmtOperationTimeout, e.timeout)
+	}
+	return err
+}
+
+func (e *timeoutExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, e.withTimeout(ctx, err)
+}
+
+func (e *timeoutExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, e.withTimeout(ctx, err)
+}
+
+func (e *timeoutExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, e.withTimeout(ctx, err)
+}
+
+func (e *timeoutExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	return e.withTimeout(ctx, e.ExternalClient.Delete(ctx, mg))
+}
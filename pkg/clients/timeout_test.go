@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+func TestGetOperationTimeout(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		timeout time.Duration
+		err     error
+	}
+
+	cases := map[string]struct {
+		c    client.Client
+		mg   resource.Managed
+		want want
+	}{
+		"ProviderConfig": {
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*v1beta1.ProviderConfig) = v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{
+					OperationTimeout: &metav1.Duration{Duration: 30 * time.Second},
+				}}
+				return nil
+			}},
+			mg:   &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{timeout: 30 * time.Second},
+		},
+		"Provider": {
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*v1alpha3.Provider) = v1alpha3.Provider{Spec: v1alpha3.ProviderSpec{
+					OperationTimeout: &metav1.Duration{Duration: time.Minute},
+				}}
+				return nil
+			}},
+			mg:   &fake.Managed{ProviderReferencer: fake.ProviderReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{timeout: time.Minute},
+		},
+		"NotConfigured": {
+			c:  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			mg: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+		},
+		"GetProviderError": {
+			c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:   &fake.Managed{ProviderReferencer: fake.ProviderReferencer{Ref: &runtimev1alpha1.Reference{Name: "default"}}},
+			want: want{err: errors.Wrap(errBoom, errGetProvider)},
+		},
+		"NoReference": {
+			c:    &test.MockClient{},
+			mg:   &fake.Managed{},
+			want: want{err: errors.New(errNeitherPCNorPGiven)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetOperationTimeout(context.Background(), tc.c, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("GetOperationTimeout(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.timeout, got); diff != "" {
+				t.Errorf("GetOperationTimeout(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTimeoutExternal(t *testing.T) {
+	errBoom := errors.New("boom")
+	timeout := 10 * time.Millisecond
+
+	cases := map[string]struct {
+		observe func(context.Context, resource.Managed) (managed.ExternalObservation, error)
+		want    error
+	}{
+		"Success": {
+			observe: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{}, nil
+			},
+		},
+		"Error": {
+			observe: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{}, errBoom
+			},
+			want: errBoom,
+		},
+		"TimedOut": {
+			observe: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				<-ctx.Done()
+				return managed.ExternalObservation{}, ctx.Err()
+			},
+			want: errors.Wrapf(context.DeadlineExceeded, errFmtOperationTimeout, timeout),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &timeoutExternal{ExternalClient: &managed.ExternalClientFns{ObserveFn: tc.observe}, timeout: timeout}
+			_, err := e.Observe(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateIfChanged updates the supplied current object if it differs from the
+// supplied original, for example because late initialization filled in its
+// spec. Callers should take the original copy before changing the object, and
+// should not change its status until after calling UpdateIfChanged; status is
+// written via the status subresource. Skipping no-op updates avoids needless
+// writes to the API server, and the conflicts they provoke.
+func UpdateIfChanged(ctx context.Context, kube client.Client, original, current runtime.Object) error {
+	if reflect.DeepEqual(original, current) {
+		return nil
+	}
+	return kube.Update(ctx, current)
+}
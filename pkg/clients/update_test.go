@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestUpdateIfChanged(t *testing.T) {
+	errBoom := errors.New("boom")
+	original := &corev1.Secret{Data: map[string][]byte{"key": []byte("value")}}
+
+	type want struct {
+		updated bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		current runtime.Object
+		err     error
+		want    want
+	}{
+		"Unchanged": {
+			current: original.DeepCopy(),
+			err:     errBoom,
+			want:    want{updated: false},
+		},
+		"Changed": {
+			current: &corev1.Secret{Data: map[string][]byte{"key": []byte("other")}},
+			want:    want{updated: true},
+		},
+		"UpdateFailed": {
+			current: &corev1.Secret{Data: map[string][]byte{"key": []byte("other")}},
+			err:     errBoom,
+			want:    want{updated: true, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{MockUpdate: func(_ context.Context, _ runtime.Object, _ ...client.UpdateOption) error {
+				updated = true
+				return tc.err
+			}}
+			err := UpdateIfChanged(context.Background(), kube, original, tc.current)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UpdateIfChanged(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("UpdateIfChanged(...): -want updated, +got updated:\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,47 +17,147 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/database"
 	"github.com/crossplane/provider-azure/pkg/controller/cache"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
 	"github.com/crossplane/provider-azure/pkg/controller/compute"
+	"github.com/crossplane/provider-azure/pkg/controller/concurrency"
 	"github.com/crossplane/provider-azure/pkg/controller/config"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 	"github.com/crossplane/provider-azure/pkg/controller/database/cosmosdb"
 	"github.com/crossplane/provider-azure/pkg/controller/database/mysqlserver"
 	"github.com/crossplane/provider-azure/pkg/controller/database/mysqlserverfirewallrule"
 	"github.com/crossplane/provider-azure/pkg/controller/database/mysqlservervirtualnetworkrule"
 	"github.com/crossplane/provider-azure/pkg/controller/database/postgresqlserver"
+	"github.com/crossplane/provider-azure/pkg/controller/database/postgresqlserverconfiguration"
 	"github.com/crossplane/provider-azure/pkg/controller/database/postgresqlserverfirewallrule"
 	"github.com/crossplane/provider-azure/pkg/controller/database/postgresqlservervirtualnetworkrule"
+	"github.com/crossplane/provider-azure/pkg/controller/drain"
+	"github.com/crossplane/provider-azure/pkg/controller/insights/diagnosticsetting"
+	"github.com/crossplane/provider-azure/pkg/controller/network/natgateway"
+	"github.com/crossplane/provider-azure/pkg/controller/network/serviceendpointpolicy"
 	"github.com/crossplane/provider-azure/pkg/controller/network/subnet"
 	"github.com/crossplane/provider-azure/pkg/controller/network/virtualnetwork"
 	"github.com/crossplane/provider-azure/pkg/controller/resourcegroup"
 	"github.com/crossplane/provider-azure/pkg/controller/storage/account"
 	"github.com/crossplane/provider-azure/pkg/controller/storage/container"
+	"github.com/crossplane/provider-azure/pkg/controller/storage/fileshare"
 )
 
+// Options configure Azure controllers.
+type Options struct {
+	// SQLServerCABundle is published to the connection secrets of MySQL and
+	// PostgreSQL servers.
+	SQLServerCABundle database.CABundle
+
+	// IgnoredTags are ignored when determining whether the tags of an
+	// external resource have drifted from those of its managed resource.
+	IgnoredTags azure.IgnoredTags
+
+	// VirtualNetworkRuleListTTL is how long the virtual network rules of a
+	// SQL server are cached after they are listed. Caching is disabled if it
+	// is not positive.
+	VirtualNetworkRuleListTTL time.Duration
+
+	// RequeueJitter is the maximum fraction of a controller's requeue
+	// interval that is added to it as jitter. Jitter is disabled if it is
+	// not positive.
+	RequeueJitter float64
+
+	// MaxConcurrentReconciles is the maximum number of reconciles each
+	// controller may run concurrently, unless overridden by
+	// MaxConcurrentReconcilesFor, which maps the names of controllers to
+	// their maximum.
+	MaxConcurrentReconciles    int
+	MaxConcurrentReconcilesFor map[string]int
+
+	// StuckThreshold is the number of consecutive failed reconciles after
+	// which a managed resource is reported as stuck. Stuck resources are not
+	// reported if it is not positive.
+	StuckThreshold int
+
+	// OperationPollInterval is the interval after which a long-running SQL
+	// server operation is first polled, and OperationPollMaxInterval the
+	// longest interval it is doubled to. Polling is disabled if either is not
+	// positive.
+	OperationPollInterval    time.Duration
+	OperationPollMaxInterval time.Duration
+
+	// DeleteTimeout is the time after which a managed resource that is still
+	// being deleted is reported with a DeleteTimeout condition. Deletes never
+	// time out if it is not positive.
+	DeleteTimeout time.Duration
+
+	// Drain tracks the in-flight reconciles of all controllers, so that they
+	// may finish before the provider stops. It must not be nil.
+	Drain *drain.Tracker
+
+	// ObserveOnly controllers only observe external resources. They never
+	// create, update, or delete them.
+	ObserveOnly bool
+}
+
 // Setup Azure controllers.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		config.Setup,
-		cache.SetupRedis,
-		compute.SetupAKSCluster,
-		mysqlserver.Setup,
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
+	co := chain.Options{
+		Concurrency:     concurrency.Limits{Max: o.MaxConcurrentReconciles, Overrides: o.MaxConcurrentReconcilesFor},
+		RequeueJitter:   o.RequeueJitter,
+		StuckThreshold:  o.StuckThreshold,
+		PollInterval:    o.OperationPollInterval,
+		PollMaxInterval: o.OperationPollMaxInterval,
+		DeleteTimeout:   o.DeleteTimeout,
+		Drain:           o.Drain,
+	}
+
+	if err := credentials.Setup(mgr, l); err != nil {
+		return err
+	}
+	if err := config.Setup(mgr, l, co); err != nil {
+		return err
+	}
+	for _, setup := range []func(ctrl.Manager, logging.Logger, chain.Options, bool) error{
+		func(mgr ctrl.Manager, l logging.Logger, co chain.Options, observeOnly bool) error {
+			return mysqlserver.Setup(mgr, l, co, observeOnly, o.SQLServerCABundle, o.IgnoredTags)
+		},
 		mysqlserverfirewallrule.Setup,
-		mysqlservervirtualnetworkrule.Setup,
-		postgresqlserver.Setup,
+		func(mgr ctrl.Manager, l logging.Logger, co chain.Options, observeOnly bool) error {
+			return mysqlservervirtualnetworkrule.Setup(mgr, l, co, observeOnly, o.VirtualNetworkRuleListTTL)
+		},
+		func(mgr ctrl.Manager, l logging.Logger, co chain.Options, observeOnly bool) error {
+			return postgresqlserver.Setup(mgr, l, co, observeOnly, o.SQLServerCABundle, o.IgnoredTags)
+		},
+		postgresqlserverconfiguration.Setup,
 		postgresqlserverfirewallrule.Setup,
-		postgresqlservervirtualnetworkrule.Setup,
+		func(mgr ctrl.Manager, l logging.Logger, co chain.Options, observeOnly bool) error {
+			return postgresqlservervirtualnetworkrule.Setup(mgr, l, co, observeOnly, o.VirtualNetworkRuleListTTL)
+		},
 		cosmosdb.Setup,
-		virtualnetwork.Setup,
+		diagnosticsetting.Setup,
 		subnet.Setup,
 		resourcegroup.Setup,
-		account.Setup,
 		container.Setup,
+		fileshare.Setup,
+	} {
+		if err := setup(mgr, l, co, o.ObserveOnly); err != nil {
+			return err
+		}
+	}
+	for _, setup := range []func(ctrl.Manager, logging.Logger, chain.Options, bool, azure.IgnoredTags) error{
+		cache.SetupRedis,
+		compute.SetupAKSCluster,
+		virtualnetwork.Setup,
+		natgateway.Setup,
+		serviceendpointpolicy.Setup,
+		account.Setup,
 	} {
-		if err := setup(mgr, l); err != nil {
+		if err := setup(mgr, l, co, o.ObserveOnly, o.IgnoredTags); err != nil {
 			return err
 		}
 	}
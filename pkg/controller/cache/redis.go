@@ -17,14 +17,23 @@ limitations under the License.
 package cache
 
 import (
+	"bytes"
 	"context"
-	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/redis/mgmt/redis"
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/redis/mgmt/redis/redisapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -36,6 +45,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/cache/v1beta1"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	redisclients "github.com/crossplane/provider-azure/pkg/clients/redis"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 const (
@@ -45,28 +56,152 @@ const (
 	errConnectFailed        = "cannot connect to Azure API"
 	errGetFailed            = "cannot get Redis instance from Azure API"
 	errListAccessKeysFailed = "cannot get access key list"
+	errGetLinkedServer      = "cannot get linked server"
 	errCreateFailed         = "cannot create the Redis instance"
 	errUpdateFailed         = "cannot update the Redis instance"
 	errDeleteFailed         = "cannot delete the Redis instance"
+	errCreateBackoff        = "not retrying failed create until %s"
+	errCreateTerminal       = "not retrying failed create until spec changes"
+	errGetSecret            = "cannot get connection secret"
+	errDeleteSecret         = "cannot delete connection secret"
+	errRenameConnection     = "cannot rename connection secret keys"
+	errNoPrivateAccess      = "public network access is disabled but no subnet is configured; the cache is only reachable via private endpoints"
+	errFmtFirewallInSubnet  = "the cache is deployed in a subnet but has firewall rules %s, which only apply to a public endpoint"
 )
 
-// SetupRedis adds a controller that reconciles Redis resources.
-func SetupRedis(mgr ctrl.Manager, l logging.Logger) error {
+// Event reasons.
+const (
+	reasonRotatedAccessKey event.Reason = "RotatedAccessKey"
+	reasonNoPublicAccess   event.Reason = "PublicNetworkAccessDisabled"
+	reasonFirewallInSubnet event.Reason = "FirewallRulesInSubnet"
+)
+
+const (
+	createBackoffBase = 30 * time.Second
+	createBackoffMax  = 30 * time.Minute
+
+	// Upgrade notifications are announced days ahead of maintenance, so
+	// needn't be listed every time a Redis is observed.
+	upgradeNotificationInterval = time.Hour
+)
+
+// SetupRedis adds a controller that reconciles Redis resources, ignoring the
+// supplied tags when determining whether they are up to date.
+func SetupRedis(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azure.IgnoredTags) error {
 	name := managed.ControllerName(v1beta1.RedisGroupKind)
+	r := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1beta1.Redis{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1beta1.RedisList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1beta1.RedisGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.RedisGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient()}),
+			managed.WithConnectionPublishers(
+				&keyRotationRecorder{client: mgr.GetClient(), record: r},
+				azure.NewSecretPublisher(mgr.GetClient(), mgr.GetScheme()),
+				&connectionSecretDeleter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connector{kube: mgr.GetClient(), backoff: newCreateBackoff(), warnings: newFirewallWarnings(), notifications: newNotificationFetches(upgradeNotificationInterval), record: r, log: l.WithValues("controller", name), ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly, chain.WithLocks(redisID))),
+			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), redisclients.NewExternalNamer(mgr.GetClient())),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(r)), throttled, o))
+}
+
+// A keyRotationRecorder records an event when the access key of a Redis no
+// longer matches the password in its connection secret, for example because
+// the key was regenerated out of band. It must precede the SecretPublisher,
+// which then updates the secret with the live key.
+type keyRotationRecorder struct {
+	client client.Client
+	record event.Recorder
+}
+
+// PublishConnection records an event if the supplied connection details will
+// rotate the password in the connection secret of the supplied managed
+// resource.
+func (r *keyRotationRecorder) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	cr, ok := mg.(*v1beta1.Redis)
+	if !ok || cr.GetWriteConnectionSecretToReference() == nil {
+		return nil
+	}
+	key := runtimev1alpha1.ResourceCredentialsSecretPasswordKey
+	if k := cr.Spec.ConnectionSecretKeys[key]; k != "" {
+		key = k
+	}
+	live, ok := c[key]
+	if !ok {
+		return nil
+	}
+	ref := cr.GetWriteConnectionSecretToReference()
+	s := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetSecret)
+	}
+	if !metav1.IsControlledBy(s, cr) {
+		return nil
+	}
+	if published, ok := s.Data[key]; ok && !bytes.Equal(published, live) {
+		r.record.Event(cr, event.Normal(reasonRotatedAccessKey, "Access key was regenerated; updating connection secret"))
+	}
+	return nil
+}
+
+// UnpublishConnection does nothing.
+func (r *keyRotationRecorder) UnpublishConnection(_ context.Context, _ resource.Managed, _ managed.ConnectionDetails) error {
+	return nil
+}
+
+// A connectionSecretDeleter deletes the connection secret of a Redis when the
+// Redis is deleted, unless its deletion policy is Orphan. Secrets that are not
+// controlled by the Redis are left in place.
+type connectionSecretDeleter struct {
+	client client.Client
+}
+
+// PublishConnection does nothing; connection details are published by the
+// SecretPublisher.
+func (d *connectionSecretDeleter) PublishConnection(_ context.Context, _ resource.Managed, _ managed.ConnectionDetails) error {
+	return nil
+}
+
+// UnpublishConnection deletes the connection secret of the supplied managed
+// resource, if it controls the secret.
+func (d *connectionSecretDeleter) UnpublishConnection(ctx context.Context, mg resource.Managed, _ managed.ConnectionDetails) error {
+	ref := mg.GetWriteConnectionSecretToReference()
+	if ref == nil || mg.GetDeletionPolicy() == runtimev1alpha1.DeletionOrphan {
+		return nil
+	}
+	s := &corev1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetSecret)
+	}
+	if !metav1.IsControlledBy(s, mg) {
+		return nil
+	}
+	return errors.Wrap(resource.IgnoreNotFound(d.client.Delete(ctx, s)), errDeleteSecret)
+}
+
+// redisID returns the Azure resource ID of the supplied Redis, if known.
+func redisID(mg resource.Managed) string {
+	cr, ok := mg.(*v1beta1.Redis)
+	if !ok {
+		return ""
+	}
+	return cr.Status.AtProvider.ID
 }
 
 type connector struct {
-	kube client.Client
+	kube          client.Client
+	backoff       *createBackoff
+	warnings      *firewallWarnings
+	notifications *notificationFetches
+	record        event.Recorder
+	log           logging.Logger
+	ignored       azure.IgnoredTags
+	observeOnly   bool
 }
 
 func (c connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -74,14 +209,296 @@ func (c connector) Connect(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return nil, errors.Wrap(err, errConnectFailed)
 	}
+	v, err := azure.GetAPIVersion(ctx, c.kube, mg, v1beta1.RedisKind)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectFailed)
+	}
 	cl := redis.NewClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{kube: c.kube, client: cl}, nil
+	cl.RequestInspector = azure.WithAPIVersion(v)
+	observed := &redisclients.ObservedProperties{}
+	if cr, ok := mg.(*v1beta1.Redis); ok {
+		cv := redisclients.APIVersion(cr.Spec.ForProvider, v)
+		cl.RequestInspector = func(p autorest.Preparer) autorest.Preparer {
+			return autorest.DecoratePreparer(p,
+				azure.WithAPIVersion(cv),
+				redisclients.WithRedisVersion(cr.Spec.ForProvider.RedisVersion),
+				redisclients.WithPublicNetworkAccess(cr.Spec.ForProvider.PublicNetworkAccess),
+				redisclients.WithReplicasPerMaster(cr.Spec.ForProvider.ReplicasPerMaster))
+		}
+		cl.ResponseInspector = azure.ByDecodingProperties(observed)
+	}
+	lcl := redis.NewLinkedServerClient(creds[azure.CredentialsKeySubscriptionID])
+	lcl.Authorizer = auth
+	lcl.RequestInspector = azure.WithAPIVersion(v)
+	fcl := redis.NewFirewallRulesClient(creds[azure.CredentialsKeySubscriptionID])
+	fcl.Authorizer = auth
+	fcl.RequestInspector = azure.WithAPIVersion(v)
+	return &external{kube: c.kube, client: cl, linked: lcl, firewall: fcl, backoff: c.backoff, warnings: c.warnings, notifications: c.notifications, credentials: azure.CredentialsFingerprint(creds), observed: observed, record: c.record, log: c.log, ignored: c.ignored, observeOnly: c.observeOnly}, nil
+}
+
+// A createBackoff tracks failed create attempts so that persistent failures
+// don't result in a create request every time a Redis is reconciled. Create
+// is retried with exponential backoff, except after terminal errors which are
+// not retried until the Redis spec changes. Attempts are also forgotten when
+// the credentials used to make them are rotated, because the new credentials
+// may well succeed where the old ones failed. Attempts are tracked in memory
+// and thus reset when the provider restarts.
+type createBackoff struct {
+	mu       sync.Mutex
+	limiter  workqueue.RateLimiter
+	attempts map[types.UID]createAttempt
+}
+
+type createAttempt struct {
+	notBefore   time.Time
+	generation  int64
+	credentials string
+	terminal    bool
+	err         error
+}
+
+func newCreateBackoff() *createBackoff {
+	return &createBackoff{
+		limiter:  workqueue.NewItemExponentialFailureRateLimiter(createBackoffBase, createBackoffMax),
+		attempts: map[types.UID]createAttempt{},
+	}
+}
+
+// Allowed returns an error if the supplied Redis should not be created yet
+// using the supplied credentials fingerprint.
+func (b *createBackoff) Allowed(cr *v1beta1.Redis, credentials string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	a, ok := b.attempts[cr.GetUID()]
+	switch {
+	case !ok:
+		return nil
+	case a.credentials != credentials:
+		return nil
+	case a.terminal && a.generation == cr.GetGeneration():
+		return errors.Wrap(a.err, errCreateTerminal)
+	case !a.terminal && time.Now().Before(a.notBefore):
+		return errors.Wrapf(a.err, errCreateBackoff, a.notBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Failed records a failed attempt to create the supplied Redis using the
+// supplied credentials fingerprint.
+func (b *createBackoff) Failed(cr *v1beta1.Redis, err error, credentials string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempts[cr.GetUID()] = createAttempt{
+		notBefore:   time.Now().Add(b.limiter.When(cr.GetUID())),
+		generation:  cr.GetGeneration(),
+		credentials: credentials,
+		terminal:    redisclients.IsTerminalCreateError(err),
+		err:         err,
+	}
+}
+
+// Succeeded forgets any failed attempts to create the supplied Redis.
+func (b *createBackoff) Succeeded(cr *v1beta1.Redis) {
+	b.Forget(cr)
+}
+
+// Forget any failed attempts to create the supplied Redis, for example because
+// it is being deleted. Forgetting is a no-op for a nil createBackoff.
+func (b *createBackoff) Forget(cr *v1beta1.Redis) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.attempts, cr.GetUID())
+	b.limiter.Forget(cr.GetUID())
+}
+
+// firewallWarnings tracks the ineffective firewall rules most recently warned
+// about for each Redis, so that a warning is recorded when they change rather
+// than every time a Redis is observed. Warnings are tracked in memory and thus
+// repeated once when the provider restarts.
+type firewallWarnings struct {
+	mu     sync.Mutex
+	warned map[types.UID]string
+}
+
+func newFirewallWarnings() *firewallWarnings {
+	return &firewallWarnings{warned: map[types.UID]string{}}
+}
+
+// Changed records the supplied ineffective firewall rules of the supplied
+// Redis, returning true if they differ from those previously recorded. A nil
+// firewallWarnings considers the rules to have always changed.
+func (w *firewallWarnings) Changed(cr *v1beta1.Redis, rules []string) bool {
+	if w == nil {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := strings.Join(rules, ", ")
+	if w.warned[cr.GetUID()] == n {
+		return false
+	}
+	if n == "" {
+		delete(w.warned, cr.GetUID())
+		return true
+	}
+	w.warned[cr.GetUID()] = n
+	return true
+}
+
+// Forget the ineffective firewall rules most recently warned about for the
+// supplied Redis, for example because it is being deleted.
+func (w *firewallWarnings) Forget(cr *v1beta1.Redis) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.warned, cr.GetUID())
+}
+
+// notificationFetches tracks when the upgrade notifications of each Redis were
+// last listed, so that they are listed at most once per interval. Fetches are
+// tracked in memory and thus repeated when the provider restarts.
+type notificationFetches struct {
+	mu       sync.Mutex
+	interval time.Duration
+	fetched  map[types.UID]time.Time
+}
+
+func newNotificationFetches(interval time.Duration) *notificationFetches {
+	return &notificationFetches{interval: interval, fetched: map[types.UID]time.Time{}}
+}
+
+// Due returns true if the upgrade notifications of the supplied Redis should
+// be listed. A nil notificationFetches considers them to always be due.
+func (f *notificationFetches) Due(cr *v1beta1.Redis) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.fetched[cr.GetUID()]
+	return !ok || time.Since(t) >= f.interval
+}
+
+// Fetched records that the upgrade notifications of the supplied Redis were
+// just listed.
+func (f *notificationFetches) Fetched(cr *v1beta1.Redis) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetched[cr.GetUID()] = time.Now()
+}
+
+// Forget when the upgrade notifications of the supplied Redis were last
+// listed, for example because it is being deleted.
+func (f *notificationFetches) Forget(cr *v1beta1.Redis) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.fetched, cr.GetUID())
 }
 
 type external struct {
-	kube   client.Client
-	client redisapi.ClientAPI
+	kube          client.Client
+	client        redisapi.ClientAPI
+	linked        redisapi.LinkedServerClientAPI
+	firewall      redisapi.FirewallRulesClientAPI
+	backoff       *createBackoff
+	warnings      *firewallWarnings
+	notifications *notificationFetches
+	credentials   string
+	record        event.Recorder
+	log           logging.Logger
+	ignored       azure.IgnoredTags
+	observeOnly   bool
+
+	// observed is populated with the ObservedProperties of the Redis
+	// returned by each call to client.Get.
+	observed *redisclients.ObservedProperties
+}
+
+// get returns the supplied Redis's Azure representation, and its
+// ObservedProperties.
+func (c *external) get(ctx context.Context, cr *v1beta1.Redis) (redis.ResourceType, redisclients.ObservedProperties, error) {
+	if c.observed == nil {
+		c.observed = &redisclients.ObservedProperties{}
+	}
+	*c.observed = redisclients.ObservedProperties{}
+	cache, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	return cache, *c.observed, err
+}
+
+// warnIfPubliclyInaccessible records a warning event if the supplied Redis
+// disables public network access without being deployed in a subnet.
+func (c *external) warnIfPubliclyInaccessible(cr *v1beta1.Redis) {
+	if c.record == nil || !redisclients.IsPubliclyInaccessible(cr.Spec.ForProvider) {
+		return
+	}
+	c.record.Event(cr, event.Warning(reasonNoPublicAccess, errors.New(errNoPrivateAccess)))
+}
+
+// warnIfFirewalledInSubnet records a warning event if the supplied Redis is
+// deployed in a subnet but also has firewall rules, which may have been added
+// out of band. The warning is only recorded when the rules change. Failing to
+// list the rules is logged rather than returned, so that it never prevents a
+// Redis from being observed.
+func (c *external) warnIfFirewalledInSubnet(ctx context.Context, cr *v1beta1.Redis) {
+	if azure.ToString(cr.Spec.ForProvider.SubnetID) == "" {
+		return
+	}
+	rules := make([]redis.FirewallRule, 0)
+	it, err := c.firewall.ListByRedisResourceComplete(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	for ; err == nil && it.NotDone(); err = it.NextWithContext(ctx) {
+		rules = append(rules, it.Value())
+	}
+	if err != nil {
+		if c.log != nil {
+			c.log.Info("Cannot list firewall rules", "name", cr.GetName(), "error", err)
+		}
+		return
+	}
+	n := redisclients.IneffectiveFirewallRules(cr.Spec.ForProvider, rules)
+	if !c.warnings.Changed(cr, n) || c.record == nil || len(n) == 0 {
+		return
+	}
+	c.record.Event(cr, event.Warning(reasonFirewallInSubnet, errors.Errorf(errFmtFirewallInSubnet, strings.Join(n, ", "))))
+}
+
+// observeUpgradeNotifications updates the upgrade notifications of the supplied
+// Redis if they are due to be listed. Failing to list them is logged rather
+// than returned, so that it never prevents a Redis from being observed; the
+// previously observed notifications are kept until they can be listed.
+func (c *external) observeUpgradeNotifications(ctx context.Context, cr *v1beta1.Redis) {
+	if !c.notifications.Due(cr) {
+		return
+	}
+	n, err := c.client.ListUpgradeNotifications(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), redisclients.UpgradeNotificationHistory)
+	if err != nil {
+		if c.log != nil {
+			c.log.Info("Cannot list upgrade notifications", "name", cr.GetName(), "error", err)
+		}
+		return
+	}
+	cr.Status.AtProvider.UpgradeNotifications = redisclients.GenerateUpgradeNotifications(n)
+	c.notifications.Fetched(cr)
+}
+
+// forget everything tracked in memory about the supplied Redis. It's called
+// when a Redis is being deleted, which may not involve a call to Delete if the
+// external resource never existed or is orphaned.
+func (c *external) forget(cr *v1beta1.Redis) {
+	c.backoff.Forget(cr)
+	c.warnings.Forget(cr)
+	c.notifications.Forget(cr)
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -89,40 +506,68 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotRedis)
 	}
-	cache, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	if meta.WasDeleted(cr) {
+		c.forget(cr)
+	}
+	cache, observed, err := c.get(ctx, cr)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(azure.IsNotFound, err), errGetFailed)
 	}
+	if err := azure.ValidateOwnership(cr, cr.Status.AtProvider.ID, azure.ToString(cache.ID), cache.Tags); err != nil && !c.observeOnly {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFailed)
+	}
 
-	redisclients.LateInitialize(&cr.Spec.ForProvider, cache)
-	if err := c.kube.Update(ctx, cr); err != nil {
+	original := cr.DeepCopy()
+	redisclients.LateInitialize(&cr.Spec.ForProvider, cache, c.ignored)
+	if err := azure.UpdateIfChanged(ctx, c.kube, original, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateRedisCRFailed)
 	}
+	notifications := cr.Status.AtProvider.UpgradeNotifications
 	cr.Status.AtProvider = redisclients.GenerateObservation(cache)
+	cr.Status.AtProvider.UpgradeNotifications = notifications
+	cr.Status.AtProvider.PublicNetworkAccess = azure.ToString(observed.PublicNetworkAccess)
+	cr.Status.AtProvider.ReplicasPerMaster = azure.ToInt(observed.ReplicasPerMaster)
+	// A cache may be linked to at most one other cache for geo-replication.
+	if l := cr.Status.AtProvider.LinkedServers; len(l) > 0 {
+		ls, err := c.linked.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), redisclients.LinkedServerName(l[0]))
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetLinkedServer)
+		}
+		redisclients.UpdateReplicationObservation(&cr.Status.AtProvider, ls)
+	}
+	c.warnIfFirewalledInSubnet(ctx, cr)
 
-	var conn managed.ConnectionDetails
-	switch cr.Status.AtProvider.ProvisioningState {
-	case redisclients.ProvisioningStateSucceeded:
+	// We publish the connection details we know as soon as we know them, so
+	// that consumers that can tolerate a cache that isn't ready yet needn't
+	// wait for it. The access keys are only known once the cache is ready.
+	conn := redisclients.GenerateConnectionDetails(cr.Status.AtProvider)
+	for k, v := range redisclients.GenerateShardConnectionDetails(cache) {
+		conn[k] = v
+	}
+	available := redisclients.IsAvailable(cr.Status.AtProvider.ProvisioningState)
+	if available {
 		k, err := c.client.ListKeys(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errListAccessKeysFailed)
 		}
-		conn = managed.ConnectionDetails{
-			runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(cr.Status.AtProvider.HostName),
-			runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(cr.Status.AtProvider.Port)),
-			runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(azure.ToString(k.PrimaryKey)),
-		}
-		cr.Status.SetConditions(runtimev1alpha1.Available())
-	case redisclients.ProvisioningStateCreating:
-		cr.Status.SetConditions(runtimev1alpha1.Creating())
-	case redisclients.ProvisioningStateDeleting:
-		cr.Status.SetConditions(runtimev1alpha1.Deleting())
-	default:
-		cr.Status.SetConditions(runtimev1alpha1.Unavailable())
+		conn[runtimev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(azure.ToString(k.PrimaryKey))
+		c.observeUpgradeNotifications(ctx, cr)
+	}
+	cr.Status.SetConditions(redisclients.Condition(cr.Status.AtProvider.ProvisioningState))
+	upToDate := !redisclients.NeedsUpdate(cr.Spec.ForProvider, cache, c.ignored) && !redisclients.PublicNetworkAccessNeedsUpdate(cr.Spec.ForProvider, observed)
+	if upToDate && cr.Status.AtProvider.ProvisioningState == redisclients.ProvisioningStateSucceeded {
+		redisclients.UpdateLastSyncTime(&cr.Status, time.Now())
+	}
+	conn, err = azure.RenameConnectionDetails(azure.WithReadiness(conn, available), cr.Spec.ConnectionSecretKeys)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRenameConnection)
 	}
 	return managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  !redisclients.NeedsUpdate(cr.Spec.ForProvider, cache),
+		ResourceUpToDate:  upToDate,
 		ConnectionDetails: conn,
 	}, nil
 }
@@ -132,9 +577,39 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotRedis)
 	}
+	if err := c.backoff.Allowed(cr, c.credentials); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	}
 	cr.Status.SetConditions(runtimev1alpha1.Creating())
-	_, err := c.client.Create(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), redisclients.NewCreateParameters(cr))
-	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	if err := azure.ValidateName(v1beta1.RedisKind, meta.GetExternalName(cr)); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	}
+	if err := redisclients.ValidateReplicasPerMaster(cr.Spec.ForProvider); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	}
+
+	// We may have issued a create request but failed to record it, for
+	// example because we were restarted before our status was persisted. Azure
+	// creates caches asynchronously, so a cache that exists (or is being
+	// provisioned) needs no further create request.
+	_, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	if err == nil {
+		c.backoff.Succeeded(cr)
+		return managed.ExternalCreation{}, nil
+	}
+	if !azure.IsNotFound(err) {
+		return managed.ExternalCreation{}, errors.Wrap(err, errGetFailed)
+	}
+
+	c.warnIfPubliclyInaccessible(cr)
+	p := redisclients.NewCreateParameters(cr)
+	p.Tags = azure.WithOwnershipTags(p.Tags, v1beta1.RedisKind, cr)
+	if _, err := c.client.Create(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), p); err != nil {
+		c.backoff.Failed(cr, err, c.credentials)
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	}
+	c.backoff.Succeeded(cr)
+	return managed.ExternalCreation{}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -147,15 +622,30 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if cr.Status.AtProvider.ProvisioningState != redisclients.ProvisioningStateSucceeded {
 		return managed.ExternalUpdate{}, nil
 	}
-	cache, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	cache, observed, err := c.get(ctx, cr)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetFailed)
 	}
+	if err := redisclients.ValidateImmutableFields(cr.Spec.ForProvider, cache); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	}
+	if err := redisclients.ValidateReplicasPerMaster(cr.Spec.ForProvider); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	}
+	if err := redisclients.ValidateReplicasPerMasterChange(cr.Spec.ForProvider, observed); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	}
+	if cache.Properties != nil {
+		if err := redisclients.ValidateVersionChange(cr.Spec.ForProvider, azure.ToString(cache.Properties.RedisVersion)); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+		}
+	}
+	c.warnIfPubliclyInaccessible(cr)
 	_, err = c.client.Update(
 		ctx,
 		cr.Spec.ForProvider.ResourceGroupName,
 		meta.GetExternalName(cr),
-		redisclients.NewUpdateParameters(cr.Spec.ForProvider, cache))
+		redisclients.NewUpdateParameters(cr.Spec.ForProvider, cache, c.ignored))
 	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
 }
 
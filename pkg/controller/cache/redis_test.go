@@ -21,15 +21,26 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/redis/mgmt/redis/redisapi"
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/date"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -61,6 +72,8 @@ var (
 	skuName          = "basic"
 	skuFamily        = "C"
 	skuCapacity      = 1
+	linkedServerID   = "/subscriptions/sub/resourceGroups/group1/providers/Microsoft.Cache/Redis/cool/linkedServers/coolsecondary"
+	linkedCacheID    = "/subscriptions/sub/resourceGroups/group2/providers/Microsoft.Cache/Redis/coolsecondary"
 )
 
 var (
@@ -82,6 +95,30 @@ func withHostName(h string) redisResourceModifier {
 	return func(r *v1beta1.Redis) { r.Status.AtProvider.HostName = h }
 }
 
+func withPrivateIP(ip string) redisResourceModifier {
+	return func(r *v1beta1.Redis) { r.Status.AtProvider.PrivateIP = ip }
+}
+
+func withGeneration(g int64) redisResourceModifier {
+	return func(r *v1beta1.Redis) { r.SetGeneration(g) }
+}
+
+func withRedisVersion(v string) redisResourceModifier {
+	return func(r *v1beta1.Redis) { r.Spec.ForProvider.RedisVersion = &v }
+}
+
+func withReplication(linkedServer, role, linkedCache string) redisResourceModifier {
+	return func(r *v1beta1.Redis) {
+		r.Status.AtProvider.LinkedServers = []string{linkedServer}
+		r.Status.AtProvider.ReplicationRole = role
+		r.Status.AtProvider.LinkedCacheID = linkedCache
+	}
+}
+
+func withUpgradeNotifications(n ...v1beta1.UpgradeNotification) redisResourceModifier {
+	return func(r *v1beta1.Redis) { r.Status.AtProvider.UpgradeNotifications = n }
+}
+
 func withPort(p int) redisResourceModifier {
 	return func(r *v1beta1.Redis) { r.Status.AtProvider.Port = p }
 }
@@ -130,9 +167,13 @@ var _ managed.ExternalConnecter = &connector{}
 
 func TestObserve(t *testing.T) {
 	type args struct {
-		cr   *v1beta1.Redis
-		r    redisapi.ClientAPI
-		kube client.Client
+		cr       *v1beta1.Redis
+		r        redisapi.ClientAPI
+		linked   redisapi.LinkedServerClientAPI
+		firewall redisapi.FirewallRulesClientAPI
+		kube     client.Client
+
+		notifications *notificationFetches
 	}
 	type want struct {
 		cr  *v1beta1.Redis
@@ -140,6 +181,18 @@ func TestObserve(t *testing.T) {
 		err error
 	}
 
+	owned := azure.WithOwnershipTags(nil, v1beta1.RedisKind, instance())
+	noNotifications := func(_ context.Context, _, _ string, _ float64) (redis.NotificationListResponse, error) {
+		return redis.NotificationListResponse{}, nil
+	}
+	maintenance := metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	previously := v1beta1.UpgradeNotification{Name: "PlannedMaintenance", Timestamp: &maintenance}
+	fetched := func() *notificationFetches {
+		f := newNotificationFetches(time.Hour)
+		f.Fetched(instance())
+		return f
+	}
+
 	cases := map[string]struct {
 		args
 		want
@@ -153,10 +206,147 @@ func TestObserve(t *testing.T) {
 				r: &fake.MockClient{
 					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
 						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Succeeded,
+								HostName:          &hostName,
+								Port:              azure.ToInt32(&port),
+							},
+						}, nil
+					},
+					MockListKeys: func(ctx context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{
+							PrimaryKey: azure.ToStringPtr(primaryKey),
+						}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withHostName(hostName),
+					withPort(port),
+					withConditions(runtimev1alpha1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"Scaling": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Scaling,
+								HostName:          &hostName,
+								Port:              azure.ToInt32(&port),
+							},
+						}, nil
+					},
+					MockListKeys: func(ctx context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{
+							PrimaryKey: azure.ToStringPtr(primaryKey),
+						}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateScaling),
+					withHostName(hostName),
+					withPort(port),
+					withConditions(redisclient.Condition(redisclient.ProvisioningStateScaling)),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"SuccessfulClustered": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Succeeded,
+								HostName:          &hostName,
+								Port:              azure.ToInt32(&port),
+								ShardCount:        azure.ToInt32Ptr(1),
+							},
+						}, nil
+					},
+					MockListKeys: func(ctx context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{
+							PrimaryKey: azure.ToStringPtr(primaryKey),
+						}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withHostName(hostName),
+					withPort(port),
+					withConditions(runtimev1alpha1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						redisclient.ConnectionSecretShardCountKey:            []byte("1"),
+						redisclient.ShardSSLPortKey(0):                       []byte("15000"),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"SuccessfulVNetInjected": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
 							Properties: &redis.Properties{
 								ProvisioningState: redis.Succeeded,
 								HostName:          &hostName,
 								Port:              azure.ToInt32(&port),
+								StaticIP:          &staticIP,
 							},
 						}, nil
 					},
@@ -165,6 +355,7 @@ func TestObserve(t *testing.T) {
 							PrimaryKey: azure.ToStringPtr(primaryKey),
 						}, nil
 					},
+					MockListUpgradeNotifications: noNotifications,
 				},
 			},
 			want: want{
@@ -172,6 +363,7 @@ func TestObserve(t *testing.T) {
 					withProvisioningState(redisclient.ProvisioningStateSucceeded),
 					withHostName(hostName),
 					withPort(port),
+					withPrivateIP(staticIP),
 					withConditions(runtimev1alpha1.Available()),
 				),
 				o: managed.ExternalObservation{
@@ -181,6 +373,8 @@ func TestObserve(t *testing.T) {
 						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
 						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
 						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						redisclient.ConnectionSecretPrivateIPKey:             []byte(staticIP),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
 					},
 				},
 			},
@@ -199,15 +393,29 @@ func TestObserve(t *testing.T) {
 				err: errors.Wrap(errorBoom, errGetFailed),
 			},
 		},
-		"KubeUpdateFailed": {
+		"NotOwned": {
 			args: args{
 				cr: instance(),
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{}, nil
+					},
+				},
+			},
+			want: want{
+				cr:  instance(),
+				err: errors.Wrap(azure.ValidateOwnership(instance(), "", "", nil), errGetFailed),
+			},
+		},
+		"KubeUpdateFailed": {
+			args: args{
+				cr: instance(func(r *v1beta1.Redis) { r.Spec.ForProvider.Zones = nil }),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(errorBoom),
 				},
 				r: &fake.MockClient{
 					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{}, nil
+						return redis.ResourceType{Tags: owned, Zones: &[]string{"us-east1a", "us-east1b"}}, nil
 					},
 				},
 			},
@@ -216,6 +424,127 @@ func TestObserve(t *testing.T) {
 				err: errors.Wrap(errorBoom, errUpdateRedisCRFailed),
 			},
 		},
+		"UpgradeNotifications": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Succeeded,
+								HostName:          &hostName,
+								Port:              azure.ToInt32(&port),
+							},
+						}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{PrimaryKey: azure.ToStringPtr(primaryKey)}, nil
+					},
+					MockListUpgradeNotifications: func(_ context.Context, _, _ string, history float64) (redis.NotificationListResponse, error) {
+						if history != redisclient.UpgradeNotificationHistory {
+							return redis.NotificationListResponse{}, errorBoom
+						}
+						return redis.NotificationListResponse{Value: &[]redis.UpgradeNotification{{
+							Name:               azure.ToStringPtr("PlannedMaintenance"),
+							Timestamp:          &date.Time{Time: maintenance.Time},
+							UpsellNotification: map[string]*string{"impact": azure.ToStringPtr("failover")},
+						}}}, nil
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withHostName(hostName),
+					withPort(port),
+					withUpgradeNotifications(v1beta1.UpgradeNotification{
+						Name:      "PlannedMaintenance",
+						Timestamp: &maintenance,
+						Details:   map[string]string{"impact": "failover"},
+					}),
+					withConditions(runtimev1alpha1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"ListUpgradeNotificationsFailed": {
+			args: args{
+				cr: instance(withUpgradeNotifications(previously)),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Succeeded}}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{PrimaryKey: azure.ToStringPtr(primaryKey)}, nil
+					},
+					MockListUpgradeNotifications: func(_ context.Context, _, _ string, _ float64) (redis.NotificationListResponse, error) {
+						return redis.NotificationListResponse{}, errorBoom
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withUpgradeNotifications(previously),
+					withConditions(runtimev1alpha1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"UpgradeNotificationsNotDue": {
+			args: args{
+				cr: instance(withUpgradeNotifications(previously)),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Succeeded}}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{PrimaryKey: azure.ToStringPtr(primaryKey)}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+				notifications: fetched(),
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withUpgradeNotifications(previously),
+					withConditions(runtimev1alpha1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
 		"ListAccessKeysFailed": {
 			args: args{
 				cr: instance(),
@@ -224,7 +553,7 @@ func TestObserve(t *testing.T) {
 				},
 				r: &fake.MockClient{
 					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{Properties: &redis.Properties{ProvisioningState: redis.Succeeded}}, nil
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Succeeded}}, nil
 					},
 					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
 						return redis.AccessKeys{}, errorBoom
@@ -241,125 +570,405 @@ func TestObserve(t *testing.T) {
 		"Creating": {
 			args: args{
 				cr: instance(),
-				kube: &test.MockClient{
-					MockUpdate: test.NewMockUpdateFn(nil),
-				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Creating, HostName: &hostName, Port: azure.ToInt32(&port)}}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateCreating),
+					withHostName(hostName),
+					withPort(port),
+					withConditions(runtimev1alpha1.Creating()),
+				),
+				o: managed.ExternalObservation{
+					ResourceUpToDate: false,
+					ResourceExists:   true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName),
+						runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(port)),
+						azure.ConnectionSecretReadyKey:                       []byte("false"),
+					},
+				},
+			},
+		},
+		"Deleting": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Deleting}}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateDeleting),
+					withConditions(runtimev1alpha1.Deleting()),
+				),
+				o: managed.ExternalObservation{
+					ResourceUpToDate:  false,
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{azure.ConnectionSecretReadyKey: []byte("false")},
+				},
+			},
+		},
+		"GeoReplicationPrimary": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Failed,
+								LinkedServers:     &[]redis.LinkedServer{{ID: azure.ToStringPtr(linkedServerID)}},
+							},
+						}, nil
+					},
+				},
+				linked: &fake.MockLinkedServerClient{
+					MockGet: func(_ context.Context, _ string, _ string, linkedServerName string) (redis.LinkedServerWithProperties, error) {
+						if linkedServerName != "coolsecondary" {
+							return redis.LinkedServerWithProperties{}, errorBoom
+						}
+						return redis.LinkedServerWithProperties{LinkedServerProperties: &redis.LinkedServerProperties{
+							LinkedRedisCacheID: azure.ToStringPtr(linkedCacheID),
+							ServerRole:         redis.ReplicationRoleSecondary,
+						}}, nil
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateFailed),
+					withReplication(linkedServerID, string(redis.ReplicationRolePrimary), linkedCacheID),
+					withConditions(redisclient.Condition(redisclient.ProvisioningStateFailed)),
+				),
+				o: managed.ExternalObservation{
+					ResourceUpToDate:  false,
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{azure.ConnectionSecretReadyKey: []byte("false")},
+				},
+			},
+		},
+		"GetLinkedServerFailed": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{
+							Tags: owned,
+							Properties: &redis.Properties{
+								ProvisioningState: redis.Failed,
+								LinkedServers:     &[]redis.LinkedServer{{ID: azure.ToStringPtr(linkedServerID)}},
+							},
+						}, nil
+					},
+				},
+				linked: &fake.MockLinkedServerClient{
+					MockGet: func(_ context.Context, _ string, _ string, _ string) (redis.LinkedServerWithProperties, error) {
+						return redis.LinkedServerWithProperties{}, errorBoom
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateFailed),
+					withReplication(linkedServerID, "", ""),
+				),
+				err: errors.Wrap(errorBoom, errGetLinkedServer),
+			},
+		},
+		"ListFirewallRulesFailedIsIgnored": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Failed}}, nil
+					},
+				},
+				firewall: &fake.MockFirewallRulesClient{
+					MockListByRedisResourceComplete: func(_ context.Context, _ string, _ string) (redis.FirewallRuleListResultIterator, error) {
+						return redis.FirewallRuleListResultIterator{}, errorBoom
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateFailed),
+					withConditions(redisclient.Condition(redisclient.ProvisioningStateFailed)),
+				),
+				o: managed.ExternalObservation{
+					ResourceUpToDate:  false,
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{azure.ConnectionSecretReadyKey: []byte("false")},
+				},
+			},
+		},
+		"Unavailable": {
+			args: args{
+				cr: instance(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Tags: owned, Properties: &redis.Properties{ProvisioningState: redis.Failed}}, nil
+					},
+					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
+						return redis.AccessKeys{}, nil
+					},
+					MockListUpgradeNotifications: noNotifications,
+				},
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateFailed),
+					withConditions(redisclient.Condition(redisclient.ProvisioningStateFailed)),
+				),
+				o: managed.ExternalObservation{
+					ResourceUpToDate:  false,
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{azure.ConnectionSecretReadyKey: []byte("false")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.firewall == nil {
+				tc.firewall = &fake.MockFirewallRulesClient{MockListByRedisResourceComplete: firewallRules()}
+			}
+			e := external{
+				kube:          tc.kube,
+				client:        tc.r,
+				linked:        tc.linked,
+				firewall:      tc.firewall,
+				notifications: tc.notifications,
+			}
+			o, err := e.Observe(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
+				t.Errorf("Observe(...): -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want, +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+// firewallRules returns a MockListByRedisResourceComplete that lists firewall
+// rules with the supplied names, one per page.
+func firewallRules(names ...string) func(context.Context, string, string) (redis.FirewallRuleListResultIterator, error) {
+	return func(ctx context.Context, _ string, _ string) (redis.FirewallRuleListResultIterator, error) {
+		i := 0
+		p := redis.NewFirewallRuleListResultPage(func(_ context.Context, _ redis.FirewallRuleListResult) (redis.FirewallRuleListResult, error) {
+			if i >= len(names) {
+				return redis.FirewallRuleListResult{}, nil
+			}
+			rules := []redis.FirewallRule{{Name: azure.ToStringPtr(names[i])}}
+			i++
+			return redis.FirewallRuleListResult{Value: &rules}, nil
+		})
+		err := p.NextWithContext(ctx)
+		return redis.NewFirewallRuleListResultIterator(p), err
+	}
+}
+
+// getNotFound is a MockGet that reports the cache does not exist.
+func getNotFound(_ context.Context, _ string, _ string) (redis.ResourceType, error) {
+	return redis.ResourceType{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		cr      *v1beta1.Redis
+		r       redisapi.ClientAPI
+		backoff *createBackoff
+	}
+	type want struct {
+		cr  *v1beta1.Redis
+		o   managed.ExternalCreation
+		err error
+	}
+	notBefore := time.Now().Add(time.Hour)
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Successful": {
+			args: args{
+				cr: instance(),
+				r: &fake.MockClient{
+					MockGet: getNotFound,
+					MockCreate: func(_ context.Context, resourceGroupName string, name string, parameters redis.CreateParameters) (result redis.CreateFuture, err error) {
+						return redis.CreateFuture{}, nil
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withConditions(runtimev1alpha1.Creating()),
+				),
+			},
+		},
+		"AlreadyExists": {
+			args: args{
+				cr: instance(),
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, _ string, _ string) (redis.ResourceType, error) {
+						return redis.ResourceType{}, nil
+					},
+				},
+			},
+			want: want{
+				cr: instance(
+					withConditions(runtimev1alpha1.Creating()),
+				),
+			},
+		},
+		"ReplicasPerMasterNotClustered": {
+			args: args{
+				cr: instance(func(r *v1beta1.Redis) {
+					r.Spec.ForProvider.ShardCount = nil
+					r.Spec.ForProvider.ReplicasPerMaster = &shardCount
+				}),
+			},
+			want: want{
+				cr: instance(
+					func(r *v1beta1.Redis) {
+						r.Spec.ForProvider.ShardCount = nil
+						r.Spec.ForProvider.ReplicasPerMaster = &shardCount
+					},
+					withConditions(runtimev1alpha1.Creating()),
+				),
+				err: errors.Wrap(redisclient.ValidateReplicasPerMaster(v1beta1.RedisParameters{ReplicasPerMaster: &shardCount}), errCreateFailed),
+			},
+		},
+		"GetFailed": {
+			args: args{
+				cr: instance(),
 				r: &fake.MockClient{
-					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{Properties: &redis.Properties{ProvisioningState: redis.Creating}}, nil
-					},
-					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
-						return redis.AccessKeys{}, nil
+					MockGet: func(_ context.Context, _ string, _ string) (redis.ResourceType, error) {
+						return redis.ResourceType{}, errorBoom
 					},
 				},
 			},
 			want: want{
 				cr: instance(
-					withProvisioningState(redisclient.ProvisioningStateCreating),
 					withConditions(runtimev1alpha1.Creating()),
 				),
-				o: managed.ExternalObservation{
-					ResourceUpToDate: false,
-					ResourceExists:   true,
-				},
+				err: errors.Wrap(errorBoom, errGetFailed),
 			},
 		},
-		"Deleting": {
+		"Failed": {
 			args: args{
 				cr: instance(),
-				kube: &test.MockClient{
-					MockUpdate: test.NewMockUpdateFn(nil),
-				},
 				r: &fake.MockClient{
-					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{Properties: &redis.Properties{ProvisioningState: redis.Deleting}}, nil
-					},
-					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
-						return redis.AccessKeys{}, nil
+					MockGet: getNotFound,
+					MockCreate: func(_ context.Context, resourceGroupName string, name string, parameters redis.CreateParameters) (result redis.CreateFuture, err error) {
+						return redis.CreateFuture{}, errorBoom
 					},
 				},
 			},
 			want: want{
 				cr: instance(
-					withProvisioningState(redisclient.ProvisioningStateDeleting),
-					withConditions(runtimev1alpha1.Deleting()),
+					withConditions(runtimev1alpha1.Creating()),
 				),
-				o: managed.ExternalObservation{
-					ResourceUpToDate: false,
-					ResourceExists:   true,
-				},
+				err: errors.Wrap(errorBoom, errCreateFailed),
 			},
 		},
-		"Unavailable": {
+		"BackingOff": {
+			args: args{
+				cr: instance(),
+				backoff: &createBackoff{attempts: map[types.UID]createAttempt{
+					"": {notBefore: notBefore, err: errorBoom},
+				}},
+			},
+			want: want{
+				cr:  instance(),
+				err: errors.Wrap(errors.Wrapf(errorBoom, errCreateBackoff, notBefore.Format(time.RFC3339)), errCreateFailed),
+			},
+		},
+		"BackoffExpired": {
 			args: args{
 				cr: instance(),
-				kube: &test.MockClient{
-					MockUpdate: test.NewMockUpdateFn(nil),
-				},
 				r: &fake.MockClient{
-					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{Properties: &redis.Properties{ProvisioningState: redis.Failed}}, nil
+					MockGet: getNotFound,
+					MockCreate: func(_ context.Context, resourceGroupName string, name string, parameters redis.CreateParameters) (result redis.CreateFuture, err error) {
+						return redis.CreateFuture{}, nil
 					},
-					MockListKeys: func(_ context.Context, resourceGroupName string, name string) (result redis.AccessKeys, err error) {
-						return redis.AccessKeys{}, nil
+				},
+				backoff: &createBackoff{
+					limiter: workqueue.DefaultItemBasedRateLimiter(),
+					attempts: map[types.UID]createAttempt{
+						"": {notBefore: time.Now().Add(-time.Hour), err: errorBoom},
 					},
 				},
 			},
 			want: want{
 				cr: instance(
-					withProvisioningState(redisclient.ProvisioningStateFailed),
-					withConditions(runtimev1alpha1.Unavailable()),
+					withConditions(runtimev1alpha1.Creating()),
 				),
-				o: managed.ExternalObservation{
-					ResourceUpToDate: false,
-					ResourceExists:   true,
-				},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			e := external{
-				kube:   tc.kube,
-				client: tc.r,
-			}
-			o, err := e.Observe(context.Background(), tc.args.cr)
-			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
-				t.Errorf("Observe(...): -want, +got\n%s", diff)
-			}
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("Observe(...): -want, +got\n%s", diff)
-			}
-			if diff := cmp.Diff(tc.want.o, o); diff != "" {
-				t.Errorf("Observe(...): -want, +got\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestCreate(t *testing.T) {
-	type args struct {
-		cr *v1beta1.Redis
-		r  redisapi.ClientAPI
-	}
-	type want struct {
-		cr  *v1beta1.Redis
-		o   managed.ExternalCreation
-		err error
-	}
-	cases := map[string]struct {
-		args
-		want
-	}{
-		"Successful": {
+		"Terminal": {
+			args: args{
+				cr: instance(),
+				backoff: &createBackoff{attempts: map[types.UID]createAttempt{
+					"": {terminal: true, err: errorBoom},
+				}},
+			},
+			want: want{
+				cr:  instance(),
+				err: errors.Wrap(errors.Wrap(errorBoom, errCreateTerminal), errCreateFailed),
+			},
+		},
+		"CredentialsRotated": {
 			args: args{
 				cr: instance(),
 				r: &fake.MockClient{
+					MockGet: getNotFound,
 					MockCreate: func(_ context.Context, resourceGroupName string, name string, parameters redis.CreateParameters) (result redis.CreateFuture, err error) {
 						return redis.CreateFuture{}, nil
 					},
 				},
+				backoff: &createBackoff{
+					limiter: workqueue.DefaultItemBasedRateLimiter(),
+					attempts: map[types.UID]createAttempt{
+						"": {terminal: true, credentials: "old", err: errorBoom},
+					},
+				},
 			},
 			want: want{
 				cr: instance(
@@ -367,27 +976,38 @@ func TestCreate(t *testing.T) {
 				),
 			},
 		},
-		"Failed": {
+		"TerminalSpecChanged": {
 			args: args{
-				cr: instance(),
+				cr: instance(withGeneration(2)),
 				r: &fake.MockClient{
+					MockGet: getNotFound,
 					MockCreate: func(_ context.Context, resourceGroupName string, name string, parameters redis.CreateParameters) (result redis.CreateFuture, err error) {
-						return redis.CreateFuture{}, errorBoom
+						return redis.CreateFuture{}, nil
+					},
+				},
+				backoff: &createBackoff{
+					limiter: workqueue.DefaultItemBasedRateLimiter(),
+					attempts: map[types.UID]createAttempt{
+						"": {terminal: true, generation: 1, err: errorBoom},
 					},
 				},
 			},
 			want: want{
 				cr: instance(
+					withGeneration(2),
 					withConditions(runtimev1alpha1.Creating()),
 				),
-				err: errors.Wrap(errorBoom, errCreateFailed),
 			},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{client: tc.r}
+			b := tc.backoff
+			if b == nil {
+				b = newCreateBackoff()
+			}
+			e := external{client: tc.r, backoff: b}
 
 			c, err := e.Create(context.Background(), tc.args.cr)
 			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
@@ -460,7 +1080,11 @@ func TestUpdate(t *testing.T) {
 				cr: instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
 				r: &fake.MockClient{
 					MockGet: func(_ context.Context, _ string, _ string) (result redis.ResourceType, err error) {
-						return redis.ResourceType{Properties: &redis.Properties{ProvisioningState: redis.Succeeded}}, nil
+						return redis.ResourceType{Properties: &redis.Properties{
+							ProvisioningState: redis.Succeeded,
+							SubnetID:          &subnetID,
+							StaticIP:          &staticIP,
+						}}, nil
 					},
 					MockUpdate: func(_ context.Context, resourceGroupName string, name string, parameters redis.UpdateParameters) (result redis.ResourceType, err error) {
 						return redis.ResourceType{}, errorBoom
@@ -472,6 +1096,43 @@ func TestUpdate(t *testing.T) {
 				err: errors.Wrap(errorBoom, errUpdateFailed),
 			},
 		},
+		"ImmutableFieldChanged": {
+			args: args{
+				cr: instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, _ string, _ string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Properties: &redis.Properties{
+							ProvisioningState: redis.Succeeded,
+							SubnetID:          azure.ToStringPtr("othersubnet"),
+							StaticIP:          &staticIP,
+						}}, nil
+					},
+				},
+			},
+			want: want{
+				cr:  instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
+				err: errors.Wrap(errors.New("subnetId cannot be changed after the cache is created"), errUpdateFailed),
+			},
+		},
+		"VersionDowngraded": {
+			args: args{
+				cr: instance(withProvisioningState(redisclient.ProvisioningStateSucceeded), withRedisVersion("4")),
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, _ string, _ string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{Properties: &redis.Properties{
+							ProvisioningState: redis.Succeeded,
+							SubnetID:          &subnetID,
+							StaticIP:          &staticIP,
+							RedisVersion:      azure.ToStringPtr("6.0.14"),
+						}}, nil
+					},
+				},
+			},
+			want: want{
+				cr:  instance(withProvisioningState(redisclient.ProvisioningStateSucceeded), withRedisVersion("4")),
+				err: errors.Wrap(errors.New("redisVersion cannot be downgraded from 6 to 4"), errUpdateFailed),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -576,3 +1237,324 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpublishConnection(t *testing.T) {
+	uid := types.UID("cool-uid")
+	controlled := func(obj runtime.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: azure.ToBoolPtr(true)}})
+		return nil
+	}
+	withUID := func(r *v1beta1.Redis) { r.SetUID(uid) }
+	orphan := func(r *v1beta1.Redis) { r.SetDeletionPolicy(runtimev1alpha1.DeletionOrphan) }
+
+	cases := map[string]struct {
+		kube client.Client
+		cr   *v1beta1.Redis
+		err  error
+	}{
+		"Orphan": {
+			cr: instance(withUID, orphan),
+		},
+		"NotFound": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)),
+			},
+			cr: instance(withUID),
+		},
+		"GetFailed": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(errorBoom),
+			},
+			cr:  instance(withUID),
+			err: errors.Wrap(errorBoom, errGetSecret),
+		},
+		"NotControlled": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			cr: instance(withUID),
+		},
+		"Deleted": {
+			kube: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, controlled),
+				MockDelete: test.NewMockDeleteFn(nil),
+			},
+			cr: instance(withUID),
+		},
+		"DeleteFailed": {
+			kube: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, controlled),
+				MockDelete: test.NewMockDeleteFn(errorBoom),
+			},
+			cr:  instance(withUID),
+			err: errors.Wrap(errorBoom, errDeleteSecret),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &connectionSecretDeleter{client: tc.kube}
+			err := d.UnpublishConnection(context.Background(), tc.cr, nil)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UnpublishConnection(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+// eventRecorder records the events it is asked to record.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestKeyRotationRecorder(t *testing.T) {
+	uid := types.UID("cool-uid")
+	published := func(key, password string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			s := obj.(*corev1.Secret)
+			s.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: azure.ToBoolPtr(true)}})
+			s.Data = map[string][]byte{key: []byte(password)}
+			return nil
+		}
+	}
+	withUID := func(r *v1beta1.Redis) { r.SetUID(uid) }
+	withPasswordKey := func(r *v1beta1.Redis) {
+		r.Spec.ConnectionSecretKeys = map[string]string{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: "REDIS_PASSWORD"}
+	}
+	rotated := event.Normal(reasonRotatedAccessKey, "Access key was regenerated; updating connection secret")
+
+	cases := map[string]struct {
+		kube client.Client
+		cr   *v1beta1.Redis
+		c    managed.ConnectionDetails
+		want []event.Event
+		err  error
+	}{
+		"NoPassword": {
+			cr: instance(withUID),
+			c:  managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(hostName)},
+		},
+		"NotYetPublished": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)),
+			},
+			cr: instance(withUID),
+			c:  managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey)},
+		},
+		"GetFailed": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(errorBoom),
+			},
+			cr:  instance(withUID),
+			c:   managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey)},
+			err: errors.Wrap(errorBoom, errGetSecret),
+		},
+		"NotControlled": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			cr: instance(withUID),
+			c:  managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey)},
+		},
+		"Unchanged": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, published(runtimev1alpha1.ResourceCredentialsSecretPasswordKey, primaryKey)),
+			},
+			cr: instance(withUID),
+			c:  managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey)},
+		},
+		"Rotated": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, published(runtimev1alpha1.ResourceCredentialsSecretPasswordKey, "stalepass")),
+			},
+			cr:   instance(withUID),
+			c:    managed.ConnectionDetails{runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(primaryKey)},
+			want: []event.Event{rotated},
+		},
+		"RotatedRenamedKey": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, published("REDIS_PASSWORD", "stalepass")),
+			},
+			cr:   instance(withUID, withPasswordKey),
+			c:    managed.ConnectionDetails{"REDIS_PASSWORD": []byte(primaryKey)},
+			want: []event.Event{rotated},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			r := &keyRotationRecorder{client: tc.kube, record: rec}
+			err := r.PublishConnection(context.Background(), tc.cr, tc.c)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("PublishConnection(...): -want error, +got error\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, rec.events); diff != "" {
+				t.Errorf("PublishConnection(...): -want events, +got events\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWarnIfPubliclyInaccessible(t *testing.T) {
+	warning := event.Warning(reasonNoPublicAccess, errors.New(errNoPrivateAccess))
+
+	cases := map[string]struct {
+		cr   *v1beta1.Redis
+		want []event.Event
+	}{
+		"PublicAccessUnset": {
+			cr: instance(),
+		},
+		"DisabledInSubnet": {
+			cr: instance(func(r *v1beta1.Redis) {
+				r.Spec.ForProvider.PublicNetworkAccess = azure.ToStringPtr(redisclient.PublicNetworkAccessDisabled)
+			}),
+		},
+		"DisabledWithoutSubnet": {
+			cr: instance(func(r *v1beta1.Redis) {
+				r.Spec.ForProvider.PublicNetworkAccess = azure.ToStringPtr(redisclient.PublicNetworkAccessDisabled)
+				r.Spec.ForProvider.SubnetID = nil
+			}),
+			want: []event.Event{warning},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			e := external{record: rec}
+			e.warnIfPubliclyInaccessible(tc.cr)
+			if diff := cmp.Diff(tc.want, rec.events, test.EquateErrors()); diff != "" {
+				t.Errorf("warnIfPubliclyInaccessible(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWarnIfFirewalledInSubnet(t *testing.T) {
+	warned := func() *firewallWarnings {
+		w := newFirewallWarnings()
+		w.Changed(instance(), []string{"office", "vpn"})
+		return w
+	}
+
+	cases := map[string]struct {
+		cr       *v1beta1.Redis
+		firewall redisapi.FirewallRulesClientAPI
+		warnings *firewallWarnings
+		want     []event.Event
+	}{
+		"NoSubnet": {
+			cr: instance(func(r *v1beta1.Redis) { r.Spec.ForProvider.SubnetID = nil }),
+		},
+		"SubnetWithoutRules": {
+			cr:       instance(),
+			firewall: &fake.MockFirewallRulesClient{MockListByRedisResourceComplete: firewallRules()},
+			warnings: warned(),
+		},
+		"SubnetWithRules": {
+			cr:       instance(),
+			firewall: &fake.MockFirewallRulesClient{MockListByRedisResourceComplete: firewallRules("office", "vpn")},
+			warnings: newFirewallWarnings(),
+			want:     []event.Event{event.Warning(reasonFirewallInSubnet, errors.Errorf(errFmtFirewallInSubnet, "office, vpn"))},
+		},
+		"RulesUnchanged": {
+			cr:       instance(),
+			firewall: &fake.MockFirewallRulesClient{MockListByRedisResourceComplete: firewallRules("office", "vpn")},
+			warnings: warned(),
+		},
+		"RulesChanged": {
+			cr:       instance(),
+			firewall: &fake.MockFirewallRulesClient{MockListByRedisResourceComplete: firewallRules("office")},
+			warnings: warned(),
+			want:     []event.Event{event.Warning(reasonFirewallInSubnet, errors.Errorf(errFmtFirewallInSubnet, "office"))},
+		},
+		"ListFailed": {
+			cr: instance(),
+			firewall: &fake.MockFirewallRulesClient{
+				MockListByRedisResourceComplete: func(_ context.Context, _ string, _ string) (redis.FirewallRuleListResultIterator, error) {
+					return redis.FirewallRuleListResultIterator{}, errorBoom
+				},
+			},
+			warnings: newFirewallWarnings(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			e := external{firewall: tc.firewall, warnings: tc.warnings, record: rec, log: logging.NewNopLogger()}
+			e.warnIfFirewalledInSubnet(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want, rec.events, test.EquateErrors()); diff != "" {
+				t.Errorf("warnIfFirewalledInSubnet(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	deleted := func(r *v1beta1.Redis) {
+		now := metav1.Now()
+		r.SetDeletionTimestamp(&now)
+	}
+
+	fetched := time.Now()
+
+	type want struct {
+		attempts map[types.UID]createAttempt
+		warned   map[types.UID]string
+		fetched  map[types.UID]time.Time
+	}
+
+	cases := map[string]struct {
+		cr   *v1beta1.Redis
+		want want
+	}{
+		"NotDeleted": {
+			cr: instance(),
+			want: want{
+				attempts: map[types.UID]createAttempt{"": {err: errorBoom}},
+				warned:   map[types.UID]string{"": "office"},
+				fetched:  map[types.UID]time.Time{"": fetched},
+			},
+		},
+		"Deleted": {
+			cr: instance(deleted),
+			want: want{
+				attempts: map[types.UID]createAttempt{},
+				warned:   map[types.UID]string{},
+				fetched:  map[types.UID]time.Time{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &createBackoff{
+				limiter:  workqueue.DefaultItemBasedRateLimiter(),
+				attempts: map[types.UID]createAttempt{"": {err: errorBoom}},
+			}
+			w := &firewallWarnings{warned: map[types.UID]string{"": "office"}}
+			n := &notificationFetches{fetched: map[types.UID]time.Time{"": fetched}}
+			e := external{client: &fake.MockClient{MockGet: getNotFound}, backoff: b, warnings: w, notifications: n}
+			if _, err := e.Observe(context.Background(), tc.cr); err != nil {
+				t.Fatalf("Observe(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.attempts, b.attempts, cmp.AllowUnexported(createAttempt{}), test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want attempts, +got attempts\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.warned, w.warned); diff != "" {
+				t.Errorf("Observe(...): -want warned, +got warned\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.fetched, n.fetched); diff != "" {
+				t.Errorf("Observe(...): -want fetched, +got fetched\n%s", diff)
+			}
+		})
+	}
+}
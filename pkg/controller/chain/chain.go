@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chain builds the chains of reconcilers and external connecters that
+// wrap the reconciler and external connecter of each Azure controller, so that
+// every controller wraps them in the same order.
+package chain
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/controller/concurrency"
+	"github.com/crossplane/provider-azure/pkg/controller/deletion"
+	"github.com/crossplane/provider-azure/pkg/controller/drain"
+	"github.com/crossplane/provider-azure/pkg/controller/jitter"
+	"github.com/crossplane/provider-azure/pkg/controller/pause"
+	"github.com/crossplane/provider-azure/pkg/controller/poll"
+	"github.com/crossplane/provider-azure/pkg/controller/stuck"
+	"github.com/crossplane/provider-azure/pkg/controller/throttle"
+)
+
+// Options configure the reconcilers that wrap the reconciler of each Azure
+// controller.
+type Options struct {
+	// Concurrency limits the reconciles each controller may run concurrently.
+	Concurrency concurrency.Limits
+
+	// RequeueJitter is the maximum fraction of a controller's requeue
+	// interval that is added to it as jitter. Jitter is disabled if it is
+	// not positive.
+	RequeueJitter float64
+
+	// StuckThreshold is the number of consecutive failed reconciles after
+	// which a managed resource is reported as stuck. Stuck resources are not
+	// reported if it is not positive.
+	StuckThreshold int
+
+	// PollInterval is the interval after which a managed resource with a
+	// long-running operation in progress is first polled, and PollMaxInterval
+	// the longest interval it is doubled to. Polling is disabled if either is
+	// not positive.
+	PollInterval    time.Duration
+	PollMaxInterval time.Duration
+
+	// DeleteTimeout is the time after which a managed resource that is still
+	// being deleted is reported with a DeleteTimeout condition. Deletes never
+	// time out if it is not positive.
+	DeleteTimeout time.Duration
+
+	// Drain tracks the in-flight reconciles of each controller, so that they
+	// may finish before the provider stops. It must not be nil.
+	Drain *drain.Tracker
+}
+
+type reconcilerConfig struct {
+	pause      bool
+	inProgress poll.InProgressFn
+	deletion   []deletion.ReconcilerOption
+	stuck      []stuck.ReconcilerOption
+}
+
+// A ReconcilerOption configures the chain of reconcilers built by
+// NewReconciler.
+type ReconcilerOption func(*reconcilerConfig)
+
+// WithPause skips reconciles of managed resources that are paused by
+// annotation.
+func WithPause() ReconcilerOption {
+	return func(c *reconcilerConfig) {
+		c.pause = true
+	}
+}
+
+// WithPolling polls managed resources with exponential backoff while the
+// supplied function reports they have a long-running operation in progress.
+func WithPolling(fn poll.InProgressFn) ReconcilerOption {
+	return func(c *reconcilerConfig) {
+		c.inProgress = fn
+	}
+}
+
+// WithFinalizer specifies the finalizer that is removed from managed resources
+// that are orphaned when their delete times out.
+func WithFinalizer(f string) ReconcilerOption {
+	return func(c *reconcilerConfig) {
+		c.deletion = append(c.deletion, deletion.WithFinalizer(f))
+	}
+}
+
+// WithFailedFn determines whether a reconcile failed using the supplied
+// function when reporting stuck managed resources.
+func WithFailedFn(fn stuck.FailedFn) ReconcilerOption {
+	return func(c *reconcilerConfig) {
+		c.stuck = append(c.stuck, stuck.WithFailedFn(fn))
+	}
+}
+
+// NewReconciler wraps the supplied reconciler of the supplied kind of managed
+// resource. The supplied Throttle should be the one used to classify the
+// errors of the reconciler's Azure requests. From outermost to innermost the
+// reconciler is wrapped by:
+//
+//  1. drain, which lets in-flight reconciles finish before the provider stops.
+//  2. jitter, which adds jitter to requeue intervals.
+//  3. throttle, which backs off from resources that Azure throttles.
+//  4. deletion, which reports resources whose delete has timed out.
+//  5. stuck, which reports resources that repeatedly fail to reconcile.
+//  6. poll, if WithPolling is supplied, which polls resources with a
+//     long-running operation in progress with exponential backoff.
+//  7. pause, if WithPause is supplied, which skips paused resources.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler, t *azure.Throttle, o Options, ro ...ReconcilerOption) reconcile.Reconciler {
+	c := &reconcilerConfig{}
+	for _, fn := range ro {
+		fn(c)
+	}
+
+	if c.pause {
+		r = pause.NewReconciler(m, of, r)
+	}
+	if c.inProgress != nil {
+		r = poll.NewReconciler(m, of, c.inProgress, r, poll.WithIntervals(o.PollInterval, o.PollMaxInterval))
+	}
+	r = stuck.NewReconciler(m, of, r, append(c.stuck, stuck.WithThreshold(o.StuckThreshold))...)
+	r = deletion.NewReconciler(m, of, r, append(c.deletion, deletion.WithTimeout(o.DeleteTimeout))...)
+	r = throttle.NewReconciler(m, of, r, t)
+	r = jitter.NewReconciler(r, o.RequeueJitter)
+	return drain.NewReconciler(r, o.Drain)
+}
+
+type connecterConfig struct {
+	id func(resource.Managed) string
+}
+
+// A ConnecterOption configures the chain of external connecters built by
+// NewConnecter.
+type ConnecterOption func(*connecterConfig)
+
+// WithLocks observes the management locks that apply to the Azure resource
+// identified by the supplied function.
+func WithLocks(id func(resource.Managed) string) ConnecterOption {
+	return func(c *connecterConfig) {
+		c.id = id
+	}
+}
+
+// NewConnecter wraps the supplied external connecter. The supplied Throttle
+// should be the one supplied to NewReconciler. From outermost to innermost the
+// connecter is wrapped by:
+//
+//  1. observe-only, which prevents external resources from being created,
+//     updated, or deleted if observeOnly is true.
+//  2. request ID, which annotates errors with their Azure request IDs.
+//  3. error classifying, which classifies Azure API errors using the Throttle.
+//  4. timeout, which bounds each request by the operation timeout of the
+//     resource's ProviderConfig.
+//  5. lock aware, if WithLocks is supplied, which observes management locks.
+func NewConnecter(kube client.Client, c managed.ExternalConnecter, t *azure.Throttle, observeOnly bool, co ...ConnecterOption) managed.ExternalConnecter {
+	cfg := &connecterConfig{}
+	for _, fn := range co {
+		fn(cfg)
+	}
+
+	if cfg.id != nil {
+		c = azure.NewLockAwareConnecter(c, kube, cfg.id)
+	}
+	c = azure.NewTimeoutConnecter(kube, c)
+	c = azure.NewErrorClassifyingConnecter(c, t)
+	c = azure.NewRequestIDConnecter(c)
+	return azure.NewObserveOnlyConnecter(c, observeOnly)
+}
@@ -21,9 +21,11 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -36,6 +38,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/compute/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/compute"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -43,28 +47,35 @@ const (
 	errGenPassword      = "cannot generate service principal secret"
 	errNotAKSCluster    = "managed resource is not a AKSCluster"
 	errCreateAKSCluster = "cannot create AKSCluster"
+	errUpdateAKSCluster = "cannot update AKSCluster"
 	errGetAKSCluster    = "cannot get AKSCluster"
 	errGetKubeConfig    = "cannot get AKSCluster kubeconfig"
 	errDeleteAKSCluster = "cannot delete AKSCluster"
 )
 
-// SetupAKSCluster adds a controller that reconciles AKSClusters.
-func SetupAKSCluster(mgr ctrl.Manager, l logging.Logger) error {
+// SetupAKSCluster adds a controller that reconciles AKSClusters, ignoring the
+// supplied tags when determining whether they are up to date.
+func SetupAKSCluster(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azure.IgnoredTags) error {
 	name := managed.ControllerName(v1alpha3.AKSClusterGroupKind)
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.AKSCluster{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.AKSClusterList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.AKSClusterGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.AKSClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o))
 }
 
 type connecter struct {
-	client client.Client
+	client      client.Client
+	ignored     azure.IgnoredTags
+	observeOnly bool
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -72,17 +83,19 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl, err := compute.NewAggregateClient(creds, auth)
+	cl, err := compute.NewAggregateClient(creds, auth, c.ignored)
 	if err != nil {
 		return nil, err
 	}
-	return &external{kube: c.client, client: cl, newPasswordFn: password.Generate}, nil
+	return &external{kube: c.client, client: cl, newPasswordFn: password.Generate, ignored: c.ignored, observeOnly: c.observeOnly}, nil
 }
 
 type external struct {
 	kube          client.Client
 	client        compute.AKSClient
 	newPasswordFn func() (password string, err error)
+	ignored       azure.IgnoredTags
+	observeOnly   bool
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -98,13 +111,22 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetAKSCluster)
 	}
+	if err := azure.ValidateOwnership(cr, cr.Status.ProviderID, to.String(c.ID), c.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetAKSCluster)
+	}
 
 	cr.Status.ProviderID = to.String(c.ID)
 	cr.Status.State = to.String(c.ProvisioningState)
 	cr.Status.Endpoint = to.String(c.Fqdn)
+	cr.Status.SKUTier = compute.SKUTier(c)
+	cr.Status.PrivateFQDN = to.String(c.PrivateFQDN)
+	cr.Status.NodeScaleSetEvictionPolicy = compute.NodeScaleSetEvictionPolicy(c)
 
 	if cr.Status.State != "Succeeded" {
-		// AKS clusters are always up to date because we can't yet update them.
+		// We can't update AKS clusters until they're done provisioning.
 		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
 	}
 
@@ -117,13 +139,15 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetAKSCluster)
 	}
+	if cr.Status.PrivateFQDN != "" {
+		cd[compute.ConnectionSecretPrivateFQDNKey] = []byte(cr.Status.PrivateFQDN)
+	}
 
 	cr.SetConditions(runtimev1alpha1.Available())
 
-	// AKS clusters are always up to date because we can't yet update them.
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  true,
+		ResourceUpToDate:  compute.IsUpToDate(cr.Spec.AKSClusterParameters, c, e.ignored),
 		ConnectionDetails: cd,
 	}
 	return o, nil
@@ -143,8 +167,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	// TODO(negz): Support updates.
-	return managed.ExternalUpdate{}, nil
+	cr, ok := mg.(*v1alpha3.AKSCluster)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotAKSCluster)
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(e.client.UpdateManagedCluster(ctx, cr), errUpdateAKSCluster)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
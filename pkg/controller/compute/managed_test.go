@@ -21,7 +21,7 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-03-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
@@ -32,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-azure/apis/compute/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/compute/fake"
 )
 
@@ -55,6 +56,12 @@ func withEndpoint(ep string) modifier {
 	}
 }
 
+func withSKUTier(tier string) modifier {
+	return func(c *v1alpha3.AKSCluster) {
+		c.Status.SKUTier = tier
+	}
+}
+
 func aksCluster(m ...modifier) *v1alpha3.AKSCluster {
 	ac := &v1alpha3.AKSCluster{}
 
@@ -71,6 +78,8 @@ func TestObserve(t *testing.T) {
 	stateSucceeded := "Succeeded"
 	stateWat := "Wat"
 	endpoint := "http://wat.example.org"
+	tierFree := "Free"
+	owned := azure.WithOwnershipTags(nil, v1alpha3.AKSClusterKind, aksCluster())
 
 	type args struct {
 		ctx context.Context
@@ -130,6 +139,23 @@ func TestObserve(t *testing.T) {
 				mg:  aksCluster(),
 			},
 		},
+		"NotOwned": {
+			e: &external{
+				client: fake.AKSClient{
+					MockGetManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error) {
+						return containerservice.ManagedCluster{ID: to.StringPtr(id)}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(),
+			},
+			want: want{
+				mg:  aksCluster(),
+				err: errors.Wrap(azure.ValidateOwnership(aksCluster(), "", id, nil), errGetAKSCluster),
+			},
+		},
 		"NotReady": {
 			e: &external{
 				client: fake.AKSClient{
@@ -146,7 +172,7 @@ func TestObserve(t *testing.T) {
 			},
 			args: args{
 				ctx: context.Background(),
-				mg:  aksCluster(),
+				mg:  aksCluster(withProviderID(id)),
 			},
 			want: want{
 				eo: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
@@ -154,6 +180,7 @@ func TestObserve(t *testing.T) {
 					withProviderID(id),
 					withState(stateWat),
 					withEndpoint(endpoint),
+					withSKUTier(tierFree),
 				),
 			},
 		},
@@ -161,7 +188,7 @@ func TestObserve(t *testing.T) {
 			e: &external{
 				client: fake.AKSClient{
 					MockGetManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error) {
-						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+						return containerservice.ManagedCluster{Tags: owned, ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 							ProvisioningState: to.StringPtr(stateSucceeded),
 						}}, nil
 					},
@@ -177,6 +204,7 @@ func TestObserve(t *testing.T) {
 			want: want{
 				mg: aksCluster(
 					withState(stateSucceeded),
+					withSKUTier(tierFree),
 				),
 				err: errors.Wrap(errBoom, errGetKubeConfig),
 			},
@@ -272,6 +300,66 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		e    managed.ExternalClient
+		args args
+		want error
+	}{
+		"ErrNotAKSCluster": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: errors.New(errNotAKSCluster),
+		},
+		"ErrUpdateCluster": {
+			e: &external{
+				client: fake.AKSClient{
+					MockUpdateManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) error {
+						return errBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(),
+			},
+			want: errors.Wrap(errBoom, errUpdateAKSCluster),
+		},
+		"Success": {
+			e: &external{
+				client: fake.AKSClient{
+					MockUpdateManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, got := tc.e.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	errBoom := errors.New("boom")
 
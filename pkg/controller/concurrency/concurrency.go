@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package concurrency determines how many reconciles each controller may run
+// concurrently.
+package concurrency
+
+import (
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// DefaultMaxConcurrentReconciles is the default maximum number of reconciles
+// each controller may run concurrently.
+const DefaultMaxConcurrentReconciles = 1
+
+// Limits determine how many reconciles each controller may run concurrently.
+type Limits struct {
+	// Max is the maximum number of reconciles each controller may run
+	// concurrently, unless overridden. DefaultMaxConcurrentReconciles is
+	// used if it is not positive.
+	Max int
+
+	// Overrides maps the names of controllers to the maximum number of
+	// reconciles they may run concurrently. Controllers of managed resources
+	// may be named either by their full name (e.g.
+	// managed/redis.cache.azure.crossplane.io) or by the group kind they
+	// reconcile (e.g. redis.cache.azure.crossplane.io).
+	Overrides map[string]int
+}
+
+// Options returns the options of the named controller.
+func (l Limits) Options(name string) controller.Options {
+	return controller.Options{MaxConcurrentReconciles: l.maxConcurrentReconciles(name)}
+}
+
+func (l Limits) maxConcurrentReconciles(name string) int {
+	n, ok := l.Overrides[name]
+	if !ok {
+		n, ok = l.Overrides[name[strings.Index(name, "/")+1:]]
+	}
+	if !ok || n < 1 {
+		n = l.Max
+	}
+	if n < 1 {
+		return DefaultMaxConcurrentReconciles
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+func TestOptions(t *testing.T) {
+	cases := map[string]struct {
+		max       int
+		overrides map[string]int
+		name      string
+		want      controller.Options
+	}{
+		"Default": {
+			max:  DefaultMaxConcurrentReconciles,
+			name: "managed/redis.cache.azure.crossplane.io",
+			want: controller.Options{MaxConcurrentReconciles: 1},
+		},
+		"Configured": {
+			max:  4,
+			name: "managed/redis.cache.azure.crossplane.io",
+			want: controller.Options{MaxConcurrentReconciles: 4},
+		},
+		"NotPositive": {
+			max:  0,
+			name: "managed/redis.cache.azure.crossplane.io",
+			want: controller.Options{MaxConcurrentReconciles: 1},
+		},
+		"OverriddenByName": {
+			max:       2,
+			overrides: map[string]int{"managed/redis.cache.azure.crossplane.io": 8},
+			name:      "managed/redis.cache.azure.crossplane.io",
+			want:      controller.Options{MaxConcurrentReconciles: 8},
+		},
+		"OverriddenByGroupKind": {
+			max:       2,
+			overrides: map[string]int{"redis.cache.azure.crossplane.io": 8},
+			name:      "managed/redis.cache.azure.crossplane.io",
+			want:      controller.Options{MaxConcurrentReconciles: 8},
+		},
+		"OtherOverridden": {
+			max:       2,
+			overrides: map[string]int{"subnet.network.azure.crossplane.io": 8},
+			name:      "managed/redis.cache.azure.crossplane.io",
+			want:      controller.Options{MaxConcurrentReconciles: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := Limits{Max: tc.max, Overrides: tc.overrides}
+			got := l.Options(tc.name)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("l.Options(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
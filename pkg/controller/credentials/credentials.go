@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials enqueues managed resources for reconciliation when the
+// credentials they use to connect to Azure change.
+package credentials
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+const timeout = 1 * time.Minute
+
+// IndexCredentialsSecret is the field index by which ProviderConfigs and
+// Providers may be listed by the namespace and name of the Secret their
+// credentials are read from.
+const IndexCredentialsSecret = "credentialsSecret"
+
+// Error strings.
+const (
+	errIndexProviderConfigs = "cannot index ProviderConfigs by credentials secret"
+	errIndexProviders       = "cannot index Providers by credentials secret"
+)
+
+// Setup indexes ProviderConfigs and Providers by the Secret their credentials
+// are read from, so that EnqueueRequestForSecret may cheaply determine which
+// of them use a Secret.
+func Setup(mgr ctrl.Manager, _ logging.Logger) error {
+	i := mgr.GetFieldIndexer()
+	if err := i.IndexField(context.Background(), &v1beta1.ProviderConfig{}, IndexCredentialsSecret, indexProviderConfig); err != nil {
+		return errors.Wrap(err, errIndexProviderConfigs)
+	}
+	return errors.Wrap(i.IndexField(context.Background(), &v1alpha3.Provider{}, IndexCredentialsSecret, indexProvider), errIndexProviders)
+}
+
+func indexProviderConfig(o runtime.Object) []string {
+	pc, ok := o.(*v1beta1.ProviderConfig)
+	if !ok || pc.Spec.Credentials.SecretRef == nil {
+		return nil
+	}
+	ref := pc.Spec.Credentials.SecretRef
+	return []string{key(ref.Namespace, ref.Name)}
+}
+
+func indexProvider(o runtime.Object) []string {
+	p, ok := o.(*v1alpha3.Provider)
+	if !ok {
+		return nil
+	}
+	ref := p.Spec.CredentialsSecretRef
+	return []string{key(ref.Namespace, ref.Name)}
+}
+
+func key(namespace, name string) string {
+	return types.NamespacedName{Namespace: namespace, Name: name}.String()
+}
+
+// EnqueueRequestForSecret returns an EventHandler that enqueues a reconcile
+// of each managed resource of the supplied list's kind that uses a
+// ProviderConfig or Provider whose credentials are read from a Secret when
+// that Secret changes. This lets resources recover as soon as bad credentials
+// are fixed, rather than at their next poll. The supplied reader must support
+// the field index added by Setup.
+func EnqueueRequestForSecret(c client.Reader, l resource.ManagedList, log logging.Logger) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{ToRequests: &mapper{client: c, list: l, log: log}}
+}
+
+type mapper struct {
+	client client.Reader
+	list   resource.ManagedList
+	log    logging.Logger
+}
+
+// Map the supplied Secret to the managed resources that use it. Errors are
+// logged; affected resources will be reconciled at their next poll.
+func (m *mapper) Map(o handler.MapObject) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	secret := key(o.Meta.GetNamespace(), o.Meta.GetName())
+	log := m.log.WithValues("secret", secret)
+
+	configs := map[string]bool{}
+	pcl := &v1beta1.ProviderConfigList{}
+	if err := m.client.List(ctx, pcl, client.MatchingFields{IndexCredentialsSecret: secret}); err != nil {
+		log.Info("Cannot list ProviderConfigs that use Secret", "error", err)
+	}
+	for _, pc := range pcl.Items {
+		configs[pc.GetName()] = true
+	}
+
+	providers := map[string]bool{}
+	pl := &v1alpha3.ProviderList{}
+	if err := m.client.List(ctx, pl, client.MatchingFields{IndexCredentialsSecret: secret}); err != nil {
+		log.Info("Cannot list Providers that use Secret", "error", err)
+	}
+	for _, p := range pl.Items {
+		providers[p.GetName()] = true
+	}
+
+	if len(configs) == 0 && len(providers) == 0 {
+		return nil
+	}
+
+	l, ok := m.list.DeepCopyObject().(resource.ManagedList)
+	if !ok {
+		return nil
+	}
+	if err := m.client.List(ctx, l); err != nil {
+		log.Info("Cannot list managed resources that may use Secret", "error", err)
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for _, mg := range l.GetItems() {
+		pcr, pr := mg.GetProviderConfigReference(), mg.GetProviderReference()
+		if (pcr != nil && configs[pcr.Name]) || (pcr == nil && pr != nil && providers[pr.Name]) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: mg.GetName()}})
+		}
+	}
+	return reqs
+}
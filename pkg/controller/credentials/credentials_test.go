@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	networkv1alpha3 "github.com/crossplane/provider-azure/apis/network/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	"github.com/crossplane/provider-azure/apis/v1beta1"
+)
+
+const (
+	namespace = "crossplane-system"
+	secret    = "azure-creds"
+)
+
+func subnet(name string, pc, p string) networkv1alpha3.Subnet {
+	s := networkv1alpha3.Subnet{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if pc != "" {
+		s.SetProviderConfigReference(&runtimev1alpha1.Reference{Name: pc})
+	}
+	if p != "" {
+		s.SetProviderReference(&runtimev1alpha1.Reference{Name: p})
+	}
+	return s
+}
+
+func TestMap(t *testing.T) {
+	ref := &runtimev1alpha1.SecretKeySelector{SecretReference: runtimev1alpha1.SecretReference{Namespace: namespace, Name: secret}, Key: "creds"}
+	other := &runtimev1alpha1.SecretKeySelector{SecretReference: runtimev1alpha1.SecretReference{Namespace: namespace, Name: "other"}, Key: "creds"}
+
+	list := func(pcs []v1beta1.ProviderConfig, ps []v1alpha3.Provider, subnets []networkv1alpha3.Subnet, err error) client.Reader {
+		return &test.MockClient{
+			MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				matches := func(o runtime.Object, index func(runtime.Object) []string) bool {
+					if lo.FieldSelector == nil {
+						return true
+					}
+					for _, v := range index(o) {
+						if lo.FieldSelector.Matches(fields.Set{IndexCredentialsSecret: v}) {
+							return true
+						}
+					}
+					return false
+				}
+				switch l := obj.(type) {
+				case *v1beta1.ProviderConfigList:
+					for i := range pcs {
+						if matches(&pcs[i], indexProviderConfig) {
+							l.Items = append(l.Items, pcs[i])
+						}
+					}
+				case *v1alpha3.ProviderList:
+					for i := range ps {
+						if matches(&ps[i], indexProvider) {
+							l.Items = append(l.Items, ps[i])
+						}
+					}
+				case *networkv1alpha3.SubnetList:
+					l.Items = subnets
+					return err
+				}
+				return nil
+			},
+		}
+	}
+	pc := func(name string, ref *runtimev1alpha1.SecretKeySelector) v1beta1.ProviderConfig {
+		c := v1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		c.Spec.Credentials.SecretRef = ref
+		return c
+	}
+	p := func(name string, ref *runtimev1alpha1.SecretKeySelector) v1alpha3.Provider {
+		c := v1alpha3.Provider{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		c.Spec.CredentialsSecretRef = *ref
+		return c
+	}
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   []reconcile.Request
+	}{
+		"UnusedSecret": {
+			reason: "Secrets that are not used by any ProviderConfig or Provider should not enqueue requests.",
+			client: list([]v1beta1.ProviderConfig{pc("default", other)}, nil, []networkv1alpha3.Subnet{subnet("a", "default", "")}, nil),
+		},
+		"ProviderConfigSecret": {
+			reason: "Resources using a ProviderConfig that uses the Secret should be enqueued.",
+			client: list(
+				[]v1beta1.ProviderConfig{pc("default", ref), pc("unrelated", other)},
+				nil,
+				[]networkv1alpha3.Subnet{subnet("a", "default", ""), subnet("b", "unrelated", "")},
+				nil),
+			want: []reconcile.Request{req("a")},
+		},
+		"ProviderSecret": {
+			reason: "Resources using a Provider that uses the Secret should be enqueued, unless they use a ProviderConfig.",
+			client: list(
+				[]v1beta1.ProviderConfig{pc("unrelated", other)},
+				[]v1alpha3.Provider{p("legacy", ref)},
+				[]networkv1alpha3.Subnet{subnet("a", "", "legacy"), subnet("b", "unrelated", "legacy")},
+				nil),
+			want: []reconcile.Request{req("a")},
+		},
+		"ListManagedError": {
+			reason: "Errors listing managed resources should not enqueue requests.",
+			client: list([]v1beta1.ProviderConfig{pc("default", ref)}, nil, []networkv1alpha3.Subnet{subnet("a", "default", "")}, errors.New("boom")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mapper{client: tc.client, list: &networkv1alpha3.SubnetList{}, log: logging.NewNopLogger()}
+			s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secret}}
+			got := m.Map(handler.MapObject{Meta: s, Object: s})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -22,8 +22,10 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +37,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database/cosmosdb"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings
@@ -46,23 +50,27 @@ const (
 )
 
 // Setup adds a controller that reconciles NoSQLAccount.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
 	name := managed.ControllerName(v1alpha3.CosmosDBAccountGroupKind)
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.CosmosDBAccount{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.CosmosDBAccountList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.CosmosDBAccountGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.CosmosDBAccountGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{kube: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{kube: mgr.GetClient(), observeOnly: observeOnly}, throttled, observeOnly)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o))
 }
 
 type connecter struct {
-	kube client.Client
+	kube        client.Client
+	observeOnly bool
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -72,13 +80,14 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 	cl := documentdb.NewDatabaseAccountsClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{kube: c.kube, client: cl}, nil
+	return &external{kube: c.kube, client: cl, observeOnly: c.observeOnly}, nil
 }
 
 // external is a createsyncdeleter using the Azure API.
 type external struct {
-	kube   client.Client
-	client cosmosdb.AccountClient
+	kube        client.Client
+	client      cosmosdb.AccountClient
+	observeOnly bool
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -99,6 +108,16 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetNoSQLAccount)
 	}
+	var recorded string
+	if r.Status.AtProvider != nil {
+		recorded = r.Status.AtProvider.ID
+	}
+	if err := azure.ValidateOwnership(r, recorded, azure.ToString(account.ID), account.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(r) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetNoSQLAccount)
+	}
 	cosmosdb.UpdateCosmosDBAccountObservation(&r.Status, account)
 
 	switch r.Status.AtProvider.State {
@@ -118,10 +137,12 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	r.Status.SetConditions(runtimev1alpha1.Creating())
+	p := cosmosdb.ToDatabaseAccountCreateOrUpdate(&r.Spec)
+	p.Tags = azure.WithOwnershipTags(p.Tags, v1alpha3.CosmosDBAccountKind, r)
 	_, err := e.client.CreateOrUpdate(ctx,
 		r.Spec.ForProvider.ResourceGroupName,
 		meta.GetExternalName(r),
-		cosmosdb.ToDatabaseAccountCreateOrUpdate(&r.Spec))
+		p)
 	// TODO(artursouza): handle secrets.
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateNoSQLAccount)
 }
@@ -28,8 +28,10 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,6 +39,7 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/database/v1beta1"
 	azurev1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
 )
 
@@ -51,6 +54,9 @@ type MockMySQLServerAPI struct {
 	MockCreateServer  func(ctx context.Context, s *v1beta1.MySQLServer, adminPassword string) error
 	MockUpdateServer  func(ctx context.Context, s *v1beta1.MySQLServer) error
 	MockDeleteServer  func(ctx context.Context, s *v1beta1.MySQLServer) error
+	MockRestartServer func(ctx context.Context, s *v1beta1.MySQLServer) error
+	MockUpgradeServer func(ctx context.Context, s *v1beta1.MySQLServer) error
+	MockListReplicas  func(ctx context.Context, s *v1beta1.MySQLServer) (mysql.ServerListResult, error)
 	MockGetRESTClient func() autorest.Sender
 }
 
@@ -74,6 +80,18 @@ func (m *MockMySQLServerAPI) DeleteServer(ctx context.Context, s *v1beta1.MySQLS
 	return m.MockDeleteServer(ctx, s)
 }
 
+func (m *MockMySQLServerAPI) RestartServer(ctx context.Context, s *v1beta1.MySQLServer) error {
+	return m.MockRestartServer(ctx, s)
+}
+
+func (m *MockMySQLServerAPI) ListReplicas(ctx context.Context, s *v1beta1.MySQLServer) (mysql.ServerListResult, error) {
+	return m.MockListReplicas(ctx, s)
+}
+
+func (m *MockMySQLServerAPI) UpgradeServer(ctx context.Context, s *v1beta1.MySQLServer) error {
+	return m.MockUpgradeServer(ctx, s)
+}
+
 type modifier func(*v1beta1.MySQLServer)
 
 func withExternalName(name string) modifier {
@@ -88,12 +106,44 @@ func withAdminName(name string) modifier {
 	}
 }
 
+func withConnectionSecretKeys(k map[string]string) modifier {
+	return func(p *v1beta1.MySQLServer) {
+		p.Spec.ConnectionSecretKeys = k
+	}
+}
+
 func withLastOperation(op azurev1alpha3.AsyncOperation) modifier {
 	return func(p *v1beta1.MySQLServer) {
 		p.Status.AtProvider.LastOperation = op
 	}
 }
 
+func withUserVisibleState(state string) modifier {
+	return func(p *v1beta1.MySQLServer) {
+		p.Status.AtProvider.UserVisibleState = state
+	}
+}
+
+func withVersion(desired, observed string) modifier {
+	return func(p *v1beta1.MySQLServer) {
+		p.Spec.ForProvider.Version = desired
+		p.Status.AtProvider.Version = observed
+	}
+}
+
+func withInfrastructureEncryption(desired, observed string) modifier {
+	return func(p *v1beta1.MySQLServer) {
+		p.Spec.ForProvider.InfrastructureEncryption = &desired
+		p.Status.AtProvider.InfrastructureEncryption = observed
+	}
+}
+
+func withRestartRequested() modifier {
+	return func(p *v1beta1.MySQLServer) {
+		meta.AddAnnotations(p, map[string]string{database.AnnotationKeyRestart: "now"})
+	}
+}
+
 func mysqlserver(m ...modifier) *v1beta1.MySQLServer {
 	p := &v1beta1.MySQLServer{}
 
@@ -108,10 +158,14 @@ const (
 )
 
 func TestObserve(t *testing.T) {
+	owned := azure.WithOwnershipTags(nil, v1beta1.MySQLServerKind, mysqlserver())
 	errBoom := errors.New("boom")
 	name := "coolserver"
 	endpoint := "coolazure.example.prg"
 	admin := "cooladmin"
+	noReplicas := func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.ServerListResult, error) {
+		return mysql.ServerListResult{}, nil
+	}
 
 	type args struct {
 		ctx context.Context
@@ -203,19 +257,260 @@ func TestObserve(t *testing.T) {
 		},
 		"ServerAvailable": {
 			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"ServerNotReady": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateInaccessible,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("false"),
+					},
+				},
+			},
+		},
+		"ReadReplicas": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.ServerListResult, error) {
+						return mysql.ServerListResult{Value: &[]mysql.Server{
+							{ServerProperties: &mysql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("replica-b.example.org")}},
+							{ServerProperties: &mysql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("replica-a.example.org")}},
+						}}, nil
+					},
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte("replica-a.example.org,replica-b.example.org"),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"ListReplicasFailed": {
+			e: &external{
+				ca:     database.DefaultCABundle(),
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.ServerListResult, error) {
+						return mysql.ServerListResult{}, errBoom
+					},
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"ConnectionSecretKeysRenamed": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+					withConnectionSecretKeys(map[string]string{runtimev1alpha1.ResourceCredentialsSecretEndpointKey: "DB_HOST"}),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						"DB_HOST": []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey: []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:               database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:        []byte{},
+						azure.ConnectionSecretReadyKey:                   []byte("true"),
+					},
+				},
+			},
+		},
+		"RestartRequested": {
+			e: &external{
+				ca: database.DefaultCABundle(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &MockMySQLServerAPI{
 					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
 						return mysql.Server{
-							Sku: &mysql.Sku{},
+							Tags: owned,
+							Sku:  &mysql.Sku{},
 							ServerProperties: &mysql.ServerProperties{
 								UserVisibleState:         mysql.ServerStateReady,
 								FullyQualifiedDomainName: &endpoint,
 								StorageProfile:           &mysql.StorageProfile{},
 							}}, nil
 					},
+					MockListReplicas: noReplicas,
 					MockGetRESTClient: func() autorest.Sender {
 						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
 							return nil, nil
@@ -228,6 +523,60 @@ func TestObserve(t *testing.T) {
 				mg: mysqlserver(
 					withExternalName(name),
 					withAdminName(admin),
+					withRestartRequested(),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"RestartCompleted": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+						if database.RestartRequested(obj.(*v1beta1.MySQLServer)) {
+							return errBoom
+						}
+						return nil
+					}),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Tags: owned,
+							Sku:  &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+					withRestartRequested(),
+					withLastOperation(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Succeeded"}),
 				),
 			},
 			want: want{
@@ -237,10 +586,32 @@ func TestObserve(t *testing.T) {
 					ConnectionDetails: managed.ConnectionDetails{
 						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
 						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
 					},
 				},
 			},
 		},
+		"RestartFailed": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{Tags: owned}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withRestartRequested(),
+					withLastOperation(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"}),
+				),
+			},
+			want: want{
+				err: errors.Wrap(database.RestartError(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"}), errRestartMySQLServer),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -284,6 +655,16 @@ func TestCreate(t *testing.T) {
 				err: errors.New(errNotMySQLServer),
 			},
 		},
+		"ErrReservedAdministratorLogin": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withAdminName("root")),
+			},
+			want: want{
+				err: errors.Wrap(database.ValidateMySQLAdministratorLogin("root"), errCreateMySQLServer),
+			},
+		},
 		"ErrGeneratePassword": {
 			e: &external{
 				newPasswordFn: func() (string, error) { return "", errBoom },
@@ -349,6 +730,143 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		e    managed.ExternalClient
+		args args
+		want error
+	}{
+		"ErrNotAMySQLServer": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: errors.New(errNotMySQLServer),
+		},
+		"OperationInProgress": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withLastOperation(azurev1alpha3.AsyncOperation{Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: nil,
+		},
+		"ServerDisabled": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withUserVisibleState(v1beta1.StateDisabled)),
+			},
+			want: nil,
+		},
+		"ErrRestartServer": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockRestartServer: func(_ context.Context, _ *v1beta1.MySQLServer) error { return errBoom },
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withRestartRequested()),
+			},
+			want: errors.Wrap(errBoom, errRestartMySQLServer),
+		},
+		"SuccessfulRestart": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockRestartServer: func(_ context.Context, _ *v1beta1.MySQLServer) error { return nil },
+					MockUpdateServer:  func(_ context.Context, _ *v1beta1.MySQLServer) error { return errBoom },
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withRestartRequested()),
+			},
+			want: nil,
+		},
+		"ErrDowngradeServer": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withVersion("5.6", "5.7")),
+			},
+			want: errors.Wrap(errors.Errorf("cannot upgrade server from version %q to %q: only upgrades from MySQL 5.6 to 5.7 are supported", "5.7", "5.6"), errUpgradeMySQLServer),
+		},
+		"ErrUpgradeServer": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockUpgradeServer: func(_ context.Context, _ *v1beta1.MySQLServer) error { return errBoom },
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withVersion("5.7", "5.6")),
+			},
+			want: errors.Wrap(errBoom, errUpgradeMySQLServer),
+		},
+		"SuccessfulUpgrade": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockUpgradeServer: func(_ context.Context, _ *v1beta1.MySQLServer) error { return nil },
+					MockUpdateServer:  func(_ context.Context, _ *v1beta1.MySQLServer) error { return errBoom },
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withVersion("5.7", "5.6")),
+			},
+			want: nil,
+		},
+		"ErrChangeInfrastructureEncryption": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(withInfrastructureEncryption("Enabled", "Disabled")),
+			},
+			want: errors.Wrap(database.ValidateInfrastructureEncryption(v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")}, "Disabled"), errUpdateMySQLServer),
+		},
+		"ErrUpdateServer": {
+			e: &external{
+				client: &MockMySQLServerAPI{
+					MockUpdateServer: func(_ context.Context, _ *v1beta1.MySQLServer) error { return errBoom },
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mysqlserver(),
+			},
+			want: errors.Wrap(errBoom, errUpdateMySQLServer),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.e.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -22,8 +22,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql/mysqlapi"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +37,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -47,19 +51,22 @@ const (
 )
 
 // Setup adds a controller that reconciles MySQLServerFirewallRules.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
 	name := managed.ControllerName(v1alpha3.MySQLServerFirewallRuleGroupKind)
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.MySQLServerFirewallRule{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.MySQLServerFirewallRuleList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.MySQLServerFirewallRuleGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.MySQLServerFirewallRuleGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient()}, throttled, observeOnly)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o))
 }
 
 type connecter struct {
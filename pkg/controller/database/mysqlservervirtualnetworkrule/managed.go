@@ -18,12 +18,15 @@ package mysqlservervirtualnetworkrule
 
 import (
 	"context"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql/mysqlapi"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +38,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -44,26 +49,39 @@ const (
 	errUpdateMySQLServerVirtualNetworkRule = "cannot update MySQLServerVirtualNetworkRule"
 	errGetMySQLServerVirtualNetworkRule    = "cannot get MySQLServerVirtualNetworkRule"
 	errDeleteMySQLServerVirtualNetworkRule = "cannot delete MySQLServerVirtualNetworkRule"
+	errListMySQLServerVirtualNetworkRules  = "cannot list MySQLServerVirtualNetworkRules"
 )
 
-// Setup adds a controller that reconciles MySQLServerVirtualNetworkRules.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles MySQLServerVirtualNetworkRules. The
+// virtual network rules of each server are cached for the supplied TTL after
+// they are listed. Caching is disabled if the TTL is not positive.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ttl time.Duration) error {
 	name := managed.ControllerName(v1alpha3.MySQLServerVirtualNetworkRuleGroupKind)
 
+	var rules *database.ListCache
+	if ttl > 0 {
+		rules = database.NewListCache(ttl)
+	}
+
+	throttled := azure.NewThrottle()
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.MySQLServerVirtualNetworkRule{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.MySQLServerVirtualNetworkRuleList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.MySQLServerVirtualNetworkRuleGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.MySQLServerVirtualNetworkRuleGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), rules: rules}, throttled, observeOnly)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
 }
 
 type connecter struct {
 	client client.Client
+	rules  *database.ListCache
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -74,11 +92,48 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 
 	cl := mysql.NewVirtualNetworkRulesClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	return &external{client: cl, rules: c.rules, subscriptionID: creds[azure.CredentialsKeySubscriptionID]}, nil
 }
 
 type external struct {
 	client mysqlapi.VirtualNetworkRulesClientAPI
+
+	// rules caches the virtual network rules of each server, if non-nil.
+	rules          *database.ListCache
+	subscriptionID string
+}
+
+// get returns the supplied virtual network rule, and whether it exists. The
+// rule is read from the cached virtual network rules of its server, if any.
+func (e *external) get(ctx context.Context, v *v1alpha3.MySQLServerVirtualNetworkRule) (mysql.VirtualNetworkRule, bool, error) {
+	if e.rules == nil {
+		az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
+		if azure.IsNotFound(err) {
+			return mysql.VirtualNetworkRule{}, false, nil
+		}
+		return az, err == nil, err
+	}
+
+	key := database.ServerKey(e.subscriptionID, v.Spec.ResourceGroupName, v.Spec.ServerName)
+	az, ok, err := e.rules.Get(key, meta.GetExternalName(v), func() (map[string]interface{}, error) {
+		rules := map[string]interface{}{}
+		it, err := e.client.ListByServerComplete(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName)
+		for ; err == nil && it.NotDone(); err = it.NextWithContext(ctx) {
+			rules[azure.ToString(it.Value().Name)] = it.Value()
+		}
+		return rules, errors.Wrap(resource.Ignore(azure.IsNotFound, err), errListMySQLServerVirtualNetworkRules)
+	})
+	if err != nil || !ok {
+		return mysql.VirtualNetworkRule{}, false, err
+	}
+	return az.(mysql.VirtualNetworkRule), true, nil
+}
+
+// invalidate any cached virtual network rules of the supplied rule's server.
+func (e *external) invalidate(v *v1alpha3.MySQLServerVirtualNetworkRule) {
+	if e.rules != nil {
+		e.rules.Invalidate(database.ServerKey(e.subscriptionID, v.Spec.ResourceGroupName, v.Spec.ServerName))
+	}
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -87,13 +142,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotMySQLServerVirtualNetworkRule)
 	}
 
-	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
-	if azure.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
-	}
+	az, exists, err := e.get(ctx, v)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetMySQLServerVirtualNetworkRule)
 	}
+	if !exists {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 
 	database.UpdateMySQLVirtualNetworkRuleStatusFromAzure(v, az)
 	v.SetConditions(runtimev1alpha1.Available())
@@ -114,6 +169,18 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.SetConditions(runtimev1alpha1.Creating())
 
+	// Virtual network rules cannot be tagged with their owner, so we make sure
+	// we're not about to overwrite a rule that something else created. We
+	// bypass the cache, which may predate the rule.
+	_, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
+	if err != nil && !azure.IsNotFound(err) {
+		return managed.ExternalCreation{}, errors.Wrap(err, errGetMySQLServerVirtualNetworkRule)
+	}
+	if err := azure.ValidateCreate(v, err == nil); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateMySQLServerVirtualNetworkRule)
+	}
+
+	e.invalidate(v)
 	vnet := database.NewMySQLVirtualNetworkRuleParameters(v)
 	if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateMySQLServerVirtualNetworkRule)
@@ -128,12 +195,13 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotMySQLServerVirtualNetworkRule)
 	}
 
-	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
+	az, exists, err := e.get(ctx, v)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetMySQLServerVirtualNetworkRule)
 	}
 
-	if database.MySQLServerVirtualNetworkRuleNeedsUpdate(v, az) {
+	if !exists || database.MySQLServerVirtualNetworkRuleNeedsUpdate(v, az) {
+		e.invalidate(v)
 		vnet := database.NewMySQLVirtualNetworkRuleParameters(v)
 		if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet); err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateMySQLServerVirtualNetworkRule)
@@ -149,6 +217,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 
 	v.SetConditions(runtimev1alpha1.Deleting())
+	e.invalidate(v)
 
 	_, err := e.client.Delete(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
 	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteMySQLServerVirtualNetworkRule)
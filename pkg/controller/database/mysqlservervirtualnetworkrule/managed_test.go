@@ -20,6 +20,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/go-autorest/autorest"
@@ -35,6 +36,7 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/database"
 	"github.com/crossplane/provider-azure/pkg/clients/fake"
 )
 
@@ -79,6 +81,10 @@ func withState(s string) virtualNetworkRuleModifier {
 	return func(r *v1alpha3.MySQLServerVirtualNetworkRule) { r.Status.State = s }
 }
 
+func withAnnotations(a map[string]string) virtualNetworkRuleModifier {
+	return func(r *v1alpha3.MySQLServerVirtualNetworkRule) { meta.AddAnnotations(r, a) }
+}
+
 func virtualNetworkRule(sm ...virtualNetworkRuleModifier) *v1alpha3.MySQLServerVirtualNetworkRule {
 	r := &v1alpha3.MySQLServerVirtualNetworkRule{
 		ObjectMeta: metav1.ObjectMeta{
@@ -106,6 +112,22 @@ func virtualNetworkRule(sm ...virtualNetworkRuleModifier) *v1alpha3.MySQLServerV
 	return r
 }
 
+// listRules returns a MockListByServerComplete that lists the supplied rules.
+func listRules(rules ...mysql.VirtualNetworkRule) func(context.Context, string, string) (mysql.VirtualNetworkRuleListResultIterator, error) {
+	return func(ctx context.Context, _ string, _ string) (mysql.VirtualNetworkRuleListResultIterator, error) {
+		listed := false
+		page := mysql.NewVirtualNetworkRuleListResultPage(func(context.Context, mysql.VirtualNetworkRuleListResult) (mysql.VirtualNetworkRuleListResult, error) {
+			if listed {
+				return mysql.VirtualNetworkRuleListResult{}, nil
+			}
+			listed = true
+			return mysql.VirtualNetworkRuleListResult{Value: &rules}, nil
+		})
+		err := page.NextWithContext(ctx)
+		return mysql.NewVirtualNetworkRuleListResultIterator(page), err
+	}
+}
+
 // Test that our Reconciler implementation satisfies the Reconciler interface.
 var _ managed.ExternalClient = &external{}
 var _ managed.ExternalConnecter = &connecter{}
@@ -122,6 +144,11 @@ func TestCreate(t *testing.T) {
 		{
 			name: "SuccessfulCreate",
 			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result mysql.VirtualNetworkRule, err error) {
+					return mysql.VirtualNetworkRule{}, autorest.DetailedError{
+						StatusCode: http.StatusNotFound,
+					}
+				},
 				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ mysql.VirtualNetworkRule) (mysql.VirtualNetworkRulesCreateOrUpdateFuture, error) {
 					return mysql.VirtualNetworkRulesCreateOrUpdateFuture{}, nil
 				},
@@ -134,6 +161,11 @@ func TestCreate(t *testing.T) {
 		{
 			name: "FailedCreate",
 			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result mysql.VirtualNetworkRule, err error) {
+					return mysql.VirtualNetworkRule{}, autorest.DetailedError{
+						StatusCode: http.StatusNotFound,
+					}
+				},
 				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ mysql.VirtualNetworkRule) (mysql.VirtualNetworkRulesCreateOrUpdateFuture, error) {
 					return mysql.VirtualNetworkRulesCreateOrUpdateFuture{}, errorBoom
 				},
@@ -144,6 +176,48 @@ func TestCreate(t *testing.T) {
 			),
 			wantErr: errors.Wrap(errorBoom, errCreateMySQLServerVirtualNetworkRule),
 		},
+		{
+			name: "FailedGet",
+			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result mysql.VirtualNetworkRule, err error) {
+					return mysql.VirtualNetworkRule{}, errorBoom
+				},
+			}},
+			r: virtualNetworkRule(),
+			want: virtualNetworkRule(
+				withConditions(runtimev1alpha1.Creating()),
+			),
+			wantErr: errors.Wrap(errorBoom, errGetMySQLServerVirtualNetworkRule),
+		},
+		{
+			name: "AlreadyExists",
+			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result mysql.VirtualNetworkRule, err error) {
+					return mysql.VirtualNetworkRule{}, nil
+				},
+			}},
+			r: virtualNetworkRule(),
+			want: virtualNetworkRule(
+				withConditions(runtimev1alpha1.Creating()),
+			),
+			wantErr: errors.Wrap(azure.ValidateCreate(virtualNetworkRule(), true), errCreateMySQLServerVirtualNetworkRule),
+		},
+		{
+			name: "AdoptExisting",
+			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result mysql.VirtualNetworkRule, err error) {
+					return mysql.VirtualNetworkRule{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ mysql.VirtualNetworkRule) (mysql.VirtualNetworkRulesCreateOrUpdateFuture, error) {
+					return mysql.VirtualNetworkRulesCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r: virtualNetworkRule(withAnnotations(map[string]string{azure.AnnotationKeyAdopt: "true"})),
+			want: virtualNetworkRule(
+				withAnnotations(map[string]string{azure.AnnotationKeyAdopt: "true"}),
+				withConditions(runtimev1alpha1.Creating()),
+			),
+		},
 	}
 
 	for _, tc := range cases {
@@ -205,6 +279,50 @@ func TestObserve(t *testing.T) {
 				withID(resourceID),
 			),
 		},
+		{
+			name: "SuccessfulObserveCached",
+			e: &external{rules: database.NewListCache(time.Hour), client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockListByServerComplete: listRules(
+					mysql.VirtualNetworkRule{Name: azure.ToStringPtr("other")},
+					mysql.VirtualNetworkRule{
+						Name: azure.ToStringPtr(name),
+						ID:   azure.ToStringPtr(resourceID),
+						Type: azure.ToStringPtr(resourceType),
+						VirtualNetworkRuleProperties: &mysql.VirtualNetworkRuleProperties{
+							VirtualNetworkSubnetID:           azure.ToStringPtr(vnetSubnetID),
+							IgnoreMissingVnetServiceEndpoint: azure.ToBoolPtr(true),
+							State:                            mysql.VirtualNetworkRuleStateReady,
+						},
+					},
+				),
+			}},
+			r: virtualNetworkRule(),
+			want: virtualNetworkRule(
+				withConditions(runtimev1alpha1.Available()),
+				withState(string(mysql.Ready)),
+				withType(resourceType),
+				withID(resourceID),
+			),
+		},
+		{
+			name: "SuccessfulObserveCachedNotExist",
+			e: &external{rules: database.NewListCache(time.Hour), client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockListByServerComplete: listRules(mysql.VirtualNetworkRule{Name: azure.ToStringPtr("other")}),
+			}},
+			r:    virtualNetworkRule(),
+			want: virtualNetworkRule(),
+		},
+		{
+			name: "FailedObserveCached",
+			e: &external{rules: database.NewListCache(time.Hour), client: &fake.MockMySQLVirtualNetworkRulesClient{
+				MockListByServerComplete: func(_ context.Context, _ string, _ string) (mysql.VirtualNetworkRuleListResultIterator, error) {
+					return mysql.VirtualNetworkRuleListResultIterator{}, errorBoom
+				},
+			}},
+			r:       virtualNetworkRule(),
+			want:    virtualNetworkRule(),
+			wantErr: errors.Wrap(errors.Wrap(errorBoom, errListMySQLServerVirtualNetworkRules), errGetMySQLServerVirtualNetworkRule),
+		},
 		{
 			name: "FailedObserve",
 			e: &external{client: &fake.MockMySQLVirtualNetworkRulesClient{
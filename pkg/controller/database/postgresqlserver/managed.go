@@ -23,8 +23,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,39 +37,78 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-azure/apis/database/v1beta1"
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
 const (
-	errUpdateCR               = "cannot update PostgreSQL custom resource"
-	errGenPassword            = "cannot generate admin password"
-	errNotPostgreSQLServer    = "managed resource is not a PostgreSQLServer"
-	errCreatePostgreSQLServer = "cannot create PostgreSQLServer"
-	errUpdatePostgreSQLServer = "cannot update PostgreSQLServer"
-	errGetPostgreSQLServer    = "cannot get PostgreSQLServer"
-	errDeletePostgreSQLServer = "cannot delete PostgreSQLServer"
-	errFetchLastOperation     = "cannot fetch last operation"
+	errUpdateCR                = "cannot update PostgreSQL custom resource"
+	errGenPassword             = "cannot generate admin password"
+	errRenameConnection        = "cannot rename connection secret keys"
+	errNotPostgreSQLServer     = "managed resource is not a PostgreSQLServer"
+	errCreatePostgreSQLServer  = "cannot create PostgreSQLServer"
+	errUpdatePostgreSQLServer  = "cannot update PostgreSQLServer"
+	errGetPostgreSQLServer     = "cannot get PostgreSQLServer"
+	errDeletePostgreSQLServer  = "cannot delete PostgreSQLServer"
+	errRestartPostgreSQLServer = "cannot restart PostgreSQLServer"
+	errUpgradePostgreSQLServer = "cannot upgrade PostgreSQLServer"
+	errFetchLastOperation      = "cannot fetch last operation"
+	errListReplicas            = "cannot list read replicas"
 )
 
-// Setup adds a controller that reconciles PostgreSQLInstances.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles PostgreSQLInstances. The supplied CA
+// bundle is published to the connection secrets of the servers, and the
+// supplied tags are ignored when determining whether the servers are up to date.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ca database.CABundle, ignored azure.IgnoredTags) error {
 	name := managed.ControllerName(v1beta1.PostgreSQLServerGroupKind)
+	r := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1beta1.PostgreSQLServer{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1beta1.PostgreSQLServerList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1beta1.PostgreSQLServerGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.PostgreSQLServerGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithConnectionPublishers(azure.NewSecretPublisher(mgr.GetClient(), mgr.GetScheme())),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), record: r, ca: ca, ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly, chain.WithLocks(serverID))),
+			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), managed.NewNameAsExternalName(mgr.GetClient()), database.NewStorageDefaulter(mgr.GetClient())),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(r)), throttled, o, chain.WithPolling(operationInProgress)))
+}
+
+// serverID returns the Azure resource ID of the supplied PostgreSQLServer, if
+// known.
+func serverID(mg resource.Managed) string {
+	cr, ok := mg.(*v1beta1.PostgreSQLServer)
+	if !ok {
+		return ""
+	}
+	return cr.Status.AtProvider.ID
+}
+
+// operationInProgress returns true if the supplied PostgreSQLServer has a long-running
+// operation in progress.
+func operationInProgress(mg resource.Managed) bool {
+	cr, ok := mg.(*v1beta1.PostgreSQLServer)
+	if !ok {
+		return false
+	}
+	return cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress
 }
 
 type connecter struct {
-	client client.Client
+	client      client.Client
+	record      event.Recorder
+	ca          database.CABundle
+	ignored     azure.IgnoredTags
+	observeOnly bool
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -75,15 +116,24 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
+	v, err := azure.GetAPIVersion(ctx, c.client, mg, v1beta1.PostgreSQLServerKind)
+	if err != nil {
+		return nil, err
+	}
 	cl := postgresql.NewServersClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{kube: c.client, client: database.NewPostgreSQLServerClient(cl), newPasswordFn: password.Generate}, nil
+	cl.RequestInspector = azure.WithAPIVersion(v)
+	return &external{kube: c.client, client: database.NewPostgreSQLServerClient(cl, c.ignored), newPasswordFn: password.Generate, record: c.record, ca: c.ca, ignored: c.ignored, observeOnly: c.observeOnly}, nil
 }
 
 type external struct {
 	kube          client.Client
 	client        database.PostgreSQLServerAPI
 	newPasswordFn func() (password string, err error)
+	record        event.Recorder
+	ca            database.CABundle
+	ignored       azure.IgnoredTags
+	observeOnly   bool
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -93,7 +143,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	server, err := e.client.GetServer(ctx, cr)
 	if azure.IsNotFound(err) {
-		if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
+		if err := e.fetchLastOperation(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 		}
 		// Azure returns NotFound for GET calls until creation is completed
@@ -107,8 +157,29 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServer)
 	}
-	database.LateInitializePostgreSQL(&cr.Spec.ForProvider, server)
-	if err := e.kube.Update(ctx, cr); err != nil {
+	if err := azure.ValidateOwnership(cr, cr.Status.AtProvider.ID, azure.ToString(server.ID), server.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServer)
+	}
+	// A requested restart that did not succeed is reported and then
+	// forgotten, so that it is retried while the annotation remains.
+	if database.RestartRequested(cr) {
+		if err := database.RestartError(cr.Status.AtProvider.LastOperation); err != nil {
+			cr.Status.AtProvider.LastOperation = apisv1alpha3.AsyncOperation{}
+			return managed.ExternalObservation{}, errors.Wrap(err, errRestartPostgreSQLServer)
+		}
+	}
+	original := cr.DeepCopy()
+	database.LateInitializePostgreSQL(&cr.Spec.ForProvider, server, e.ignored)
+	// A requested restart is complete once its operation has succeeded, at
+	// which point we remove the annotation that requested it.
+	restarted := database.RestartRequested(cr) && database.RestartSucceeded(cr.Status.AtProvider.LastOperation)
+	if restarted {
+		meta.RemoveAnnotations(cr, database.AnnotationKeyRestart)
+	}
+	if err := azure.UpdateIfChanged(ctx, e.kube, original, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
 	}
 	database.UpdatePostgreSQLObservation(&cr.Status.AtProvider, server)
@@ -116,29 +187,57 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// status subresource but fetches the the whole object after it's done. So,
 	// changes to status has to be done after kube.Update in order not to get them
 	// lost.
-	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
+	if err := e.fetchLastOperation(ctx, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
-	// Any state beside 'ready' is considered unavailable.
-	switch server.UserVisibleState { //nolint:exhaustive
-	case v1beta1.StateReady:
-		cr.SetConditions(runtimev1alpha1.Available())
-	default:
-		cr.SetConditions(runtimev1alpha1.Unavailable())
+	if restarted {
+		// Forget the restart so that a subsequent request isn't mistaken for
+		// a completed one.
+		cr.Status.AtProvider.LastOperation = apisv1alpha3.AsyncOperation{}
+	}
+	cr.SetConditions(database.SQLServerCondition(cr.Status.AtProvider.UserVisibleState))
+	conn := managed.ConnectionDetails{
+		runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(cr.Status.AtProvider.FullyQualifiedDomainName),
+		runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", cr.Spec.ForProvider.AdministratorLogin, meta.GetExternalName(cr))),
+		database.ConnectionSecretCACertKey:                   e.ca.For(cr.Spec.ForProvider.Location),
+	}
+	// Failing to list read replicas mustn't prevent the server from being
+	// observed. We omit the read endpoints instead, which leaves any that were
+	// previously published to the connection secret unchanged.
+	replicas, err := e.client.ListReplicas(ctx, cr)
+	if err != nil {
+		e.record.Event(cr, event.Warning(database.ReasonCannotListReplicas, errors.Wrap(err, errListReplicas)))
+	} else {
+		conn[database.ConnectionSecretReadEndpointsKey] = database.PostgreSQLReadEndpoints(replicas)
 	}
 
+	conn, err = azure.RenameConnectionDetails(azure.WithReadiness(conn, cr.Status.AtProvider.UserVisibleState == v1beta1.StateReady), cr.Spec.ConnectionSecretKeys)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRenameConnection)
+	}
 	o := managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: database.IsPostgreSQLUpToDate(cr.Spec.ForProvider, server), // NOTE(negz): We don't yet support updating Azure SQL servers.
-		ConnectionDetails: managed.ConnectionDetails{
-			runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(cr.Status.AtProvider.FullyQualifiedDomainName),
-			runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", cr.Spec.ForProvider.AdministratorLogin, meta.GetExternalName(cr))),
-		},
+		ResourceExists:    true,
+		ResourceUpToDate:  database.IsPostgreSQLUpToDate(cr.Spec.ForProvider, server, e.ignored) && !database.RestartRequested(cr), // NOTE(negz): We don't yet support updating Azure SQL servers.
+		ConnectionDetails: conn,
 	}
 
 	return o, nil
 }
 
+// fetchLastOperation updates the status of the supplied server's last
+// operation, recording an event if the operation completed since it was last
+// observed.
+func (e *external) fetchLastOperation(ctx context.Context, cr *v1beta1.PostgreSQLServer) error {
+	before := cr.Status.AtProvider.LastOperation
+	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
+		return err
+	}
+	if ev, ok := database.OperationCompletedEvent(before, cr.Status.AtProvider.LastOperation); ok {
+		e.record.Event(cr, ev)
+	}
+	return nil
+}
+
 func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1beta1.PostgreSQLServer)
 	if !ok {
@@ -146,22 +245,31 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	cr.SetConditions(runtimev1alpha1.Creating())
+	if err := database.ValidatePostgreSQLAdministratorLogin(cr.Spec.ForProvider.AdministratorLogin); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePostgreSQLServer)
+	}
 
 	pw, err := e.newPasswordFn()
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errGenPassword)
 	}
+	// We rename the password's key before creating the server, because we
+	// couldn't log in to a server whose password we couldn't publish.
+	conn, err := azure.RenameConnectionDetails(managed.ConnectionDetails{
+		runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(pw),
+	}, cr.Spec.ConnectionSecretKeys)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errRenameConnection)
+	}
 	if err := e.client.CreateServer(ctx, cr, pw); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePostgreSQLServer)
 	}
 
 	return managed.ExternalCreation{
-			ConnectionDetails: managed.ConnectionDetails{
-				runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(pw),
-			},
-		}, errors.Wrap(
-			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
-			errFetchLastOperation)
+		ConnectionDetails: conn,
+	}, errors.Wrap(
+		azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
+		errFetchLastOperation)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -172,6 +280,23 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
 		return managed.ExternalUpdate{}, nil
 	}
+	if !database.SQLServerUpdatable(cr.Status.AtProvider.UserVisibleState) {
+		return managed.ExternalUpdate{}, nil
+	}
+	if database.RestartRequested(cr) {
+		if err := e.client.RestartServer(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRestartPostgreSQLServer)
+		}
+		return managed.ExternalUpdate{}, errors.Wrap(
+			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
+			errFetchLastOperation)
+	}
+	if database.UpgradeRequested(cr.Spec.ForProvider.Version, cr.Status.AtProvider.Version) {
+		return managed.ExternalUpdate{}, errors.Wrap(database.ValidatePostgreSQLUpgrade(cr.Status.AtProvider.Version, cr.Spec.ForProvider.Version), errUpgradePostgreSQLServer)
+	}
+	if err := database.ValidateInfrastructureEncryption(cr.Spec.ForProvider, cr.Status.AtProvider.InfrastructureEncryption); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePostgreSQLServer)
+	}
 	if err := e.client.UpdateServer(ctx, cr); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePostgreSQLServer)
 	}
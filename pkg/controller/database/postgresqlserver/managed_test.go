@@ -28,8 +28,10 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,6 +39,7 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/database/v1beta1"
 	azurev1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
 )
 
@@ -50,6 +53,8 @@ type MockPostgreSQLServerAPI struct {
 	MockGetServer     func(ctx context.Context, s *v1beta1.PostgreSQLServer) (postgresql.Server, error)
 	MockCreateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer, adminPassword string) error
 	MockDeleteServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer) error
+	MockRestartServer func(ctx context.Context, s *v1beta1.PostgreSQLServer) error
+	MockListReplicas  func(ctx context.Context, s *v1beta1.PostgreSQLServer) (postgresql.ServerListResult, error)
 	MockUpdateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer) error
 	MockGetRESTClient func() autorest.Sender
 }
@@ -74,6 +79,14 @@ func (m *MockPostgreSQLServerAPI) DeleteServer(ctx context.Context, s *v1beta1.P
 	return m.MockDeleteServer(ctx, s)
 }
 
+func (m *MockPostgreSQLServerAPI) RestartServer(ctx context.Context, s *v1beta1.PostgreSQLServer) error {
+	return m.MockRestartServer(ctx, s)
+}
+
+func (m *MockPostgreSQLServerAPI) ListReplicas(ctx context.Context, s *v1beta1.PostgreSQLServer) (postgresql.ServerListResult, error) {
+	return m.MockListReplicas(ctx, s)
+}
+
 type modifier func(*v1beta1.PostgreSQLServer)
 
 func withExternalName(name string) modifier {
@@ -94,6 +107,32 @@ func withLastOperation(op azurev1alpha3.AsyncOperation) modifier {
 	}
 }
 
+func withUserVisibleState(state string) modifier {
+	return func(p *v1beta1.PostgreSQLServer) {
+		p.Status.AtProvider.UserVisibleState = state
+	}
+}
+
+func withVersion(desired, observed string) modifier {
+	return func(p *v1beta1.PostgreSQLServer) {
+		p.Spec.ForProvider.Version = desired
+		p.Status.AtProvider.Version = observed
+	}
+}
+
+func withInfrastructureEncryption(desired, observed string) modifier {
+	return func(p *v1beta1.PostgreSQLServer) {
+		p.Spec.ForProvider.InfrastructureEncryption = &desired
+		p.Status.AtProvider.InfrastructureEncryption = observed
+	}
+}
+
+func withRestartRequested() modifier {
+	return func(p *v1beta1.PostgreSQLServer) {
+		meta.AddAnnotations(p, map[string]string{database.AnnotationKeyRestart: "now"})
+	}
+}
+
 func postgresqlserver(m ...modifier) *v1beta1.PostgreSQLServer {
 	p := &v1beta1.PostgreSQLServer{}
 
@@ -108,10 +147,14 @@ const (
 )
 
 func TestObserve(t *testing.T) {
+	owned := azure.WithOwnershipTags(nil, v1beta1.PostgreSQLServerKind, postgresqlserver())
 	errBoom := errors.New("boom")
 	name := "coolserver"
 	endpoint := "coolazure.example.prg"
 	admin := "cooladmin"
+	noReplicas := func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.ServerListResult, error) {
+		return postgresql.ServerListResult{}, nil
+	}
 
 	type args struct {
 		ctx context.Context
@@ -203,19 +246,73 @@ func TestObserve(t *testing.T) {
 		},
 		"ServerAvailable": {
 			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockPostgreSQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
+						return postgresql.Server{
+							Tags: owned,
+							Sku:  &postgresql.Sku{},
+							ServerProperties: &postgresql.ServerProperties{
+								UserVisibleState:         postgresql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &postgresql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: postgresqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"ReadReplicas": {
+			e: &external{
+				ca: database.DefaultCABundle(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &MockPostgreSQLServerAPI{
 					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
 						return postgresql.Server{
-							Sku: &postgresql.Sku{},
+							Tags: owned,
+							Sku:  &postgresql.Sku{},
 							ServerProperties: &postgresql.ServerProperties{
 								UserVisibleState:         postgresql.ServerStateReady,
 								FullyQualifiedDomainName: &endpoint,
 								StorageProfile:           &postgresql.StorageProfile{},
 							}}, nil
 					},
+					MockListReplicas: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.ServerListResult, error) {
+						return postgresql.ServerListResult{Value: &[]postgresql.Server{
+							{ServerProperties: &postgresql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("replica-b.example.org")}},
+							{ServerProperties: &postgresql.ServerProperties{FullyQualifiedDomainName: azure.ToStringPtr("replica-a.example.org")}},
+						}}, nil
+					},
 					MockGetRESTClient: func() autorest.Sender {
 						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
 							return nil, nil
@@ -237,10 +334,180 @@ func TestObserve(t *testing.T) {
 					ConnectionDetails: managed.ConnectionDetails{
 						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
 						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte("replica-a.example.org,replica-b.example.org"),
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
 					},
 				},
 			},
 		},
+		"ListReplicasFailed": {
+			e: &external{
+				ca:     database.DefaultCABundle(),
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockPostgreSQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
+						return postgresql.Server{
+							Tags: owned,
+							Sku:  &postgresql.Sku{},
+							ServerProperties: &postgresql.ServerProperties{
+								UserVisibleState:         postgresql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &postgresql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.ServerListResult, error) {
+						return postgresql.ServerListResult{}, errBoom
+					},
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: postgresqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"RestartRequested": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockPostgreSQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
+						return postgresql.Server{
+							Tags: owned,
+							Sku:  &postgresql.Sku{},
+							ServerProperties: &postgresql.ServerProperties{
+								UserVisibleState:         postgresql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &postgresql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: postgresqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+					withRestartRequested(),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"RestartCompleted": {
+			e: &external{
+				ca: database.DefaultCABundle(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+						if database.RestartRequested(obj.(*v1beta1.PostgreSQLServer)) {
+							return errBoom
+						}
+						return nil
+					}),
+				},
+				client: &MockPostgreSQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
+						return postgresql.Server{
+							Tags: owned,
+							Sku:  &postgresql.Sku{},
+							ServerProperties: &postgresql.ServerProperties{
+								UserVisibleState:         postgresql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &postgresql.StorageProfile{},
+							}}, nil
+					},
+					MockListReplicas: noReplicas,
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: postgresqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+					withRestartRequested(),
+					withLastOperation(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Succeeded"}),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+						database.ConnectionSecretCACertKey:                   database.DefaultCABundle().Public,
+						database.ConnectionSecretReadEndpointsKey:            []byte{},
+						azure.ConnectionSecretReadyKey:                       []byte("true"),
+					},
+				},
+			},
+		},
+		"RestartFailed": {
+			e: &external{
+				client: &MockPostgreSQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
+						return postgresql.Server{Tags: owned}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: postgresqlserver(
+					withRestartRequested(),
+					withLastOperation(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"}),
+				),
+			},
+			want: want{
+				err: errors.Wrap(database.RestartError(azurev1alpha3.AsyncOperation{Method: http.MethodPost, Status: "Failed"}), errRestartPostgreSQLServer),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -284,6 +551,16 @@ func TestCreate(t *testing.T) {
 				err: errors.New(errNotPostgreSQLServer),
 			},
 		},
+		"ErrReservedAdministratorLogin": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withAdminName("root")),
+			},
+			want: want{
+				err: errors.Wrap(database.ValidatePostgreSQLAdministratorLogin("root"), errCreatePostgreSQLServer),
+			},
+		},
 		"ErrGeneratePassword": {
 			e: &external{
 				newPasswordFn: func() (string, error) { return "", errBoom },
@@ -349,6 +626,113 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		e    managed.ExternalClient
+		args args
+		want error
+	}{
+		"ErrNotAPostgreSQLServer": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: errors.New(errNotPostgreSQLServer),
+		},
+		"OperationInProgress": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withLastOperation(azurev1alpha3.AsyncOperation{Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: nil,
+		},
+		"ServerDisabled": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withUserVisibleState(v1beta1.StateDisabled)),
+			},
+			want: nil,
+		},
+		"ErrRestartServer": {
+			e: &external{
+				client: &MockPostgreSQLServerAPI{
+					MockRestartServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) error { return errBoom },
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withRestartRequested()),
+			},
+			want: errors.Wrap(errBoom, errRestartPostgreSQLServer),
+		},
+		"SuccessfulRestart": {
+			e: &external{
+				client: &MockPostgreSQLServerAPI{
+					MockRestartServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) error { return nil },
+					MockUpdateServer:  func(_ context.Context, _ *v1beta1.PostgreSQLServer) error { return errBoom },
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withRestartRequested()),
+			},
+			want: nil,
+		},
+		"ErrUpgradeServer": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withVersion("11", "10")),
+			},
+			want: errors.Wrap(errors.Errorf("cannot upgrade server from version %q to %q: in place upgrades of PostgreSQL servers are not supported", "10", "11"), errUpgradePostgreSQLServer),
+		},
+		"ErrChangeInfrastructureEncryption": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(withInfrastructureEncryption("Enabled", "Disabled")),
+			},
+			want: errors.Wrap(database.ValidateInfrastructureEncryption(v1beta1.SQLServerParameters{InfrastructureEncryption: azure.ToStringPtr("Enabled")}, "Disabled"), errUpdatePostgreSQLServer),
+		},
+		"ErrUpdateServer": {
+			e: &external{
+				client: &MockPostgreSQLServerAPI{
+					MockUpdateServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) error { return errBoom },
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  postgresqlserver(),
+			},
+			want: errors.Wrap(errBoom, errUpdatePostgreSQLServer),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.e.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	errBoom := errors.New("boom")
 
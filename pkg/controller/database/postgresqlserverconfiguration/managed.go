@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresqlserverconfiguration
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
+	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql/postgresqlapi"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+)
+
+// Error strings.
+const (
+	errNotPostgreSQLServerConfiguration    = "managed resource is not a PostgreSQLServerConfiguration"
+	errCreatePostgreSQLServerConfiguration = "cannot create PostgreSQLServerConfiguration"
+	errUpdatePostgreSQLServerConfiguration = "cannot update PostgreSQLServerConfiguration"
+	errGetPostgreSQLServerConfiguration    = "cannot get PostgreSQLServerConfiguration"
+	errDeletePostgreSQLServerConfiguration = "cannot reset PostgreSQLServerConfiguration to its default value"
+	errRestartPostgreSQLServer             = "cannot restart PostgreSQLServer"
+	errFetchLastOperation                  = "cannot fetch last operation"
+)
+
+// Setup adds a controller that reconciles PostgreSQLServerConfigurations.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
+	name := managed.ControllerName(v1alpha3.PostgreSQLServerConfigurationGroupKind)
+	throttled := azure.NewThrottle()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
+		For(&v1alpha3.PostgreSQLServerConfiguration{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.PostgreSQLServerConfigurationList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.PostgreSQLServerConfigurationGroupVersionKind), managed.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha3.PostgreSQLServerConfigurationGroupVersionKind),
+			managed.WithConnectionPublishers(),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient()}, throttled, observeOnly)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o))
+}
+
+type connecter struct {
+	client client.Client
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	creds, auth, err := azure.GetAuthInfo(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	cl := postgresql.NewConfigurationsClient(creds[azure.CredentialsKeySubscriptionID])
+	cl.Authorizer = auth
+	sc := postgresql.NewServersClient(creds[azure.CredentialsKeySubscriptionID])
+	sc.Authorizer = auth
+	return &external{client: cl, servers: sc, sender: cl.Client}, nil
+}
+
+type external struct {
+	client  postgresqlapi.ConfigurationsClientAPI
+	servers postgresqlapi.ServersClientAPI
+	sender  autorest.Sender
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha3.PostgreSQLServerConfiguration)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPostgreSQLServerConfiguration)
+	}
+
+	if err := azure.FetchAsyncOperation(ctx, e.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
+
+	// We wait for any change to the parameter, or any restart of its server,
+	// to complete before we take further action.
+	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	p := cr.Spec.ForProvider
+	az, err := e.client.Get(ctx, p.ResourceGroupName, p.ServerName, p.Name)
+	if azure.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServerConfiguration)
+	}
+
+	database.UpdatePostgreSQLConfigurationObservation(&cr.Status.AtProvider, az)
+
+	// NOTE(negz): A configuration parameter always exists, but we consider it
+	// to exist only once it has been overridden. This allows us to 'create'
+	// the parameter by setting it, and to tell when a 'delete' (i.e. a reset
+	// to its default value) has completed.
+	if !database.PostgreSQLConfigurationIsOverridden(az) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.SetConditions(runtimev1alpha1.Available())
+
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: database.PostgreSQLServerConfigurationIsUpToDate(cr, az) && !cr.Status.AtProvider.RestartPending,
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha3.PostgreSQLServerConfiguration)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPostgreSQLServerConfiguration)
+	}
+
+	cr.SetConditions(runtimev1alpha1.Creating())
+	return managed.ExternalCreation{}, errors.Wrap(e.set(ctx, cr), errCreatePostgreSQLServerConfiguration)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha3.PostgreSQLServerConfiguration)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotPostgreSQLServerConfiguration)
+	}
+
+	p := cr.Spec.ForProvider
+	az, err := e.client.Get(ctx, p.ResourceGroupName, p.ServerName, p.Name)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetPostgreSQLServerConfiguration)
+	}
+
+	if !database.PostgreSQLServerConfigurationIsUpToDate(cr, az) {
+		return managed.ExternalUpdate{}, errors.Wrap(e.set(ctx, cr), errUpdatePostgreSQLServerConfiguration)
+	}
+
+	// The parameter has its desired value, but the server must be restarted
+	// for that value to take effect.
+	op, err := e.servers.Restart(ctx, p.ResourceGroupName, p.ServerName)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRestartPostgreSQLServer)
+	}
+	cr.Status.AtProvider.LastOperation = apisv1alpha3.AsyncOperation{
+		PollingURL: op.PollingURL(),
+		Method:     http.MethodPost,
+	}
+	cr.Status.AtProvider.RestartPending = false
+	return managed.ExternalUpdate{}, nil
+}
+
+// set the supplied configuration parameter to its desired value, noting
+// whether its server will need to be restarted once the change is complete.
+func (e *external) set(ctx context.Context, cr *v1alpha3.PostgreSQLServerConfiguration) error {
+	p := cr.Spec.ForProvider
+	op, err := e.client.CreateOrUpdate(ctx, p.ResourceGroupName, p.ServerName, p.Name, database.NewPostgreSQLConfigurationParameters(cr))
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.LastOperation = apisv1alpha3.AsyncOperation{
+		PollingURL: op.PollingURL(),
+		Method:     http.MethodPut,
+	}
+	cr.Status.AtProvider.RestartPending = database.PostgreSQLConfigurationRequiresRestart(p)
+	return nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha3.PostgreSQLServerConfiguration)
+	if !ok {
+		return errors.New(errNotPostgreSQLServerConfiguration)
+	}
+
+	cr.SetConditions(runtimev1alpha1.Deleting())
+	p := cr.Spec.ForProvider
+	az, err := e.client.Get(ctx, p.ResourceGroupName, p.ServerName, p.Name)
+	if err != nil {
+		return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errGetPostgreSQLServerConfiguration)
+	}
+	// NOTE(negz): We don't restart the server after resetting a parameter
+	// that requires it; the default value will take effect when the server is
+	// next restarted.
+	_, err = e.client.CreateOrUpdate(ctx, p.ResourceGroupName, p.ServerName, p.Name, database.NewPostgreSQLConfigurationResetParameters(az))
+	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeletePostgreSQLServerConfiguration)
+}
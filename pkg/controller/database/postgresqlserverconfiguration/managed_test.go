@@ -0,0 +1,514 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresqlserverconfiguration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/clients/fake"
+)
+
+const (
+	name              = "coolConfig"
+	serverName        = "coolSrv"
+	resourceGroupName = "coolRG"
+	parameterName     = "log_min_duration_statement"
+	value             = "500"
+	defaultValue      = "-1"
+	resourceID        = "a-very-cool-id"
+)
+
+type configurationModifier func(*v1alpha3.PostgreSQLServerConfiguration)
+
+func withConditions(c ...runtimev1alpha1.Condition) configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withObservation(o v1alpha3.SQLServerConfigurationObservation) configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Status.AtProvider = o }
+}
+
+func withRestartServer(b bool) configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Spec.ForProvider.RestartServer = &b }
+}
+
+func withRestartPending() configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Status.AtProvider.RestartPending = true }
+}
+
+func withLastOperation(method string) configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Status.AtProvider.LastOperation.Method = method }
+}
+
+func withLastOperationStatus(status string) configurationModifier {
+	return func(r *v1alpha3.PostgreSQLServerConfiguration) { r.Status.AtProvider.LastOperation.Status = status }
+}
+
+func configuration(sm ...configurationModifier) *v1alpha3.PostgreSQLServerConfiguration {
+	r := &v1alpha3.PostgreSQLServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha3.SQLServerConfigurationSpec{
+			ForProvider: v1alpha3.SQLServerConfigurationParameters{
+				ServerName:        serverName,
+				ResourceGroupName: resourceGroupName,
+				Name:              parameterName,
+				Value:             value,
+			},
+		},
+	}
+
+	for _, m := range sm {
+		m(r)
+	}
+
+	return r
+}
+
+func azConfiguration(v, source string) postgresql.Configuration {
+	return postgresql.Configuration{
+		ID: azure.ToStringPtr(resourceID),
+		ConfigurationProperties: &postgresql.ConfigurationProperties{
+			Value:        azure.ToStringPtr(v),
+			DefaultValue: azure.ToStringPtr(defaultValue),
+			Source:       azure.ToStringPtr(source),
+		},
+	}
+}
+
+// Test that our Reconciler implementation satisfies the Reconciler interface.
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connecter{}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o   managed.ExternalObservation
+		mg  resource.Managed
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		ec   managed.ExternalClient
+		mg   resource.Managed
+		want want
+	}{
+		"NotPostgreSQLServerConfiguration": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{}},
+			want: want{
+				err: errors.New(errNotPostgreSQLServerConfiguration),
+			},
+		},
+		"NotFound": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return postgresql.Configuration{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false},
+				mg: configuration(),
+			},
+		},
+		"SystemDefault": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration(defaultValue, database.ConfigurationSourceSystemDefault), nil
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+				mg: configuration(withObservation(v1alpha3.SQLServerConfigurationObservation{
+					ID:           resourceID,
+					DefaultValue: defaultValue,
+					Source:       database.ConfigurationSourceSystemDefault,
+				})),
+			},
+		},
+		"UpToDate": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				mg: configuration(
+					withConditions(runtimev1alpha1.Available()),
+					withObservation(v1alpha3.SQLServerConfigurationObservation{
+						ID:           resourceID,
+						DefaultValue: defaultValue,
+						Source:       database.ConfigurationSourceUserOverride,
+					}),
+				),
+			},
+		},
+		"NeedsUpdate": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration("1000", database.ConfigurationSourceUserOverride), nil
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				mg: configuration(
+					withConditions(runtimev1alpha1.Available()),
+					withObservation(v1alpha3.SQLServerConfigurationObservation{
+						ID:           resourceID,
+						DefaultValue: defaultValue,
+						Source:       database.ConfigurationSourceUserOverride,
+					}),
+				),
+			},
+		},
+		"RestartPending": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+				},
+			}},
+			mg: configuration(withRestartPending()),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				mg: configuration(
+					withRestartPending(),
+					withConditions(runtimev1alpha1.Available()),
+					withObservation(v1alpha3.SQLServerConfigurationObservation{
+						ID:             resourceID,
+						DefaultValue:   defaultValue,
+						Source:         database.ConfigurationSourceUserOverride,
+						RestartPending: true,
+					}),
+				),
+			},
+		},
+		"OperationInProgress": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{}},
+			mg: configuration(withLastOperationStatus(azure.AsyncOperationStatusInProgress)),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				mg: configuration(withLastOperationStatus(azure.AsyncOperationStatusInProgress)),
+			},
+		},
+		"GetFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return postgresql.Configuration{}, errBoom
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg:  configuration(),
+				err: errors.Wrap(errBoom, errGetPostgreSQLServerConfiguration),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.ec.Observe(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.ec.Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("tc.ec.Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		ec   managed.ExternalClient
+		mg   resource.Managed
+		want want
+	}{
+		"NotPostgreSQLServerConfiguration": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{}},
+			want: want{
+				err: errors.New(errNotPostgreSQLServerConfiguration),
+			},
+		},
+		"CreateFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, _ postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, errBoom
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg:  configuration(withConditions(runtimev1alpha1.Creating())),
+				err: errors.Wrap(errBoom, errCreatePostgreSQLServerConfiguration),
+			},
+		},
+		"Successful": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockCreateOrUpdate: func(_ context.Context, _, _, n string, p postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					if n != parameterName || azure.ToString(p.Value) != value {
+						return postgresql.ConfigurationsCreateOrUpdateFuture{}, errBoom
+					}
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, nil
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg: configuration(withConditions(runtimev1alpha1.Creating()), withLastOperation(http.MethodPut)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.ec.Create(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.ec.Create(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		ec   managed.ExternalClient
+		mg   resource.Managed
+		want want
+	}{
+		"NotPostgreSQLServerConfiguration": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{}},
+			want: want{
+				err: errors.New(errNotPostgreSQLServerConfiguration),
+			},
+		},
+		"GetFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return postgresql.Configuration{}, errBoom
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg:  configuration(),
+				err: errors.Wrap(errBoom, errGetPostgreSQLServerConfiguration),
+			},
+		},
+		"UpdateFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration("1000", database.ConfigurationSourceUserOverride), nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, _ postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, errBoom
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg:  configuration(),
+				err: errors.Wrap(errBoom, errUpdatePostgreSQLServerConfiguration),
+			},
+		},
+		"Successful": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration("1000", database.ConfigurationSourceUserOverride), nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, _ postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, nil
+				},
+			}},
+			mg: configuration(),
+			want: want{
+				mg: configuration(withLastOperation(http.MethodPut)),
+			},
+		},
+		"SuccessfulRestartRequired": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration("1000", database.ConfigurationSourceUserOverride), nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, _ postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, nil
+				},
+			}},
+			mg: configuration(withRestartServer(true)),
+			want: want{
+				mg: configuration(withRestartServer(true), withLastOperation(http.MethodPut), withRestartPending()),
+			},
+		},
+		"RestartFailed": {
+			ec: &external{
+				client: &fake.MockPostgreSQLConfigurationsClient{
+					MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+						return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+					},
+				},
+				servers: &fake.MockPostgreSQLServersClient{
+					MockRestart: func(_ context.Context, _, _ string) (postgresql.ServersRestartFuture, error) {
+						return postgresql.ServersRestartFuture{}, errBoom
+					},
+				},
+			},
+			mg: configuration(withRestartPending()),
+			want: want{
+				mg:  configuration(withRestartPending()),
+				err: errors.Wrap(errBoom, errRestartPostgreSQLServer),
+			},
+		},
+		"SuccessfulRestart": {
+			ec: &external{
+				client: &fake.MockPostgreSQLConfigurationsClient{
+					MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+						return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+					},
+				},
+				servers: &fake.MockPostgreSQLServersClient{
+					MockRestart: func(_ context.Context, _, s string) (postgresql.ServersRestartFuture, error) {
+						if s != serverName {
+							return postgresql.ServersRestartFuture{}, errBoom
+						}
+						return postgresql.ServersRestartFuture{}, nil
+					},
+				},
+			},
+			mg: configuration(withRestartPending()),
+			want: want{
+				mg: configuration(withLastOperation(http.MethodPost)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.ec.Update(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.ec.Update(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		ec   managed.ExternalClient
+		mg   resource.Managed
+		want error
+	}{
+		"NotPostgreSQLServerConfiguration": {
+			ec:   &external{client: &fake.MockPostgreSQLConfigurationsClient{}},
+			want: errors.New(errNotPostgreSQLServerConfiguration),
+		},
+		"NotFound": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return postgresql.Configuration{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			mg: configuration(),
+		},
+		"GetFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return postgresql.Configuration{}, errBoom
+				},
+			}},
+			mg:   configuration(),
+			want: errors.Wrap(errBoom, errGetPostgreSQLServerConfiguration),
+		},
+		"ResetFailed": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, _ postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, errBoom
+				},
+			}},
+			mg:   configuration(),
+			want: errors.Wrap(errBoom, errDeletePostgreSQLServerConfiguration),
+		},
+		"Successful": {
+			ec: &external{client: &fake.MockPostgreSQLConfigurationsClient{
+				MockGet: func(_ context.Context, _, _, _ string) (postgresql.Configuration, error) {
+					return azConfiguration(value, database.ConfigurationSourceUserOverride), nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _, _, _ string, p postgresql.Configuration) (postgresql.ConfigurationsCreateOrUpdateFuture, error) {
+					if azure.ToString(p.Value) != defaultValue {
+						return postgresql.ConfigurationsCreateOrUpdateFuture{}, errBoom
+					}
+					return postgresql.ConfigurationsCreateOrUpdateFuture{}, nil
+				},
+			}},
+			mg: configuration(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.ec.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.ec.Delete(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package postgresqlservervirtualnetworkrule
 
 import (
 	"context"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql/postgresqlapi"
 
@@ -26,8 +27,10 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -37,6 +40,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/database"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -46,26 +51,39 @@ const (
 	errUpdatePostgreSQLServerVirtualNetworkRule = "cannot update PostgreSQLServerVirtualNetworkRule"
 	errGetPostgreSQLServerVirtualNetworkRule    = "cannot get PostgreSQLServerVirtualNetworkRule"
 	errDeletePostgreSQLServerVirtualNetworkRule = "cannot delete PostgreSQLServerVirtualNetworkRule"
+	errListPostgreSQLServerVirtualNetworkRules  = "cannot list PostgreSQLServerVirtualNetworkRules"
 )
 
-// Setup adds a controller that reconciles PostgreSQLServerVirtualNetworkRules.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles PostgreSQLServerVirtualNetworkRules. The
+// virtual network rules of each server are cached for the supplied TTL after
+// they are listed. Caching is disabled if the TTL is not positive.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ttl time.Duration) error {
 	name := managed.ControllerName(v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupKind)
 
+	var rules *database.ListCache
+	if ttl > 0 {
+		rules = database.NewListCache(ttl)
+	}
+
+	throttled := azure.NewThrottle()
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.PostgreSQLServerVirtualNetworkRule{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.PostgreSQLServerVirtualNetworkRuleList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), rules: rules}, throttled, observeOnly)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
 }
 
 type connecter struct {
 	client client.Client
+	rules  *database.ListCache
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -76,11 +94,48 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 
 	cl := postgresql.NewVirtualNetworkRulesClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	return &external{client: cl, rules: c.rules, subscriptionID: creds[azure.CredentialsKeySubscriptionID]}, nil
 }
 
 type external struct {
 	client postgresqlapi.VirtualNetworkRulesClientAPI
+
+	// rules caches the virtual network rules of each server, if non-nil.
+	rules          *database.ListCache
+	subscriptionID string
+}
+
+// get returns the supplied virtual network rule, and whether it exists. The
+// rule is read from the cached virtual network rules of its server, if any.
+func (e *external) get(ctx context.Context, v *v1alpha3.PostgreSQLServerVirtualNetworkRule) (postgresql.VirtualNetworkRule, bool, error) {
+	if e.rules == nil {
+		az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
+		if azure.IsNotFound(err) {
+			return postgresql.VirtualNetworkRule{}, false, nil
+		}
+		return az, err == nil, err
+	}
+
+	key := database.ServerKey(e.subscriptionID, v.Spec.ResourceGroupName, v.Spec.ServerName)
+	az, ok, err := e.rules.Get(key, meta.GetExternalName(v), func() (map[string]interface{}, error) {
+		rules := map[string]interface{}{}
+		it, err := e.client.ListByServerComplete(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName)
+		for ; err == nil && it.NotDone(); err = it.NextWithContext(ctx) {
+			rules[azure.ToString(it.Value().Name)] = it.Value()
+		}
+		return rules, errors.Wrap(resource.Ignore(azure.IsNotFound, err), errListPostgreSQLServerVirtualNetworkRules)
+	})
+	if err != nil || !ok {
+		return postgresql.VirtualNetworkRule{}, false, err
+	}
+	return az.(postgresql.VirtualNetworkRule), true, nil
+}
+
+// invalidate any cached virtual network rules of the supplied rule's server.
+func (e *external) invalidate(v *v1alpha3.PostgreSQLServerVirtualNetworkRule) {
+	if e.rules != nil {
+		e.rules.Invalidate(database.ServerKey(e.subscriptionID, v.Spec.ResourceGroupName, v.Spec.ServerName))
+	}
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -89,13 +144,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotPostgreSQLServerVirtualNetworkRule)
 	}
 
-	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
-	if azure.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
-	}
+	az, exists, err := e.get(ctx, v)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServerVirtualNetworkRule)
 	}
+	if !exists {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 
 	database.UpdatePostgreSQLVirtualNetworkRuleStatusFromAzure(v, az)
 
@@ -116,8 +171,20 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.SetConditions(runtimev1alpha1.Creating())
 
+	// Virtual network rules cannot be tagged with their owner, so we make sure
+	// we're not about to overwrite a rule that something else created. We
+	// bypass the cache, which may predate the rule.
+	_, err := e.client.Get(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
+	if err != nil && !azure.IsNotFound(err) {
+		return managed.ExternalCreation{}, errors.Wrap(err, errGetPostgreSQLServerVirtualNetworkRule)
+	}
+	if err := azure.ValidateCreate(v, err == nil); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePostgreSQLServerVirtualNetworkRule)
+	}
+
+	e.invalidate(v)
 	vnet := database.NewPostgreSQLVirtualNetworkRuleParameters(v)
-	_, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet)
+	_, err = e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet)
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreatePostgreSQLServerVirtualNetworkRule)
 }
 
@@ -127,6 +194,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotPostgreSQLServerVirtualNetworkRule)
 	}
 
+	e.invalidate(v)
 	vnet := database.NewPostgreSQLVirtualNetworkRuleParameters(v)
 	_, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet)
 	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePostgreSQLServerVirtualNetworkRule)
@@ -139,6 +207,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 
 	v.SetConditions(runtimev1alpha1.Deleting())
+	e.invalidate(v)
 
 	_, err := e.client.Delete(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v))
 
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deletion provides a reconciler that reports managed resources whose
+// external resource has not been deleted within a timeout, and optionally
+// orphans them.
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// DefaultTimeout is the default time after which a managed resource that is
+// still being deleted is considered to have timed out.
+const DefaultTimeout = 1 * time.Hour
+
+// AnnotationKeyOrphanOnTimeout is the annotation that causes a managed resource
+// whose delete has timed out to be orphaned when set to "true". Its finalizer
+// is removed, leaving the external resource in Azure, whether or not it is
+// eventually deleted.
+const AnnotationKeyOrphanOnTimeout = "azure.crossplane.io/orphan-on-delete-timeout"
+
+// TypeDeleteTimeout resources have been deleting for longer than the delete
+// timeout.
+const TypeDeleteTimeout runtimev1alpha1.ConditionType = "DeleteTimeout"
+
+// ReasonDeleteTimedOut indicates the external resource of a managed resource
+// has not been deleted within the delete timeout.
+const ReasonDeleteTimedOut runtimev1alpha1.ConditionReason = "DeleteTimedOut"
+
+// DefaultFinalizer is the finalizer the managed reconciler adds to managed
+// resources. crossplane-runtime does not export it, so we must keep it in sync
+// with crossplane-runtime's; TestDefaultFinalizer fails if they differ.
+const DefaultFinalizer = "finalizer.managedresource.crossplane.io"
+
+const (
+	timeout = 1 * time.Minute
+
+	errGetManaged          = "cannot get managed resource"
+	errUpdateManaged       = "cannot remove managed resource finalizer"
+	errUpdateManagedStatus = "cannot update managed resource status"
+)
+
+// TimedOut returns a condition that indicates the external resource of the
+// managed resource has not been deleted within the supplied timeout.
+func TimedOut(t time.Duration) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeDeleteTimeout,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeleteTimedOut,
+		Message:            fmt.Sprintf("external resource has not been deleted after %s; set annotation %s to \"true\" to orphan it", t, AnnotationKeyOrphanOnTimeout),
+	}
+}
+
+// ShouldOrphan returns true if the supplied object is annotated to be orphaned
+// when its delete times out.
+func ShouldOrphan(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyOrphanOnTimeout] == "true"
+}
+
+// A Reconciler wraps another reconciler, reporting managed resources that have
+// been deleting for longer than its timeout with a DeleteTimeout condition.
+// Such resources are orphaned if they are annotated to be.
+//
+// The cache may not yet reflect changes the wrapped reconciler just made, for
+// example removing its finalizer or writing its status. Resources that appear
+// to have timed out in the cache are therefore read again from the API server
+// before they are orphaned or their condition is written.
+type Reconciler struct {
+	client     client.Client
+	reader     client.Reader
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+	timeout    time.Duration
+	finalizer  string
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithTimeout specifies the time after which a managed resource that is still
+// being deleted is considered to have timed out. Deletes never time out if it
+// is not positive. Reconcilers use the DefaultTimeout by default.
+func WithTimeout(t time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.timeout = t
+	}
+}
+
+// WithFinalizer specifies the finalizer that is removed from managed resources
+// that are orphaned. Reconcilers remove the DefaultFinalizer by default.
+func WithFinalizer(f string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.finalizer = f
+	}
+}
+
+// NewReconciler returns a Reconciler that reports managed resources of the
+// supplied kind whose deletes have timed out, and otherwise delegates to the
+// supplied reconciler.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	rec := &Reconciler{client: m.GetClient(), reader: m.GetAPIReader(), newManaged: nm, wrapped: r, timeout: DefaultTimeout, finalizer: DefaultFinalizer}
+	for _, ro := range o {
+		ro(rec)
+	}
+	return rec
+}
+
+// Reconcile a managed resource, reporting it if its delete has timed out.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.wrapped.Reconcile(req)
+	if r.timeout <= 0 {
+		return result, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if gerr := r.client.Get(ctx, req.NamespacedName, mg); gerr != nil || !r.timedOut(mg) {
+		return result, err
+	}
+	if gerr := r.reader.Get(ctx, req.NamespacedName, mg); gerr != nil {
+		if err == nil && !kerrors.IsNotFound(gerr) {
+			err = errors.Wrap(gerr, errGetManaged)
+		}
+		return result, err
+	}
+	if !r.timedOut(mg) {
+		return result, err
+	}
+
+	if ShouldOrphan(mg) {
+		meta.RemoveFinalizer(mg, r.finalizer)
+		if uerr := r.client.Update(ctx, mg); resource.IgnoreNotFound(uerr) != nil {
+			return result, errors.Wrap(uerr, errUpdateManaged)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if mg.GetCondition(TypeDeleteTimeout).Status == corev1.ConditionTrue {
+		return result, err
+	}
+	if uerr := azure.UpdateConditions(ctx, r.client, r.reader, mg, TimedOut(r.timeout)); resource.IgnoreNotFound(uerr) != nil && err == nil {
+		return result, errors.Wrap(uerr, errUpdateManagedStatus)
+	}
+	return result, err
+}
+
+// timedOut returns true if the supplied managed resource has been deleting for
+// longer than the timeout and still has our finalizer.
+func (r *Reconciler) timedOut(mg resource.Managed) bool {
+	dt := mg.GetDeletionTimestamp()
+	return dt != nil && time.Since(dt.Time) >= r.timeout && meta.FinalizerExists(mg, r.finalizer)
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+var wrappedResult = reconcile.Result{RequeueAfter: 30 * time.Second}
+
+func wrapped(err error) reconcile.Reconciler {
+	return reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+		return wrappedResult, err
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	type want struct {
+		result     reconcile.Result
+		err        error
+		finalizers []string
+		conditions []runtimev1alpha1.Condition
+	}
+
+	deletingSince := func(d time.Duration) test.ObjectFn {
+		return func(o runtime.Object) error {
+			ts := metav1.NewTime(time.Now().Add(-d))
+			o.(*fake.Managed).SetDeletionTimestamp(&ts)
+			o.(*fake.Managed).SetFinalizers([]string{DefaultFinalizer})
+			return nil
+		}
+	}
+	withOrphanAnnotation := func(o runtime.Object) error {
+		o.(*fake.Managed).SetAnnotations(map[string]string{AnnotationKeyOrphanOnTimeout: "true"})
+		return nil
+	}
+	withTimedOutCondition := func(o runtime.Object) error {
+		o.(*fake.Managed).SetConditions(TimedOut(time.Hour))
+		return nil
+	}
+
+	cases := map[string]struct {
+		client  client.Client
+		reader  client.Reader
+		wrapped reconcile.Reconciler
+		want    want
+	}{
+		"NotFound": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			wrapped: wrapped(nil),
+			want:    want{result: wrappedResult},
+		},
+		"NotDeleting": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			wrapped: wrapped(nil),
+			want:    want{result: wrappedResult},
+		},
+		"NotYetTimedOut": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(time.Minute)),
+			},
+			wrapped: wrapped(nil),
+			want:    want{result: wrappedResult, finalizers: []string{DefaultFinalizer}},
+		},
+		"TimedOut": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, deletingSince(2*time.Hour)),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped: wrapped(errBoom),
+			want: want{
+				result:     wrappedResult,
+				err:        errBoom,
+				finalizers: []string{DefaultFinalizer},
+				conditions: []runtimev1alpha1.Condition{TimedOut(time.Hour)},
+			},
+		},
+		"TimedOutStatusUpdateError": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, deletingSince(2*time.Hour)),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			wrapped: wrapped(nil),
+			want: want{
+				result:     wrappedResult,
+				err:        errors.Wrap(errBoom, errUpdateManagedStatus),
+				finalizers: []string{DefaultFinalizer},
+				conditions: []runtimev1alpha1.Condition{TimedOut(time.Hour)},
+			},
+		},
+		"AlreadyTimedOut": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(2*time.Hour), withTimedOutCondition),
+			},
+			wrapped: wrapped(nil),
+			want: want{
+				result:     wrappedResult,
+				finalizers: []string{DefaultFinalizer},
+				conditions: []runtimev1alpha1.Condition{TimedOut(time.Hour)},
+			},
+		},
+		"FinalizedSinceCached": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(2*time.Hour)),
+			},
+			reader: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			wrapped: wrapped(nil),
+			want:    want{result: wrappedResult, finalizers: []string{DefaultFinalizer}},
+		},
+		"ReadLatestError": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(2*time.Hour)),
+			},
+			reader: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			wrapped: wrapped(nil),
+			want: want{
+				result:     wrappedResult,
+				err:        errors.Wrap(errBoom, errGetManaged),
+				finalizers: []string{DefaultFinalizer},
+			},
+		},
+		"TimedOutInCacheOnly": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(2*time.Hour)),
+			},
+			reader: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, deletingSince(2*time.Hour), withTimedOutCondition),
+			},
+			wrapped: wrapped(nil),
+			want: want{
+				result:     wrappedResult,
+				finalizers: []string{DefaultFinalizer},
+				conditions: []runtimev1alpha1.Condition{TimedOut(time.Hour)},
+			},
+		},
+		"Orphaned": {
+			client: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, deletingSince(2*time.Hour), withOrphanAnnotation),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			wrapped: wrapped(errBoom),
+			want:    want{finalizers: []string{}},
+		},
+		"OrphanedSinceRead": {
+			client: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, deletingSince(2*time.Hour), withOrphanAnnotation),
+				MockUpdate: test.NewMockUpdateFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			wrapped: wrapped(errBoom),
+			want:    want{finalizers: []string{}},
+		},
+		"OrphanUpdateError": {
+			client: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, deletingSince(2*time.Hour), withOrphanAnnotation),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			wrapped: wrapped(nil),
+			want: want{
+				result:     wrappedResult,
+				err:        errors.Wrap(errBoom, errUpdateManaged),
+				finalizers: []string{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			rd := tc.reader
+			if rd == nil {
+				rd = tc.client
+			}
+			r := &Reconciler{
+				client:     tc.client,
+				reader:     rd,
+				newManaged: func() resource.Managed { return mg },
+				wrapped:    tc.wrapped,
+				timeout:    time.Hour,
+				finalizer:  DefaultFinalizer,
+			}
+
+			got, err := r.Reconcile(reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.finalizers, mg.GetFinalizers()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want finalizers, +got finalizers:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, mg.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want conditions, +got conditions:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShouldOrphan(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"NoAnnotation": {want: false},
+		"Orphan":       {annotations: map[string]string{AnnotationKeyOrphanOnTimeout: "true"}, want: true},
+		"NotTrue":      {annotations: map[string]string{AnnotationKeyOrphanOnTimeout: "false"}, want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			if diff := cmp.Diff(tc.want, ShouldOrphan(mg)); diff != "" {
+				t.Errorf("ShouldOrphan(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultFinalizer(t *testing.T) {
+	// crossplane-runtime does not export the finalizer its managed reconciler
+	// adds, so we observe the finalizer it adds to a managed resource.
+	var got []string
+	c := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+			got = obj.(*fake.Managed).GetFinalizers()
+			return nil
+		}),
+		MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+	}
+	r := managed.NewReconciler(&fake.Manager{Client: c, Scheme: fake.SchemeWith(&fake.Managed{})},
+		resource.ManagedKind(fake.GVK(&fake.Managed{})),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+		managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return &managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+				},
+			}, nil
+		})),
+	)
+	if _, err := r.Reconcile(reconcile.Request{}); err != nil {
+		t.Fatalf("r.Reconcile(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{DefaultFinalizer}, got); diff != "" {
+		t.Errorf("managed.Reconciler added finalizers: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain lets in-flight reconciles finish before the provider stops,
+// so that it doesn't leave Azure resources partially created or updated.
+package drain
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultTimeout is the default maximum duration for which to wait for
+// in-flight reconciles to finish. It is less than the default termination
+// grace period of a pod, so that we exit before we're killed.
+const DefaultTimeout = 25 * time.Second
+
+// A Tracker tracks in-flight reconciles. The zero value is ready to use.
+type Tracker struct {
+	mu       sync.RWMutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// start tracking a reconcile. It returns false if the tracker is draining, in
+// which case the reconcile should not be started.
+func (t *Tracker) start() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.draining {
+		return false
+	}
+	t.inflight.Add(1)
+	return true
+}
+
+func (t *Tracker) done() {
+	t.inflight.Done()
+}
+
+// Drain stops the tracker from starting new reconciles, then waits up to the
+// supplied timeout for in-flight reconciles to finish. It returns false if
+// any reconciles were still in flight when the timeout expired.
+func (t *Tracker) Drain(timeout time.Duration) bool {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		t.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// A Reconciler tracks the in-flight reconciles of the reconciler it wraps.
+type Reconciler struct {
+	wrapped reconcile.Reconciler
+	tracker *Tracker
+}
+
+// NewReconciler returns a Reconciler that tracks the in-flight reconciles of
+// the supplied reconciler using the supplied tracker.
+func NewReconciler(r reconcile.Reconciler, t *Tracker) *Reconciler {
+	return &Reconciler{wrapped: r, tracker: t}
+}
+
+// Reconcile the supplied request, unless the tracker is draining. Requests
+// that are not reconciled are requeued, and thus reconciled when the provider
+// restarts.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if !r.tracker.start() {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	defer r.tracker.done()
+	return r.wrapped.Reconcile(req)
+}
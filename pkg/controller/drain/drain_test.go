@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconcileFunc func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFunc) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	return fn(req)
+}
+
+func TestDrain(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		block   time.Duration
+		timeout time.Duration
+		want    bool
+	}{
+		"Drained": {
+			reason:  "Drain should return true if in-flight reconciles finish before the timeout.",
+			block:   10 * time.Millisecond,
+			timeout: 5 * time.Second,
+			want:    true,
+		},
+		"TimedOut": {
+			reason:  "Drain should return false if in-flight reconciles are still running at the timeout.",
+			block:   5 * time.Second,
+			timeout: 10 * time.Millisecond,
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := &Tracker{}
+			started := make(chan struct{})
+			r := &Reconciler{tracker: tr, wrapped: reconcileFunc(func(_ reconcile.Request) (reconcile.Result, error) {
+				close(started)
+				time.Sleep(tc.block)
+				return reconcile.Result{}, nil
+			})}
+			go r.Reconcile(reconcile.Request{}) //nolint:errcheck
+			<-started
+
+			got := tr.Drain(tc.timeout)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDrain(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		draining bool
+		want     reconcile.Result
+	}{
+		"NotDraining": {
+			reason: "Requests should be passed to the wrapped reconciler when the tracker is not draining.",
+			want:   reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"Draining": {
+			reason:   "Requests should be requeued without being reconciled when the tracker is draining.",
+			draining: true,
+			want:     reconcile.Result{Requeue: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{tracker: &Tracker{draining: tc.draining}, wrapped: reconcileFunc(func(_ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			})}
+			got, err := r.Reconcile(reconcile.Request{})
+			if err != nil {
+				t.Fatalf("Reconcile(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
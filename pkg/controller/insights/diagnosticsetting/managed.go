@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsetting
+
+import (
+	"context"
+
+	azureinsights "github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights/insightsapi"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/insights/v1alpha3"
+	azureclients "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/insights"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+)
+
+// Error strings.
+const (
+	errNotDiagnosticSetting    = "managed resource is not a DiagnosticSetting"
+	errCreateDiagnosticSetting = "cannot create DiagnosticSetting"
+	errUpdateDiagnosticSetting = "cannot update DiagnosticSetting"
+	errGetDiagnosticSetting    = "cannot get DiagnosticSetting"
+	errDeleteDiagnosticSetting = "cannot delete DiagnosticSetting"
+)
+
+// Setup adds a controller that reconciles DiagnosticSettings.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
+	name := managed.ControllerName(v1alpha3.DiagnosticSettingGroupKind)
+	throttled := azureclients.NewThrottle()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
+		For(&v1alpha3.DiagnosticSetting{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.DiagnosticSettingList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.DiagnosticSettingGroupVersionKind), managed.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha3.DiagnosticSettingGroupVersionKind),
+			managed.WithConnectionPublishers(),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient()}, throttled, observeOnly)),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+type connecter struct {
+	client client.Client
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	creds, auth, err := azureclients.GetAuthInfo(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	cl := azureinsights.NewDiagnosticSettingsClient(creds[azureclients.CredentialsKeySubscriptionID])
+	cl.Authorizer = auth
+	return &external{client: cl}, nil
+}
+
+type external struct {
+	client insightsapi.DiagnosticSettingsClientAPI
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	d, ok := mg.(*v1alpha3.DiagnosticSetting)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDiagnosticSetting)
+	}
+
+	az, err := e.client.Get(ctx, d.Spec.ResourceID, meta.GetExternalName(d))
+	if azureclients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDiagnosticSetting)
+	}
+
+	insights.UpdateDiagnosticSettingStatusFromAzure(d, az)
+
+	d.SetConditions(runtimev1alpha1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  !insights.DiagnosticSettingNeedsUpdate(d, az),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	d, ok := mg.(*v1alpha3.DiagnosticSetting)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDiagnosticSetting)
+	}
+
+	d.Status.SetConditions(runtimev1alpha1.Creating())
+
+	if err := insights.ValidateDiagnosticSetting(d); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDiagnosticSetting)
+	}
+	if _, err := e.client.CreateOrUpdate(ctx, d.Spec.ResourceID, insights.NewDiagnosticSettingParameters(d), meta.GetExternalName(d)); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDiagnosticSetting)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	d, ok := mg.(*v1alpha3.DiagnosticSetting)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDiagnosticSetting)
+	}
+
+	if err := insights.ValidateDiagnosticSetting(d); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDiagnosticSetting)
+	}
+	if _, err := e.client.CreateOrUpdate(ctx, d.Spec.ResourceID, insights.NewDiagnosticSettingParameters(d), meta.GetExternalName(d)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDiagnosticSetting)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	d, ok := mg.(*v1alpha3.DiagnosticSetting)
+	if !ok {
+		return errors.New(errNotDiagnosticSetting)
+	}
+
+	mg.SetConditions(runtimev1alpha1.Deleting())
+
+	_, err := e.client.Delete(ctx, d.Spec.ResourceID, meta.GetExternalName(d))
+	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteDiagnosticSetting)
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsetting
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	resourcefake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/insights/v1alpha3"
+	"github.com/crossplane/provider-azure/pkg/clients/insights/fake"
+)
+
+const (
+	name        = "coolSetting"
+	uid         = types.UID("definitely-a-uuid")
+	resourceID  = "/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.DBforMySQL/servers/coolServer"
+	workspaceID = "/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.OperationalInsights/workspaces/coolWorkspace"
+	id          = resourceID + "/providers/microsoft.insights/diagnosticSettings/coolSetting"
+	category    = "MySqlSlowLogs"
+)
+
+var (
+	ctx       = context.Background()
+	errorBoom = errors.New("boom")
+)
+
+type testCase struct {
+	name    string
+	e       managed.ExternalClient
+	r       resource.Managed
+	want    resource.Managed
+	wantObs managed.ExternalObservation
+	wantErr error
+}
+
+type diagnosticSettingModifier func(*v1alpha3.DiagnosticSetting)
+
+func withConditions(c ...runtimev1alpha1.Condition) diagnosticSettingModifier {
+	return func(r *v1alpha3.DiagnosticSetting) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withID(id string) diagnosticSettingModifier {
+	return func(r *v1alpha3.DiagnosticSetting) { r.Status.ID = id }
+}
+
+func withoutDestination() diagnosticSettingModifier {
+	return func(r *v1alpha3.DiagnosticSetting) { r.Spec.WorkspaceID = nil }
+}
+
+func diagnosticSetting(dm ...diagnosticSettingModifier) *v1alpha3.DiagnosticSetting {
+	r := &v1alpha3.DiagnosticSetting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			UID:        uid,
+			Finalizers: []string{},
+		},
+		Spec: v1alpha3.DiagnosticSettingSpec{
+			ResourceID: resourceID,
+			DiagnosticSettingProperties: v1alpha3.DiagnosticSettingProperties{
+				WorkspaceID: to.StringPtr(workspaceID),
+				Logs:        []v1alpha3.LogSetting{{Category: category, Enabled: true}},
+			},
+		},
+	}
+	meta.SetExternalName(r, name)
+
+	for _, m := range dm {
+		m(r)
+	}
+
+	return r
+}
+
+// Test that our Reconciler implementation satisfies the Reconciler interface.
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connecter{}
+
+func TestObserve(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotDiagnosticSetting",
+			e:       &external{client: &fake.MockDiagnosticSettingsClient{}},
+			r:       &resourcefake.Managed{},
+			want:    &resourcefake.Managed{},
+			wantErr: errors.New(errNotDiagnosticSetting),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockGet: func(_ context.Context, _ string, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(),
+		},
+		{
+			name: "GetFailed",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockGet: func(_ context.Context, _ string, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{}, errorBoom
+				},
+			}},
+			r:       diagnosticSetting(),
+			want:    diagnosticSetting(),
+			wantErr: errors.Wrap(errorBoom, errGetDiagnosticSetting),
+		},
+		{
+			name: "UpToDate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockGet: func(_ context.Context, _ string, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{
+						ID: to.StringPtr(id),
+						DiagnosticSettings: &insights.DiagnosticSettings{
+							WorkspaceID: to.StringPtr(workspaceID),
+							Logs:        &[]insights.LogSettings{{Category: to.StringPtr(category), Enabled: to.BoolPtr(true)}},
+						},
+					}, nil
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(withConditions(runtimev1alpha1.Available()), withID(id)),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "NeedsUpdate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockGet: func(_ context.Context, _ string, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{
+						ID: to.StringPtr(id),
+						DiagnosticSettings: &insights.DiagnosticSettings{
+							WorkspaceID: to.StringPtr(workspaceID),
+							Logs:        &[]insights.LogSettings{{Category: to.StringPtr(category), Enabled: to.BoolPtr(false)}},
+						},
+					}, nil
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(withConditions(runtimev1alpha1.Available()), withID(id)),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obs, err := tc.e.Observe(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantObs, obs); diff != "" {
+				t.Errorf("tc.e.Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotDiagnosticSetting",
+			e:       &external{client: &fake.MockDiagnosticSettingsClient{}},
+			r:       &resourcefake.Managed{},
+			want:    &resourcefake.Managed{},
+			wantErr: errors.New(errNotDiagnosticSetting),
+		},
+		{
+			name:    "NoDestination",
+			e:       &external{client: &fake.MockDiagnosticSettingsClient{}},
+			r:       diagnosticSetting(withoutDestination()),
+			want:    diagnosticSetting(withoutDestination(), withConditions(runtimev1alpha1.Creating())),
+			wantErr: errors.Wrap(errors.New("at least one of workspaceId, storageAccountId, or eventHubAuthorizationRuleId must be specified"), errCreateDiagnosticSetting),
+		},
+		{
+			name: "SuccessfulCreate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockCreateOrUpdate: func(_ context.Context, uri string, p insights.DiagnosticSettingsResource, n string) (insights.DiagnosticSettingsResource, error) {
+					if uri != resourceID || n != name || to.String(p.WorkspaceID) != workspaceID {
+						return insights.DiagnosticSettingsResource{}, errorBoom
+					}
+					return insights.DiagnosticSettingsResource{}, nil
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(withConditions(runtimev1alpha1.Creating())),
+		},
+		{
+			name: "FailedCreate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ insights.DiagnosticSettingsResource, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{}, errorBoom
+				},
+			}},
+			r:       diagnosticSetting(),
+			want:    diagnosticSetting(withConditions(runtimev1alpha1.Creating())),
+			wantErr: errors.Wrap(errorBoom, errCreateDiagnosticSetting),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Create(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotDiagnosticSetting",
+			e:       &external{client: &fake.MockDiagnosticSettingsClient{}},
+			r:       &resourcefake.Managed{},
+			want:    &resourcefake.Managed{},
+			wantErr: errors.New(errNotDiagnosticSetting),
+		},
+		{
+			name: "SuccessfulUpdate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, p insights.DiagnosticSettingsResource, _ string) (insights.DiagnosticSettingsResource, error) {
+					if p.Logs == nil || len(*p.Logs) != 1 || to.String((*p.Logs)[0].Category) != category {
+						return insights.DiagnosticSettingsResource{}, errorBoom
+					}
+					return insights.DiagnosticSettingsResource{}, nil
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(),
+		},
+		{
+			name: "FailedUpdate",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ insights.DiagnosticSettingsResource, _ string) (insights.DiagnosticSettingsResource, error) {
+					return insights.DiagnosticSettingsResource{}, errorBoom
+				},
+			}},
+			r:       diagnosticSetting(),
+			want:    diagnosticSetting(),
+			wantErr: errors.Wrap(errorBoom, errUpdateDiagnosticSetting),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Update(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotDiagnosticSetting",
+			e:       &external{client: &fake.MockDiagnosticSettingsClient{}},
+			r:       &resourcefake.Managed{},
+			want:    &resourcefake.Managed{},
+			wantErr: errors.New(errNotDiagnosticSetting),
+		},
+		{
+			name: "SuccessfulDelete",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (autorest.Response, error) {
+					return autorest.Response{}, nil
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (autorest.Response, error) {
+					return autorest.Response{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    diagnosticSetting(),
+			want: diagnosticSetting(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "FailedDelete",
+			e: &external{client: &fake.MockDiagnosticSettingsClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (autorest.Response, error) {
+					return autorest.Response{}, errorBoom
+				},
+			}},
+			r:       diagnosticSetting(),
+			want:    diagnosticSetting(withConditions(runtimev1alpha1.Deleting())),
+			wantErr: errors.Wrap(errorBoom, errDeleteDiagnosticSetting),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.e.Delete(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jitter spreads out the requeues of reconcilers that manage many
+// resources, so that they don't all call the Azure API at the same time.
+package jitter
+
+import (
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultMaxFactor is the default maximum fraction of a requeue interval that
+// is added to it as jitter.
+const DefaultMaxFactor = 0.1
+
+// A Reconciler adds jitter to the requeue interval returned by the reconciler
+// it wraps.
+type Reconciler struct {
+	wrapped reconcile.Reconciler
+	factor  float64
+}
+
+// NewReconciler returns a Reconciler that adds up to the supplied maximum
+// fraction of the requeue interval returned by the supplied reconciler to it
+// as jitter. Jitter is disabled if the factor is not positive.
+func NewReconciler(r reconcile.Reconciler, maxFactor float64) *Reconciler {
+	return &Reconciler{wrapped: r, factor: maxFactor}
+}
+
+// Reconcile the supplied request, adding jitter to any requeue interval.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.wrapped.Reconcile(req)
+	if result.RequeueAfter > 0 && r.factor > 0 {
+		result.RequeueAfter = wait.Jitter(result.RequeueAfter, r.factor)
+	}
+	return result, err
+}
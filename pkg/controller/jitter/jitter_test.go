@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	interval := 10 * time.Minute
+
+	type want struct {
+		min time.Duration
+		max time.Duration
+		err error
+	}
+
+	cases := map[string]struct {
+		r      reconcile.Reconciler
+		factor float64
+		want   want
+	}{
+		"NoRequeue": {
+			r:      reconcileFn(func(reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil }),
+			factor: 0.5,
+			want:   want{},
+		},
+		"Jittered": {
+			r: reconcileFn(func(reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: interval}, nil
+			}),
+			factor: 0.5,
+			want:   want{min: interval, max: interval + interval/2},
+		},
+		"Disabled": {
+			r: reconcileFn(func(reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: interval}, nil
+			}),
+			factor: 0,
+			want:   want{min: interval, max: interval},
+		},
+		"Error": {
+			r: reconcileFn(func(reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: interval}, errBoom
+			}),
+			factor: 0.5,
+			want:   want{min: interval, max: interval + interval/2, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.r, tc.factor)
+			got, err := r.Reconcile(reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if got.RequeueAfter < tc.want.min || got.RequeueAfter > tc.want.max {
+				t.Errorf("r.Reconcile(...): want RequeueAfter in [%s, %s], got %s", tc.want.min, tc.want.max, got.RequeueAfter)
+			}
+		})
+	}
+}
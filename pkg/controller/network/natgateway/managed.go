@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateway
+
+import (
+	"context"
+
+	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
+	azureclients "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+)
+
+// Error strings.
+const (
+	errNotNATGateway    = "managed resource is not a NATGateway"
+	errCreateNATGateway = "cannot create NATGateway"
+	errUpdateNATGateway = "cannot update NATGateway"
+	errGetNATGateway    = "cannot get NATGateway"
+	errDeleteNATGateway = "cannot delete NATGateway"
+)
+
+// Setup adds a controller that reconciles NATGateways, ignoring the supplied
+// tags when determining whether they are up to date.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azureclients.IgnoredTags) error {
+	name := managed.ControllerName(v1alpha3.NATGatewayGroupKind)
+	throttled := azureclients.NewThrottle()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
+		For(&v1alpha3.NATGateway{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.NATGatewayList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.NATGatewayGroupVersionKind), managed.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha3.NATGatewayGroupVersionKind),
+			managed.WithConnectionPublishers(),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+type connecter struct {
+	client      client.Client
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	creds, auth, err := azureclients.GetAuthInfo(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	cl := azurenetwork.NewNatGatewaysClient(creds[azureclients.CredentialsKeySubscriptionID])
+	cl.Authorizer = auth
+	return &external{client: cl, ignored: c.ignored, observeOnly: c.observeOnly}, nil
+}
+
+type external struct {
+	client      networkapi.NatGatewaysClientAPI
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	g, ok := mg.(*v1alpha3.NATGateway)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotNATGateway)
+	}
+
+	az, err := e.client.Get(ctx, g.Spec.ResourceGroupName, meta.GetExternalName(g), "")
+	if azureclients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetNATGateway)
+	}
+	if err := azureclients.ValidateOwnership(g, g.Status.ID, azureclients.ToString(az.ID), az.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(g) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetNATGateway)
+	}
+
+	network.UpdateNATGatewayStatusFromAzure(g, az)
+	g.SetConditions(network.Condition(g.Status.State))
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  network.Failed(g.Status.State) == nil && !network.NATGatewayNeedsUpdate(g, az, e.ignored),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	g, ok := mg.(*v1alpha3.NATGateway)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotNATGateway)
+	}
+
+	g.Status.SetConditions(runtimev1alpha1.Creating())
+
+	up := network.NewNATGatewayParameters(g)
+	up.Tags = azureclients.WithOwnershipTags(up.Tags, v1alpha3.NATGatewayKind, g)
+	if _, err := e.client.CreateOrUpdate(ctx, g.Spec.ResourceGroupName, meta.GetExternalName(g), up); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateNATGateway)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	g, ok := mg.(*v1alpha3.NATGateway)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNATGateway)
+	}
+
+	az, err := e.client.Get(ctx, g.Spec.ResourceGroupName, meta.GetExternalName(g), "")
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetNATGateway)
+	}
+
+	up := network.NewNATGatewayParameters(g)
+	up.Tags = e.ignored.Preserve(up.Tags, az.Tags)
+	if _, err := e.client.CreateOrUpdate(ctx, g.Spec.ResourceGroupName, meta.GetExternalName(g), up); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateNATGateway)
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(network.Failed(g.Status.State), errUpdateNATGateway)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	g, ok := mg.(*v1alpha3.NATGateway)
+	if !ok {
+		return errors.New(errNotNATGateway)
+	}
+
+	mg.SetConditions(runtimev1alpha1.Deleting())
+
+	_, err := e.client.Delete(ctx, g.Spec.ResourceGroupName, meta.GetExternalName(g))
+	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteNATGateway)
+}
@@ -0,0 +1,468 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateway
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	azurenetwork "github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/clients/network/fake"
+)
+
+const (
+	name              = "coolGateway"
+	uid               = types.UID("definitely-a-uuid")
+	resourceGroupName = "coolRG"
+	location          = "coolplace"
+	id                = "/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.Network/natGateways/coolGateway"
+)
+
+var (
+	ctx         = context.Background()
+	errorBoom   = errors.New("boom")
+	idleTimeout = 10
+)
+
+type testCase struct {
+	name    string
+	e       managed.ExternalClient
+	r       resource.Managed
+	want    resource.Managed
+	wantObs managed.ExternalObservation
+	wantErr error
+}
+
+type natGatewayModifier func(*v1alpha3.NATGateway)
+
+func withConditions(c ...runtimev1alpha1.Condition) natGatewayModifier {
+	return func(r *v1alpha3.NATGateway) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withState(s string) natGatewayModifier {
+	return func(r *v1alpha3.NATGateway) { r.Status.State = s }
+}
+
+func withID(id string) natGatewayModifier {
+	return func(r *v1alpha3.NATGateway) { r.Status.ID = id }
+}
+
+func withDeletionTimestamp(t metav1.Time) natGatewayModifier {
+	return func(r *v1alpha3.NATGateway) { r.SetDeletionTimestamp(&t) }
+}
+
+func natGateway(gm ...natGatewayModifier) *v1alpha3.NATGateway {
+	r := &v1alpha3.NATGateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			UID:        uid,
+			Finalizers: []string{},
+		},
+		Spec: v1alpha3.NATGatewaySpec{
+			ResourceGroupName: resourceGroupName,
+			NATGatewayPropertiesFormat: v1alpha3.NATGatewayPropertiesFormat{
+				IdleTimeoutInMinutes: &idleTimeout,
+			},
+			Location: location,
+		},
+	}
+	meta.SetExternalName(r, name)
+
+	for _, m := range gm {
+		m(r)
+	}
+
+	return r
+}
+
+// Test that our Reconciler implementation satisfies the Reconciler interface.
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connecter{}
+
+func TestObserve(t *testing.T) {
+	owned := azure.WithOwnershipTags(nil, v1alpha3.NATGatewayKind, natGateway())
+	deleted := metav1.Now()
+	cases := []testCase{
+		{
+			name:    "NotNATGateway",
+			e:       &external{client: &fake.MockNatGatewaysClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotNATGateway),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    natGateway(),
+			want: natGateway(),
+		},
+		{
+			name: "GetFailed",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, errorBoom
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(),
+			wantErr: errors.Wrap(errorBoom, errGetNATGateway),
+		},
+		{
+			name: "NotOwned",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{ID: azure.ToStringPtr(id)}, nil
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(),
+			wantErr: errors.Wrap(azure.ValidateOwnership(natGateway(), "", id, nil), errGetNATGateway),
+		},
+		{
+			name: "NotOwnedDeleted",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{ID: azure.ToStringPtr(id)}, nil
+				},
+			}},
+			r:       natGateway(withDeletionTimestamp(deleted)),
+			want:    natGateway(withDeletionTimestamp(deleted)),
+			wantObs: managed.ExternalObservation{ResourceExists: false},
+		},
+		{
+			name: "NotOwnedObserveOnly",
+			e: &external{observeOnly: true, client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{
+						ID: azure.ToStringPtr(id),
+						NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+							IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+							ProvisioningState:    azure.ToStringPtr("Succeeded"),
+						},
+					}, nil
+				},
+			}},
+			r: natGateway(),
+			want: natGateway(
+				withConditions(runtimev1alpha1.Available()),
+				withState("Succeeded"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "UpToDate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+							IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+							ProvisioningState:    azure.ToStringPtr("Succeeded"),
+						},
+					}, nil
+				},
+			}},
+			r: natGateway(),
+			want: natGateway(
+				withConditions(runtimev1alpha1.Available()),
+				withState("Succeeded"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "Failed",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+							IdleTimeoutInMinutes: azure.ToInt32(&idleTimeout),
+							ProvisioningState:    azure.ToStringPtr("Failed"),
+						},
+					}, nil
+				},
+			}},
+			r: natGateway(),
+			want: natGateway(
+				withConditions(azurenetwork.Condition("Failed")),
+				withState("Failed"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "NeedsUpdate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+							IdleTimeoutInMinutes: azure.ToInt32Ptr(4),
+							ProvisioningState:    azure.ToStringPtr("Succeeded"),
+						},
+					}, nil
+				},
+			}},
+			r: natGateway(),
+			want: natGateway(
+				withConditions(runtimev1alpha1.Available()),
+				withState("Succeeded"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obs, err := tc.e.Observe(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantObs, obs); diff != "" {
+				t.Errorf("tc.e.Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotNATGateway",
+			e:       &external{client: &fake.MockNatGatewaysClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotNATGateway),
+		},
+		{
+			name: "SuccessfulCreate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.NatGateway) (network.NatGatewaysCreateOrUpdateFuture, error) {
+					return network.NatGatewaysCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    natGateway(),
+			want: natGateway(withConditions(runtimev1alpha1.Creating())),
+		},
+		{
+			name: "FailedCreate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.NatGateway) (network.NatGatewaysCreateOrUpdateFuture, error) {
+					return network.NatGatewaysCreateOrUpdateFuture{}, errorBoom
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(withConditions(runtimev1alpha1.Creating())),
+			wantErr: errors.Wrap(errorBoom, errCreateNATGateway),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Create(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotNATGateway",
+			e:       &external{client: &fake.MockNatGatewaysClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotNATGateway),
+		},
+		{
+			name: "GetFailed",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, errorBoom
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(),
+			wantErr: errors.Wrap(errorBoom, errGetNATGateway),
+		},
+		{
+			name: "SuccessfulUpdate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, g network.NatGateway) (network.NatGatewaysCreateOrUpdateFuture, error) {
+					if azure.ToInt(g.IdleTimeoutInMinutes) != idleTimeout {
+						return network.NatGatewaysCreateOrUpdateFuture{}, errorBoom
+					}
+					return network.NatGatewaysCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    natGateway(),
+			want: natGateway(),
+		},
+		{
+			name: "RetriedFailedOperation",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.NatGateway) (network.NatGatewaysCreateOrUpdateFuture, error) {
+					return network.NatGatewaysCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:       natGateway(withState(azurenetwork.ProvisioningStateFailed)),
+			want:    natGateway(withState(azurenetwork.ProvisioningStateFailed)),
+			wantErr: errors.Wrap(azurenetwork.Failed(azurenetwork.ProvisioningStateFailed), errUpdateNATGateway),
+		},
+		{
+			name: "FailedUpdate",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.NatGateway, error) {
+					return network.NatGateway{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.NatGateway) (network.NatGatewaysCreateOrUpdateFuture, error) {
+					return network.NatGatewaysCreateOrUpdateFuture{}, errorBoom
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(),
+			wantErr: errors.Wrap(errorBoom, errUpdateNATGateway),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Update(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotNATGateway",
+			e:       &external{client: &fake.MockNatGatewaysClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotNATGateway),
+		},
+		{
+			name: "SuccessfulDelete",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.NatGatewaysDeleteFuture, error) {
+					return network.NatGatewaysDeleteFuture{}, nil
+				},
+			}},
+			r:    natGateway(),
+			want: natGateway(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.NatGatewaysDeleteFuture, error) {
+					return network.NatGatewaysDeleteFuture{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    natGateway(),
+			want: natGateway(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "FailedDelete",
+			e: &external{client: &fake.MockNatGatewaysClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.NatGatewaysDeleteFuture, error) {
+					return network.NatGatewaysDeleteFuture{}, errorBoom
+				},
+			}},
+			r:       natGateway(),
+			want:    natGateway(withConditions(runtimev1alpha1.Deleting())),
+			wantErr: errors.Wrap(errorBoom, errDeleteNATGateway),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.e.Delete(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
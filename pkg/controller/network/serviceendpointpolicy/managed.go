@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceendpointpolicy
+
+import (
+	"context"
+
+	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
+	azureclients "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+)
+
+// Error strings.
+const (
+	errNotServiceEndpointPolicy    = "managed resource is not a ServiceEndpointPolicy"
+	errCreateServiceEndpointPolicy = "cannot create ServiceEndpointPolicy"
+	errUpdateServiceEndpointPolicy = "cannot update ServiceEndpointPolicy"
+	errGetServiceEndpointPolicy    = "cannot get ServiceEndpointPolicy"
+	errDeleteServiceEndpointPolicy = "cannot delete ServiceEndpointPolicy"
+)
+
+// Setup adds a controller that reconciles ServiceEndpointPolicies, ignoring
+// the supplied tags when determining whether they are up to date.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azureclients.IgnoredTags) error {
+	name := managed.ControllerName(v1alpha3.ServiceEndpointPolicyGroupKind)
+	throttled := azureclients.NewThrottle()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
+		For(&v1alpha3.ServiceEndpointPolicy{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.ServiceEndpointPolicyList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.ServiceEndpointPolicyGroupVersionKind), managed.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha3.ServiceEndpointPolicyGroupVersionKind),
+			managed.WithConnectionPublishers(),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+type connecter struct {
+	client      client.Client
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	creds, auth, err := azureclients.GetAuthInfo(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	cl := azurenetwork.NewServiceEndpointPoliciesClient(creds[azureclients.CredentialsKeySubscriptionID])
+	cl.Authorizer = auth
+	return &external{client: cl, ignored: c.ignored, observeOnly: c.observeOnly}, nil
+}
+
+type external struct {
+	client      networkapi.ServiceEndpointPoliciesClientAPI
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	p, ok := mg.(*v1alpha3.ServiceEndpointPolicy)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotServiceEndpointPolicy)
+	}
+
+	az, err := e.client.Get(ctx, p.Spec.ResourceGroupName, meta.GetExternalName(p), "")
+	if azureclients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetServiceEndpointPolicy)
+	}
+	if err := azureclients.ValidateOwnership(p, p.Status.ID, azureclients.ToString(az.ID), az.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(p) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetServiceEndpointPolicy)
+	}
+
+	network.UpdateServiceEndpointPolicyStatusFromAzure(p, az)
+	p.SetConditions(network.Condition(p.Status.State))
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  network.Failed(p.Status.State) == nil && !network.ServiceEndpointPolicyNeedsUpdate(p, az, e.ignored),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	p, ok := mg.(*v1alpha3.ServiceEndpointPolicy)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotServiceEndpointPolicy)
+	}
+
+	p.Status.SetConditions(runtimev1alpha1.Creating())
+
+	up := network.NewServiceEndpointPolicyParameters(p)
+	up.Tags = azureclients.WithOwnershipTags(up.Tags, v1alpha3.ServiceEndpointPolicyKind, p)
+	if _, err := e.client.CreateOrUpdate(ctx, p.Spec.ResourceGroupName, meta.GetExternalName(p), up); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateServiceEndpointPolicy)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	p, ok := mg.(*v1alpha3.ServiceEndpointPolicy)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotServiceEndpointPolicy)
+	}
+
+	az, err := e.client.Get(ctx, p.Spec.ResourceGroupName, meta.GetExternalName(p), "")
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetServiceEndpointPolicy)
+	}
+
+	up := network.NewServiceEndpointPolicyParameters(p)
+	up.Tags = e.ignored.Preserve(up.Tags, az.Tags)
+	if _, err := e.client.CreateOrUpdate(ctx, p.Spec.ResourceGroupName, meta.GetExternalName(p), up); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateServiceEndpointPolicy)
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(network.Failed(p.Status.State), errUpdateServiceEndpointPolicy)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	p, ok := mg.(*v1alpha3.ServiceEndpointPolicy)
+	if !ok {
+		return errors.New(errNotServiceEndpointPolicy)
+	}
+
+	mg.SetConditions(runtimev1alpha1.Deleting())
+
+	_, err := e.client.Delete(ctx, p.Spec.ResourceGroupName, meta.GetExternalName(p))
+	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteServiceEndpointPolicy)
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceendpointpolicy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	azurenetwork "github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/clients/network/fake"
+)
+
+const (
+	name              = "coolPolicy"
+	uid               = types.UID("definitely-a-uuid")
+	resourceGroupName = "coolRG"
+	location          = "coolplace"
+	id                = "/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.Network/serviceEndpointPolicies/coolPolicy"
+)
+
+var (
+	ctx       = context.Background()
+	errorBoom = errors.New("boom")
+	account   = "/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.Storage/storageAccounts/coolaccount"
+)
+
+type testCase struct {
+	name    string
+	e       managed.ExternalClient
+	r       resource.Managed
+	want    resource.Managed
+	wantObs managed.ExternalObservation
+	wantErr error
+}
+
+type policyModifier func(*v1alpha3.ServiceEndpointPolicy)
+
+func withConditions(c ...runtimev1alpha1.Condition) policyModifier {
+	return func(r *v1alpha3.ServiceEndpointPolicy) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withState(s string) policyModifier {
+	return func(r *v1alpha3.ServiceEndpointPolicy) { r.Status.State = s }
+}
+
+func withID(id string) policyModifier {
+	return func(r *v1alpha3.ServiceEndpointPolicy) { r.Status.ID = id }
+}
+
+func policy(gm ...policyModifier) *v1alpha3.ServiceEndpointPolicy {
+	r := &v1alpha3.ServiceEndpointPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			UID:        uid,
+			Finalizers: []string{},
+		},
+		Spec: v1alpha3.ServiceEndpointPolicySpec{
+			ResourceGroupName: resourceGroupName,
+			ServiceEndpointPolicyPropertiesFormat: v1alpha3.ServiceEndpointPolicyPropertiesFormat{
+				ServiceEndpointPolicyDefinitions: []v1alpha3.ServiceEndpointPolicyDefinition{{
+					Name:             "coolDefinition",
+					Service:          "Microsoft.Storage",
+					ServiceResources: []string{account},
+				}},
+			},
+			Location: location,
+		},
+	}
+	meta.SetExternalName(r, name)
+
+	for _, m := range gm {
+		m(r)
+	}
+
+	return r
+}
+
+// Test that our Reconciler implementation satisfies the Reconciler interface.
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connecter{}
+
+func TestObserve(t *testing.T) {
+	owned := azure.WithOwnershipTags(nil, v1alpha3.ServiceEndpointPolicyKind, policy())
+	cases := []testCase{
+		{
+			name:    "NotServiceEndpointPolicy",
+			e:       &external{client: &fake.MockServiceEndpointPoliciesClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotServiceEndpointPolicy),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    policy(),
+			want: policy(),
+		},
+		{
+			name: "GetFailed",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{}, errorBoom
+				},
+			}},
+			r:       policy(),
+			want:    policy(),
+			wantErr: errors.Wrap(errorBoom, errGetServiceEndpointPolicy),
+		},
+		{
+			name: "NotOwned",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{ID: azure.ToStringPtr(id)}, nil
+				},
+			}},
+			r:       policy(),
+			want:    policy(),
+			wantErr: errors.Wrap(azure.ValidateOwnership(policy(), "", id, nil), errGetServiceEndpointPolicy),
+		},
+		{
+			name: "UpToDate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						ServiceEndpointPolicyPropertiesFormat: &network.ServiceEndpointPolicyPropertiesFormat{
+							ServiceEndpointPolicyDefinitions: &[]network.ServiceEndpointPolicyDefinition{{
+								Name: azure.ToStringPtr("coolDefinition"),
+								ServiceEndpointPolicyDefinitionPropertiesFormat: &network.ServiceEndpointPolicyDefinitionPropertiesFormat{
+									Service:          azure.ToStringPtr("Microsoft.Storage"),
+									ServiceResources: &[]string{account},
+								},
+							}},
+							ProvisioningState: azure.ToStringPtr("Succeeded"),
+						},
+					}, nil
+				},
+			}},
+			r: policy(),
+			want: policy(
+				withConditions(runtimev1alpha1.Available()),
+				withState("Succeeded"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "Updating",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						ServiceEndpointPolicyPropertiesFormat: &network.ServiceEndpointPolicyPropertiesFormat{
+							ServiceEndpointPolicyDefinitions: &[]network.ServiceEndpointPolicyDefinition{{
+								Name: azure.ToStringPtr("coolDefinition"),
+								ServiceEndpointPolicyDefinitionPropertiesFormat: &network.ServiceEndpointPolicyDefinitionPropertiesFormat{
+									Service:          azure.ToStringPtr("Microsoft.Storage"),
+									ServiceResources: &[]string{account},
+								},
+							}},
+							ProvisioningState: azure.ToStringPtr("Updating"),
+						},
+					}, nil
+				},
+			}},
+			r: policy(),
+			want: policy(
+				withConditions(azurenetwork.Condition("Updating")),
+				withState("Updating"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		{
+			name: "NeedsUpdate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{
+						ID:   azure.ToStringPtr(id),
+						Tags: owned,
+						ServiceEndpointPolicyPropertiesFormat: &network.ServiceEndpointPolicyPropertiesFormat{
+							ServiceEndpointPolicyDefinitions: &[]network.ServiceEndpointPolicyDefinition{{
+								Name: azure.ToStringPtr("coolDefinition"),
+								ServiceEndpointPolicyDefinitionPropertiesFormat: &network.ServiceEndpointPolicyDefinitionPropertiesFormat{
+									Service:          azure.ToStringPtr("Microsoft.Storage"),
+									ServiceResources: &[]string{"/subscriptions/sub/resourceGroups/coolRG/providers/Microsoft.Storage/storageAccounts/otheraccount"},
+								},
+							}},
+							ProvisioningState: azure.ToStringPtr("Succeeded"),
+						},
+					}, nil
+				},
+			}},
+			r: policy(),
+			want: policy(
+				withConditions(runtimev1alpha1.Available()),
+				withState("Succeeded"),
+				withID(id),
+			),
+			wantObs: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obs, err := tc.e.Observe(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantObs, obs); diff != "" {
+				t.Errorf("tc.e.Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotServiceEndpointPolicy",
+			e:       &external{client: &fake.MockServiceEndpointPoliciesClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotServiceEndpointPolicy),
+		},
+		{
+			name: "SuccessfulCreate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.ServiceEndpointPolicy) (network.ServiceEndpointPoliciesCreateOrUpdateFuture, error) {
+					return network.ServiceEndpointPoliciesCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    policy(),
+			want: policy(withConditions(runtimev1alpha1.Creating())),
+		},
+		{
+			name: "FailedCreate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.ServiceEndpointPolicy) (network.ServiceEndpointPoliciesCreateOrUpdateFuture, error) {
+					return network.ServiceEndpointPoliciesCreateOrUpdateFuture{}, errorBoom
+				},
+			}},
+			r:       policy(),
+			want:    policy(withConditions(runtimev1alpha1.Creating())),
+			wantErr: errors.Wrap(errorBoom, errCreateServiceEndpointPolicy),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Create(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotServiceEndpointPolicy",
+			e:       &external{client: &fake.MockServiceEndpointPoliciesClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotServiceEndpointPolicy),
+		},
+		{
+			name: "GetFailed",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{}, errorBoom
+				},
+			}},
+			r:       policy(),
+			want:    policy(),
+			wantErr: errors.Wrap(errorBoom, errGetServiceEndpointPolicy),
+		},
+		{
+			name: "SuccessfulUpdate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, p network.ServiceEndpointPolicy) (network.ServiceEndpointPoliciesCreateOrUpdateFuture, error) {
+					if len(*p.ServiceEndpointPolicyDefinitions) != 1 {
+						return network.ServiceEndpointPoliciesCreateOrUpdateFuture{}, errorBoom
+					}
+					return network.ServiceEndpointPoliciesCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    policy(),
+			want: policy(),
+		},
+		{
+			name: "FailedUpdate",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (network.ServiceEndpointPolicy, error) {
+					return network.ServiceEndpointPolicy{}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.ServiceEndpointPolicy) (network.ServiceEndpointPoliciesCreateOrUpdateFuture, error) {
+					return network.ServiceEndpointPoliciesCreateOrUpdateFuture{}, errorBoom
+				},
+			}},
+			r:       policy(),
+			want:    policy(),
+			wantErr: errors.Wrap(errorBoom, errUpdateServiceEndpointPolicy),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.e.Update(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []testCase{
+		{
+			name:    "NotServiceEndpointPolicy",
+			e:       &external{client: &fake.MockServiceEndpointPoliciesClient{}},
+			r:       &v1alpha3.Subnet{},
+			want:    &v1alpha3.Subnet{},
+			wantErr: errors.New(errNotServiceEndpointPolicy),
+		},
+		{
+			name: "SuccessfulDelete",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.ServiceEndpointPoliciesDeleteFuture, error) {
+					return network.ServiceEndpointPoliciesDeleteFuture{}, nil
+				},
+			}},
+			r:    policy(),
+			want: policy(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "NotFound",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.ServiceEndpointPoliciesDeleteFuture, error) {
+					return network.ServiceEndpointPoliciesDeleteFuture{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    policy(),
+			want: policy(withConditions(runtimev1alpha1.Deleting())),
+		},
+		{
+			name: "FailedDelete",
+			e: &external{client: &fake.MockServiceEndpointPoliciesClient{
+				MockDelete: func(_ context.Context, _ string, _ string) (network.ServiceEndpointPoliciesDeleteFuture, error) {
+					return network.ServiceEndpointPoliciesDeleteFuture{}, errorBoom
+				},
+			}},
+			r:       policy(),
+			want:    policy(withConditions(runtimev1alpha1.Deleting())),
+			wantErr: errors.Wrap(errorBoom, errDeleteServiceEndpointPolicy),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.e.Delete(ctx, tc.r)
+
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,12 +18,15 @@ package subnet
 
 import (
 	"context"
+	"strings"
 
 	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +38,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azureclients "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -44,22 +49,38 @@ const (
 	errUpdateSubnet = "cannot update Subnet"
 	errGetSubnet    = "cannot get Subnet"
 	errDeleteSubnet = "cannot delete Subnet"
+
+	errListVirtualNetworks = "cannot list VirtualNetworks"
+	errFmtInlineConflict   = "subnet is declared inline by VirtualNetwork %q"
+	errFmtPrefixInUse      = "cannot change address prefixes from %q to %q while %d IP configurations are allocated in the subnet"
 )
 
 // Setup adds a controller that reconciles Subnets.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
 	name := managed.ControllerName(v1alpha3.SubnetGroupKind)
+	throttled := azureclients.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.Subnet{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.SubnetList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.SubnetGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.SubnetGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient()}, throttled, observeOnly, chain.WithLocks(subnetID))),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+// subnetID returns the Azure resource ID of the supplied Subnet, if known.
+func subnetID(mg resource.Managed) string {
+	cr, ok := mg.(*v1alpha3.Subnet)
+	if !ok {
+		return ""
+	}
+	return cr.Status.ID
 }
 
 type connecter struct {
@@ -73,10 +94,28 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 	cl := azurenetwork.NewSubnetsClient(creds[azureclients.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	return &external{kube: c.client, client: cl}, nil
 }
 
-type external struct{ client networkapi.SubnetsClientAPI }
+type external struct {
+	kube   client.Client
+	client networkapi.SubnetsClientAPI
+}
+
+// checkInlineSubnets returns an error if the supplied Subnet is also declared
+// inline by a VirtualNetwork.
+func (e *external) checkInlineSubnets(ctx context.Context, s *v1alpha3.Subnet) error {
+	l := &v1alpha3.VirtualNetworkList{}
+	if err := e.kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListVirtualNetworks)
+	}
+	for i := range l.Items {
+		if network.InlineSubnetConflicts(&l.Items[i], s) {
+			return errors.Errorf(errFmtInlineConflict, l.Items[i].GetName())
+		}
+	}
+	return nil
+}
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	s, ok := mg.(*v1alpha3.Subnet)
@@ -93,7 +132,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	network.UpdateSubnetStatusFromAzure(s, az)
-	s.SetConditions(runtimev1alpha1.Available())
+	s.SetConditions(network.Condition(s.Status.State))
 
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
@@ -111,9 +150,19 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	s.Status.SetConditions(runtimev1alpha1.Creating())
 
+	if err := azureclients.ValidateName(v1alpha3.SubnetKind, meta.GetExternalName(s)); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
+	}
+	if err := network.ValidateSubnetAddressPrefixes(s.Spec.SubnetPropertiesFormat); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
+	}
+	if err := e.checkInlineSubnets(ctx, s); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
+	}
+
 	snet := network.NewSubnetParameters(s)
 	if _, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
+		return managed.ExternalCreation{}, errors.Wrap(azureclients.WaitForResourceGroup(s, s.Spec.ResourceGroupName, err), errCreateSubnet)
 	}
 
 	return managed.ExternalCreation{}, nil
@@ -125,18 +174,45 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotSubnet)
 	}
 
+	// Azure rejects updates while another operation is in progress. We'll be
+	// requeued and try again once it has completed.
+	if network.IsOperationInProgress(s.Status.State) {
+		return managed.ExternalUpdate{}, nil
+	}
+
 	az, err := e.client.Get(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), "")
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetSubnet)
 	}
 
-	if network.SubnetNeedsUpdate(s, az) {
+	// Resubmitting a subnet whose last operation failed retries it.
+	failed := network.Failed(s.Status.State)
+	drift := network.SubnetDrift(s, az)
+
+	// Differences caused by a change to our spec are not drift, and make any
+	// drift corrected before the change irrelevant.
+	specChanged := s.GetGeneration() != s.Status.ObservedGeneration
+	if specChanged {
+		azureclients.ClearDriftCorrected(&s.Status.ConditionedStatus)
+	}
+	if len(drift) > 0 || failed != nil {
+		if err := network.ValidateSubnetAddressPrefixes(s.Spec.SubnetPropertiesFormat); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateSubnet)
+		}
 		snet := network.NewSubnetParameters(s)
+		network.PreserveSubnetNATGateway(snet.SubnetPropertiesFormat, az.SubnetPropertiesFormat)
 		if _, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet); err != nil {
+			if network.SubnetAddressPrefixChanged(s, az) && network.IsSubnetInUse(err) {
+				return managed.ExternalUpdate{}, errors.Wrapf(err, errFmtPrefixInUse, strings.Join(network.SubnetAddressPrefixes(az.SubnetPropertiesFormat), ","), strings.Join(network.SubnetAddressPrefixes(snet.SubnetPropertiesFormat), ","), network.AllocatedIPConfigurations(az))
+			}
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateSubnet)
 		}
+		if len(drift) > 0 && !specChanged {
+			s.SetConditions(azureclients.DriftCorrected(drift))
+		}
 	}
-	return managed.ExternalUpdate{}, nil
+	s.Status.ObservedGeneration = s.GetGeneration()
+	return managed.ExternalUpdate{}, errors.Wrap(failed, errUpdateSubnet)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
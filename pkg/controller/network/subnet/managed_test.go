@@ -23,9 +23,11 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
@@ -36,6 +38,7 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
+	azurenetwork "github.com/crossplane/provider-azure/pkg/clients/network"
 	"github.com/crossplane/provider-azure/pkg/clients/network/fake"
 )
 
@@ -66,9 +69,23 @@ func withConditions(c ...runtimev1alpha1.Condition) subnetModifier {
 	return func(r *v1alpha3.Subnet) { r.Status.ConditionedStatus.Conditions = c }
 }
 
+func withGeneration(current, observed int64) subnetModifier {
+	return func(r *v1alpha3.Subnet) {
+		r.SetGeneration(current)
+		r.Status.ObservedGeneration = observed
+	}
+}
+
 func withState(s string) subnetModifier {
 	return func(r *v1alpha3.Subnet) { r.Status.State = s }
 }
+
+func withAddressPrefix(p string, available int) subnetModifier {
+	return func(r *v1alpha3.Subnet) {
+		r.Status.AddressPrefix = p
+		r.Status.AvailableIPAddressCount = &available
+	}
+}
 func subnet(sm ...subnetModifier) *v1alpha3.Subnet {
 	r := &v1alpha3.Subnet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -110,7 +127,7 @@ func TestCreate(t *testing.T) {
 		},
 		{
 			name: "SuccessfulCreate",
-			e: &external{client: &fake.MockSubnetsClient{
+			e: &external{kube: &test.MockClient{MockList: test.NewMockListFn(nil)}, client: &fake.MockSubnetsClient{
 				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ network.Subnet) (network.SubnetsCreateOrUpdateFuture, error) {
 					return network.SubnetsCreateOrUpdateFuture{}, nil
 				},
@@ -122,7 +139,7 @@ func TestCreate(t *testing.T) {
 		},
 		{
 			name: "FailedCreate",
-			e: &external{client: &fake.MockSubnetsClient{
+			e: &external{kube: &test.MockClient{MockList: test.NewMockListFn(nil)}, client: &fake.MockSubnetsClient{
 				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ network.Subnet) (network.SubnetsCreateOrUpdateFuture, error) {
 					return network.SubnetsCreateOrUpdateFuture{}, errorBoom
 				},
@@ -133,6 +150,34 @@ func TestCreate(t *testing.T) {
 			),
 			wantErr: errors.Wrap(errorBoom, errCreateSubnet),
 		},
+		{
+			name: "FailedListVirtualNetworks",
+			e:    &external{kube: &test.MockClient{MockList: test.NewMockListFn(errorBoom)}, client: &fake.MockSubnetsClient{}},
+			r:    subnet(),
+			want: subnet(
+				withConditions(runtimev1alpha1.Creating()),
+			),
+			wantErr: errors.Wrap(errors.Wrap(errorBoom, errListVirtualNetworks), errCreateSubnet),
+		},
+		{
+			name: "InlineSubnetConflict",
+			e: &external{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(o runtime.Object) error {
+					v := v1alpha3.VirtualNetwork{ObjectMeta: metav1.ObjectMeta{Name: "coolVnet"}}
+					meta.SetExternalName(&v, virtualNetworkName)
+					v.Spec.ResourceGroupName = resourceGroupName
+					v.Spec.VirtualNetworkPropertiesFormat.Subnets = []v1alpha3.InlineSubnet{{Name: name}}
+					o.(*v1alpha3.VirtualNetworkList).Items = []v1alpha3.VirtualNetwork{v}
+					return nil
+				})},
+				client: &fake.MockSubnetsClient{},
+			},
+			r: subnet(),
+			want: subnet(
+				withConditions(runtimev1alpha1.Creating()),
+			),
+			wantErr: errors.Wrap(errors.Errorf(errFmtInlineConflict, "coolVnet"), errCreateSubnet),
+		},
 	}
 
 	for _, tc := range cases {
@@ -182,7 +227,7 @@ func TestObserve(t *testing.T) {
 					return network.Subnet{
 						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
 							AddressPrefix:     azure.ToStringPtr(addressPrefix),
-							ProvisioningState: azure.ToStringPtr(string(network.Available)),
+							ProvisioningState: azure.ToStringPtr(string(network.Succeeded)),
 						},
 					}, nil
 				},
@@ -190,7 +235,46 @@ func TestObserve(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(runtimev1alpha1.Available()),
-				withState(string(network.Available)),
+				withState(string(network.Succeeded)),
+				withAddressPrefix(addressPrefix, 65531),
+			),
+		},
+		{
+			name: "ObserveUpdating",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix:     azure.ToStringPtr(addressPrefix),
+							ProvisioningState: azure.ToStringPtr(string(network.Updating)),
+						},
+					}, nil
+				},
+			}},
+			r: subnet(),
+			want: subnet(
+				withConditions(azurenetwork.Condition(string(network.Updating))),
+				withState(string(network.Updating)),
+				withAddressPrefix(addressPrefix, 65531),
+			),
+		},
+		{
+			name: "ObserveFailed",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix:     azure.ToStringPtr(addressPrefix),
+							ProvisioningState: azure.ToStringPtr(string(network.Failed)),
+						},
+					}, nil
+				},
+			}},
+			r: subnet(),
+			want: subnet(
+				withConditions(azurenetwork.Condition(string(network.Failed))),
+				withState(string(network.Failed)),
+				withAddressPrefix(addressPrefix, 65531),
 			),
 		},
 		{
@@ -222,6 +306,11 @@ func TestObserve(t *testing.T) {
 }
 
 func TestUpdate(t *testing.T) {
+	errInUse := autorest.DetailedError{
+		StatusCode: http.StatusBadRequest,
+		Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "InUseSubnetCannotBeUpdated"}},
+	}
+
 	cases := []testCase{
 		{
 			name:    "NotSubnet",
@@ -244,6 +333,43 @@ func TestUpdate(t *testing.T) {
 			r:    subnet(),
 			want: subnet(),
 		},
+		{
+			name: "DriftCorrectedRetained",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix: azure.ToStringPtr(addressPrefix),
+						},
+					}, nil
+				},
+			}},
+			r:    subnet(withConditions(azure.DriftCorrected([]string{"addressPrefix"}))),
+			want: subnet(withConditions(azure.DriftCorrected([]string{"addressPrefix"}))),
+		},
+		{
+			name: "SpecChanged",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix: azure.ToStringPtr("10.1.0.0/16"),
+						},
+					}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ network.Subnet) (network.SubnetsCreateOrUpdateFuture, error) {
+					return network.SubnetsCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    subnet(withGeneration(2, 1), withConditions(azure.DriftCorrected([]string{"serviceEndpoints"}))),
+			want: subnet(withGeneration(2, 2)),
+		},
+		{
+			name: "OperationInProgress",
+			e:    &external{client: &fake.MockSubnetsClient{}},
+			r:    subnet(withState(string(network.Updating))),
+			want: subnet(withState(string(network.Updating))),
+		},
 		{
 			name: "SuccessfulNeedsUpdate",
 			e: &external{client: &fake.MockSubnetsClient{
@@ -259,7 +385,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:    subnet(),
-			want: subnet(),
+			want: subnet(withConditions(azure.DriftCorrected([]string{"addressPrefix"}))),
 		},
 		{
 			name: "UnsuccessfulGet",
@@ -294,6 +420,25 @@ func TestUpdate(t *testing.T) {
 			want:    subnet(),
 			wantErr: errors.Wrap(errorBoom, errUpdateSubnet),
 		},
+		{
+			name: "AddressPrefixInUse",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix:    azure.ToStringPtr("10.1.0.0/16"),
+							IPConfigurations: &[]network.IPConfiguration{{}, {}},
+						},
+					}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ string, _ network.Subnet) (network.SubnetsCreateOrUpdateFuture, error) {
+					return network.SubnetsCreateOrUpdateFuture{}, errInUse
+				},
+			}},
+			r:       subnet(),
+			want:    subnet(),
+			wantErr: errors.Wrapf(errInUse, errFmtPrefixInUse, "10.1.0.0/16", addressPrefix, 2),
+		},
 	}
 
 	for _, tc := range cases {
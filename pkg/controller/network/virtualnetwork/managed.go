@@ -21,9 +21,12 @@ import (
 
 	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +38,8 @@ import (
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azureclients "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/network"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings.
@@ -44,26 +49,46 @@ const (
 	errUpdateVirtualNetwork = "cannot update VirtualNetwork"
 	errGetVirtualNetwork    = "cannot get VirtualNetwork"
 	errDeleteVirtualNetwork = "cannot delete VirtualNetwork"
+	errListSubnets          = "cannot list Subnets"
+	errFmtSubnetConflict    = "inline subnet %q is also managed by Subnet %q"
+
+	errFmtEncryptionNotSupported = "virtual network encryption is not supported in location %q"
 )
 
-// Setup adds a controller that reconciles VirtualNetworks.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles VirtualNetworks, ignoring the
+// supplied tags when determining whether they are up to date.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azureclients.IgnoredTags) error {
 	name := managed.ControllerName(v1alpha3.VirtualNetworkGroupKind)
+	throttled := azureclients.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.VirtualNetwork{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.VirtualNetworkList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.VirtualNetworkGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.VirtualNetworkGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{client: mgr.GetClient(), ignored: ignored, observeOnly: observeOnly}, throttled, observeOnly, chain.WithLocks(virtualNetworkID))),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+// virtualNetworkID returns the Azure resource ID of the supplied
+// VirtualNetwork, if known.
+func virtualNetworkID(mg resource.Managed) string {
+	cr, ok := mg.(*v1alpha3.VirtualNetwork)
+	if !ok {
+		return ""
+	}
+	return cr.Status.ID
 }
 
 type connecter struct {
-	client client.Client
+	client      client.Client
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -73,11 +98,77 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 	cl := azurenetwork.NewVirtualNetworksClient(creds[azureclients.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{client: cl}, nil
+
+	// Our Azure SDK predates some VirtualNetwork properties, so we send and
+	// receive them using a newer API version when they're requested.
+	observed := &network.VirtualNetworkExtensions{}
+	requested := &network.VirtualNetworkExtensions{}
+	if v, ok := mg.(*v1alpha3.VirtualNetwork); ok {
+		if ext := network.NewVirtualNetworkExtensions(v); !ext.IsZero() {
+			*requested = ext
+			cl.RequestInspector = func(p autorest.Preparer) autorest.Preparer {
+				return autorest.DecoratePreparer(p,
+					azureclients.WithAPIVersion(network.VirtualNetworkExtensionsAPIVersion),
+					azureclients.WithProperties(requested.Properties()))
+			}
+			cl.ResponseInspector = azureclients.ByDecodingProperties(observed)
+		}
+	}
+	return &external{kube: c.client, client: cl, observed: observed, requested: requested, ignored: c.ignored, observeOnly: c.observeOnly}, nil
 }
 
 type external struct {
-	client networkapi.VirtualNetworksClientAPI
+	kube        client.Client
+	client      networkapi.VirtualNetworksClientAPI
+	ignored     azureclients.IgnoredTags
+	observeOnly bool
+
+	// observed is populated with the VirtualNetworkExtensions of the
+	// VirtualNetwork returned by each call to client.Get.
+	observed *network.VirtualNetworkExtensions
+
+	// requested are the VirtualNetworkExtensions sent by each call to
+	// client.CreateOrUpdate.
+	requested *network.VirtualNetworkExtensions
+}
+
+// get returns the supplied VirtualNetwork's Azure representation, and its
+// VirtualNetworkExtensions.
+func (e *external) get(ctx context.Context, v *v1alpha3.VirtualNetwork) (azurenetwork.VirtualNetwork, network.VirtualNetworkExtensions, error) {
+	if e.observed == nil {
+		e.observed = &network.VirtualNetworkExtensions{}
+	}
+	*e.observed = network.VirtualNetworkExtensions{}
+	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), "")
+	return az, *e.observed, err
+}
+
+// createOrUpdate creates or updates the supplied VirtualNetwork, explaining
+// any failure caused by requesting encryption where it isn't supported.
+func (e *external) createOrUpdate(ctx context.Context, v *v1alpha3.VirtualNetwork, vnet azurenetwork.VirtualNetwork) error {
+	_, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet)
+	if v.Spec.VirtualNetworkPropertiesFormat.Encryption != nil && network.IsEncryptionNotSupported(err) {
+		return errors.Wrapf(err, errFmtEncryptionNotSupported, v.Spec.Location)
+	}
+	return err
+}
+
+// checkInlineSubnets returns an error if any of the subnets declared inline by
+// the supplied VirtualNetwork is also managed by a Subnet resource.
+func (e *external) checkInlineSubnets(ctx context.Context, v *v1alpha3.VirtualNetwork) error {
+	if len(v.Spec.VirtualNetworkPropertiesFormat.Subnets) == 0 {
+		return nil
+	}
+	l := &v1alpha3.SubnetList{}
+	if err := e.kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListSubnets)
+	}
+	for i := range l.Items {
+		if network.InlineSubnetConflicts(v, &l.Items[i]) {
+			return errors.Errorf(errFmtSubnetConflict, meta.GetExternalName(&l.Items[i]), l.Items[i].GetName())
+		}
+	}
+	return nil
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -93,10 +184,16 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetVirtualNetwork)
 	}
+	if err := azureclients.ValidateOwnership(v, v.Status.ID, azureclients.ToString(az.ID), az.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(v) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetVirtualNetwork)
+	}
 
 	network.UpdateVirtualNetworkStatusFromAzure(v, az)
 
-	v.SetConditions(runtimev1alpha1.Available())
+	v.SetConditions(network.Condition(v.Status.State))
 
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
@@ -114,10 +211,27 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.Status.SetConditions(runtimev1alpha1.Creating())
 
-	vnet := network.NewVirtualNetworkParameters(v)
-	if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet); err != nil {
+	if err := azureclients.ValidateName(v1alpha3.VirtualNetworkKind, meta.GetExternalName(v)); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
 	}
+	if err := network.ValidateVirtualNetworkEncryption(v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
+	}
+	if err := network.ValidateVirtualNetworkBGPCommunities(v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
+	}
+	if err := network.ValidateInlineSubnets(v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
+	}
+	if err := e.checkInlineSubnets(ctx, v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
+	}
+
+	vnet := network.NewVirtualNetworkParameters(v)
+	vnet.Tags = azureclients.WithOwnershipTags(vnet.Tags, v1alpha3.VirtualNetworkKind, v)
+	if err := e.createOrUpdate(ctx, v, vnet); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(azureclients.WaitForResourceGroup(v, v.Spec.ResourceGroupName, err), errCreateVirtualNetwork)
+	}
 
 	return managed.ExternalCreation{}, nil
 }
@@ -128,18 +242,55 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotVirtualNetwork)
 	}
 
-	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), "")
+	// Azure rejects updates while another operation is in progress. We'll be
+	// requeued and try again once it has completed.
+	if network.IsOperationInProgress(v.Status.State) {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	az, ext, err := e.get(ctx, v)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetVirtualNetwork)
 	}
 
-	if network.VirtualNetworkNeedsUpdate(v, az) {
+	// Resubmitting a virtual network whose last operation failed retries it.
+	failed := network.Failed(v.Status.State)
+	drift := network.VirtualNetworkDrift(v, az, ext, e.ignored)
+
+	// Differences caused by a change to our spec are not drift, and make any
+	// drift corrected before the change irrelevant.
+	specChanged := v.GetGeneration() != v.Status.ObservedGeneration
+	if specChanged {
+		azureclients.ClearDriftCorrected(&v.Status.ConditionedStatus)
+	}
+	if len(drift) > 0 || failed != nil {
+		if err := network.ValidateVirtualNetworkEncryption(v); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
+		}
+		if err := network.ValidateVirtualNetworkBGPCommunities(v); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
+		}
+		if err := network.ValidateInlineSubnets(v); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
+		}
+		if err := e.checkInlineSubnets(ctx, v); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
+		}
 		vnet := network.NewVirtualNetworkParameters(v)
-		if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet); err != nil {
+		network.PreserveSubnets(&vnet, az)
+		vnet.Tags = e.ignored.Preserve(vnet.Tags, az.Tags)
+		if e.requested != nil {
+			*e.requested = network.NewVirtualNetworkExtensions(v).WithObserved(ext)
+		}
+		if err := e.createOrUpdate(ctx, v, vnet); err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
 		}
+		if len(drift) > 0 && !specChanged {
+			v.SetConditions(azureclients.DriftCorrected(drift))
+		}
 	}
-	return managed.ExternalUpdate{}, nil
+	v.Status.ObservedGeneration = v.GetGeneration()
+	return managed.ExternalUpdate{}, errors.Wrap(failed, errUpdateVirtualNetwork)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -23,9 +23,11 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
@@ -36,6 +38,7 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/network/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
+	azurenetwork "github.com/crossplane/provider-azure/pkg/clients/network"
 	"github.com/crossplane/provider-azure/pkg/clients/network/fake"
 )
 
@@ -45,12 +48,18 @@ const (
 	addressPrefix     = "10.0.0.0/16"
 	resourceGroupName = "coolRG"
 	location          = "coolplace"
+	subnetName        = "coolInlineSubnet"
 )
 
 var (
 	ctx       = context.Background()
 	errorBoom = errors.New("boom")
-	tags      = map[string]string{"one": "test", "two": "test"}
+
+	errEncryptionNotSupported = autorest.DetailedError{
+		StatusCode: http.StatusBadRequest,
+		Original:   &azureautorest.RequestError{ServiceError: &azureautorest.ServiceError{Code: "VirtualNetworkEncryptionNotSupported"}},
+	}
+	tags = map[string]string{"one": "test", "two": "test"}
 )
 
 type testCase struct {
@@ -67,10 +76,50 @@ func withConditions(c ...runtimev1alpha1.Condition) virtualNetworkModifier {
 	return func(r *v1alpha3.VirtualNetwork) { r.Status.ConditionedStatus.Conditions = c }
 }
 
+func withInlineSubnet(n string) virtualNetworkModifier {
+	return func(r *v1alpha3.VirtualNetwork) {
+		r.Spec.VirtualNetworkPropertiesFormat.Subnets = append(r.Spec.VirtualNetworkPropertiesFormat.Subnets, v1alpha3.InlineSubnet{
+			Name:                   n,
+			SubnetPropertiesFormat: v1alpha3.SubnetPropertiesFormat{AddressPrefix: addressPrefix},
+		})
+	}
+}
+
+func withGeneration(current, observed int64) virtualNetworkModifier {
+	return func(r *v1alpha3.VirtualNetwork) {
+		r.SetGeneration(current)
+		r.Status.ObservedGeneration = observed
+	}
+}
+
 func withState(s string) virtualNetworkModifier {
 	return func(r *v1alpha3.VirtualNetwork) { r.Status.State = s }
 }
 
+func withEncryption(enabled bool, enforcement *string) virtualNetworkModifier {
+	return func(r *v1alpha3.VirtualNetwork) {
+		r.Spec.VirtualNetworkPropertiesFormat.Encryption = &v1alpha3.VirtualNetworkEncryption{Enabled: enabled, Enforcement: enforcement}
+	}
+}
+
+func withBGPCommunity(c string) virtualNetworkModifier {
+	return func(r *v1alpha3.VirtualNetwork) {
+		r.Spec.VirtualNetworkPropertiesFormat.BGPCommunities = &v1alpha3.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: c}
+	}
+}
+
+// withObserved returns an external whose client's Get populates its observed
+// VirtualNetworkExtensions, as the client's response inspector would.
+func withObserved(c *fake.MockVirtualNetworksClient, ext azurenetwork.VirtualNetworkExtensions) *external {
+	e := &external{client: c, observed: &azurenetwork.VirtualNetworkExtensions{}}
+	get := c.MockGet
+	c.MockGet = func(ctx context.Context, rg string, n string, expand string) (network.VirtualNetwork, error) {
+		*e.observed = ext
+		return get(ctx, rg, n, expand)
+	}
+	return e
+}
+
 func virtualNetwork(vm ...virtualNetworkModifier) *v1alpha3.VirtualNetwork {
 	r := &v1alpha3.VirtualNetwork{
 		ObjectMeta: metav1.ObjectMeta{
@@ -139,6 +188,58 @@ func TestCreate(t *testing.T) {
 			),
 			wantErr: errors.Wrap(errorBoom, errCreateVirtualNetwork),
 		},
+		{
+			name: "ResourceGroupNotFound",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.VirtualNetwork) (result network.VirtualNetworksCreateOrUpdateFuture, err error) {
+					return network.VirtualNetworksCreateOrUpdateFuture{}, &azureautorest.ServiceError{Code: "ResourceGroupNotFound"}
+				},
+			}},
+			r: virtualNetwork(),
+			want: virtualNetwork(
+				withConditions(azure.WaitingForResourceGroup(resourceGroupName)),
+			),
+			wantErr: errors.Wrap(azure.WaitForResourceGroup(virtualNetwork(), resourceGroupName, &azureautorest.ServiceError{Code: "ResourceGroupNotFound"}), errCreateVirtualNetwork),
+		},
+		{
+			name: "InlineSubnetConflict",
+			e: &external{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(o runtime.Object) error {
+					s := v1alpha3.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "coolSubnetCR"}}
+					meta.SetExternalName(&s, subnetName)
+					s.Spec.ResourceGroupName = resourceGroupName
+					s.Spec.VirtualNetworkName = name
+					o.(*v1alpha3.SubnetList).Items = []v1alpha3.Subnet{s}
+					return nil
+				})},
+				client: &fake.MockVirtualNetworksClient{},
+			},
+			r: virtualNetwork(withInlineSubnet(subnetName)),
+			want: virtualNetwork(
+				withInlineSubnet(subnetName),
+				withConditions(runtimev1alpha1.Creating()),
+			),
+			wantErr: errors.Wrap(errors.Errorf(errFmtSubnetConflict, subnetName, "coolSubnetCR"), errCreateVirtualNetwork),
+		},
+		{
+			name: "SuccessfulCreateInlineSubnet",
+			e: &external{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil)},
+				client: &fake.MockVirtualNetworksClient{
+					MockCreateOrUpdate: func(_ context.Context, _ string, _ string, v network.VirtualNetwork) (result network.VirtualNetworksCreateOrUpdateFuture, err error) {
+						if v.Subnets == nil || len(*v.Subnets) != 1 || azure.ToString((*v.Subnets)[0].Name) != subnetName {
+							return network.VirtualNetworksCreateOrUpdateFuture{}, errorBoom
+						}
+						return network.VirtualNetworksCreateOrUpdateFuture{}, nil
+					},
+				},
+			},
+			r: virtualNetwork(withInlineSubnet(subnetName)),
+			want: virtualNetwork(
+				withInlineSubnet(subnetName),
+				withConditions(runtimev1alpha1.Creating()),
+			),
+		},
 	}
 
 	for _, tc := range cases {
@@ -170,17 +271,17 @@ func TestObserve(t *testing.T) {
 			e: &external{client: &fake.MockVirtualNetworksClient{
 				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
 					return network.VirtualNetwork{
-							Tags: azure.ToStringPtrMap(tags),
-							VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
-								AddressSpace: &network.AddressSpace{
-									AddressPrefixes: &[]string{addressPrefix},
-								},
-								EnableDdosProtection: azure.ToBoolPtr(true),
-								EnableVMProtection:   azure.ToBoolPtr(true),
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
 							},
-						}, autorest.DetailedError{
-							StatusCode: http.StatusNotFound,
-						}
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, autorest.DetailedError{
+						StatusCode: http.StatusNotFound,
+					}
 				},
 			}},
 			r:    virtualNetwork(),
@@ -191,14 +292,14 @@ func TestObserve(t *testing.T) {
 			e: &external{client: &fake.MockVirtualNetworksClient{
 				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
 					return network.VirtualNetwork{
-						Tags: azure.ToStringPtrMap(tags),
+						Tags: azure.WithOwnershipTags(azure.ToStringPtrMap(tags), v1alpha3.VirtualNetworkKind, virtualNetwork()),
 						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
 							AddressSpace: &network.AddressSpace{
 								AddressPrefixes: &[]string{addressPrefix},
 							},
 							EnableDdosProtection: azure.ToBoolPtr(true),
 							EnableVMProtection:   azure.ToBoolPtr(true),
-							ProvisioningState:    azure.ToStringPtr(string(network.Available)),
+							ProvisioningState:    azure.ToStringPtr(string(network.Succeeded)),
 						},
 					}, nil
 				},
@@ -206,9 +307,38 @@ func TestObserve(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(runtimev1alpha1.Available()),
-				withState(string(network.Available)),
+				withState(string(network.Succeeded)),
+			),
+		},
+		{
+			name: "ObserveFailed",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.WithOwnershipTags(azure.ToStringPtrMap(tags), v1alpha3.VirtualNetworkKind, virtualNetwork()),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							ProvisioningState: azure.ToStringPtr(string(network.Failed)),
+						},
+					}, nil
+				},
+			}},
+			r: virtualNetwork(),
+			want: virtualNetwork(
+				withConditions(azurenetwork.Condition(string(network.Failed))),
+				withState(string(network.Failed)),
 			),
 		},
+		{
+			name: "NotOwned",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{Tags: azure.ToStringPtrMap(tags)}, nil
+				},
+			}},
+			r:       virtualNetwork(),
+			want:    virtualNetwork(),
+			wantErr: errors.Wrap(azure.ValidateOwnership(virtualNetwork(), "", "", nil), errGetVirtualNetwork),
+		},
 		{
 			name: "FailedObserve",
 			e: &external{client: &fake.MockVirtualNetworksClient{
@@ -265,6 +395,53 @@ func TestUpdate(t *testing.T) {
 			r:    virtualNetwork(),
 			want: virtualNetwork(),
 		},
+		{
+			name: "DriftCorrectedRetained",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+			}},
+			r:    virtualNetwork(withConditions(azure.DriftCorrected([]string{"addressSpace"}))),
+			want: virtualNetwork(withConditions(azure.DriftCorrected([]string{"addressSpace"}))),
+		},
+		{
+			name: "SpecChanged",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{"10.1.0.0/16"},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.VirtualNetwork) (result network.VirtualNetworksCreateOrUpdateFuture, err error) {
+					return network.VirtualNetworksCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    virtualNetwork(withGeneration(2, 1), withConditions(azure.DriftCorrected([]string{"tags"}))),
+			want: virtualNetwork(withGeneration(2, 2)),
+		},
+		{
+			name: "OperationInProgress",
+			e:    &external{client: &fake.MockVirtualNetworksClient{}},
+			r:    virtualNetwork(withState(string(network.Updating))),
+			want: virtualNetwork(withState(string(network.Updating))),
+		},
 		{
 			name: "SuccessfulNeedsUpdate",
 			e: &external{client: &fake.MockVirtualNetworksClient{
@@ -285,7 +462,33 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:    virtualNetwork(),
-			want: virtualNetwork(),
+			want: virtualNetwork(withConditions(azure.DriftCorrected([]string{"addressSpace"}))),
+		},
+		{
+			name: "SuccessfulPreservesSubnets",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{"10.1.0.0/16"},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+							Subnets:              &[]network.Subnet{{Name: azure.ToStringPtr("standalone")}},
+						},
+					}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, v network.VirtualNetwork) (result network.VirtualNetworksCreateOrUpdateFuture, err error) {
+					if v.Subnets == nil || len(*v.Subnets) != 1 {
+						return network.VirtualNetworksCreateOrUpdateFuture{}, errorBoom
+					}
+					return network.VirtualNetworksCreateOrUpdateFuture{}, nil
+				},
+			}},
+			r:    virtualNetwork(),
+			want: virtualNetwork(withConditions(azure.DriftCorrected([]string{"addressSpace"}))),
 		},
 		{
 			name: "UnsuccessfulGet",
@@ -329,6 +532,110 @@ func TestUpdate(t *testing.T) {
 			r:       virtualNetwork(),
 			want:    virtualNetwork(),
 			wantErr: errors.Wrap(errorBoom, errUpdateVirtualNetwork),
+		}, {
+			name: "SuccessfulEncryptionUpToDate",
+			e: withObserved(&fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+			}, azurenetwork.VirtualNetworkExtensions{
+				Encryption: &azurenetwork.VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("AllowUnencrypted")},
+			}),
+			r:    virtualNetwork(withEncryption(true, azure.ToStringPtr("AllowUnencrypted"))),
+			want: virtualNetwork(withEncryption(true, azure.ToStringPtr("AllowUnencrypted"))),
+		},
+		{
+			name: "InvalidEncryption",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+			}},
+			r:       virtualNetwork(withEncryption(false, azure.ToStringPtr("DropUnencrypted"))),
+			want:    virtualNetwork(withEncryption(false, azure.ToStringPtr("DropUnencrypted"))),
+			wantErr: errors.Wrap(errors.New("encryption enforcement may only be set when encryption is enabled"), errUpdateVirtualNetwork),
+		},
+		{
+			name: "SuccessfulBGPCommunityUpToDate",
+			e: withObserved(&fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+			}, azurenetwork.VirtualNetworkExtensions{
+				BGPCommunities: &azurenetwork.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: azure.ToStringPtr("12076:20000"), RegionalCommunity: azure.ToStringPtr("12076:50004")},
+			}),
+			r:    virtualNetwork(withBGPCommunity("12076:20000")),
+			want: virtualNetwork(withBGPCommunity("12076:20000")),
+		},
+		{
+			name: "InvalidBGPCommunity",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+			}},
+			r:       virtualNetwork(withBGPCommunity("12076:50004")),
+			want:    virtualNetwork(withBGPCommunity("12076:50004")),
+			wantErr: errors.Wrap(errors.New(`invalid BGP community "12076:50004": must be of the form 12076:<value>, where value is between 20000 and 49999`), errUpdateVirtualNetwork),
+		},
+		{
+			name: "EncryptionNotSupported",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{
+						Tags: azure.ToStringPtrMap(tags),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: &[]string{addressPrefix},
+							},
+							EnableDdosProtection: azure.ToBoolPtr(true),
+							EnableVMProtection:   azure.ToBoolPtr(true),
+						},
+					}, nil
+				},
+				MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ network.VirtualNetwork) (result network.VirtualNetworksCreateOrUpdateFuture, err error) {
+					return network.VirtualNetworksCreateOrUpdateFuture{}, errEncryptionNotSupported
+				},
+			}},
+			r:       virtualNetwork(withEncryption(true, nil)),
+			want:    virtualNetwork(withEncryption(true, nil)),
+			wantErr: errors.Wrap(errors.Wrapf(errEncryptionNotSupported, errFmtEncryptionNotSupported, location), errUpdateVirtualNetwork),
 		},
 	}
 
@@ -347,6 +654,53 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdatePreservesObservedExtensions(t *testing.T) {
+	// Only the flow timeout is declared, but the VirtualNetwork is replaced
+	// using the API version that supports encryption and BGP communities.
+	observed := azurenetwork.VirtualNetworkExtensions{
+		FlowTimeoutInMinutes: azure.ToInt32Ptr(4),
+		Encryption:           &azurenetwork.VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("AllowUnencrypted")},
+		BGPCommunities:       &azurenetwork.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: azure.ToStringPtr("12076:20000"), RegionalCommunity: azure.ToStringPtr("12076:50004")},
+	}
+	want := azurenetwork.VirtualNetworkExtensions{
+		FlowTimeoutInMinutes: azure.ToInt32Ptr(10),
+		Encryption:           &azurenetwork.VirtualNetworkEncryption{Enabled: azure.ToBoolPtr(true), Enforcement: azure.ToStringPtr("AllowUnencrypted")},
+		BGPCommunities:       &azurenetwork.VirtualNetworkBGPCommunities{VirtualNetworkCommunity: azure.ToStringPtr("12076:20000")},
+	}
+
+	var got *azurenetwork.VirtualNetworkExtensions
+	c := &fake.MockVirtualNetworksClient{
+		MockGet: func(_ context.Context, _ string, _ string, _ string) (network.VirtualNetwork, error) {
+			return network.VirtualNetwork{
+				Tags: azure.ToStringPtrMap(tags),
+				VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+					AddressSpace:         &network.AddressSpace{AddressPrefixes: &[]string{addressPrefix}},
+					EnableDdosProtection: azure.ToBoolPtr(true),
+					EnableVMProtection:   azure.ToBoolPtr(true),
+				},
+			}, nil
+		},
+	}
+	e := withObserved(c, observed)
+	e.requested = &azurenetwork.VirtualNetworkExtensions{}
+	c.MockCreateOrUpdate = func(_ context.Context, _ string, _ string, _ network.VirtualNetwork) (network.VirtualNetworksCreateOrUpdateFuture, error) {
+		sent := *e.requested
+		got = &sent
+		return network.VirtualNetworksCreateOrUpdateFuture{}, nil
+	}
+
+	timeout := 10
+	v := virtualNetwork(func(r *v1alpha3.VirtualNetwork) {
+		r.Spec.VirtualNetworkPropertiesFormat.FlowTimeoutInMinutes = &timeout
+	})
+	if _, err := e.Update(ctx, v); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+	if diff := cmp.Diff(&want, got); diff != "" {
+		t.Errorf("requested extensions: -want, +got:\n%s", diff)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	cases := []testCase{
 		{
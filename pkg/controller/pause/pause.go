@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pause provides a reconciler that skips reconciliation of managed
+// resources that have been paused by annotation.
+package pause
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// AnnotationKeyPaused is the annotation that pauses reconciliation of a
+// managed resource when set to "true".
+const AnnotationKeyPaused = "crossplane.io/paused"
+
+// TypePaused resources are not being reconciled.
+const TypePaused runtimev1alpha1.ConditionType = "Paused"
+
+// Reasons a resource is or is not paused.
+const (
+	ReasonPaused  runtimev1alpha1.ConditionReason = "ReconcilePaused"
+	ReasonResumed runtimev1alpha1.ConditionReason = "ReconcileResumed"
+)
+
+const (
+	timeout = 1 * time.Minute
+
+	errGetManaged          = "cannot get managed resource"
+	errUpdateManagedStatus = "cannot update managed resource status"
+)
+
+// Paused returns a condition that indicates reconciliation of the managed
+// resource is paused.
+func Paused() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPaused,
+	}
+}
+
+// Resumed returns a condition that indicates reconciliation of the managed
+// resource has resumed after being paused.
+func Resumed() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResumed,
+	}
+}
+
+// IsPaused returns true if the supplied object is annotated as paused.
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyPaused] == "true"
+}
+
+// A Reconciler wraps another reconciler, skipping reconciliation of managed
+// resources that are annotated as paused. Removing the annotation triggers a
+// watch event, which resumes reconciliation.
+type Reconciler struct {
+	client     client.Client
+	reader     client.Reader
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+}
+
+// NewReconciler returns a Reconciler that pauses reconciliation of the
+// supplied kind of managed resource, and otherwise delegates to the supplied
+// reconciler.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	return &Reconciler{client: m.GetClient(), reader: m.GetAPIReader(), newManaged: nm, wrapped: r}
+}
+
+// Reconcile a managed resource unless it is paused.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.client.Get(ctx, req.NamespacedName, mg); err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.wrapped.Reconcile(req)
+		}
+		return reconcile.Result{}, errors.Wrap(err, errGetManaged)
+	}
+
+	paused := mg.GetCondition(TypePaused).Status == corev1.ConditionTrue
+	switch {
+	case IsPaused(mg) && paused:
+		return reconcile.Result{}, nil
+	case IsPaused(mg):
+		return reconcile.Result{}, errors.Wrap(azure.UpdateConditions(ctx, r.client, r.reader, mg, Paused()), errUpdateManagedStatus)
+	case paused:
+		if err := azure.UpdateConditions(ctx, r.client, r.reader, mg, Resumed()); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errUpdateManagedStatus)
+		}
+	}
+
+	return r.wrapped.Reconcile(req)
+}
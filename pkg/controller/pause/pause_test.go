@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+var wrappedResult = reconcile.Result{Requeue: true}
+
+func wrapped(called *bool) reconcile.Reconciler {
+	return reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+		*called = true
+		return wrappedResult, nil
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	type want struct {
+		result     reconcile.Result
+		err        error
+		delegated  bool
+		conditions []runtimev1alpha1.Condition
+	}
+
+	withAnnotation := func(o runtime.Object) error {
+		o.(*fake.Managed).SetAnnotations(map[string]string{AnnotationKeyPaused: "true"})
+		return nil
+	}
+	withPausedCondition := func(o runtime.Object) error {
+		o.(*fake.Managed).SetConditions(Paused())
+		return nil
+	}
+
+	cases := map[string]struct {
+		client client.Client
+		want   want
+	}{
+		"NotFound": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			want: want{result: wrappedResult, delegated: true},
+		},
+		"GetError": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			want: want{err: errors.Wrap(errBoom, errGetManaged)},
+		},
+		"NotPaused": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			want: want{result: wrappedResult, delegated: true},
+		},
+		"Pause": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withAnnotation),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{conditions: []runtimev1alpha1.Condition{Paused()}},
+		},
+		"PauseStatusUpdateError": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withAnnotation),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: want{
+				err:        errors.Wrap(errBoom, errUpdateManagedStatus),
+				conditions: []runtimev1alpha1.Condition{Paused()},
+			},
+		},
+		"AlreadyPaused": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, withAnnotation, withPausedCondition),
+			},
+			want: want{conditions: []runtimev1alpha1.Condition{Paused()}},
+		},
+		"Resume": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withPausedCondition),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{
+				result:     wrappedResult,
+				delegated:  true,
+				conditions: []runtimev1alpha1.Condition{Resumed()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			delegated := false
+			r := &Reconciler{
+				client:     tc.client,
+				reader:     tc.client,
+				newManaged: func() resource.Managed { return mg },
+				wrapped:    wrapped(&delegated),
+			}
+
+			got, err := r.Reconcile(reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.delegated, delegated); diff != "" {
+				t.Errorf("r.Reconcile(...): -want delegated, +got delegated:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, mg.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want conditions, +got conditions:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"NoAnnotation": {want: false},
+		"Paused":       {annotations: map[string]string{AnnotationKeyPaused: "true"}, want: true},
+		"NotTrue":      {annotations: map[string]string{AnnotationKeyPaused: "false"}, want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			if diff := cmp.Diff(tc.want, IsPaused(mg)); diff != "" {
+				t.Errorf("IsPaused(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
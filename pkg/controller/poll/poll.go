@@ -0,0 +1,229 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poll provides a reconciler that polls managed resources with
+// exponential backoff while they have a long-running Azure operation in
+// progress.
+package poll
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Default polling intervals.
+const (
+	DefaultInterval    = 1 * time.Minute
+	DefaultMaxInterval = 10 * time.Minute
+)
+
+// minInterval is the interval at which the managed reconciler requeues a
+// resource after a successful reconcile. There's no point polling sooner.
+const minInterval = 1 * time.Minute
+
+const timeout = 1 * time.Minute
+
+// An InProgressFn returns true if the supplied managed resource has a
+// long-running operation in progress.
+type InProgressFn func(mg resource.Managed) bool
+
+// A poll of a managed resource.
+type poll struct {
+	// count is the number of consecutive reconciles for which the resource
+	// has had an operation in progress.
+	count int
+
+	// due is the time at which the resource should next be reconciled.
+	due time.Time
+
+	// generation is the generation of the resource when the poll was
+	// scheduled.
+	generation int64
+}
+
+// A Reconciler wraps another reconciler. While a managed resource has a
+// long-running operation in progress it is requeued after an interval that
+// starts at the reconciler's interval and doubles each reconcile, up to its max
+// interval. Reconciles that are triggered before the interval has elapsed, for
+// example by a watch event caused by a status update, are not delegated to the
+// wrapped reconciler unless the resource's spec has changed or it has been
+// deleted.
+type Reconciler struct {
+	client     client.Reader
+	reader     client.Reader
+	newManaged func() resource.Managed
+	inProgress InProgressFn
+	wrapped    reconcile.Reconciler
+	interval   time.Duration
+	max        time.Duration
+
+	mu    sync.Mutex
+	polls map[types.NamespacedName]poll
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithIntervals specifies the requeue interval of the first reconcile after a
+// long-running operation is found to be in progress, and the longest interval
+// it is doubled to while the operation remains in progress. Polling is
+// disabled if either is not positive. Reconcilers use the DefaultInterval and
+// DefaultMaxInterval by default.
+func WithIntervals(interval, max time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.interval, r.max = interval, max
+	}
+}
+
+// NewReconciler returns a Reconciler that polls managed resources of the
+// supplied kind with exponential backoff while the supplied function reports
+// they have an operation in progress, and otherwise delegates to the supplied
+// reconciler. Intervals shorter than that at which the managed reconciler
+// requeues resources are raised to match it.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, fn InProgressFn, r reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	rec := &Reconciler{
+		client:     m.GetClient(),
+		reader:     m.GetAPIReader(),
+		newManaged: nm,
+		inProgress: fn,
+		wrapped:    r,
+		interval:   DefaultInterval,
+		max:        DefaultMaxInterval,
+		polls:      map[types.NamespacedName]poll{},
+	}
+	for _, ro := range o {
+		ro(rec)
+	}
+	if rec.interval > 0 && rec.interval < minInterval {
+		rec.interval = minInterval
+	}
+	if rec.max > 0 && rec.max < rec.interval {
+		rec.max = rec.interval
+	}
+	return rec
+}
+
+// Reconcile a managed resource, polling it with exponential backoff if it has
+// an operation in progress.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if r.interval <= 0 || r.max <= 0 {
+		return r.wrapped.Reconcile(req)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.client.Get(ctx, req.NamespacedName, mg); err == nil {
+		if wait := r.wait(req.NamespacedName, mg); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	result, err := r.wrapped.Reconcile(req)
+	if err != nil {
+		return result, err
+	}
+
+	// The wrapped reconciler asks to be requeued sooner than it usually would
+	// when it fails, or when it expects the resource to change soon. We don't
+	// delay such reconciles.
+	if result.RequeueAfter < minInterval {
+		r.forget(req.NamespacedName)
+		return result, nil
+	}
+
+	// Our cache may not yet reflect the status written by the wrapped
+	// reconciler, so we read the resource from the API server.
+	if gerr := r.reader.Get(ctx, req.NamespacedName, mg); gerr != nil {
+		if kerrors.IsNotFound(gerr) {
+			r.forget(req.NamespacedName)
+		}
+		return result, nil
+	}
+
+	if !r.inProgress(mg) {
+		r.forget(req.NamespacedName)
+		return result, nil
+	}
+
+	return reconcile.Result{RequeueAfter: r.schedule(req.NamespacedName, mg.GetGeneration(), result.RequeueAfter)}, nil
+}
+
+// wait returns how long to wait before polling the supplied resource, if it
+// is being polled. Deleted resources and resources whose spec has changed
+// since the poll was scheduled are not made to wait.
+func (r *Reconciler) wait(nn types.NamespacedName, mg resource.Managed) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.polls[nn]
+	if !ok || meta.WasDeleted(mg) || mg.GetGeneration() != p.generation {
+		return 0
+	}
+	return time.Until(p.due)
+}
+
+// schedule the next poll of the supplied resource, returning the interval
+// after which it is due. The interval is never shorter than the supplied
+// interval requested by the wrapped reconciler.
+func (r *Reconciler) schedule(nn types.NamespacedName, generation int64, requested time.Duration) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.polls[nn]
+	p.count++
+	d := r.backoff(p.count)
+	if requested > d {
+		d = requested
+	}
+	p.due = time.Now().Add(d)
+	p.generation = generation
+	r.polls[nn] = p
+	return d
+}
+
+// forget that the supplied resource is being polled.
+func (r *Reconciler) forget(nn types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.polls, nn)
+}
+
+// backoff returns the requeue interval for the supplied poll number.
+func (r *Reconciler) backoff(poll int) time.Duration {
+	d := r.interval
+	for i := 1; i < poll && d < r.max; i++ {
+		d *= 2
+	}
+	if d > r.max {
+		return r.max
+	}
+	return d
+}
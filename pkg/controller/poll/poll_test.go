@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+var (
+	wrappedResult = reconcile.Result{RequeueAfter: 1 * time.Minute}
+	failedResult  = reconcile.Result{RequeueAfter: 30 * time.Second}
+)
+
+func wrapped(result reconcile.Result, err error) reconcile.Reconciler {
+	return reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+		return result, err
+	})
+}
+
+func inProgress(v bool) InProgressFn {
+	return func(_ resource.Managed) bool { return v }
+}
+
+// get returns a MockClient that gets a managed resource with the supplied
+// generation, deleted if so specified.
+func get(generation int64, deleted bool) client.Client {
+	return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		mg := obj.(*fake.Managed)
+		mg.SetGeneration(generation)
+		if deleted {
+			now := metav1.Now()
+			mg.SetDeletionTimestamp(&now)
+		}
+		return nil
+	})}
+}
+
+func TestReconcile(t *testing.T) {
+	nn := types.NamespacedName{Name: "cool"}
+	pending := poll{count: 2, due: time.Now().Add(1 * time.Hour), generation: 1}
+
+	// Waits are measured from the time of the reconcile, so we only require
+	// them to be approximately equal.
+	approx := cmp.Comparer(func(a, b time.Duration) bool {
+		d := a - b
+		return d > -time.Second && d < time.Second
+	})
+
+	type want struct {
+		result reconcile.Result
+		err    error
+		polls  int
+	}
+
+	cases := map[string]struct {
+		client     client.Client
+		reader     client.Client
+		wrapped    reconcile.Reconciler
+		inProgress InProgressFn
+		poll       *poll
+		want       want
+	}{
+		"WrappedError": {
+			client:  get(1, false),
+			wrapped: wrapped(wrappedResult, errBoom),
+			poll:    &poll{count: 2, generation: 1},
+			want:    want{result: wrappedResult, err: errBoom, polls: 2},
+		},
+		"WrappedFailed": {
+			client:  get(1, false),
+			wrapped: wrapped(failedResult, nil),
+			poll:    &poll{count: 2, generation: 1},
+			want:    want{result: failedResult},
+		},
+		"PollPending": {
+			client:  get(1, false),
+			wrapped: wrapped(wrappedResult, errBoom),
+			poll:    &pending,
+			want:    want{result: reconcile.Result{RequeueAfter: 1 * time.Hour}, polls: 2},
+		},
+		"PollPendingSpecChanged": {
+			client:     get(2, false),
+			reader:     get(2, false),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(true),
+			poll:       &pending,
+			want:       want{result: reconcile.Result{RequeueAfter: 4 * time.Minute}, polls: 3},
+		},
+		"PollPendingDeleted": {
+			client:     get(1, true),
+			reader:     get(1, true),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(false),
+			poll:       &pending,
+			want:       want{result: wrappedResult},
+		},
+		"NotFound": {
+			client: get(1, false),
+			reader: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			wrapped: wrapped(wrappedResult, nil),
+			poll:    &poll{count: 2, generation: 1},
+			want:    want{result: wrappedResult},
+		},
+		"NotInProgress": {
+			client:     get(1, false),
+			reader:     get(1, false),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(false),
+			poll:       &poll{count: 2, generation: 1},
+			want:       want{result: wrappedResult},
+		},
+		"FirstPoll": {
+			client:     get(1, false),
+			reader:     get(1, false),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(true),
+			want:       want{result: reconcile.Result{RequeueAfter: 1 * time.Minute}, polls: 1},
+		},
+		"ThirdPoll": {
+			client:     get(1, false),
+			reader:     get(1, false),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(true),
+			poll:       &poll{count: 2, generation: 1},
+			want:       want{result: reconcile.Result{RequeueAfter: 4 * time.Minute}, polls: 3},
+		},
+		"MaxInterval": {
+			client:     get(1, false),
+			reader:     get(1, false),
+			wrapped:    wrapped(wrappedResult, nil),
+			inProgress: inProgress(true),
+			poll:       &poll{count: 100, generation: 1},
+			want:       want{result: reconcile.Result{RequeueAfter: 10 * time.Minute}, polls: 101},
+		},
+		"WrappedRequestedLonger": {
+			client:     get(1, false),
+			reader:     get(1, false),
+			wrapped:    wrapped(reconcile.Result{RequeueAfter: 20 * time.Minute}, nil),
+			inProgress: inProgress(true),
+			want:       want{result: reconcile.Result{RequeueAfter: 20 * time.Minute}, polls: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				client:     tc.client,
+				reader:     tc.reader,
+				newManaged: func() resource.Managed { return &fake.Managed{} },
+				inProgress: tc.inProgress,
+				wrapped:    tc.wrapped,
+				interval:   1 * time.Minute,
+				max:        10 * time.Minute,
+				polls:      map[types.NamespacedName]poll{},
+			}
+			if tc.poll != nil {
+				r.polls[nn] = *tc.poll
+			}
+
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: nn})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got, approx); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.polls, r.polls[nn].count); diff != "" {
+				t.Errorf("r.Reconcile(...): -want polls, +got polls:\n%s", diff)
+			}
+		})
+	}
+}
+
+// manager is a fake manager that reads from its client.
+type manager struct{ *fake.Manager }
+
+func (m manager) GetAPIReader() client.Reader { return m.Client }
+
+func TestNewReconciler(t *testing.T) {
+	type want struct {
+		Interval time.Duration
+		Max      time.Duration
+	}
+
+	cases := map[string]struct {
+		o    []ReconcilerOption
+		want want
+	}{
+		"Default": {
+			want: want{Interval: DefaultInterval, Max: DefaultMaxInterval},
+		},
+		"Configured": {
+			o:    []ReconcilerOption{WithIntervals(2*time.Minute, 20*time.Minute)},
+			want: want{Interval: 2 * time.Minute, Max: 20 * time.Minute},
+		},
+		"Raised": {
+			o:    []ReconcilerOption{WithIntervals(10*time.Second, 30*time.Second)},
+			want: want{Interval: minInterval, Max: minInterval},
+		},
+		"Disabled": {
+			o:    []ReconcilerOption{WithIntervals(0, 0)},
+			want: want{Interval: 0, Max: 0},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := manager{&fake.Manager{Client: &test.MockClient{}, Scheme: fake.SchemeWith(&fake.Managed{})}}
+			r := NewReconciler(m, resource.ManagedKind(fake.GVK(&fake.Managed{})), inProgress(true), wrapped(wrappedResult, nil), tc.o...)
+			if diff := cmp.Diff(tc.want, want{Interval: r.interval, Max: r.max}); diff != "" {
+				t.Errorf("NewReconciler(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
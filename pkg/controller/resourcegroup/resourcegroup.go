@@ -26,8 +26,10 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -38,6 +40,8 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/provider-azure/pkg/clients/resourcegroup"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
 )
 
 // Error strings
@@ -50,22 +54,26 @@ const (
 )
 
 // Setup adds a controller that reconciles ResourceGroups.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
 	name := managed.ControllerName(v1alpha3.ResourceGroupGroupKind)
+	throttled := azure.NewThrottle()
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.ResourceGroup{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.ResourceGroupList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.ResourceGroupGroupVersionKind), managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha3.ResourceGroupGroupVersionKind),
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{kube: mgr.GetClient()}),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{kube: mgr.GetClient(), observeOnly: observeOnly}, throttled, observeOnly)),
 			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o))
 }
 
 type connecter struct {
-	kube client.Client
+	kube        client.Client
+	observeOnly bool
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -75,12 +83,13 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 	cl := resources.NewGroupsClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	return &external{client: cl, observeOnly: c.observeOnly}, nil
 }
 
 // external is a createsyncdeleter using the Azure Groups API.
 type external struct {
-	client resourcegroup.GroupsClient
+	client      resourcegroup.GroupsClient
+	observeOnly bool
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -102,6 +111,14 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetResourceGroup)
 	}
+	// ResourceGroups do not record the ID of their group, so groups created
+	// before ownership tags were written must be annotated for adoption.
+	if err := azure.ValidateOwnership(r, "", azure.ToString(g.ID), g.Tags); err != nil && !e.observeOnly {
+		if meta.WasDeleted(r) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetResourceGroup)
+	}
 	if g.Properties != nil {
 		r.Status.ProvisioningState = v1alpha3.ProvisioningState(to.String(g.Properties.ProvisioningState))
 	}
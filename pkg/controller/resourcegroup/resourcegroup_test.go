@@ -36,6 +36,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	fakerg "github.com/crossplane/provider-azure/pkg/clients/resourcegroup/fake"
 )
 
@@ -55,6 +56,21 @@ func withProvisioningstate(s v1alpha3.ProvisioningState) resourceGroupModifier {
 	return func(r *v1alpha3.ResourceGroup) { r.Status.ProvisioningState = s }
 }
 
+func withDeletionTimestamp(t metav1.Time) resourceGroupModifier {
+	return func(r *v1alpha3.ResourceGroup) { r.SetDeletionTimestamp(&t) }
+}
+
+func withAnnotations(a map[string]string) resourceGroupModifier {
+	return func(r *v1alpha3.ResourceGroup) { meta.AddAnnotations(r, a) }
+}
+
+func owned() map[string]*string {
+	return map[string]*string{
+		azure.TagKeyKind: to.StringPtr(v1alpha3.ResourceGroupKind),
+		azure.TagKeyUID:  to.StringPtr(string(uid)),
+	}
+}
+
 func resourceGrp(rm ...resourceGroupModifier) *v1alpha3.ResourceGroup {
 	r := &v1alpha3.ResourceGroup{
 		ObjectMeta: metav1.ObjectMeta{
@@ -79,6 +95,7 @@ func resourceGrp(rm ...resourceGroupModifier) *v1alpha3.ResourceGroup {
 
 func TestObserve(t *testing.T) {
 	errBoom := errors.New("boom")
+	now := metav1.Now()
 
 	type args struct {
 		ctx context.Context
@@ -164,7 +181,7 @@ func TestObserve(t *testing.T) {
 						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
 					},
 					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
-						return resources.Group{Properties: &resources.GroupProperties{
+						return resources.Group{Tags: owned(), Properties: &resources.GroupProperties{
 							ProvisioningState: to.StringPtr(string(v1alpha3.ProvisioningStateSucceeded)),
 						}}, nil
 					},
@@ -184,6 +201,92 @@ func TestObserve(t *testing.T) {
 				),
 			},
 		},
+		"NotOwned": {
+			e: &external{
+				client: &fakerg.MockClient{
+					MockCheckExistence: func(_ context.Context, _ string) (result autorest.Response, err error) {
+						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+					},
+					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
+						return resources.Group{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: resourceGrp(),
+			},
+			want: want{
+				mg:  resourceGrp(),
+				err: errors.Wrap(azure.ValidateOwnership(resourceGrp(), "", "", nil), errGetResourceGroup),
+			},
+		},
+		"NotOwnedDeleted": {
+			e: &external{
+				client: &fakerg.MockClient{
+					MockCheckExistence: func(_ context.Context, _ string) (result autorest.Response, err error) {
+						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+					},
+					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
+						return resources.Group{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: resourceGrp(withDeletionTimestamp(now)),
+			},
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false},
+				mg: resourceGrp(withDeletionTimestamp(now)),
+			},
+		},
+		"NotOwnedObserveOnly": {
+			e: &external{
+				observeOnly: true,
+				client: &fakerg.MockClient{
+					MockCheckExistence: func(_ context.Context, _ string) (result autorest.Response, err error) {
+						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+					},
+					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
+						return resources.Group{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: resourceGrp(),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				mg: resourceGrp(withConditions(runtimev1alpha1.Available())),
+			},
+		},
+		"Adopted": {
+			e: &external{
+				client: &fakerg.MockClient{
+					MockCheckExistence: func(_ context.Context, _ string) (result autorest.Response, err error) {
+						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+					},
+					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
+						return resources.Group{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: resourceGrp(withAnnotations(map[string]string{azure.AnnotationKeyAdopt: "true"})),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				mg: resourceGrp(
+					withAnnotations(map[string]string{azure.AnnotationKeyAdopt: "true"}),
+					withConditions(runtimev1alpha1.Available()),
+				),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -21,7 +21,9 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,16 +32,23 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane/provider-azure/pkg/clients"
 	azurestorage "github.com/crossplane/provider-azure/pkg/clients/storage"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+	storagectrl "github.com/crossplane/provider-azure/pkg/controller/storage"
+	"github.com/crossplane/provider-azure/pkg/controller/stuck"
 )
 
 const (
@@ -51,6 +60,12 @@ const (
 	requeueAfterOnWait    = 30 * time.Second
 )
 
+// Error strings.
+const (
+	errObserveOnlyCreate = "cannot create storage account: the provider is running in observe-only mode"
+	errListLocks         = "cannot list management locks"
+)
+
 var (
 	resultRequeue    = reconcile.Result{Requeue: true}
 	requeueOnSuccess = reconcile.Result{RequeueAfter: requeueAfterOnSuccess}
@@ -60,6 +75,7 @@ var (
 // Reconciler reconciles an Azure storage account
 type Reconciler struct {
 	client.Client
+	reader client.Reader
 	syncdeleterMaker
 	managed.ReferenceResolver
 	managed.Initializer
@@ -67,22 +83,28 @@ type Reconciler struct {
 	log logging.Logger
 }
 
-// Setup adds a controller that reconciles Accounts.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles Accounts, ignoring the supplied tags
+// when determining whether they are up to date. Accounts are only observed if
+// observeOnly is true.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool, ignored azure.IgnoredTags) error {
 	name := managed.ControllerName(v1alpha3.AccountGroupKind)
 
+	throttled := azure.NewThrottle()
 	r := &Reconciler{
 		Client:           mgr.GetClient(),
-		syncdeleterMaker: &accountSyncdeleterMaker{mgr.GetClient()},
+		reader:           mgr.GetAPIReader(),
+		syncdeleterMaker: &accountSyncdeleterMaker{Client: mgr.GetClient(), reader: mgr.GetAPIReader(), record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)), ignored: ignored, observeOnly: observeOnly, throttle: throttled},
 		Initializer:      managed.NewNameAsExternalName(mgr.GetClient()),
 		log:              l.WithValues("controller", name),
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.Account{}).
 		Owns(&corev1.Secret{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.AccountList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.AccountGroupVersionKind), r, throttled, o, chain.WithPause(), chain.WithFinalizer(finalizer), chain.WithFailedFn(stuck.RequeueFailed)))
 }
 
 // Reconcile reads that state of the cluster for a Provider acct and makes changes based on the state read
@@ -106,8 +128,8 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 
 	bh, err := r.newSyncdeleter(ctx, b)
 	if err != nil {
-		b.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, r.Status().Update(ctx, b)
+		b.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, r, r.reader, b)
 	}
 
 	// Check for deletion
@@ -124,20 +146,136 @@ type syncdeleterMaker interface {
 
 type accountSyncdeleterMaker struct {
 	client.Client
+	reader      client.Reader
+	record      event.Recorder
+	ignored     azure.IgnoredTags
+	observeOnly bool
+	throttle    *azure.Throttle
 }
 
 func (m *accountSyncdeleterMaker) newSyncdeleter(ctx context.Context, b *v1alpha3.Account) (syncdeleter, error) {
+	ctx, err := azure.WithProviderConfig(ctx, m.Client, b)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get auth information")
+	}
 	creds, auth, err := azure.GetAuthInfo(ctx, m.Client, b)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get auth information")
 	}
+	t, err := azure.GetOperationTimeout(ctx, m.Client, b)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get operation timeout")
+	}
 
 	cl := storage.NewAccountsClient(creds[azure.CredentialsKeySubscriptionID])
 	cl.Authorizer = auth
 
-	return newAccountSyncDeleter(
-		azurestorage.NewAccountHandle(&cl, b.Spec.ResourceGroupName, meta.GetExternalName(b)),
-		m.Client, b), nil
+	ao := &classifyingAccountOperations{
+		AccountOperations: azurestorage.NewAccountHandle(&cl, b.Spec.ResourceGroupName, meta.GetExternalName(b)),
+		acct:              b,
+		throttle:          m.throttle,
+	}
+	lc := locks.NewManagementLocksClient(creds[azure.CredentialsKeySubscriptionID])
+	lc.Authorizer = auth
+	ll := azure.NewLockLister(lc)
+	cll := azure.LockListerFn(func(ctx context.Context, id string) ([]locks.ManagementLockObject, error) {
+		l, err := ll.ListLocks(ctx, id)
+		return l, m.throttle.Classify(b, err)
+	})
+
+	asd := newAccountSyncDeleter(ao, cll, m.Client, m.reader, m.record, m.ignored, b)
+	asd.observeOnly = m.observeOnly
+
+	var sd syncdeleter = asd
+	if t > 0 {
+		sd = &timeoutSyncdeleter{syncdeleter: sd, timeout: t}
+	}
+	return sd, nil
+}
+
+// classifyingAccountOperations classify the errors returned by the storage
+// account operations they wrap using a Throttle, so that the reconciler backs
+// off from accounts whose requests Azure throttles and reports accounts that
+// our credentials are not authorized to access.
+type classifyingAccountOperations struct {
+	azurestorage.AccountOperations
+	acct     *v1alpha3.Account
+	throttle *azure.Throttle
+}
+
+func (o *classifyingAccountOperations) classify(err error) error {
+	return o.throttle.Classify(o.acct, err)
+}
+
+func (o *classifyingAccountOperations) Create(ctx context.Context, params storage.AccountCreateParameters) (*storage.Account, error) {
+	a, err := o.AccountOperations.Create(ctx, params)
+	return a, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) Update(ctx context.Context, params storage.AccountUpdateParameters) (*storage.Account, error) {
+	a, err := o.AccountOperations.Update(ctx, params)
+	return a, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) Get(ctx context.Context) (*storage.Account, error) {
+	a, err := o.AccountOperations.Get(ctx)
+	return a, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) Delete(ctx context.Context) error {
+	return o.classify(o.AccountOperations.Delete(ctx))
+}
+
+func (o *classifyingAccountOperations) IsAccountNameAvailable(ctx context.Context, name string) error {
+	return o.classify(o.AccountOperations.IsAccountNameAvailable(ctx, name))
+}
+
+func (o *classifyingAccountOperations) ListKeys(ctx context.Context) ([]storage.AccountKey, error) {
+	k, err := o.AccountOperations.ListKeys(ctx)
+	return k, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) GetBlobServiceProperties(ctx context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+	p, err := o.AccountOperations.GetBlobServiceProperties(ctx)
+	return p, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) SetBlobServiceProperties(ctx context.Context, p mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error) {
+	set, err := o.AccountOperations.SetBlobServiceProperties(ctx, p)
+	return set, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) SetRoutingPreference(ctx context.Context, p mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+	set, err := o.AccountOperations.SetRoutingPreference(ctx, p)
+	return set, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) UpgradeKind(ctx context.Context, k storage.Kind) error {
+	return o.classify(o.AccountOperations.UpgradeKind(ctx, k))
+}
+
+func (o *classifyingAccountOperations) CreateWithImmutableStorage(ctx context.Context, params storage.AccountCreateParameters, s azurestorage.ImmutableStorageWithVersioning) (*storage.Account, error) {
+	a, err := o.AccountOperations.CreateWithImmutableStorage(ctx, params, s)
+	return a, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) GetExtensions(ctx context.Context) (*azurestorage.AccountExtensions, error) {
+	ext, err := o.AccountOperations.GetExtensions(ctx)
+	return ext, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) SetAccess(ctx context.Context, a azurestorage.AccountAccess) (*azurestorage.AccountAccess, error) {
+	set, err := o.AccountOperations.SetAccess(ctx, a)
+	return set, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) Failover(ctx context.Context) (*apisv1alpha3.AsyncOperation, error) {
+	op, err := o.AccountOperations.Failover(ctx)
+	return op, o.classify(err)
+}
+
+func (o *classifyingAccountOperations) FetchOperation(ctx context.Context, op *apisv1alpha3.AsyncOperation) error {
+	return o.classify(o.AccountOperations.FetchOperation(ctx, op))
 }
 
 type deleter interface {
@@ -169,29 +307,85 @@ type syncdeleter interface {
 	syncer
 }
 
+// A timeoutSyncdeleter bounds each sync and delete by the operation timeout of
+// the ProviderConfig (or Provider) of an account, so that a hung Azure API call
+// fails and is retried rather than blocking a worker.
+type timeoutSyncdeleter struct {
+	syncdeleter
+	timeout time.Duration
+}
+
+func (s *timeoutSyncdeleter) sync(ctx context.Context) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.syncdeleter.sync(ctx)
+}
+
+func (s *timeoutSyncdeleter) delete(ctx context.Context) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.syncdeleter.delete(ctx)
+}
+
 type accountSyncDeleter struct {
 	createupdater
 	azurestorage.AccountOperations
-	kube client.Client
-	acct *v1alpha3.Account
+	locks  azure.LockLister
+	kube   client.Client
+	reader client.Reader
+	record event.Recorder
+	acct   *v1alpha3.Account
+
+	// observeOnly accounts are never created, updated, or deleted.
+	observeOnly bool
 }
 
-func newAccountSyncDeleter(ao azurestorage.AccountOperations, kube client.Client, b *v1alpha3.Account) *accountSyncDeleter {
+func newAccountSyncDeleter(ao azurestorage.AccountOperations, l azure.LockLister, kube client.Client, reader client.Reader, record event.Recorder, ignored azure.IgnoredTags, b *v1alpha3.Account) *accountSyncDeleter {
 	return &accountSyncDeleter{
-		createupdater:     newAccountCreateUpdater(ao, kube, b),
+		createupdater:     newAccountCreateUpdater(ao, kube, reader, ignored, b),
 		AccountOperations: ao,
+		locks:             l,
 		kube:              kube,
+		reader:            reader,
+		record:            record,
 		acct:              b,
 	}
 }
 
 func (asd *accountSyncDeleter) delete(ctx context.Context) (reconcile.Result, error) {
 	asd.acct.Status.SetConditions(runtimev1alpha1.Deleting())
-	switch asd.acct.Spec.DeletionPolicy {
+	policy := asd.acct.Spec.DeletionPolicy
+	if asd.observeOnly {
+		// We never delete storage accounts in observe-only mode.
+		policy = runtimev1alpha1.DeletionOrphan
+	}
+	switch policy {
 	case runtimev1alpha1.DeletionDelete, "":
+		account, err := asd.Get(ctx)
+		if err != nil && !azure.IsNotFound(err) {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
+		// An account we don't own is released without being deleted.
+		if account == nil || asd.validateOwnership(account) != nil {
+			break
+		}
+		l, err := asd.listLocks(ctx, account)
+		if err != nil {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
+		azure.SetLockedCondition(asd.acct, l)
+		if azure.IsLocked(l) {
+			// Azure refuses to delete a locked storage account. The Locked
+			// condition explains why it has not been deleted; it will be
+			// deleted once it is unlocked.
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
+			return requeueOnWait, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
 		if err := asd.Delete(ctx); err != nil && !azure.IsNotFound(err) {
-			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-			return resultRequeue, asd.kube.Status().Update(ctx, asd.acct)
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
 		}
 	case runtimev1alpha1.DeletionOrphan:
 		// No need to do anything if we plan to orphan this account.
@@ -200,7 +394,7 @@ func (asd *accountSyncDeleter) delete(ctx context.Context) (reconcile.Result, er
 	// NOTE(negz): We don't update the conditioned status here because assuming
 	// no other finalizers need to be cleaned up the object should cease to
 	// exist after we update it.
-	meta.RemoveFinalizer(asd.acct, finalizer)
+	storagectrl.RemoveFinalizer(asd.acct, finalizer)
 	return reconcile.Result{}, asd.kube.Update(ctx, asd.acct)
 }
 
@@ -209,17 +403,127 @@ func (asd *accountSyncDeleter) delete(ctx context.Context) (reconcile.Result, er
 func (asd *accountSyncDeleter) sync(ctx context.Context) (reconcile.Result, error) {
 	account, err := asd.Get(ctx)
 	if err != nil && !azure.IsNotFound(err) {
-		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, asd.kube.Status().Update(ctx, asd.acct)
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+
+	// Ownership is irrelevant in observe-only mode, in which we never mutate
+	// the accounts we observe.
+	if account != nil && !asd.observeOnly {
+		if err := asd.validateOwnership(account); err != nil {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
 	}
 
 	if account == nil {
+		if asd.observeOnly {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(errors.New(errObserveOnlyCreate)))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
 		return asd.create(ctx)
 	}
 
+	l, err := asd.listLocks(ctx, account)
+	if err != nil {
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+	azure.SetLockedCondition(asd.acct, l)
+
+	// In observe-only mode we only sync the observed storage account back to
+	// our spec and status.
+	if asd.observeOnly {
+		if account.ProvisioningState == storage.Succeeded {
+			asd.acct.Status.SetConditions(runtimev1alpha1.Available())
+		}
+		return newAccountSyncBacker(asd.AccountOperations, asd.kube, asd.reader, asd.acct).syncback(ctx, account)
+	}
+
+	// Azure refuses to update a read only storage account, or to list its
+	// keys, so there is nothing we can do until it is unlocked.
+	if azure.IsReadOnly(l) {
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.NewLockedError("update", l)))
+		return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+
+	if azurestorage.FailoverRequested(asd.acct) {
+		return asd.failover(ctx, account)
+	}
+
 	return asd.update(ctx, account)
 }
 
+// listLocks returns the management locks that apply to the supplied storage
+// account, including those inherited from its resource group or subscription.
+func (asd *accountSyncDeleter) listLocks(ctx context.Context, account *storage.Account) ([]locks.ManagementLockObject, error) {
+	id := to.String(account.ID)
+	if id == "" {
+		return nil, nil
+	}
+	l, err := asd.locks.ListLocks(ctx, id)
+	return l, errors.Wrap(err, errListLocks)
+}
+
+// validateOwnership returns an error if our account may not manage the
+// supplied storage account.
+func (asd *accountSyncDeleter) validateOwnership(account *storage.Account) error {
+	var recorded string
+	if s := asd.acct.Status.StorageAccountStatus; s != nil {
+		recorded = s.ID
+	}
+	return azure.ValidateOwnership(asd.acct, recorded, to.String(account.ID), account.Tags)
+}
+
+// failover starts a requested failover of the storage account to its
+// secondary region, or tracks the progress of one that was already started.
+// The annotation that requested the failover is removed once it completes.
+func (asd *accountSyncDeleter) failover(ctx context.Context, account *storage.Account) (reconcile.Result, error) {
+	op := asd.acct.Status.LastFailover
+	if !azurestorage.FailoverInProgress(op) {
+		var sku storage.SkuName
+		if account.Sku != nil {
+			sku = account.Sku.Name
+		}
+		if err := azurestorage.ValidateFailover(sku); err != nil {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
+		op, err := asd.Failover(ctx)
+		if err != nil {
+			asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(errors.Wrap(err, "failed to start storage account failover"))))
+			return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+		}
+		asd.record.Event(asd.acct, event.Normal(azurestorage.ReasonFailoverStarted, "Started failover of storage account to its secondary region"))
+		asd.acct.Status.LastFailover = op
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
+		return requeueOnWait, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+
+	fetched := *op
+	if err := asd.FetchOperation(ctx, &fetched); err != nil {
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(errors.Wrap(err, "failed to fetch storage account failover"))))
+		return resultRequeue, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+	if azurestorage.FailoverInProgress(&fetched) {
+		asd.acct.Status.LastFailover = &fetched
+		asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
+		return requeueOnWait, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+	}
+
+	// We remove the annotation before recording the completed failover in
+	// our status so that a failure to do so results in the failover being
+	// observed to complete again, rather than in a new failover.
+	meta.RemoveAnnotations(asd.acct, azurestorage.AnnotationKeyFailover)
+	if err := asd.kube.Update(ctx, asd.acct); err != nil {
+		return resultRequeue, err
+	}
+	asd.record.Event(asd.acct, azurestorage.FailoverCompletedEvent(fetched))
+	asd.acct.Status.LastFailover = &fetched
+	asd.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
+	return requeueOnWait, azure.UpdateStatus(ctx, asd.kube, asd.reader, asd.acct)
+}
+
 // createupdater interface defining create and update operations on/for storage account resource
 type createupdater interface {
 	creator
@@ -231,17 +535,21 @@ type accountCreateUpdater struct {
 	syncbacker
 	azurestorage.AccountOperations
 	kube      client.Client
+	reader    client.Reader
 	acct      *v1alpha3.Account
 	projectID string
+	ignored   azure.IgnoredTags
 }
 
 // newAccountCreateUpdater new instance of accountCreateUpdater
-func newAccountCreateUpdater(ao azurestorage.AccountOperations, kube client.Client, acct *v1alpha3.Account) *accountCreateUpdater {
+func newAccountCreateUpdater(ao azurestorage.AccountOperations, kube client.Client, reader client.Reader, ignored azure.IgnoredTags, acct *v1alpha3.Account) *accountCreateUpdater {
 	return &accountCreateUpdater{
-		syncbacker:        newAccountSyncBacker(ao, kube, acct),
+		syncbacker:        newAccountSyncBacker(ao, kube, reader, acct),
 		AccountOperations: ao,
 		kube:              kube,
+		reader:            reader,
 		acct:              acct,
+		ignored:           ignored,
 	}
 }
 
@@ -250,12 +558,25 @@ func (acu *accountCreateUpdater) create(ctx context.Context) (reconcile.Result,
 	acu.acct.Status.SetConditions(runtimev1alpha1.Creating())
 	meta.AddFinalizer(acu.acct, finalizer)
 
-	accountSpec := v1alpha3.ToStorageAccountCreate(acu.acct.Spec.StorageAccountSpec)
+	if err := azure.ValidateName(v1alpha3.AccountKind, meta.GetExternalName(acu.acct)); err != nil {
+		acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
+		return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+	}
 
-	a, err := acu.Create(ctx, accountSpec)
+	accountSpec := v1alpha3.ToStorageAccountCreate(acu.acct.Spec.StorageAccountSpec)
+	accountSpec.Tags = azure.WithOwnershipTags(accountSpec.Tags, v1alpha3.AccountKind, acu.acct)
+
+	var a *storage.Account
+	var err error
+	if s := acu.acct.Spec.ImmutableStorageWithVersioning; s != nil {
+		a, err = acu.CreateWithImmutableStorage(ctx, accountSpec, azurestorage.NewImmutableStorageWithVersioning(s))
+	} else {
+		a, err = acu.Create(ctx, accountSpec)
+	}
 	if err != nil {
-		acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, acu.kube.Status().Update(ctx, acu.acct)
+		err = azure.WaitForResourceGroup(acu.acct, acu.acct.Spec.ResourceGroupName, err)
+		acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
 	}
 
 	return acu.syncback(ctx, a)
@@ -266,16 +587,57 @@ func (acu *accountCreateUpdater) update(ctx context.Context, account *storage.Ac
 	if account.ProvisioningState == storage.Succeeded {
 		acu.acct.Status.SetConditions(runtimev1alpha1.Available())
 
-		current := v1alpha3.NewStorageAccountSpec(account)
-		if reflect.DeepEqual(current, acu.acct.Spec.StorageAccountSpec) {
+		ext, err := acu.getExtensions(ctx)
+		if err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		if err := acu.validateImmutableStorage(ext); err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		if err := acu.syncBlobService(ctx); err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		if err := acu.syncRoutingPreference(ctx, ext); err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		if err := acu.syncAccess(ctx, ext); err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		ignoreKeyCase := to.Bool(acu.acct.Spec.IgnoreTagKeyCase)
+		if isUpToDate(acu.acct.Spec.StorageAccountSpec, v1alpha3.NewStorageAccountSpec(account), acu.ignored, ignoreKeyCase) {
 			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
-			return requeueOnSuccess, acu.kube.Status().Update(ctx, acu.acct)
+			return requeueOnSuccess, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
 		}
 
-		a, err := acu.Update(ctx, v1alpha3.ToStorageAccountUpdate(acu.acct.Spec.StorageAccountSpec))
-		if err != nil {
+		if err := validateSkuChange(account, acu.acct.Spec.StorageAccountSpec); err != nil {
 			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-			return resultRequeue, acu.kube.Status().Update(ctx, acu.acct)
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		if err := acu.upgradeKind(ctx, account); err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
+		}
+
+		params := v1alpha3.ToStorageAccountUpdate(acu.acct.Spec.StorageAccountSpec)
+		if ignoreKeyCase {
+			params.Tags = *to.StringMapPtr(azure.PreserveTagKeyCase(acu.acct.Spec.StorageAccountSpec.Tags, to.StringMap(account.Tags)))
+		}
+		params.Tags = acu.ignored.Preserve(params.Tags, account.Tags)
+		a, err := acu.Update(ctx, params)
+		if err != nil {
+			acu.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, acu.kube, acu.reader, acu.acct)
 		}
 		account = a
 	}
@@ -283,16 +645,142 @@ func (acu *accountCreateUpdater) update(ctx context.Context, account *storage.Ac
 	return acu.syncback(ctx, account)
 }
 
+// upgradeKind upgrades the supplied storage account in place if its kind does
+// not match the desired kind. Azure cannot otherwise change the kind of an
+// existing storage account.
+func (acu *accountCreateUpdater) upgradeKind(ctx context.Context, observed *storage.Account) error {
+	desired := acu.acct.Spec.StorageAccountSpec
+	if desired == nil || observed.Kind == desired.Kind {
+		return nil
+	}
+	if err := azurestorage.ValidateKindChange(observed.Kind, desired.Kind); err != nil {
+		return err
+	}
+	return errors.Wrap(acu.UpgradeKind(ctx, desired.Kind), "failed to upgrade storage account kind")
+}
+
+// getExtensions returns the extension properties of the storage account, or
+// nil if none of them are desired. They are fetched once per reconcile and
+// shared by the routing preference, immutable storage, and access.
+func (acu *accountCreateUpdater) getExtensions(ctx context.Context) (*azurestorage.AccountExtensions, error) {
+	if acu.acct.Spec.RoutingPreference == nil && acu.acct.Spec.ImmutableStorageWithVersioning == nil &&
+		azurestorage.NewAccountAccess(acu.acct.Spec.AccountParameters).IsZero() {
+		return nil, nil
+	}
+	ext, err := acu.GetExtensions(ctx)
+	return ext, errors.Wrap(err, "failed to get storage account extensions")
+}
+
+// validateImmutableStorage returns an error if the account-level immutable
+// storage of the storage account does not match the desired immutable
+// storage. It can only be configured when the account is created.
+func (acu *accountCreateUpdater) validateImmutableStorage(ext *azurestorage.AccountExtensions) error {
+	desired := acu.acct.Spec.ImmutableStorageWithVersioning
+	if desired == nil {
+		return nil
+	}
+	return azurestorage.ValidateImmutableStorageChange(desired, &ext.ImmutableStorage)
+}
+
+// syncBlobService updates the blob service of the storage account if it does
+// not match the desired blob service properties, and reports its state.
+func (acu *accountCreateUpdater) syncBlobService(ctx context.Context) error {
+	p := acu.acct.Spec.BlobServiceProperties
+	if p == nil {
+		acu.acct.Status.BlobService = nil
+		return nil
+	}
+	if err := azurestorage.ValidateBlobServiceProperties(p); err != nil {
+		return err
+	}
+
+	observed, err := acu.GetBlobServiceProperties(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get blob service properties")
+	}
+	desired := azurestorage.NewBlobServiceProperties(p)
+	if !azurestorage.IsBlobServiceUpToDate(desired, *observed) {
+		if observed, err = acu.SetBlobServiceProperties(ctx, desired); err != nil {
+			return errors.Wrap(err, "failed to set blob service properties")
+		}
+	}
+	acu.acct.Status.BlobService = azurestorage.NewBlobServiceStatus(*observed, time.Now())
+	return nil
+}
+
+// syncRoutingPreference updates the routing preference of the storage account
+// if it does not match the desired routing preference, and reports the
+// routing specific endpoints it publishes.
+func (acu *accountCreateUpdater) syncRoutingPreference(ctx context.Context, ext *azurestorage.AccountExtensions) error {
+	p := acu.acct.Spec.RoutingPreference
+	if p == nil {
+		acu.acct.Status.MicrosoftEndpoints = nil
+		acu.acct.Status.InternetEndpoints = nil
+		return nil
+	}
+
+	observed := &ext.Properties
+	if !azurestorage.IsRoutingPreferenceUpToDate(p, observed.RoutingPreference) {
+		var err error
+		if observed, err = acu.SetRoutingPreference(ctx, azurestorage.NewRoutingPreference(p)); err != nil {
+			return errors.Wrap(err, "failed to set routing preference")
+		}
+	}
+	acu.acct.Status.MicrosoftEndpoints = azurestorage.NewMicrosoftEndpoints(*observed)
+	acu.acct.Status.InternetEndpoints = azurestorage.NewInternetEndpoints(*observed)
+	return nil
+}
+
+// syncAccess updates the public and shared key access of the storage account
+// if it does not match the desired access.
+func (acu *accountCreateUpdater) syncAccess(ctx context.Context, ext *azurestorage.AccountExtensions) error {
+	desired := azurestorage.NewAccountAccess(acu.acct.Spec.AccountParameters)
+	if desired.IsZero() {
+		return nil
+	}
+	if azurestorage.IsAccountAccessUpToDate(desired, ext.Access) {
+		return nil
+	}
+	_, err := acu.SetAccess(ctx, desired)
+	return errors.Wrap(err, "failed to set account access")
+}
+
+// isUpToDate returns true if the supplied desired spec matches the supplied
+// observed spec, disregarding the supplied ignored tags. Tag keys are compared
+// case-insensitively if ignoreTagKeyCase is true.
+func isUpToDate(desired, observed *v1alpha3.StorageAccountSpec, ignored azure.IgnoredTags, ignoreTagKeyCase bool) bool {
+	if desired == nil || observed == nil {
+		return desired == observed
+	}
+	d, o := desired.DeepCopy(), observed.DeepCopy()
+	d.Tags, o.Tags = ignored.Filter(d.Tags), ignored.Filter(o.Tags)
+	if ignoreTagKeyCase {
+		d.Tags, o.Tags = azure.FoldTagKeys(d.Tags), azure.FoldTagKeys(o.Tags)
+	}
+	return reflect.DeepEqual(d, o)
+}
+
+// validateSkuChange returns an error if the supplied account cannot be
+// converted to the SKU of the supplied desired spec.
+func validateSkuChange(observed *storage.Account, desired *v1alpha3.StorageAccountSpec) error {
+	if observed.Sku == nil || desired == nil || desired.Sku == nil {
+		return nil
+	}
+	return azurestorage.ValidateSkuChange(observed.Sku.Name, desired.Sku.Name)
+}
+
 type accountSyncbacker struct {
 	secretupdater
-	acct *v1alpha3.Account
-	kube client.Client
+	acct   *v1alpha3.Account
+	kube   client.Client
+	reader client.Reader
 }
 
-func newAccountSyncBacker(ao azurestorage.AccountOperations, kube client.Client, acct *v1alpha3.Account) *accountSyncbacker {
+func newAccountSyncBacker(ao azurestorage.AccountOperations, kube client.Client, reader client.Reader, acct *v1alpha3.Account) *accountSyncbacker {
 	return &accountSyncbacker{
 		secretupdater: newAccountSecretUpdater(ao, kube, acct),
 		kube:          kube,
+		reader:        reader,
 		acct:          acct,
 	}
 }
@@ -307,16 +795,16 @@ func (asb *accountSyncbacker) syncback(ctx context.Context, acct *storage.Accoun
 
 	if acct.ProvisioningState != storage.Succeeded {
 		asb.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
-		return requeueOnWait, asb.kube.Status().Update(ctx, asb.acct)
+		return requeueOnWait, azure.UpdateStatus(ctx, asb.kube, asb.reader, asb.acct)
 	}
 
 	if err := asb.updatesecret(ctx, acct); err != nil {
-		asb.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, asb.kube.Status().Update(ctx, asb.acct)
+		asb.acct.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, asb.kube, asb.reader, asb.acct)
 	}
 
 	asb.acct.Status.SetConditions(runtimev1alpha1.ReconcileSuccess())
-	return requeueOnSuccess, asb.kube.Status().Update(ctx, asb.acct)
+	return requeueOnSuccess, azure.UpdateStatus(ctx, asb.kube, asb.reader, asb.acct)
 }
 
 type accountSecretUpdater struct {
@@ -340,17 +828,27 @@ func (asu *accountSecretUpdater) updatesecret(ctx context.Context, acct *storage
 	if acct.PrimaryEndpoints != nil {
 		secret.Data[runtimev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(to.String(acct.PrimaryEndpoints.Blob))
 	}
-
-	keys, err := asu.ListKeys(ctx)
-	if err != nil {
-		return errors.Wrapf(err, "failed to list account keys")
+	if e := asu.acct.Status.MicrosoftEndpoints; e != nil && e.Blob != "" {
+		secret.Data[azurestorage.ConnectionSecretMicrosoftEndpointKey] = []byte(e.Blob)
 	}
-	if len(keys) == 0 {
-		return errors.New("account keys are empty")
+	if e := asu.acct.Status.InternetEndpoints; e != nil && e.Blob != "" {
+		secret.Data[azurestorage.ConnectionSecretInternetEndpointKey] = []byte(e.Blob)
 	}
 
 	secret.Data[runtimev1alpha1.ResourceCredentialsSecretUserKey] = []byte(meta.GetExternalName(asu.acct))
-	secret.Data[runtimev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(to.String(keys[0].Value))
+
+	// Consumers of an account that disallows shared key access must use
+	// Azure Active Directory, so we don't publish its access key.
+	if !azurestorage.IsSharedKeyAccessDisabled(asu.acct.Spec.AccountParameters) {
+		keys, err := asu.ListKeys(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list account keys")
+		}
+		if len(keys) == 0 {
+			return errors.New("account keys are empty")
+		}
+		secret.Data[runtimev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(to.String(keys[0].Value))
+	}
 
 	if err := asu.kube.Create(ctx, secret); err != nil {
 		if kerrors.IsAlreadyExists(err) {
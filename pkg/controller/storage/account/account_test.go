@@ -24,11 +24,14 @@ import (
 
 	"github.com/crossplane/provider-azure/apis"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,6 +43,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -47,6 +51,7 @@ import (
 	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
 	v1alpha3test "github.com/crossplane/provider-azure/apis/storage/v1alpha3/test"
 	azurev1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	azurestorage "github.com/crossplane/provider-azure/pkg/clients/storage"
 	azurestoragefake "github.com/crossplane/provider-azure/pkg/clients/storage/fake"
 )
@@ -151,6 +156,25 @@ func newStoragAccountSpecWithProperties() *v1alpha3.StorageAccountSpec {
 	return v1alpha3.NewStorageAccountSpec(&storage.Account{AccountProperties: &storage.AccountProperties{}})
 }
 
+func newBlobServiceProperties() *v1alpha3.BlobServiceProperties {
+	days := int32(7)
+	return &v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true, Days: &days}}
+}
+
+func newStoragAccountSpecWithSku(n storage.SkuName) *v1alpha3.StorageAccountSpec {
+	return v1alpha3.NewStorageAccountSpec(&storage.Account{
+		AccountProperties: &storage.AccountProperties{},
+		Sku:               &storage.Sku{Name: n},
+	})
+}
+
+func newStoragAccountSpecWithKind(k storage.Kind) *v1alpha3.StorageAccountSpec {
+	return v1alpha3.NewStorageAccountSpec(&storage.Account{
+		AccountProperties: &storage.AccountProperties{},
+		Kind:              k,
+	})
+}
+
 type storageAccount struct {
 	*storage.Account
 }
@@ -183,7 +207,8 @@ func (sap *storageAccountProperties) withProvisioningStage(ps storage.Provisioni
 
 const (
 	testNamespace   = "default"
-	testAccountName = "testAccount"
+	testAccountName = "testaccount"
+	testAccountID   = "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Storage/storageAccounts/testaccount"
 )
 
 func TestReconciler_Reconcile(t *testing.T) {
@@ -295,11 +320,15 @@ func Test_syncdeleter_delete(t *testing.T) {
 	ctx := context.TODO()
 	bucketName := "test-account"
 	errBoom := errors.New("boom")
+	owned := &storage.Account{Tags: azure.WithOwnershipTags(nil, v1alpha3.AccountKind, v1alpha3test.NewMockAccount(bucketName).Account)}
+	ownedWithID := &storage.Account{ID: to.StringPtr(testAccountID), Tags: owned.Tags}
+	locked := []locks.ManagementLockObject{{Name: to.StringPtr("keep"), ManagementLockProperties: &locks.ManagementLockProperties{Level: locks.CanNotDelete}}}
 
 	type fields struct {
-		ao   azurestorage.AccountOperations
-		cc   client.Client
-		acct *v1alpha3.Account
+		ao    azurestorage.AccountOperations
+		locks azure.LockLister
+		cc    client.Client
+		acct  *v1alpha3.Account
 	}
 	type want struct {
 		err  error
@@ -342,7 +371,10 @@ func Test_syncdeleter_delete(t *testing.T) {
 						return nil
 					},
 				},
-				ao: azurestoragefake.NewMockAccountOperations(),
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet:    func(context.Context) (*storage.Account, error) { return owned, nil },
+					MockDelete: func(context.Context) error { return nil },
+				},
 			},
 			want: want{
 				err: nil,
@@ -365,6 +397,7 @@ func Test_syncdeleter_delete(t *testing.T) {
 					},
 				},
 				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(context.Context) (*storage.Account, error) { return owned, nil },
 					MockDelete: func(ctx context.Context) error {
 						return errBoom
 					},
@@ -388,6 +421,7 @@ func Test_syncdeleter_delete(t *testing.T) {
 					MockUpdate: func(ctx context.Context, obj runtime.Object, _ ...client.UpdateOption) error { return nil },
 				},
 				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(context.Context) (*storage.Account, error) { return owned, nil },
 					MockDelete: func(ctx context.Context) error {
 						return autorest.DetailedError{
 							StatusCode: http.StatusNotFound,
@@ -405,10 +439,79 @@ func Test_syncdeleter_delete(t *testing.T) {
 					Account,
 			},
 		},
+		{
+			name: "DeleteLocked",
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(bucketName).WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Account,
+				cc: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet:    func(context.Context) (*storage.Account, error) { return ownedWithID, nil },
+					MockDelete: func(context.Context) error { return errBoom },
+				},
+				locks: azure.LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return locked, nil }),
+			},
+			want: want{
+				err: nil,
+				res: requeueOnWait,
+				acct: v1alpha3test.NewMockAccount(bucketName).WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).
+					WithStatusConditions(runtimev1alpha1.Deleting(), azure.Locked(locked), runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "DeleteListLocksFailed",
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(bucketName).WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Account,
+				cc: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet:    func(context.Context) (*storage.Account, error) { return ownedWithID, nil },
+					MockDelete: func(context.Context) error { return nil },
+				},
+				locks: azure.LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return nil, errBoom }),
+			},
+			want: want{
+				err: nil,
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(bucketName).WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).
+					WithStatusConditions(runtimev1alpha1.Deleting(), runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errListLocks))).
+					Account,
+			},
+		},
+		{
+			name: "DeleteNotOwned",
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(bucketName).WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Account,
+				cc: &test.MockClient{
+					MockUpdate: func(ctx context.Context, obj runtime.Object, _ ...client.UpdateOption) error { return nil },
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet:    func(context.Context) (*storage.Account, error) { return &storage.Account{}, nil },
+					MockDelete: func(context.Context) error { return errBoom },
+				},
+			},
+			want: want{
+				err: nil,
+				res: reconcile.Result{},
+				acct: v1alpha3test.NewMockAccount(bucketName).
+					WithFinalizers([]string{}).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithStatusConditions(runtimev1alpha1.Deleting()).
+					Account,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			bh := newAccountSyncDeleter(tt.fields.ao, tt.fields.cc, tt.fields.acct)
+			bh := newAccountSyncDeleter(tt.fields.ao, tt.fields.locks, tt.fields.cc, tt.fields.cc, event.NewNopRecorder(), nil, tt.fields.acct)
 			got, err := bh.delete(ctx)
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("accountSyncDeleter.delete(): -want error, +got error: \n%s", diff)
@@ -427,11 +530,17 @@ func Test_syncdeleter_sync(t *testing.T) {
 	ctx := context.TODO()
 	name := testAccountName
 	errBoom := errors.New("boom")
+	failover := map[string]string{azurestorage.AnnotationKeyFailover: ""}
+	inProgress := &azurev1alpha3.AsyncOperation{Method: http.MethodPost, PollingURL: "https://example.org/op", Status: azure.AsyncOperationStatusInProgress}
+	owned := azure.WithOwnershipTags(nil, v1alpha3.AccountKind, v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account)
+	ownedNoUID := azure.WithOwnershipTags(nil, v1alpha3.AccountKind, v1alpha3test.NewMockAccount(name).Account)
+	readOnly := []locks.ManagementLockObject{{Name: to.StringPtr("frozen"), ManagementLockProperties: &locks.ManagementLockProperties{Level: locks.ReadOnly}}}
 
 	type fields struct {
-		ao   azurestorage.AccountOperations
-		kube client.Client
-		acct *v1alpha3.Account
+		ao    azurestorage.AccountOperations
+		locks azure.LockLister
+		kube  client.Client
+		acct  *v1alpha3.Account
 	}
 	type want struct {
 		err  error
@@ -487,6 +596,27 @@ func Test_syncdeleter_sync(t *testing.T) {
 				acct: v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
 			},
 		},
+		{
+			name: "NotOwned",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{}, nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithUID("test-uid").
+					WithStatusConditions(runtimev1alpha1.ReconcileError(azure.ValidateOwnership(v1alpha3test.NewMockAccount(name).Account, "", "", nil))).
+					Account,
+			},
+		},
 		{
 			name: "Update",
 			fields: fields{
@@ -495,7 +625,7 @@ func Test_syncdeleter_sync(t *testing.T) {
 				},
 				ao: &azurestoragefake.MockAccountOperations{
 					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
-						return &storage.Account{}, nil
+						return &storage.Account{Tags: owned}, nil
 					},
 				},
 				acct: v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
@@ -505,13 +635,200 @@ func Test_syncdeleter_sync(t *testing.T) {
 				acct: v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
 			},
 		},
+		{
+			name: "ListLocksFailed",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{ID: to.StringPtr(testAccountID), Tags: owned}, nil
+					},
+				},
+				locks: azure.LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return nil, errBoom }),
+				acct:  v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithUID("test-uid").
+					WithStatusConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errListLocks))).
+					Account,
+			},
+		},
+		{
+			name: "ReadOnlyLocked",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{ID: to.StringPtr(testAccountID), Tags: owned}, nil
+					},
+				},
+				locks: azure.LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return readOnly, nil }),
+				acct:  v1alpha3test.NewMockAccount(name).WithUID("test-uid").Account,
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithUID("test-uid").
+					WithStatusConditions(azure.Locked(readOnly), runtimev1alpha1.ReconcileError(azure.NewLockedError("update", readOnly))).
+					Account,
+			},
+		},
+		{
+			name: "Unlocked",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{ID: to.StringPtr(testAccountID), Tags: owned}, nil
+					},
+				},
+				locks: azure.LockListerFn(func(context.Context, string) ([]locks.ManagementLockObject, error) { return nil, nil }),
+				acct:  v1alpha3test.NewMockAccount(name).WithUID("test-uid").WithStatusConditions(azure.Locked(readOnly)).Account,
+			},
+			want: want{
+				res:  requeueOnSuccess,
+				acct: v1alpha3test.NewMockAccount(name).WithUID("test-uid").WithStatusConditions(azure.Unlocked()).Account,
+			},
+		},
+		{
+			name: "FailoverNotGeoReplicated",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{Tags: ownedNoUID, Sku: &storage.Sku{Name: storage.StandardLRS}}, nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithAnnotations(failover).Account,
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithAnnotations(failover).
+					WithStatusConditions(runtimev1alpha1.ReconcileError(azurestorage.ValidateFailover(storage.StandardLRS))).
+					Account,
+			},
+		},
+		{
+			name: "FailoverStarted",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{Tags: ownedNoUID, Sku: &storage.Sku{Name: storage.StandardRAGRS}}, nil
+					},
+					MockFailover: func(i context.Context) (*azurev1alpha3.AsyncOperation, error) {
+						return inProgress, nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithAnnotations(failover).Account,
+			},
+			want: want{
+				res: requeueOnWait,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithAnnotations(failover).
+					WithLastFailover(inProgress).
+					WithStatusConditions(runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "FailoverInProgress",
+			fields: fields{
+				kube: &test.MockClient{
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{Tags: ownedNoUID, Sku: &storage.Sku{Name: storage.StandardRAGRS}}, nil
+					},
+					MockFetchOperation: func(i context.Context, op *azurev1alpha3.AsyncOperation) error { return nil },
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithAnnotations(failover).WithLastFailover(inProgress).Account,
+			},
+			want: want{
+				res: requeueOnWait,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithAnnotations(failover).
+					WithLastFailover(inProgress).
+					WithStatusConditions(runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "FailoverCompleted",
+			fields: fields{
+				kube: &test.MockClient{
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{Tags: ownedNoUID, Sku: &storage.Sku{Name: storage.StandardRAGRS}}, nil
+					},
+					MockFetchOperation: func(i context.Context, op *azurev1alpha3.AsyncOperation) error {
+						op.Status = "Succeeded"
+						return nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithAnnotations(failover).WithLastFailover(inProgress).Account,
+			},
+			want: want{
+				res: requeueOnWait,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithAnnotations(map[string]string{}).
+					WithLastFailover(&azurev1alpha3.AsyncOperation{Method: http.MethodPost, PollingURL: "https://example.org/op", Status: "Succeeded"}).
+					WithStatusConditions(runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "FailoverCompletedUpdateError",
+			fields: fields{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGet: func(i context.Context) (attrs *storage.Account, e error) {
+						return &storage.Account{Tags: ownedNoUID, Sku: &storage.Sku{Name: storage.StandardRAGRS}}, nil
+					},
+					MockFetchOperation: func(i context.Context, op *azurev1alpha3.AsyncOperation) error {
+						op.Status = "Succeeded"
+						return nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithAnnotations(failover).WithLastFailover(inProgress).Account,
+			},
+			want: want{
+				err: errBoom,
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithAnnotations(map[string]string{}).
+					WithLastFailover(inProgress).
+					Account,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			bh := &accountSyncDeleter{
 				createupdater:     newMockAccountCreateUpdater(),
 				AccountOperations: tt.fields.ao,
+				locks:             tt.fields.locks,
 				kube:              tt.fields.kube,
+				record:            event.NewNopRecorder(),
 				acct:              tt.fields.acct,
 			}
 
@@ -576,6 +893,46 @@ func Test_createupdater_create(t *testing.T) {
 					Account,
 			},
 		},
+		{
+			name: "InvalidName",
+			fields: fields{
+				kube: test.NewMockClient(),
+				acct: v1alpha3test.NewMockAccount("Invalid_Name").
+					Account,
+			},
+			want: want{
+				res: resultRequeue,
+				obj: v1alpha3test.NewMockAccount("Invalid_Name").
+					WithStatusConditions(runtimev1alpha1.Creating(), runtimev1alpha1.ReconcileError(azure.ValidateName(v1alpha3.AccountKind, "Invalid_Name"))).
+					WithFinalizer(finalizer).
+					Account,
+			},
+		},
+		{
+			name: "CreateWithImmutableStorageFailed",
+			fields: fields{
+				ao: &azurestoragefake.MockAccountOperations{
+					MockCreateWithImmutableStorage: func(_ context.Context, _ storage.AccountCreateParameters, s azurestorage.ImmutableStorageWithVersioning) (*storage.Account, error) {
+						if !to.Bool(s.Enabled) {
+							return nil, errors.New("immutable storage not enabled")
+						}
+						return nil, errBoom
+					},
+				},
+				kube: test.NewMockClient(),
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					Account,
+			},
+			want: want{
+				res: resultRequeue,
+				obj: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					WithStatusConditions(runtimev1alpha1.Creating(), runtimev1alpha1.ReconcileError(errBoom)).
+					WithFinalizer(finalizer).
+					Account,
+			},
+		},
 		{
 			name: "CreateSuccessful",
 			fields: fields{
@@ -629,11 +986,23 @@ func Test_bucketCreateUpdater_update(t *testing.T) {
 	name := testAccountName
 	errBoom := errors.New("boom")
 
+	tagged := func() *v1alpha3.Account {
+		spec := v1alpha3.NewStorageAccountSpec(&storage.Account{
+			AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			Location:          to.StringPtr("test-location"),
+		})
+		spec.Tags = map[string]string{"costcenter": "cool", "env": "prod"}
+		a := v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(spec).Account
+		a.Spec.IgnoreTagKeyCase = to.BoolPtr(true)
+		return a
+	}
+
 	type fields struct {
-		sb   syncbacker
-		ao   azurestorage.AccountOperations
-		kube client.Client
-		acct *v1alpha3.Account
+		sb      syncbacker
+		ao      azurestorage.AccountOperations
+		kube    client.Client
+		acct    *v1alpha3.Account
+		ignored azure.IgnoredTags
 	}
 	type want struct {
 		res  reconcile.Result
@@ -682,13 +1051,229 @@ func Test_bucketCreateUpdater_update(t *testing.T) {
 			},
 		},
 		{
-			name: "UpdateFailed",
+			name: "BlobServiceUpToDate",
 			attrs: &storage.Account{
 				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
-				Location:          to.StringPtr("test-location"),
 			},
 			fields: fields{
-				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).Account,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(newBlobServiceProperties()).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetBlobServiceProperties: func(_ context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+						p := azurestorage.NewBlobServiceProperties(newBlobServiceProperties())
+						return &p, nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(newBlobServiceProperties()).
+					WithBlobServiceStatus(&v1alpha3.BlobServiceStatus{IsVersioningEnabled: true, ChangeFeedEnabled: true}).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "InvalidBlobService",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(&v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true}}).
+					Account,
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(&v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true}}).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						azurestorage.ValidateBlobServiceProperties(&v1alpha3.BlobServiceProperties{RestorePolicy: &v1alpha3.RestorePolicy{Enabled: true}}))).
+					Account,
+			},
+		},
+		{
+			name: "SetBlobServiceFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(newBlobServiceProperties()).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetBlobServiceProperties: func(_ context.Context) (*mgmtstorage.BlobServiceProperties, error) {
+						return &mgmtstorage.BlobServiceProperties{}, nil
+					},
+					MockSetBlobServiceProperties: func(_ context.Context, _ mgmtstorage.BlobServiceProperties) (*mgmtstorage.BlobServiceProperties, error) {
+						return nil, errBoom
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecBlobServiceProperties(newBlobServiceProperties()).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						errors.Wrap(errBoom, "failed to set blob service properties"))).
+					Account,
+			},
+		},
+		{
+			name: "RoutingPreferenceUpdated",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.InternetRouting), PublishMicrosoftEndpoints: true}).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+						return &azurestorage.AccountExtensions{}, nil
+					},
+					MockSetRoutingPreference: func(_ context.Context, rp mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+						return &mgmtstorage.AccountProperties{
+							RoutingPreference: &rp,
+							PrimaryEndpoints: &mgmtstorage.Endpoints{
+								MicrosoftEndpoints: &mgmtstorage.AccountMicrosoftEndpoints{Blob: to.StringPtr("https://example-microsoftrouting.blob.core.windows.net/")},
+							},
+						}, nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.InternetRouting), PublishMicrosoftEndpoints: true}).
+					WithRoutingEndpoints(&v1alpha3.RoutingEndpoints{Blob: "https://example-microsoftrouting.blob.core.windows.net/"}, nil).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "ExtensionsUpToDate",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.InternetRouting)}).
+					WithSpecAllowSharedKeyAccess(false).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func() func(context.Context) (*azurestorage.AccountExtensions, error) {
+						called := false
+						return func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+							if called {
+								return nil, errors.New("extensions fetched more than once")
+							}
+							called = true
+							return &azurestorage.AccountExtensions{
+								Properties: mgmtstorage.AccountProperties{RoutingPreference: &mgmtstorage.RoutingPreference{RoutingChoice: mgmtstorage.InternetRouting}},
+								Access:     azurestorage.AccountAccess{AllowSharedKeyAccess: to.BoolPtr(false)},
+							}, nil
+						}
+					}(),
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{RoutingChoice: string(mgmtstorage.InternetRouting)}).
+					WithSpecAllowSharedKeyAccess(false).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Account,
+			},
+		},
+		{
+			name: "SetAccessFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecAllowSharedKeyAccess(false).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+						return &azurestorage.AccountExtensions{}, nil
+					},
+					MockSetAccess: func(_ context.Context, a azurestorage.AccountAccess) (*azurestorage.AccountAccess, error) {
+						if to.Bool(a.AllowSharedKeyAccess) {
+							return nil, errors.New("shared key access not disallowed")
+						}
+						return nil, errBoom
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecAllowSharedKeyAccess(false).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						errors.Wrap(errBoom, "failed to set account access"))).
+					Account,
+			},
+		},
+		{
+			name: "SetRoutingPreferenceFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{PublishInternetEndpoints: true}).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+						return &azurestorage.AccountExtensions{}, nil
+					},
+					MockSetRoutingPreference: func(_ context.Context, _ mgmtstorage.RoutingPreference) (*mgmtstorage.AccountProperties, error) {
+						return nil, errBoom
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).
+					WithSpecRoutingPreference(&v1alpha3.RoutingPreference{PublishInternetEndpoints: true}).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						errors.Wrap(errBoom, "failed to set routing preference"))).
+					Account,
+			},
+		},
+		{
+			name: "UpdateFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Location:          to.StringPtr("test-location"),
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithProperties()).Account,
 				ao: &azurestoragefake.MockAccountOperations{
 					MockUpdate: func(ctx context.Context, update storage.AccountUpdateParameters) (attrs *storage.Account, e error) {
 						return nil, errBoom
@@ -706,6 +1291,155 @@ func Test_bucketCreateUpdater_update(t *testing.T) {
 					Account,
 			},
 		},
+		{
+			name: "InvalidSkuChange",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Sku:               &storage.Sku{Name: storage.StandardLRS},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithSku(storage.StandardZRS)).Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockUpdate: func(ctx context.Context, update storage.AccountUpdateParameters) (attrs *storage.Account, e error) {
+						return nil, errBoom
+					},
+				},
+				kube: &test.MockClient{
+					MockStatusUpdate: func(ctx context.Context, obj runtime.Object, _ ...client.UpdateOption) error { return nil },
+				},
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithSku(storage.StandardZRS)).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						azurestorage.ValidateSkuChange(storage.StandardLRS, storage.StandardZRS))).
+					Account,
+			},
+		},
+		{
+			name: "InvalidKindChange",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Kind:              azurestorage.KindStorageV2,
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(storage.Storage)).Account,
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(storage.Storage)).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						azurestorage.ValidateKindChange(azurestorage.KindStorageV2, storage.Storage))).
+					Account,
+			},
+		},
+		{
+			name: "GetExtensionsFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+						return nil, errBoom
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						errors.Wrap(errBoom, "failed to get storage account extensions"))).
+					Account,
+			},
+		},
+		{
+			name: "ImmutableStorageChanged",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockGetExtensions: func(_ context.Context) (*azurestorage.AccountExtensions, error) {
+						return &azurestorage.AccountExtensions{ImmutableStorage: azurestorage.ImmutableStorageWithVersioning{Enabled: to.BoolPtr(false)}}, nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecImmutableStorageWithVersioning(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						azurestorage.ValidateImmutableStorageChange(&v1alpha3.ImmutableStorageWithVersioning{Enabled: true}, &azurestorage.ImmutableStorageWithVersioning{}))).
+					Account,
+			},
+		},
+		{
+			name: "UpgradeKindFailed",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Kind:              storage.Storage,
+			},
+			fields: fields{
+				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(azurestorage.KindStorageV2)).Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockUpgradeKind: func(_ context.Context, _ storage.Kind) error { return errBoom },
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: resultRequeue,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(azurestorage.KindStorageV2)).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileError(
+						errors.Wrap(errBoom, "failed to upgrade storage account kind"))).
+					Account,
+			},
+		},
+		{
+			name: "UpgradeKindSuccess",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Kind:              storage.BlobStorage,
+			},
+			fields: fields{
+				sb: &MockAccountSyncbacker{
+					MockSyncback: func(ctx context.Context, a *storage.Account) (result reconcile.Result, e error) {
+						if a.Kind != azurestorage.KindStorageV2 {
+							return resultRequeue, errBoom
+						}
+						return requeueOnSuccess, nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(azurestorage.KindStorageV2)).Account,
+				ao: &azurestoragefake.MockAccountOperations{
+					MockUpgradeKind: func(_ context.Context, _ storage.Kind) error { return nil },
+					MockUpdate: func(ctx context.Context, update storage.AccountUpdateParameters) (attrs *storage.Account, e error) {
+						return &storage.Account{Kind: azurestorage.KindStorageV2}, nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: v1alpha3test.NewMockAccount(name).
+					WithSpecStorageAccountSpec(newStoragAccountSpecWithKind(azurestorage.KindStorageV2)).
+					WithStatusConditions(runtimev1alpha1.Available()).
+					Account,
+			},
+		},
 		{
 			name: "UpdateSuccess",
 			attrs: &storage.Account{
@@ -734,6 +1468,95 @@ func Test_bucketCreateUpdater_update(t *testing.T) {
 					Account,
 			},
 		},
+		{
+			name: "TagKeyCaseIgnored",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Location:          to.StringPtr("test-location"),
+				Tags:              map[string]*string{"CostCenter": to.StringPtr("cool"), "Env": to.StringPtr("prod")},
+			},
+			fields: fields{
+				acct: tagged(),
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: func() *v1alpha3.Account {
+					a := tagged()
+					a.Status.SetConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess())
+					return a
+				}(),
+			},
+		},
+		{
+			name: "UpdatePreservesTagKeyCase",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Location:          to.StringPtr("test-location"),
+				Tags:              map[string]*string{"CostCenter": to.StringPtr("old")},
+			},
+			fields: fields{
+				sb: &MockAccountSyncbacker{
+					MockSyncback: func(ctx context.Context, a *storage.Account) (result reconcile.Result, e error) {
+						return requeueOnSuccess, nil
+					},
+				},
+				acct: tagged(),
+				ao: &azurestoragefake.MockAccountOperations{
+					MockUpdate: func(ctx context.Context, update storage.AccountUpdateParameters) (attrs *storage.Account, e error) {
+						want := map[string]string{"CostCenter": "cool", "env": "prod"}
+						if diff := cmp.Diff(want, to.StringMap(update.Tags)); diff != "" {
+							return nil, errors.Errorf("tags: -want, +got:\n%s", diff)
+						}
+						return &storage.Account{}, nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: func() *v1alpha3.Account {
+					a := tagged()
+					a.Status.SetConditions(runtimev1alpha1.Available())
+					return a
+				}(),
+			},
+		},
+		{
+			name: "UpdatePreservesIgnoredTags",
+			attrs: &storage.Account{
+				AccountProperties: &storage.AccountProperties{ProvisioningState: storage.Succeeded},
+				Location:          to.StringPtr("test-location"),
+				Tags:              map[string]*string{"CostCenter": to.StringPtr("old"), "hidden-link:/cool": to.StringPtr("Resource")},
+			},
+			fields: fields{
+				sb: &MockAccountSyncbacker{
+					MockSyncback: func(ctx context.Context, a *storage.Account) (result reconcile.Result, e error) {
+						return requeueOnSuccess, nil
+					},
+				},
+				acct: tagged(),
+				ao: &azurestoragefake.MockAccountOperations{
+					MockUpdate: func(ctx context.Context, update storage.AccountUpdateParameters) (attrs *storage.Account, e error) {
+						want := map[string]string{"CostCenter": "cool", "env": "prod", "hidden-link:/cool": "Resource"}
+						if diff := cmp.Diff(want, to.StringMap(update.Tags)); diff != "" {
+							return nil, errors.Errorf("tags: -want, +got:\n%s", diff)
+						}
+						return &storage.Account{}, nil
+					},
+				},
+				kube:    test.NewMockClient(),
+				ignored: azure.DefaultIgnoredTagPrefixes,
+			},
+			want: want{
+				res: requeueOnSuccess,
+				acct: func() *v1alpha3.Account {
+					a := tagged()
+					a.Status.SetConditions(runtimev1alpha1.Available())
+					return a
+				}(),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -742,6 +1565,7 @@ func Test_bucketCreateUpdater_update(t *testing.T) {
 				AccountOperations: tt.fields.ao,
 				kube:              tt.fields.kube,
 				acct:              tt.fields.acct,
+				ignored:           tt.fields.ignored,
 			}
 			got, err := bh.update(ctx, tt.attrs)
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
@@ -977,6 +1801,32 @@ func Test_accountSecretUpdater_updatesecret(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SharedKeyAccessDisabled",
+			fields: fields{
+				ops: &azurestoragefake.MockAccountOperations{
+					MockListKeys: func(ctx context.Context) (keys []storage.AccountKey, e error) {
+						return nil, errors.New("keys should not be listed")
+					},
+				},
+				kube: &test.MockClient{
+					MockCreate: func(ctx context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+						if _, ok := obj.(*corev1.Secret).Data[runtimev1alpha1.ResourceCredentialsSecretPasswordKey]; ok {
+							return errors.New("account key should not be published")
+						}
+						return nil
+					},
+				},
+				acct: v1alpha3test.NewMockAccount(name).WithSpecWriteConnectionSecretToReference(ns, csName).WithSpecAllowSharedKeyAccess(false).Account,
+			},
+			acct: &storage.Account{
+				AccountProperties: &storage.AccountProperties{
+					PrimaryEndpoints: &storage.Endpoints{
+						Blob: to.StringPtr("test-blob-endpoint"),
+					},
+				},
+			},
+		},
 		{
 			name: "CreateNewSecretFailed",
 			fields: fields{
@@ -1058,3 +1908,107 @@ func Test_accountSecretUpdater_updatesecret(t *testing.T) {
 		})
 	}
 }
+
+func Test_isUpToDate(t *testing.T) {
+	tests := map[string]struct {
+		desired          *v1alpha3.StorageAccountSpec
+		observed         *v1alpha3.StorageAccountSpec
+		ignoreTagKeyCase bool
+		want             bool
+	}{
+		"Nil": {
+			want: true,
+		},
+		"DesiredNil": {
+			observed: &v1alpha3.StorageAccountSpec{Location: "westus"},
+			want:     false,
+		},
+		"UpToDate": {
+			desired:  &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "bar"}},
+			observed: &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "bar"}},
+			want:     true,
+		},
+		"IgnoredTagInjected": {
+			desired:  &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "bar"}},
+			observed: &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "bar", "hidden-link:/x": "Resource"}},
+			want:     true,
+		},
+		"TagsDrifted": {
+			desired:  &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "bar"}},
+			observed: &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"foo": "baz"}},
+			want:     false,
+		},
+		"TagKeyCaseDrifted": {
+			desired:  &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"costcenter": "bar"}},
+			observed: &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"CostCenter": "bar"}},
+			want:     false,
+		},
+		"TagKeyCaseIgnored": {
+			desired:          &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"costcenter": "bar"}},
+			observed:         &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"CostCenter": "bar"}},
+			ignoreTagKeyCase: true,
+			want:             true,
+		},
+		"TagValueCaseNotIgnored": {
+			desired:          &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"costcenter": "bar"}},
+			observed:         &v1alpha3.StorageAccountSpec{Location: "westus", Tags: map[string]string{"CostCenter": "Bar"}},
+			ignoreTagKeyCase: true,
+			want:             false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := isUpToDate(tc.desired, tc.observed, azure.DefaultIgnoredTagPrefixes, tc.ignoreTagKeyCase)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("isUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClassifyingAccountOperations(t *testing.T) {
+	errThrottled := autorest.DetailedError{StatusCode: http.StatusTooManyRequests}
+	errForbidden := autorest.DetailedError{StatusCode: http.StatusForbidden}
+
+	cases := map[string]struct {
+		err       error
+		backoff   bool
+		forbidden bool
+	}{
+		"Succeeded": {},
+		"Throttled": {
+			err:     errThrottled,
+			backoff: true,
+		},
+		"Forbidden": {
+			err:       errForbidden,
+			forbidden: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			acct := v1alpha3test.NewMockAccount(testAccountName).Account
+			th := azure.NewThrottle()
+			ao := &classifyingAccountOperations{
+				AccountOperations: &azurestoragefake.MockAccountOperations{
+					MockGet: func(context.Context) (*storage.Account, error) { return nil, tc.err },
+				},
+				acct:     acct,
+				throttle: th,
+			}
+
+			_, err := ao.Get(context.Background())
+			if diff := cmp.Diff(tc.err != nil, err != nil); diff != "" {
+				t.Errorf("Get(...): -want error, +got error:\n%s", diff)
+			}
+			if _, backoff := th.Backoff(acct); backoff != tc.backoff {
+				t.Errorf("Get(...): want backoff %t, got %t", tc.backoff, backoff)
+			}
+			forbidden := acct.GetCondition(runtimev1alpha1.TypeReady).Reason == azure.ReasonForbidden
+			if forbidden != tc.forbidden {
+				t.Errorf("Get(...): want forbidden %t, got %t", tc.forbidden, forbidden)
+			}
+		})
+	}
+}
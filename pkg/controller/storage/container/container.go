@@ -18,9 +18,11 @@ package container
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"time"
 
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/azure-storage-blob-go/azblob"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
@@ -32,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -41,6 +44,9 @@ import (
 
 	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
 	"github.com/crossplane/provider-azure/pkg/clients/storage"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	storagectrl "github.com/crossplane/provider-azure/pkg/controller/storage"
+	"github.com/crossplane/provider-azure/pkg/controller/stuck"
 )
 
 const (
@@ -53,7 +59,24 @@ const (
 
 // Error strings
 const (
-	errAcctSecretNil = "account does not have a connection secret"
+	errAcctSecretNil       = "account does not have a connection secret"
+	errFmtNoSharedKey      = "storage account %s disallows shared key access, which containers require; set allowSharedKeyAccess to true on the account"
+	errFmtNoAccessKey      = "connection secret %s of the storage account does not contain an access key"
+	errGetAuthInfo         = "cannot get auth information"
+	errGetOperationTimeout = "cannot get operation timeout"
+	errGetLegalHold        = "cannot get legal hold tags"
+	errSetLegalHold        = "cannot set legal hold tags"
+	errClearLegalHold      = "cannot clear legal hold tags"
+	errGetPolicies         = "cannot get stored access policies"
+	errCheckEmpty          = "cannot determine whether container contains blobs"
+	errNotEmpty            = "refusing to delete container that contains blobs; set forceDelete to delete it and its blobs"
+
+	errObserveOnlyCreate = "cannot create container: the provider is running in observe-only mode"
+)
+
+// Event reasons.
+const (
+	reasonDriftCorrected event.Reason = "DriftCorrected"
 )
 
 var (
@@ -64,6 +87,7 @@ var (
 // Reconciler reconciles an Azure storage container
 type Reconciler struct {
 	client.Client
+	reader client.Reader
 	syncdeleterMaker
 	managed.ReferenceResolver
 	managed.Initializer
@@ -71,21 +95,25 @@ type Reconciler struct {
 	log logging.Logger
 }
 
-// Setup adds a controller that reconciles Containers.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup adds a controller that reconciles Containers. Containers are only
+// observed if observeOnly is true.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
 	name := managed.ControllerName(v1alpha3.ContainerGroupKind)
 
+	throttled := azure.NewThrottle()
 	r := &Reconciler{
 		Client:           mgr.GetClient(),
-		syncdeleterMaker: &containerSyncdeleterMaker{mgr.GetClient()},
+		reader:           mgr.GetAPIReader(),
+		syncdeleterMaker: &containerSyncdeleterMaker{Client: mgr.GetClient(), reader: mgr.GetAPIReader(), record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)), observeOnly: observeOnly, throttle: throttled},
 		Initializer:      managed.NewNameAsExternalName(mgr.GetClient()),
 		log:              l.WithValues("controller", name),
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
 		For(&v1alpha3.Container{}).
-		Complete(r)
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.ContainerGroupVersionKind), r, throttled, o, chain.WithPause(), chain.WithFinalizer(finalizer), chain.WithFailedFn(stuck.RequeueFailed)))
 }
 
 // Reconcile reads that state of the cluster for a Provider acct and makes changes based on the state read
@@ -106,8 +134,8 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 
 	sd, err := r.newSyncdeleter(ctx, c)
 	if err != nil {
-		c.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, r.Status().Update(ctx, c)
+		c.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, r, r.reader, c)
 	}
 
 	// Check for deletion
@@ -124,6 +152,10 @@ type syncdeleterMaker interface {
 
 type containerSyncdeleterMaker struct {
 	client.Client
+	reader      client.Reader
+	record      event.Recorder
+	observeOnly bool
+	throttle    *azure.Throttle
 }
 
 func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alpha3.Container) (syncdeleter, error) { // nolint:gocyclo
@@ -143,7 +175,7 @@ func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alp
 		// For storage account not found errors - check if we are on deletion path
 		// if so - remove finalizer from this container object
 		if kerrors.IsNotFound(err) && c.DeletionTimestamp != nil {
-			meta.RemoveFinalizer(c, finalizer)
+			storagectrl.RemoveFinalizer(c, finalizer)
 			if err := m.Client.Update(ctx, c); err != nil {
 				return nil, errors.Wrapf(err, "failed to update after removing finalizer")
 			}
@@ -155,6 +187,12 @@ func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alp
 		return nil, errors.New(errAcctSecretNil)
 	}
 
+	// Containers are managed using the account's access key, which is not
+	// published when the account disallows shared key access.
+	if storage.IsSharedKeyAccessDisabled(acct.Spec.AccountParameters) {
+		return nil, errors.Errorf(errFmtNoSharedKey, acct.GetName())
+	}
+
 	// Retrieve storage account secret
 	s := &corev1.Secret{}
 	n := types.NamespacedName{
@@ -168,11 +206,47 @@ func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alp
 	accountName := string(s.Data[runtimev1alpha1.ResourceCredentialsSecretUserKey])
 	accountPassword := string(s.Data[runtimev1alpha1.ResourceCredentialsSecretPasswordKey])
 	containerName := meta.GetExternalName(c)
+	if accountPassword == "" {
+		return nil, errors.Errorf(errFmtNoAccessKey, n)
+	}
 
-	ch, err := storage.NewContainerHandle(accountName, accountPassword, containerName)
+	h, err := storage.NewContainerHandle(accountName, accountPassword, containerName)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create client handle: %s, storage account: %s", containerName, accountName)
 	}
+	ch := &classifyingContainerOperations{ContainerOperations: h, container: c, throttle: m.throttle}
+
+	// Containers are bounded by the operation timeout of their storage
+	// account's ProviderConfig, which is read once and shared with any
+	// subsequent read of the account's auth information.
+	ctx, err = azure.WithProviderConfig(ctx, m.Client, acct)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetOperationTimeout)
+	}
+	t, err := azure.GetOperationTimeout(ctx, m.Client, acct)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetOperationTimeout)
+	}
+
+	// Legal holds can only be managed via the Azure Resource Manager API,
+	// which requires the storage account's Azure credentials. We only use it
+	// if legal holds are (or were) desired, so that containers that don't use
+	// them needn't make an extra request each reconcile. Legal holds are thus
+	// no longer managed once they have been cleared.
+	var lh storage.LegalHoldOperations
+	if len(c.Spec.LegalHoldTags) > 0 || len(c.Status.LegalHoldTags) > 0 {
+		creds, auth, err := azure.GetAuthInfo(ctx, m.Client, acct)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetAuthInfo)
+		}
+		cl := mgmtstorage.NewBlobContainersClient(creds[azure.CredentialsKeySubscriptionID])
+		cl.Authorizer = auth
+		lh = &classifyingLegalHoldOperations{
+			LegalHoldOperations: storage.NewLegalHoldHandle(cl, acct.Spec.ResourceGroupName, meta.GetExternalName(acct), containerName),
+			container:           c,
+			throttle:            m.throttle,
+		}
+	}
 
 	// set owner reference on the container to storage account, thus
 	// if the account is delete - container is garbage collected as well
@@ -180,16 +254,83 @@ func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alp
 	or.BlockOwnerDeletion = to.BoolPtr(true)
 	meta.AddOwnerReference(c, or)
 
-	return &containerSyncdeleter{
+	var sd syncdeleter = &containerSyncdeleter{
 		createupdater: &containerCreateUpdater{
 			ContainerOperations: ch,
+			legalHold:           lh,
 			kube:                m.Client,
+			reader:              m.reader,
 			container:           c,
+			record:              m.record,
 		},
 		ContainerOperations: ch,
 		kube:                m.Client,
+		reader:              m.reader,
 		container:           c,
-	}, nil
+		observeOnly:         m.observeOnly,
+	}
+	if t > 0 {
+		sd = &timeoutSyncdeleter{syncdeleter: sd, timeout: t}
+	}
+	return sd, nil
+}
+
+// classifyingContainerOperations classify the errors returned by the container
+// operations they wrap using a Throttle, so that the reconciler backs off from
+// containers whose requests Azure throttles and reports containers that our
+// credentials are not authorized to access.
+type classifyingContainerOperations struct {
+	storage.ContainerOperations
+	container *v1alpha3.Container
+	throttle  *azure.Throttle
+}
+
+func (o *classifyingContainerOperations) Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error {
+	return o.throttle.Classify(o.container, o.ContainerOperations.Create(ctx, publicAccessType, metadata, identifiers))
+}
+
+func (o *classifyingContainerOperations) Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata, identifiers []azblob.SignedIdentifier) error {
+	return o.throttle.Classify(o.container, o.ContainerOperations.Update(ctx, publicAccessType, metadata, identifiers))
+}
+
+func (o *classifyingContainerOperations) Get(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
+	access, md, err := o.ContainerOperations.Get(ctx)
+	return access, md, o.throttle.Classify(o.container, err)
+}
+
+func (o *classifyingContainerOperations) GetAccessPolicy(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+	ids, err := o.ContainerOperations.GetAccessPolicy(ctx)
+	return ids, o.throttle.Classify(o.container, err)
+}
+
+func (o *classifyingContainerOperations) Delete(ctx context.Context) error {
+	return o.throttle.Classify(o.container, o.ContainerOperations.Delete(ctx))
+}
+
+func (o *classifyingContainerOperations) IsEmpty(ctx context.Context) (bool, error) {
+	empty, err := o.ContainerOperations.IsEmpty(ctx)
+	return empty, o.throttle.Classify(o.container, err)
+}
+
+// classifyingLegalHoldOperations classify the errors returned by the legal
+// hold operations they wrap using a Throttle.
+type classifyingLegalHoldOperations struct {
+	storage.LegalHoldOperations
+	container *v1alpha3.Container
+	throttle  *azure.Throttle
+}
+
+func (o *classifyingLegalHoldOperations) GetLegalHoldTags(ctx context.Context) ([]string, error) {
+	tags, err := o.LegalHoldOperations.GetLegalHoldTags(ctx)
+	return tags, o.throttle.Classify(o.container, err)
+}
+
+func (o *classifyingLegalHoldOperations) SetLegalHold(ctx context.Context, tags []string) error {
+	return o.throttle.Classify(o.container, o.LegalHoldOperations.SetLegalHold(ctx, tags))
+}
+
+func (o *classifyingLegalHoldOperations) ClearLegalHold(ctx context.Context, tags []string) error {
+	return o.throttle.Classify(o.container, o.LegalHoldOperations.ClearLegalHold(ctx, tags))
 }
 
 type deleter interface {
@@ -213,40 +354,96 @@ type syncdeleter interface {
 	syncer
 }
 
+// A timeoutSyncdeleter bounds each sync and delete of a container by the
+// operation timeout of its storage account's ProviderConfig (or Provider).
+type timeoutSyncdeleter struct {
+	syncdeleter
+	timeout time.Duration
+}
+
+func (s *timeoutSyncdeleter) sync(ctx context.Context) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.syncdeleter.sync(ctx)
+}
+
+func (s *timeoutSyncdeleter) delete(ctx context.Context) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.syncdeleter.delete(ctx)
+}
+
 type containerSyncdeleter struct {
 	createupdater
 	storage.ContainerOperations
 	kube      client.Client
+	reader    client.Reader
 	container *v1alpha3.Container
+
+	// observeOnly containers are never created, updated, or deleted.
+	observeOnly bool
 }
 
 func (csd *containerSyncdeleter) delete(ctx context.Context) (reconcile.Result, error) {
 	csd.container.Status.SetConditions(runtimev1alpha1.Deleting())
-	if csd.container.Spec.DeletionPolicy == runtimev1alpha1.DeletionDelete {
+	// We never delete containers in observe-only mode.
+	if csd.container.Spec.DeletionPolicy == runtimev1alpha1.DeletionDelete && !csd.observeOnly {
+		if err := csd.ensureDeletable(ctx); err != nil {
+			csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, csd.kube, csd.reader, csd.container)
+		}
 		if err := csd.Delete(ctx); err != nil && !azure.IsNotFound(err) {
-			csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-			return resultRequeue, csd.kube.Status().Update(ctx, csd.container)
+			csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, csd.kube, csd.reader, csd.container)
 		}
 	}
 
 	// NOTE(negz): We don't update the conditioned status here because assuming
 	// no other finalizers need to be cleaned up the object should cease to
 	// exist after we update it.
-	meta.RemoveFinalizer(csd.container, finalizer)
+	storagectrl.RemoveFinalizer(csd.container, finalizer)
 	return reconcile.Result{}, csd.kube.Update(ctx, csd.container)
 }
 
+// ensureDeletable returns an error if the container contains blobs and is not
+// to be force deleted. Azure deletes a container's blobs along with it.
+func (csd *containerSyncdeleter) ensureDeletable(ctx context.Context) error {
+	if csd.container.Spec.ForceDelete {
+		return nil
+	}
+	empty, err := csd.IsEmpty(ctx)
+	if storage.IsNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errCheckEmpty)
+	}
+	if !empty {
+		return errors.New(errNotEmpty)
+	}
+	return nil
+}
+
 func (csd *containerSyncdeleter) sync(ctx context.Context) (reconcile.Result, error) {
 	access, meta, err := csd.Get(ctx)
 	if err != nil && !storage.IsNotFoundError(err) {
-		csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, csd.kube.Status().Update(ctx, csd.container)
+		csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, csd.kube, csd.reader, csd.container)
 	}
 
 	if access == nil {
+		if csd.observeOnly {
+			csd.container.Status.SetConditions(runtimev1alpha1.ReconcileError(errors.New(errObserveOnlyCreate)))
+			return resultRequeue, azure.UpdateStatus(ctx, csd.kube, csd.reader, csd.container)
+		}
 		return csd.create(ctx)
 	}
 
+	if csd.observeOnly {
+		csd.container.Status.SetConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess())
+		return requeueOnSuccess, azure.UpdateStatus(ctx, csd.kube, csd.reader, csd.container)
+	}
+
 	return csd.update(ctx, access, meta)
 }
 
@@ -258,8 +455,11 @@ type createupdater interface {
 // containerCreateUpdater implementation of createupdater interface
 type containerCreateUpdater struct {
 	storage.ContainerOperations
+	legalHold storage.LegalHoldOperations
 	kube      client.Client
+	reader    client.Reader
 	container *v1alpha3.Container
+	record    event.Recorder
 }
 
 var _ createupdater = &containerCreateUpdater{}
@@ -274,26 +474,108 @@ func (ccu *containerCreateUpdater) create(ctx context.Context) (reconcile.Result
 	}
 
 	spec := container.Spec
-	if err := ccu.Create(ctx, spec.PublicAccessType, spec.Metadata); err != nil {
-		container.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-		return resultRequeue, ccu.kube.Status().Update(ctx, container)
+	if err := ccu.Create(ctx, spec.PublicAccessType, spec.Metadata, storage.NewSignedIdentifiers(spec.StoredAccessPolicies)); err != nil {
+		container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, container)
 	}
 
 	container.Status.SetConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess())
-	return reconcile.Result{}, ccu.kube.Status().Update(ctx, ccu.container)
+	return reconcile.Result{}, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, ccu.container)
 }
 
 func (ccu *containerCreateUpdater) update(ctx context.Context, accessType *azblob.PublicAccessType, meta azblob.Metadata) (reconcile.Result, error) {
 	container := ccu.container
 	spec := container.Spec
 
-	if !reflect.DeepEqual(*accessType, spec.PublicAccessType) || !reflect.DeepEqual(meta, spec.Metadata) {
-		if err := ccu.Update(ctx, spec.PublicAccessType, spec.Metadata); err != nil {
-			container.Status.SetConditions(runtimev1alpha1.ReconcileError(err))
-			return resultRequeue, ccu.kube.Status().Update(ctx, container)
+	// Stored access policies are only managed when they are specified.
+	var desired []azblob.SignedIdentifier
+	policiesDrifted := false
+	if spec.StoredAccessPolicies != nil {
+		ids, err := ccu.GetAccessPolicy(ctx)
+		if err != nil {
+			container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(errors.Wrap(err, errGetPolicies))))
+			return resultRequeue, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, container)
+		}
+		desired = storage.NewSignedIdentifiers(spec.StoredAccessPolicies)
+		policiesDrifted = storage.SignedIdentifiersNeedUpdate(desired, ids)
+	}
+
+	if !reflect.DeepEqual(*accessType, spec.PublicAccessType) || !reflect.DeepEqual(meta, spec.Metadata) || policiesDrifted {
+		if spec.StoredAccessPolicies == nil {
+			// Setting the public access type replaces all stored access
+			// policies, so we pass through those that exist in Azure.
+			ids, err := ccu.GetAccessPolicy(ctx)
+			if err != nil {
+				container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(errors.Wrap(err, errGetPolicies))))
+				return resultRequeue, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, container)
+			}
+			desired = ids
+		}
+		if err := ccu.Update(ctx, spec.PublicAccessType, spec.Metadata, desired); err != nil {
+			container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+			return resultRequeue, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, container)
+		}
+		ccu.recordDrift(*accessType, meta)
+		if policiesDrifted && ccu.record != nil {
+			ccu.record.Event(container, event.Normal(reasonDriftCorrected, "Updated stored access policies"))
 		}
 	}
 
+	if err := ccu.updateLegalHold(ctx); err != nil {
+		container.Status.SetConditions(runtimev1alpha1.ReconcileError(azure.WithRequestIDs(err)))
+		return resultRequeue, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, container)
+	}
+
 	container.Status.SetConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess())
-	return requeueOnSuccess, ccu.kube.Status().Update(ctx, ccu.container)
+	return requeueOnSuccess, azure.UpdateStatus(ctx, ccu.kube, ccu.reader, ccu.container)
+}
+
+// recordDrift records an event for the supplied public access type and
+// metadata if they differed from those of the container's spec before it was
+// updated to match.
+func (ccu *containerCreateUpdater) recordDrift(accessType azblob.PublicAccessType, meta azblob.Metadata) {
+	if ccu.record == nil {
+		return
+	}
+	spec := ccu.container.Spec
+	if accessType != spec.PublicAccessType {
+		ccu.record.Event(ccu.container, event.Normal(reasonDriftCorrected, fmt.Sprintf("Updated public access type from %s to %s", publicAccess(accessType), publicAccess(spec.PublicAccessType))))
+	}
+	if !reflect.DeepEqual(meta, spec.Metadata) {
+		ccu.record.Event(ccu.container, event.Normal(reasonDriftCorrected, fmt.Sprintf("Updated metadata from %v to %v", map[string]string(meta), map[string]string(spec.Metadata))))
+	}
+}
+
+// publicAccess returns a description of the supplied public access type.
+// Azure represents private containers with an empty public access type.
+func publicAccess(t azblob.PublicAccessType) string {
+	if t == azblob.PublicAccessNone {
+		return "private"
+	}
+	return string(t)
+}
+
+// updateLegalHold sets and clears legal hold tags such that the container's
+// legal hold tags match those of its spec.
+func (ccu *containerCreateUpdater) updateLegalHold(ctx context.Context) error {
+	if ccu.legalHold == nil {
+		return nil
+	}
+	observed, err := ccu.legalHold.GetLegalHoldTags(ctx)
+	if err != nil {
+		return errors.Wrap(err, errGetLegalHold)
+	}
+	add, remove := storage.LegalHoldTagsToUpdate(ccu.container.Spec.LegalHoldTags, observed)
+	if len(add) > 0 {
+		if err := ccu.legalHold.SetLegalHold(ctx, add); err != nil {
+			return errors.Wrap(err, errSetLegalHold)
+		}
+	}
+	if len(remove) > 0 {
+		if err := ccu.legalHold.ClearLegalHold(ctx, remove); err != nil {
+			return errors.Wrap(err, errClearLegalHold)
+		}
+	}
+	ccu.container.Status.LegalHoldTags = storage.NormalizeLegalHoldTags(ccu.container.Spec.LegalHoldTags)
+	return nil
 }
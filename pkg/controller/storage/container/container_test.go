@@ -42,12 +42,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
 	v1alpha3test "github.com/crossplane/provider-azure/apis/storage/v1alpha3/test"
+	apisv1alpha3 "github.com/crossplane/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
 	"github.com/crossplane/provider-azure/pkg/clients/storage"
 	azurestoragefake "github.com/crossplane/provider-azure/pkg/clients/storage/fake"
 )
@@ -290,6 +293,7 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 	}
 	ctx := context.TODO()
 	testAccountKey := "dGVzdC1rZXkK"
+	testProviderName := "test-provider"
 
 	ch, err := storage.NewContainerHandle(testAccountName, testAccountKey, testContainerName)
 	if err != nil {
@@ -304,9 +308,10 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 		c   *v1alpha3.Container
 	}
 	type want struct {
-		err    error
-		syndel syncdeleter
-		cont   *v1alpha3.Container
+		err     error
+		syndel  syncdeleter
+		timeout time.Duration
+		cont    *v1alpha3.Container
 	}
 	tests := []struct {
 		name   string
@@ -385,6 +390,52 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 				err: errors.New(errAcctSecretNil),
 			},
 		},
+		{
+			name: "AccountDisallowsSharedKeyAccess",
+			fields: fields{
+				Client: fake.NewFakeClient(
+					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
+					newSecret(testNamespace, testAccountName, map[string][]byte{
+						runtimev1alpha1.ResourceCredentialsSecretUserKey: []byte(testAccountName),
+					}),
+					func() *v1alpha3.Account {
+						a := v1alpha3test.NewMockAccount(testAccountName).
+							WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
+							Account
+						a.Spec.AccountParameters.AllowSharedKeyAccess = azure.ToBoolPtr(false, azure.FieldRequired)
+						return a
+					}()),
+			},
+			args: args{
+				ctx: ctx,
+				c: newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).
+					Container,
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoSharedKey, testAccountName),
+			},
+		},
+		{
+			name: "AccountSecretHasNoAccessKey",
+			fields: fields{
+				Client: fake.NewFakeClient(
+					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
+					newSecret(testNamespace, testAccountName, map[string][]byte{
+						runtimev1alpha1.ResourceCredentialsSecretUserKey: []byte(testAccountName),
+					}),
+					v1alpha3test.NewMockAccount(testAccountName).
+						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
+						Account),
+			},
+			args: args{
+				ctx: ctx,
+				c: newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).
+					Container,
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoAccessKey, types.NamespacedName{Namespace: testNamespace, Name: testAccountName}),
+			},
+		},
 		{
 			name: "FailedToCreateContainerHandle",
 			fields: fields{
@@ -394,7 +445,9 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(testAccountName),
 						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-key"),
 					}),
+					&apisv1alpha3.Provider{ObjectMeta: metav1.ObjectMeta{Name: testProviderName}},
 					v1alpha3test.NewMockAccount(testAccountName).
+						WithSpecProvider(testProviderName).
 						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
 						Account),
 			},
@@ -418,7 +471,9 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(testAccountName),
 						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("dGVzdC1rZXkK"),
 					}),
+					&apisv1alpha3.Provider{ObjectMeta: metav1.ObjectMeta{Name: testProviderName}},
 					v1alpha3test.NewMockAccount(testAccountName).
+						WithSpecProvider(testProviderName).
 						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
 						Account),
 			},
@@ -432,6 +487,35 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 				syndel: &containerSyncdeleter{},
 			},
 		},
+		{
+			name: "SuccessWithOperationTimeout",
+			fields: fields{
+				Client: fake.NewFakeClient(
+					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
+					newSecret(testNamespace, testAccountName, map[string][]byte{
+						runtimev1alpha1.ResourceCredentialsSecretUserKey:     []byte(testAccountName),
+						runtimev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("dGVzdC1rZXkK"),
+					}),
+					&apisv1alpha3.Provider{
+						ObjectMeta: metav1.ObjectMeta{Name: testProviderName},
+						Spec:       apisv1alpha3.ProviderSpec{OperationTimeout: &metav1.Duration{Duration: time.Minute}},
+					},
+					v1alpha3test.NewMockAccount(testAccountName).
+						WithSpecProvider(testProviderName).
+						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
+						Account),
+			},
+			args: args{
+				ctx: ctx,
+				c: newCont().WithSpecProviderRef(testAccountName).
+					WithFinalizer(finalizer).
+					Container,
+			},
+			want: want{
+				syndel:  &containerSyncdeleter{},
+				timeout: time.Minute,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -443,22 +527,28 @@ func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
 				t.Errorf("containerSyncdeleterMaker.newSyncdeleter(): -got error, +want error: \n%s", diff)
 			}
 			if tt.want.syndel != nil {
+				co := &classifyingContainerOperations{ContainerOperations: ch, container: tt.args.c}
 				tt.want.syndel = &containerSyncdeleter{
 					createupdater: &containerCreateUpdater{
-						ContainerOperations: ch,
+						ContainerOperations: co,
 						kube:                tt.fields.Client,
 						container:           tt.args.c,
 					},
-					ContainerOperations: ch,
+					ContainerOperations: co,
 					kube:                tt.fields.Client,
 					container:           tt.args.c,
 				}
+				if tt.want.timeout > 0 {
+					tt.want.syndel = &timeoutSyncdeleter{syncdeleter: tt.want.syndel, timeout: tt.want.timeout}
+				}
 				// BUG(negz): This test is broken. It appears to intend to compare
 				// unexported fields, but does not. This behaviour was maintained
 				// when porting the test from https://github.com/go-test/deep to cmp.
 				if diff := cmp.Diff(tt.want.syndel, got,
+					cmp.AllowUnexported(timeoutSyncdeleter{}, classifyingContainerOperations{}),
 					cmpopts.IgnoreUnexported(containerSyncdeleter{}),
 					cmpopts.IgnoreUnexported(azblob.ContainerURL{}),
+					cmpopts.IgnoreUnexported(storage.ContainerHandle{}),
 				); diff != "" {
 					t.Errorf("containerSyncdeleterMaker.newSyncdeleter(): -want, +got:\n%s", diff)
 				}
@@ -495,10 +585,11 @@ func Test_containerSyncdeleter_delete(t *testing.T) {
 		cont *v1alpha3.Container
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
+		name        string
+		observeOnly bool
+		fields      fields
+		args        args
+		want        want
 	}{
 		{
 			name: "DeletionOrphan",
@@ -518,11 +609,30 @@ func Test_containerSyncdeleter_delete(t *testing.T) {
 					Container,
 			},
 		},
+		{
+			name: "DeletionOrphanSeveralFinalizers",
+			fields: fields{
+				kube: test.NewMockClient(),
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionOrphan).
+					WithFinalizers([]string{"cool", finalizer, finalizer, "other"}).Container,
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: reconcile.Result{},
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionOrphan).
+					WithFinalizers([]string{"cool", "other"}).
+					WithStatusConditions(runtimev1alpha1.Deleting()).
+					Container,
+			},
+		},
 		{
 			name: "DeleteErrorNotFound",
 			fields: fields{
 				kube: test.NewMockClient(),
 				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockIsEmpty: func(ctx context.Context) (bool, error) { return true, nil },
 					MockDelete: func(ctx context.Context) error {
 						return autorest.DetailedError{StatusCode: http.StatusNotFound}
 					},
@@ -547,6 +657,7 @@ func Test_containerSyncdeleter_delete(t *testing.T) {
 			fields: fields{
 				kube: test.NewMockClient(),
 				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockIsEmpty: func(ctx context.Context) (bool, error) { return true, nil },
 					MockDelete: func(ctx context.Context) error {
 						return errBoom
 					},
@@ -565,6 +676,91 @@ func Test_containerSyncdeleter_delete(t *testing.T) {
 					Container,
 			},
 		},
+		{
+			name: "NotEmpty",
+			fields: fields{
+				kube: test.NewMockClient(),
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockIsEmpty: func(ctx context.Context) (bool, error) { return false, nil },
+				},
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Container,
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: resultRequeue,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).
+					WithStatusConditions(runtimev1alpha1.Deleting(), runtimev1alpha1.ReconcileError(errors.New(errNotEmpty))).
+					Container,
+			},
+		},
+		{
+			name: "IsEmptyError",
+			fields: fields{
+				kube: test.NewMockClient(),
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockIsEmpty: func(ctx context.Context) (bool, error) { return false, errBoom },
+				},
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Container,
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: resultRequeue,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).
+					WithStatusConditions(runtimev1alpha1.Deleting(), runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errCheckEmpty))).
+					Container,
+			},
+		},
+		{
+			name: "ForceDeleteNotEmpty",
+			fields: fields{
+				kube: test.NewMockClient(),
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockIsEmpty: func(ctx context.Context) (bool, error) { return false, nil },
+					MockDelete:  func(ctx context.Context) error { return nil },
+				},
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithSpecForceDelete(true).
+					WithFinalizer(finalizer).Container,
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: reconcile.Result{},
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithSpecForceDelete(true).
+					WithFinalizers([]string{}).
+					WithStatusConditions(runtimev1alpha1.Deleting()).
+					Container,
+			},
+		},
+		{
+			name:        "ObserveOnly",
+			observeOnly: true,
+			fields: fields{
+				kube: test.NewMockClient(),
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizer(finalizer).Container,
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: reconcile.Result{},
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecDeletionPolicy(runtimev1alpha1.DeletionDelete).
+					WithFinalizers([]string{}).
+					WithStatusConditions(runtimev1alpha1.Deleting()).
+					Container,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -573,6 +769,7 @@ func Test_containerSyncdeleter_delete(t *testing.T) {
 				ContainerOperations: tt.fields.ContainerOperations,
 				kube:                tt.fields.kube,
 				container:           tt.fields.container,
+				observeOnly:         tt.observeOnly,
 			}
 			got, err := csd.delete(tt.args.ctx)
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
@@ -608,10 +805,11 @@ func Test_containerSyncdeleter_sync(t *testing.T) {
 	}
 
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
+		name        string
+		observeOnly bool
+		fields      fields
+		args        args
+		want        want
 	}{
 		{
 			name: "GetErrorNotFound",
@@ -682,6 +880,48 @@ func Test_containerSyncdeleter_sync(t *testing.T) {
 				cont: v1alpha3test.NewMockContainer(testContainerName).Container,
 			},
 		},
+		{
+			name:        "ObserveOnlyNotFound",
+			observeOnly: true,
+			fields: fields{
+				createupdater: newMockCreateUpdater(),
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
+						return nil, nil, nil
+					},
+				},
+				container: v1alpha3test.NewMockContainer(testContainerName).Container,
+				kube:      test.NewMockClient(),
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: resultRequeue,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithStatusConditions(runtimev1alpha1.ReconcileError(errors.New(errObserveOnlyCreate))).
+					Container,
+			},
+		},
+		{
+			name:        "ObserveOnly",
+			observeOnly: true,
+			fields: fields{
+				createupdater: newMockCreateUpdater(),
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
+						return azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer), nil, nil
+					},
+				},
+				container: v1alpha3test.NewMockContainer(testContainerName).Container,
+				kube:      test.NewMockClient(),
+			},
+			args: args{ctx: ctx},
+			want: want{
+				res: requeueOnSuccess,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Container,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -690,6 +930,7 @@ func Test_containerSyncdeleter_sync(t *testing.T) {
 				ContainerOperations: tt.fields.ContainerOperations,
 				kube:                tt.fields.kube,
 				container:           tt.fields.container,
+				observeOnly:         tt.observeOnly,
 			}
 			got, err := csd.sync(tt.args.ctx)
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
@@ -753,7 +994,7 @@ func Test_containerCreateUpdater_create(t *testing.T) {
 			fields: fields{
 				container: v1alpha3test.NewMockContainer(testContainerName).Container,
 				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockCreate: func(ctx context.Context, pub azblob.PublicAccessType, meta azblob.Metadata) error {
+					MockCreate: func(ctx context.Context, pub azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
 						return errBoom
 					},
 				},
@@ -809,9 +1050,16 @@ func Test_containerCreateUpdater_create(t *testing.T) {
 func Test_containerCreateUpdater_update(t *testing.T) {
 	ctx := context.TODO()
 	errBoom := errors.New("boom")
+	policy := v1alpha3.StoredAccessPolicy{
+		ID:          "readers",
+		Permissions: "rl",
+		Start:       metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Expiry:      metav1.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
 
 	type fields struct {
 		ContainerOperations storage.ContainerOperations
+		legalHold           storage.LegalHoldOperations
 		kube                client.Client
 		container           *v1alpha3.Container
 	}
@@ -836,7 +1084,8 @@ func Test_containerCreateUpdater_update(t *testing.T) {
 			fields: fields{
 				container: v1alpha3test.NewMockContainer(testContainerName).
 					WithSpecPAC(azblob.PublicAccessContainer).Container,
-				kube: test.NewMockClient(),
+				ContainerOperations: azurestoragefake.NewMockContainerOperations(),
+				kube:                test.NewMockClient(),
 			},
 			args: args{
 				ctx:        ctx,
@@ -858,7 +1107,8 @@ func Test_containerCreateUpdater_update(t *testing.T) {
 					WithStatusConditions().
 					Container,
 				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockUpdate: func(ctx context.Context, publicAccessType azblob.PublicAccessType, meta azblob.Metadata) error {
+					MockGetAccessPolicy: func(ctx context.Context) ([]azblob.SignedIdentifier, error) { return nil, nil },
+					MockUpdate: func(ctx context.Context, publicAccessType azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
 						return errBoom
 					},
 				},
@@ -903,11 +1153,169 @@ func Test_containerCreateUpdater_update(t *testing.T) {
 					Container,
 			},
 		},
+		{
+			name: "GetPoliciesFailed",
+			fields: fields{
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					Container,
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGetAccessPolicy: func(ctx context.Context) ([]azblob.SignedIdentifier, error) { return nil, errBoom },
+				},
+				kube: test.NewMockClient(),
+			},
+			args: args{
+				ctx:        ctx,
+				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
+				meta: azblob.Metadata{
+					"foo": "bar",
+				},
+			},
+			want: want{
+				res: resultRequeue,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithStatusConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errGetPolicies))).
+					Container,
+			},
+		},
+		{
+			name: "PoliciesUnmanaged",
+			fields: fields{
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					Container,
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGetAccessPolicy: func(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+						return []azblob.SignedIdentifier{{ID: "external"}}, nil
+					},
+					MockUpdate: func(ctx context.Context, publicAccessType azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
+						if diff := cmp.Diff([]azblob.SignedIdentifier{{ID: "external"}}, ids); diff != "" {
+							return errors.New(diff)
+						}
+						return nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			args: args{
+				ctx:        ctx,
+				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessBlob),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Container,
+			},
+		},
+		{
+			name: "PoliciesUpdated",
+			fields: fields{
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecStoredAccessPolicies(policy).
+					Container,
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGetAccessPolicy: func(ctx context.Context) ([]azblob.SignedIdentifier, error) {
+						return []azblob.SignedIdentifier{{ID: "stale"}}, nil
+					},
+					MockUpdate: func(ctx context.Context, publicAccessType azblob.PublicAccessType, meta azblob.Metadata, ids []azblob.SignedIdentifier) error {
+						if diff := cmp.Diff(storage.NewSignedIdentifiers([]v1alpha3.StoredAccessPolicy{policy}), ids); diff != "" {
+							return errors.New(diff)
+						}
+						return nil
+					},
+				},
+				kube: test.NewMockClient(),
+			},
+			args: args{
+				ctx:        ctx,
+				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecStoredAccessPolicies(policy).
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Container,
+			},
+		},
+		{
+			name: "LegalHoldUpdated",
+			fields: fields{
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecLegalHoldTags("Litigation").
+					WithStatusLegalHoldTags("audit").
+					Container,
+				legalHold: &azurestoragefake.MockLegalHoldOperations{
+					MockGetLegalHoldTags: func(_ context.Context) ([]string, error) { return []string{"audit"}, nil },
+					MockSetLegalHold: func(_ context.Context, tags []string) error {
+						if diff := cmp.Diff([]string{"litigation"}, tags); diff != "" {
+							return errors.New(diff)
+						}
+						return nil
+					},
+					MockClearLegalHold: func(_ context.Context, tags []string) error {
+						if diff := cmp.Diff([]string{"audit"}, tags); diff != "" {
+							return errors.New(diff)
+						}
+						return nil
+					},
+				},
+				ContainerOperations: azurestoragefake.NewMockContainerOperations(),
+				kube:                test.NewMockClient(),
+			},
+			args: args{
+				ctx:        ctx,
+				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
+			},
+			want: want{
+				res: requeueOnSuccess,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecLegalHoldTags("Litigation").
+					WithStatusLegalHoldTags("litigation").
+					WithStatusConditions(runtimev1alpha1.Available(), runtimev1alpha1.ReconcileSuccess()).
+					Container,
+			},
+		},
+		{
+			name: "SetLegalHoldFailed",
+			fields: fields{
+				container: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecLegalHoldTags("litigation").
+					Container,
+				legalHold: &azurestoragefake.MockLegalHoldOperations{
+					MockGetLegalHoldTags: func(_ context.Context) ([]string, error) { return nil, nil },
+					MockSetLegalHold:     func(_ context.Context, _ []string) error { return errBoom },
+				},
+				ContainerOperations: azurestoragefake.NewMockContainerOperations(),
+				kube:                test.NewMockClient(),
+			},
+			args: args{
+				ctx:        ctx,
+				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
+			},
+			want: want{
+				res: resultRequeue,
+				cont: v1alpha3test.NewMockContainer(testContainerName).
+					WithSpecPAC(azblob.PublicAccessContainer).
+					WithSpecLegalHoldTags("litigation").
+					WithStatusConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errSetLegalHold))).
+					Container,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ccu := &containerCreateUpdater{
 				ContainerOperations: tt.fields.ContainerOperations,
+				legalHold:           tt.fields.legalHold,
 				kube:                tt.fields.kube,
 				container:           tt.fields.container,
 			}
@@ -924,3 +1332,113 @@ func Test_containerCreateUpdater_update(t *testing.T) {
 		})
 	}
 }
+
+// eventRecorder records the events it is asked to record.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func Test_containerCreateUpdater_recordDrift(t *testing.T) {
+	type args struct {
+		accessType azblob.PublicAccessType
+		meta       azblob.Metadata
+	}
+	tests := []struct {
+		name      string
+		container *v1alpha3.Container
+		args      args
+		want      []event.Event
+	}{
+		{
+			name: "NoDrift",
+			container: v1alpha3test.NewMockContainer(testContainerName).
+				WithSpecPAC(azblob.PublicAccessContainer).
+				WithSpecMetadata(map[string]string{"foo": "bar"}).
+				Container,
+			args: args{accessType: azblob.PublicAccessContainer, meta: azblob.Metadata{"foo": "bar"}},
+		},
+		{
+			name: "PublicAccessDrift",
+			container: v1alpha3test.NewMockContainer(testContainerName).
+				WithSpecPAC(azblob.PublicAccessNone).
+				Container,
+			args: args{accessType: azblob.PublicAccessBlob},
+			want: []event.Event{event.Normal(reasonDriftCorrected, "Updated public access type from blob to private")},
+		},
+		{
+			name: "MetadataDrift",
+			container: v1alpha3test.NewMockContainer(testContainerName).
+				WithSpecPAC(azblob.PublicAccessContainer).
+				WithSpecMetadata(map[string]string{"foo": "bar"}).
+				Container,
+			args: args{accessType: azblob.PublicAccessContainer, meta: azblob.Metadata{"foo": "baz", "added": "elsewhere"}},
+			want: []event.Event{event.Normal(reasonDriftCorrected, "Updated metadata from map[added:elsewhere foo:baz] to map[foo:bar]")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &eventRecorder{}
+			ccu := &containerCreateUpdater{container: tt.container, record: r}
+			ccu.recordDrift(tt.args.accessType, tt.args.meta)
+			if diff := cmp.Diff(tt.want, r.events); diff != "" {
+				t.Errorf("containerCreateUpdater.recordDrift(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClassifyingContainerOperations(t *testing.T) {
+	cases := map[string]struct {
+		err       error
+		backoff   bool
+		forbidden bool
+		notFound  bool
+	}{
+		"Succeeded": {},
+		"Throttled": {
+			err:     azblob.NewResponseError(nil, &http.Response{StatusCode: http.StatusTooManyRequests}, ""),
+			backoff: true,
+		},
+		"Forbidden": {
+			err:       azblob.NewResponseError(nil, &http.Response{StatusCode: http.StatusForbidden}, ""),
+			forbidden: true,
+		},
+		"NotFound": {
+			err:      newStorageNotFoundError(),
+			notFound: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &v1alpha3.Container{}
+			th := azure.NewThrottle()
+			co := &classifyingContainerOperations{
+				ContainerOperations: &azurestoragefake.MockContainerOperations{
+					MockGet: func(context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) { return nil, nil, tc.err },
+				},
+				container: c,
+				throttle:  th,
+			}
+
+			_, _, err := co.Get(context.Background())
+			if diff := cmp.Diff(tc.err != nil, err != nil); diff != "" {
+				t.Errorf("Get(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.notFound, storage.IsNotFoundError(err)); diff != "" {
+				t.Errorf("Get(...): -want not found, +got not found:\n%s", diff)
+			}
+			if _, backoff := th.Backoff(c); backoff != tc.backoff {
+				t.Errorf("Get(...): want backoff %t, got %t", tc.backoff, backoff)
+			}
+			forbidden := c.GetCondition(runtimev1alpha1.TypeReady).Reason == azure.ReasonForbidden
+			if forbidden != tc.forbidden {
+				t.Errorf("Get(...): want forbidden %t, got %t", tc.forbidden, forbidden)
+			}
+		})
+	}
+}
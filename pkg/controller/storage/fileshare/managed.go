@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fileshare
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage/storageapi"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	azurestorage "github.com/crossplane/provider-azure/pkg/clients/storage"
+	"github.com/crossplane/provider-azure/pkg/controller/chain"
+	"github.com/crossplane/provider-azure/pkg/controller/credentials"
+)
+
+// Error strings.
+const (
+	errNotFileShare      = "managed resource is not a FileShare"
+	errConnectFailed     = "cannot connect to Azure API"
+	errGetFileShare      = "cannot get FileShare"
+	errCreateFileShare   = "cannot create FileShare"
+	errUpdateFileShare   = "cannot update FileShare"
+	errDeleteFileShare   = "cannot delete FileShare"
+	errUpdateFileShareCR = "cannot update FileShare custom resource"
+	errGetAccount        = "cannot get storage Account of FileShare"
+	errNoFileEndpoint    = "storage Account of FileShare has no primary file endpoint"
+	errConnectionDetails = "cannot determine FileShare connection details"
+)
+
+// Connection secret keys.
+const (
+	// ConnectionSecretUNCPathKey is the key of the UNC path used to mount the
+	// share via SMB. It is published only for SMB shares.
+	ConnectionSecretUNCPathKey = "uncPath"
+
+	// ConnectionSecretNFSMountTargetKey is the key of the target used to
+	// mount the share via NFS. It is published only for NFS shares.
+	ConnectionSecretNFSMountTargetKey = "nfsMountTarget"
+)
+
+// Setup adds a controller that reconciles FileShares.
+func Setup(mgr ctrl.Manager, l logging.Logger, o chain.Options, observeOnly bool) error {
+	name := managed.ControllerName(v1alpha3.FileShareGroupKind)
+	throttled := azure.NewThrottle()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.Concurrency.Options(name)).
+		For(&v1alpha3.FileShare{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, credentials.EnqueueRequestForSecret(mgr.GetClient(), &v1alpha3.FileShareList{}, l)).
+		Complete(chain.NewReconciler(mgr, resource.ManagedKind(v1alpha3.FileShareGroupVersionKind), managed.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha3.FileShareGroupVersionKind),
+			managed.WithExternalConnecter(chain.NewConnecter(mgr.GetClient(), &connecter{kube: mgr.GetClient()}, throttled, observeOnly)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))), throttled, o, chain.WithPause()))
+}
+
+type connecter struct {
+	kube client.Client
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	creds, auth, err := azure.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectFailed)
+	}
+	cl := storage.NewFileSharesClient(creds[azure.CredentialsKeySubscriptionID])
+	cl.Authorizer = auth
+	ac := storage.NewAccountsClient(creds[azure.CredentialsKeySubscriptionID])
+	ac.Authorizer = auth
+	return &external{kube: c.kube, client: cl, accounts: ac}, nil
+}
+
+type external struct {
+	kube     client.Client
+	client   storageapi.FileSharesClientAPI
+	accounts storageapi.AccountsClientAPI
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha3.FileShare)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotFileShare)
+	}
+
+	p := cr.Spec.ForProvider
+	az, err := e.client.Get(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr), "")
+	if azure.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFileShare)
+	}
+
+	original := cr.DeepCopy()
+	azurestorage.LateInitializeFileShare(&cr.Spec.ForProvider, az)
+	if err := azure.UpdateIfChanged(ctx, e.kube, original, cr); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateFileShareCR)
+	}
+	cr.Status.AtProvider = azurestorage.GenerateFileShareObservation(az)
+
+	cd, err := e.connectionDetails(ctx, cr, az)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.SetConditions(runtimev1alpha1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  azurestorage.IsFileShareUpToDate(cr.Spec.ForProvider, az),
+		ConnectionDetails: cd,
+	}, nil
+}
+
+// connectionDetails returns the connection details of the supplied share. They
+// are derived from the primary file endpoint of its account, because the DNS
+// suffix of the endpoint differs between the public and sovereign clouds.
+func (e *external) connectionDetails(ctx context.Context, cr *v1alpha3.FileShare, az storage.FileShare) (managed.ConnectionDetails, error) {
+	p := cr.Spec.ForProvider
+	acct, err := e.accounts.GetProperties(ctx, p.ResourceGroupName, p.AccountName, "")
+	if err != nil {
+		return nil, errors.Wrap(err, errGetAccount)
+	}
+	if acct.AccountProperties == nil || acct.PrimaryEndpoints == nil || acct.PrimaryEndpoints.File == nil {
+		return nil, errors.New(errNoFileEndpoint)
+	}
+	ep := azure.ToString(acct.PrimaryEndpoints.File)
+	share := meta.GetExternalName(cr)
+	cd := managed.ConnectionDetails{
+		runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(azurestorage.FileShareEndpoint(ep, share)),
+	}
+
+	if azurestorage.IsNFSFileShare(az) {
+		t, err := azurestorage.FileShareNFSMountTarget(ep, p.AccountName, share)
+		if err != nil {
+			return nil, errors.Wrap(err, errConnectionDetails)
+		}
+		cd[ConnectionSecretNFSMountTargetKey] = []byte(t)
+		return cd, nil
+	}
+
+	unc, err := azurestorage.FileShareUNCPath(ep, share)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectionDetails)
+	}
+	cd[ConnectionSecretUNCPathKey] = []byte(unc)
+	return cd, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha3.FileShare)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotFileShare)
+	}
+
+	cr.SetConditions(runtimev1alpha1.Creating())
+	p := cr.Spec.ForProvider
+	_, err := e.client.Create(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr), azurestorage.NewFileShareParameters(p))
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFileShare)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha3.FileShare)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotFileShare)
+	}
+
+	p := cr.Spec.ForProvider
+	_, err := e.client.Update(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr), azurestorage.NewFileShareUpdateParameters(p))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFileShare)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha3.FileShare)
+	if !ok {
+		return errors.New(errNotFileShare)
+	}
+
+	cr.SetConditions(runtimev1alpha1.Deleting())
+	p := cr.Spec.ForProvider
+	_, err := e.client.Delete(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr))
+	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteFileShare)
+}
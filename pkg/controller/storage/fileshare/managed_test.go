@@ -0,0 +1,438 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fileshare
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-azure/apis/storage/v1alpha3"
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+	"github.com/crossplane/provider-azure/pkg/clients/storage/fake"
+)
+
+const (
+	name              = "coolshare"
+	accountName       = "coolaccount"
+	resourceGroupName = "coolRG"
+	resourceID        = "a-very-cool-id"
+	quota             = int32(100)
+	tier              = "Hot"
+)
+
+var errBoom = errors.New("boom")
+
+func accounts(fileEndpoint string) *fake.MockAccountsClient {
+	return &fake.MockAccountsClient{
+		MockGetProperties: func(_ context.Context, _, _ string, _ storage.AccountExpand) (storage.Account, error) {
+			return storage.Account{AccountProperties: &storage.AccountProperties{
+				PrimaryEndpoints: &storage.Endpoints{File: azure.ToStringPtr(fileEndpoint)},
+			}}, nil
+		},
+	}
+}
+
+type fileShareModifier func(*v1alpha3.FileShare)
+
+func withConditions(c ...runtimev1alpha1.Condition) fileShareModifier {
+	return func(r *v1alpha3.FileShare) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withQuota(q int32) fileShareModifier {
+	return func(r *v1alpha3.FileShare) { r.Spec.ForProvider.ShareQuota = &q }
+}
+
+func withAccessTier(t string) fileShareModifier {
+	return func(r *v1alpha3.FileShare) { r.Spec.ForProvider.AccessTier = &t }
+}
+
+func withProtocol(p string) fileShareModifier {
+	return func(r *v1alpha3.FileShare) { r.Spec.ForProvider.EnabledProtocols = &p }
+}
+
+func withObservation(o v1alpha3.FileShareObservation) fileShareModifier {
+	return func(r *v1alpha3.FileShare) { r.Status.AtProvider = o }
+}
+
+func fileShare(m ...fileShareModifier) *v1alpha3.FileShare {
+	r := &v1alpha3.FileShare{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha3.FileShareSpec{
+			ForProvider: v1alpha3.FileShareParameters{
+				ResourceGroupName: resourceGroupName,
+				AccountName:       accountName,
+			},
+		},
+	}
+	meta.SetExternalName(r, name)
+	for _, f := range m {
+		f(r)
+	}
+	return r
+}
+
+// Test that our Reconciler implementation satisfies the Reconciler interface.
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connecter{}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		cr  *v1alpha3.FileShare
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		e    *external
+		mg   resource.Managed
+		want want
+	}{
+		"NotFileShare": {
+			e:  &external{},
+			mg: &v1alpha3.Container{},
+			want: want{
+				err: errors.New(errNotFileShare),
+			},
+		},
+		"NotFound": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+					return storage.FileShare{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			mg: fileShare(),
+			want: want{
+				cr: fileShare(),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetFailed": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+					return storage.FileShare{}, errBoom
+				},
+			}},
+			mg: fileShare(),
+			want: want{
+				cr:  fileShare(),
+				err: errors.Wrap(errBoom, errGetFileShare),
+			},
+		},
+		"KubeUpdateFailed": {
+			e: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				client: &fake.MockFileSharesClient{
+					MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+						return storage.FileShare{FileShareProperties: &storage.FileShareProperties{ShareQuota: azure.ToInt32Ptr(int(quota))}}, nil
+					},
+				},
+			},
+			mg: fileShare(),
+			want: want{
+				cr:  fileShare(withQuota(quota)),
+				err: errors.Wrap(errBoom, errUpdateFileShareCR),
+			},
+		},
+		"Exists": {
+			e: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockFileSharesClient{
+					MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+						return storage.FileShare{
+							ID: azure.ToStringPtr(resourceID),
+							FileShareProperties: &storage.FileShareProperties{
+								ShareQuota: azure.ToInt32Ptr(int(quota)),
+								AccessTier: storage.ShareAccessTierCool,
+							},
+						}, nil
+					},
+				},
+				accounts: accounts("https://coolaccount.file.core.windows.net/"),
+			},
+			mg: fileShare(withAccessTier(tier)),
+			want: want{
+				cr: fileShare(
+					withAccessTier(tier),
+					withQuota(quota),
+					withConditions(runtimev1alpha1.Available()),
+					withObservation(v1alpha3.FileShareObservation{ID: resourceID}),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("https://coolaccount.file.core.windows.net/coolshare"),
+						ConnectionSecretUNCPathKey:                           []byte(`\\coolaccount.file.core.windows.net\coolshare`),
+					},
+				},
+			},
+		},
+		"ExistsNFS": {
+			e: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockFileSharesClient{
+					MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+						return storage.FileShare{
+							ID: azure.ToStringPtr(resourceID),
+							FileShareProperties: &storage.FileShareProperties{
+								ShareQuota:       azure.ToInt32Ptr(int(quota)),
+								EnabledProtocols: storage.NFS,
+							},
+						}, nil
+					},
+				},
+				accounts: accounts("https://coolaccount.file.core.chinacloudapi.cn/"),
+			},
+			mg: fileShare(withQuota(quota), withProtocol(string(storage.NFS))),
+			want: want{
+				cr: fileShare(
+					withQuota(quota),
+					withProtocol(string(storage.NFS)),
+					withConditions(runtimev1alpha1.Available()),
+					withObservation(v1alpha3.FileShareObservation{ID: resourceID}),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("https://coolaccount.file.core.chinacloudapi.cn/coolshare"),
+						ConnectionSecretNFSMountTargetKey:                    []byte("coolaccount.file.core.chinacloudapi.cn:/coolaccount/coolshare"),
+					},
+				},
+			},
+		},
+		"GetAccountFailed": {
+			e: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockFileSharesClient{
+					MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+						return storage.FileShare{ID: azure.ToStringPtr(resourceID), FileShareProperties: &storage.FileShareProperties{}}, nil
+					},
+				},
+				accounts: &fake.MockAccountsClient{
+					MockGetProperties: func(_ context.Context, _, _ string, _ storage.AccountExpand) (storage.Account, error) {
+						return storage.Account{}, errBoom
+					},
+				},
+			},
+			mg: fileShare(),
+			want: want{
+				cr:  fileShare(withObservation(v1alpha3.FileShareObservation{ID: resourceID})),
+				err: errors.Wrap(errBoom, errGetAccount),
+			},
+		},
+		"NoFileEndpoint": {
+			e: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockFileSharesClient{
+					MockGet: func(_ context.Context, _, _, _ string, _ storage.GetShareExpand) (storage.FileShare, error) {
+						return storage.FileShare{ID: azure.ToStringPtr(resourceID), FileShareProperties: &storage.FileShareProperties{}}, nil
+					},
+				},
+				accounts: &fake.MockAccountsClient{
+					MockGetProperties: func(_ context.Context, _, _ string, _ storage.AccountExpand) (storage.Account, error) {
+						return storage.Account{AccountProperties: &storage.AccountProperties{}}, nil
+					},
+				},
+			},
+			mg: fileShare(),
+			want: want{
+				cr:  fileShare(withObservation(v1alpha3.FileShareObservation{ID: resourceID})),
+				err: errors.New(errNoFileEndpoint),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if tc.want.cr == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		cr  *v1alpha3.FileShare
+		err error
+	}
+
+	cases := map[string]struct {
+		e    *external
+		mg   resource.Managed
+		want want
+	}{
+		"NotFileShare": {
+			e:  &external{},
+			mg: &v1alpha3.Container{},
+			want: want{
+				err: errors.New(errNotFileShare),
+			},
+		},
+		"CreateFailed": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockCreate: func(_ context.Context, _, _, _ string, _ storage.FileShare) (storage.FileShare, error) {
+					return storage.FileShare{}, errBoom
+				},
+			}},
+			mg: fileShare(),
+			want: want{
+				cr:  fileShare(withConditions(runtimev1alpha1.Creating())),
+				err: errors.Wrap(errBoom, errCreateFileShare),
+			},
+		},
+		"Successful": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockCreate: func(_ context.Context, rg, acct, share string, fs storage.FileShare) (storage.FileShare, error) {
+					if rg != resourceGroupName || acct != accountName || share != name {
+						return storage.FileShare{}, errBoom
+					}
+					if diff := cmp.Diff(azure.ToInt32Ptr(int(quota)), fs.ShareQuota); diff != "" {
+						t.Errorf("Create(...): -want quota, +got quota:\n%s", diff)
+					}
+					return storage.FileShare{}, nil
+				},
+			}},
+			mg: fileShare(withQuota(quota)),
+			want: want{
+				cr: fileShare(withQuota(quota), withConditions(runtimev1alpha1.Creating())),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Create(...): -want error, +got error:\n%s", diff)
+			}
+			if tc.want.cr == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		e   *external
+		mg  resource.Managed
+		err error
+	}{
+		"NotFileShare": {
+			e:   &external{},
+			mg:  &v1alpha3.Container{},
+			err: errors.New(errNotFileShare),
+		},
+		"UpdateFailed": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockUpdate: func(_ context.Context, _, _, _ string, _ storage.FileShare) (storage.FileShare, error) {
+					return storage.FileShare{}, errBoom
+				},
+			}},
+			mg:  fileShare(),
+			err: errors.Wrap(errBoom, errUpdateFileShare),
+		},
+		"Successful": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockUpdate: func(_ context.Context, _, _, _ string, fs storage.FileShare) (storage.FileShare, error) {
+					if fs.AccessTier != storage.ShareAccessTierHot {
+						return storage.FileShare{}, errBoom
+					}
+					return storage.FileShare{}, nil
+				},
+			}},
+			mg: fileShare(withAccessTier(tier)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.e.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		e   *external
+		mg  resource.Managed
+		err error
+	}{
+		"NotFileShare": {
+			e:   &external{},
+			mg:  &v1alpha3.Container{},
+			err: errors.New(errNotFileShare),
+		},
+		"NotFound": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockDelete: func(_ context.Context, _, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			mg: fileShare(),
+		},
+		"DeleteFailed": {
+			e: &external{client: &fake.MockFileSharesClient{
+				MockDelete: func(_ context.Context, _, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, errBoom
+				},
+			}},
+			mg:  fileShare(),
+			err: errors.Wrap(errBoom, errDeleteFileShare),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
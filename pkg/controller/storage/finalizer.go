@@ -0,0 +1,35 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoveFinalizer removes every occurrence of the supplied finalizer from the
+// supplied object, preserving any other finalizers and their order. Unlike
+// meta.RemoveFinalizer it is safe to use on objects that carry the supplied
+// finalizer more than once.
+func RemoveFinalizer(o metav1.Object, finalizer string) {
+	f := make([]string, 0, len(o.GetFinalizers()))
+	for _, e := range o.GetFinalizers() {
+		if e != finalizer {
+			f = append(f, e)
+		}
+	}
+	o.SetFinalizers(f)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRemoveFinalizer(t *testing.T) {
+	ours := "finalizer.cool"
+
+	cases := map[string]struct {
+		finalizers []string
+		want       []string
+	}{
+		"NoFinalizers": {
+			finalizers: nil,
+			want:       []string{},
+		},
+		"OnlyOurs": {
+			finalizers: []string{ours},
+			want:       []string{},
+		},
+		"SeveralFinalizers": {
+			finalizers: []string{"a", ours, "b"},
+			want:       []string{"a", "b"},
+		},
+		"NotPresent": {
+			finalizers: []string{"a", "b"},
+			want:       []string{"a", "b"},
+		},
+		"Duplicated": {
+			finalizers: []string{ours, ours, "a", ours},
+			want:       []string{"a"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{Finalizers: tc.finalizers}
+			RemoveFinalizer(o, ours)
+			if diff := cmp.Diff(tc.want, o.GetFinalizers()); diff != "" {
+				t.Errorf("RemoveFinalizer(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
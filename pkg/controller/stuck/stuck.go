@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stuck provides a reconciler that reports managed resources that
+// have failed to reconcile many times in a row. Such resources are typically
+// looping on a terminal Azure error, rather than a transient one.
+package stuck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+// DefaultThreshold is the default number of consecutive failed reconciles
+// after which a managed resource is considered stuck.
+const DefaultThreshold = 10
+
+// TypeStuck resources have failed to reconcile at least the stuck threshold
+// times in a row.
+const TypeStuck runtimev1alpha1.ConditionType = "Stuck"
+
+// Reasons a resource is or is not stuck.
+const (
+	ReasonStuck   runtimev1alpha1.ConditionReason = "ReconcileStuck"
+	ReasonUnstuck runtimev1alpha1.ConditionReason = "ReconcileSucceeded"
+)
+
+const (
+	timeout = 1 * time.Minute
+
+	// shortWait is the interval after which the managed reconciler requeues
+	// a resource that failed to reconcile. It is crossplane-runtime's
+	// default, which we don't override. The managed reconciler also uses it
+	// after creating or deleting an external resource, but never for more
+	// than a few reconciles in a row unless those reconciles are failing.
+	shortWait = 30 * time.Second
+
+	msgFmtStuck = "at least %d consecutive reconciles failed; see the Synced condition for the latest error"
+
+	errGetManaged          = "cannot get managed resource"
+	errUpdateManagedStatus = "cannot update managed resource status"
+)
+
+// Resources is the number of stuck managed resources of each controller.
+var Resources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "provider_azure_stuck_managed_resources",
+	Help: "Number of managed resources that have failed to reconcile at least the stuck threshold times in a row.",
+}, []string{"controller"})
+
+func init() {
+	metrics.Registry.MustRegister(Resources)
+}
+
+// Stuck returns a condition that indicates the managed resource has failed to
+// reconcile at least the supplied threshold of times in a row. Its message
+// doesn't change as further reconciles fail, so that it needn't be rewritten.
+func Stuck(threshold int) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeStuck,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonStuck,
+		Message:            fmt.Sprintf(msgFmtStuck, threshold),
+	}
+}
+
+// Unstuck returns a condition that indicates the managed resource, which was
+// previously stuck, has since reconciled successfully.
+func Unstuck() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeStuck,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnstuck,
+	}
+}
+
+// A Reconciler wraps another reconciler, counting the consecutive reconciles
+// of each managed resource that fail. Resources that fail at least its
+// threshold of times in a row are marked with a Stuck condition, and counted by the
+// Resources metric until they next reconcile successfully. Failed reconciles of
+// resources that are being deleted are not counted; slow deletes are instead
+// reported by the deletion reconciler when they time out.
+//
+// Whether a reconcile failed is determined by the result of the wrapped
+// reconciler alone, not by the managed resource's Synced condition, which may
+// be stale in the cache. By default the result is interpreted as that of the
+// managed reconciler; see ManagedReconcileFailed.
+type Reconciler struct {
+	client     client.Client
+	reader     client.Reader
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+	name       string
+	threshold  int
+	failed     FailedFn
+
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// A FailedFn returns true if the supplied result and error returned by a
+// reconciler indicate that the reconcile failed.
+type FailedFn func(result reconcile.Result, err error) bool
+
+// ManagedReconcileFailed returns true if the supplied result and error of the
+// managed reconciler indicate that the reconcile failed. The managed
+// reconciler returns an error only when it cannot update the resource's
+// status; otherwise it signals that a reconcile failed by requeueing the
+// resource after its short wait.
+func ManagedReconcileFailed(result reconcile.Result, err error) bool {
+	return err != nil || result.RequeueAfter == shortWait
+}
+
+// RequeueFailed returns true if the supplied result and error indicate that
+// the reconcile failed, assuming a reconciler that signals failure by
+// returning an error or by asking to be requeued with rate limiting, and that
+// otherwise requeues after an explicit interval. The storage account and
+// container reconcilers behave this way.
+func RequeueFailed(result reconcile.Result, err error) bool {
+	return err != nil || (result.Requeue && result.RequeueAfter == 0)
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithThreshold specifies the number of consecutive failed reconciles after
+// which a managed resource is considered stuck. Stuck resources are not
+// reported if it is not positive. Reconcilers use the DefaultThreshold by
+// default.
+func WithThreshold(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.threshold = n
+	}
+}
+
+// WithFailedFn determines whether a reconcile failed using the supplied
+// function, rather than ManagedReconcileFailed.
+func WithFailedFn(fn FailedFn) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.failed = fn
+	}
+}
+
+// NewReconciler returns a Reconciler that reports stuck managed resources of
+// the supplied kind, and otherwise delegates to the supplied reconciler.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	rec := &Reconciler{
+		client:     m.GetClient(),
+		reader:     m.GetAPIReader(),
+		newManaged: nm,
+		wrapped:    r,
+		name:       managed.ControllerName(schema.GroupVersionKind(of).GroupKind().String()),
+		threshold:  DefaultThreshold,
+		failed:     ManagedReconcileFailed,
+		failures:   map[types.NamespacedName]int{},
+	}
+	for _, ro := range o {
+		ro(rec)
+	}
+	return rec
+}
+
+// Reconcile a managed resource, reporting it if it is stuck.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.wrapped.Reconcile(req)
+	if r.threshold <= 0 {
+		return result, err
+	}
+
+	failed := r.failed(result, err)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	gerr := r.client.Get(ctx, req.NamespacedName, mg)
+	if kerrors.IsNotFound(gerr) {
+		r.record(req.NamespacedName, false)
+		return result, err
+	}
+	if gerr == nil && failed && meta.WasDeleted(mg) {
+		// Deletes that are still in progress requeue after the short wait,
+		// and are reported by the deletion reconciler if they time out, so
+		// they are not counted as failures.
+		r.record(req.NamespacedName, false)
+		return result, err
+	}
+	if n := r.record(req.NamespacedName, failed); failed && n < r.threshold {
+		return result, err
+	}
+	if gerr != nil {
+		if err == nil {
+			err = errors.Wrap(gerr, errGetManaged)
+		}
+		return result, err
+	}
+
+	// The condition is written only when it changes, because each write
+	// results in a watch event that requeues the resource immediately.
+	want := Unstuck()
+	if failed {
+		want = Stuck(r.threshold)
+	}
+	got := mg.GetCondition(TypeStuck)
+	switch {
+	case got.Status == want.Status:
+		return result, err
+	case !failed && got.Status != corev1.ConditionTrue:
+		// Never stuck, so there's nothing to clear.
+		return result, err
+	}
+	if uerr := azure.UpdateConditions(ctx, r.client, r.reader, mg, want); uerr != nil && err == nil {
+		return result, errors.Wrap(uerr, errUpdateManagedStatus)
+	}
+	return result, err
+}
+
+// record records whether the latest reconcile of the supplied resource failed,
+// returning its number of consecutive failures.
+func (r *Reconciler) record(nn types.NamespacedName, failed bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if failed {
+		r.failures[nn]++
+	} else {
+		delete(r.failures, nn)
+	}
+	stuck := 0
+	for _, n := range r.failures {
+		if n >= r.threshold {
+			stuck++
+		}
+	}
+	Resources.WithLabelValues(r.name).Set(float64(stuck))
+	return r.failures[nn]
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stuck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+var (
+	failedResult    = reconcile.Result{RequeueAfter: shortWait}
+	succeededResult = reconcile.Result{RequeueAfter: 1 * time.Minute}
+
+	// The storage account and container reconcilers requeue with rate
+	// limiting when they fail, and after 30 seconds while they wait for an
+	// Azure operation to complete.
+	requeueFailedResult = reconcile.Result{Requeue: true}
+	requeueWaitResult   = reconcile.Result{RequeueAfter: 30 * time.Second}
+)
+
+func wrapped(result reconcile.Result, err error) reconcile.Reconciler {
+	return reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+		return result, err
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	nn := types.NamespacedName{Name: "cool"}
+	now := metav1.Now()
+
+	type want struct {
+		result     reconcile.Result
+		err        error
+		failures   int
+		conditions []runtimev1alpha1.Condition
+	}
+
+	withCondition := func(c runtimev1alpha1.Condition) test.ObjectFn {
+		return func(o runtime.Object) error {
+			o.(*fake.Managed).SetConditions(c)
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		client   client.Client
+		wrapped  reconcile.Reconciler
+		failed   FailedFn
+		failures int
+		want     want
+	}{
+		"NotFound": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			wrapped:  wrapped(succeededResult, nil),
+			failures: 12,
+			want:     want{result: succeededResult},
+		},
+		"Succeeded": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			wrapped:  wrapped(succeededResult, nil),
+			failures: 2,
+			want:     want{result: succeededResult},
+		},
+		"SucceededAfterStuck": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withCondition(Stuck(3))),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped:  wrapped(succeededResult, nil),
+			failures: 12,
+			want: want{
+				result:     succeededResult,
+				conditions: []runtimev1alpha1.Condition{Unstuck()},
+			},
+		},
+		"Failed": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			wrapped:  wrapped(failedResult, nil),
+			failures: 1,
+			want: want{
+				result:   failedResult,
+				failures: 2,
+			},
+		},
+		"Stuck": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped:  wrapped(failedResult, nil),
+			failures: 2,
+			want: want{
+				result:     failedResult,
+				failures:   3,
+				conditions: []runtimev1alpha1.Condition{Stuck(3)},
+			},
+		},
+		"AlreadyStuck": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, withCondition(Stuck(3))),
+			},
+			wrapped:  wrapped(failedResult, nil),
+			failures: 7,
+			want: want{
+				result:     failedResult,
+				failures:   8,
+				conditions: []runtimev1alpha1.Condition{Stuck(3)},
+			},
+		},
+		"StillDeleting": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+					o.(*fake.Managed).SetDeletionTimestamp(&now)
+					return nil
+				}),
+			},
+			wrapped:  wrapped(failedResult, nil),
+			failures: 7,
+			want:     want{result: failedResult},
+		},
+		"StuckReturningError": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped:  wrapped(reconcile.Result{}, errBoom),
+			failures: 2,
+			want: want{
+				err:        errBoom,
+				failures:   3,
+				conditions: []runtimev1alpha1.Condition{Stuck(3)},
+			},
+		},
+		"RequeueFailed": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			wrapped:  wrapped(requeueFailedResult, nil),
+			failed:   RequeueFailed,
+			failures: 1,
+			want: want{
+				result:   requeueFailedResult,
+				failures: 2,
+			},
+		},
+		"RequeueStuck": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped:  wrapped(requeueFailedResult, nil),
+			failed:   RequeueFailed,
+			failures: 2,
+			want: want{
+				result:     requeueFailedResult,
+				failures:   3,
+				conditions: []runtimev1alpha1.Condition{Stuck(3)},
+			},
+		},
+		"RequeueWaitSucceeded": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			wrapped:  wrapped(requeueWaitResult, nil),
+			failed:   RequeueFailed,
+			failures: 2,
+			want:     want{result: requeueWaitResult},
+		},
+		"RequeueWaitSucceededAfterStuck": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withCondition(Stuck(3))),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			wrapped:  wrapped(requeueWaitResult, nil),
+			failed:   RequeueFailed,
+			failures: 12,
+			want: want{
+				result:     requeueWaitResult,
+				conditions: []runtimev1alpha1.Condition{Unstuck()},
+			},
+		},
+		"StuckStatusUpdateError": {
+			client: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			wrapped:  wrapped(failedResult, nil),
+			failures: 5,
+			want: want{
+				result:     failedResult,
+				err:        errors.Wrap(errBoom, errUpdateManagedStatus),
+				failures:   6,
+				conditions: []runtimev1alpha1.Condition{Stuck(3)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			failed := tc.failed
+			if failed == nil {
+				failed = ManagedReconcileFailed
+			}
+			r := &Reconciler{
+				client:     tc.client,
+				reader:     tc.client,
+				newManaged: func() resource.Managed { return mg },
+				wrapped:    tc.wrapped,
+				name:       "test",
+				threshold:  3,
+				failed:     failed,
+				failures:   map[types.NamespacedName]int{nn: tc.failures},
+			}
+
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: nn})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.failures, r.failures[nn]); diff != "" {
+				t.Errorf("r.Reconcile(...): -want failures, +got failures:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, mg.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want conditions, +got conditions:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileSlowDelete(t *testing.T) {
+	nn := types.NamespacedName{Name: "cool"}
+	now := metav1.Now()
+	mg := &fake.Managed{}
+	r := &Reconciler{
+		client: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+				o.(*fake.Managed).SetDeletionTimestamp(&now)
+				return nil
+			}),
+		},
+		newManaged: func() resource.Managed { return mg },
+		wrapped:    wrapped(failedResult, nil),
+		name:       "slow-delete",
+		threshold:  3,
+		failed:     ManagedReconcileFailed,
+		failures:   map[types.NamespacedName]int{},
+	}
+
+	// Deletes of some resources, for example Redis caches, take far longer
+	// than the threshold of reconciles. They must not be reported as stuck.
+	for i := 0; i < 10; i++ {
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: nn}); err != nil {
+			t.Fatalf("r.Reconcile(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(0, r.failures[nn]); diff != "" {
+		t.Errorf("r.Reconcile(...): -want failures, +got failures:\n%s", diff)
+	}
+	if diff := cmp.Diff(float64(0), testutil.ToFloat64(Resources.WithLabelValues("slow-delete"))); diff != "" {
+		t.Errorf("r.Reconcile(...): -want stuck resources, +got stuck resources:\n%s", diff)
+	}
+	if diff := cmp.Diff([]runtimev1alpha1.Condition(nil), mg.Conditions, test.EquateConditions()); diff != "" {
+		t.Errorf("r.Reconcile(...): -want conditions, +got conditions:\n%s", diff)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle provides a reconciler that backs off from managed resources
+// whose requests Azure has throttled.
+package throttle
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+const (
+	timeout = 1 * time.Minute
+
+	errGetManaged = "cannot get managed resource"
+)
+
+// A Reconciler wraps another reconciler, skipping reconciliation of managed
+// resources while the supplied Throttle is backing off from them. A managed
+// resource that is backing off, or that the wrapped reconciler found to be
+// throttled, is requeued once its backoff has elapsed rather than after the
+// wrapped reconciler's short error wait. Its status is therefore not rewritten
+// by each retry while it backs off.
+type Reconciler struct {
+	client     client.Client
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+	throttle   *azure.Throttle
+}
+
+// NewReconciler returns a Reconciler that backs off from the supplied kind of
+// managed resource while the supplied Throttle is backing off from it, and
+// otherwise delegates to the supplied reconciler. The Throttle should be the
+// one used to classify the errors of the wrapped reconciler's Azure requests.
+func NewReconciler(m ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler, t *azure.Throttle) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	return &Reconciler{client: m.GetClient(), newManaged: nm, wrapped: r, throttle: t}
+}
+
+// Reconcile a managed resource unless Azure recently throttled it.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.client.Get(ctx, req.NamespacedName, mg); err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.wrapped.Reconcile(req)
+		}
+		return reconcile.Result{}, errors.Wrap(err, errGetManaged)
+	}
+
+	if until, ok := r.throttle.Backoff(mg); ok {
+		return reconcile.Result{RequeueAfter: time.Until(until)}, nil
+	}
+
+	result, err := r.wrapped.Reconcile(req)
+
+	// Errors returned by the wrapped reconciler typically indicate that it
+	// could not update the managed resource, and are retried as usual.
+	if until, ok := r.throttle.Backoff(mg); ok && err == nil {
+		return reconcile.Result{RequeueAfter: time.Until(until)}, nil
+	}
+	return result, err
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	azure "github.com/crossplane/provider-azure/pkg/clients"
+)
+
+var (
+	errBoom      = errors.New("boom")
+	errThrottled = autorest.DetailedError{StatusCode: http.StatusTooManyRequests}
+)
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+var wrappedResult = reconcile.Result{Requeue: true}
+
+func TestReconcile(t *testing.T) {
+	type want struct {
+		result    reconcile.Result
+		backoff   bool
+		err       error
+		delegated bool
+	}
+
+	cases := map[string]struct {
+		client    client.Client
+		throttled bool
+		classify  error
+		wrapped   error
+		want      want
+	}{
+		"NotFound": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			},
+			want: want{result: wrappedResult, delegated: true},
+		},
+		"GetError": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			want: want{err: errors.Wrap(errBoom, errGetManaged)},
+		},
+		"NotThrottled": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			want: want{result: wrappedResult, delegated: true},
+		},
+		"BackingOff": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			throttled: true,
+			want:      want{backoff: true},
+		},
+		"Throttled": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			classify: errThrottled,
+			want:     want{backoff: true, delegated: true},
+		},
+		"ThrottledWrappedError": {
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			classify: errThrottled,
+			wrapped:  errBoom,
+			want:     want{result: wrappedResult, err: errBoom, delegated: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			th := azure.NewThrottle()
+			if tc.throttled {
+				_ = th.Classify(mg, errThrottled)
+			}
+			delegated := false
+			r := &Reconciler{
+				client:     tc.client,
+				newManaged: func() resource.Managed { return mg },
+				wrapped: reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+					delegated = true
+					_ = th.Classify(mg, tc.classify)
+					return wrappedResult, tc.wrapped
+				}),
+				throttle: th,
+			}
+
+			got, err := r.Reconcile(reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.delegated, delegated); diff != "" {
+				t.Errorf("r.Reconcile(...): -want delegated, +got delegated:\n%s", diff)
+			}
+			if tc.want.backoff {
+				if got.RequeueAfter <= 0 || got.RequeueAfter > azure.DefaultThrottleBackoff {
+					t.Errorf("r.Reconcile(...): want requeue within %s, got %s", azure.DefaultThrottleBackoff, got.RequeueAfter)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}